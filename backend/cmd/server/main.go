@@ -9,11 +9,17 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/your-org/kintai/backend/internal/audit"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/handler"
+	"github.com/your-org/kintai/backend/internal/mailer"
 	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/notify"
+	"github.com/your-org/kintai/backend/internal/oidc"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"github.com/your-org/kintai/backend/internal/repository"
+	"github.com/your-org/kintai/backend/internal/revocation"
 	"github.com/your-org/kintai/backend/internal/router"
 	"github.com/your-org/kintai/backend/internal/service"
 	"github.com/your-org/kintai/backend/pkg/logger"
@@ -50,6 +56,10 @@ func main() {
 	if err != nil {
 		zapLogger.Fatal("データベース接続に失敗", err)
 	}
+	// 作成・更新・削除をすべて監査ログに記録する
+	if err := db.Use(audit.NewRecorder()); err != nil {
+		zapLogger.Fatal("監査ログの初期化に失敗", err)
+	}
 
 	// 開発環境のみAutoMigrate（本番はgolang-migrate使用）
 	// マイグレーションは手動で実行済みの場合はスキップ
@@ -77,18 +87,64 @@ func main() {
 	// リポジトリ層の初期化
 	repos := repository.NewRepositories(db)
 
+	// アクセストークンの失効リスト（Redis 障害時はインメモリで継続）
+	denyList := revocation.NewRedisDenyList(cfg.RedisURL, zapLogger)
+
+	// メール送信（MAIL_DRIVER で切り替え）
+	mail, err := mailer.New(context.Background(), cfg, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("メール送信の初期化に失敗", err)
+	}
+
+	// 通知のメール配信（NOTIFICATION_EMAIL_ENABLED=false の場合はアプリ内通知のみ）
+	notifier, err := notify.New(cfg, mail, zapLogger)
+	if err != nil {
+		zapLogger.Fatal("通知配信の初期化に失敗", err)
+	}
+
+	// リアルタイム配信（Redis pub/sub で全インスタンスに配信。Redis 障害時はこのインスタンス内のみ）
+	events := realtime.NewRedisHub(cfg.RedisURL, realtime.Config{
+		ReplaySize: cfg.EventsReplaySize,
+		Heartbeat:  time.Duration(cfg.EventsHeartbeatSeconds) * time.Second,
+	}, zapLogger)
+
+	// シングルサインオン（OIDC_ISSUER_URL 未設定の場合は無効）
+	var sso *oidc.Provider
+	if cfg.OIDCIssuerURL != "" {
+		sso = oidc.NewProvider(oidc.Config{
+			IssuerURL:    cfg.OIDCIssuerURL,
+			ClientID:     cfg.OIDCClientID,
+			ClientSecret: cfg.OIDCClientSecret,
+			RedirectURL:  cfg.OIDCRedirectURL,
+			Scopes:       cfg.OIDCScopes,
+			GroupsClaim:  cfg.OIDCGroupsClaim,
+		})
+	}
+
 	// サービス層の初期化
 	services := service.NewServices(service.Deps{
-		Repos:  repos,
-		Config: cfg,
-		Logger: zapLogger,
+		Repos:    repos,
+		Config:   cfg,
+		Logger:   zapLogger,
+		DenyList: denyList,
+		Mailer:   mail,
+		OIDC:     sso,
+		Notifier: notifier,
+		Events:   events,
 	})
 
+	// バックグラウンドジョブ（退勤打刻漏れの自動処理・期限切れリフレッシュトークンの削除・リアルタイムイベントの受信）
+	jobCtx, stopJobs := context.WithCancel(middleware.WithSystem(context.Background()))
+	defer stopJobs()
+	go events.Run(jobCtx)
+	go service.RunAttendanceAutoCloseJob(jobCtx, services.Attendance, cfg, zapLogger)
+	go service.RunRefreshTokenCleanupJob(jobCtx, services.Auth, cfg, zapLogger)
+
 	// ハンドラー層の初期化
-	handlers := handler.NewHandlers(services, zapLogger)
+	handlers := handler.NewHandlers(services, zapLogger).WithCookieAuth(cfg)
 
 	// ミドルウェアの初期化
-	mw := middleware.NewMiddleware(cfg, zapLogger)
+	mw := middleware.NewMiddleware(cfg, zapLogger).WithDenyList(denyList).WithAPITokens(services.APIToken)
 
 	// Ginエンジンの設定
 	if cfg.Env == "production" {
@@ -127,5 +183,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		zapLogger.Fatal("サーバーの強制シャットダウン", err)
 	}
+	// 受け付け済みの通知を配信し切ってから終了する
+	if notifier != nil {
+		notifier.Close()
+	}
 	zapLogger.Info("サーバーが正常にシャットダウンしました")
 }
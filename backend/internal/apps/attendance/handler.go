@@ -1,6 +1,7 @@
 package attendance
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -31,6 +32,9 @@ func (h *AttendanceHandler) ClockIn(c *gin.Context) {
 
 	attendance, err := h.svc.ClockIn(c.Request.Context(), userID, &req)
 	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
 	}
@@ -168,6 +172,9 @@ func (h *LeaveHandler) Approve(c *gin.Context) {
 
 	leave, err := h.svc.Approve(c.Request.Context(), leaveID, approverID, &req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
 	}
@@ -196,6 +203,9 @@ func (h *LeaveHandler) GetPending(c *gin.Context) {
 	page, pageSize := parsePagination(c)
 	leaves, total, err := h.svc.GetPending(c.Request.Context(), page, pageSize)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -248,6 +258,9 @@ func (h *OvertimeRequestHandler) Approve(c *gin.Context) {
 	}
 	overtime, err := h.svc.Approve(c.Request.Context(), id, approverID, &req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
 	}
@@ -273,6 +286,9 @@ func (h *OvertimeRequestHandler) GetPending(c *gin.Context) {
 	page, pageSize := parsePagination(c)
 	overtimes, total, err := h.svc.GetPending(c.Request.Context(), page, pageSize)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -380,6 +396,9 @@ func (h *AttendanceCorrectionHandler) Create(c *gin.Context) {
 	}
 	correction, err := h.svc.Create(c.Request.Context(), userID, &req)
 	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
 	}
@@ -403,6 +422,9 @@ func (h *AttendanceCorrectionHandler) Approve(c *gin.Context) {
 	}
 	correction, err := h.svc.Approve(c.Request.Context(), id, approverID, &req)
 	if err != nil {
+		if respondForbiddenError(c, err) || respondValidationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
 	}
@@ -428,8 +450,352 @@ func (h *AttendanceCorrectionHandler) GetPending(c *gin.Context) {
 	page, pageSize := parsePagination(c)
 	corrections, total, err := h.svc.GetPending(c.Request.Context(), page, pageSize)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
 	paginatedResponse(c, corrections, total, page, pageSize)
 }
+
+type SubstituteHolidayHandler struct {
+	svc    SubstituteHolidayService
+	logger *logger.Logger
+}
+
+func NewSubstituteHolidayHandler(svc SubstituteHolidayService, logger *logger.Logger) *SubstituteHolidayHandler {
+	return &SubstituteHolidayHandler{svc: svc, logger: logger}
+}
+
+func (h *SubstituteHolidayHandler) Create(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	var req model.SubstituteHolidayRequestCreate
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid request", Details: err.Error()})
+		return
+	}
+	request, err := h.svc.Create(c.Request.Context(), userID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *SubstituteHolidayHandler) Approve(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	approverID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	var req model.SubstituteHolidayApproval
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid request"})
+		return
+	}
+	request, err := h.svc.Approve(c.Request.Context(), id, approverID, &req)
+	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+func (h *SubstituteHolidayHandler) GetMy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	page, pageSize := parsePagination(c)
+	requests, total, err := h.svc.GetByUser(c.Request.Context(), userID, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	paginatedResponse(c, requests, total, page, pageSize)
+}
+
+func (h *SubstituteHolidayHandler) GetPending(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	requests, total, err := h.svc.GetPending(c.Request.Context(), page, pageSize)
+	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	paginatedResponse(c, requests, total, page, pageSize)
+}
+
+func (h *SubstituteHolidayHandler) GetMyCompensatoryDays(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	summary, err := h.svc.GetCompensatoryDays(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// ===== WorkingHourPolicyHandler =====
+
+type WorkingHourPolicyHandler struct {
+	svc    WorkingHourPolicyService
+	logger *logger.Logger
+}
+
+func NewWorkingHourPolicyHandler(svc WorkingHourPolicyService, logger *logger.Logger) *WorkingHourPolicyHandler {
+	return &WorkingHourPolicyHandler{svc: svc, logger: logger}
+}
+
+func (h *WorkingHourPolicyHandler) List(c *gin.Context) {
+	policies, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *WorkingHourPolicyHandler) GetByDepartment(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	policy, err := h.svc.GetByDepartment(c.Request.Context(), departmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *WorkingHourPolicyHandler) Save(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	var req model.WorkingHourPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid request", Details: err.Error()})
+		return
+	}
+	policy, err := h.svc.Save(c.Request.Context(), departmentID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *WorkingHourPolicyHandler) GetSchedule(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	date, ok := parseQueryDate(c, "date")
+	if !ok {
+		return
+	}
+	days, err := h.svc.GetSchedule(c.Request.Context(), departmentID, date)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, days)
+}
+
+func (h *WorkingHourPolicyHandler) SetSchedule(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	var req model.WorkingHourScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid request", Details: err.Error()})
+		return
+	}
+	days, err := h.svc.SetSchedule(c.Request.Context(), departmentID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, days)
+}
+
+func (h *WorkingHourPolicyHandler) GetSettlement(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	date, ok := parseQueryDate(c, "date")
+	if !ok {
+		return
+	}
+	report, err := h.svc.GetSettlement(c.Request.Context(), departmentID, date)
+	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if errors.Is(err, ErrWorkingHourPolicyNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
+
+// ===== RemoteWorkPolicyHandler =====
+
+type RemoteWorkPolicyHandler struct {
+	svc    RemoteWorkPolicyService
+	logger *logger.Logger
+}
+
+func NewRemoteWorkPolicyHandler(svc RemoteWorkPolicyService, logger *logger.Logger) *RemoteWorkPolicyHandler {
+	return &RemoteWorkPolicyHandler{svc: svc, logger: logger}
+}
+
+func (h *RemoteWorkPolicyHandler) List(c *gin.Context) {
+	policies, err := h.svc.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policies)
+}
+
+func (h *RemoteWorkPolicyHandler) GetByDepartment(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	policy, err := h.svc.GetByDepartment(c.Request.Context(), departmentID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *RemoteWorkPolicyHandler) Save(c *gin.Context) {
+	departmentID, err := parseUUID(c, "department_id")
+	if err != nil {
+		return
+	}
+	var req model.RemoteWorkPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid request", Details: err.Error()})
+		return
+	}
+	policy, err := h.svc.Save(c.Request.Context(), departmentID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, policy)
+}
+
+// ===== TeamHandler =====
+
+type TeamHandler struct {
+	svc    TeamService
+	logger *logger.Logger
+}
+
+func NewTeamHandler(svc TeamService, logger *logger.Logger) *TeamHandler {
+	return &TeamHandler{svc: svc, logger: logger}
+}
+
+func (h *TeamHandler) GetMembers(c *gin.Context) {
+	managerID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	members, err := h.svc.GetMembers(c.Request.Context(), managerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+func (h *TeamHandler) GetToday(c *gin.Context) {
+	managerID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	statuses, err := h.svc.GetTodayStatus(c.Request.Context(), managerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+func (h *TeamHandler) GetSummary(c *gin.Context) {
+	managerID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	start, end, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "invalid date range format"})
+		return
+	}
+	summaries, err := h.svc.GetSummaries(c.Request.Context(), managerID, start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summaries)
+}
+
+func (h *TeamHandler) GetPending(c *gin.Context) {
+	managerID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "unauthorized"})
+		return
+	}
+	pending, err := h.svc.GetPending(c.Request.Context(), managerID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pending)
+}
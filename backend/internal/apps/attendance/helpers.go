@@ -21,6 +21,25 @@ func getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	return uuid.Parse(userIDStr.(string))
 }
 
+// respondForbiddenError は承認範囲外の操作であれば 403 を返して true を返す
+func respondForbiddenError(c *gin.Context, err error) bool {
+	if !errors.Is(err, ErrNotDirectReport) && !errors.Is(err, ErrForbidden) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+	return true
+}
+
+// respondValidationError は入力検証エラーであれば項目別エラーを返して true を返す
+func respondValidationError(c *gin.Context, err error) bool {
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: http.StatusBadRequest, Message: verr.Error(), Errors: verr.Fields})
+	return true
+}
+
 func parseUUID(c *gin.Context, param string) (uuid.UUID, error) {
 	id, err := uuid.Parse(c.Param(param))
 	if err != nil {
@@ -74,3 +93,13 @@ func paginatedResponse(c *gin.Context, data interface{}, total int64, page, page
 		TotalPages: totalPages,
 	})
 }
+
+// parseQueryDate はクエリの日付（YYYY-MM-DD）を解析する。未指定の場合は今日を返す
+func parseQueryDate(c *gin.Context, key string) (time.Time, bool) {
+	date, err := time.Parse("2006-01-02", c.DefaultQuery(key, time.Now().Format("2006-01-02")))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: http.StatusBadRequest, Message: "invalid date format"})
+		return time.Time{}, false
+	}
+	return date, true
+}
@@ -0,0 +1,35 @@
+package attendance
+
+import (
+	"context"
+	"time"
+
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+// defaultAutoCloseInterval は退勤打刻漏れ処理の既定の実行間隔
+const defaultAutoCloseInterval = time.Hour
+
+// RunAutoCloseJob は退勤打刻漏れの勤怠を定期的に処理する。ctx がキャンセルされるまで戻らない
+func RunAutoCloseJob(ctx context.Context, svc AttendanceService, interval time.Duration, log *logger.Logger) {
+	if interval <= 0 {
+		interval = defaultAutoCloseInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		closed, err := svc.CloseOpenAttendances(ctx, time.Now())
+		if err != nil {
+			log.Error("退勤打刻漏れの自動処理に失敗しました", "error", err)
+		} else if closed > 0 {
+			log.Info("退勤打刻漏れの勤怠を処理しました", "count", closed)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
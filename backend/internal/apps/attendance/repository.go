@@ -7,20 +7,46 @@ import (
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	FindAll(ctx context.Context, page, pageSize int) ([]model.User, int64, error)
+	FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) ([]model.User, error)
+}
+
+// HolidayRepository は会社カレンダーの参照インターフェース（shared.HolidayRepository が実装）
+type HolidayRepository interface {
+	IsHoliday(ctx context.Context, date time.Time) (bool, *model.Holiday, error)
+}
+
+// ShiftRepository はシフトの参照インターフェース（shared.ShiftRepository が実装）
+type ShiftRepository interface {
+	FindByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.Shift, error)
+}
+
+// ShiftScheduleRepository はシフト表の参照インターフェース（repository.ShiftScheduleRepository が実装）
+type ShiftScheduleRepository interface {
+	FindByDepartmentAndDate(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.ShiftSchedule, error)
 }
 
 // Repositories は勤怠関連リポジトリを束ねる構造体
 type Repositories struct {
 	User                 UserRepository
+	Shift                ShiftRepository
+	ShiftSchedule        ShiftScheduleRepository
 	Attendance           AttendanceRepository
 	LeaveRequest         LeaveRequestRepository
 	OvertimeRequest      OvertimeRequestRepository
 	AttendanceCorrection AttendanceCorrectionRepository
 	LeaveBalance         LeaveBalanceRepository
+	Holiday              HolidayRepository
+	SubstituteHoliday    SubstituteHolidayRepository
+	CompensatoryDay      CompensatoryDayRepository
+	WorkingHourPolicy    WorkingHourPolicyRepository
+	RemoteWorkPolicy     RemoteWorkPolicyRepository
+	Team                 TeamRepository
 }
 
 // NewRepositories は勤怠関連リポジトリを初期化する
@@ -31,6 +57,11 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		OvertimeRequest:      NewOvertimeRequestRepository(db),
 		AttendanceCorrection: NewAttendanceCorrectionRepository(db),
 		LeaveBalance:         NewLeaveBalanceRepository(db),
+		SubstituteHoliday:    NewSubstituteHolidayRepository(db),
+		CompensatoryDay:      NewCompensatoryDayRepository(db),
+		WorkingHourPolicy:    NewWorkingHourPolicyRepository(db),
+		RemoteWorkPolicy:     NewRemoteWorkPolicyRepository(db),
+		Team:                 NewTeamRepository(db),
 	}
 }
 
@@ -44,11 +75,17 @@ type AttendanceRepository interface {
 	FindByDateRange(ctx context.Context, start, end time.Time) ([]model.Attendance, error)
 	Update(ctx context.Context, attendance *model.Attendance) error
 	GetSummary(ctx context.Context, userID uuid.UUID, start, end time.Time) (*model.AttendanceSummary, error)
+	CountWorkModesByUser(ctx context.Context, start, end time.Time) ([]model.UserWorkModeCount, error)
 	CountTodayPresent(ctx context.Context) (int64, error)
 	CountTodayAbsent(ctx context.Context, totalUsers int64) (int64, error)
 	GetMonthlyOvertime(ctx context.Context, start, end time.Time) (int64, error)
+	FindOpenBefore(ctx context.Context, date time.Time) ([]model.Attendance, error)
+	WithAutoCloseLock(ctx context.Context, fn func() error) (bool, error)
 }
 
+// autoCloseLockKey は退勤打刻漏れの自動処理を複数インスタンスで重複実行しないためのアドバイザリロックのキー
+const autoCloseLockKey = 7_420_002
+
 type attendanceRepository struct {
 	db *gorm.DB
 }
@@ -116,7 +153,10 @@ func (r *attendanceRepository) GetSummary(ctx context.Context, userID uuid.UUID,
 
 	r.db.WithContext(ctx).Model(&model.Attendance{}).
 		Where("user_id = ? AND date BETWEEN ? AND ?", userID, start, end).
-		Select("COUNT(*) as total_work_days, COALESCE(SUM(work_minutes), 0) as total_work_minutes, COALESCE(SUM(overtime_minutes), 0) as total_overtime_minutes, COALESCE(AVG(work_minutes), 0) as average_work_minutes").
+		Select("COUNT(*) as total_work_days, COALESCE(SUM(work_minutes), 0) as total_work_minutes, COALESCE(SUM(overtime_minutes), 0) as total_overtime_minutes, "+
+			"COALESCE(SUM(within_statutory_overtime_minutes), 0) as total_within_statutory_overtime_minutes, COALESCE(SUM(late_night_minutes), 0) as total_late_night_minutes, "+
+			"COALESCE(SUM(holiday_work_minutes), 0) as total_holiday_work_minutes, COALESCE(SUM(overtime_over60_minutes), 0) as total_overtime_over60_minutes, "+
+			"COALESCE(AVG(work_minutes), 0) as average_work_minutes").
 		Where("status = ?", model.AttendanceStatusPresent).
 		Scan(&summary)
 
@@ -131,9 +171,71 @@ func (r *attendanceRepository) GetSummary(ctx context.Context, userID uuid.UUID,
 		Count(&leaveCount)
 	summary.LeaveDays = int(leaveCount)
 
+	var incompleteCount int64
+	r.db.WithContext(ctx).Model(&model.Attendance{}).
+		Where("user_id = ? AND date BETWEEN ? AND ? AND status = ?", userID, start, end, model.AttendanceStatusIncomplete).
+		Count(&incompleteCount)
+	summary.IncompleteDays = int(incompleteCount)
+
+	var workDays []struct {
+		Date     time.Time
+		WorkMode model.WorkMode
+	}
+	r.db.WithContext(ctx).Model(&model.Attendance{}).
+		Select("date, work_mode").
+		Where("user_id = ? AND date BETWEEN ? AND ? AND status = ?", userID, start, end, model.AttendanceStatusPresent).
+		Order("date ASC").
+		Scan(&workDays)
+	for _, d := range workDays {
+		summary.AddWorkModeDay(d.Date, d.WorkMode)
+	}
+
 	return &summary, nil
 }
 
+// CountWorkModesByUser は期間内の出勤日数をユーザー・月・勤務形態ごとに集計する
+func (r *attendanceRepository) CountWorkModesByUser(ctx context.Context, start, end time.Time) ([]model.UserWorkModeCount, error) {
+	var counts []model.UserWorkModeCount
+	err := r.db.WithContext(ctx).Model(&model.Attendance{}).
+		Select("user_id, to_char(date, 'YYYY-MM') as month, work_mode, COUNT(*) as days").
+		Where("date BETWEEN ? AND ? AND status = ?", start.Format("2006-01-02"), end.Format("2006-01-02"), model.AttendanceStatusPresent).
+		Group("user_id, to_char(date, 'YYYY-MM'), work_mode").
+		Order("month ASC").
+		Scan(&counts).Error
+	return counts, err
+}
+
+// FindOpenBefore は date より前の日付で出勤打刻のみ残っている勤怠を返す
+func (r *attendanceRepository) FindOpenBefore(ctx context.Context, date time.Time) ([]model.Attendance, error) {
+	var attendances []model.Attendance
+	err := r.db.WithContext(ctx).
+		Where("date < ? AND clock_in IS NOT NULL AND clock_out IS NULL AND status = ?", date, model.AttendanceStatusPresent).
+		Order("date ASC").
+		Find(&attendances).Error
+	return attendances, err
+}
+
+// WithAutoCloseLock は退勤打刻漏れの自動処理のロックを取得できた場合に fn を実行する。
+// ロックはトランザクションの終了時に解放されるため、コネクションプールでも別の接続に残らない。
+// 他のインスタンスが処理中の場合は fn を実行せずに false を返す
+func (r *attendanceRepository) WithAutoCloseLock(ctx context.Context, fn func() error) (bool, error) {
+	acquired := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", autoCloseLockKey).Scan(&acquired).Error; err != nil {
+				return err
+			}
+		} else {
+			acquired = true
+		}
+		if !acquired {
+			return nil
+		}
+		return fn()
+	})
+	return acquired, err
+}
+
 func (r *attendanceRepository) CountTodayPresent(ctx context.Context) (int64, error) {
 	var count int64
 	today := time.Now().Format("2006-01-02")
@@ -167,6 +269,7 @@ type LeaveRequestRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.LeaveRequest, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LeaveRequest, int64, error)
 	FindPending(ctx context.Context, page, pageSize int) ([]model.LeaveRequest, int64, error)
+	FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.LeaveRequest, int64, error)
 	Update(ctx context.Context, req *model.LeaveRequest) error
 	CountPending(ctx context.Context) (int64, error)
 }
@@ -215,6 +318,25 @@ func (r *leaveRequestRepository) FindPending(ctx context.Context, page, pageSize
 	query := r.db.WithContext(ctx).Where("status = ?", model.ApprovalStatusPending)
 	query.Model(&model.LeaveRequest{}).Count(&total)
 
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").
+		Offset(offset).Limit(pageSize).
+		Order("created_at ASC").
+		Find(&requests).Error
+	return requests, total, err
+}
+
+// FindPendingByUserIDs は userIDs のユーザーが提出した承認待ちの申請を返す
+func (r *leaveRequestRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.LeaveRequest, int64, error) {
+	var requests []model.LeaveRequest
+	var total int64
+	if len(userIDs) == 0 {
+		return requests, 0, nil
+	}
+
+	query := r.db.WithContext(ctx).Where("status = ? AND user_id IN ?", model.ApprovalStatusPending, userIDs)
+	query.Model(&model.LeaveRequest{}).Count(&total)
+
 	offset := (page - 1) * pageSize
 	err := query.Preload("User").
 		Offset(offset).Limit(pageSize).
@@ -241,6 +363,7 @@ type OvertimeRequestRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.OvertimeRequest, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.OvertimeRequest, int64, error)
 	FindPending(ctx context.Context, page, pageSize int) ([]model.OvertimeRequest, int64, error)
+	FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.OvertimeRequest, int64, error)
 	Update(ctx context.Context, req *model.OvertimeRequest) error
 	CountPending(ctx context.Context) (int64, error)
 	GetUserMonthlyOvertime(ctx context.Context, userID uuid.UUID, year, month int) (int64, error)
@@ -286,6 +409,20 @@ func (r *overtimeRequestRepository) FindPending(ctx context.Context, page, pageS
 	return requests, total, err
 }
 
+// FindPendingByUserIDs は userIDs のユーザーが提出した承認待ちの申請を返す
+func (r *overtimeRequestRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.OvertimeRequest, int64, error) {
+	var requests []model.OvertimeRequest
+	var total int64
+	if len(userIDs) == 0 {
+		return requests, 0, nil
+	}
+	query := r.db.WithContext(ctx).Where("status = ? AND user_id IN ?", model.OvertimeStatusPending, userIDs)
+	query.Model(&model.OvertimeRequest{}).Count(&total)
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Offset(offset).Limit(pageSize).Order("created_at ASC").Find(&requests).Error
+	return requests, total, err
+}
+
 func (r *overtimeRequestRepository) Update(ctx context.Context, req *model.OvertimeRequest) error {
 	return r.db.WithContext(ctx).Save(req).Error
 }
@@ -323,7 +460,9 @@ type AttendanceCorrectionRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.AttendanceCorrection, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.AttendanceCorrection, int64, error)
 	FindPending(ctx context.Context, page, pageSize int) ([]model.AttendanceCorrection, int64, error)
+	FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.AttendanceCorrection, int64, error)
 	Update(ctx context.Context, correction *model.AttendanceCorrection) error
+	ApplyApproval(ctx context.Context, correction *model.AttendanceCorrection, attendance *model.Attendance) error
 	CountPending(ctx context.Context) (int64, error)
 }
 
@@ -366,10 +505,39 @@ func (r *attendanceCorrectionRepository) FindPending(ctx context.Context, page,
 	return corrections, total, err
 }
 
+// FindPendingByUserIDs は userIDs のユーザーが提出した承認待ちの申請を返す
+func (r *attendanceCorrectionRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.AttendanceCorrection, int64, error) {
+	var corrections []model.AttendanceCorrection
+	var total int64
+	if len(userIDs) == 0 {
+		return corrections, 0, nil
+	}
+	query := r.db.WithContext(ctx).Where("status = ? AND user_id IN ?", model.CorrectionStatusPending, userIDs)
+	query.Model(&model.AttendanceCorrection{}).Count(&total)
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Offset(offset).Limit(pageSize).Order("created_at ASC").Find(&corrections).Error
+	return corrections, total, err
+}
+
 func (r *attendanceCorrectionRepository) Update(ctx context.Context, correction *model.AttendanceCorrection) error {
 	return r.db.WithContext(ctx).Save(correction).Error
 }
 
+// ApplyApproval は勤怠データの反映と修正申請の承認を単一トランザクションで保存する
+func (r *attendanceCorrectionRepository) ApplyApproval(ctx context.Context, correction *model.AttendanceCorrection, attendance *model.Attendance) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if attendance.ID == uuid.Nil {
+			if err := tx.Create(attendance).Error; err != nil {
+				return err
+			}
+		} else if err := tx.Omit(clause.Associations).Save(attendance).Error; err != nil {
+			return err
+		}
+		correction.AttendanceID = &attendance.ID
+		return tx.Omit(clause.Associations).Save(correction).Error
+	})
+}
+
 func (r *attendanceCorrectionRepository) CountPending(ctx context.Context) (int64, error) {
 	var count int64
 	err := r.db.WithContext(ctx).Model(&model.AttendanceCorrection{}).Where("status = ?", model.CorrectionStatusPending).Count(&count).Error
@@ -421,3 +589,297 @@ func (r *leaveBalanceRepository) Upsert(ctx context.Context, balance *model.Leav
 		Assign(model.LeaveBalance{TotalDays: balance.TotalDays, UsedDays: balance.UsedDays, CarriedOver: balance.CarriedOver}).
 		FirstOrCreate(balance).Error
 }
+
+// ===== SubstituteHolidayRepository =====
+
+type SubstituteHolidayRepository interface {
+	Create(ctx context.Context, req *model.SubstituteHolidayRequest) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.SubstituteHolidayRequest, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	FindPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	FindApprovedByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.SubstituteHolidayRequest, error)
+	CountPendingDaikyu(ctx context.Context, userID uuid.UUID) (int64, error)
+	Update(ctx context.Context, req *model.SubstituteHolidayRequest) error
+	ApplyApproval(ctx context.Context, req *model.SubstituteHolidayRequest, day *model.CompensatoryDay) error
+}
+
+type substituteHolidayRepository struct{ db *gorm.DB }
+
+func NewSubstituteHolidayRepository(db *gorm.DB) SubstituteHolidayRepository {
+	return &substituteHolidayRepository{db: db}
+}
+
+func (r *substituteHolidayRepository) Create(ctx context.Context, req *model.SubstituteHolidayRequest) error {
+	return r.db.WithContext(ctx).Create(req).Error
+}
+
+func (r *substituteHolidayRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.SubstituteHolidayRequest, error) {
+	var req model.SubstituteHolidayRequest
+	err := r.db.WithContext(ctx).Preload("User").Preload("Approver").First(&req, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func (r *substituteHolidayRepository) FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var requests []model.SubstituteHolidayRequest
+	var total int64
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	query.Model(&model.SubstituteHolidayRequest{}).Count(&total)
+	offset := (page - 1) * pageSize
+	err := query.Preload("Approver").Offset(offset).Limit(pageSize).Order("created_at DESC").Find(&requests).Error
+	return requests, total, err
+}
+
+func (r *substituteHolidayRepository) FindPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var requests []model.SubstituteHolidayRequest
+	var total int64
+	query := r.db.WithContext(ctx).Where("status = ?", model.ApprovalStatusPending)
+	query.Model(&model.SubstituteHolidayRequest{}).Count(&total)
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Offset(offset).Limit(pageSize).Order("created_at ASC").Find(&requests).Error
+	return requests, total, err
+}
+
+// FindPendingByUserIDs は userIDs のユーザーが提出した承認待ちの申請を返す
+func (r *substituteHolidayRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var requests []model.SubstituteHolidayRequest
+	var total int64
+	if len(userIDs) == 0 {
+		return requests, 0, nil
+	}
+	query := r.db.WithContext(ctx).Where("status = ? AND user_id IN ?", model.ApprovalStatusPending, userIDs)
+	query.Model(&model.SubstituteHolidayRequest{}).Count(&total)
+	offset := (page - 1) * pageSize
+	err := query.Preload("User").Offset(offset).Limit(pageSize).Order("created_at ASC").Find(&requests).Error
+	return requests, total, err
+}
+
+// FindApprovedByUserAndDateRange は出勤日または休日が期間内にある承認済みの申請を返す
+func (r *substituteHolidayRepository) FindApprovedByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.SubstituteHolidayRequest, error) {
+	var requests []model.SubstituteHolidayRequest
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND status = ?", userID, model.ApprovalStatusApproved).
+		Where("(work_date BETWEEN ? AND ?) OR (holiday_date BETWEEN ? AND ?)", start, end, start, end).
+		Find(&requests).Error
+	return requests, err
+}
+
+// CountPendingDaikyu はユーザーの承認待ちの代休申請の件数を返す
+func (r *substituteHolidayRepository) CountPendingDaikyu(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.SubstituteHolidayRequest{}).
+		Where("user_id = ? AND type = ? AND status = ?", userID, model.SubstituteHolidayTypeDaikyu, model.ApprovalStatusPending).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *substituteHolidayRepository) Update(ctx context.Context, req *model.SubstituteHolidayRequest) error {
+	return r.db.WithContext(ctx).Save(req).Error
+}
+
+// ApplyApproval は申請の承認と代休の消化を単一トランザクションで保存する
+func (r *substituteHolidayRepository) ApplyApproval(ctx context.Context, req *model.SubstituteHolidayRequest, day *model.CompensatoryDay) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if day != nil {
+			if err := tx.Save(day).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit(clause.Associations).Save(req).Error
+	})
+}
+
+// ===== CompensatoryDayRepository =====
+
+type CompensatoryDayRepository interface {
+	Create(ctx context.Context, day *model.CompensatoryDay) error
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.CompensatoryDay, error)
+	FindByUserAndEarnedDate(ctx context.Context, userID uuid.UUID, date time.Time) (*model.CompensatoryDay, error)
+	FindAvailable(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.CompensatoryDay, error)
+}
+
+type compensatoryDayRepository struct{ db *gorm.DB }
+
+func NewCompensatoryDayRepository(db *gorm.DB) CompensatoryDayRepository {
+	return &compensatoryDayRepository{db: db}
+}
+
+func (r *compensatoryDayRepository) Create(ctx context.Context, day *model.CompensatoryDay) error {
+	return r.db.WithContext(ctx).Create(day).Error
+}
+
+func (r *compensatoryDayRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.CompensatoryDay, error) {
+	var days []model.CompensatoryDay
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("earned_date DESC").Find(&days).Error
+	return days, err
+}
+
+func (r *compensatoryDayRepository) FindByUserAndEarnedDate(ctx context.Context, userID uuid.UUID, date time.Time) (*model.CompensatoryDay, error) {
+	var day model.CompensatoryDay
+	err := r.db.WithContext(ctx).Where("user_id = ? AND earned_date = ?", userID, date).First(&day).Error
+	if err != nil {
+		return nil, err
+	}
+	return &day, nil
+}
+
+// FindAvailable は指定日に取得可能な未消化の代休を有効期限の近い順に返す
+func (r *compensatoryDayRepository) FindAvailable(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.CompensatoryDay, error) {
+	var days []model.CompensatoryDay
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_date IS NULL AND earned_date < ? AND expires_at >= ?", userID, date, date).
+		Order("expires_at ASC").
+		Find(&days).Error
+	return days, err
+}
+
+// ===== WorkingHourPolicyRepository =====
+
+type WorkingHourPolicyRepository interface {
+	Create(ctx context.Context, policy *model.WorkingHourPolicy) error
+	FindAll(ctx context.Context) ([]model.WorkingHourPolicy, error)
+	FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error)
+	Update(ctx context.Context, policy *model.WorkingHourPolicy) error
+	FindScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time) ([]model.WorkingHourScheduleDay, error)
+	ReplaceScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time, days []model.WorkingHourScheduleDay) error
+}
+
+type workingHourPolicyRepository struct{ db *gorm.DB }
+
+func NewWorkingHourPolicyRepository(db *gorm.DB) WorkingHourPolicyRepository {
+	return &workingHourPolicyRepository{db: db}
+}
+
+func (r *workingHourPolicyRepository) Create(ctx context.Context, policy *model.WorkingHourPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *workingHourPolicyRepository) FindAll(ctx context.Context) ([]model.WorkingHourPolicy, error) {
+	var policies []model.WorkingHourPolicy
+	err := r.db.WithContext(ctx).Preload("Department").Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *workingHourPolicyRepository) FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error) {
+	var policy model.WorkingHourPolicy
+	err := r.db.WithContext(ctx).Where("department_id = ?", departmentID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *workingHourPolicyRepository) Update(ctx context.Context, policy *model.WorkingHourPolicy) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(policy).Error
+}
+
+func (r *workingHourPolicyRepository) FindScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time) ([]model.WorkingHourScheduleDay, error) {
+	var days []model.WorkingHourScheduleDay
+	err := r.db.WithContext(ctx).
+		Where("policy_id = ? AND date BETWEEN ? AND ?", policyID, start, end).
+		Order("date ASC").Find(&days).Error
+	return days, err
+}
+
+// ReplaceScheduleDays は期間内の勤務カレンダーを単一トランザクションで置き換える
+func (r *workingHourPolicyRepository) ReplaceScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time, days []model.WorkingHourScheduleDay) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("policy_id = ? AND date BETWEEN ? AND ?", policyID, start, end).
+			Delete(&model.WorkingHourScheduleDay{}).Error; err != nil {
+			return err
+		}
+		if len(days) == 0 {
+			return nil
+		}
+		return tx.Create(&days).Error
+	})
+}
+
+// ===== RemoteWorkPolicyRepository =====
+
+type RemoteWorkPolicyRepository interface {
+	Create(ctx context.Context, policy *model.RemoteWorkPolicy) error
+	FindAll(ctx context.Context) ([]model.RemoteWorkPolicy, error)
+	FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error)
+	Update(ctx context.Context, policy *model.RemoteWorkPolicy) error
+}
+
+type remoteWorkPolicyRepository struct{ db *gorm.DB }
+
+func NewRemoteWorkPolicyRepository(db *gorm.DB) RemoteWorkPolicyRepository {
+	return &remoteWorkPolicyRepository{db: db}
+}
+
+func (r *remoteWorkPolicyRepository) Create(ctx context.Context, policy *model.RemoteWorkPolicy) error {
+	return r.db.WithContext(ctx).Create(policy).Error
+}
+
+func (r *remoteWorkPolicyRepository) FindAll(ctx context.Context) ([]model.RemoteWorkPolicy, error) {
+	var policies []model.RemoteWorkPolicy
+	err := r.db.WithContext(ctx).Preload("Department").Order("created_at ASC").Find(&policies).Error
+	return policies, err
+}
+
+func (r *remoteWorkPolicyRepository) FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error) {
+	var policy model.RemoteWorkPolicy
+	err := r.db.WithContext(ctx).Where("department_id = ?", departmentID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *remoteWorkPolicyRepository) Update(ctx context.Context, policy *model.RemoteWorkPolicy) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(policy).Error
+}
+
+// ===== TeamRepository =====
+
+// TeamRepository はマネージャーの直属の部下と、従業員の上長を解決する
+type TeamRepository interface {
+	FindDirectReportIDs(ctx context.Context, managerID uuid.UUID) ([]uuid.UUID, error)
+	FindManagerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type teamRepository struct{ db *gorm.DB }
+
+func NewTeamRepository(db *gorm.DB) TeamRepository {
+	return &teamRepository{db: db}
+}
+
+// FindDirectReportIDs は管理する部署の所属ユーザー（Department.ManagerID）と
+// 人事上の上長が当該マネージャーである従業員（HREmployee.ManagerID）のユーザーIDを返す
+func (r *teamRepository) FindDirectReportIDs(ctx context.Context, managerID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT u.id FROM users u
+		JOIN departments d ON d.id = u.department_id AND d.deleted_at IS NULL
+		WHERE d.manager_id = ? AND u.id <> ? AND u.deleted_at IS NULL
+		UNION
+		SELECT e.user_id FROM hr_employees e
+		JOIN hr_employees m ON m.id = e.manager_id AND m.deleted_at IS NULL
+		WHERE m.user_id = ? AND e.user_id IS NOT NULL AND e.user_id <> ? AND e.deleted_at IS NULL`,
+		managerID, managerID, managerID, managerID).
+		Scan(&ids).Error
+	return ids, err
+}
+
+// FindManagerIDs は FindDirectReportIDs の逆引きで、所属部署のマネージャーと
+// 人事上の上長のユーザーIDを返す（本人は含めない）
+func (r *teamRepository) FindManagerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT d.manager_id FROM users u
+		JOIN departments d ON d.id = u.department_id AND d.deleted_at IS NULL
+		WHERE u.id = ? AND d.manager_id IS NOT NULL AND d.manager_id <> ? AND u.deleted_at IS NULL
+		UNION
+		SELECT m.user_id FROM hr_employees e
+		JOIN hr_employees m ON m.id = e.manager_id AND m.deleted_at IS NULL
+		WHERE e.user_id = ? AND m.user_id IS NOT NULL AND m.user_id <> ? AND e.deleted_at IS NULL`,
+		userID, userID, userID, userID).
+		Scan(&ids).Error
+	return ids, err
+}
@@ -4,26 +4,35 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
 
 // NotificationSender は通知送信インターフェース（shared.NotificationService が実装）
 type NotificationSender interface {
 	Send(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, title, message string) error
+	Dispatch(ctx context.Context, n *model.Notification) error
 }
 
 // エラー定義
 var (
-	ErrAlreadyClockedIn      = errors.New("既に出勤打刻済みです")
-	ErrNotClockedIn          = errors.New("出勤打刻がありません")
-	ErrAlreadyClockedOut     = errors.New("既に退勤打刻済みです")
-	ErrLeaveNotFound         = errors.New("休暇申請が見つかりません")
-	ErrLeaveAlreadyProcessed = errors.New("この休暇申請は既に処理済みです")
+	ErrAlreadyClockedIn          = errors.New("既に出勤打刻済みです")
+	ErrNotClockedIn              = errors.New("出勤打刻がありません")
+	ErrAlreadyClockedOut         = errors.New("既に退勤打刻済みです")
+	ErrLeaveNotFound             = errors.New("休暇申請が見つかりません")
+	ErrLeaveAlreadyProcessed     = errors.New("この休暇申請は既に処理済みです")
+	ErrWorkingHourPolicyNotFound = errors.New("労働時間制度が設定されていません")
+	ErrRemoteWorkPolicyNotFound  = errors.New("在宅勤務ポリシーが設定されていません")
+	ErrNotDirectReport           = errors.New("直属の部下以外の申請は承認できません")
+	ErrForbidden                 = middleware.ErrForbidden
 )
 
 // Deps はサービスの依存関係
@@ -31,6 +40,8 @@ type Deps struct {
 	Repos  *Repositories
 	Config *config.Config
 	Logger *logger.Logger
+	// Events is optional; when nil, approval status changes are not pushed to clients.
+	Events *realtime.Hub
 }
 
 // Services は勤怠サービスを束ねる構造体
@@ -40,6 +51,10 @@ type Services struct {
 	OvertimeRequest      OvertimeRequestService
 	LeaveBalance         LeaveBalanceService
 	AttendanceCorrection AttendanceCorrectionService
+	SubstituteHoliday    SubstituteHolidayService
+	WorkingHourPolicy    WorkingHourPolicyService
+	RemoteWorkPolicy     RemoteWorkPolicyService
+	Team                 TeamService
 }
 
 // NewServices は勤怠サービスを初期化する
@@ -50,6 +65,10 @@ func NewServices(deps Deps, notifier NotificationSender) *Services {
 		OvertimeRequest:      NewOvertimeRequestService(deps, notifier),
 		LeaveBalance:         NewLeaveBalanceService(deps),
 		AttendanceCorrection: NewAttendanceCorrectionService(deps, notifier),
+		SubstituteHoliday:    NewSubstituteHolidayService(deps, notifier),
+		WorkingHourPolicy:    NewWorkingHourPolicyService(deps),
+		RemoteWorkPolicy:     NewRemoteWorkPolicyService(deps),
+		Team:                 NewTeamService(deps),
 	}
 }
 
@@ -61,14 +80,23 @@ type AttendanceService interface {
 	GetByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time, page, pageSize int) ([]model.Attendance, int64, error)
 	GetSummary(ctx context.Context, userID uuid.UUID, start, end time.Time) (*model.AttendanceSummary, error)
 	GetTodayStatus(ctx context.Context, userID uuid.UUID) (*model.Attendance, error)
+	CloseOpenAttendances(ctx context.Context, now time.Time) (int, error)
 }
 
+// 退勤打刻漏れの自動処理モード（Config.AttendanceAutoCloseMode）
+const (
+	AutoCloseModeOff        = "off"        // 何もしない
+	AutoCloseModeShiftEnd   = "shift_end"  // シフト終了時刻（シフトが無ければ所定労働時間）で退勤扱いにする
+	AutoCloseModeIncomplete = "incomplete" // 打刻漏れとして勤怠修正申請を求める
+)
+
 type attendanceService struct {
-	deps Deps
+	deps     Deps
+	calendar dayCalendar
 }
 
 func NewAttendanceService(deps Deps) AttendanceService {
-	return &attendanceService{deps: deps}
+	return &attendanceService{deps: deps, calendar: dayCalendar{deps: deps}}
 }
 
 func (s *attendanceService) ClockIn(ctx context.Context, userID uuid.UUID, req *model.ClockInRequest) (*model.Attendance, error) {
@@ -79,13 +107,29 @@ func (s *attendanceService) ClockIn(ctx context.Context, userID uuid.UUID, req *
 		return nil, ErrAlreadyClockedIn
 	}
 
+	workMode := req.WorkMode
+	if workMode == "" {
+		workMode = model.WorkModeOffice
+	}
+	if !workMode.IsValid() {
+		verr := &model.ValidationError{}
+		verr.Add("work_mode", "勤務形態が不正です")
+		return nil, verr
+	}
+	if workMode == model.WorkModeRemote {
+		if err := s.checkRemoteAllowance(ctx, userID, today); err != nil {
+			return nil, err
+		}
+	}
+
 	now := time.Now()
 	attendance := &model.Attendance{
-		UserID:  userID,
-		Date:    today,
-		ClockIn: &now,
-		Status:  model.AttendanceStatusPresent,
-		Note:    req.Note,
+		UserID:   userID,
+		Date:     today,
+		ClockIn:  &now,
+		Status:   model.AttendanceStatusPresent,
+		Note:     req.Note,
+		WorkMode: workMode,
 	}
 
 	if err := s.deps.Repos.Attendance.Create(ctx, attendance); err != nil {
@@ -113,25 +157,149 @@ func (s *attendanceService) ClockOut(ctx context.Context, userID uuid.UUID, req
 		attendance.Note = req.Note
 	}
 
-	// 勤務時間を計算
-	if attendance.ClockIn != nil {
-		workDuration := now.Sub(*attendance.ClockIn)
-		attendance.WorkMinutes = int(workDuration.Minutes()) - attendance.BreakMinutes
-
-		// 8時間超過を残業として計算
-		standardMinutes := 8 * 60
-		if attendance.WorkMinutes > standardMinutes {
-			attendance.OvertimeMinutes = attendance.WorkMinutes - standardMinutes
-		}
+	// 勤務時間と給与計算用の内訳を計算（振替休日・法定休日を考慮する）
+	kind, err := s.calendar.computeWorkTime(ctx, attendance)
+	if err != nil {
+		return nil, err
 	}
 
 	if err := s.deps.Repos.Attendance.Update(ctx, attendance); err != nil {
 		return nil, err
 	}
 
+	if kind != dayKindWorkday && attendance.WorkMinutes > 0 {
+		if err := s.calendar.grantCompensatoryDay(ctx, userID, attendance.Date); err != nil {
+			logWarn(s.deps, "代休の付与に失敗しました", "user_id", userID, "error", err)
+		}
+	}
+
 	return attendance, nil
 }
 
+// CloseOpenAttendances は前日以前の退勤打刻漏れの勤怠を設定に従って処理し、処理件数を返す。
+// 他のインスタンスが処理中の場合は何もしない
+func (s *attendanceService) CloseOpenAttendances(ctx context.Context, now time.Time) (int, error) {
+	mode := AutoCloseModeIncomplete
+	if s.deps.Config != nil && s.deps.Config.AttendanceAutoCloseMode != "" {
+		mode = s.deps.Config.AttendanceAutoCloseMode
+	}
+	if mode == AutoCloseModeOff {
+		return 0, nil
+	}
+
+	closed := 0
+	_, err := s.deps.Repos.Attendance.WithAutoCloseLock(ctx, func() error {
+		var err error
+		closed, err = s.closeOpenAttendances(ctx, mode, now)
+		return err
+	})
+	return closed, err
+}
+
+// closeOpenAttendances は設定されたタイムゾーンで前日以前の打刻漏れの勤怠を処理する
+func (s *attendanceService) closeOpenAttendances(ctx context.Context, mode string, now time.Time) (int, error) {
+	open, err := s.deps.Repos.Attendance.FindOpenBefore(ctx, localDate(s.deps, now))
+	if err != nil {
+		return 0, err
+	}
+	closed := 0
+	for i := range open {
+		att := &open[i]
+		if mode == AutoCloseModeShiftEnd {
+			end := s.scheduledEnd(ctx, att)
+			if end.After(now) {
+				// 夜勤などまだ勤務中の可能性がある場合は次回に持ち越す
+				continue
+			}
+			att.ClockOut = &end
+			att.AutoClosedAt = &now
+			kind, err := s.calendar.computeWorkTime(ctx, att)
+			if err != nil {
+				return closed, err
+			}
+			if err := s.deps.Repos.Attendance.Update(ctx, att); err != nil {
+				return closed, err
+			}
+			if kind != dayKindWorkday && att.WorkMinutes > 0 {
+				if err := s.calendar.grantCompensatoryDay(ctx, att.UserID, att.Date); err != nil {
+					logWarn(s.deps, "代休の付与に失敗しました", "user_id", att.UserID, "error", err)
+				}
+			}
+		} else {
+			att.Status = model.AttendanceStatusIncomplete
+			if err := s.deps.Repos.Attendance.Update(ctx, att); err != nil {
+				return closed, err
+			}
+		}
+		closed++
+	}
+	return closed, nil
+}
+
+// scheduledEnd は勤怠日のシフト終了時刻を返す。シフトが無い場合は出勤時刻に所定労働時間を加えた時刻とする
+func (s *attendanceService) scheduledEnd(ctx context.Context, att *model.Attendance) time.Time {
+	fallback := att.ClockIn.Add(time.Duration(s.calendar.scheduledWorkMinutes()) * time.Minute)
+	if s.deps.Repos.Shift == nil {
+		return fallback
+	}
+	shifts, err := s.deps.Repos.Shift.FindByUserAndDateRange(ctx, att.UserID, att.Date, att.Date)
+	if err != nil {
+		return fallback
+	}
+	for _, sh := range shifts {
+		if sh.ShiftType == model.ShiftTypeOff || sh.EndTime == nil {
+			continue
+		}
+		y, m, d := att.Date.Date()
+		end := time.Date(y, m, d, sh.EndTime.Hour(), sh.EndTime.Minute(), 0, 0, location(s.deps))
+		if !end.After(*att.ClockIn) {
+			// 日付をまたぐシフト
+			end = end.AddDate(0, 0, 1)
+		}
+		if end.After(*att.ClockIn) {
+			return end
+		}
+	}
+	return fallback
+}
+
+// checkRemoteAllowance は所属部署の在宅勤務ポリシーで定められた週（月〜日）の上限日数を検証する
+func (s *attendanceService) checkRemoteAllowance(ctx context.Context, userID uuid.UUID, date time.Time) error {
+	if s.deps.Repos.RemoteWorkPolicy == nil {
+		return nil
+	}
+	departmentID := userDepartmentID(ctx, s.deps, userID)
+	if departmentID == nil {
+		return nil
+	}
+	policy, err := s.deps.Repos.RemoteWorkPolicy.FindByDepartmentID(ctx, *departmentID)
+	if err != nil {
+		return nil
+	}
+
+	verr := &model.ValidationError{}
+	if policy.MaxRemoteDaysPerWeek == 0 {
+		verr.Add("work_mode", "所属部署では在宅勤務が許可されていません")
+		return verr
+	}
+	weekStart := date.AddDate(0, 0, -((int(date.Weekday()) + 6) % 7))
+	attendances, _, err := s.deps.Repos.Attendance.FindByUserAndDateRange(ctx, userID, weekStart, weekStart.AddDate(0, 0, 6), 1, 7)
+	if err != nil {
+		return err
+	}
+	used := 0
+	for _, a := range attendances {
+		if a.WorkMode == model.WorkModeRemote && !sameDate(a.Date, date) {
+			used++
+		}
+	}
+	if used >= policy.MaxRemoteDaysPerWeek {
+		verr.Add("work_mode", fmt.Sprintf("今週の在宅勤務は上限（%d日）に達しています", policy.MaxRemoteDaysPerWeek))
+		return verr
+	}
+	return nil
+}
+
 func (s *attendanceService) GetByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time, page, pageSize int) ([]model.Attendance, int64, error) {
 	return s.deps.Repos.Attendance.FindByUserAndDateRange(ctx, userID, start, end, page, pageSize)
 }
@@ -186,6 +354,14 @@ func (s *leaveService) Create(ctx context.Context, userID uuid.UUID, req *model.
 		return nil, err
 	}
 
+	notifyApprovers(ctx, s.deps, s.notifier, userID, model.Notification{
+		Type:     model.NotificationTypeLeaveRequested,
+		Title:    "休暇申請",
+		Message:  withReason(fmt.Sprintf("%s〜%s の休暇申請の承認をお願いします。", req.StartDate, req.EndDate), req.Reason),
+		LinkURL:  "/leaves",
+		Approval: &model.ApprovalTarget{Kind: model.ApprovalKindLeave, ID: leave.ID},
+	})
+
 	return leave, nil
 }
 
@@ -198,6 +374,9 @@ func (s *leaveService) Approve(ctx context.Context, leaveID uuid.UUID, approverI
 	if leave.Status != model.ApprovalStatusPending {
 		return nil, ErrLeaveAlreadyProcessed
 	}
+	if err := authorizeApproval(ctx, s.deps, leave.UserID); err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	leave.Status = req.Status
@@ -210,6 +389,7 @@ func (s *leaveService) Approve(ctx context.Context, leaveID uuid.UUID, approverI
 	if err := s.deps.Repos.LeaveRequest.Update(ctx, leave); err != nil {
 		return nil, err
 	}
+	publishApproval(ctx, s.deps, leave.UserID, "leave", leave.ID, string(leave.Status))
 
 	// 申請者に通知を送信
 	if req.Status == model.ApprovalStatusApproved {
@@ -232,7 +412,14 @@ func (s *leaveService) GetByUser(ctx context.Context, userID uuid.UUID, page, pa
 }
 
 func (s *leaveService) GetPending(ctx context.Context, page, pageSize int) ([]model.LeaveRequest, int64, error) {
-	return s.deps.Repos.LeaveRequest.FindPending(ctx, page, pageSize)
+	all, userIDs, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, 0, err
+	}
+	if all {
+		return s.deps.Repos.LeaveRequest.FindPending(ctx, page, pageSize)
+	}
+	return s.deps.Repos.LeaveRequest.FindPendingByUserIDs(ctx, userIDs, page, pageSize)
 }
 
 // ===== OvertimeRequestService =====
@@ -266,6 +453,13 @@ func (s *overtimeRequestService) Create(ctx context.Context, userID uuid.UUID, r
 	if err := s.deps.Repos.OvertimeRequest.Create(ctx, overtime); err != nil {
 		return nil, err
 	}
+	notifyApprovers(ctx, s.deps, s.notifier, userID, model.Notification{
+		Type:     model.NotificationTypeOvertimeReq,
+		Title:    "残業申請",
+		Message:  withReason(fmt.Sprintf("%s の残業申請（予定 %d 分）の承認をお願いします。", req.Date, req.PlannedMinutes), req.Reason),
+		LinkURL:  "/overtime",
+		Approval: &model.ApprovalTarget{Kind: model.ApprovalKindOvertime, ID: overtime.ID},
+	})
 	return overtime, nil
 }
 
@@ -277,6 +471,9 @@ func (s *overtimeRequestService) Approve(ctx context.Context, id uuid.UUID, appr
 	if overtime.Status != model.OvertimeStatusPending {
 		return nil, errors.New("この残業申請は既に処理済みです")
 	}
+	if err := authorizeApproval(ctx, s.deps, overtime.UserID); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	overtime.Status = req.Status
 	overtime.ApprovedBy = &approverID
@@ -287,6 +484,7 @@ func (s *overtimeRequestService) Approve(ctx context.Context, id uuid.UUID, appr
 	if err := s.deps.Repos.OvertimeRequest.Update(ctx, overtime); err != nil {
 		return nil, err
 	}
+	publishApproval(ctx, s.deps, overtime.UserID, "overtime", overtime.ID, string(overtime.Status))
 	// 通知送信
 	notifType := model.NotificationTypeLeaveApproved
 	title := "残業申請が承認されました"
@@ -305,7 +503,14 @@ func (s *overtimeRequestService) GetByUser(ctx context.Context, userID uuid.UUID
 }
 
 func (s *overtimeRequestService) GetPending(ctx context.Context, page, pageSize int) ([]model.OvertimeRequest, int64, error) {
-	return s.deps.Repos.OvertimeRequest.FindPending(ctx, page, pageSize)
+	all, userIDs, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, 0, err
+	}
+	if all {
+		return s.deps.Repos.OvertimeRequest.FindPending(ctx, page, pageSize)
+	}
+	return s.deps.Repos.OvertimeRequest.FindPendingByUserIDs(ctx, userIDs, page, pageSize)
 }
 
 func (s *overtimeRequestService) GetOvertimeAlerts(ctx context.Context) ([]model.OvertimeAlert, error) {
@@ -439,10 +644,11 @@ type AttendanceCorrectionService interface {
 type attendanceCorrectionService struct {
 	deps     Deps
 	notifier NotificationSender
+	calendar dayCalendar
 }
 
 func NewAttendanceCorrectionService(deps Deps, notifier NotificationSender) AttendanceCorrectionService {
-	return &attendanceCorrectionService{deps: deps, notifier: notifier}
+	return &attendanceCorrectionService{deps: deps, notifier: notifier, calendar: dayCalendar{deps: deps}}
 }
 
 func (s *attendanceCorrectionService) Create(ctx context.Context, userID uuid.UUID, req *model.AttendanceCorrectionCreate) (*model.AttendanceCorrection, error) {
@@ -450,41 +656,65 @@ func (s *attendanceCorrectionService) Create(ctx context.Context, userID uuid.UU
 	if err != nil {
 		return nil, errors.New("日付の形式が不正です")
 	}
+	if req.CorrectedClockIn == nil && req.CorrectedClockOut == nil && req.CorrectedBreakMinutes == nil &&
+		req.CorrectedStatus == nil && req.CorrectedNote == nil {
+		return nil, errors.New("修正内容を1つ以上指定してください")
+	}
 	correction := &model.AttendanceCorrection{
 		UserID: userID, Date: date, Reason: req.Reason,
-		Status: model.CorrectionStatusPending,
+		Status:                model.CorrectionStatusPending,
+		CorrectedBreakMinutes: req.CorrectedBreakMinutes,
+		CorrectedStatus:       req.CorrectedStatus,
+		CorrectedNote:         req.CorrectedNote,
 	}
-	// 既存の出勤データを取得
+	// 既存の出勤データを取得（未登録の日は新規作成の申請になる）
 	existing, _ := s.deps.Repos.Attendance.FindByUserAndDate(ctx, userID, date)
 	if existing != nil {
 		correction.AttendanceID = &existing.ID
 		correction.OriginalClockIn = existing.ClockIn
 		correction.OriginalClockOut = existing.ClockOut
+		correction.OriginalBreakMinutes = &existing.BreakMinutes
+		correction.OriginalStatus = &existing.Status
+		correction.OriginalNote = &existing.Note
+	}
+
+	verr := &model.ValidationError{}
+	today := time.Now()
+	if date.After(time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)) {
+		verr.Add("date", "未来の日付は指定できません")
 	}
 	if req.CorrectedClockIn != nil {
-		t, err := time.Parse("2006-01-02T15:04:05", *req.CorrectedClockIn)
+		t, err := parseCorrectionTime(*req.CorrectedClockIn, date)
 		if err != nil {
-			t, err = time.Parse("15:04", *req.CorrectedClockIn)
-			if err == nil {
-				t = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
-			}
-		}
-		if err == nil {
+			verr.Add("corrected_clock_in", err.Error())
+		} else {
 			correction.CorrectedClockIn = &t
 		}
 	}
 	if req.CorrectedClockOut != nil {
-		t, err := time.Parse("2006-01-02T15:04:05", *req.CorrectedClockOut)
+		t, err := parseCorrectionTime(*req.CorrectedClockOut, date)
 		if err != nil {
-			t, err = time.Parse("15:04", *req.CorrectedClockOut)
-			if err == nil {
-				t = time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local)
-			}
-		}
-		if err == nil {
+			verr.Add("corrected_clock_out", err.Error())
+		} else {
 			correction.CorrectedClockOut = &t
 		}
 	}
+	if req.CorrectedBreakMinutes != nil && *req.CorrectedBreakMinutes < 0 {
+		verr.Add("corrected_break_minutes", "休憩時間は0分以上で指定してください")
+	}
+	if req.CorrectedStatus != nil && !isValidAttendanceStatus(*req.CorrectedStatus) {
+		verr.Add("corrected_status", "勤怠ステータスが不正です")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+	// 既存データを書き換えないよう複製に反映して検証する
+	preview := *correctionTarget(existing, correction)
+	applyCorrection(&preview, correction)
+	if err := validateCorrectedAttendance(&preview); err != nil {
+		return nil, err
+	}
+
 	if err := s.deps.Repos.AttendanceCorrection.Create(ctx, correction); err != nil {
 		return nil, err
 	}
@@ -499,62 +729,58 @@ func (s *attendanceCorrectionService) Approve(ctx context.Context, id uuid.UUID,
 	if correction.Status != model.CorrectionStatusPending {
 		return nil, errors.New("この修正申請は既に処理済みです")
 	}
+	if err := authorizeApproval(ctx, s.deps, correction.UserID); err != nil {
+		return nil, err
+	}
 	now := time.Now()
 	correction.Status = req.Status
 	correction.ApprovedBy = &approverID
 	correction.ApprovedAt = &now
+
 	if req.Status == model.CorrectionStatusRejected {
 		correction.RejectedReason = req.RejectedReason
-	}
-	// 承認時：勤怠データを修正
-	if req.Status == model.CorrectionStatusApproved {
+		if err := s.deps.Repos.AttendanceCorrection.Update(ctx, correction); err != nil {
+			return nil, err
+		}
+	} else {
+		// 承認時：勤怠データへの反映と申請の更新をまとめて保存する
+		var existing *model.Attendance
 		if correction.AttendanceID != nil {
-			att, _ := s.deps.Repos.Attendance.FindByID(ctx, *correction.AttendanceID)
-			if att != nil {
-				if correction.CorrectedClockIn != nil {
-					att.ClockIn = correction.CorrectedClockIn
-				}
-				if correction.CorrectedClockOut != nil {
-					att.ClockOut = correction.CorrectedClockOut
-				}
-				if att.ClockIn != nil && att.ClockOut != nil {
-					workDuration := att.ClockOut.Sub(*att.ClockIn)
-					att.WorkMinutes = int(workDuration.Minutes()) - att.BreakMinutes
-					if att.WorkMinutes > 480 {
-						att.OvertimeMinutes = att.WorkMinutes - 480
-					} else {
-						att.OvertimeMinutes = 0
-					}
-				}
-				_ = s.deps.Repos.Attendance.Update(ctx, att)
+			existing, err = s.deps.Repos.Attendance.FindByID(ctx, *correction.AttendanceID)
+			if err != nil {
+				return nil, errors.New("修正対象の勤怠データが見つかりません")
 			}
-		} else {
-			// 新規作成
-			att := &model.Attendance{
-				UserID: correction.UserID, Date: correction.Date,
-				ClockIn: correction.CorrectedClockIn, ClockOut: correction.CorrectedClockOut,
-				Status: model.AttendanceStatusPresent,
-			}
-			if att.ClockIn != nil && att.ClockOut != nil {
-				workDuration := att.ClockOut.Sub(*att.ClockIn)
-				att.WorkMinutes = int(workDuration.Minutes())
-				if att.WorkMinutes > 480 {
-					att.OvertimeMinutes = att.WorkMinutes - 480
-				}
+		}
+		att := correctionTarget(existing, correction)
+		applyCorrection(att, correction)
+		if err := validateCorrectedAttendance(att); err != nil {
+			return nil, err
+		}
+		kind, err := s.calendar.computeWorkTime(ctx, att)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.deps.Repos.AttendanceCorrection.ApplyApproval(ctx, correction, att); err != nil {
+			return nil, err
+		}
+		if kind != dayKindWorkday && att.WorkMinutes > 0 {
+			if err := s.calendar.grantCompensatoryDay(ctx, att.UserID, att.Date); err != nil {
+				logWarn(s.deps, "代休の付与に失敗しました", "user_id", att.UserID, "error", err)
 			}
-			_ = s.deps.Repos.Attendance.Create(ctx, att)
 		}
 	}
-	if err := s.deps.Repos.AttendanceCorrection.Update(ctx, correction); err != nil {
-		return nil, err
-	}
-	// 通知送信
+
+	publishApproval(ctx, s.deps, correction.UserID, "correction", correction.ID, string(correction.Status))
+
+	// 通知送信（保存済みの承認結果は取り消さない）
 	title := "勤怠修正申請が承認されました"
 	if req.Status == model.CorrectionStatusRejected {
 		title = "勤怠修正申請が却下されました"
 	}
-	_ = s.notifier.Send(ctx, correction.UserID, model.NotificationTypeCorrectionResult, title,
-		fmt.Sprintf("%s の勤怠修正申請が処理されました", correction.Date.Format("2006-01-02")))
+	if err := s.notifier.Send(ctx, correction.UserID, model.NotificationTypeCorrectionResult, title,
+		fmt.Sprintf("%s の勤怠修正申請が処理されました", correction.Date.Format("2006-01-02"))); err != nil {
+		logWarn(s.deps, "勤怠修正の通知送信に失敗しました", "correction_id", correction.ID, "error", err)
+	}
 	return correction, nil
 }
 
@@ -563,5 +789,1303 @@ func (s *attendanceCorrectionService) GetByUser(ctx context.Context, userID uuid
 }
 
 func (s *attendanceCorrectionService) GetPending(ctx context.Context, page, pageSize int) ([]model.AttendanceCorrection, int64, error) {
-	return s.deps.Repos.AttendanceCorrection.FindPending(ctx, page, pageSize)
+	all, userIDs, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, 0, err
+	}
+	if all {
+		return s.deps.Repos.AttendanceCorrection.FindPending(ctx, page, pageSize)
+	}
+	return s.deps.Repos.AttendanceCorrection.FindPendingByUserIDs(ctx, userIDs, page, pageSize)
+}
+
+// correctionTimeLayouts は修正申請で受け付ける時刻の形式
+var correctionTimeLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", time.RFC3339}
+
+// parseCorrectionTime は "HH:MM" または日時形式の時刻を解釈する。"HH:MM" は対象日の時刻として扱う
+func parseCorrectionTime(value string, date time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("15:04", value, time.Local); err == nil {
+		return time.Date(date.Year(), date.Month(), date.Day(), t.Hour(), t.Minute(), 0, 0, time.Local), nil
+	}
+	for _, layout := range correctionTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("時刻の形式が不正です（HH:MM または YYYY-MM-DDTHH:MM:SS）")
+}
+
+func isValidAttendanceStatus(status model.AttendanceStatus) bool {
+	switch status {
+	case model.AttendanceStatusPresent, model.AttendanceStatusAbsent,
+		model.AttendanceStatusLeave, model.AttendanceStatusHoliday:
+		return true
+	}
+	return false
+}
+
+// applyCorrection は修正申請の内容を勤怠データに反映する
+func applyCorrection(att *model.Attendance, correction *model.AttendanceCorrection) {
+	if correction.CorrectedClockIn != nil {
+		att.ClockIn = correction.CorrectedClockIn
+	}
+	if correction.CorrectedClockOut != nil {
+		att.ClockOut = correction.CorrectedClockOut
+	}
+	if correction.CorrectedBreakMinutes != nil {
+		att.BreakMinutes = *correction.CorrectedBreakMinutes
+	}
+	if correction.CorrectedStatus != nil {
+		att.Status = *correction.CorrectedStatus
+	}
+	if correction.CorrectedNote != nil {
+		att.Note = *correction.CorrectedNote
+	}
+	// 退勤打刻漏れは退勤時刻の修正をもって出勤として確定する
+	if att.Status == model.AttendanceStatusIncomplete && att.ClockOut != nil && correction.CorrectedStatus == nil {
+		att.Status = model.AttendanceStatusPresent
+	}
+}
+
+// correctionTarget は修正の反映先となる勤怠データを返す。既存データが無い日は新規の勤怠を作る
+func correctionTarget(existing *model.Attendance, correction *model.AttendanceCorrection) *model.Attendance {
+	if existing != nil {
+		return existing
+	}
+	return &model.Attendance{
+		UserID: correction.UserID, Date: correction.Date,
+		Status: model.AttendanceStatusPresent,
+	}
+}
+
+// validateCorrectedAttendance は修正反映後の勤怠データの整合性を検証する
+func validateCorrectedAttendance(att *model.Attendance) error {
+	verr := &model.ValidationError{}
+	now := time.Now()
+	if att.Status == model.AttendanceStatusIncomplete {
+		verr.Add("corrected_clock_out", "退勤打刻漏れの勤怠は退勤時刻を指定してください")
+	}
+	if att.ClockIn != nil && att.ClockIn.After(now) {
+		verr.Add("corrected_clock_in", "未来の時刻は指定できません")
+	}
+	if att.ClockOut != nil && att.ClockOut.After(now) {
+		verr.Add("corrected_clock_out", "未来の時刻は指定できません")
+	}
+	if att.ClockIn != nil && att.ClockOut != nil {
+		if !att.ClockOut.After(*att.ClockIn) {
+			verr.Add("corrected_clock_out", "退勤時刻は出勤時刻より後にしてください")
+		} else if float64(att.BreakMinutes) > att.ClockOut.Sub(*att.ClockIn).Minutes() {
+			verr.Add("corrected_break_minutes", "休憩時間が勤務時間を超えています")
+		}
+	}
+	return verr.OrNil()
+}
+
+// ===== 労働時間の内訳 =====
+
+const (
+	// statutoryWorkMinutes は1日の法定労働時間（分）
+	statutoryWorkMinutes = 8 * 60
+	// defaultScheduledWorkMinutes は1日の所定労働時間の既定値（分）
+	defaultScheduledWorkMinutes = statutoryWorkMinutes
+	// monthlyOvertimeThresholdMinutes は割増率が50%となる月間の法定外残業時間（分）
+	monthlyOvertimeThresholdMinutes = 60 * 60
+	// legalHolidayWeekday は法定休日とする曜日
+	legalHolidayWeekday = time.Sunday
+)
+
+// dayKind は労働時間の内訳計算における日の区分
+type dayKind int
+
+const (
+	dayKindWorkday      dayKind = iota // 勤務日
+	dayKindRestDay                     // 法定外休日（所定休日）
+	dayKindLegalHoliday                // 法定休日
+)
+
+// dailyRule は1日の勤務時間を法定内・法定外に区分する基準
+type dailyRule struct {
+	scheduledMinutes int  // 所定労働時間
+	thresholdMinutes int  // これを超える勤務は法定外残業
+	settleByPeriod   bool // フレックスタイム制：時間外労働は清算期間でまとめて判定する
+}
+
+// calculateWorkMinutes は出退勤時刻と休憩時間から勤務時間と給与計算用の内訳を計算する。
+// 法定休日の勤務は全時間を休日労働、法定外休日の勤務は全時間を法定外残業とする
+// （週40時間は所定勤務日で満たしている前提）。月60時間超の内訳は applyMonthlyOvertime で計算する
+func calculateWorkMinutes(att *model.Attendance, kind dayKind, rule dailyRule) {
+	if att.ClockIn == nil || att.ClockOut == nil {
+		return
+	}
+	att.WorkMinutes = int(att.ClockOut.Sub(*att.ClockIn).Minutes()) - att.BreakMinutes
+	if att.WorkMinutes < 0 {
+		att.WorkMinutes = 0
+	}
+	att.WithinStatutoryOvertimeMinutes = 0
+	att.OvertimeMinutes = 0
+	att.HolidayWorkMinutes = 0
+	att.OvertimeOver60Minutes = 0
+
+	// 休憩の時間帯は記録していないため、深夜時間は勤務時間を上限とする
+	att.LateNightMinutes = min(lateNightMinutes(*att.ClockIn, *att.ClockOut), att.WorkMinutes)
+
+	switch {
+	case kind == dayKindLegalHoliday:
+		att.HolidayWorkMinutes = att.WorkMinutes
+	case rule.settleByPeriod:
+		// 法定外休日の勤務も含め、清算期間の総労働時間で判定する
+	case kind == dayKindRestDay:
+		att.OvertimeMinutes = att.WorkMinutes
+	default:
+		// 基準時間の超過を法定外残業、所定労働時間超〜基準時間を法定内残業として計算
+		if att.WorkMinutes > rule.thresholdMinutes {
+			att.OvertimeMinutes = att.WorkMinutes - rule.thresholdMinutes
+		}
+		if att.WorkMinutes > rule.scheduledMinutes && rule.scheduledMinutes < rule.thresholdMinutes {
+			att.WithinStatutoryOvertimeMinutes = min(att.WorkMinutes, rule.thresholdMinutes) - rule.scheduledMinutes
+		}
+	}
+}
+
+// lateNightMinutes は start〜end のうち深夜時間帯（22:00〜翌5:00）に含まれる分数を返す
+func lateNightMinutes(start, end time.Time) int {
+	if !end.After(start) {
+		return 0
+	}
+	start, end = start.In(time.Local), end.In(time.Local)
+	var total time.Duration
+	// 前日22:00からの深夜帯も考慮するため、出勤日の前日から走査する
+	day := time.Date(start.Year(), start.Month(), start.Day()-1, 0, 0, 0, 0, time.Local)
+	for day.Before(end) {
+		from := time.Date(day.Year(), day.Month(), day.Day(), 22, 0, 0, 0, time.Local)
+		to := time.Date(day.Year(), day.Month(), day.Day()+1, 5, 0, 0, 0, time.Local)
+		if from.Before(end) && to.After(start) {
+			overlapStart, overlapEnd := from, to
+			if start.After(overlapStart) {
+				overlapStart = start
+			}
+			if end.Before(overlapEnd) {
+				overlapEnd = end
+			}
+			total += overlapEnd.Sub(overlapStart)
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return int(total.Minutes())
+}
+
+// logWarn は Logger 未設定時も安全に警告ログを出力する
+func logWarn(deps Deps, msg string, keysAndValues ...interface{}) {
+	if deps.Logger != nil {
+		deps.Logger.Warn(msg, keysAndValues...)
+	}
+}
+
+// notifyApprovers は申請者の上長（部署のマネージャー・人事上の上長）に承認依頼を通知する。
+// n.Title には申請の種類を渡し、申請者名を付けた件名にする。チャットに配信される場合は承認・却下ボタンが付く
+func notifyApprovers(ctx context.Context, deps Deps, notifier NotificationSender, requesterID uuid.UUID, n model.Notification) {
+	if notifier == nil || deps.Repos.Team == nil {
+		return
+	}
+	managerIDs, err := deps.Repos.Team.FindManagerIDs(ctx, requesterID)
+	if err != nil {
+		logWarn(deps, "承認依頼の宛先を取得できませんでした", "user_id", requesterID.String(), "error", err)
+		return
+	}
+	title := n.Title + "の承認依頼"
+	if deps.Repos.User != nil {
+		if requester, err := deps.Repos.User.FindByID(ctx, requesterID); err == nil {
+			title = fmt.Sprintf("%s %s さんの%s", requester.LastName, requester.FirstName, n.Title)
+		}
+	}
+	n.Title = title
+	for _, managerID := range managerIDs {
+		msg := n
+		msg.UserID = managerID
+		if err := notifier.Dispatch(ctx, &msg); err != nil {
+			logWarn(deps, "承認依頼を通知できませんでした", "user_id", managerID.String(), "error", err)
+		}
+	}
+}
+
+// withReason は通知の文面に申請理由を付ける
+func withReason(message, reason string) string {
+	if reason == "" {
+		return message
+	}
+	return message + "\n理由: " + reason
+}
+
+// publishApproval は申請者の画面へ承認状況の変更をリアルタイムで知らせる
+func publishApproval(ctx context.Context, deps Deps, userID uuid.UUID, kind string, id uuid.UUID, status string) {
+	deps.Events.PublishTo(ctx, userID, realtime.EventApproval, realtime.ApprovalChange{Kind: kind, ID: id, Status: status})
+}
+
+// ===== 休日判定 =====
+
+// defaultCompensatoryDayExpiryDays は代休の有効期限の既定値（日数）
+const defaultCompensatoryDayExpiryDays = 60
+
+// dayCalendar は公開済みのシフト・会社カレンダー・承認済みの振替休日から勤務日・休日を判定する
+type dayCalendar struct {
+	deps Deps
+}
+
+// isRestDay はユーザーにとって指定日が休日（法定休日・法定外休日）かどうかを返す
+func (c dayCalendar) isRestDay(ctx context.Context, userID uuid.UUID, date time.Time) (bool, error) {
+	kind, err := c.classifyDay(ctx, userID, date)
+	return kind != dayKindWorkday, err
+}
+
+// classifyDay はユーザーにとっての指定日の区分を返す。
+// 承認済みの振替休日があれば、振替出勤日は勤務日、振替先の日は元の出勤日の区分を引き継ぐ。
+// 振替がない日はシフト、シフトの対象外の日は会社カレンダーで判定する
+func (c dayCalendar) classifyDay(ctx context.Context, userID uuid.UUID, date time.Time) (dayKind, error) {
+	if c.deps.Repos.SubstituteHoliday != nil {
+		swaps, err := c.deps.Repos.SubstituteHoliday.FindApprovedByUserAndDateRange(ctx, userID, date, date)
+		if err != nil {
+			return dayKindWorkday, err
+		}
+		for _, swap := range swaps {
+			if swap.Type != model.SubstituteHolidayTypeFurikae {
+				continue
+			}
+			if swap.WorkDate != nil && sameDate(*swap.WorkDate, date) {
+				return dayKindWorkday, nil
+			}
+			if sameDate(swap.HolidayDate, date) {
+				if swap.WorkDate != nil {
+					if kind, err := c.scheduledDayKind(ctx, userID, *swap.WorkDate); err != nil || kind != dayKindWorkday {
+						return kind, err
+					}
+				}
+				return dayKindRestDay, nil
+			}
+		}
+	}
+	return c.scheduledDayKind(ctx, userID, date)
+}
+
+// scheduledDayKind は振替を考慮しないユーザーの日の区分を返す。
+// 公開済みのシフトがあれば勤務日（「休み」のシフトは休日）、公開済みのシフト表の期間内で
+// シフトがない日は休日とし、どちらにも当たらない日は会社カレンダーで判定する
+func (c dayCalendar) scheduledDayKind(ctx context.Context, userID uuid.UUID, date time.Time) (dayKind, error) {
+	if c.deps.Repos.Shift != nil {
+		shifts, err := c.deps.Repos.Shift.FindByUserAndDateRange(ctx, userID, date, date)
+		if err != nil {
+			return dayKindWorkday, err
+		}
+		for _, shift := range shifts {
+			if shift.ShiftType != model.ShiftTypeOff {
+				return dayKindWorkday, nil
+			}
+		}
+		if len(shifts) > 0 || c.inPublishedSchedule(ctx, userID, date) {
+			return shiftRestDayKind(date), nil
+		}
+	}
+	return c.calendarDayKind(ctx, date)
+}
+
+// inPublishedSchedule は所属部署の公開済みのシフト表の期間に指定日が含まれるかを返す
+func (c dayCalendar) inPublishedSchedule(ctx context.Context, userID uuid.UUID, date time.Time) bool {
+	if c.deps.Repos.ShiftSchedule == nil {
+		return false
+	}
+	departmentID := userDepartmentID(ctx, c.deps, userID)
+	if departmentID == nil {
+		return false
+	}
+	schedule, err := c.deps.Repos.ShiftSchedule.FindByDepartmentAndDate(ctx, *departmentID, date)
+	return err == nil && schedule.Status == model.ShiftScheduleStatusPublished
+}
+
+// shiftRestDayKind はシフト上の休みの日の区分を返す。法定休日の曜日は法定休日、それ以外は法定外休日
+func shiftRestDayKind(date time.Time) dayKind {
+	if date.Weekday() == legalHolidayWeekday {
+		return dayKindLegalHoliday
+	}
+	return dayKindRestDay
+}
+
+// calendarDayKind は振替を考慮しない会社カレンダー上の日の区分を返す
+func (c dayCalendar) calendarDayKind(ctx context.Context, date time.Time) (dayKind, error) {
+	if date.Weekday() == legalHolidayWeekday {
+		return dayKindLegalHoliday, nil
+	}
+	holiday, err := c.isCalendarHoliday(ctx, date)
+	if err != nil {
+		return dayKindWorkday, err
+	}
+	if holiday {
+		return dayKindRestDay, nil
+	}
+	return dayKindWorkday, nil
+}
+
+// computeWorkTime は勤怠レコードの勤務時間と給与計算用の内訳を計算し、日の区分を返す
+func (c dayCalendar) computeWorkTime(ctx context.Context, att *model.Attendance) (dayKind, error) {
+	kind, err := c.classifyDay(ctx, att.UserID, att.Date)
+	if err != nil {
+		return kind, err
+	}
+	rule, kind, err := c.dailyRule(ctx, att.UserID, att.Date, kind)
+	if err != nil {
+		return kind, err
+	}
+	calculateWorkMinutes(att, kind, rule)
+	return kind, c.applyMonthlyOvertime(ctx, att)
+}
+
+// scheduledWorkMinutes は通常の労働時間制における1日の所定労働時間を返す
+func (c dayCalendar) scheduledWorkMinutes() int {
+	if c.deps.Config != nil && c.deps.Config.ScheduledWorkMinutes > 0 {
+		return c.deps.Config.ScheduledWorkMinutes
+	}
+	return defaultScheduledWorkMinutes
+}
+
+// policyForUser はユーザーの所属部署の労働時間制度を返す（未設定の場合は nil）
+func (c dayCalendar) policyForUser(ctx context.Context, userID uuid.UUID) *model.WorkingHourPolicy {
+	if c.deps.Repos.WorkingHourPolicy == nil {
+		return nil
+	}
+	departmentID := userDepartmentID(ctx, c.deps, userID)
+	if departmentID == nil {
+		return nil
+	}
+	policy, err := c.deps.Repos.WorkingHourPolicy.FindByDepartmentID(ctx, *departmentID)
+	if err != nil {
+		return nil
+	}
+	return policy
+}
+
+// userDepartmentID はユーザーの所属部署IDを返す（ユーザー不明・未所属の場合は nil）
+func userDepartmentID(ctx context.Context, deps Deps, userID uuid.UUID) *uuid.UUID {
+	if deps.Repos.User == nil {
+		return nil
+	}
+	user, err := deps.Repos.User.FindByID(ctx, userID)
+	if err != nil {
+		return nil
+	}
+	return user.DepartmentID
+}
+
+// dailyRule は所属部署の労働時間制度から1日の区分基準を返す。
+// 変形労働時間制では勤務カレンダーの所定労働時間が日の区分にも優先する
+func (c dayCalendar) dailyRule(ctx context.Context, userID uuid.UUID, date time.Time, kind dayKind) (dailyRule, dayKind, error) {
+	rule := dailyRule{scheduledMinutes: c.scheduledWorkMinutes(), thresholdMinutes: statutoryWorkMinutes}
+	policy := c.policyForUser(ctx, userID)
+	if policy == nil {
+		return rule, kind, nil
+	}
+	switch policy.System {
+	case model.WorkingHourSystemFlextime:
+		rule.settleByPeriod = true
+	case model.WorkingHourSystemMonthlyVariable, model.WorkingHourSystemYearlyVariable:
+		if kind == dayKindLegalHoliday {
+			return rule, kind, nil
+		}
+		days, err := c.deps.Repos.WorkingHourPolicy.FindScheduleDays(ctx, policy.ID, date, date)
+		if err != nil {
+			return rule, kind, err
+		}
+		if len(days) == 0 {
+			// 勤務カレンダー未登録の日は通常の基準で計算する
+			return rule, kind, nil
+		}
+		if days[0].ScheduledMinutes == 0 {
+			return rule, dayKindRestDay, nil
+		}
+		// 所定が8時間を超える日は所定労働時間、それ以外の日は8時間を超えた分が法定外残業
+		rule.scheduledMinutes = days[0].ScheduledMinutes
+		rule.thresholdMinutes = max(days[0].ScheduledMinutes, statutoryWorkMinutes)
+		return rule, dayKindWorkday, nil
+	}
+	return rule, kind, nil
+}
+
+// applyMonthlyOvertime は当月の前日までの法定外残業の累計から、月60時間を超えた分を計算する。
+// 過去日の修正で累計が変わっても、以降の日の内訳は再計算しない
+func (c dayCalendar) applyMonthlyOvertime(ctx context.Context, att *model.Attendance) error {
+	att.OvertimeOver60Minutes = 0
+	if att.OvertimeMinutes == 0 {
+		return nil
+	}
+	prior := 0
+	monthStart := time.Date(att.Date.Year(), att.Date.Month(), 1, 0, 0, 0, 0, att.Date.Location())
+	if att.Date.After(monthStart) {
+		records, _, err := c.deps.Repos.Attendance.FindByUserAndDateRange(ctx, att.UserID, monthStart, att.Date.AddDate(0, 0, -1), 1, 31)
+		if err != nil {
+			return err
+		}
+		for _, r := range records {
+			if r.ID != att.ID && r.Date.Before(att.Date) {
+				prior += r.OvertimeMinutes
+			}
+		}
+	}
+	if over := prior + att.OvertimeMinutes - monthlyOvertimeThresholdMinutes; over > 0 {
+		att.OvertimeOver60Minutes = min(over, att.OvertimeMinutes)
+	}
+	return nil
+}
+
+// isCalendarHoliday は土日または会社カレンダー上の休日かどうかを返す
+func (c dayCalendar) isCalendarHoliday(ctx context.Context, date time.Time) (bool, error) {
+	if date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		return true, nil
+	}
+	if c.deps.Repos.Holiday == nil {
+		return false, nil
+	}
+	isHoliday, _, err := c.deps.Repos.Holiday.IsHoliday(ctx, date)
+	return isHoliday, err
+}
+
+// grantCompensatoryDay は休日出勤日に対する代休を付与する（同じ日の付与は1回のみ）
+func (c dayCalendar) grantCompensatoryDay(ctx context.Context, userID uuid.UUID, workDate time.Time) error {
+	if c.deps.Repos.CompensatoryDay == nil {
+		return nil
+	}
+	if existing, _ := c.deps.Repos.CompensatoryDay.FindByUserAndEarnedDate(ctx, userID, workDate); existing != nil {
+		return nil
+	}
+	expiryDays := defaultCompensatoryDayExpiryDays
+	if c.deps.Config != nil && c.deps.Config.CompensatoryDayExpiryDays > 0 {
+		expiryDays = c.deps.Config.CompensatoryDayExpiryDays
+	}
+	return c.deps.Repos.CompensatoryDay.Create(ctx, &model.CompensatoryDay{
+		UserID: userID, EarnedDate: workDate, ExpiresAt: workDate.AddDate(0, 0, expiryDays),
+	})
+}
+
+func sameDate(a, b time.Time) bool {
+	return a.Format("2006-01-02") == b.Format("2006-01-02")
+}
+
+// location は勤務日の区切りに使うタイムゾーンを返す（未設定の場合はサーバーのタイムゾーン）
+func location(deps Deps) *time.Location {
+	if deps.Config != nil && deps.Config.Location != nil {
+		return deps.Config.Location
+	}
+	return time.Local
+}
+
+// localDate は設定されたタイムゾーンでの t の日付を、日付カラムと同じ UTC の0時で返す
+func localDate(deps Deps, t time.Time) time.Time {
+	y, m, d := t.In(location(deps)).Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// ===== SubstituteHolidayService =====
+
+type SubstituteHolidayService interface {
+	Create(ctx context.Context, userID uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error)
+	Approve(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.SubstituteHolidayApproval) (*model.SubstituteHolidayRequest, error)
+	GetByUser(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	GetPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	GetCompensatoryDays(ctx context.Context, userID uuid.UUID) (*model.CompensatoryDaySummary, error)
+}
+
+type substituteHolidayService struct {
+	deps     Deps
+	notifier NotificationSender
+	calendar dayCalendar
+}
+
+func NewSubstituteHolidayService(deps Deps, notifier NotificationSender) SubstituteHolidayService {
+	return &substituteHolidayService{deps: deps, notifier: notifier, calendar: dayCalendar{deps: deps}}
+}
+
+func (s *substituteHolidayService) Create(ctx context.Context, userID uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error) {
+	verr := &model.ValidationError{}
+	holidayDate, err := time.Parse("2006-01-02", req.HolidayDate)
+	if err != nil {
+		verr.Add("holiday_date", "日付の形式が不正です")
+	}
+	var workDate *time.Time
+	if req.Type == model.SubstituteHolidayTypeFurikae {
+		d, err := time.Parse("2006-01-02", req.WorkDate)
+		if err != nil {
+			verr.Add("work_date", "振替休日では出勤日の指定が必要です")
+		} else {
+			workDate = &d
+		}
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	// 取得する休みは本来の勤務日でなければならない
+	restDay, err := s.calendar.isRestDay(ctx, userID, holidayDate)
+	if err != nil {
+		return nil, err
+	}
+	if restDay {
+		verr.Add("holiday_date", "休日には振替・代休を設定できません")
+	}
+
+	switch req.Type {
+	case model.SubstituteHolidayTypeFurikae:
+		// 振替休日は休日出勤より前に申請する
+		today := localDate(s.deps, time.Now())
+		if workDate.Before(today) {
+			verr.Add("work_date", "振替休日は出勤日より前に申請してください（事後の場合は代休を申請してください）")
+		}
+		workRestDay, err := s.calendar.isRestDay(ctx, userID, *workDate)
+		if err != nil {
+			return nil, err
+		}
+		if !workRestDay {
+			verr.Add("work_date", "出勤日には休日を指定してください")
+		}
+	case model.SubstituteHolidayTypeDaikyu:
+		// 承認待ちの代休申請はそれぞれ1日分を予約しているものとして数える
+		available, err := s.deps.Repos.CompensatoryDay.FindAvailable(ctx, userID, holidayDate)
+		if err != nil {
+			return nil, err
+		}
+		pending, err := s.deps.Repos.SubstituteHoliday.CountPendingDaikyu(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if int64(len(available)) <= pending {
+			verr.Add("holiday_date", "取得可能な代休がありません")
+		}
+	default:
+		verr.Add("type", "申請種別が不正です")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	request := &model.SubstituteHolidayRequest{
+		UserID: userID, Type: req.Type, WorkDate: workDate, HolidayDate: holidayDate,
+		Reason: req.Reason, Status: model.ApprovalStatusPending,
+	}
+	if err := s.deps.Repos.SubstituteHoliday.Create(ctx, request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *substituteHolidayService) Approve(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.SubstituteHolidayApproval) (*model.SubstituteHolidayRequest, error) {
+	request, err := s.deps.Repos.SubstituteHoliday.FindByID(ctx, id)
+	if err != nil {
+		return nil, errors.New("振替休日・代休申請が見つかりません")
+	}
+	if request.Status != model.ApprovalStatusPending {
+		return nil, errors.New("この申請は既に処理済みです")
+	}
+	if err := authorizeApproval(ctx, s.deps, request.UserID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	request.Status = req.Status
+	request.ApprovedBy = &approverID
+	request.ApprovedAt = &now
+
+	if req.Status == model.ApprovalStatusRejected {
+		request.RejectedReason = req.RejectedReason
+		if err := s.deps.Repos.SubstituteHoliday.Update(ctx, request); err != nil {
+			return nil, err
+		}
+	} else {
+		// 代休は有効期限の近いものから消化する
+		var day *model.CompensatoryDay
+		if request.Type == model.SubstituteHolidayTypeDaikyu {
+			available, err := s.deps.Repos.CompensatoryDay.FindAvailable(ctx, request.UserID, request.HolidayDate)
+			if err != nil {
+				return nil, err
+			}
+			if len(available) == 0 {
+				return nil, errors.New("取得可能な代休がありません")
+			}
+			day = &available[0]
+			day.UsedDate = &request.HolidayDate
+			day.RequestID = &request.ID
+			request.CompensatoryDayID = &day.ID
+		}
+		if err := s.deps.Repos.SubstituteHoliday.ApplyApproval(ctx, request, day); err != nil {
+			return nil, err
+		}
+	}
+
+	publishApproval(ctx, s.deps, request.UserID, "substitute_holiday", request.ID, string(request.Status))
+
+	notifType := model.NotificationTypeLeaveApproved
+	title := "振替休日・代休申請が承認されました"
+	if req.Status == model.ApprovalStatusRejected {
+		notifType = model.NotificationTypeLeaveRejected
+		title = "振替休日・代休申請が却下されました"
+	}
+	if err := s.notifier.Send(ctx, request.UserID, notifType, title,
+		fmt.Sprintf("%s の振替休日・代休申請が処理されました", request.HolidayDate.Format("2006-01-02"))); err != nil {
+		logWarn(s.deps, "振替休日・代休の通知送信に失敗しました", "request_id", request.ID, "error", err)
+	}
+	return request, nil
+}
+
+func (s *substituteHolidayService) GetByUser(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	return s.deps.Repos.SubstituteHoliday.FindByUserID(ctx, userID, page, pageSize)
+}
+
+func (s *substituteHolidayService) GetPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	all, userIDs, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, 0, err
+	}
+	if all {
+		return s.deps.Repos.SubstituteHoliday.FindPending(ctx, page, pageSize)
+	}
+	return s.deps.Repos.SubstituteHoliday.FindPendingByUserIDs(ctx, userIDs, page, pageSize)
+}
+
+func (s *substituteHolidayService) GetCompensatoryDays(ctx context.Context, userID uuid.UUID) (*model.CompensatoryDaySummary, error) {
+	days, err := s.deps.Repos.CompensatoryDay.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := localDate(s.deps, time.Now())
+	summary := &model.CompensatoryDaySummary{Days: days}
+	for _, d := range days {
+		if d.UsedDate == nil && !d.ExpiresAt.Before(today) {
+			summary.Available++
+		}
+	}
+	return summary, nil
+}
+
+// ===== WorkingHourPolicyService =====
+
+const (
+	// statutoryWeeklyWorkMinutes は週の法定労働時間（分）
+	statutoryWeeklyWorkMinutes = 40 * 60
+	// yearlyVariableMaxDailyMinutes は1年単位の変形労働時間制の1日の上限（分）
+	yearlyVariableMaxDailyMinutes = 10 * 60
+)
+
+type WorkingHourPolicyService interface {
+	List(ctx context.Context) ([]model.WorkingHourPolicy, error)
+	GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error)
+	Save(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourPolicyRequest) (*model.WorkingHourPolicy, error)
+	GetSchedule(ctx context.Context, departmentID uuid.UUID, date time.Time) ([]model.WorkingHourScheduleDay, error)
+	SetSchedule(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourScheduleRequest) ([]model.WorkingHourScheduleDay, error)
+	GetSettlement(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error)
+}
+
+type workingHourPolicyService struct {
+	deps     Deps
+	calendar dayCalendar
+}
+
+func NewWorkingHourPolicyService(deps Deps) WorkingHourPolicyService {
+	return &workingHourPolicyService{deps: deps, calendar: dayCalendar{deps: deps}}
+}
+
+func (s *workingHourPolicyService) List(ctx context.Context) ([]model.WorkingHourPolicy, error) {
+	return s.deps.Repos.WorkingHourPolicy.FindAll(ctx)
+}
+
+func (s *workingHourPolicyService) GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error) {
+	policy, err := s.deps.Repos.WorkingHourPolicy.FindByDepartmentID(ctx, departmentID)
+	if err != nil {
+		return nil, ErrWorkingHourPolicyNotFound
+	}
+	return policy, nil
+}
+
+// Save は部署の労働時間制度を登録・更新する
+func (s *workingHourPolicyService) Save(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourPolicyRequest) (*model.WorkingHourPolicy, error) {
+	verr := &model.ValidationError{}
+	periodStart, err := time.Parse("2006-01-02", req.PeriodStart)
+	if err != nil {
+		verr.Add("period_start", "日付の形式が不正です")
+	} else if periodStart.Day() > 28 {
+		verr.Add("period_start", "起算日は各月の1日〜28日で指定してください")
+	}
+
+	months := req.PeriodMonths
+	switch req.System {
+	case model.WorkingHourSystemStandard, model.WorkingHourSystemMonthlyVariable:
+		months = 1
+	case model.WorkingHourSystemFlextime:
+		if months == 0 {
+			months = 1
+		}
+		if months < 1 || months > 3 {
+			verr.Add("period_months", "フレックスタイム制の清算期間は1〜3ヶ月で指定してください")
+		}
+	case model.WorkingHourSystemYearlyVariable:
+		if months == 0 {
+			months = 12
+		}
+		if months < 2 || months > 12 {
+			verr.Add("period_months", "1年単位の変形労働時間制の対象期間は2〜12ヶ月で指定してください")
+		}
+	default:
+		verr.Add("system", "労働時間制度の種別が不正です")
+	}
+
+	standardMinutes := req.StandardDailyMinutes
+	if standardMinutes == 0 {
+		standardMinutes = statutoryWorkMinutes
+	}
+	if standardMinutes < 1 || standardMinutes > 24*60 {
+		verr.Add("standard_daily_minutes", "1日の標準労働時間は1〜1440分で指定してください")
+	}
+
+	coreStart, coreEnd := req.CoreTimeStart, req.CoreTimeEnd
+	if req.System != model.WorkingHourSystemFlextime {
+		coreStart, coreEnd = "", ""
+	} else if coreStart != "" || coreEnd != "" {
+		startTime, startErr := time.Parse("15:04", coreStart)
+		endTime, endErr := time.Parse("15:04", coreEnd)
+		if startErr != nil {
+			verr.Add("core_time_start", "時刻の形式が不正です（HH:MM）")
+		}
+		if endErr != nil {
+			verr.Add("core_time_end", "時刻の形式が不正です（HH:MM）")
+		}
+		if startErr == nil && endErr == nil && !endTime.After(startTime) {
+			verr.Add("core_time_end", "コアタイムの終了は開始より後にしてください")
+		}
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	policy, err := s.deps.Repos.WorkingHourPolicy.FindByDepartmentID(ctx, departmentID)
+	isNew := err != nil
+	if isNew {
+		policy = &model.WorkingHourPolicy{DepartmentID: departmentID}
+	}
+	policy.System = req.System
+	policy.PeriodStart = periodStart
+	policy.PeriodMonths = months
+	policy.StandardDailyMinutes = standardMinutes
+	policy.CoreTimeStart = coreStart
+	policy.CoreTimeEnd = coreEnd
+
+	if isNew {
+		err = s.deps.Repos.WorkingHourPolicy.Create(ctx, policy)
+	} else {
+		err = s.deps.Repos.WorkingHourPolicy.Update(ctx, policy)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// GetSchedule は指定日を含む対象期間の勤務カレンダーを返す
+func (s *workingHourPolicyService) GetSchedule(ctx context.Context, departmentID uuid.UUID, date time.Time) ([]model.WorkingHourScheduleDay, error) {
+	policy, err := s.GetByDepartment(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := settlementPeriod(policy, date)
+	return s.deps.Repos.WorkingHourPolicy.FindScheduleDays(ctx, policy.ID, start, end)
+}
+
+// SetSchedule は変形労働時間制の対象期間の勤務カレンダーを登録する。
+// 期間内の全日を指定し、所定労働時間の総枠が法定労働時間の総枠以内であることを検証する
+func (s *workingHourPolicyService) SetSchedule(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourScheduleRequest) ([]model.WorkingHourScheduleDay, error) {
+	policy, err := s.GetByDepartment(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if policy.System != model.WorkingHourSystemMonthlyVariable && policy.System != model.WorkingHourSystemYearlyVariable {
+		return nil, errors.New("勤務カレンダーは変形労働時間制の部署のみ登録できます")
+	}
+
+	verr := &model.ValidationError{}
+	periodStart, err := time.Parse("2006-01-02", req.PeriodStart)
+	if err != nil {
+		verr.Add("period_start", "日付の形式が不正です")
+		return nil, verr
+	}
+	start, end := settlementPeriod(policy, periodStart)
+	if !sameDate(start, periodStart) {
+		verr.Add("period_start", fmt.Sprintf("対象期間の開始日（%s）を指定してください", start.Format("2006-01-02")))
+		return nil, verr
+	}
+
+	periodDays := int(end.Sub(start).Hours()/24) + 1
+	seen := make(map[string]bool, len(req.Days))
+	days := make([]model.WorkingHourScheduleDay, 0, len(req.Days))
+	total := 0
+	for i, d := range req.Days {
+		field := fmt.Sprintf("days[%d]", i)
+		date, err := time.Parse("2006-01-02", d.Date)
+		if err != nil {
+			verr.Add(field+".date", "日付の形式が不正です")
+			continue
+		}
+		if date.Before(start) || date.After(end) {
+			verr.Add(field+".date", "対象期間外の日付です")
+			continue
+		}
+		if seen[d.Date] {
+			verr.Add(field+".date", "日付が重複しています")
+			continue
+		}
+		seen[d.Date] = true
+		if d.ScheduledMinutes < 0 || d.ScheduledMinutes > 24*60 {
+			verr.Add(field+".scheduled_minutes", "所定労働時間は0〜1440分で指定してください")
+			continue
+		}
+		if policy.System == model.WorkingHourSystemYearlyVariable && d.ScheduledMinutes > yearlyVariableMaxDailyMinutes {
+			verr.Add(field+".scheduled_minutes", "1年単位の変形労働時間制では1日10時間を超えて設定できません")
+			continue
+		}
+		total += d.ScheduledMinutes
+		days = append(days, model.WorkingHourScheduleDay{PolicyID: policy.ID, Date: date, ScheduledMinutes: d.ScheduledMinutes})
+	}
+	if len(seen) != periodDays && len(verr.Fields) == 0 {
+		verr.Add("days", fmt.Sprintf("対象期間の全日（%d日分）を指定してください", periodDays))
+	}
+	if limit := statutoryLimitMinutes(start, end); total > limit {
+		verr.Add("days", fmt.Sprintf("所定労働時間の合計（%d分）が法定労働時間の総枠（%d分）を超えています", total, limit))
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	if err := s.deps.Repos.WorkingHourPolicy.ReplaceScheduleDays(ctx, policy.ID, start, end, days); err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+// GetSettlement は指定日を含む清算期間・対象期間について部署メンバーの締め集計を返す。
+// 集計するのは操作主体の承認範囲（付与された部署・直属の部下）に含まれるメンバーのみで、
+// 範囲内のメンバーがいない部署は ErrForbidden とする
+func (s *workingHourPolicyService) GetSettlement(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error) {
+	all, approvable, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, err
+	}
+	users, err := s.deps.Repos.User.FindByDepartmentID(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		users = slices.DeleteFunc(users, func(u model.User) bool { return !slices.Contains(approvable, u.ID) })
+		if len(users) == 0 {
+			return nil, ErrForbidden
+		}
+	}
+
+	policy, err := s.GetByDepartment(ctx, departmentID)
+	if err != nil {
+		return nil, err
+	}
+	start, end := settlementPeriod(policy, date)
+	limit := statutoryLimitMinutes(start, end)
+	required, err := s.requiredMinutes(ctx, policy, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.WorkingHourSettlementReport{
+		DepartmentID:          departmentID,
+		System:                policy.System,
+		PeriodStart:           start.Format("2006-01-02"),
+		PeriodEnd:             end.Format("2006-01-02"),
+		RequiredMinutes:       required,
+		StatutoryLimitMinutes: limit,
+		Users:                 make([]model.WorkingHourSettlementUser, 0, len(users)),
+	}
+	for _, user := range users {
+		attendances, _, err := s.deps.Repos.Attendance.FindByUserAndDateRange(ctx, user.ID, start, end, 1, 400)
+		if err != nil {
+			return nil, err
+		}
+		row, err := s.settleUser(ctx, policy, attendances, required, limit)
+		if err != nil {
+			return nil, err
+		}
+		row.UserID = user.ID
+		row.UserName = user.LastName + " " + user.FirstName
+		report.Users = append(report.Users, *row)
+	}
+	return report, nil
+}
+
+// settleUser は1人分の期間集計を行う。
+// フレックスタイム制は総労働時間を法定労働時間の総枠・所定総労働時間と比較し、
+// 変形労働時間制は日ごとの法定外残業に加えて総枠を超えた分を法定外残業とする
+func (s *workingHourPolicyService) settleUser(ctx context.Context, policy *model.WorkingHourPolicy, attendances []model.Attendance, required, limit int) (*model.WorkingHourSettlementUser, error) {
+	row := &model.WorkingHourSettlementUser{CoreTimeViolations: []string{}}
+	dailyOvertime, dailyWithin := 0, 0
+	for i := range attendances {
+		att := &attendances[i]
+		row.ActualMinutes += att.WorkMinutes - att.HolidayWorkMinutes
+		row.HolidayWorkMinutes += att.HolidayWorkMinutes
+		row.LateNightMinutes += att.LateNightMinutes
+		dailyOvertime += att.OvertimeMinutes
+		dailyWithin += att.WithinStatutoryOvertimeMinutes
+
+		if policy.System == model.WorkingHourSystemFlextime {
+			violated, err := s.violatesCoreTime(ctx, policy, att)
+			if err != nil {
+				return nil, err
+			}
+			if violated {
+				row.CoreTimeViolations = append(row.CoreTimeViolations, att.Date.Format("2006-01-02"))
+			}
+		}
+	}
+
+	switch policy.System {
+	case model.WorkingHourSystemFlextime:
+		row.OvertimeMinutes = max(row.ActualMinutes-limit, 0)
+		row.WithinStatutoryOvertimeMinutes = max(min(row.ActualMinutes, limit)-required, 0)
+		row.ShortageMinutes = max(required-row.ActualMinutes, 0)
+	case model.WorkingHourSystemMonthlyVariable, model.WorkingHourSystemYearlyVariable:
+		// 日ごとに法定外残業とした時間を除いても総枠を超える分は、期間の法定外残業となる
+		row.OvertimeMinutes = dailyOvertime + max(row.ActualMinutes-dailyOvertime-limit, 0)
+		row.WithinStatutoryOvertimeMinutes = dailyWithin
+	default:
+		row.OvertimeMinutes = dailyOvertime
+		row.WithinStatutoryOvertimeMinutes = dailyWithin
+	}
+	return row, nil
+}
+
+// violatesCoreTime は勤務日にコアタイムの全時間帯を勤務していなければ true を返す
+func (s *workingHourPolicyService) violatesCoreTime(ctx context.Context, policy *model.WorkingHourPolicy, att *model.Attendance) (bool, error) {
+	if policy.CoreTimeStart == "" || policy.CoreTimeEnd == "" || att.Status != model.AttendanceStatusPresent {
+		return false, nil
+	}
+	kind, err := s.calendar.classifyDay(ctx, att.UserID, att.Date)
+	if err != nil || kind != dayKindWorkday {
+		return false, err
+	}
+	if att.ClockIn == nil || att.ClockOut == nil {
+		return true, nil
+	}
+	clockIn, clockOut := att.ClockIn.In(time.Local), att.ClockOut.In(time.Local)
+	if clockIn.Format("15:04") > policy.CoreTimeStart {
+		return true, nil
+	}
+	// 日をまたいで退勤した場合はコアタイム終了まで勤務している
+	return sameDate(clockIn, clockOut) && clockOut.Format("15:04") < policy.CoreTimeEnd, nil
+}
+
+// requiredMinutes は期間の所定総労働時間を返す
+func (s *workingHourPolicyService) requiredMinutes(ctx context.Context, policy *model.WorkingHourPolicy, start, end time.Time) (int, error) {
+	if policy.System == model.WorkingHourSystemMonthlyVariable || policy.System == model.WorkingHourSystemYearlyVariable {
+		days, err := s.deps.Repos.WorkingHourPolicy.FindScheduleDays(ctx, policy.ID, start, end)
+		if err != nil {
+			return 0, err
+		}
+		total := 0
+		for _, d := range days {
+			total += d.ScheduledMinutes
+		}
+		return total, nil
+	}
+
+	daily := policy.StandardDailyMinutes
+	if policy.System == model.WorkingHourSystemStandard {
+		daily = s.calendar.scheduledWorkMinutes()
+	}
+	total := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		kind, err := s.calendar.calendarDayKind(ctx, d)
+		if err != nil {
+			return 0, err
+		}
+		if kind == dayKindWorkday {
+			total += daily
+		}
+	}
+	// フレックスタイム制の総労働時間は法定労働時間の総枠を超えて定められない
+	if policy.System == model.WorkingHourSystemFlextime {
+		total = min(total, statutoryLimitMinutes(start, end))
+	}
+	return total, nil
+}
+
+// settlementPeriod は date を含む清算期間・対象期間を返す（起算日から PeriodMonths ヶ月ごとに区切る）
+func settlementPeriod(policy *model.WorkingHourPolicy, date time.Time) (time.Time, time.Time) {
+	months := max(policy.PeriodMonths, 1)
+	base := time.Date(policy.PeriodStart.Year(), policy.PeriodStart.Month(), policy.PeriodStart.Day(), 0, 0, 0, 0, time.UTC)
+	elapsed := (date.Year()-base.Year())*12 + int(date.Month()) - int(base.Month())
+	if date.Day() < base.Day() {
+		elapsed--
+	}
+	n := elapsed / months
+	if elapsed < 0 && elapsed%months != 0 {
+		n--
+	}
+	start := base.AddDate(0, n*months, 0)
+	return start, start.AddDate(0, months, -1)
+}
+
+// statutoryLimitMinutes は期間の法定労働時間の総枠（40時間×暦日数÷7）を返す
+func statutoryLimitMinutes(start, end time.Time) int {
+	days := int(end.Sub(start).Hours()/24) + 1
+	return statutoryWeeklyWorkMinutes * days / 7
+}
+
+// ===== RemoteWorkPolicyService =====
+
+type RemoteWorkPolicyService interface {
+	List(ctx context.Context) ([]model.RemoteWorkPolicy, error)
+	GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error)
+	Save(ctx context.Context, departmentID uuid.UUID, req *model.RemoteWorkPolicyRequest) (*model.RemoteWorkPolicy, error)
+}
+
+type remoteWorkPolicyService struct{ deps Deps }
+
+func NewRemoteWorkPolicyService(deps Deps) RemoteWorkPolicyService {
+	return &remoteWorkPolicyService{deps: deps}
+}
+
+func (s *remoteWorkPolicyService) List(ctx context.Context) ([]model.RemoteWorkPolicy, error) {
+	return s.deps.Repos.RemoteWorkPolicy.FindAll(ctx)
+}
+
+func (s *remoteWorkPolicyService) GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error) {
+	policy, err := s.deps.Repos.RemoteWorkPolicy.FindByDepartmentID(ctx, departmentID)
+	if err != nil {
+		return nil, ErrRemoteWorkPolicyNotFound
+	}
+	return policy, nil
+}
+
+// Save は部署の在宅勤務ポリシーを登録・更新する
+func (s *remoteWorkPolicyService) Save(ctx context.Context, departmentID uuid.UUID, req *model.RemoteWorkPolicyRequest) (*model.RemoteWorkPolicy, error) {
+	if req.MaxRemoteDaysPerWeek == nil || *req.MaxRemoteDaysPerWeek < 0 || *req.MaxRemoteDaysPerWeek > 7 {
+		verr := &model.ValidationError{}
+		verr.Add("max_remote_days_per_week", "週あたりの在宅勤務日数は0〜7日で指定してください")
+		return nil, verr
+	}
+
+	policy, err := s.deps.Repos.RemoteWorkPolicy.FindByDepartmentID(ctx, departmentID)
+	if err != nil {
+		policy = &model.RemoteWorkPolicy{DepartmentID: departmentID, MaxRemoteDaysPerWeek: *req.MaxRemoteDaysPerWeek}
+		if err := s.deps.Repos.RemoteWorkPolicy.Create(ctx, policy); err != nil {
+			return nil, err
+		}
+		return policy, nil
+	}
+	policy.MaxRemoteDaysPerWeek = *req.MaxRemoteDaysPerWeek
+	if err := s.deps.Repos.RemoteWorkPolicy.Update(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// ===== TeamService =====
+
+// approvalResolver は申請者と承認者の組織上の関係を解決する
+func approvalResolver(deps Deps) middleware.Resolver {
+	var r middleware.Resolver
+	if deps.Repos.User != nil {
+		r.DepartmentOf = func(ctx context.Context, ownerID uuid.UUID) (*uuid.UUID, error) {
+			user, err := deps.Repos.User.FindByID(ctx, ownerID)
+			if err != nil {
+				return nil, err
+			}
+			return user.DepartmentID, nil
+		}
+	}
+	if deps.Repos.Team != nil {
+		r.IsTeamMember = func(ctx context.Context, managerID, ownerID uuid.UUID) (bool, error) {
+			reportIDs, err := deps.Repos.Team.FindDirectReportIDs(ctx, managerID)
+			if err != nil {
+				return false, err
+			}
+			for _, id := range reportIDs {
+				if id == ownerID {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+	return r
+}
+
+// authorizeApproval は操作主体が申請者の申請を承認できる範囲かを確認する。
+// マネージャーは直属の部下、部署を指定した付与はその部署の申請に限られ、
+// 本人の申請は全社範囲の権限がある場合のみ承認できる。
+// サービスアカウントはユーザーとの組織上の関係を持たないため承認できない
+func authorizeApproval(ctx context.Context, deps Deps, requesterID uuid.UUID) error {
+	if middleware.IsSystem(ctx) {
+		return nil
+	}
+	actor, ok := middleware.ActorFromContext(ctx)
+	if !ok || actor.ServiceAccount {
+		return ErrForbidden
+	}
+	ownerID := requesterID
+	if requesterID == actor.UserID {
+		ownerID = uuid.Nil
+	}
+	err := actor.Authorize(ctx, middleware.PermAttendanceApprove, ownerID, approvalResolver(deps))
+	if errors.Is(err, middleware.ErrForbidden) {
+		return ErrNotDirectReport
+	}
+	return err
+}
+
+// approvableUserIDs は操作主体が申請を承認できる申請者を返す。
+// 全社範囲で承認できる場合は all が true になり、申請者は列挙しない
+func approvableUserIDs(ctx context.Context, deps Deps) (all bool, userIDs []uuid.UUID, err error) {
+	if middleware.IsSystem(ctx) {
+		return true, nil, nil
+	}
+	actor, ok := middleware.ActorFromContext(ctx)
+	if !ok || actor.ServiceAccount {
+		return false, nil, ErrForbidden
+	}
+	scope, departments := actor.ScopeFor(middleware.PermAttendanceApprove)
+	if scope == middleware.ScopeAll {
+		return true, nil, nil
+	}
+
+	seen := map[uuid.UUID]bool{actor.UserID: true}
+	add := func(id uuid.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	if scope == middleware.ScopeTeam && deps.Repos.Team != nil {
+		ids, err := deps.Repos.Team.FindDirectReportIDs(ctx, actor.UserID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, id := range ids {
+			add(id)
+		}
+	}
+	for _, departmentID := range departments {
+		members, err := deps.Repos.User.FindByDepartmentID(ctx, departmentID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, m := range members {
+			add(m.ID)
+		}
+	}
+	return false, userIDs, nil
+}
+
+type TeamService interface {
+	GetMembers(ctx context.Context, managerID uuid.UUID) ([]model.User, error)
+	GetTodayStatus(ctx context.Context, managerID uuid.UUID) ([]model.TeamMemberStatus, error)
+	GetSummaries(ctx context.Context, managerID uuid.UUID, start, end time.Time) ([]model.TeamMemberSummary, error)
+	GetPending(ctx context.Context, managerID uuid.UUID) (*model.TeamPendingRequests, error)
+}
+
+type teamService struct{ deps Deps }
+
+func NewTeamService(deps Deps) TeamService {
+	return &teamService{deps: deps}
+}
+
+// GetMembers は直属の部下を氏名順に返す
+func (s *teamService) GetMembers(ctx context.Context, managerID uuid.UUID) ([]model.User, error) {
+	ids, err := s.deps.Repos.Team.FindDirectReportIDs(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	members := make([]model.User, 0, len(ids))
+	for _, id := range ids {
+		user, err := s.deps.Repos.User.FindByID(ctx, id)
+		if err != nil || !user.IsActive {
+			continue
+		}
+		members = append(members, *user)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if members[i].LastName != members[j].LastName {
+			return members[i].LastName < members[j].LastName
+		}
+		return members[i].FirstName < members[j].FirstName
+	})
+	return members, nil
+}
+
+func (s *teamService) GetTodayStatus(ctx context.Context, managerID uuid.UUID) ([]model.TeamMemberStatus, error) {
+	members, err := s.GetMembers(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	today := time.Now().Truncate(24 * time.Hour)
+	statuses := make([]model.TeamMemberStatus, 0, len(members))
+	for _, m := range members {
+		status := model.TeamMemberStatus{User: m}
+		if att, err := s.deps.Repos.Attendance.FindByUserAndDate(ctx, m.ID, today); err == nil {
+			status.Attendance = att
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+func (s *teamService) GetSummaries(ctx context.Context, managerID uuid.UUID, start, end time.Time) ([]model.TeamMemberSummary, error) {
+	members, err := s.GetMembers(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+	summaries := make([]model.TeamMemberSummary, 0, len(members))
+	for _, m := range members {
+		summary, err := s.deps.Repos.Attendance.GetSummary(ctx, m.ID, start, end)
+		if err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, model.TeamMemberSummary{User: m, Summary: summary})
+	}
+	return summaries, nil
+}
+
+// GetPending は部下から提出された承認待ちの申請を種類別に返す
+func (s *teamService) GetPending(ctx context.Context, managerID uuid.UUID) (*model.TeamPendingRequests, error) {
+	ids, err := s.deps.Repos.Team.FindDirectReportIDs(ctx, managerID)
+	if err != nil {
+		return nil, err
+	}
+
+	pending := &model.TeamPendingRequests{
+		Leaves:             []model.LeaveRequest{},
+		OvertimeRequests:   []model.OvertimeRequest{},
+		Corrections:        []model.AttendanceCorrection{},
+		SubstituteHolidays: []model.SubstituteHolidayRequest{},
+	}
+	if len(ids) == 0 {
+		return pending, nil
+	}
+	leaves, _, err := s.deps.Repos.LeaveRequest.FindPendingByUserIDs(ctx, ids, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+	pending.Leaves = append(pending.Leaves, leaves...)
+	overtimes, _, err := s.deps.Repos.OvertimeRequest.FindPendingByUserIDs(ctx, ids, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+	pending.OvertimeRequests = append(pending.OvertimeRequests, overtimes...)
+	corrections, _, err := s.deps.Repos.AttendanceCorrection.FindPendingByUserIDs(ctx, ids, 1, 10000)
+	if err != nil {
+		return nil, err
+	}
+	pending.Corrections = append(pending.Corrections, corrections...)
+	if s.deps.Repos.SubstituteHoliday != nil {
+		requests, _, err := s.deps.Repos.SubstituteHoliday.FindPendingByUserIDs(ctx, ids, 1, 10000)
+		if err != nil {
+			return nil, err
+		}
+		pending.SubstituteHolidays = append(pending.SubstituteHolidays, requests...)
+	}
+	return pending, nil
 }
@@ -4,7 +4,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/your-org/kintai/backend/internal/handler"
 	"github.com/your-org/kintai/backend/internal/middleware"
-	"github.com/your-org/kintai/backend/internal/model"
 )
 
 func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw *middleware.Middleware) {
@@ -40,8 +39,36 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw
 		leaveBalance.GET("", h.LeaveBalance.GetMy)
 	}
 
+	substituteHolidays := protected.Group("/substitute-holidays")
+	{
+		substituteHolidays.POST("", h.SubstituteHoliday.Create)
+		substituteHolidays.GET("", h.SubstituteHoliday.GetMy)
+		substituteHolidays.GET("/compensatory-days", h.SubstituteHoliday.GetMyCompensatoryDays)
+	}
+
+	// 労働時間制度の設定は管理者のみ
+	workingHours := protected.Group("/working-hour-policies")
+	workingHours.Use(mw.RequirePermission(middleware.PermAttendanceAdmin))
+	{
+		workingHours.GET("", h.WorkingHourPolicy.List)
+		workingHours.GET("/:department_id", h.WorkingHourPolicy.GetByDepartment)
+		workingHours.PUT("/:department_id", h.WorkingHourPolicy.Save)
+		workingHours.GET("/:department_id/schedule", h.WorkingHourPolicy.GetSchedule)
+		workingHours.PUT("/:department_id/schedule", h.WorkingHourPolicy.SetSchedule)
+	}
+
+	// 在宅勤務ポリシーの設定は管理者のみ
+	remoteWork := protected.Group("/remote-work-policies")
+	remoteWork.Use(mw.RequirePermission(middleware.PermAttendanceAdmin))
+	{
+		remoteWork.GET("", h.RemoteWorkPolicy.List)
+		remoteWork.GET("/:department_id", h.RemoteWorkPolicy.GetByDepartment)
+		remoteWork.PUT("/:department_id", h.RemoteWorkPolicy.Save)
+	}
+
+	// 承認・部下の勤怠参照。承認範囲（直属の部下・付与された部署）はサービス層で判定する
 	admin := protected.Group("")
-	admin.Use(mw.RequireRole(model.RoleAdmin, model.RoleManager))
+	admin.Use(mw.RequirePermission(middleware.PermAttendanceApprove))
 	{
 		admin.GET("/leaves/pending", h.Leave.GetPending)
 		admin.PUT("/leaves/:id/approve", h.Leave.Approve)
@@ -53,6 +80,17 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw
 		admin.GET("/corrections/pending", h.AttendanceCorrection.GetPending)
 		admin.PUT("/corrections/:id/approve", h.AttendanceCorrection.Approve)
 
+		admin.GET("/substitute-holidays/pending", h.SubstituteHoliday.GetPending)
+		admin.PUT("/substitute-holidays/:id/approve", h.SubstituteHoliday.Approve)
+
+		admin.GET("/working-hour-policies/:department_id/settlement", h.WorkingHourPolicy.GetSettlement)
+
+		// 直属の部下（管理する部署の所属者・人事上の部下）の勤怠
+		admin.GET("/team/members", h.Team.GetMembers)
+		admin.GET("/team/today", h.Team.GetToday)
+		admin.GET("/team/summary", h.Team.GetSummary)
+		admin.GET("/team/pending", h.Team.GetPending)
+
 		admin.GET("/leave-balances/:user_id", h.LeaveBalance.GetByUser)
 		admin.PUT("/leave-balances/:user_id/:leave_type", h.LeaveBalance.SetBalance)
 		admin.POST("/leave-balances/:user_id/initialize", h.LeaveBalance.Initialize)
@@ -6,12 +6,21 @@ import (
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
 
 // UserRepository defines the user lookups required by expense services.
 type UserRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
+	FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) ([]model.User, error)
+}
+
+// TeamRepository resolves the direct reports of a manager for approval scoping,
+// and the managers of an employee for approval requests.
+type TeamRepository interface {
+	FindDirectReportIDs(ctx context.Context, managerID uuid.UUID) ([]uuid.UUID, error)
+	FindManagerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error)
 }
 
 // Repositories groups repository dependencies required by the expense app.
@@ -30,6 +39,12 @@ type Repositories struct {
 	ExpenseApprovalFlow        ExpenseApprovalFlowRepository
 	ExpenseDelegate            ExpenseDelegateRepository
 	ExpensePolicyViolation     ExpensePolicyViolationRepository
+	Team                       TeamRepository
+}
+
+// Notifier delivers expense notifications to the approvers and applicants.
+type Notifier interface {
+	Dispatch(ctx context.Context, n *model.Notification) error
 }
 
 // Deps defines dependencies for expense app services.
@@ -37,4 +52,8 @@ type Deps struct {
 	Repos  *Repositories
 	Config *config.Config
 	Logger *logger.Logger
+	// Notifier is optional; when nil, notifications are stored in-app without preferences or other channels.
+	Notifier Notifier
+	// Events is optional; when nil, approval status changes are not pushed to clients.
+	Events *realtime.Hub
 }
@@ -50,6 +50,9 @@ func (h *ExpenseHandler) GetByID(c *gin.Context) {
 		return
 	}
 	expense, err := h.svc.GetByID(c.Request.Context(), id)
+	if respondAuthorizationError(c, err) {
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "経費申請が見つかりません"})
 		return
@@ -93,6 +96,9 @@ func (h *ExpenseHandler) Update(c *gin.Context) {
 	}
 	expense, err := h.svc.Update(c.Request.Context(), id, userID, &req)
 	if err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -105,6 +111,9 @@ func (h *ExpenseHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -115,6 +124,9 @@ func (h *ExpenseHandler) GetPending(c *gin.Context) {
 	page, pageSize := parsePagination(c)
 	expenses, total, err := h.svc.GetPending(c.Request.Context(), page, pageSize)
 	if err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -142,6 +154,9 @@ func (h *ExpenseHandler) Approve(c *gin.Context) {
 		return
 	}
 	if err := h.svc.Approve(c.Request.Context(), id, approverID, &req); err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -164,6 +179,9 @@ func (h *ExpenseHandler) AdvancedApprove(c *gin.Context) {
 		return
 	}
 	if err := h.svc.AdvancedApprove(c.Request.Context(), id, approverID, &req); err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -249,6 +267,9 @@ func (h *ExpenseCommentHandler) GetComments(c *gin.Context) {
 	}
 	comments, err := h.svc.GetComments(c.Request.Context(), id)
 	if err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -272,6 +293,9 @@ func (h *ExpenseCommentHandler) AddComment(c *gin.Context) {
 	}
 	comment, err := h.svc.AddComment(c.Request.Context(), id, userID, &req)
 	if err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -296,6 +320,9 @@ func (h *ExpenseHistoryHandler) GetHistory(c *gin.Context) {
 	}
 	history, err := h.svc.GetHistory(c.Request.Context(), id)
 	if err != nil {
+		if respondAuthorizationError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
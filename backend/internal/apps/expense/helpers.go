@@ -1,17 +1,144 @@
 package expense
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 )
 
 var errUnauthorized = errors.New("unauthorized")
 
+var (
+	ErrForbidden            = middleware.ErrForbidden
+	ErrSelfApproval         = errors.New("自分の経費申請は承認できません")
+	ErrInvalidExpenseStatus = errors.New("申請者が設定できるステータスは下書きまたは申請中のみです")
+)
+
+// ownerResolver は申請者と操作主体の組織上の関係を解決する
+func ownerResolver(deps Deps) middleware.Resolver {
+	r := middleware.Resolver{
+		DepartmentOf: func(ctx context.Context, ownerID uuid.UUID) (*uuid.UUID, error) {
+			user, err := deps.Repos.User.FindByID(ctx, ownerID)
+			if err != nil {
+				return nil, nil
+			}
+			return user.DepartmentID, nil
+		},
+	}
+	if deps.Repos.Team != nil {
+		r.IsTeamMember = func(ctx context.Context, managerID, ownerID uuid.UUID) (bool, error) {
+			ids, err := deps.Repos.Team.FindDirectReportIDs(ctx, managerID)
+			if err != nil {
+				return false, err
+			}
+			for _, id := range ids {
+				if id == ownerID {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+	return r
+}
+
+// approvableUserIDs は操作主体が経費申請を承認できる申請者を返す。
+// 全社範囲で承認できる場合は all が true になり、申請者は列挙しない
+func approvableUserIDs(ctx context.Context, deps Deps) (all bool, userIDs []uuid.UUID, err error) {
+	if middleware.IsSystem(ctx) {
+		return true, nil, nil
+	}
+	actor, ok := middleware.ActorFromContext(ctx)
+	if !ok || actor.ServiceAccount {
+		return false, nil, ErrForbidden
+	}
+	scope, departments := actor.ScopeFor(middleware.PermExpenseApprove)
+	if scope == middleware.ScopeAll {
+		return true, nil, nil
+	}
+
+	// 本人の経費申請は承認できないため含めない
+	seen := map[uuid.UUID]bool{actor.UserID: true}
+	add := func(id uuid.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			userIDs = append(userIDs, id)
+		}
+	}
+	if scope == middleware.ScopeTeam && deps.Repos.Team != nil {
+		ids, err := deps.Repos.Team.FindDirectReportIDs(ctx, actor.UserID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, id := range ids {
+			add(id)
+		}
+	}
+	for _, departmentID := range departments {
+		members, err := deps.Repos.User.FindByDepartmentID(ctx, departmentID)
+		if err != nil {
+			return false, nil, err
+		}
+		for _, m := range members {
+			add(m.ID)
+		}
+	}
+	return false, userIDs, nil
+}
+
+// authorizeExpense は操作主体が経費申請に対して perm を持つか判定する
+func authorizeExpense(ctx context.Context, deps Deps, e *model.Expense, perm middleware.Permission) error {
+	return middleware.Authorize(ctx, perm, e.UserID, ownerResolver(deps))
+}
+
+// authorizeExpenseID は経費申請を取得して authorizeExpense を評価する。
+// 内部処理のコンテキストでは取得自体を省略する
+func authorizeExpenseID(ctx context.Context, deps Deps, id uuid.UUID, perm middleware.Permission) error {
+	if middleware.IsSystem(ctx) {
+		return nil
+	}
+	e, err := deps.Repos.Expense.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	return authorizeExpense(ctx, deps, e, perm)
+}
+
+// authorizeApproval は本人による承認を禁止し、承認範囲を判定する。
+// 精算済みへの変更は承認ではなく精算の権限で判定する。
+// サービスアカウントはユーザーとの組織上の関係を持たないため承認できない
+func authorizeApproval(ctx context.Context, deps Deps, e *model.Expense, approverID uuid.UUID, status model.ExpenseStatus) error {
+	if e.UserID == approverID {
+		return ErrSelfApproval
+	}
+	if status == model.ExpenseStatusReimbursed {
+		return authorizeExpense(ctx, deps, e, middleware.PermExpenseReimburse)
+	}
+	if actor, ok := middleware.ActorFromContext(ctx); ok && actor.ServiceAccount {
+		return ErrForbidden
+	}
+	return authorizeExpense(ctx, deps, e, middleware.PermExpenseApprove)
+}
+
+// respondAuthorizationError は権限・状態エラーであれば応答して true を返す
+func respondAuthorizationError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, ErrForbidden), errors.Is(err, ErrSelfApproval):
+		c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+	case errors.Is(err, ErrInvalidExpenseStatus):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+	default:
+		return false
+	}
+	return true
+}
+
 func getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	userIDStr, exists := c.Get("userID")
 	if !exists {
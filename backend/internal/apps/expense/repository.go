@@ -16,6 +16,7 @@ type ExpenseRepository interface {
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Expense, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int, status, category string) ([]model.Expense, int64, error)
 	FindPending(ctx context.Context, page, pageSize int) ([]model.Expense, int64, error)
+	FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.Expense, int64, error)
 	FindAll(ctx context.Context, page, pageSize int, status, category string) ([]model.Expense, int64, error)
 	Update(ctx context.Context, expense *model.Expense) error
 	Delete(ctx context.Context, id uuid.UUID) error
@@ -71,6 +72,20 @@ func (r *expenseRepository) FindPending(ctx context.Context, page, pageSize int)
 	return expenses, total, err
 }
 
+// FindPendingByUserIDs は userIDs のユーザーが提出した承認待ちの経費申請を返す
+func (r *expenseRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.Expense, int64, error) {
+	var expenses []model.Expense
+	var total int64
+	if len(userIDs) == 0 {
+		return expenses, 0, nil
+	}
+	q := r.db.WithContext(ctx).Where("status = ? AND user_id IN ?", model.ExpenseStatusPending, userIDs)
+	q.Model(&model.Expense{}).Count(&total)
+	err := q.Preload("Items").Preload("User").Order("created_at DESC").
+		Offset((page - 1) * pageSize).Limit(pageSize).Find(&expenses).Error
+	return expenses, total, err
+}
+
 func (r *expenseRepository) FindAll(ctx context.Context, page, pageSize int, status, category string) ([]model.Expense, int64, error) {
 	var expenses []model.Expense
 	var total int64
@@ -393,9 +408,10 @@ func (r *expenseBudgetRepository) FindAll(ctx context.Context) ([]model.ExpenseB
 
 // ===== ExpenseNotificationRepository =====
 
+// ExpenseNotificationRepository は共通の通知（notifications）のうち、経費申請に紐づくものを扱う
 type ExpenseNotificationRepository interface {
-	Create(ctx context.Context, notification *model.ExpenseNotification) error
-	FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error)
+	Create(ctx context.Context, notification *model.Notification) error
+	FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
 }
@@ -408,13 +424,19 @@ func NewExpenseNotificationRepository(db *gorm.DB) ExpenseNotificationRepository
 	return &expenseNotificationRepository{db: db}
 }
 
-func (r *expenseNotificationRepository) Create(ctx context.Context, notification *model.ExpenseNotification) error {
+// expenseScope は経費申請に紐づく通知、または経費の通知種別に絞り込む
+func (r *expenseNotificationRepository) expenseScope(ctx context.Context) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&model.Notification{}).
+		Where("expense_id IS NOT NULL OR type IN ?", model.NotificationTypesForModule("expense"))
+}
+
+func (r *expenseNotificationRepository) Create(ctx context.Context, notification *model.Notification) error {
 	return r.db.WithContext(ctx).Create(notification).Error
 }
 
-func (r *expenseNotificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
-	var notifications []model.ExpenseNotification
-	q := r.db.WithContext(ctx).Where("user_id = ?", userID)
+func (r *expenseNotificationRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error) {
+	var notifications []model.Notification
+	q := r.expenseScope(ctx).Where("user_id = ?", userID)
 	if filter == "unread" {
 		q = q.Where("is_read = false")
 	}
@@ -424,13 +446,13 @@ func (r *expenseNotificationRepository) FindByUserID(ctx context.Context, userID
 
 func (r *expenseNotificationRepository) MarkAsRead(ctx context.Context, id uuid.UUID) error {
 	now := time.Now()
-	return r.db.WithContext(ctx).Model(&model.ExpenseNotification{}).Where("id = ?", id).
+	return r.expenseScope(ctx).Where("id = ?", id).
 		Updates(map[string]interface{}{"is_read": true, "read_at": now}).Error
 }
 
 func (r *expenseNotificationRepository) MarkAllAsRead(ctx context.Context, userID uuid.UUID) error {
 	now := time.Now()
-	return r.db.WithContext(ctx).Model(&model.ExpenseNotification{}).
+	return r.expenseScope(ctx).
 		Where("user_id = ? AND is_read = false", userID).
 		Updates(map[string]interface{}{"is_read": true, "read_at": now}).Error
 }
@@ -11,7 +11,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 )
 
 // ===== ExpenseService =====
@@ -82,12 +84,22 @@ func (s *expenseService) Create(ctx context.Context, userID uuid.UUID, req *mode
 		NewValue:  string(expense.Status),
 		ChangedBy: userName,
 	})
+	if expense.Status == model.ExpenseStatusPending {
+		notifyApprovers(ctx, s.deps, expense, userName)
+	}
 
 	return expense, nil
 }
 
 func (s *expenseService) GetByID(ctx context.Context, id uuid.UUID) (*model.Expense, error) {
-	return s.deps.Repos.Expense.FindByID(ctx, id)
+	expense, err := s.deps.Repos.Expense.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeExpense(ctx, s.deps, expense, middleware.PermExpenseRead); err != nil {
+		return nil, err
+	}
+	return expense, nil
 }
 
 func (s *expenseService) GetList(ctx context.Context, userID uuid.UUID, page, pageSize int, status, category string) ([]model.Expense, int64, error) {
@@ -99,6 +111,17 @@ func (s *expenseService) Update(ctx context.Context, id uuid.UUID, userID uuid.U
 	if err != nil {
 		return nil, err
 	}
+	if err := authorizeExpense(ctx, s.deps, expense, middleware.PermExpenseWrite); err != nil {
+		return nil, err
+	}
+	// 承認系のステータスは承認APIでのみ変更できる
+	if req.Status != nil {
+		switch model.ExpenseStatus(*req.Status) {
+		case model.ExpenseStatusDraft, model.ExpenseStatusPending:
+		default:
+			return nil, ErrInvalidExpenseStatus
+		}
+	}
 
 	oldStatus := string(expense.Status)
 
@@ -151,16 +174,29 @@ func (s *expenseService) Update(ctx context.Context, id uuid.UUID, userID uuid.U
 		NewValue:  string(expense.Status),
 		ChangedBy: userName,
 	})
+	if oldStatus != string(model.ExpenseStatusPending) && expense.Status == model.ExpenseStatusPending {
+		notifyApprovers(ctx, s.deps, expense, userName)
+	}
 
 	return expense, nil
 }
 
 func (s *expenseService) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := authorizeExpenseID(ctx, s.deps, id, middleware.PermExpenseWrite); err != nil {
+		return err
+	}
 	return s.deps.Repos.Expense.Delete(ctx, id)
 }
 
 func (s *expenseService) GetPending(ctx context.Context, page, pageSize int) ([]model.Expense, int64, error) {
-	return s.deps.Repos.Expense.FindPending(ctx, page, pageSize)
+	all, userIDs, err := approvableUserIDs(ctx, s.deps)
+	if err != nil {
+		return nil, 0, err
+	}
+	if all {
+		return s.deps.Repos.Expense.FindPending(ctx, page, pageSize)
+	}
+	return s.deps.Repos.Expense.FindPendingByUserIDs(ctx, userIDs, page, pageSize)
 }
 
 func (s *expenseService) Approve(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.ExpenseApproveRequest) error {
@@ -168,6 +204,9 @@ func (s *expenseService) Approve(ctx context.Context, id uuid.UUID, approverID u
 	if err != nil {
 		return err
 	}
+	if err := authorizeApproval(ctx, s.deps, expense, approverID, model.ExpenseStatus(req.Status)); err != nil {
+		return err
+	}
 
 	now := time.Now()
 	expense.Status = model.ExpenseStatus(req.Status)
@@ -180,20 +219,21 @@ func (s *expenseService) Approve(ctx context.Context, id uuid.UUID, approverID u
 	if err := s.deps.Repos.Expense.Update(ctx, expense); err != nil {
 		return err
 	}
+	publishApproval(ctx, s.deps, expense)
 
 	// 通知作成
-	notifType := "approved"
-	message := fmt.Sprintf("経費申請「%s」が承認されました", expense.Title)
+	n := &model.Notification{
+		UserID:  expense.UserID,
+		Type:    model.NotificationTypeExpenseApproved,
+		Title:   "経費申請が承認されました",
+		Message: fmt.Sprintf("経費申請「%s」が承認されました", expense.Title),
+	}
 	if expense.Status == model.ExpenseStatusRejected {
-		notifType = "rejected"
-		message = fmt.Sprintf("経費申請「%s」が却下されました", expense.Title)
-	}
-	s.deps.Repos.ExpenseNotification.Create(ctx, &model.ExpenseNotification{
-		UserID:    expense.UserID,
-		ExpenseID: &expense.ID,
-		Type:      notifType,
-		Message:   message,
-	})
+		n.Type = model.NotificationTypeExpenseRejected
+		n.Title = "経費申請が却下されました"
+		n.Message = fmt.Sprintf("経費申請「%s」が却下されました", expense.Title)
+	}
+	sendNotification(ctx, s.deps, expense.ID, n)
 
 	// 履歴記録
 	approver, _ := s.deps.Repos.User.FindByID(ctx, approverID)
@@ -217,6 +257,9 @@ func (s *expenseService) AdvancedApprove(ctx context.Context, id uuid.UUID, appr
 	if err != nil {
 		return err
 	}
+	if err := authorizeApproval(ctx, s.deps, expense, approverID, model.ExpenseStatusApproved); err != nil {
+		return err
+	}
 
 	now := time.Now()
 	switch req.Action {
@@ -238,6 +281,7 @@ func (s *expenseService) AdvancedApprove(ctx context.Context, id uuid.UUID, appr
 	if err := s.deps.Repos.Expense.Update(ctx, expense); err != nil {
 		return err
 	}
+	publishApproval(ctx, s.deps, expense)
 
 	// 履歴記録
 	approver, _ := s.deps.Repos.User.FindByID(ctx, approverID)
@@ -416,6 +460,9 @@ func NewExpenseCommentService(deps Deps) ExpenseCommentService {
 }
 
 func (s *expenseCommentService) GetComments(ctx context.Context, expenseID uuid.UUID) ([]model.ExpenseCommentResponse, error) {
+	if err := authorizeExpenseID(ctx, s.deps, expenseID, middleware.PermExpenseRead); err != nil {
+		return nil, err
+	}
 	comments, err := s.deps.Repos.ExpenseComment.FindByExpenseID(ctx, expenseID)
 	if err != nil {
 		return nil, err
@@ -443,6 +490,9 @@ func (s *expenseCommentService) GetComments(ctx context.Context, expenseID uuid.
 }
 
 func (s *expenseCommentService) AddComment(ctx context.Context, expenseID, userID uuid.UUID, req *model.ExpenseCommentRequest) (*model.ExpenseCommentResponse, error) {
+	if err := authorizeExpenseID(ctx, s.deps, expenseID, middleware.PermExpenseRead); err != nil {
+		return nil, err
+	}
 	comment := &model.ExpenseComment{
 		ExpenseID: expenseID,
 		UserID:    userID,
@@ -461,11 +511,11 @@ func (s *expenseCommentService) AddComment(ctx context.Context, expenseID, userI
 	// 通知
 	expense, _ := s.deps.Repos.Expense.FindByID(ctx, expenseID)
 	if expense != nil && expense.UserID != userID {
-		s.deps.Repos.ExpenseNotification.Create(ctx, &model.ExpenseNotification{
-			UserID:    expense.UserID,
-			ExpenseID: &expenseID,
-			Type:      "comment",
-			Message:   fmt.Sprintf("%sが経費申請にコメントしました", userName),
+		sendNotification(ctx, s.deps, expenseID, &model.Notification{
+			UserID:  expense.UserID,
+			Type:    model.NotificationTypeExpenseComment,
+			Title:   "経費申請にコメントがありました",
+			Message: fmt.Sprintf("%sが経費申請「%s」にコメントしました", userName, expense.Title),
 		})
 	}
 
@@ -494,6 +544,9 @@ func NewExpenseHistoryService(deps Deps) ExpenseHistoryService {
 }
 
 func (s *expenseHistoryService) GetHistory(ctx context.Context, expenseID uuid.UUID) ([]model.ExpenseHistoryResponse, error) {
+	if err := authorizeExpenseID(ctx, s.deps, expenseID, middleware.PermExpenseRead); err != nil {
+		return nil, err
+	}
 	histories, err := s.deps.Repos.ExpenseHistory.FindByExpenseID(ctx, expenseID)
 	if err != nil {
 		return nil, err
@@ -717,7 +770,7 @@ func (s *expensePolicyService) GetPolicyViolations(ctx context.Context, userID u
 // ===== ExpenseNotificationService =====
 
 type ExpenseNotificationService interface {
-	GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error)
+	GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error)
 	MarkAsRead(ctx context.Context, id uuid.UUID) error
 	MarkAllAsRead(ctx context.Context, userID uuid.UUID) error
 	GetReminders(ctx context.Context, userID uuid.UUID) ([]model.ExpenseReminder, error)
@@ -734,7 +787,54 @@ func NewExpenseNotificationService(deps Deps) ExpenseNotificationService {
 	return &expenseNotificationService{deps: deps}
 }
 
-func (s *expenseNotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
+// sendNotification は経費申請に紐づく通知を送る。共通の通知（notifications）に保存されるため、
+// 経費の通知一覧と全体の通知一覧・未読件数の両方に表示される
+func sendNotification(ctx context.Context, deps Deps, expenseID uuid.UUID, n *model.Notification) {
+	n.ExpenseID = &expenseID
+	n.LinkURL = "/expenses/" + expenseID.String()
+	if deps.Notifier == nil {
+		if err := deps.Repos.ExpenseNotification.Create(ctx, n); err != nil {
+			deps.Logger.Warn("経費通知を保存できませんでした", "user_id", n.UserID.String(), "error", err)
+		}
+		return
+	}
+	if err := deps.Notifier.Dispatch(ctx, n); err != nil {
+		deps.Logger.Warn("経費通知を送信できませんでした", "user_id", n.UserID.String(), "error", err)
+	}
+}
+
+// notifyApprovers は申請者の上長に経費申請の承認依頼を通知する。チャットに配信される場合は承認・却下ボタンが付く
+func notifyApprovers(ctx context.Context, deps Deps, expense *model.Expense, requesterName string) {
+	if deps.Repos.Team == nil {
+		return
+	}
+	managerIDs, err := deps.Repos.Team.FindManagerIDs(ctx, expense.UserID)
+	if err != nil {
+		deps.Logger.Warn("承認依頼の宛先を取得できませんでした", "user_id", expense.UserID.String(), "error", err)
+		return
+	}
+	title := "経費申請の承認依頼"
+	if requesterName != "" {
+		title = requesterName + " さんの経費申請"
+	}
+	for _, managerID := range managerIDs {
+		sendNotification(ctx, deps, expense.ID, &model.Notification{
+			UserID:   managerID,
+			Type:     model.NotificationTypeExpenseSubmitted,
+			Title:    title,
+			Message:  fmt.Sprintf("経費申請「%s」（%.0f円）の承認をお願いします。", expense.Title, expense.TotalAmount),
+			Approval: &model.ApprovalTarget{Kind: model.ApprovalKindExpense, ID: expense.ID},
+		})
+	}
+}
+
+// publishApproval は申請者の画面へ経費申請の承認状況の変更をリアルタイムで知らせる
+func publishApproval(ctx context.Context, deps Deps, expense *model.Expense) {
+	deps.Events.PublishTo(ctx, expense.UserID, realtime.EventApproval,
+		realtime.ApprovalChange{Kind: "expense", ID: expense.ID, Status: string(expense.Status)})
+}
+
+func (s *expenseNotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error) {
 	return s.deps.Repos.ExpenseNotification.FindByUserID(ctx, userID, filter)
 }
 
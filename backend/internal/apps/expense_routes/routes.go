@@ -3,19 +3,16 @@ package expense_routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/your-org/kintai/backend/internal/handler"
+	"github.com/your-org/kintai/backend/internal/middleware"
 )
 
-func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers) {
+func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw *middleware.Middleware) {
+	// 個別の経費申請は本人・承認者の範囲をサービス層で判定する
 	expenses := protected.Group("/expenses")
 	{
 		expenses.GET("", h.Expense.GetList)
 		expenses.POST("", h.Expense.Create)
 		expenses.GET("/stats", h.Expense.GetStats)
-		expenses.GET("/pending", h.Expense.GetPending)
-		expenses.GET("/report", h.Expense.GetReport)
-		expenses.GET("/report/monthly", h.Expense.GetMonthlyTrend)
-		expenses.GET("/export/csv", h.Expense.ExportCSV)
-		expenses.GET("/export/pdf", h.Expense.ExportPDF)
 
 		expenses.POST("/receipts/upload", h.ExpenseReceipt.Upload)
 
@@ -26,9 +23,6 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers) {
 		expenses.POST("/templates/:id/use", h.ExpenseTemplate.UseTemplate)
 
 		expenses.GET("/policies", h.ExpensePolicy.GetPolicies)
-		expenses.POST("/policies", h.ExpensePolicy.Create)
-		expenses.PUT("/policies/:id", h.ExpensePolicy.Update)
-		expenses.DELETE("/policies/:id", h.ExpensePolicy.Delete)
 		expenses.GET("/budgets", h.ExpensePolicy.GetBudgets)
 		expenses.GET("/policy-violations", h.ExpensePolicy.GetPolicyViolations)
 
@@ -48,10 +42,33 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers) {
 		expenses.GET("/:id", h.Expense.GetByID)
 		expenses.PUT("/:id", h.Expense.Update)
 		expenses.DELETE("/:id", h.Expense.Delete)
-		expenses.PUT("/:id/approve", h.Expense.Approve)
-		expenses.PUT("/:id/advanced-approve", h.Expense.AdvancedApprove)
 		expenses.GET("/:id/comments", h.ExpenseComment.GetComments)
 		expenses.POST("/:id/comments", h.ExpenseComment.AddComment)
 		expenses.GET("/:id/history", h.ExpenseHistory.GetHistory)
 	}
+
+	approvals := protected.Group("/expenses")
+	approvals.Use(mw.RequirePermission(middleware.PermExpenseApprove, middleware.PermExpenseReimburse))
+	{
+		approvals.GET("/pending", h.Expense.GetPending)
+		approvals.PUT("/:id/approve", h.Expense.Approve)
+		approvals.PUT("/:id/advanced-approve", h.Expense.AdvancedApprove)
+	}
+
+	reports := protected.Group("/expenses")
+	reports.Use(mw.RequirePermission(middleware.PermExpenseReport))
+	{
+		reports.GET("/report", h.Expense.GetReport)
+		reports.GET("/report/monthly", h.Expense.GetMonthlyTrend)
+		reports.GET("/export/csv", h.Expense.ExportCSV)
+		reports.GET("/export/pdf", h.Expense.ExportPDF)
+	}
+
+	policies := protected.Group("/expenses/policies")
+	policies.Use(mw.RequirePermission(middleware.PermExpenseAdmin))
+	{
+		policies.POST("", h.ExpensePolicy.Create)
+		policies.PUT("/:id", h.ExpensePolicy.Update)
+		policies.DELETE("/:id", h.ExpensePolicy.Delete)
+	}
 }
@@ -7,11 +7,13 @@ import (
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
 
 // UserRepository defines user lookups required by HR services.
 type UserRepository interface {
+	FindByID(ctx context.Context, id uuid.UUID) (*model.User, error)
 	FindAll(ctx context.Context, page, pageSize int) ([]model.User, int64, error)
 }
 
@@ -57,9 +59,18 @@ type Repositories struct {
 	Survey       SurveyRepository
 }
 
+// Notifier delivers notifications sent by HR services.
+type Notifier interface {
+	Dispatch(ctx context.Context, n *model.Notification) error
+}
+
 // Deps defines dependencies for HR app services.
 type Deps struct {
 	Repos  *Repositories
 	Config *config.Config
 	Logger *logger.Logger
+	// Notifier is optional; when nil, HR services send no notifications.
+	Notifier Notifier
+	// Events is optional; when nil, announcements are not pushed to clients.
+	Events *realtime.Hub
 }
@@ -196,6 +196,12 @@ func (h *EvaluationHandler) Create(c *gin.Context) {
 	}
 	e, err := h.svc.Create(c.Request.Context(), req, userID)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -209,6 +215,12 @@ func (h *EvaluationHandler) GetByID(c *gin.Context) {
 	}
 	e, err := h.svc.FindByID(c.Request.Context(), id)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "隧穂ｾ｡縺瑚ｦ九▽縺九ｊ縺ｾ縺帙ｓ"})
 		return
 	}
@@ -221,6 +233,9 @@ func (h *EvaluationHandler) GetAll(c *gin.Context) {
 	status := c.Query("status")
 	list, total, err := h.svc.FindAll(c.Request.Context(), page, pageSize, cycleID, status)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -239,6 +254,12 @@ func (h *EvaluationHandler) Update(c *gin.Context) {
 	}
 	e, err := h.svc.Update(c.Request.Context(), id, req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -252,6 +273,12 @@ func (h *EvaluationHandler) Submit(c *gin.Context) {
 	}
 	e, err := h.svc.Submit(c.Request.Context(), id)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -305,6 +332,12 @@ func (h *GoalHandler) Create(c *gin.Context) {
 	}
 	g, err := h.svc.Create(c.Request.Context(), req, userID)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -318,6 +351,12 @@ func (h *GoalHandler) GetByID(c *gin.Context) {
 	}
 	g, err := h.svc.FindByID(c.Request.Context(), id)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "逶ｮ讓吶′隕九▽縺九ｊ縺ｾ縺帙ｓ"})
 		return
 	}
@@ -349,6 +388,12 @@ func (h *GoalHandler) Update(c *gin.Context) {
 	}
 	g, err := h.svc.Update(c.Request.Context(), id, req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -361,6 +406,12 @@ func (h *GoalHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -381,6 +432,12 @@ func (h *GoalHandler) UpdateProgress(c *gin.Context) {
 	}
 	g, err := h.svc.UpdateProgress(c.Request.Context(), id, body.Progress)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -915,6 +972,12 @@ func (h *OneOnOneHandler) Create(c *gin.Context) {
 	}
 	m, err := h.svc.Create(c.Request.Context(), req, managerID)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -928,6 +991,12 @@ func (h *OneOnOneHandler) GetByID(c *gin.Context) {
 	}
 	m, err := h.svc.FindByID(c.Request.Context(), id)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "1on1縺瑚ｦ九▽縺九ｊ縺ｾ縺帙ｓ"})
 		return
 	}
@@ -939,6 +1008,9 @@ func (h *OneOnOneHandler) GetAll(c *gin.Context) {
 	employeeID := c.Query("employee_id")
 	list, err := h.svc.FindAll(c.Request.Context(), status, employeeID)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -957,6 +1029,12 @@ func (h *OneOnOneHandler) Update(c *gin.Context) {
 	}
 	m, err := h.svc.Update(c.Request.Context(), id, req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -969,6 +1047,12 @@ func (h *OneOnOneHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.svc.Delete(c.Request.Context(), id); err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -987,6 +1071,12 @@ func (h *OneOnOneHandler) AddActionItem(c *gin.Context) {
 	}
 	m, err := h.svc.AddActionItem(c.Request.Context(), meetingID, req)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
@@ -1001,6 +1091,12 @@ func (h *OneOnOneHandler) ToggleActionItem(c *gin.Context) {
 	actionID := c.Param("actionId")
 	m, err := h.svc.ToggleActionItem(c.Request.Context(), meetingID, actionID)
 	if err != nil {
+		if respondForbiddenError(c, err) {
+			return
+		}
+		if respondEmployeeNotFound(c, err) {
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: err.Error()})
 		return
 	}
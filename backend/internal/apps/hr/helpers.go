@@ -7,11 +7,30 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 )
 
 var errUnauthorized = errors.New("unauthorized")
 
+// respondForbiddenError は権限範囲外の操作であれば 403 を返して true を返す
+func respondForbiddenError(c *gin.Context, err error) bool {
+	if !errors.Is(err, middleware.ErrForbidden) {
+		return false
+	}
+	c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+	return true
+}
+
+// respondEmployeeNotFound は対象の人事従業員が存在しなければ 404 を返して true を返す
+func respondEmployeeNotFound(c *gin.Context, err error) bool {
+	if !errors.Is(err, ErrEmployeeNotFound) {
+		return false
+	}
+	c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+	return true
+}
+
 func getUserIDFromContext(c *gin.Context) (uuid.UUID, error) {
 	userIDStr, exists := c.Get("userID")
 	if !exists {
@@ -17,6 +17,9 @@ type HREmployeeRepository interface {
 	Update(ctx context.Context, e *model.HREmployee) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByDepartmentID(ctx context.Context, deptID uuid.UUID) ([]model.HREmployee, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.HREmployee, error)
+	FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]model.HREmployee, error)
+	FindIDsByUserDepartments(ctx context.Context, departmentIDs []uuid.UUID) ([]uuid.UUID, error)
 	CountByStatus(ctx context.Context) (active int64, total int64, err error)
 }
 
@@ -72,6 +75,27 @@ func (r *hrEmployeeRepository) FindByDepartmentID(ctx context.Context, deptID uu
 	return list, err
 }
 
+func (r *hrEmployeeRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.HREmployee, error) {
+	var e model.HREmployee
+	err := r.db.WithContext(ctx).First(&e, "user_id = ?", userID).Error
+	return &e, err
+}
+
+func (r *hrEmployeeRepository) FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]model.HREmployee, error) {
+	var list []model.HREmployee
+	err := r.db.WithContext(ctx).Where("manager_id = ?", managerID).Find(&list).Error
+	return list, err
+}
+
+// FindIDsByUserDepartments は紐づくユーザーが指定部署に所属する人事従業員のIDを返す
+func (r *hrEmployeeRepository) FindIDsByUserDepartments(ctx context.Context, departmentIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&model.HREmployee{}).
+		Where("user_id IN (?)", r.db.Model(&model.User{}).Select("id").Where("department_id IN ?", departmentIDs)).
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 func (r *hrEmployeeRepository) CountByStatus(ctx context.Context) (int64, int64, error) {
 	var active, total int64
 	r.db.WithContext(ctx).Model(&model.HREmployee{}).Count(&total)
@@ -125,6 +149,7 @@ type EvaluationRepository interface {
 	Create(ctx context.Context, e *model.Evaluation) error
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Evaluation, error)
 	FindAll(ctx context.Context, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error)
+	FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error)
 	Update(ctx context.Context, e *model.Evaluation) error
 	CreateCycle(ctx context.Context, c *model.EvaluationCycle) error
 	FindAllCycles(ctx context.Context) ([]model.EvaluationCycle, error)
@@ -147,9 +172,16 @@ func (r *evaluationRepository) FindByID(ctx context.Context, id uuid.UUID) (*mod
 }
 
 func (r *evaluationRepository) FindAll(ctx context.Context, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error) {
+	return r.findAll(r.db.WithContext(ctx).Model(&model.Evaluation{}), page, pageSize, cycleID, status)
+}
+
+func (r *evaluationRepository) FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error) {
+	return r.findAll(r.db.WithContext(ctx).Model(&model.Evaluation{}).Where("employee_id IN ?", employeeIDs), page, pageSize, cycleID, status)
+}
+
+func (r *evaluationRepository) findAll(q *gorm.DB, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error) {
 	var list []model.Evaluation
 	var total int64
-	q := r.db.WithContext(ctx).Model(&model.Evaluation{})
 	if cycleID != "" {
 		q = q.Where("cycle_id = ?", cycleID)
 	}
@@ -470,6 +502,7 @@ type OneOnOneRepository interface {
 	Create(ctx context.Context, m *model.OneOnOneMeeting) error
 	FindByID(ctx context.Context, id uuid.UUID) (*model.OneOnOneMeeting, error)
 	FindAll(ctx context.Context, status, employeeID string) ([]model.OneOnOneMeeting, error)
+	FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, status, employeeID string) ([]model.OneOnOneMeeting, error)
 	Update(ctx context.Context, m *model.OneOnOneMeeting) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -491,8 +524,15 @@ func (r *oneOnOneRepository) FindByID(ctx context.Context, id uuid.UUID) (*model
 }
 
 func (r *oneOnOneRepository) FindAll(ctx context.Context, status, employeeID string) ([]model.OneOnOneMeeting, error) {
+	return r.findAll(r.db.WithContext(ctx), status, employeeID)
+}
+
+func (r *oneOnOneRepository) FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, status, employeeID string) ([]model.OneOnOneMeeting, error) {
+	return r.findAll(r.db.WithContext(ctx).Where("employee_id IN ?", employeeIDs), status, employeeID)
+}
+
+func (r *oneOnOneRepository) findAll(q *gorm.DB, status, employeeID string) ([]model.OneOnOneMeeting, error) {
 	var list []model.OneOnOneMeeting
-	q := r.db.WithContext(ctx)
 	if status != "" {
 		q = q.Where("status = ?", status)
 	}
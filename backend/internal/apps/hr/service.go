@@ -3,16 +3,44 @@ package hr
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"strconv"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"gorm.io/datatypes"
 )
 
+// ErrEmployeeNotFound は対象の人事従業員が登録されていないことを表す
+var ErrEmployeeNotFound = errors.New("人事従業員が見つかりません")
+
+// notifyEmployee は社員に紐づくユーザーへ共通のディスパッチャーで通知する。
+// ユーザーに紐づかない社員には通知しない。通知の失敗は業務処理を失敗させない
+func notifyEmployee(ctx context.Context, deps Deps, employeeID uuid.UUID, notifType model.NotificationType, title, message, link string) {
+	if deps.Notifier == nil {
+		return
+	}
+	emp, err := deps.Repos.HREmployee.FindByID(ctx, employeeID)
+	if err != nil || emp == nil || emp.UserID == nil {
+		return
+	}
+	err = deps.Notifier.Dispatch(ctx, &model.Notification{
+		UserID:  *emp.UserID,
+		Type:    notifType,
+		Title:   title,
+		Message: message,
+		LinkURL: link,
+	})
+	if err != nil {
+		deps.Logger.Warn("HR通知の送信に失敗しました", "user_id", emp.UserID.String(), "type", string(notifType), "error", err)
+	}
+}
+
 // ===== HREmployeeService =====
 
 type HREmployeeService interface {
@@ -62,11 +90,36 @@ func (s *hrEmployeeService) Create(ctx context.Context, req model.HREmployeeCrea
 }
 
 func (s *hrEmployeeService) FindByID(ctx context.Context, id uuid.UUID) (*model.HREmployee, error) {
-	return s.deps.Repos.HREmployee.FindByID(ctx, id)
+	e, err := s.deps.Repos.HREmployee.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	redactSalary(ctx, e)
+	return e, nil
 }
 
 func (s *hrEmployeeService) FindAll(ctx context.Context, page, pageSize int, department, status, employmentType, search string) ([]model.HREmployee, int64, error) {
-	return s.deps.Repos.HREmployee.FindAll(ctx, page, pageSize, department, status, employmentType, search)
+	list, total, err := s.deps.Repos.HREmployee.FindAll(ctx, page, pageSize, department, status, employmentType, search)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range list {
+		redactSalary(ctx, &list[i])
+	}
+	return list, total, nil
+}
+
+// redactSalary は給与閲覧権限のない操作主体に対して従業員と上長の給与情報を伏せる
+func redactSalary(ctx context.Context, e *model.HREmployee) {
+	if middleware.IsSystem(ctx) {
+		return
+	}
+	if actor, ok := middleware.ActorFromContext(ctx); ok && actor.Can(middleware.PermSalaryRead) {
+		return
+	}
+	for ; e != nil; e = e.Manager {
+		e.BaseSalary = 0
+	}
 }
 
 func (s *hrEmployeeService) Update(ctx context.Context, id uuid.UUID, req model.HREmployeeUpdateRequest) (*model.HREmployee, error) {
@@ -192,6 +245,83 @@ func (s *hrDepartmentService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.deps.Repos.HRDepartment.Delete(ctx, id)
 }
 
+// authorizeEmployee は人事従業員に対する perm の操作を判定する。
+// 所有者は従業員に紐づくユーザーとし、チーム範囲は人事上の上長が操作主体であるかで判定する
+func authorizeEmployee(ctx context.Context, deps Deps, perm middleware.Permission, employeeID uuid.UUID) error {
+	if middleware.IsSystem(ctx) {
+		return nil
+	}
+	emp, err := deps.Repos.HREmployee.FindByID(ctx, employeeID)
+	if err != nil || emp == nil {
+		return ErrEmployeeNotFound
+	}
+	var ownerID uuid.UUID
+	if emp.UserID != nil {
+		ownerID = *emp.UserID
+	}
+	return middleware.Authorize(ctx, perm, ownerID, employeeResolver(deps, emp.ManagerID))
+}
+
+func employeeResolver(deps Deps, managerEmpID *uuid.UUID) middleware.Resolver {
+	return middleware.Resolver{
+		IsTeamMember: func(ctx context.Context, managerID, _ uuid.UUID) (bool, error) {
+			if managerEmpID == nil {
+				return false, nil
+			}
+			mgr, err := deps.Repos.HREmployee.FindByID(ctx, *managerEmpID)
+			if err != nil || mgr == nil || mgr.UserID == nil {
+				return false, nil
+			}
+			return *mgr.UserID == managerID, nil
+		},
+		DepartmentOf: func(ctx context.Context, ownerID uuid.UUID) (*uuid.UUID, error) {
+			user, err := deps.Repos.User.FindByID(ctx, ownerID)
+			if err != nil || user == nil {
+				return nil, nil
+			}
+			return user.DepartmentID, nil
+		},
+	}
+}
+
+// scopedEmployeeIDs は perm の範囲で操作主体が扱える人事従業員のIDを返す。
+// 全社範囲であれば all に true を返す
+func scopedEmployeeIDs(ctx context.Context, deps Deps, perm middleware.Permission) (all bool, ids []uuid.UUID, err error) {
+	if middleware.IsSystem(ctx) {
+		return true, nil, nil
+	}
+	actor, ok := middleware.ActorFromContext(ctx)
+	if !ok {
+		return false, nil, middleware.ErrForbidden
+	}
+	scope, departments := actor.ScopeFor(perm)
+	if scope == middleware.ScopeAll {
+		return true, nil, nil
+	}
+	if self, err := deps.Repos.HREmployee.FindByUserID(ctx, actor.UserID); err == nil && self != nil {
+		if scope >= middleware.ScopeOwn {
+			ids = append(ids, self.ID)
+		}
+		if scope == middleware.ScopeTeam {
+			reports, err := deps.Repos.HREmployee.FindByManagerID(ctx, self.ID)
+			if err != nil {
+				return false, nil, err
+			}
+			for _, r := range reports {
+				ids = append(ids, r.ID)
+			}
+		}
+	}
+	if len(departments) > 0 {
+		deptIDs, err := deps.Repos.HREmployee.FindIDsByUserDepartments(ctx, departments)
+		if err != nil {
+			return false, nil, err
+		}
+		ids = append(ids, deptIDs...)
+	}
+	return false, ids, nil
+}
+
 // ===== EvaluationService =====
 
 type EvaluationService interface {
@@ -211,6 +341,9 @@ func NewEvaluationService(deps Deps) EvaluationService {
 }
 
 func (s *evaluationService) Create(ctx context.Context, req model.EvaluationCreateRequest, reviewerID uuid.UUID) (*model.Evaluation, error) {
+	if err := authorizeEmployee(ctx, s.deps, middleware.PermHREvaluate, req.EmployeeID); err != nil {
+		return nil, err
+	}
 	e := &model.Evaluation{
 		EmployeeID:     req.EmployeeID,
 		CycleID:        req.CycleID,
@@ -229,15 +362,34 @@ func (s *evaluationService) Create(ctx context.Context, req model.EvaluationCrea
 }
 
 func (s *evaluationService) FindByID(ctx context.Context, id uuid.UUID) (*model.Evaluation, error) {
-	return s.deps.Repos.Evaluation.FindByID(ctx, id)
+	return s.find(ctx, id)
+}
+
+// find は評価を取得し、対象従業員に対する評価権限の範囲を判定する
+func (s *evaluationService) find(ctx context.Context, id uuid.UUID) (*model.Evaluation, error) {
+	e, err := s.deps.Repos.Evaluation.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEmployee(ctx, s.deps, middleware.PermHREvaluate, e.EmployeeID); err != nil {
+		return nil, err
+	}
+	return e, nil
 }
 
 func (s *evaluationService) FindAll(ctx context.Context, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error) {
+	all, ids, err := scopedEmployeeIDs(ctx, s.deps, middleware.PermHREvaluate)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !all {
+		return s.deps.Repos.Evaluation.FindAllByEmployeeIDs(ctx, ids, page, pageSize, cycleID, status)
+	}
 	return s.deps.Repos.Evaluation.FindAll(ctx, page, pageSize, cycleID, status)
 }
 
 func (s *evaluationService) Update(ctx context.Context, id uuid.UUID, req model.EvaluationUpdateRequest) (*model.Evaluation, error) {
-	e, err := s.deps.Repos.Evaluation.FindByID(ctx, id)
+	e, err := s.find(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -266,7 +418,7 @@ func (s *evaluationService) Update(ctx context.Context, id uuid.UUID, req model.
 }
 
 func (s *evaluationService) Submit(ctx context.Context, id uuid.UUID) (*model.Evaluation, error) {
-	e, err := s.deps.Repos.Evaluation.FindByID(ctx, id)
+	e, err := s.find(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -274,6 +426,8 @@ func (s *evaluationService) Submit(ctx context.Context, id uuid.UUID) (*model.Ev
 	if err := s.deps.Repos.Evaluation.Update(ctx, e); err != nil {
 		return nil, err
 	}
+	notifyEmployee(ctx, s.deps, e.EmployeeID, model.NotificationTypeEvaluationSubmitted,
+		"評価が提出されました", "評価が提出されました。内容を確認してください", "/hr/evaluations")
 	return e, nil
 }
 
@@ -313,10 +467,25 @@ func NewGoalService(deps Deps) GoalService {
 	return &goalService{deps: deps}
 }
 
+// authorize は目標の対象従業員に対する操作を判定する
+func (s *goalService) authorize(ctx context.Context, employeeID uuid.UUID) error {
+	return authorizeEmployee(ctx, s.deps, middleware.PermHRGoalWrite, employeeID)
+}
+
 func (s *goalService) Create(ctx context.Context, req model.HRGoalCreateRequest, userID uuid.UUID) (*model.HRGoal, error) {
-	empID := userID
+	var empID uuid.UUID
 	if req.EmployeeID != nil {
 		empID = *req.EmployeeID
+	} else {
+		// 対象の指定がなければ自分の人事従業員レコードを対象とする
+		emp, err := s.deps.Repos.HREmployee.FindByUserID(ctx, userID)
+		if err != nil || emp == nil {
+			return nil, ErrEmployeeNotFound
+		}
+		empID = emp.ID
+	}
+	if err := s.authorize(ctx, empID); err != nil {
+		return nil, err
 	}
 	g := &model.HRGoal{
 		EmployeeID:  empID,
@@ -347,10 +516,31 @@ func (s *goalService) Create(ctx context.Context, req model.HRGoalCreateRequest,
 }
 
 func (s *goalService) FindByID(ctx context.Context, id uuid.UUID) (*model.HRGoal, error) {
-	return s.deps.Repos.Goal.FindByID(ctx, id)
+	g, err := s.deps.Repos.Goal.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorize(ctx, g.EmployeeID); err != nil {
+		return nil, err
+	}
+	return g, nil
 }
 
 func (s *goalService) FindAll(ctx context.Context, page, pageSize int, status, category, employeeID string) ([]model.HRGoal, int64, error) {
+	// 人事情報の閲覧権限がなければ自分の目標のみ
+	if !middleware.IsSystem(ctx) {
+		actor, ok := middleware.ActorFromContext(ctx)
+		if !ok {
+			return nil, 0, middleware.ErrForbidden
+		}
+		if !actor.Can(middleware.PermHRRead) {
+			emp, err := s.deps.Repos.HREmployee.FindByUserID(ctx, actor.UserID)
+			if err != nil || emp == nil {
+				return []model.HRGoal{}, 0, nil
+			}
+			employeeID = emp.ID.String()
+		}
+	}
 	return s.deps.Repos.Goal.FindAll(ctx, page, pageSize, status, category, employeeID)
 }
 
@@ -359,6 +549,9 @@ func (s *goalService) Update(ctx context.Context, id uuid.UUID, req model.HRGoal
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorize(ctx, g.EmployeeID); err != nil {
+		return nil, err
+	}
 	if req.Title != nil {
 		g.Title = *req.Title
 	}
@@ -389,6 +582,9 @@ func (s *goalService) UpdateProgress(ctx context.Context, id uuid.UUID, progress
 	if err != nil {
 		return nil, err
 	}
+	if err := s.authorize(ctx, g.EmployeeID); err != nil {
+		return nil, err
+	}
 	g.Progress = progress
 	if progress >= 100 {
 		g.Status = model.GoalStatusCompleted
@@ -402,6 +598,15 @@ func (s *goalService) UpdateProgress(ctx context.Context, id uuid.UUID, progress
 }
 
 func (s *goalService) Delete(ctx context.Context, id uuid.UUID) error {
+	if !middleware.IsSystem(ctx) {
+		g, err := s.deps.Repos.Goal.FindByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := s.authorize(ctx, g.EmployeeID); err != nil {
+			return err
+		}
+	}
 	return s.deps.Repos.Goal.Delete(ctx, id)
 }
 
@@ -501,7 +706,18 @@ func (s *trainingService) Enroll(ctx context.Context, programID, employeeID uuid
 		EmployeeID: employeeID,
 		Status:     "enrolled",
 	}
-	return s.deps.Repos.Training.CreateEnrollment(ctx, enrollment)
+	if err := s.deps.Repos.Training.CreateEnrollment(ctx, enrollment); err != nil {
+		return err
+	}
+	if s.deps.Notifier != nil {
+		title := "研修に登録されました"
+		if program, err := s.deps.Repos.Training.FindByID(ctx, programID); err == nil && program != nil {
+			title = fmt.Sprintf("研修「%s」に登録されました", program.Title)
+		}
+		notifyEmployee(ctx, s.deps, employeeID, model.NotificationTypeTrainingEnrolled,
+			title, title, "/hr/training")
+	}
+	return nil
 }
 
 func (s *trainingService) Complete(ctx context.Context, programID, employeeID uuid.UUID) error {
@@ -693,6 +909,7 @@ func (s *announcementService) Create(ctx context.Context, req model.Announcement
 	if err := s.deps.Repos.Announcement.Create(ctx, a); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, a)
 	return a, nil
 }
 
@@ -728,9 +945,19 @@ func (s *announcementService) Update(ctx context.Context, id uuid.UUID, req mode
 	if err := s.deps.Repos.Announcement.Update(ctx, a); err != nil {
 		return nil, err
 	}
+	s.publish(ctx, a)
 	return a, nil
 }
 
+// publish は公開中のお知らせの作成・更新を接続中の全ユーザーに知らせる
+func (s *announcementService) publish(ctx context.Context, a *model.HRAnnouncement) {
+	if !a.IsPublished {
+		return
+	}
+	s.deps.Events.Broadcast(ctx, realtime.EventAnnouncement,
+		realtime.AnnouncementChange{ID: a.ID, Title: a.Title, Priority: string(a.Priority)})
+}
+
 func (s *announcementService) Delete(ctx context.Context, id uuid.UUID) error {
 	return s.deps.Repos.Announcement.Delete(ctx, id)
 }
@@ -1158,6 +1385,9 @@ func (s *oneOnOneService) Create(ctx context.Context, req model.OneOnOneCreateRe
 	if err != nil {
 		return nil, fmt.Errorf("invalid employee_id: %w", err)
 	}
+	if err := authorizeEmployee(ctx, s.deps, middleware.PermHROneOnOne, empID); err != nil {
+		return nil, err
+	}
 	scheduledDate, _ := time.Parse("2006-01-02T15:04:05Z07:00", req.ScheduledDate)
 	if scheduledDate.IsZero() {
 		scheduledDate, _ = time.Parse("2006-01-02", req.ScheduledDate)
@@ -1178,19 +1408,40 @@ func (s *oneOnOneService) Create(ctx context.Context, req model.OneOnOneCreateRe
 	if err := s.deps.Repos.OneOnOne.Create(ctx, m); err != nil {
 		return nil, err
 	}
+	notifyEmployee(ctx, s.deps, empID, model.NotificationTypeOneOnOneScheduled,
+		"1on1が設定されました", fmt.Sprintf("%s に1on1ミーティングが設定されました", scheduledDate.Format("2006/01/02 15:04")), "/hr/one-on-one")
 	return m, nil
 }
 
 func (s *oneOnOneService) FindByID(ctx context.Context, id uuid.UUID) (*model.OneOnOneMeeting, error) {
-	return s.deps.Repos.OneOnOne.FindByID(ctx, id)
+	return s.find(ctx, id)
+}
+
+// find は1on1を取得し、対象従業員に対する1on1権限の範囲を判定する
+func (s *oneOnOneService) find(ctx context.Context, id uuid.UUID) (*model.OneOnOneMeeting, error) {
+	m, err := s.deps.Repos.OneOnOne.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeEmployee(ctx, s.deps, middleware.PermHROneOnOne, m.EmployeeID); err != nil {
+		return nil, err
+	}
+	return m, nil
 }
 
 func (s *oneOnOneService) FindAll(ctx context.Context, status, employeeID string) ([]model.OneOnOneMeeting, error) {
+	all, ids, err := scopedEmployeeIDs(ctx, s.deps, middleware.PermHROneOnOne)
+	if err != nil {
+		return nil, err
+	}
+	if !all {
+		return s.deps.Repos.OneOnOne.FindAllByEmployeeIDs(ctx, ids, status, employeeID)
+	}
 	return s.deps.Repos.OneOnOne.FindAll(ctx, status, employeeID)
 }
 
 func (s *oneOnOneService) Update(ctx context.Context, id uuid.UUID, req model.OneOnOneUpdateRequest) (*model.OneOnOneMeeting, error) {
-	m, err := s.deps.Repos.OneOnOne.FindByID(ctx, id)
+	m, err := s.find(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -1213,11 +1464,14 @@ func (s *oneOnOneService) Update(ctx context.Context, id uuid.UUID, req model.On
 }
 
 func (s *oneOnOneService) Delete(ctx context.Context, id uuid.UUID) error {
+	if _, err := s.find(ctx, id); err != nil {
+		return err
+	}
 	return s.deps.Repos.OneOnOne.Delete(ctx, id)
 }
 
 func (s *oneOnOneService) AddActionItem(ctx context.Context, meetingID uuid.UUID, req model.ActionItemRequest) (*model.OneOnOneMeeting, error) {
-	m, err := s.deps.Repos.OneOnOne.FindByID(ctx, meetingID)
+	m, err := s.find(ctx, meetingID)
 	if err != nil {
 		return nil, err
 	}
@@ -1240,7 +1494,7 @@ func (s *oneOnOneService) AddActionItem(ctx context.Context, meetingID uuid.UUID
 }
 
 func (s *oneOnOneService) ToggleActionItem(ctx context.Context, meetingID uuid.UUID, actionID string) (*model.OneOnOneMeeting, error) {
-	m, err := s.deps.Repos.OneOnOne.FindByID(ctx, meetingID)
+	m, err := s.find(ctx, meetingID)
 	if err != nil {
 		return nil, err
 	}
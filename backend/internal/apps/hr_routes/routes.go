@@ -3,33 +3,15 @@ package hr_routes
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/your-org/kintai/backend/internal/handler"
+	"github.com/your-org/kintai/backend/internal/middleware"
 )
 
-func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers) {
+func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw *middleware.Middleware) {
+	// 全従業員が利用できる機能（目標は本人・部下の範囲をサービス層で判定する）
 	hr := protected.Group("/hr")
 	{
-		hr.GET("/stats", h.HRDashboard.GetStats)
-		hr.GET("/activities", h.HRDashboard.GetActivities)
-
-		hr.GET("/employees", h.HREmployee.GetAll)
-		hr.GET("/employees/:id", h.HREmployee.GetByID)
-		hr.POST("/employees", h.HREmployee.Create)
-		hr.PUT("/employees/:id", h.HREmployee.Update)
-		hr.DELETE("/employees/:id", h.HREmployee.Delete)
-
 		hr.GET("/departments", h.HRDepartment.GetAll)
 		hr.GET("/departments/:id", h.HRDepartment.GetByID)
-		hr.POST("/departments", h.HRDepartment.Create)
-		hr.PUT("/departments/:id", h.HRDepartment.Update)
-		hr.DELETE("/departments/:id", h.HRDepartment.Delete)
-
-		hr.GET("/evaluations", h.Evaluation.GetAll)
-		hr.GET("/evaluations/:id", h.Evaluation.GetByID)
-		hr.POST("/evaluations", h.Evaluation.Create)
-		hr.PUT("/evaluations/:id", h.Evaluation.Update)
-		hr.PUT("/evaluations/:id/submit", h.Evaluation.Submit)
-		hr.GET("/evaluation-cycles", h.Evaluation.GetCycles)
-		hr.POST("/evaluation-cycles", h.Evaluation.CreateCycle)
 
 		hr.GET("/goals", h.Goal.GetAll)
 		hr.GET("/goals/:id", h.Goal.GetByID)
@@ -40,79 +22,139 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers) {
 
 		hr.GET("/training", h.Training.GetAll)
 		hr.GET("/training/:id", h.Training.GetByID)
-		hr.POST("/training", h.Training.Create)
-		hr.PUT("/training/:id", h.Training.Update)
-		hr.DELETE("/training/:id", h.Training.Delete)
 		hr.POST("/training/:id/enroll", h.Training.Enroll)
 		hr.PUT("/training/:id/complete", h.Training.Complete)
 
-		hr.GET("/positions", h.Recruitment.GetAllPositions)
-		hr.GET("/positions/:id", h.Recruitment.GetPosition)
-		hr.POST("/positions", h.Recruitment.CreatePosition)
-		hr.PUT("/positions/:id", h.Recruitment.UpdatePosition)
-		hr.GET("/applicants", h.Recruitment.GetAllApplicants)
-		hr.POST("/applicants", h.Recruitment.CreateApplicant)
-		hr.PUT("/applicants/:id/stage", h.Recruitment.UpdateApplicantStage)
-
-		hr.GET("/documents", h.Document.GetAll)
-		hr.POST("/documents", h.Document.Upload)
-		hr.DELETE("/documents/:id", h.Document.Delete)
-		hr.GET("/documents/:id/download", h.Document.Download)
-
 		hr.GET("/announcements", h.Announcement.GetAll)
 		hr.GET("/announcements/:id", h.Announcement.GetByID)
-		hr.POST("/announcements", h.Announcement.Create)
-		hr.PUT("/announcements/:id", h.Announcement.Update)
-		hr.DELETE("/announcements/:id", h.Announcement.Delete)
-
-		hr.GET("/attendance-integration", h.AttendanceIntegration.GetIntegration)
-		hr.GET("/attendance-integration/alerts", h.AttendanceIntegration.GetAlerts)
-		hr.GET("/attendance-integration/trend", h.AttendanceIntegration.GetTrend)
 
 		hr.GET("/org-chart", h.OrgChart.GetOrgChart)
-		hr.POST("/org-chart/simulate", h.OrgChart.Simulate)
-
-		hr.GET("/one-on-ones", h.OneOnOne.GetAll)
-		hr.GET("/one-on-ones/:id", h.OneOnOne.GetByID)
-		hr.POST("/one-on-ones", h.OneOnOne.Create)
-		hr.PUT("/one-on-ones/:id", h.OneOnOne.Update)
-		hr.DELETE("/one-on-ones/:id", h.OneOnOne.Delete)
-		hr.POST("/one-on-ones/:id/actions", h.OneOnOne.AddActionItem)
-		hr.PUT("/one-on-ones/:id/actions/:actionId/toggle", h.OneOnOne.ToggleActionItem)
-
-		hr.GET("/skill-map", h.Skill.GetSkillMap)
-		hr.GET("/skill-map/gap-analysis", h.Skill.GetGapAnalysis)
-		hr.POST("/skill-map/:employeeId", h.Skill.AddSkill)
-		hr.PUT("/skill-map/:employeeId/:skillId", h.Skill.UpdateSkill)
-
-		hr.GET("/salary", h.Salary.GetOverview)
-		hr.POST("/salary/simulate", h.Salary.Simulate)
-		hr.GET("/salary/:employeeId/history", h.Salary.GetHistory)
-		hr.GET("/salary/budget", h.Salary.GetBudget)
-
-		hr.GET("/onboarding", h.Onboarding.GetAll)
-		hr.GET("/onboarding/templates", h.Onboarding.GetTemplates)
-		hr.POST("/onboarding/templates", h.Onboarding.CreateTemplate)
-		hr.GET("/onboarding/:id", h.Onboarding.GetByID)
-		hr.POST("/onboarding", h.Onboarding.Create)
-		hr.PUT("/onboarding/:id", h.Onboarding.Update)
-		hr.PUT("/onboarding/:id/tasks/:taskId/toggle", h.Onboarding.ToggleTask)
-
-		hr.GET("/offboarding", h.Offboarding.GetAll)
-		hr.GET("/offboarding/analytics", h.Offboarding.GetAnalytics)
-		hr.GET("/offboarding/:id", h.Offboarding.GetByID)
-		hr.POST("/offboarding", h.Offboarding.Create)
-		hr.PUT("/offboarding/:id", h.Offboarding.Update)
-		hr.PUT("/offboarding/:id/checklist/:itemKey/toggle", h.Offboarding.ToggleChecklist)
 
 		hr.GET("/surveys", h.Survey.GetAll)
 		hr.GET("/surveys/:id", h.Survey.GetByID)
-		hr.POST("/surveys", h.Survey.Create)
-		hr.PUT("/surveys/:id", h.Survey.Update)
-		hr.DELETE("/surveys/:id", h.Survey.Delete)
-		hr.PUT("/surveys/:id/publish", h.Survey.Publish)
-		hr.PUT("/surveys/:id/close", h.Survey.Close)
-		hr.GET("/surveys/:id/results", h.Survey.GetResults)
 		hr.POST("/surveys/:id/respond", h.Survey.SubmitResponse)
 	}
+
+	hrRead := protected.Group("/hr")
+	hrRead.Use(mw.RequirePermission(middleware.PermHRRead))
+	{
+		hrRead.GET("/stats", h.HRDashboard.GetStats)
+		hrRead.GET("/activities", h.HRDashboard.GetActivities)
+
+		hrRead.GET("/employees", h.HREmployee.GetAll)
+		hrRead.GET("/employees/:id", h.HREmployee.GetByID)
+
+		hrRead.GET("/evaluations", h.Evaluation.GetAll)
+		hrRead.GET("/evaluations/:id", h.Evaluation.GetByID)
+		hrRead.GET("/evaluation-cycles", h.Evaluation.GetCycles)
+
+		hrRead.GET("/positions", h.Recruitment.GetAllPositions)
+		hrRead.GET("/positions/:id", h.Recruitment.GetPosition)
+		hrRead.GET("/applicants", h.Recruitment.GetAllApplicants)
+
+		hrRead.GET("/documents", h.Document.GetAll)
+		hrRead.GET("/documents/:id/download", h.Document.Download)
+
+		hrRead.GET("/attendance-integration", h.AttendanceIntegration.GetIntegration)
+		hrRead.GET("/attendance-integration/alerts", h.AttendanceIntegration.GetAlerts)
+		hrRead.GET("/attendance-integration/trend", h.AttendanceIntegration.GetTrend)
+
+		hrRead.GET("/skill-map", h.Skill.GetSkillMap)
+		hrRead.GET("/skill-map/gap-analysis", h.Skill.GetGapAnalysis)
+
+		hrRead.GET("/onboarding", h.Onboarding.GetAll)
+		hrRead.GET("/onboarding/templates", h.Onboarding.GetTemplates)
+		hrRead.GET("/onboarding/:id", h.Onboarding.GetByID)
+
+		hrRead.GET("/offboarding", h.Offboarding.GetAll)
+		hrRead.GET("/offboarding/analytics", h.Offboarding.GetAnalytics)
+		hrRead.GET("/offboarding/:id", h.Offboarding.GetByID)
+
+		hrRead.GET("/surveys/:id/results", h.Survey.GetResults)
+	}
+
+	// 評価の入力は直属の部下の評価者（マネージャー）と人事管理者
+	evaluations := protected.Group("/hr")
+	evaluations.Use(mw.RequirePermission(middleware.PermHREvaluate))
+	{
+		evaluations.POST("/evaluations", h.Evaluation.Create)
+		evaluations.PUT("/evaluations/:id", h.Evaluation.Update)
+		evaluations.PUT("/evaluations/:id/submit", h.Evaluation.Submit)
+	}
+
+	oneOnOnes := protected.Group("/hr/one-on-ones")
+	oneOnOnes.Use(mw.RequirePermission(middleware.PermHROneOnOne))
+	{
+		oneOnOnes.GET("", h.OneOnOne.GetAll)
+		oneOnOnes.GET("/:id", h.OneOnOne.GetByID)
+		oneOnOnes.POST("", h.OneOnOne.Create)
+		oneOnOnes.PUT("/:id", h.OneOnOne.Update)
+		oneOnOnes.DELETE("/:id", h.OneOnOne.Delete)
+		oneOnOnes.POST("/:id/actions", h.OneOnOne.AddActionItem)
+		oneOnOnes.PUT("/:id/actions/:actionId/toggle", h.OneOnOne.ToggleActionItem)
+	}
+
+	// 給与情報は給与権限を持つ操作主体のみ（基本ロールでは管理者のみ）
+	salary := protected.Group("/hr/salary")
+	salary.Use(mw.RequirePermission(middleware.PermSalaryRead))
+	{
+		salary.GET("", h.Salary.GetOverview)
+		salary.GET("/:employeeId/history", h.Salary.GetHistory)
+		salary.GET("/budget", h.Salary.GetBudget)
+	}
+	salaryAdmin := protected.Group("/hr/salary")
+	salaryAdmin.Use(mw.RequirePermission(middleware.PermSalaryManage))
+	{
+		salaryAdmin.POST("/simulate", h.Salary.Simulate)
+	}
+
+	hrAdmin := protected.Group("/hr")
+	hrAdmin.Use(mw.RequirePermission(middleware.PermHRManage))
+	{
+		hrAdmin.POST("/employees", h.HREmployee.Create)
+		hrAdmin.PUT("/employees/:id", h.HREmployee.Update)
+		hrAdmin.DELETE("/employees/:id", h.HREmployee.Delete)
+
+		hrAdmin.POST("/departments", h.HRDepartment.Create)
+		hrAdmin.PUT("/departments/:id", h.HRDepartment.Update)
+		hrAdmin.DELETE("/departments/:id", h.HRDepartment.Delete)
+
+		hrAdmin.POST("/evaluation-cycles", h.Evaluation.CreateCycle)
+
+		hrAdmin.POST("/training", h.Training.Create)
+		hrAdmin.PUT("/training/:id", h.Training.Update)
+		hrAdmin.DELETE("/training/:id", h.Training.Delete)
+
+		hrAdmin.POST("/positions", h.Recruitment.CreatePosition)
+		hrAdmin.PUT("/positions/:id", h.Recruitment.UpdatePosition)
+		hrAdmin.POST("/applicants", h.Recruitment.CreateApplicant)
+		hrAdmin.PUT("/applicants/:id/stage", h.Recruitment.UpdateApplicantStage)
+
+		hrAdmin.POST("/documents", h.Document.Upload)
+		hrAdmin.DELETE("/documents/:id", h.Document.Delete)
+
+		hrAdmin.POST("/announcements", h.Announcement.Create)
+		hrAdmin.PUT("/announcements/:id", h.Announcement.Update)
+		hrAdmin.DELETE("/announcements/:id", h.Announcement.Delete)
+
+		hrAdmin.POST("/org-chart/simulate", h.OrgChart.Simulate)
+
+		hrAdmin.POST("/skill-map/:employeeId", h.Skill.AddSkill)
+		hrAdmin.PUT("/skill-map/:employeeId/:skillId", h.Skill.UpdateSkill)
+
+		hrAdmin.POST("/onboarding/templates", h.Onboarding.CreateTemplate)
+		hrAdmin.POST("/onboarding", h.Onboarding.Create)
+		hrAdmin.PUT("/onboarding/:id", h.Onboarding.Update)
+		hrAdmin.PUT("/onboarding/:id/tasks/:taskId/toggle", h.Onboarding.ToggleTask)
+
+		hrAdmin.POST("/offboarding", h.Offboarding.Create)
+		hrAdmin.PUT("/offboarding/:id", h.Offboarding.Update)
+		hrAdmin.PUT("/offboarding/:id/checklist/:itemKey/toggle", h.Offboarding.ToggleChecklist)
+
+		hrAdmin.POST("/surveys", h.Survey.Create)
+		hrAdmin.PUT("/surveys/:id", h.Survey.Update)
+		hrAdmin.DELETE("/surveys/:id", h.Survey.Delete)
+		hrAdmin.PUT("/surveys/:id/publish", h.Survey.Publish)
+		hrAdmin.PUT("/surveys/:id/close", h.Survey.Close)
+	}
 }
@@ -4,7 +4,6 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/your-org/kintai/backend/internal/handler"
 	"github.com/your-org/kintai/backend/internal/middleware"
-	"github.com/your-org/kintai/backend/internal/model"
 )
 
 func RegisterPublicRoutes(v1 *gin.RouterGroup, h *handler.Handlers) {
@@ -13,9 +12,27 @@ func RegisterPublicRoutes(v1 *gin.RouterGroup, h *handler.Handlers) {
 		auth.POST("/login", h.Auth.Login)
 		auth.POST("/register", h.Auth.Register)
 		auth.POST("/refresh", h.Auth.RefreshToken)
+		auth.GET("/csrf", h.Auth.CSRFToken)
+		auth.POST("/forgot-password", h.Auth.ForgotPassword)
+		auth.POST("/reset-password", h.Auth.ResetPassword)
+		auth.POST("/verify-email", h.Auth.VerifyEmail)
+		auth.POST("/resend-verification", h.Auth.ResendVerification)
+		auth.POST("/mfa/verify", h.Auth.VerifyMFA)
+		auth.POST("/mfa/setup", h.Auth.StartMFASetup)
+		auth.POST("/mfa/setup/confirm", h.Auth.CompleteMFASetup)
+		auth.GET("/oidc/login", h.SSO.Login)
+		auth.GET("/oidc/callback", h.SSO.Callback)
+		auth.POST("/oidc/exchange", h.SSO.Exchange)
 	}
 }
 
+// RegisterIntegrationRoutes は外部サービスから呼び出されるエンドポイントを登録する。
+// 送信元の署名とボタンのトークンで検証するため、ログインと CSRF トークンは要求しない
+func RegisterIntegrationRoutes(integrations *gin.RouterGroup, h *handler.Handlers) {
+	integrations.POST("/slack/actions", h.ChatAction.SlackAction)
+	integrations.POST("/teams/actions", h.ChatAction.TeamsAction)
+}
+
 func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw *middleware.Middleware) {
 	protected.POST("/auth/logout", h.Auth.Logout)
 
@@ -23,11 +40,16 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw
 	{
 		notifications.GET("", h.Notification.GetMy)
 		notifications.GET("/unread-count", h.Notification.GetUnreadCount)
+		notifications.GET("/preferences", h.Notification.GetPreferences)
+		notifications.PUT("/preferences", h.Notification.UpdatePreferences)
 		notifications.PUT("/:id/read", h.Notification.MarkAsRead)
 		notifications.PUT("/read-all", h.Notification.MarkAllAsRead)
 		notifications.DELETE("/:id", h.Notification.Delete)
 	}
 
+	// 通知・承認状況・お知らせのリアルタイム配信（Server-Sent Events）
+	protected.GET("/events", h.Events.Stream)
+
 	timeEntries := protected.Group("/time-entries")
 	{
 		timeEntries.POST("", h.TimeEntry.Create)
@@ -39,6 +61,23 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw
 	users := protected.Group("/users")
 	{
 		users.GET("/me", h.User.GetMe)
+		users.GET("/me/login-history", h.LoginSecurity.GetMyHistory)
+		users.GET("/me/sessions", h.Session.GetMy)
+		users.DELETE("/me/sessions/:id", h.Session.Revoke)
+		users.POST("/me/sessions/revoke-others", h.Session.RevokeOthers)
+		users.GET("/me/mfa", h.MFA.Status)
+		users.POST("/me/mfa/setup", h.MFA.Setup)
+		users.POST("/me/mfa/enable", h.MFA.Enable)
+		users.POST("/me/mfa/recovery-codes", h.MFA.RegenerateRecoveryCodes)
+		users.POST("/me/mfa/disable", h.MFA.Disable)
+		users.GET("/me/api-tokens", h.APIToken.ListMy)
+		users.POST("/me/api-tokens", h.APIToken.CreateMy)
+		users.DELETE("/me/api-tokens/:id", h.APIToken.RevokeMy)
+	}
+
+	apiTokens := protected.Group("/api-tokens")
+	{
+		apiTokens.GET("/scopes", h.APIToken.Scopes)
 	}
 
 	departments := protected.Group("/departments")
@@ -65,39 +104,112 @@ func RegisterProtectedRoutes(protected *gin.RouterGroup, h *handler.Handlers, mw
 		holidays.GET("/working-days", h.Holiday.GetWorkingDays)
 	}
 
-	admin := protected.Group("")
-	admin.Use(mw.RequireRole(model.RoleAdmin, model.RoleManager))
+	userAdmin := protected.Group("/users")
+	userAdmin.Use(mw.RequirePermission(middleware.PermUserManage))
 	{
-		admin.GET("/users", h.User.GetAll)
-		admin.POST("/users", h.User.Create)
-		admin.PUT("/users/:id", h.User.Update)
-		admin.DELETE("/users/:id", h.User.Delete)
+		userAdmin.GET("", h.User.GetAll)
+		userAdmin.POST("", h.User.Create)
+		userAdmin.PUT("/:id", h.User.Update)
+		userAdmin.DELETE("/:id", h.User.Delete)
+	}
 
-		admin.POST("/shifts", h.Shift.Create)
-		admin.POST("/shifts/bulk", h.Shift.BulkCreate)
-		admin.DELETE("/shifts/:id", h.Shift.Delete)
+	roleAdmin := protected.Group("")
+	roleAdmin.Use(mw.RequirePermission(middleware.PermRoleManage))
+	{
+		roleAdmin.GET("/permissions", h.Role.ListPermissions)
+		roleAdmin.GET("/roles", h.Role.List)
+		roleAdmin.POST("/roles", h.Role.Create)
+		roleAdmin.GET("/roles/:id", h.Role.GetByID)
+		roleAdmin.PUT("/roles/:id", h.Role.Update)
+		roleAdmin.DELETE("/roles/:id", h.Role.Delete)
+
+		roleAdmin.GET("/users/:id/roles", h.Role.ListAssignments)
+		roleAdmin.POST("/users/:id/roles", h.Role.Assign)
+		roleAdmin.DELETE("/users/:id/roles/:assignmentId", h.Role.Unassign)
+	}
 
-		admin.POST("/projects", h.Project.Create)
-		admin.PUT("/projects/:id", h.Project.Update)
-		admin.DELETE("/projects/:id", h.Project.Delete)
+	securityAdmin := protected.Group("")
+	securityAdmin.Use(mw.RequirePermission(middleware.PermSecurityManage))
+	{
+		securityAdmin.GET("/mfa-policy", h.MFA.GetPolicy)
+		securityAdmin.PUT("/mfa-policy", h.MFA.UpdatePolicy)
+		securityAdmin.DELETE("/users/:id/mfa", h.MFA.Reset)
+		securityAdmin.GET("/users/:id/login-history", h.LoginSecurity.GetHistory)
+		securityAdmin.POST("/users/:id/unlock", h.LoginSecurity.Unlock)
+		securityAdmin.GET("/users/:id/sessions", h.Session.GetByUser)
+		securityAdmin.POST("/users/:id/force-logout", h.Session.ForceLogout)
+
+		securityAdmin.GET("/service-accounts", h.APIToken.ListServiceAccounts)
+		securityAdmin.POST("/service-accounts", h.APIToken.CreateServiceAccount)
+		securityAdmin.PUT("/service-accounts/:id", h.APIToken.UpdateServiceAccount)
+		securityAdmin.DELETE("/service-accounts/:id", h.APIToken.DeleteServiceAccount)
+		securityAdmin.GET("/service-accounts/:id/tokens", h.APIToken.ListServiceAccountTokens)
+		securityAdmin.POST("/service-accounts/:id/tokens", h.APIToken.CreateServiceAccountToken)
+		securityAdmin.DELETE("/service-accounts/:id/tokens/:tokenId", h.APIToken.RevokeServiceAccountToken)
+	}
 
-		admin.GET("/time-entries/summary", h.TimeEntry.GetSummary)
+	auditLogs := protected.Group("/audit-logs")
+	auditLogs.Use(mw.RequirePermission(middleware.PermAuditRead))
+	{
+		auditLogs.GET("", h.Audit.List)
+		auditLogs.GET("/export", h.Audit.Export)
+		auditLogs.GET("/verify", h.Audit.Verify)
+	}
 
-		admin.POST("/holidays", h.Holiday.Create)
-		admin.PUT("/holidays/:id", h.Holiday.Update)
-		admin.DELETE("/holidays/:id", h.Holiday.Delete)
+	shiftAdmin := protected.Group("")
+	shiftAdmin.Use(mw.RequirePermission(middleware.PermShiftManage))
+	{
+		shiftAdmin.POST("/shifts", h.Shift.Create)
+		shiftAdmin.POST("/shifts/bulk", h.Shift.BulkCreate)
+		shiftAdmin.PUT("/shifts/:id", h.Shift.Update)
+		shiftAdmin.DELETE("/shifts/:id", h.Shift.Delete)
+
+		shiftAdmin.GET("/shift-schedules", h.ShiftSchedule.GetAll)
+		shiftAdmin.POST("/shift-schedules", h.ShiftSchedule.Create)
+		shiftAdmin.GET("/shift-schedules/:id", h.ShiftSchedule.GetByID)
+		shiftAdmin.GET("/shift-schedules/:id/shifts", h.ShiftSchedule.GetShifts)
+		shiftAdmin.POST("/shift-schedules/:id/publish", h.ShiftSchedule.Publish)
+		shiftAdmin.GET("/shift-schedules/:id/versions", h.ShiftSchedule.GetVersions)
+		shiftAdmin.GET("/shift-schedules/:id/diff", h.ShiftSchedule.GetDiff)
+	}
+
+	projectAdmin := protected.Group("/projects")
+	projectAdmin.Use(mw.RequirePermission(middleware.PermProjectManage))
+	{
+		projectAdmin.POST("", h.Project.Create)
+		projectAdmin.PUT("/:id", h.Project.Update)
+		projectAdmin.DELETE("/:id", h.Project.Delete)
+	}
+
+	holidayAdmin := protected.Group("/holidays")
+	holidayAdmin.Use(mw.RequirePermission(middleware.PermHolidayManage))
+	{
+		holidayAdmin.POST("", h.Holiday.Create)
+		holidayAdmin.PUT("/:id", h.Holiday.Update)
+		holidayAdmin.DELETE("/:id", h.Holiday.Delete)
+	}
+
+	approvalFlows := protected.Group("/approval-flows")
+	approvalFlows.Use(mw.RequirePermission(middleware.PermApprovalFlowManage))
+	{
+		approvalFlows.GET("", h.ApprovalFlow.GetAll)
+		approvalFlows.GET("/:id", h.ApprovalFlow.GetByID)
+		approvalFlows.POST("", h.ApprovalFlow.Create)
+		approvalFlows.PUT("/:id", h.ApprovalFlow.Update)
+		approvalFlows.DELETE("/:id", h.ApprovalFlow.Delete)
+	}
 
-		admin.GET("/approval-flows", h.ApprovalFlow.GetAll)
-		admin.GET("/approval-flows/:id", h.ApprovalFlow.GetByID)
-		admin.POST("/approval-flows", h.ApprovalFlow.Create)
-		admin.PUT("/approval-flows/:id", h.ApprovalFlow.Update)
-		admin.DELETE("/approval-flows/:id", h.ApprovalFlow.Delete)
+	reports := protected.Group("")
+	reports.Use(mw.RequirePermission(middleware.PermReportRead))
+	{
+		reports.GET("/time-entries/summary", h.TimeEntry.GetSummary)
 
-		admin.GET("/export/attendance", h.Export.ExportAttendance)
-		admin.GET("/export/leaves", h.Export.ExportLeaves)
-		admin.GET("/export/overtime", h.Export.ExportOvertime)
-		admin.GET("/export/projects", h.Export.ExportProjects)
+		reports.GET("/export/attendance", h.Export.ExportAttendance)
+		reports.GET("/export/leaves", h.Export.ExportLeaves)
+		reports.GET("/export/overtime", h.Export.ExportOvertime)
+		reports.GET("/export/projects", h.Export.ExportProjects)
+		reports.GET("/export/work-modes", h.Export.ExportWorkModes)
 
-		admin.GET("/dashboard/stats", h.Dashboard.GetStats)
+		reports.GET("/dashboard/stats", h.Dashboard.GetStats)
 	}
 }
@@ -0,0 +1,447 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/model"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// GenesisHash は最初の監査ログの PrevHash
+var GenesisHash = strings.Repeat("0", 64)
+
+const (
+	// maxRowsPerStatement を超える行を変更した一括の作成・更新・削除は、行ごとではなく件数のみを1件で記録する
+	maxRowsPerStatement = 1000
+	// chainLockKey はハッシュチェーンの末尾に追記するトランザクションを直列化するアドバイザリロックのキー
+	chainLockKey = 7_420_001
+	redacted     = "[REDACTED]"
+	beforeKey    = "audit:before"
+)
+
+// skippedTables は記録しないテーブル（監査ログ自体と、それ自体が記録・一時的な資格情報であるもの）
+var skippedTables = map[string]bool{
+	"audit_logs":      true,
+	"refresh_tokens":  true,
+	"user_tokens":     true,
+	"login_histories": true,
+	"notifications":   true,
+}
+
+// ignoredColumns は差分に含めない列（更新日時と、利用のたびに更新される記録）
+var ignoredColumns = map[string]bool{
+	"updated_at":     true,
+	"deleted_at":     true,
+	"last_used_at":   true,
+	"last_used_ip":   true,
+	"last_used_step": true,
+}
+
+// Recorder は GORM のプラグインとして、データの作成・更新・削除を同じトランザクション内で監査ログに記録する。
+// 操作主体・IPアドレス・リクエストIDはコンテキスト（middleware.Actor と middleware.ClientInfo）から取得する。
+// ハッシュチェーンを分岐させないため、監査ログの追記はトランザクション単位で直列化する（PostgreSQL のアドバイザリロック）。
+// 生のSQL（Exec）による変更は記録しない
+type Recorder struct {
+	now func() time.Time
+}
+
+// NewRecorder は Recorder を作成する。db.Use で登録する
+func NewRecorder() *Recorder {
+	return &Recorder{now: time.Now}
+}
+
+// Name は gorm.Plugin の名前
+func (r *Recorder) Name() string {
+	return "kintai:audit"
+}
+
+// Initialize は作成・更新・削除のコールバックを登録する
+func (r *Recorder) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("audit:after_create", r.afterCreate); err != nil {
+		return err
+	}
+	if err := cb.Update().Before("gorm:update").Register("audit:before_update", r.captureBefore); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("audit:after_update", r.afterUpdate); err != nil {
+		return err
+	}
+	if err := cb.Delete().Before("gorm:delete").Register("audit:before_delete", r.captureBefore); err != nil {
+		return err
+	}
+	return cb.Delete().After("gorm:delete").Register("audit:after_delete", r.afterDelete)
+}
+
+// Hash は記録の内容と前の記録のハッシュから SHA-256 を計算する
+func Hash(l *model.AuditLog) string {
+	actorID := ""
+	if l.ActorID != nil {
+		actorID = l.ActorID.String()
+	}
+	fields := []string{
+		strconv.FormatInt(l.Seq, 10),
+		l.OccurredAt.UTC().Format(time.RFC3339Nano),
+		actorID,
+		string(l.ActorType),
+		string(l.Action),
+		l.EntityType,
+		l.EntityID,
+		string(l.Changes),
+		l.IPAddress,
+		l.RequestID,
+		l.PrevHash,
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Change は1列の変更前後の値
+type Change struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// Diff は変更前後の行（列名→値）の差分を返す。作成時は before、削除時は after が nil。
+// API で返さない列（json:"-"）は値を伏せ、変更があったことのみを残す
+func Diff(sch *schema.Schema, before, after map[string]any) map[string]Change {
+	changes := make(map[string]Change)
+	columns := make(map[string]bool)
+	for k := range before {
+		columns[k] = true
+	}
+	for k := range after {
+		columns[k] = true
+	}
+	for col := range columns {
+		if ignoredColumns[col] {
+			continue
+		}
+		oldVal, newVal := normalize(before[col]), normalize(after[col])
+		if equalJSON(oldVal, newVal) {
+			continue
+		}
+		if isHidden(sch, col) {
+			oldVal, newVal = redact(oldVal), redact(newVal)
+		}
+		changes[col] = Change{Old: oldVal, New: newVal}
+	}
+	return changes
+}
+
+// ===== コールバック =====
+
+func (r *Recorder) afterCreate(db *gorm.DB) {
+	if skip(db) || db.Statement.RowsAffected == 0 {
+		return
+	}
+	ids := primaryKeys(db.Statement)
+	if len(ids) == 0 {
+		return
+	}
+	if len(ids) > maxRowsPerStatement {
+		r.recordBulk(db, model.AuditActionCreate)
+		return
+	}
+	after, err := loadRows(db, []clause.Expression{pkIn(db.Statement, ids)}, true)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+		return
+	}
+	r.record(db, model.AuditActionCreate, nil, after)
+}
+
+func (r *Recorder) captureBefore(db *gorm.DB) {
+	if skip(db) {
+		return
+	}
+	conds := targetConditions(db.Statement)
+	if len(conds) == 0 {
+		return
+	}
+	before, err := loadRows(db, conds, db.Statement.Unscoped)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+		return
+	}
+	db.InstanceSet(beforeKey, before)
+}
+
+func (r *Recorder) afterUpdate(db *gorm.DB) {
+	before, ok := takeBefore(db)
+	if !ok {
+		return
+	}
+	if len(before) > maxRowsPerStatement {
+		r.recordBulk(db, model.AuditActionUpdate)
+		return
+	}
+	ids := columnValues(before, db.Statement.Schema.PrioritizedPrimaryField.DBName)
+	after, err := loadRows(db, []clause.Expression{pkIn(db.Statement, ids)}, true)
+	if err != nil {
+		_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+		return
+	}
+	r.record(db, model.AuditActionUpdate, before, after)
+}
+
+func (r *Recorder) afterDelete(db *gorm.DB) {
+	before, ok := takeBefore(db)
+	if !ok {
+		return
+	}
+	if len(before) > maxRowsPerStatement {
+		r.recordBulk(db, model.AuditActionDelete)
+		return
+	}
+	r.record(db, model.AuditActionDelete, before, nil)
+}
+
+// record は主キーで対応付けた変更前後の行の差分を記録する
+func (r *Recorder) record(db *gorm.DB, action model.AuditAction, before, after []map[string]any) {
+	pk := db.Statement.Schema.PrioritizedPrimaryField.DBName
+	afterByID := make(map[string]map[string]any, len(after))
+	for _, row := range after {
+		afterByID[entityID(row[pk])] = row
+	}
+
+	var logs []model.AuditLog
+	appendLog := func(id string, old, cur map[string]any) {
+		changes := Diff(db.Statement.Schema, old, cur)
+		if len(changes) == 0 {
+			return
+		}
+		data, _ := json.Marshal(changes)
+		logs = append(logs, model.AuditLog{
+			Action:     action,
+			EntityType: db.Statement.Table,
+			EntityID:   id,
+			Changes:    model.AuditChanges(data),
+		})
+	}
+	if action == model.AuditActionCreate {
+		for _, row := range after {
+			appendLog(entityID(row[pk]), nil, row)
+		}
+	} else {
+		for _, row := range before {
+			id := entityID(row[pk])
+			var cur map[string]any
+			if action == model.AuditActionUpdate {
+				cur = afterByID[id]
+			}
+			appendLog(id, row, cur)
+		}
+	}
+	r.write(db, logs)
+}
+
+// recordBulk は一括の作成・更新・削除を件数のみで記録する
+func (r *Recorder) recordBulk(db *gorm.DB, action model.AuditAction) {
+	data, _ := json.Marshal(map[string]Change{"rows_affected": {New: db.Statement.RowsAffected}})
+	r.write(db, []model.AuditLog{{
+		Action:     action,
+		EntityType: db.Statement.Table,
+		Changes:    model.AuditChanges(data),
+	}})
+}
+
+// write は操作主体を設定し、ハッシュチェーンの末尾に追記する
+func (r *Recorder) write(db *gorm.DB, logs []model.AuditLog) {
+	if len(logs) == 0 {
+		return
+	}
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true})
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", chainLockKey).Error; err != nil {
+			_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+			return
+		}
+	}
+	var last model.AuditLog
+	if err := tx.Select("seq", "hash").Order("seq DESC").Limit(1).Find(&last).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+		return
+	}
+	prevHash := last.Hash
+	if prevHash == "" {
+		prevHash = GenesisHash
+	}
+
+	actorID, actorType := actorOf(db.Statement.Context)
+	info := middleware.ClientInfoFromContext(db.Statement.Context)
+	// PostgreSQL の timestamptz の精度に合わせ、読み戻した値でも同じハッシュになるようにする
+	now := r.now().UTC().Truncate(time.Microsecond)
+	for i := range logs {
+		l := &logs[i]
+		l.Seq = last.Seq + int64(i) + 1
+		l.OccurredAt = now
+		l.ActorID = actorID
+		l.ActorType = actorType
+		l.IPAddress = info.IPAddress
+		l.RequestID = info.RequestID
+		l.PrevHash = prevHash
+		l.Hash = Hash(l)
+		prevHash = l.Hash
+	}
+	if err := tx.Create(&logs).Error; err != nil {
+		_ = db.AddError(fmt.Errorf("監査ログの記録に失敗しました: %w", err))
+	}
+}
+
+// ===== ヘルパー =====
+
+func skip(db *gorm.DB) bool {
+	stmt := db.Statement
+	return db.Error != nil || db.DryRun || stmt.Schema == nil || stmt.Schema.PrioritizedPrimaryField == nil ||
+		skippedTables[stmt.Table]
+}
+
+func takeBefore(db *gorm.DB) ([]map[string]any, bool) {
+	if db.Error != nil || db.Statement.RowsAffected == 0 {
+		return nil, false
+	}
+	v, ok := db.InstanceGet(beforeKey)
+	if !ok {
+		return nil, false
+	}
+	rows, _ := v.([]map[string]any)
+	return rows, len(rows) > 0
+}
+
+func actorOf(ctx context.Context) (*uuid.UUID, model.AuditActorType) {
+	if actor, ok := middleware.ActorFromContext(ctx); ok {
+		id := actor.UserID
+		if actor.ServiceAccount {
+			return &id, model.AuditActorServiceAccount
+		}
+		return &id, model.AuditActorUser
+	}
+	if middleware.ClientInfoFromContext(ctx).RequestID != "" {
+		return nil, model.AuditActorAnonymous
+	}
+	return nil, model.AuditActorSystem
+}
+
+// targetConditions は更新・削除の対象を選ぶ条件（指定された WHERE とモデルの主キー）を返す
+func targetConditions(stmt *gorm.Statement) []clause.Expression {
+	var conds []clause.Expression
+	if c, ok := stmt.Clauses["WHERE"]; ok {
+		if where, ok := c.Expression.(clause.Where); ok {
+			conds = append(conds, where.Exprs...)
+		}
+	}
+	if ids := primaryKeys(stmt); len(ids) > 0 {
+		conds = append(conds, pkIn(stmt, ids))
+	}
+	return conds
+}
+
+// primaryKeys はモデルの値（構造体またはスライス）から設定済みの主キーを返す
+func primaryKeys(stmt *gorm.Statement) []any {
+	pk := stmt.Schema.PrioritizedPrimaryField
+	var ids []any
+	add := func(v reflect.Value) {
+		v = reflect.Indirect(v)
+		if v.Kind() != reflect.Struct || v.Type() != stmt.Schema.ModelType {
+			return
+		}
+		if id, zero := pk.ValueOf(stmt.Context, v); !zero {
+			ids = append(ids, id)
+		}
+	}
+	switch stmt.ReflectValue.Kind() {
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < stmt.ReflectValue.Len(); i++ {
+			add(stmt.ReflectValue.Index(i))
+		}
+	case reflect.Struct:
+		add(stmt.ReflectValue)
+	}
+	return ids
+}
+
+func pkIn(stmt *gorm.Statement, ids []any) clause.Expression {
+	return clause.IN{Column: clause.Column{Table: clause.CurrentTable, Name: stmt.Schema.PrioritizedPrimaryField.DBName}, Values: ids}
+}
+
+// loadRows は同じトランザクションで対象の行を列名→値で読み込む
+func loadRows(db *gorm.DB, conds []clause.Expression, unscoped bool) ([]map[string]any, error) {
+	stmt := db.Statement
+	tx := db.Session(&gorm.Session{NewDB: true, SkipHooks: true}).
+		Model(reflect.New(stmt.Schema.ModelType).Interface()).
+		Table(stmt.Table).
+		Clauses(clause.Where{Exprs: conds})
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	var rows []map[string]any
+	err := tx.Limit(maxRowsPerStatement + 1).Find(&rows).Error
+	return rows, err
+}
+
+func columnValues(rows []map[string]any, column string) []any {
+	values := make([]any, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row[column])
+	}
+	return values
+}
+
+func entityID(v any) string {
+	if v == nil {
+		return ""
+	}
+	return fmt.Sprint(normalize(v))
+}
+
+// normalize はドライバーから読み込んだ値を比較・JSON化しやすい形にそろえる
+func normalize(v any) any {
+	switch x := v.(type) {
+	case []byte:
+		return string(x)
+	case [16]byte:
+		return uuid.UUID(x).String()
+	case time.Time:
+		return x.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if x == nil {
+			return nil
+		}
+		return x.UTC().Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+func equalJSON(a, b any) bool {
+	ja, errA := json.Marshal(a)
+	jb, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(ja) == string(jb)
+}
+
+func isHidden(sch *schema.Schema, column string) bool {
+	if sch == nil {
+		return false
+	}
+	f, ok := sch.FieldsByDBName[column]
+	return ok && strings.Split(f.Tag.Get("json"), ",")[0] == "-"
+}
+
+func redact(v any) any {
+	if v == nil {
+		return nil
+	}
+	return redacted
+}
@@ -0,0 +1,205 @@
+package audit
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+func userSchema(t *testing.T) *schema.Schema {
+	t.Helper()
+	sch, err := schema.Parse(&model.User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatalf("parse schema: %v", err)
+	}
+	return sch
+}
+
+func TestDiff(t *testing.T) {
+	sch := userSchema(t)
+	before := map[string]any{
+		"id": "u1", "first_name": "太郎", "role": "employee", "password_hash": "old-hash",
+		"updated_at": time.Now(), "is_active": true,
+	}
+	after := map[string]any{
+		"id": "u1", "first_name": "太郎", "role": "manager", "password_hash": "new-hash",
+		"updated_at": time.Now().Add(time.Minute), "is_active": true,
+	}
+
+	changes := Diff(sch, before, after)
+
+	if len(changes) != 2 {
+		t.Fatalf("Expected role and password_hash changes, got %v", changes)
+	}
+	if c := changes["role"]; c.Old != "employee" || c.New != "manager" {
+		t.Errorf("Expected role employee -> manager, got %+v", c)
+	}
+	if c := changes["password_hash"]; c.Old != redacted || c.New != redacted {
+		t.Errorf("Expected hidden column to be redacted, got %+v", c)
+	}
+}
+
+func TestDiff_CreateAndDelete(t *testing.T) {
+	sch := userSchema(t)
+	id := uuid.New()
+	row := map[string]any{"id": [16]byte(id), "email": "a@example.com", "department_id": nil}
+
+	created := Diff(sch, nil, row)
+	if c := created["id"]; c.Old != nil || c.New != id.String() {
+		t.Errorf("Expected uuid bytes to be normalized, got %+v", c)
+	}
+	if _, ok := created["department_id"]; ok {
+		t.Error("Expected nil values to be omitted on create")
+	}
+
+	deleted := Diff(sch, row, nil)
+	if c := deleted["email"]; c.Old != "a@example.com" || c.New != nil {
+		t.Errorf("Expected deleted values as old, got %+v", c)
+	}
+}
+
+func TestHash_ChainsPreviousHash(t *testing.T) {
+	actorID := uuid.New()
+	l := &model.AuditLog{
+		Seq:        1,
+		OccurredAt: time.Date(2026, 4, 1, 9, 0, 0, 123456000, time.UTC),
+		ActorID:    &actorID,
+		ActorType:  model.AuditActorUser,
+		Action:     model.AuditActionUpdate,
+		EntityType: "users",
+		EntityID:   "u1",
+		Changes:    `{"role":{"old":"employee","new":"manager"}}`,
+		PrevHash:   GenesisHash,
+	}
+	h := Hash(l)
+	if len(h) != 64 {
+		t.Fatalf("Expected hex SHA-256, got %q", h)
+	}
+
+	// タイムゾーンが変わっても同じ時刻なら同じハッシュ
+	copied := *l
+	copied.OccurredAt = l.OccurredAt.In(time.FixedZone("JST", 9*60*60))
+	if Hash(&copied) != h {
+		t.Error("Expected hash to be independent of the time zone")
+	}
+
+	for name, modify := range map[string]func(*model.AuditLog){
+		"changes":   func(x *model.AuditLog) { x.Changes = `{"role":{"old":"employee","new":"admin"}}` },
+		"prev_hash": func(x *model.AuditLog) { x.PrevHash = h },
+		"seq":       func(x *model.AuditLog) { x.Seq = 2 },
+		"actor":     func(x *model.AuditLog) { x.ActorID = nil },
+	} {
+		tampered := *l
+		modify(&tampered)
+		if Hash(&tampered) == h {
+			t.Errorf("Expected hash to change when %s is modified", name)
+		}
+	}
+}
+
+func TestActorOf(t *testing.T) {
+	userID := uuid.New()
+	ctx := middleware.WithActor(context.Background(), middleware.Actor{UserID: userID, Role: model.RoleAdmin})
+	if id, typ := actorOf(ctx); id == nil || *id != userID || typ != model.AuditActorUser {
+		t.Errorf("Expected user actor, got %v %s", id, typ)
+	}
+
+	ctx = middleware.WithActor(context.Background(), middleware.Actor{UserID: userID, ServiceAccount: true})
+	if _, typ := actorOf(ctx); typ != model.AuditActorServiceAccount {
+		t.Errorf("Expected service account actor, got %s", typ)
+	}
+
+	ctx = middleware.WithClientInfo(context.Background(), middleware.ClientInfo{RequestID: "req-1"})
+	if id, typ := actorOf(ctx); id != nil || typ != model.AuditActorAnonymous {
+		t.Errorf("Expected anonymous actor for an unauthenticated request, got %s", typ)
+	}
+
+	if _, typ := actorOf(context.Background()); typ != model.AuditActorSystem {
+		t.Errorf("Expected system actor outside requests, got %s", typ)
+	}
+}
+
+func TestRecorder_Update(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherRegexp))
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB, PreferSimpleProtocol: true}), &gorm.Config{
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := NewRecorder()
+	now := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time { return now }
+	if err := db.Use(recorder); err != nil {
+		t.Fatal(err)
+	}
+
+	userID, actorID := uuid.New(), uuid.New()
+	columns := []string{"id", "first_name", "role"}
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1 AND "users"."deleted_at" IS NULL`).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(userID.String(), "太郎", "employee"))
+	mock.ExpectExec(`UPDATE "users" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(`SELECT \* FROM "users" WHERE "users"."id" = \$1 LIMIT`).
+		WillReturnRows(sqlmock.NewRows(columns).AddRow(userID.String(), "太郎", "manager"))
+	mock.ExpectExec(regexp.QuoteMeta("SELECT pg_advisory_xact_lock(")).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT "seq","hash" FROM "audit_logs"`).
+		WillReturnRows(sqlmock.NewRows([]string{"seq", "hash"}).AddRow(41, "prev"))
+	args := make([]driver.Value, 12)
+	captured := make([]any, 12)
+	for i := range args {
+		args[i] = captureArg{&captured[i]}
+	}
+	mock.ExpectQuery(`INSERT INTO "audit_logs"`).WithArgs(args...).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New().String()))
+
+	ctx := middleware.WithActor(context.Background(), middleware.Actor{UserID: actorID, Role: model.RoleAdmin})
+	ctx = middleware.WithClientInfo(ctx, middleware.ClientInfo{IPAddress: "192.0.2.1", RequestID: "req-1"})
+	user := model.User{BaseModel: model.BaseModel{ID: userID}}
+	if err := db.WithContext(ctx).Model(&user).Update("role", model.RoleManager).Error; err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Fatal(err)
+	}
+
+	// seq, occurred_at, actor_id, actor_type, action, entity_type, entity_id, changes, ip_address, request_id, prev_hash, hash
+	want := model.AuditLog{
+		Seq: 42, OccurredAt: now, ActorID: &actorID, ActorType: model.AuditActorUser, Action: model.AuditActionUpdate,
+		EntityType: "users", EntityID: userID.String(), IPAddress: "192.0.2.1", RequestID: "req-1", PrevHash: "prev",
+	}
+	var changes map[string]Change
+	if err := json.Unmarshal([]byte(captured[7].(string)), &changes); err != nil || len(changes) != 1 || changes["role"].New != "manager" {
+		t.Errorf("Expected only the role change, got %v (%v)", captured[7], err)
+	}
+	want.Changes = model.AuditChanges(captured[7].(string))
+	if captured[0] != int64(42) || captured[3] != string(model.AuditActorUser) || captured[10] != "prev" {
+		t.Errorf("Expected seq 42 chained to the previous hash, got %v", captured)
+	}
+	if captured[11] != Hash(&want) {
+		t.Errorf("Expected hash %s, got %v", Hash(&want), captured[11])
+	}
+}
+
+// captureArg は任意の値に一致し、その値を保存する
+type captureArg struct{ v *any }
+
+func (a captureArg) Match(v driver.Value) bool {
+	*a.v = v
+	return true
+}
@@ -3,7 +3,10 @@ package config
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Config はアプリケーション設定を保持する構造体
@@ -21,14 +24,94 @@ type Config struct {
 	// CORS
 	AllowedOrigins []string
 
+	// Cookie認証モード。有効な場合、ログイン時のトークンを HttpOnly Cookie で返し、
+	// 状態を変更するリクエストにCSRFトークンを要求する。AuthCookieSameSite は lax / strict / none
+	AuthCookieMode     bool
+	AuthCookieDomain   string
+	AuthCookieSecure   bool
+	AuthCookieSameSite string
+
 	// Rate Limiting
 	RateLimitRPS   int
 	RateLimitBurst int
 
-	// AWS SES
+	// AWS SES（SESFromEmail はメール送信元として全ドライバーで使う）
 	AWSRegion    string
 	SESFromEmail string
 
+	// メール送信（MailDriver: log / smtp / ses）
+	MailDriver   string
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// FrontendURL はメール本文のリンク先
+	FrontendURL string
+
+	// パスワードリセット・メールアドレス確認トークンの有効期限
+	PasswordResetTokenExpiryMinutes   int
+	EmailVerificationTokenExpiryHours int
+
+	// 同一メールアドレスへの認証メール送信回数の上限（ウィンドウ内）
+	AuthEmailRateLimit         int
+	AuthEmailRateWindowMinutes int
+
+	// 多要素認証（認証アプリに表示する発行者名とログイン時のチャレンジの有効期限）
+	MFAIssuer                 string
+	MFAChallengeExpiryMinutes int
+
+	// OpenID Connect によるシングルサインオン（OIDCIssuerURL が空の場合は無効）
+	OIDCIssuerURL    string
+	OIDCClientID     string
+	OIDCClientSecret string
+	// OIDCRedirectURL はIdPに登録するコールバック（/api/v1/auth/oidc/callback）
+	OIDCRedirectURL string
+	OIDCScopes      []string
+	OIDCGroupsClaim string
+	// OIDCRoleMapping はIdPのグループから基本ロールへの対応（OIDC_ROLE_MAPPING="group=role,..."）
+	OIDCRoleMapping map[string]string
+	// OIDCAutoProvision が true の場合、未登録のユーザーを初回ログイン時に作成する
+	OIDCAutoProvision bool
+
+	// ログイン試行の制限。アカウントごとに LoginMaxFailedAttempts 回続けて失敗するとロックし、
+	// ロック時間はロックのたびに LoginLockoutMinutes から倍増する（上限 LoginLockoutMaxMinutes）。
+	// IPアドレスごとにはウィンドウ内の失敗が上限に達するとログインを拒否する（0 の場合は制限しない）
+	LoginMaxFailedAttempts   int
+	LoginLockoutMinutes      int
+	LoginLockoutMaxMinutes   int
+	LoginIPMaxFailedAttempts int
+	LoginIPWindowMinutes     int
+	// ログイン履歴の保存期間（日）
+	LoginHistoryRetentionDays int
+
+	// APIトークン（個人用アクセストークン・サービスアカウント）に設定できる有効期限の上限（日）
+	APITokenMaxExpiryDays int
+
+	// 通知のメール配信。NotificationLocale はメールテンプレートの言語（ja / en）。
+	// 配信は非同期で、失敗した場合は NotificationMaxAttempts 回まで間隔を倍にしながら再試行する
+	NotificationEmailEnabled        bool
+	NotificationLocale              string
+	NotificationWorkers             int
+	NotificationMaxAttempts         int
+	NotificationRetryBackoffSeconds int
+
+	// チャット（Slack / Microsoft Teams の Incoming Webhook）への通知。配信先は各ユーザーが通知設定で登録し、
+	// ChatWebhookAllowedHosts のホスト（先頭が "." の場合はそのサブドメイン）への https のみ許可する。
+	// 承認依頼には承認・却下ボタンを付ける。ボタンのトークンは ChatActionSecret で署名し（空の場合はボタンを付けない）、
+	// ChatActionTTLHours 時間で失効する。Slack からのコールバックは SlackSigningSecret で検証し、
+	// Teams のボタンは TeamsActionURL（/api/v1/integrations/teams/actions の公開URL）に送信される
+	NotificationChatEnabled bool
+	ChatWebhookAllowedHosts []string
+	ChatActionSecret        string
+	ChatActionTTLHours      int
+	SlackSigningSecret      string
+	TeamsActionURL          string
+
+	// リアルタイム配信（Server-Sent Events）。EventsReplaySize は Last-Event-ID からの再開のために
+	// 保持する直近のイベント数、EventsHeartbeatSeconds は接続維持のコメント行を送る間隔
+	EventsReplaySize       int
+	EventsHeartbeatSeconds int
+
 	// Sentry
 	SentryDSN string
 
@@ -37,6 +120,22 @@ type Config struct {
 
 	// ログレベル
 	LogLevel string
+
+	// 勤務日の区切りに使うタイムゾーン（APP_TIME_ZONE、IANAのタイムゾーン名）
+	Location *time.Location
+
+	// 代休の有効期限（休日出勤日からの日数）
+	CompensatoryDayExpiryDays int
+
+	// 1日の所定労働時間（分）。これを超え8時間以内の勤務は法定内残業となる
+	ScheduledWorkMinutes int
+
+	// 退勤打刻漏れの自動処理（off / shift_end / incomplete）と実行間隔（分）
+	AttendanceAutoCloseMode            string
+	AttendanceAutoCloseIntervalMinutes int
+
+	// 期限切れリフレッシュトークンの削除間隔（分）
+	RefreshTokenCleanupIntervalMinutes int
 }
 
 // Load は環境変数から設定を読み込む
@@ -50,18 +149,86 @@ func Load() (*Config, error) {
 		JWTAccessTokenExpiry:  getEnvAsInt("JWT_ACCESS_TOKEN_EXPIRY", 15),
 		JWTRefreshTokenExpiry: getEnvAsInt("JWT_REFRESH_TOKEN_EXPIRY", 168),
 		AllowedOrigins:        []string{getEnv("ALLOWED_ORIGINS", "http://localhost:5173")},
+		AuthCookieMode:        getEnvAsBool("AUTH_COOKIE_MODE", false),
+		AuthCookieDomain:      getEnv("AUTH_COOKIE_DOMAIN", ""),
+		AuthCookieSecure:      getEnvAsBool("AUTH_COOKIE_SECURE", true),
+		AuthCookieSameSite:    getEnv("AUTH_COOKIE_SAME_SITE", "lax"),
 		RateLimitRPS:          getEnvAsInt("RATE_LIMIT_RPS", 100),
 		RateLimitBurst:        getEnvAsInt("RATE_LIMIT_BURST", 200),
 		AWSRegion:             getEnv("AWS_REGION", "ap-northeast-1"),
 		SESFromEmail:          getEnv("SES_FROM_EMAIL", "noreply@example.com"),
+		MailDriver:            getEnv("MAIL_DRIVER", "log"),
+		SMTPHost:              getEnv("SMTP_HOST", "localhost"),
+		SMTPPort:              getEnvAsInt("SMTP_PORT", 1025),
+		SMTPUsername:          getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:          getEnv("SMTP_PASSWORD", ""),
+		FrontendURL:           getEnv("FRONTEND_URL", "http://localhost:5173"),
 		SentryDSN:             getEnv("SENTRY_DSN", ""),
 		OTLPEndpoint:          getEnv("OTLP_ENDPOINT", "localhost:4317"),
 		LogLevel:              getEnv("LOG_LEVEL", "debug"),
+
+		CompensatoryDayExpiryDays: getEnvAsInt("COMPENSATORY_DAY_EXPIRY_DAYS", 60),
+		ScheduledWorkMinutes:      getEnvAsInt("SCHEDULED_WORK_MINUTES", 480),
+
+		AttendanceAutoCloseMode:            getEnv("ATTENDANCE_AUTO_CLOSE_MODE", "incomplete"),
+		AttendanceAutoCloseIntervalMinutes: getEnvAsInt("ATTENDANCE_AUTO_CLOSE_INTERVAL_MINUTES", 60),
+
+		RefreshTokenCleanupIntervalMinutes: getEnvAsInt("REFRESH_TOKEN_CLEANUP_INTERVAL_MINUTES", 60),
+
+		PasswordResetTokenExpiryMinutes:   getEnvAsInt("PASSWORD_RESET_TOKEN_EXPIRY_MINUTES", 60),
+		EmailVerificationTokenExpiryHours: getEnvAsInt("EMAIL_VERIFICATION_TOKEN_EXPIRY_HOURS", 48),
+		AuthEmailRateLimit:                getEnvAsInt("AUTH_EMAIL_RATE_LIMIT", 3),
+		AuthEmailRateWindowMinutes:        getEnvAsInt("AUTH_EMAIL_RATE_WINDOW_MINUTES", 15),
+
+		MFAIssuer:                 getEnv("MFA_ISSUER", "Kintai"),
+		MFAChallengeExpiryMinutes: getEnvAsInt("MFA_CHALLENGE_EXPIRY_MINUTES", 5),
+
+		OIDCIssuerURL:     getEnv("OIDC_ISSUER_URL", ""),
+		OIDCClientID:      getEnv("OIDC_CLIENT_ID", ""),
+		OIDCClientSecret:  getEnv("OIDC_CLIENT_SECRET", ""),
+		OIDCRedirectURL:   getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/api/v1/auth/oidc/callback"),
+		OIDCScopes:        strings.Fields(getEnv("OIDC_SCOPES", "openid email profile")),
+		OIDCGroupsClaim:   getEnv("OIDC_GROUPS_CLAIM", "groups"),
+		OIDCRoleMapping:   getEnvAsMap("OIDC_ROLE_MAPPING"),
+		OIDCAutoProvision: getEnvAsBool("OIDC_AUTO_PROVISION", false),
+
+		LoginMaxFailedAttempts:    getEnvAsInt("LOGIN_MAX_FAILED_ATTEMPTS", 5),
+		LoginLockoutMinutes:       getEnvAsInt("LOGIN_LOCKOUT_MINUTES", 5),
+		LoginLockoutMaxMinutes:    getEnvAsInt("LOGIN_LOCKOUT_MAX_MINUTES", 1440),
+		LoginIPMaxFailedAttempts:  getEnvAsInt("LOGIN_IP_MAX_FAILED_ATTEMPTS", 20),
+		LoginIPWindowMinutes:      getEnvAsInt("LOGIN_IP_WINDOW_MINUTES", 15),
+		LoginHistoryRetentionDays: getEnvAsInt("LOGIN_HISTORY_RETENTION_DAYS", 180),
+
+		APITokenMaxExpiryDays: getEnvAsInt("API_TOKEN_MAX_EXPIRY_DAYS", 365),
+
+		NotificationEmailEnabled:        getEnvAsBool("NOTIFICATION_EMAIL_ENABLED", true),
+		NotificationLocale:              getEnv("NOTIFICATION_LOCALE", "ja"),
+		NotificationWorkers:             getEnvAsInt("NOTIFICATION_WORKERS", 2),
+		NotificationMaxAttempts:         getEnvAsInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		NotificationRetryBackoffSeconds: getEnvAsInt("NOTIFICATION_RETRY_BACKOFF_SECONDS", 10),
+		NotificationChatEnabled:         getEnvAsBool("NOTIFICATION_CHAT_ENABLED", false),
+		ChatWebhookAllowedHosts:         strings.Fields(getEnv("CHAT_WEBHOOK_ALLOWED_HOSTS", "hooks.slack.com .webhook.office.com")),
+		ChatActionSecret:                getEnv("CHAT_ACTION_SECRET", ""),
+		ChatActionTTLHours:              getEnvAsInt("CHAT_ACTION_TTL_HOURS", 72),
+		SlackSigningSecret:              getEnv("SLACK_SIGNING_SECRET", ""),
+		TeamsActionURL:                  getEnv("TEAMS_ACTION_URL", "http://localhost:8080/api/v1/integrations/teams/actions"),
+		EventsReplaySize:                getEnvAsInt("EVENTS_REPLAY_SIZE", 1000),
+		EventsHeartbeatSeconds:          getEnvAsInt("EVENTS_HEARTBEAT_SECONDS", 25),
+	}
+
+	loc, err := time.LoadLocation(getEnv("APP_TIME_ZONE", "Asia/Tokyo"))
+	if err != nil {
+		return nil, fmt.Errorf("APP_TIME_ZONE が不正です: %w", err)
 	}
+	cfg.Location = loc
 
 	if cfg.Env == "production" && cfg.JWTSecretKey == "dev-secret-key-change-in-production" {
 		return nil, fmt.Errorf("本番環境ではJWT_SECRET_KEYを設定してください")
 	}
+	// CORS は許可したオリジンを資格情報付きで返すため、Cookie認証では任意のサイトからのリクエストを許してしまう
+	if cfg.AuthCookieMode && slices.Contains(cfg.AllowedOrigins, "*") {
+		return nil, fmt.Errorf("Cookie認証モードではALLOWED_ORIGINSに*を指定できません")
+	}
 
 	return cfg, nil
 }
@@ -81,3 +248,25 @@ func getEnvAsInt(key string, defaultVal int) int {
 	}
 	return defaultVal
 }
+
+func getEnvAsBool(key string, defaultVal bool) bool {
+	if val, ok := os.LookupEnv(key); ok {
+		if boolVal, err := strconv.ParseBool(val); err == nil {
+			return boolVal
+		}
+	}
+	return defaultVal
+}
+
+// getEnvAsMap は "key=value,key=value" 形式を読み込む。形式に合わない要素は無視する
+func getEnvAsMap(key string) map[string]string {
+	m := make(map[string]string)
+	for _, pair := range strings.Split(os.Getenv(key), ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		k, v = strings.TrimSpace(k), strings.TrimSpace(v)
+		if ok && k != "" && v != "" {
+			m[k] = v
+		}
+	}
+	return m
+}
@@ -69,6 +69,32 @@ func TestGetEnvAsInt_Branches(t *testing.T) {
 	})
 }
 
+func TestGetEnvAsBool_Branches(t *testing.T) {
+	t.Setenv("GET_ENV_BOOL_KEY", "true")
+	if !getEnvAsBool("GET_ENV_BOOL_KEY", false) {
+		t.Fatal("expected true")
+	}
+
+	t.Setenv("GET_ENV_BOOL_KEY_INVALID", "yes please")
+	if !getEnvAsBool("GET_ENV_BOOL_KEY_INVALID", true) {
+		t.Fatal("expected default true for invalid value")
+	}
+}
+
+func TestGetEnvAsMap_Branches(t *testing.T) {
+	t.Setenv("GET_ENV_MAP_KEY", " kintai-admins = admin ,managers=manager,broken,=employee")
+
+	got := getEnvAsMap("GET_ENV_MAP_KEY")
+	if len(got) != 2 || got["kintai-admins"] != "admin" || got["managers"] != "manager" {
+		t.Fatalf("unexpected map: %v", got)
+	}
+
+	unsetEnv(t, "GET_ENV_MAP_KEY_MISSING")
+	if got := getEnvAsMap("GET_ENV_MAP_KEY_MISSING"); len(got) != 0 {
+		t.Fatalf("expected empty map, got %v", got)
+	}
+}
+
 func TestLoad_Branches(t *testing.T) {
 	t.Run("production with default jwt secret returns error", func(t *testing.T) {
 		t.Setenv("APP_ENV", "production")
@@ -96,4 +122,43 @@ func TestLoad_Branches(t *testing.T) {
 			t.Fatalf("unexpected AllowedOrigins: %#v", cfg.AllowedOrigins)
 		}
 	})
+
+	t.Run("cookie mode with wildcard origin returns error", func(t *testing.T) {
+		unsetEnv(t, "APP_ENV")
+		t.Setenv("AUTH_COOKIE_MODE", "true")
+		t.Setenv("ALLOWED_ORIGINS", "*")
+
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), "ALLOWED_ORIGINS") {
+			t.Fatalf("expected ALLOWED_ORIGINS error, got %v", err)
+		}
+
+		t.Setenv("AUTH_COOKIE_MODE", "false")
+		if _, err := Load(); err != nil {
+			t.Fatalf("expected wildcard origin to be allowed without cookie mode, got %v", err)
+		}
+	})
+
+	t.Run("time zone defaults to Asia/Tokyo", func(t *testing.T) {
+		unsetEnv(t, "APP_ENV")
+		unsetEnv(t, "APP_TIME_ZONE")
+
+		cfg, err := Load()
+		if err != nil {
+			t.Fatalf("Load() returned error: %v", err)
+		}
+		if cfg.Location == nil || cfg.Location.String() != "Asia/Tokyo" {
+			t.Fatalf("unexpected Location: %v", cfg.Location)
+		}
+	})
+
+	t.Run("invalid time zone returns error", func(t *testing.T) {
+		unsetEnv(t, "APP_ENV")
+		t.Setenv("APP_TIME_ZONE", "Mars/Olympus")
+
+		_, err := Load()
+		if err == nil || !strings.Contains(err.Error(), "APP_TIME_ZONE") {
+			t.Fatalf("expected APP_TIME_ZONE error, got %v", err)
+		}
+	})
 }
@@ -10,8 +10,9 @@ func TestLoad_DefaultValues(t *testing.T) {
 	envVars := []string{
 		"APP_ENV", "APP_PORT", "DATABASE_URL", "REDIS_URL",
 		"JWT_SECRET_KEY", "JWT_ACCESS_TOKEN_EXPIRY", "JWT_REFRESH_TOKEN_EXPIRY",
-		"ALLOWED_ORIGINS", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST",
+		"ALLOWED_ORIGINS", "AUTH_COOKIE_MODE", "AUTH_COOKIE_SECURE", "AUTH_COOKIE_SAME_SITE", "RATE_LIMIT_RPS", "RATE_LIMIT_BURST",
 		"AWS_REGION", "SES_FROM_EMAIL", "SENTRY_DSN", "OTLP_ENDPOINT", "LOG_LEVEL",
+		"COMPENSATORY_DAY_EXPIRY_DAYS", "ATTENDANCE_AUTO_CLOSE_MODE", "ATTENDANCE_AUTO_CLOSE_INTERVAL_MINUTES",
 	}
 	for _, v := range envVars {
 		os.Unsetenv(v)
@@ -44,6 +45,43 @@ func TestLoad_DefaultValues(t *testing.T) {
 	if cfg.LogLevel != "debug" {
 		t.Errorf("Expected LogLevel 'debug', got '%s'", cfg.LogLevel)
 	}
+	if cfg.CompensatoryDayExpiryDays != 60 {
+		t.Errorf("Expected CompensatoryDayExpiryDays 60, got %d", cfg.CompensatoryDayExpiryDays)
+	}
+	if cfg.ScheduledWorkMinutes != 480 {
+		t.Errorf("Expected ScheduledWorkMinutes 480, got %d", cfg.ScheduledWorkMinutes)
+	}
+	if cfg.LoginMaxFailedAttempts != 5 || cfg.LoginLockoutMinutes != 5 || cfg.LoginLockoutMaxMinutes != 1440 {
+		t.Errorf("Expected lockout after 5 failures for 5-1440 minutes, got %d failures for %d-%d minutes",
+			cfg.LoginMaxFailedAttempts, cfg.LoginLockoutMinutes, cfg.LoginLockoutMaxMinutes)
+	}
+	if cfg.LoginIPMaxFailedAttempts != 20 || cfg.LoginIPWindowMinutes != 15 {
+		t.Errorf("Expected 20 failures per IP in 15 minutes, got %d in %d", cfg.LoginIPMaxFailedAttempts, cfg.LoginIPWindowMinutes)
+	}
+	if cfg.APITokenMaxExpiryDays != 365 {
+		t.Errorf("Expected APITokenMaxExpiryDays 365, got %d", cfg.APITokenMaxExpiryDays)
+	}
+	if !cfg.NotificationEmailEnabled || cfg.NotificationLocale != "ja" || cfg.NotificationMaxAttempts != 5 {
+		t.Errorf("Expected Japanese notification emails with 5 attempts, got enabled=%v locale=%s attempts=%d",
+			cfg.NotificationEmailEnabled, cfg.NotificationLocale, cfg.NotificationMaxAttempts)
+	}
+	if cfg.NotificationChatEnabled || cfg.ChatActionSecret != "" || cfg.ChatActionTTLHours != 72 {
+		t.Errorf("Expected chat notifications off with 72h action tokens, got enabled=%v ttl=%d",
+			cfg.NotificationChatEnabled, cfg.ChatActionTTLHours)
+	}
+	if len(cfg.ChatWebhookAllowedHosts) != 2 || cfg.ChatWebhookAllowedHosts[0] != "hooks.slack.com" {
+		t.Errorf("Expected Slack and Teams webhook hosts, got %v", cfg.ChatWebhookAllowedHosts)
+	}
+	if cfg.EventsReplaySize != 1000 || cfg.EventsHeartbeatSeconds != 25 {
+		t.Errorf("Expected 1000 replayable events with 25s heartbeat, got %d / %ds", cfg.EventsReplaySize, cfg.EventsHeartbeatSeconds)
+	}
+	if cfg.AuthCookieMode || !cfg.AuthCookieSecure || cfg.AuthCookieSameSite != "lax" {
+		t.Errorf("Expected cookie mode off with secure lax cookies, got mode=%v secure=%v samesite=%s",
+			cfg.AuthCookieMode, cfg.AuthCookieSecure, cfg.AuthCookieSameSite)
+	}
+	if cfg.AttendanceAutoCloseMode != "incomplete" || cfg.AttendanceAutoCloseIntervalMinutes != 60 {
+		t.Errorf("Expected auto-close 'incomplete' every 60 minutes, got '%s' every %d", cfg.AttendanceAutoCloseMode, cfg.AttendanceAutoCloseIntervalMinutes)
+	}
 }
 
 func TestLoad_CustomValues(t *testing.T) {
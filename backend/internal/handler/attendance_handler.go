@@ -11,6 +11,10 @@ type LeaveHandler = appattendance.LeaveHandler
 type OvertimeRequestHandler = appattendance.OvertimeRequestHandler
 type LeaveBalanceHandler = appattendance.LeaveBalanceHandler
 type AttendanceCorrectionHandler = appattendance.AttendanceCorrectionHandler
+type SubstituteHolidayHandler = appattendance.SubstituteHolidayHandler
+type WorkingHourPolicyHandler = appattendance.WorkingHourPolicyHandler
+type RemoteWorkPolicyHandler = appattendance.RemoteWorkPolicyHandler
+type TeamHandler = appattendance.TeamHandler
 
 func NewAttendanceHandler(svc service.AttendanceService, logger *logger.Logger) *AttendanceHandler {
 	return appattendance.NewAttendanceHandler(svc, logger)
@@ -31,3 +35,19 @@ func NewLeaveBalanceHandler(svc service.LeaveBalanceService, logger *logger.Logg
 func NewAttendanceCorrectionHandler(svc service.AttendanceCorrectionService, logger *logger.Logger) *AttendanceCorrectionHandler {
 	return appattendance.NewAttendanceCorrectionHandler(svc, logger)
 }
+
+func NewSubstituteHolidayHandler(svc service.SubstituteHolidayService, logger *logger.Logger) *SubstituteHolidayHandler {
+	return appattendance.NewSubstituteHolidayHandler(svc, logger)
+}
+
+func NewWorkingHourPolicyHandler(svc service.WorkingHourPolicyService, logger *logger.Logger) *WorkingHourPolicyHandler {
+	return appattendance.NewWorkingHourPolicyHandler(svc, logger)
+}
+
+func NewRemoteWorkPolicyHandler(svc service.RemoteWorkPolicyService, logger *logger.Logger) *RemoteWorkPolicyHandler {
+	return appattendance.NewRemoteWorkPolicyHandler(svc, logger)
+}
+
+func NewTeamHandler(svc service.TeamService, logger *logger.Logger) *TeamHandler {
+	return appattendance.NewTeamHandler(svc, logger)
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/service"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+func newChatActionRouter(svc service.ChatActionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	log, _ := logger.NewLogger("debug", "test")
+	h := NewChatActionHandler(svc, log)
+	r := gin.New()
+	r.POST("/integrations/slack/actions", h.SlackAction)
+	r.POST("/integrations/teams/actions", h.TeamsAction)
+	return r
+}
+
+func TestChatActionHandler_SlackAction(t *testing.T) {
+	var gotTimestamp, gotSignature, gotBody string
+	svc := &mocks.MockChatActionService{
+		HandleSlackActionFunc: func(_ context.Context, timestamp, signature string, body []byte) (string, error) {
+			gotTimestamp, gotSignature, gotBody = timestamp, signature, string(body)
+			if signature != "v0=valid" {
+				return "", service.ErrInvalidSlackRequest
+			}
+			return "承認しました", nil
+		},
+	}
+	r := newChatActionRouter(svc)
+
+	for _, tc := range []struct {
+		signature string
+		want      int
+	}{
+		{"v0=valid", http.StatusOK},
+		{"v0=forged", http.StatusUnauthorized},
+	} {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/integrations/slack/actions", strings.NewReader("payload=%7B%7D"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("X-Slack-Request-Timestamp", "1700000000")
+		req.Header.Set("X-Slack-Signature", tc.signature)
+		r.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected %d, got %d", tc.signature, tc.want, w.Code)
+		}
+	}
+	// 署名は受信した本文そのものに対して検証する
+	if gotTimestamp != "1700000000" || gotSignature != "v0=forged" || gotBody != "payload=%7B%7D" {
+		t.Errorf("Unexpected request passed to the service: %q %q %q", gotTimestamp, gotSignature, gotBody)
+	}
+
+	disabled := newChatActionRouter(&mocks.MockChatActionService{
+		HandleSlackActionFunc: func(context.Context, string, string, []byte) (string, error) {
+			return "", service.ErrChatActionsDisabled
+		},
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/integrations/slack/actions", strings.NewReader(""))
+	disabled.ServeHTTP(w, req)
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when chat actions are disabled, got %d", w.Code)
+	}
+}
+
+func TestChatActionHandler_TeamsAction(t *testing.T) {
+	svc := &mocks.MockChatActionService{
+		HandleTeamsActionFunc: func(_ context.Context, authorization, token string) (string, error) {
+			if authorization != "Bearer teams-jwt" {
+				return "", service.ErrInvalidChatAction
+			}
+			switch token {
+			case "ok":
+				return "山田 太郎 さんの残業申請（2026-02-10）を承認しました", nil
+			case "done":
+				return "", service.ErrChatActionProcessed
+			default:
+				return "", service.ErrInvalidChatAction
+			}
+		},
+	}
+	r := newChatActionRouter(svc)
+	post := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/integrations/teams/actions", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer teams-jwt")
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	w := post(`{"token":"ok"}`)
+	var resp map[string]string
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if w.Code != http.StatusOK || w.Header().Get("CARD-ACTION-STATUS") != resp["message"] || !strings.HasSuffix(resp["message"], "を承認しました") {
+		t.Errorf("Unexpected response: %d %s %s", w.Code, w.Header().Get("CARD-ACTION-STATUS"), w.Body.String())
+	}
+
+	// 処理済みなどの失敗もカードに表示するため 200 で返す
+	w = post(`{"token":"done"}`)
+	if w.Code != http.StatusOK || w.Header().Get("CARD-ACTION-STATUS") != service.ErrChatActionProcessed.Error() {
+		t.Errorf("Unexpected response: %d %s", w.Code, w.Header().Get("CARD-ACTION-STATUS"))
+	}
+	if w := post(`{"token":"forged"}`); w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for an invalid token, got %d", w.Code)
+	}
+	if w := post(`{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 without a token, got %d", w.Code)
+	}
+
+	// Teams のトークンがないリクエストは押した人を確認できないため拒否する
+	w = httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/integrations/teams/actions", strings.NewReader(`{"token":"ok"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without the Teams token, got %d", w.Code)
+	}
+}
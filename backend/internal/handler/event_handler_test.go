@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/realtime"
+)
+
+// sseEvent は受信した1件のイベント
+type sseEvent struct {
+	id, event, data string
+}
+
+// newEventStreamServer は userID を認証済みとして扱うイベントストリームのサーバーを起動する
+func newEventStreamServer(t *testing.T, hub *realtime.Hub, userID uuid.UUID) *httptest.Server {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/events", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		c.Next()
+	}, NewEventStreamHandler(hub, nil).Stream)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// openEventStream は購読を開始し、届いたイベントを順に返すチャネルを返す
+func openEventStream(t *testing.T, ctx context.Context, url, lastEventID string) <-chan sseEvent {
+	t.Helper()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if lastEventID != "" {
+		req.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response: %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+
+	events := make(chan sseEvent, 16)
+	go func() {
+		defer resp.Body.Close()
+		defer close(events)
+		var ev sseEvent
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case line == "":
+				if ev.event != "" {
+					events <- ev
+				}
+				ev = sseEvent{}
+			case strings.HasPrefix(line, "id: "):
+				ev.id = strings.TrimPrefix(line, "id: ")
+			case strings.HasPrefix(line, "event: "):
+				ev.event = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				ev.data = strings.TrimPrefix(line, "data: ")
+			}
+		}
+	}()
+	return events
+}
+
+func nextEvent(t *testing.T, events <-chan sseEvent) sseEvent {
+	t.Helper()
+	select {
+	case ev, ok := <-events:
+		if !ok {
+			t.Fatal("stream closed unexpectedly")
+		}
+		return ev
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for event")
+	}
+	return sseEvent{}
+}
+
+// waitForSubscribers は購読の登録を待つ（登録前に発行したイベントは届かないため）
+func waitForSubscribers(t *testing.T, hub *realtime.Hub, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for hub.Subscribers() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d subscribers, got %d", n, hub.Subscribers())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestEventStreamHandler_StreamsUserEvents(t *testing.T) {
+	hub := realtime.NewHub(nil, realtime.Config{ReplaySize: 10}, nil)
+	userID := uuid.New()
+	srv := newEventStreamServer(t, hub, userID)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := openEventStream(t, ctx, srv.URL+"/events", "")
+	waitForSubscribers(t, hub, 1)
+
+	hub.PublishTo(ctx, uuid.New(), realtime.EventNotification, map[string]string{"title": "他人宛"})
+	hub.PublishTo(ctx, userID, realtime.EventApproval, realtime.ApprovalChange{Kind: "leave", ID: uuid.New(), Status: "approved"})
+	hub.Broadcast(ctx, realtime.EventAnnouncement, realtime.AnnouncementChange{ID: uuid.New(), Title: "全社会議"})
+
+	ev := nextEvent(t, events)
+	if ev.event != "approval" || ev.id == "" || !strings.Contains(ev.data, `"status":"approved"`) {
+		t.Errorf("unexpected first event: %+v", ev)
+	}
+	if ev := nextEvent(t, events); ev.event != "announcement" || !strings.Contains(ev.data, "全社会議") {
+		t.Errorf("unexpected second event: %+v", ev)
+	}
+
+	// 切断すると購読も終了する
+	cancel()
+	waitForSubscribers(t, hub, 0)
+}
+
+func TestEventStreamHandler_ResumesFromLastEventID(t *testing.T) {
+	hub := realtime.NewHub(nil, realtime.Config{ReplaySize: 10}, nil)
+	userID := uuid.New()
+	srv := newEventStreamServer(t, hub, userID)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events := openEventStream(t, ctx, srv.URL+"/events", "")
+	waitForSubscribers(t, hub, 1)
+	hub.PublishTo(ctx, userID, realtime.EventNotification, map[string]int{"n": 1})
+	last := nextEvent(t, events)
+	cancel()
+	waitForSubscribers(t, hub, 0)
+
+	// 切断中に発行されたイベント
+	hub.PublishTo(context.Background(), userID, realtime.EventNotification, map[string]int{"n": 2})
+
+	ctx2, cancel2 := context.WithCancel(context.Background())
+	defer cancel2()
+	resumed := openEventStream(t, ctx2, srv.URL+"/events", last.id)
+	if ev := nextEvent(t, resumed); ev.data != `{"n":2}` {
+		t.Errorf("expected missed event to be replayed, got %+v", ev)
+	}
+}
+
+func TestEventStreamHandler_ResyncsUnknownLastEventID(t *testing.T) {
+	hub := realtime.NewHub(nil, realtime.Config{ReplaySize: 10}, nil)
+	srv := newEventStreamServer(t, hub, uuid.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events := openEventStream(t, ctx, srv.URL+"/events", "expired-id")
+	if ev := nextEvent(t, events); ev.event != "resync" {
+		t.Errorf("expected resync event, got %+v", ev)
+	}
+}
+
+func TestEventStreamHandler_Unavailable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewEventStreamHandler(nil, nil)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+	h.Stream(c)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without user, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/events", nil)
+	c.Set("userID", uuid.New().String())
+	h.Stream(c)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected 503 without hub, got %d", w.Code)
+	}
+}
@@ -2092,8 +2092,8 @@ func TestNewExpensePolicyHandler(t *testing.T) {
 func TestExpenseNotificationHandler_GetNotifications_Success(t *testing.T) {
 	userID := uuid.New()
 	mockService := &mocks.MockExpenseNotificationService{
-		GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
-			return []model.ExpenseNotification{{BaseModel: model.BaseModel{ID: uuid.New()}, Message: "承認されました"}}, nil
+		GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, filter string) ([]model.Notification, error) {
+			return []model.Notification{{BaseModel: model.BaseModel{ID: uuid.New()}, Message: "承認されました"}}, nil
 		},
 	}
 	handler := NewExpenseNotificationHandler(mockService, getTestLogger())
@@ -2130,7 +2130,7 @@ func TestExpenseNotificationHandler_GetNotifications_Unauthorized(t *testing.T)
 func TestExpenseNotificationHandler_GetNotifications_ServiceError(t *testing.T) {
 	userID := uuid.New()
 	mockService := &mocks.MockExpenseNotificationService{
-		GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
+		GetNotificationsFunc: func(ctx context.Context, uid uuid.UUID, filter string) ([]model.Notification, error) {
 			return nil, errors.New("notifications error")
 		},
 	}
@@ -2,13 +2,23 @@ package handler
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
 	"github.com/your-org/kintai/backend/internal/service"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
@@ -16,17 +26,31 @@ import (
 // Handlers は全ハンドラーを束ねる構造体
 type Handlers struct {
 	Auth                 *AuthHandler
+	MFA                  *MFAHandler
+	SSO                  *SSOHandler
+	LoginSecurity        *LoginSecurityHandler
+	Session              *SessionHandler
+	APIToken             *APITokenHandler
+	Audit                *AuditHandler
 	Attendance           *AttendanceHandler
 	Leave                *LeaveHandler
 	Shift                *ShiftHandler
+	ShiftSchedule        *ShiftScheduleHandler
 	User                 *UserHandler
 	Department           *DepartmentHandler
+	Role                 *RoleHandler
 	Dashboard            *DashboardHandler
 	Health               *HealthHandler
 	OvertimeRequest      *OvertimeRequestHandler
 	LeaveBalance         *LeaveBalanceHandler
 	AttendanceCorrection *AttendanceCorrectionHandler
+	SubstituteHoliday    *SubstituteHolidayHandler
+	WorkingHourPolicy    *WorkingHourPolicyHandler
+	RemoteWorkPolicy     *RemoteWorkPolicyHandler
+	Team                 *TeamHandler
 	Notification         *NotificationHandler
+	Events               *EventStreamHandler
+	ChatAction           *ChatActionHandler
 	Project              *ProjectHandler
 	TimeEntry            *TimeEntryHandler
 	Holiday              *HolidayHandler
@@ -73,17 +97,31 @@ func NewHandlers(services *service.Services, logger *logger.Logger) *Handlers {
 
 	return &Handlers{
 		Auth:                 NewAuthHandler(services.Auth, logger),
+		MFA:                  NewMFAHandler(services.MFA, logger),
+		SSO:                  NewSSOHandler(services.SSO, logger),
+		LoginSecurity:        NewLoginSecurityHandler(services.LoginSecurity, logger),
+		Session:              NewSessionHandler(services.Session, logger),
+		APIToken:             NewAPITokenHandler(services.APIToken, logger),
+		Audit:                NewAuditHandler(services.Audit, logger),
 		Attendance:           NewAttendanceHandler(services.Attendance, logger),
 		Leave:                NewLeaveHandler(services.Leave, logger),
 		Shift:                NewShiftHandler(services.Shift, logger),
+		ShiftSchedule:        NewShiftScheduleHandler(services.ShiftSchedule, logger),
 		User:                 NewUserHandler(services.User, logger),
 		Department:           NewDepartmentHandler(services.Department, logger),
+		Role:                 NewRoleHandler(services.Role, logger),
 		Dashboard:            NewDashboardHandler(services.Dashboard, logger),
 		Health:               NewHealthHandlerWithChecks(readinessChecks...),
 		OvertimeRequest:      NewOvertimeRequestHandler(services.OvertimeRequest, logger),
 		LeaveBalance:         NewLeaveBalanceHandler(services.LeaveBalance, logger),
 		AttendanceCorrection: NewAttendanceCorrectionHandler(services.AttendanceCorrection, logger),
+		SubstituteHoliday:    NewSubstituteHolidayHandler(services.SubstituteHoliday, logger),
+		WorkingHourPolicy:    NewWorkingHourPolicyHandler(services.WorkingHourPolicy, logger),
+		RemoteWorkPolicy:     NewRemoteWorkPolicyHandler(services.RemoteWorkPolicy, logger),
+		Team:                 NewTeamHandler(services.Team, logger),
 		Notification:         NewNotificationHandler(services.Notification, logger),
+		Events:               NewEventStreamHandler(services.Events, logger),
+		ChatAction:           NewChatActionHandler(services.ChatAction, logger),
 		Project:              NewProjectHandler(services.Project, logger),
 		TimeEntry:            NewTimeEntryHandler(services.TimeEntry, logger),
 		Holiday:              NewHolidayHandler(services.Holiday, logger),
@@ -141,6 +179,16 @@ func parseUUID(c *gin.Context, param string) (uuid.UUID, error) {
 	return id, nil
 }
 
+// respondValidationError は入力検証エラーであれば項目別エラーを返して true を返す
+func respondValidationError(c *gin.Context, err error) bool {
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		return false
+	}
+	c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: http.StatusBadRequest, Message: verr.Error(), Errors: verr.Fields})
+	return true
+}
+
 func parsePagination(c *gin.Context) (int, int) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
@@ -254,6 +302,7 @@ func (h *HealthHandler) Readiness(c *gin.Context) {
 type AuthHandler struct {
 	service service.AuthService
 	logger  *logger.Logger
+	cookies *authCookies
 }
 
 func NewAuthHandler(service service.AuthService, logger *logger.Logger) *AuthHandler {
@@ -268,6 +317,8 @@ func NewAuthHandler(service service.AuthService, logger *logger.Logger) *AuthHan
 // @Param body body model.LoginRequest true "ログイン情報"
 // @Success 200 {object} model.TokenResponse
 // @Failure 401 {object} model.ErrorResponse
+// @Failure 423 {object} model.ErrorResponse "連続失敗によるロック中"
+// @Failure 429 {object} model.ErrorResponse "同じIPアドレスからの失敗が多すぎる"
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req model.LoginRequest
@@ -278,11 +329,18 @@ func (h *AuthHandler) Login(c *gin.Context) {
 
 	token, err := h.service.Login(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+		switch {
+		case errors.Is(err, service.ErrAccountLocked):
+			c.JSON(http.StatusLocked, model.ErrorResponse{Code: 423, Message: err.Error()})
+		case errors.Is(err, service.ErrTooManyLoginAttempts):
+			c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Code: 429, Message: err.Error()})
+		default:
+			c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, token)
+	respondToken(c, h.cookies, http.StatusOK, token, token)
 }
 
 // Register godoc
@@ -312,26 +370,31 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // RefreshToken godoc
 // @Summary トークンリフレッシュ
+// @Description Cookie認証モードでは本文を省略するとリフレッシュトークンのCookieを使う
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param body body model.RefreshTokenRequest true "リフレッシュトークン"
+// @Param body body model.RefreshTokenRequest false "リフレッシュトークン"
 // @Success 200 {object} model.TokenResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	var req model.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil && h.cookies == nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
 		return
 	}
+	if req.RefreshToken == "" && h.cookies != nil {
+		req.RefreshToken, _ = c.Cookie(middleware.RefreshTokenCookie)
+	}
 
 	token, err := h.service.RefreshToken(c.Request.Context(), req.RefreshToken)
 	if err != nil {
+		h.cookies.clear(c)
 		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, token)
+	respondToken(c, h.cookies, http.StatusOK, token, token)
 }
 
 // Logout godoc
@@ -352,270 +415,2041 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 		return
 	}
 
+	h.cookies.clear(c)
 	c.Status(http.StatusNoContent)
 }
 
-// ===== ShiftHandler =====
+// CSRFToken godoc
+// @Summary CSRFトークンの取得
+// @Description Cookie認証モードで、ログインを含む状態を変更するリクエストの X-CSRF-Token ヘッダーに設定するトークンを発行する（Cookieにも設定する）
+// @Tags auth
+// @Produce json
+// @Success 200 {object} model.CSRFTokenResponse
+// @Failure 404 {object} model.ErrorResponse "Cookie認証モードが無効"
+// @Router /auth/csrf [get]
+func (h *AuthHandler) CSRFToken(c *gin.Context) {
+	if h.cookies == nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "Cookie認証は無効です"})
+		return
+	}
+	// 発行済みのトークンは使い回す（複数のタブで同時に使えるようにする）
+	token, err := c.Cookie(middleware.CSRFCookie)
+	if err != nil || token == "" {
+		if token, err = newCSRFToken(); err != nil {
+			c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "CSRFトークンの発行に失敗しました"})
+			return
+		}
+		h.cookies.set(c, middleware.CSRFCookie, token, h.cookies.refreshMaxAge, "/", false)
+	}
+	c.JSON(http.StatusOK, model.CSRFTokenResponse{CSRFToken: token})
+}
 
-type ShiftHandler struct {
-	service service.ShiftService
-	logger  *logger.Logger
+// ForgotPassword godoc
+// @Summary パスワードリセットメールの送信
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body model.ForgotPasswordRequest true "メールアドレス"
+// @Success 202
+// @Failure 429 {object} model.ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req model.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	if err := h.service.RequestPasswordReset(c.Request.Context(), req.Email); err != nil {
+		respondAuthEmailError(c, err)
+		return
+	}
+
+	// 登録の有無にかかわらず同じ応答を返す
+	c.JSON(http.StatusAccepted, gin.H{"message": "登録済みのメールアドレスであれば、パスワード再設定のご案内を送信しました"})
 }
 
-func NewShiftHandler(service service.ShiftService, logger *logger.Logger) *ShiftHandler {
-	return &ShiftHandler{service: service, logger: logger}
+// ResetPassword godoc
+// @Summary パスワードの再設定
+// @Tags auth
+// @Accept json
+// @Param body body model.ResetPasswordRequest true "リセットトークンと新しいパスワード"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req model.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	if err := h.service.ResetPassword(c.Request.Context(), &req); err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "パスワードの再設定に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
-// CreateShift godoc
-// @Summary シフトを作成
-// @Tags shifts
-// @Security BearerAuth
+// VerifyEmail godoc
+// @Summary メールアドレスの確認
+// @Tags auth
+// @Accept json
+// @Param body body model.VerifyEmailRequest true "確認トークン"
+// @Success 204
+// @Failure 400 {object} model.ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req model.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	if err := h.service.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "メールアドレスの確認に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResendVerification godoc
+// @Summary 確認メールの再送
+// @Tags auth
 // @Accept json
 // @Produce json
-// @Param body body model.ShiftCreateRequest true "シフト情報"
-// @Success 201 {object} model.Shift
-// @Router /shifts [post]
-func (h *ShiftHandler) Create(c *gin.Context) {
-	var req model.ShiftCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+// @Param body body model.ResendVerificationRequest true "メールアドレス"
+// @Success 202
+// @Failure 429 {object} model.ErrorResponse
+// @Router /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	var req model.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Email == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
 		return
 	}
 
-	shift, err := h.service.Create(c.Request.Context(), &req)
+	if err := h.service.ResendVerification(c.Request.Context(), req.Email); err != nil {
+		respondAuthEmailError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "未確認のメールアドレスであれば、確認メールを再送しました"})
+}
+
+func respondAuthEmailError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrTooManyEmailRequests) {
+		c.JSON(http.StatusTooManyRequests, model.ErrorResponse{Code: 429, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "メールの送信に失敗しました"})
+}
+
+// VerifyMFA godoc
+// @Summary ログイン時の多要素認証
+// @Description ログインで返されたMFAトークンと認証アプリのコード（またはリカバリーコード）でトークンを発行する
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body model.MFAVerifyRequest true "MFAトークンと認証コード"
+// @Success 200 {object} model.TokenResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	var req model.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.MFAToken == "" || req.Code == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	token, err := h.service.VerifyMFA(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		respondMFAChallengeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, shift)
+	respondToken(c, h.cookies, http.StatusOK, token, token)
 }
 
-// BulkCreateShifts godoc
-// @Summary シフトを一括作成
-// @Tags shifts
-// @Security BearerAuth
+// StartMFASetup godoc
+// @Summary ログイン時の多要素認証の登録を開始
+// @Description ポリシーで多要素認証が必須の未登録ユーザーが、MFAトークンで認証アプリの登録情報を取得する
+// @Tags auth
 // @Accept json
 // @Produce json
-// @Param body body model.ShiftBulkCreateRequest true "シフト一括情報"
-// @Success 201 {object} map[string]string
-// @Router /shifts/bulk [post]
-func (h *ShiftHandler) BulkCreate(c *gin.Context) {
-	var req model.ShiftBulkCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+// @Param body body model.MFATokenRequest true "MFAトークン"
+// @Success 200 {object} model.MFASetupResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/mfa/setup [post]
+func (h *AuthHandler) StartMFASetup(c *gin.Context) {
+	var req model.MFATokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.MFAToken == "" {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
 		return
 	}
 
-	if err := h.service.BulkCreate(c.Request.Context(), &req); err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+	setup, err := h.service.StartMFASetup(c.Request.Context(), req.MFAToken)
+	if err != nil {
+		respondMFAChallengeError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusCreated, gin.H{"message": "シフトを一括作成しました"})
+	c.JSON(http.StatusOK, setup)
 }
 
-// GetShifts godoc
-// @Summary シフト一覧を取得
-// @Tags shifts
+// CompleteMFASetup godoc
+// @Summary ログイン時の多要素認証の登録を完了
+// @Description 認証アプリのコードで登録を確認し、リカバリーコードとトークンを返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body model.MFAVerifyRequest true "MFAトークンと認証コード"
+// @Success 200 {object} model.MFAEnrollmentResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/mfa/setup/confirm [post]
+func (h *AuthHandler) CompleteMFASetup(c *gin.Context) {
+	var req model.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.MFAToken == "" || req.Code == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	enrollment, err := h.service.CompleteMFASetup(c.Request.Context(), &req)
+	if err != nil {
+		respondMFAChallengeError(c, err)
+		return
+	}
+
+	respondToken(c, h.cookies, http.StatusOK, enrollment, enrollment.TokenResponse)
+}
+
+func respondMFAChallengeError(c *gin.Context, err error) {
+	if errors.Is(err, service.ErrMFAChallengeInvalid) || errors.Is(err, service.ErrInvalidMFACode) {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, service.ErrMFAAlreadyEnabled) || errors.Is(err, service.ErrMFANotEnabled) {
+		c.JSON(http.StatusConflict, model.ErrorResponse{Code: 409, Message: err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "多要素認証に失敗しました"})
+}
+
+// ===== SSOHandler =====
+
+const (
+	// ssoFlowCookie はログイン開始からコールバックまで state・nonce・code_verifier を保持するCookie
+	ssoFlowCookie     = "kintai_sso_flow"
+	ssoFlowCookiePath = "/api/v1/auth/oidc"
+)
+
+type SSOHandler struct {
+	service service.SSOService
+	logger  *logger.Logger
+	cookies *authCookies
+}
+
+func NewSSOHandler(service service.SSOService, logger *logger.Logger) *SSOHandler {
+	return &SSOHandler{service: service, logger: logger}
+}
+
+// SSOLogin godoc
+// @Summary シングルサインオンを開始
+// @Description IdPの認可エンドポイントにリダイレクトする
+// @Tags auth
+// @Success 302
+// @Failure 404 {object} model.ErrorResponse
+// @Router /auth/oidc/login [get]
+func (h *SSOHandler) Login(c *gin.Context) {
+	auth, err := h.service.Begin(c.Request.Context())
+	if err != nil {
+		if errors.Is(err, service.ErrSSONotConfigured) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		h.logger.Error("SSOの開始に失敗しました", "error", err)
+		c.JSON(http.StatusBadGateway, model.ErrorResponse{Code: 502, Message: "IdPに接続できません"})
+		return
+	}
+
+	maxAge := int(time.Until(auth.ExpiresAt).Seconds())
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoFlowCookie, auth.FlowToken, maxAge, ssoFlowCookiePath, "", isSecureRequest(c), true)
+	c.Redirect(http.StatusFound, auth.AuthorizationURL)
+}
+
+// SSOCallback godoc
+// @Summary シングルサインオンのコールバック
+// @Description IdPの応答を検証し、ログインコードを付けてフロントエンドにリダイレクトする（失敗時は sso_error を付ける）
+// @Tags auth
+// @Param code query string false "認可コード"
+// @Param state query string false "state"
+// @Success 302
+// @Router /auth/oidc/callback [get]
+func (h *SSOHandler) Callback(c *gin.Context) {
+	var cb model.SSOCallback
+	_ = c.ShouldBindQuery(&cb)
+	flowToken, _ := c.Cookie(ssoFlowCookie)
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(ssoFlowCookie, "", -1, ssoFlowCookiePath, "", isSecureRequest(c), true)
+
+	redirectURL, err := h.service.Callback(c.Request.Context(), flowToken, &cb)
+	if err != nil {
+		h.logger.Warn("SSOログインに失敗しました", "error", err)
+	}
+	c.Redirect(http.StatusFound, redirectURL)
+}
+
+// SSOExchange godoc
+// @Summary SSOのログインコードをトークンに交換
+// @Description MFAが必要な場合はパスワードログインと同じく mfa_token を返す
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param body body model.SSOExchangeRequest true "ログインコード"
+// @Success 200 {object} model.TokenResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Router /auth/oidc/exchange [post]
+func (h *SSOHandler) Exchange(c *gin.Context) {
+	var req model.SSOExchangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	token, err := h.service.Exchange(c.Request.Context(), req.Code)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidUserToken) || errors.Is(err, service.ErrSSOAccountDisabled) {
+			c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "ログインに失敗しました"})
+		return
+	}
+
+	respondToken(c, h.cookies, http.StatusOK, token, token)
+}
+
+// isSecureRequest はTLS（リバースプロキシ経由を含む）のリクエストかを返す
+func isSecureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || c.GetHeader("X-Forwarded-Proto") == "https"
+}
+
+// ===== Cookie認証 =====
+
+const (
+	// アクセストークンはAPI全体、リフレッシュトークンはリフレッシュ・ログアウトの時だけ送信させる
+	accessTokenCookiePath  = "/api/v1"
+	refreshTokenCookiePath = "/api/v1/auth"
+)
+
+// authCookies はCookie認証モードでトークンとCSRFトークンをCookieに設定する。nil の場合はCookieを使わない
+type authCookies struct {
+	domain        string
+	secure        bool
+	sameSite      http.SameSite
+	refreshMaxAge int
+}
+
+// WithCookieAuth はCookie認証モード（AUTH_COOKIE_MODE）を認証系のハンドラーに設定する。無効な場合は何もしない
+func (h *Handlers) WithCookieAuth(cfg *config.Config) *Handlers {
+	if cfg == nil || !cfg.AuthCookieMode {
+		return h
+	}
+	cookies := &authCookies{
+		domain:        cfg.AuthCookieDomain,
+		secure:        cfg.AuthCookieSecure,
+		sameSite:      parseSameSite(cfg.AuthCookieSameSite),
+		refreshMaxAge: cfg.JWTRefreshTokenExpiry * 60 * 60,
+	}
+	h.Auth.cookies = cookies
+	h.SSO.cookies = cookies
+	return h
+}
+
+func parseSameSite(s string) http.SameSite {
+	switch strings.ToLower(s) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
+}
+
+func (a *authCookies) set(c *gin.Context, name, value string, maxAge int, path string, httpOnly bool) {
+	c.SetSameSite(a.sameSite)
+	c.SetCookie(name, value, maxAge, path, a.domain, a.secure, httpOnly)
+}
+
+// setTokens は発行したトークンをCookieに移し、応答本文からは取り除く。
+// ログインのたびにCSRFトークンも発行し直す
+func (a *authCookies) setTokens(c *gin.Context, token *model.TokenResponse) error {
+	if a == nil || token == nil || token.AccessToken == "" {
+		return nil
+	}
+	csrf, err := newCSRFToken()
+	if err != nil {
+		return err
+	}
+	a.set(c, middleware.AccessTokenCookie, token.AccessToken, token.ExpiresIn, accessTokenCookiePath, true)
+	a.set(c, middleware.RefreshTokenCookie, token.RefreshToken, a.refreshMaxAge, refreshTokenCookiePath, true)
+	a.set(c, middleware.CSRFCookie, csrf, a.refreshMaxAge, "/", false)
+	token.AccessToken = ""
+	token.RefreshToken = ""
+	token.CSRFToken = csrf
+	return nil
+}
+
+// clear はトークンのCookieを削除する
+func (a *authCookies) clear(c *gin.Context) {
+	if a == nil {
+		return
+	}
+	a.set(c, middleware.AccessTokenCookie, "", -1, accessTokenCookiePath, true)
+	a.set(c, middleware.RefreshTokenCookie, "", -1, refreshTokenCookiePath, true)
+}
+
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// respondToken はトークンを応答する。Cookie認証モードではトークンをCookieに設定する
+func respondToken(c *gin.Context, cookies *authCookies, status int, body any, token *model.TokenResponse) {
+	if err := cookies.setTokens(c, token); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "トークンの発行に失敗しました"})
+		return
+	}
+	c.JSON(status, body)
+}
+
+// ===== LoginSecurityHandler =====
+
+type LoginSecurityHandler struct {
+	service service.LoginSecurityService
+	logger  *logger.Logger
+}
+
+func NewLoginSecurityHandler(service service.LoginSecurityService, logger *logger.Logger) *LoginSecurityHandler {
+	return &LoginSecurityHandler{service: service, logger: logger}
+}
+
+// GetMyLoginHistory godoc
+// @Summary 自分のログイン履歴を取得
+// @Tags auth
 // @Security BearerAuth
 // @Produce json
-// @Param start_date query string true "開始日"
-// @Param end_date query string true "終了日"
-// @Success 200 {array} model.Shift
-// @Router /shifts [get]
-func (h *ShiftHandler) GetByDateRange(c *gin.Context) {
-	start, end, err := parseDateRange(c)
+// @Param page query int false "ページ番号"
+// @Param page_size query int false "ページサイズ"
+// @Success 200 {object} model.PaginatedResponse
+// @Router /users/me/login-history [get]
+func (h *LoginSecurityHandler) GetMyHistory(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	h.respondHistory(c, userID)
+}
+
+// GetLoginHistory godoc
+// @Summary ユーザーのログイン履歴を取得 (管理者用)
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ユーザーID"
+// @Param page query int false "ページ番号"
+// @Param page_size query int false "ページサイズ"
+// @Success 200 {object} model.PaginatedResponse
+// @Router /users/{id}/login-history [get]
+func (h *LoginSecurityHandler) GetHistory(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "日付フォーマットが不正です"})
 		return
 	}
+	h.respondHistory(c, userID)
+}
 
-	shifts, err := h.service.GetByDateRange(c.Request.Context(), start, end)
+func (h *LoginSecurityHandler) respondHistory(c *gin.Context, userID uuid.UUID) {
+	page, pageSize := parsePagination(c)
+	histories, total, err := h.service.GetHistory(c.Request.Context(), userID, page, pageSize)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
 		return
 	}
-
-	c.JSON(http.StatusOK, shifts)
+	paginatedResponse(c, histories, total, page, pageSize)
 }
 
-// DeleteShift godoc
-// @Summary シフトを削除
-// @Tags shifts
+// UnlockUser godoc
+// @Summary アカウントのロックを解除 (管理者用)
+// @Description ログインの連続失敗によるロックと失敗回数の記録を解除する
+// @Tags auth
 // @Security BearerAuth
-// @Param id path string true "シフトID"
+// @Param id path string true "ユーザーID"
 // @Success 204
-// @Router /shifts/{id} [delete]
-func (h *ShiftHandler) Delete(c *gin.Context) {
-	id, err := parseUUID(c, "id")
+// @Failure 404 {object} model.ErrorResponse
+// @Router /users/{id}/unlock [post]
+func (h *LoginSecurityHandler) Unlock(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
 	if err != nil {
 		return
 	}
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
+	if err := h.service.Unlock(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "ロックの解除に失敗しました"})
 		return
 	}
 
 	c.Status(http.StatusNoContent)
 }
 
-// ===== UserHandler =====
+// ===== SessionHandler =====
 
-type UserHandler struct {
-	service service.UserService
+type SessionHandler struct {
+	service service.SessionService
 	logger  *logger.Logger
 }
 
-func NewUserHandler(service service.UserService, logger *logger.Logger) *UserHandler {
-	return &UserHandler{service: service, logger: logger}
+func NewSessionHandler(service service.SessionService, logger *logger.Logger) *SessionHandler {
+	return &SessionHandler{service: service, logger: logger}
+}
+
+// GetMySessions godoc
+// @Summary 自分のログイン中のセッションを取得
+// @Description デバイス・IPアドレス・最終利用日時を返す。current はリクエストに使ったセッション
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.Session
+// @Router /users/me/sessions [get]
+func (h *SessionHandler) GetMy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	h.respondSessions(c, userID)
+}
+
+// GetUserSessions godoc
+// @Summary ユーザーのログイン中のセッションを取得 (管理者用)
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "ユーザーID"
+// @Success 200 {array} model.Session
+// @Router /users/{id}/sessions [get]
+func (h *SessionHandler) GetByUser(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	h.respondSessions(c, userID)
+}
+
+func (h *SessionHandler) respondSessions(c *gin.Context, userID uuid.UUID) {
+	sessions, err := h.service.List(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, sessions)
+}
+
+// RevokeMySession godoc
+// @Summary 自分のセッションを終了
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "セッションID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /users/me/sessions/{id} [delete]
+func (h *SessionHandler) Revoke(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	sessionID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.Revoke(c.Request.Context(), userID, sessionID); err != nil {
+		if errors.Is(err, service.ErrSessionNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "セッションの終了に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions godoc
+// @Summary 使用中以外のセッションをすべて終了
+// @Tags auth
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.SessionRevokeResponse
+// @Router /users/me/sessions/revoke-others [post]
+func (h *SessionHandler) RevokeOthers(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+
+	revoked, err := h.service.RevokeOthers(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "セッションの終了に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, model.SessionRevokeResponse{Revoked: revoked})
+}
+
+// ForceLogout godoc
+// @Summary ユーザーを強制ログアウト (管理者用)
+// @Description 全セッションを終了し、発行済みのアクセストークンも失効させる
+// @Tags auth
+// @Security BearerAuth
+// @Param id path string true "ユーザーID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /users/{id}/force-logout [post]
+func (h *SessionHandler) ForceLogout(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.ForceLogout(c.Request.Context(), userID); err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "強制ログアウトに失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ===== APITokenHandler =====
+
+type APITokenHandler struct {
+	service service.APITokenService
+	logger  *logger.Logger
+}
+
+func NewAPITokenHandler(service service.APITokenService, logger *logger.Logger) *APITokenHandler {
+	return &APITokenHandler{service: service, logger: logger}
+}
+
+// ListAPITokenScopes godoc
+// @Summary APIトークンに設定できるスコープの一覧
+// @Tags api-tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.APITokenScopeDefinition
+// @Router /api-tokens/scopes [get]
+func (h *APITokenHandler) Scopes(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Scopes())
+}
+
+// ListMyAPITokens godoc
+// @Summary 自分の個人用アクセストークンの一覧
+// @Tags api-tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.APIToken
+// @Router /users/me/api-tokens [get]
+func (h *APITokenHandler) ListMy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+
+	tokens, err := h.service.ListPersonal(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateMyAPIToken godoc
+// @Summary 個人用アクセストークンを発行
+// @Description トークンは応答でのみ返し、再表示できない。本人の権限のうちスコープで指定した操作に限り利用できる
+// @Tags api-tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.APITokenRequest true "トークン情報"
+// @Success 201 {object} model.APITokenIssued
+// @Failure 400 {object} model.ErrorResponse
+// @Router /users/me/api-tokens [post]
+func (h *APITokenHandler) CreateMy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	var req model.APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	issued, err := h.service.CreatePersonal(c.Request.Context(), userID, &req)
+	if err != nil {
+		h.respondError(c, err, "発行に失敗しました")
+		return
+	}
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeMyAPIToken godoc
+// @Summary 個人用アクセストークンを失効
+// @Tags api-tokens
+// @Security BearerAuth
+// @Param id path string true "トークンID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /users/me/api-tokens/{id} [delete]
+func (h *APITokenHandler) RevokeMy(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	tokenID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.RevokePersonal(c.Request.Context(), userID, tokenID); err != nil {
+		h.respondError(c, err, "失効に失敗しました")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListServiceAccounts godoc
+// @Summary サービスアカウントの一覧 (管理者用)
+// @Tags api-tokens
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.ServiceAccount
+// @Router /service-accounts [get]
+func (h *APITokenHandler) ListServiceAccounts(c *gin.Context) {
+	accounts, err := h.service.ListServiceAccounts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, accounts)
+}
+
+// CreateServiceAccount godoc
+// @Summary サービスアカウントを作成 (管理者用)
+// @Description 権限は基本ロールで決まる。自分より強いロールは設定できない
+// @Tags api-tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.ServiceAccountRequest true "サービスアカウント情報"
+// @Success 201 {object} model.ServiceAccount
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 403 {object} model.ErrorResponse
+// @Router /service-accounts [post]
+func (h *APITokenHandler) CreateServiceAccount(c *gin.Context) {
+	var req model.ServiceAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	account, err := h.service.CreateServiceAccount(c.Request.Context(), &req)
+	if err != nil {
+		h.respondError(c, err, "作成に失敗しました")
+		return
+	}
+	c.JSON(http.StatusCreated, account)
+}
+
+// UpdateServiceAccount godoc
+// @Summary サービスアカウントを更新 (管理者用)
+// @Description is_active を false にすると発行済みのトークンは利用できなくなる
+// @Tags api-tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "サービスアカウントID"
+// @Param body body model.ServiceAccountUpdateRequest true "更新内容"
+// @Success 200 {object} model.ServiceAccount
+// @Failure 404 {object} model.ErrorResponse
+// @Router /service-accounts/{id} [put]
+func (h *APITokenHandler) UpdateServiceAccount(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	var req model.ServiceAccountUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	account, err := h.service.UpdateServiceAccount(c.Request.Context(), id, &req)
+	if err != nil {
+		h.respondError(c, err, "更新に失敗しました")
+		return
+	}
+	c.JSON(http.StatusOK, account)
+}
+
+// DeleteServiceAccount godoc
+// @Summary サービスアカウントを削除 (管理者用)
+// @Description 発行済みのトークンもすべて失効させる
+// @Tags api-tokens
+// @Security BearerAuth
+// @Param id path string true "サービスアカウントID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /service-accounts/{id} [delete]
+func (h *APITokenHandler) DeleteServiceAccount(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.DeleteServiceAccount(c.Request.Context(), id); err != nil {
+		h.respondError(c, err, "削除に失敗しました")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// ListServiceAccountTokens godoc
+// @Summary サービスアカウントのトークンの一覧 (管理者用)
+// @Tags api-tokens
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "サービスアカウントID"
+// @Success 200 {array} model.APIToken
+// @Failure 404 {object} model.ErrorResponse
+// @Router /service-accounts/{id}/tokens [get]
+func (h *APITokenHandler) ListServiceAccountTokens(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	tokens, err := h.service.ListServiceAccountTokens(c.Request.Context(), id)
+	if err != nil {
+		h.respondError(c, err, "取得に失敗しました")
+		return
+	}
+	c.JSON(http.StatusOK, tokens)
+}
+
+// CreateServiceAccountToken godoc
+// @Summary サービスアカウントのトークンを発行 (管理者用)
+// @Description トークンは応答でのみ返し、再表示できない
+// @Tags api-tokens
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "サービスアカウントID"
+// @Param body body model.APITokenRequest true "トークン情報"
+// @Success 201 {object} model.APITokenIssued
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /service-accounts/{id}/tokens [post]
+func (h *APITokenHandler) CreateServiceAccountToken(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	var req model.APITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	issued, err := h.service.CreateServiceAccountToken(c.Request.Context(), id, &req)
+	if err != nil {
+		h.respondError(c, err, "発行に失敗しました")
+		return
+	}
+	c.JSON(http.StatusCreated, issued)
+}
+
+// RevokeServiceAccountToken godoc
+// @Summary サービスアカウントのトークンを失効 (管理者用)
+// @Tags api-tokens
+// @Security BearerAuth
+// @Param id path string true "サービスアカウントID"
+// @Param tokenId path string true "トークンID"
+// @Success 204
+// @Failure 404 {object} model.ErrorResponse
+// @Router /service-accounts/{id}/tokens/{tokenId} [delete]
+func (h *APITokenHandler) RevokeServiceAccountToken(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	tokenID, err := parseUUID(c, "tokenId")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.RevokeServiceAccountToken(c.Request.Context(), id, tokenID); err != nil {
+		h.respondError(c, err, "失効に失敗しました")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+func (h *APITokenHandler) respondError(c *gin.Context, err error, message string) {
+	if respondValidationError(c, err) {
+		return
+	}
+	switch {
+	case errors.Is(err, service.ErrAPITokenNotFound), errors.Is(err, service.ErrServiceAccountNotFound), errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+	case errors.Is(err, service.ErrForbidden):
+		c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: message})
+	}
+}
+
+// ===== AuditHandler =====
+
+type AuditHandler struct {
+	service service.AuditService
+	logger  *logger.Logger
+}
+
+func NewAuditHandler(service service.AuditService, logger *logger.Logger) *AuditHandler {
+	return &AuditHandler{service: service, logger: logger}
+}
+
+// bindAuditQuery は監査ログの検索条件を読み込む。不正な場合は 400 を返して false を返す
+func bindAuditQuery(c *gin.Context) (*model.AuditLogQuery, bool) {
+	var q model.AuditLogQuery
+	if err := c.ShouldBindQuery(&q); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "検索条件が不正です", Details: err.Error()})
+		return nil, false
+	}
+	q.Page, q.PageSize = parsePagination(c)
+	return &q, true
+}
+
+// ListAuditLogs godoc
+// @Summary 監査ログの検索 (管理者用)
+// @Description データの作成・更新・削除の記録を新しい順に返す。期間は発生日で指定し、終了日を含む
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Param actor_id query string false "操作者ID"
+// @Param action query string false "操作（create / update / delete）"
+// @Param entity_type query string false "対象（テーブル名）"
+// @Param entity_id query string false "対象ID"
+// @Param request_id query string false "リクエストID"
+// @Param start_date query string false "開始日 (YYYY-MM-DD)"
+// @Param end_date query string false "終了日 (YYYY-MM-DD)"
+// @Param page query int false "ページ番号"
+// @Param page_size query int false "ページサイズ"
+// @Success 200 {object} model.PaginatedResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /audit-logs [get]
+func (h *AuditHandler) List(c *gin.Context) {
+	q, ok := bindAuditQuery(c)
+	if !ok {
+		return
+	}
+	logs, total, err := h.service.List(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+	paginatedResponse(c, logs, total, q.Page, q.PageSize)
+}
+
+// ExportAuditLogs godoc
+// @Summary 監査ログのCSVエクスポート (管理者用)
+// @Description 検索と同じ条件で絞り込んだ記録をCSVで返す
+// @Tags audit
+// @Security BearerAuth
+// @Produce text/csv
+// @Param actor_id query string false "操作者ID"
+// @Param action query string false "操作（create / update / delete）"
+// @Param entity_type query string false "対象（テーブル名）"
+// @Param entity_id query string false "対象ID"
+// @Param start_date query string false "開始日 (YYYY-MM-DD)"
+// @Param end_date query string false "終了日 (YYYY-MM-DD)"
+// @Success 200 {file} file
+// @Failure 400 {object} model.ErrorResponse
+// @Router /audit-logs/export [get]
+func (h *AuditHandler) Export(c *gin.Context) {
+	q, ok := bindAuditQuery(c)
+	if !ok {
+		return
+	}
+	data, err := h.service.ExportCSV(c.Request.Context(), q)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "エクスポートに失敗しました"})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=audit_logs.csv")
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
+// VerifyAuditLogs godoc
+// @Summary 監査ログの改ざん検証 (管理者用)
+// @Description ハッシュチェーンをたどり、記録の欠落・改ざんを検出する
+// @Tags audit
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.AuditVerifyResult
+// @Router /audit-logs/verify [get]
+func (h *AuditHandler) Verify(c *gin.Context) {
+	result, err := h.service.Verify(c.Request.Context())
+	if err != nil {
+		h.logger.Error("監査ログの検証に失敗しました", "error", err)
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "検証に失敗しました"})
+		return
+	}
+	if !result.Valid {
+		h.logger.Warn("監査ログの改ざんを検出しました", "seq", *result.BrokenSeq, "reason", result.Reason)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// ===== MFAHandler =====
+
+type MFAHandler struct {
+	service service.MFAService
+	logger  *logger.Logger
+}
+
+func NewMFAHandler(service service.MFAService, logger *logger.Logger) *MFAHandler {
+	return &MFAHandler{service: service, logger: logger}
+}
+
+// GetMFAStatus godoc
+// @Summary 自分の多要素認証の設定状況を取得
+// @Tags mfa
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.MFAStatus
+// @Router /users/me/mfa [get]
+func (h *MFAHandler) Status(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+
+	status, err := h.service.Status(c.Request.Context(), userID)
+	if err != nil {
+		respondMFAError(c, err, "取得に失敗しました")
+		return
+	}
+
+	c.JSON(http.StatusOK, status)
+}
+
+// SetupMFA godoc
+// @Summary 多要素認証の登録を開始
+// @Description 秘密鍵とQRコード用のプロビジョニングURIを返す。有効化するまでログインには使われない
+// @Tags mfa
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.MFASetupResponse
+// @Failure 409 {object} model.ErrorResponse
+// @Router /users/me/mfa/setup [post]
+func (h *MFAHandler) Setup(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+
+	setup, err := h.service.Setup(c.Request.Context(), userID)
+	if err != nil {
+		respondMFAError(c, err, "登録の開始に失敗しました")
+		return
+	}
+
+	c.JSON(http.StatusOK, setup)
+}
+
+// EnableMFA godoc
+// @Summary 多要素認証を有効化
+// @Description 認証アプリのコードで登録を確認し、リカバリーコードを返す（表示は一度きり）
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.MFACodeRequest true "認証コード"
+// @Success 200 {object} model.MFAEnrollmentResponse
+// @Failure 400 {object} model.ErrorResponse
+// @Router /users/me/mfa/enable [post]
+func (h *MFAHandler) Enable(c *gin.Context) {
+	userID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.Enable(c.Request.Context(), userID, code)
+	if err != nil {
+		respondMFAError(c, err, "有効化に失敗しました")
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// RegenerateRecoveryCodes godoc
+// @Summary リカバリーコードを再発行
+// @Description 以前のリカバリーコードはすべて無効になる
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.MFACodeRequest true "認証コード"
+// @Success 200 {object} model.MFAEnrollmentResponse
+// @Router /users/me/mfa/recovery-codes [post]
+func (h *MFAHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	userID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	enrollment, err := h.service.RegenerateRecoveryCodes(c.Request.Context(), userID, code)
+	if err != nil {
+		respondMFAError(c, err, "再発行に失敗しました")
+		return
+	}
+
+	c.JSON(http.StatusOK, enrollment)
+}
+
+// DisableMFA godoc
+// @Summary 多要素認証を無効化
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Param body body model.MFACodeRequest true "認証コードまたはリカバリーコード"
+// @Success 204
+// @Failure 403 {object} model.ErrorResponse
+// @Router /users/me/mfa/disable [post]
+func (h *MFAHandler) Disable(c *gin.Context) {
+	userID, code, ok := h.bindCode(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.Disable(c.Request.Context(), userID, code); err != nil {
+		respondMFAError(c, err, "無効化に失敗しました")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ResetMFA godoc
+// @Summary ユーザーの多要素認証を解除 (管理者用)
+// @Description 端末を紛失したユーザーの登録を解除する。ポリシーで必須の場合は次回ログイン時に再登録となる
+// @Tags mfa
+// @Security BearerAuth
+// @Param id path string true "ユーザーID"
+// @Success 204
+// @Router /users/{id}/mfa [delete]
+func (h *MFAHandler) Reset(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.Reset(c.Request.Context(), userID); err != nil {
+		respondMFAError(c, err, "解除に失敗しました")
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetMFAPolicy godoc
+// @Summary 多要素認証ポリシーを取得
+// @Tags mfa
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.MFAPolicy
+// @Router /mfa-policy [get]
+func (h *MFAHandler) GetPolicy(c *gin.Context) {
+	policy, err := h.service.GetPolicy(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+// UpdateMFAPolicy godoc
+// @Summary 多要素認証ポリシーを更新
+// @Description 指定したロール・カスタムロールのユーザーは多要素認証の登録がログインの条件になる
+// @Tags mfa
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.MFAPolicyRequest true "ポリシー"
+// @Success 200 {object} model.MFAPolicy
+// @Router /mfa-policy [put]
+func (h *MFAHandler) UpdatePolicy(c *gin.Context) {
+	var req model.MFAPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	policy, err := h.service.UpdatePolicy(c.Request.Context(), &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "更新に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, policy)
+}
+
+func (h *MFAHandler) bindCode(c *gin.Context) (uuid.UUID, string, bool) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return uuid.Nil, "", false
+	}
+	var req model.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.Code == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "認証コードを入力してください"})
+		return uuid.Nil, "", false
+	}
+	return userID, req.Code, true
+}
+
+func respondMFAError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, service.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+	case errors.Is(err, service.ErrInvalidMFACode):
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+	case errors.Is(err, service.ErrMFARequired):
+		c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+	case errors.Is(err, service.ErrMFAAlreadyEnabled), errors.Is(err, service.ErrMFANotEnabled):
+		c.JSON(http.StatusConflict, model.ErrorResponse{Code: 409, Message: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: fallback})
+	}
+}
+
+// ===== ShiftHandler =====
+
+type ShiftHandler struct {
+	service service.ShiftService
+	logger  *logger.Logger
+}
+
+func NewShiftHandler(service service.ShiftService, logger *logger.Logger) *ShiftHandler {
+	return &ShiftHandler{service: service, logger: logger}
+}
+
+// CreateShift godoc
+// @Summary シフトを作成
+// @Tags shifts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.ShiftCreateRequest true "シフト情報"
+// @Success 201 {object} model.Shift
+// @Router /shifts [post]
+func (h *ShiftHandler) Create(c *gin.Context) {
+	var req model.ShiftCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	shift, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+// BulkCreateShifts godoc
+// @Summary シフトを一括作成
+// @Tags shifts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.ShiftBulkCreateRequest true "シフト一括情報"
+// @Success 201 {object} map[string]string
+// @Router /shifts/bulk [post]
+func (h *ShiftHandler) BulkCreate(c *gin.Context) {
+	var req model.ShiftBulkCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	if err := h.service.BulkCreate(c.Request.Context(), &req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{"message": "シフトを一括作成しました"})
+}
+
+// GetShifts godoc
+// @Summary シフト一覧を取得
+// @Tags shifts
+// @Security BearerAuth
+// @Produce json
+// @Param start_date query string true "開始日"
+// @Param end_date query string true "終了日"
+// @Success 200 {array} model.Shift
+// @Router /shifts [get]
+func (h *ShiftHandler) GetByDateRange(c *gin.Context) {
+	start, end, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "日付フォーマットが不正です"})
+		return
+	}
+
+	shifts, err := h.service.GetByDateRange(c.Request.Context(), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, shifts)
+}
+
+// UpdateShift godoc
+// @Summary シフトを更新
+// @Tags shifts
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "シフトID"
+// @Param body body model.ShiftUpdateRequest true "更新内容"
+// @Success 200 {object} model.Shift
+// @Router /shifts/{id} [put]
+func (h *ShiftHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req model.ShiftUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	shift, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+// DeleteShift godoc
+// @Summary シフトを削除
+// @Tags shifts
+// @Security BearerAuth
+// @Param id path string true "シフトID"
+// @Success 204
+// @Router /shifts/{id} [delete]
+func (h *ShiftHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ===== ShiftScheduleHandler =====
+
+type ShiftScheduleHandler struct {
+	service service.ShiftScheduleService
+	logger  *logger.Logger
+}
+
+func NewShiftScheduleHandler(service service.ShiftScheduleService, logger *logger.Logger) *ShiftScheduleHandler {
+	return &ShiftScheduleHandler{service: service, logger: logger}
+}
+
+// CreateShiftSchedule godoc
+// @Summary シフト表を作成（下書き）
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.ShiftScheduleCreateRequest true "シフト表情報"
+// @Success 201 {object} model.ShiftSchedule
+// @Router /shift-schedules [post]
+func (h *ShiftScheduleHandler) Create(c *gin.Context) {
+	var req model.ShiftScheduleCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	schedule, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+// GetShiftSchedules godoc
+// @Summary シフト表一覧を取得
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Produce json
+// @Param department_id query string false "部署ID"
+// @Success 200 {array} model.ShiftSchedule
+// @Router /shift-schedules [get]
+func (h *ShiftScheduleHandler) GetAll(c *gin.Context) {
+	var departmentID *uuid.UUID
+	if d := c.Query("department_id"); d != "" {
+		id, err := uuid.Parse(d)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "無効なIDフォーマットです"})
+			return
+		}
+		departmentID = &id
+	}
+
+	schedules, err := h.service.GetAll(c.Request.Context(), departmentID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+// GetShiftSchedule godoc
+// @Summary シフト表を取得
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "シフト表ID"
+// @Success 200 {object} model.ShiftSchedule
+// @Router /shift-schedules/{id} [get]
+func (h *ShiftScheduleHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	schedule, err := h.service.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "シフト表が見つかりません"})
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetShiftScheduleShifts godoc
+// @Summary シフト表に含まれるシフトを取得（下書きを含む）
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "シフト表ID"
+// @Success 200 {array} model.Shift
+// @Router /shift-schedules/{id}/shifts [get]
+func (h *ShiftScheduleHandler) GetShifts(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	shifts, err := h.service.GetShifts(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, shifts)
+}
+
+// PublishShiftSchedule godoc
+// @Summary シフト表を公開
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "シフト表ID"
+// @Param body body model.ShiftSchedulePublishRequest false "公開メモ"
+// @Success 200 {object} model.ShiftSchedule
+// @Router /shift-schedules/{id}/publish [post]
+func (h *ShiftScheduleHandler) Publish(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	// 公開メモは任意のため、本文が空の場合に限り省略を認める
+	var req model.ShiftSchedulePublishRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+			c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+			return
+		}
+	}
+
+	schedule, err := h.service.Publish(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShiftScheduleNotFound):
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		case errors.Is(err, service.ErrShiftSchedulePublished):
+			c.JSON(http.StatusConflict, model.ErrorResponse{Code: 409, Message: err.Error()})
+		default:
+			h.logger.Error("シフト表の公開に失敗しました", "error", err)
+			c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "シフト表の公開に失敗しました"})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, schedule)
+}
+
+// GetShiftScheduleVersions godoc
+// @Summary シフト表の公開履歴を取得
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "シフト表ID"
+// @Success 200 {array} model.ShiftScheduleVersion
+// @Router /shift-schedules/{id}/versions [get]
+func (h *ShiftScheduleHandler) GetVersions(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	versions, err := h.service.GetVersions(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetShiftScheduleDiff godoc
+// @Summary シフト表のバージョン間の差分を取得
+// @Tags shift-schedules
+// @Security BearerAuth
+// @Produce json
+// @Param id path string true "シフト表ID"
+// @Param from query int true "比較元バージョン"
+// @Param to query int true "比較先バージョン"
+// @Success 200 {object} model.ShiftScheduleDiff
+// @Router /shift-schedules/{id}/diff [get]
+func (h *ShiftScheduleHandler) GetDiff(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "バージョンの指定が不正です"})
+		return
+	}
+
+	diff, err := h.service.GetDiff(c.Request.Context(), id, from, to)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, diff)
+}
+
+// ===== UserHandler =====
+
+type UserHandler struct {
+	service service.UserService
+	logger  *logger.Logger
+}
+
+func NewUserHandler(service service.UserService, logger *logger.Logger) *UserHandler {
+	return &UserHandler{service: service, logger: logger}
+}
+
+// GetMe godoc
+// @Summary 自分のプロフィールを取得
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.User
+// @Router /users/me [get]
+func (h *UserHandler) GetMe(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+
+	user, err := h.service.GetByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "ユーザーが見つかりません"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// GetAllUsers godoc
+// @Summary 全ユーザー一覧を取得 (管理者用)
+// @Tags users
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} model.PaginatedResponse
+// @Router /users [get]
+func (h *UserHandler) GetAll(c *gin.Context) {
+	page, pageSize := parsePagination(c)
+	users, total, err := h.service.GetAll(c.Request.Context(), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	paginatedResponse(c, users, total, page, pageSize)
+}
+
+// UpdateUser godoc
+// @Summary ユーザー情報を更新 (管理者用)
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ユーザーID"
+// @Param body body model.UserUpdateRequest true "更新情報"
+// @Success 200 {object} model.User
+// @Router /users/{id} [put]
+func (h *UserHandler) Update(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	var req model.UserUpdateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+
+	user, err := h.service.Update(c.Request.Context(), id, &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// CreateUser godoc
+// @Summary ユーザーを作成 (管理者用)
+// @Tags users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param body body model.UserCreateRequest true "ユーザー情報"
+// @Success 201 {object} model.User
+// @Router /users [post]
+func (h *UserHandler) Create(c *gin.Context) {
+	var req model.UserCreateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	user, err := h.service.Create(c.Request.Context(), &req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+// DeleteUser godoc
+// @Summary ユーザーを削除 (管理者用)
+// @Tags users
+// @Security BearerAuth
+// @Param id path string true "ユーザーID"
+// @Success 204
+// @Router /users/{id} [delete]
+func (h *UserHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// ===== DepartmentHandler =====
+
+type DepartmentHandler struct {
+	service service.DepartmentService
+	logger  *logger.Logger
+}
+
+func NewDepartmentHandler(service service.DepartmentService, logger *logger.Logger) *DepartmentHandler {
+	return &DepartmentHandler{service: service, logger: logger}
+}
+
+// GetAllDepartments godoc
+// @Summary 部署一覧を取得
+// @Tags departments
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.Department
+// @Router /departments [get]
+func (h *DepartmentHandler) GetAll(c *gin.Context) {
+	departments, err := h.service.GetAll(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, departments)
+}
+
+// ===== RoleHandler =====
+
+type RoleHandler struct {
+	service service.RoleService
+	logger  *logger.Logger
+}
+
+func NewRoleHandler(service service.RoleService, logger *logger.Logger) *RoleHandler {
+	return &RoleHandler{service: service, logger: logger}
+}
+
+// ListPermissions godoc
+// @Summary 付与可能な権限の一覧を取得
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.PermissionDefinition
+// @Router /permissions [get]
+func (h *RoleHandler) ListPermissions(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.ListPermissions(c.Request.Context()))
+}
+
+// ListRoles godoc
+// @Summary カスタムロール一覧を取得
+// @Tags roles
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} model.CustomRole
+// @Router /roles [get]
+func (h *RoleHandler) List(c *gin.Context) {
+	roles, err := h.service.List(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusOK, roles)
 }
 
-// GetMe godoc
-// @Summary 自分のプロフィールを取得
-// @Tags users
+// GetRole godoc
+// @Summary カスタムロールを取得
+// @Tags roles
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {object} model.User
-// @Router /users/me [get]
-func (h *UserHandler) GetMe(c *gin.Context) {
-	userID, err := getUserIDFromContext(c)
+// @Param id path string true "ロールID"
+// @Success 200 {object} model.CustomRole
+// @Router /roles/{id} [get]
+func (h *RoleHandler) GetByID(c *gin.Context) {
+	id, err := parseUUID(c, "id")
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
 		return
 	}
 
-	user, err := h.service.GetByID(c.Request.Context(), userID)
+	role, err := h.service.GetByID(c.Request.Context(), id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: "ユーザーが見つかりません"})
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, role)
 }
 
-// GetAllUsers godoc
-// @Summary 全ユーザー一覧を取得 (管理者用)
-// @Tags users
+// CreateRole godoc
+// @Summary カスタムロールを作成
+// @Tags roles
 // @Security BearerAuth
+// @Accept json
 // @Produce json
-// @Success 200 {object} model.PaginatedResponse
-// @Router /users [get]
-func (h *UserHandler) GetAll(c *gin.Context) {
-	page, pageSize := parsePagination(c)
-	users, total, err := h.service.GetAll(c.Request.Context(), page, pageSize)
+// @Param body body model.CustomRoleRequest true "ロール情報"
+// @Success 201 {object} model.CustomRole
+// @Router /roles [post]
+func (h *RoleHandler) Create(c *gin.Context) {
+	var req model.CustomRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+
+	role, err := h.service.Create(c.Request.Context(), &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "作成に失敗しました"})
 		return
 	}
 
-	paginatedResponse(c, users, total, page, pageSize)
+	c.JSON(http.StatusCreated, role)
 }
 
-// UpdateUser godoc
-// @Summary ユーザー情報を更新 (管理者用)
-// @Tags users
+// UpdateRole godoc
+// @Summary カスタムロールを更新
+// @Tags roles
 // @Security BearerAuth
 // @Accept json
 // @Produce json
-// @Param id path string true "ユーザーID"
-// @Param body body model.UserUpdateRequest true "更新情報"
-// @Success 200 {object} model.User
-// @Router /users/{id} [put]
-func (h *UserHandler) Update(c *gin.Context) {
+// @Param id path string true "ロールID"
+// @Param body body model.CustomRoleRequest true "ロール情報"
+// @Success 200 {object} model.CustomRole
+// @Router /roles/{id} [put]
+func (h *RoleHandler) Update(c *gin.Context) {
 	id, err := parseUUID(c, "id")
 	if err != nil {
 		return
 	}
 
-	var req model.UserUpdateRequest
+	var req model.CustomRoleRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
 		return
 	}
 
-	user, err := h.service.Update(c.Request.Context(), id, &req)
+	role, err := h.service.Update(c.Request.Context(), id, &req)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrRoleNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "更新に失敗しました"})
 		return
 	}
 
-	c.JSON(http.StatusOK, user)
+	c.JSON(http.StatusOK, role)
 }
 
-// CreateUser godoc
-// @Summary ユーザーを作成 (管理者用)
-// @Tags users
+// DeleteRole godoc
+// @Summary カスタムロールを削除
+// @Tags roles
 // @Security BearerAuth
-// @Accept json
-// @Produce json
-// @Param body body model.UserCreateRequest true "ユーザー情報"
-// @Success 201 {object} model.User
-// @Router /users [post]
-func (h *UserHandler) Create(c *gin.Context) {
-	var req model.UserCreateRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+// @Param id path string true "ロールID"
+// @Success 204
+// @Router /roles/{id} [delete]
+func (h *RoleHandler) Delete(c *gin.Context) {
+	id, err := parseUUID(c, "id")
+	if err != nil {
 		return
 	}
 
-	user, err := h.service.Create(c.Request.Context(), &req)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
+	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		switch {
+		case errors.Is(err, service.ErrRoleNotFound):
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		case errors.Is(err, service.ErrRoleInUse):
+			c.JSON(http.StatusConflict, model.ErrorResponse{Code: 409, Message: err.Error()})
+		default:
+			c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
+		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, user)
+	c.Status(http.StatusNoContent)
 }
 
-// DeleteUser godoc
-// @Summary ユーザーを削除 (管理者用)
-// @Tags users
+// ListUserRoles godoc
+// @Summary ユーザーのロール割り当て一覧を取得
+// @Tags roles
 // @Security BearerAuth
+// @Produce json
 // @Param id path string true "ユーザーID"
-// @Success 204
-// @Router /users/{id} [delete]
-func (h *UserHandler) Delete(c *gin.Context) {
-	id, err := parseUUID(c, "id")
+// @Success 200 {array} model.UserRoleAssignment
+// @Router /users/{id}/roles [get]
+func (h *RoleHandler) ListAssignments(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
 	if err != nil {
 		return
 	}
 
-	if err := h.service.Delete(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
+	assignments, err := h.service.ListAssignments(c.Request.Context(), userID)
+	if err != nil {
+		if errors.Is(err, service.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, assignments)
 }
 
-// ===== DepartmentHandler =====
+// AssignRole godoc
+// @Summary ユーザーにカスタムロールを割り当て
+// @Tags roles
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path string true "ユーザーID"
+// @Param body body model.RoleAssignmentRequest true "割り当て情報"
+// @Success 201 {object} model.UserRoleAssignment
+// @Router /users/{id}/roles [post]
+func (h *RoleHandler) Assign(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
 
-type DepartmentHandler struct {
-	service service.DepartmentService
-	logger  *logger.Logger
-}
+	var req model.RoleAssignmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
 
-func NewDepartmentHandler(service service.DepartmentService, logger *logger.Logger) *DepartmentHandler {
-	return &DepartmentHandler{service: service, logger: logger}
+	assignment, err := h.service.Assign(c.Request.Context(), userID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+			return
+		}
+		if errors.Is(err, service.ErrUserNotFound) || errors.Is(err, service.ErrRoleNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "割り当てに失敗しました"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, assignment)
 }
 
-// GetAllDepartments godoc
-// @Summary 部署一覧を取得
-// @Tags departments
+// UnassignRole godoc
+// @Summary ユーザーのロール割り当てを解除
+// @Tags roles
 // @Security BearerAuth
-// @Produce json
-// @Success 200 {array} model.Department
-// @Router /departments [get]
-func (h *DepartmentHandler) GetAll(c *gin.Context) {
-	departments, err := h.service.GetAll(c.Request.Context())
+// @Param id path string true "ユーザーID"
+// @Param assignmentId path string true "割り当てID"
+// @Success 204
+// @Router /users/{id}/roles/{assignmentId} [delete]
+func (h *RoleHandler) Unassign(c *gin.Context) {
+	userID, err := parseUUID(c, "id")
+	if err != nil {
+		return
+	}
+	assignmentID, err := parseUUID(c, "assignmentId")
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
 		return
 	}
 
-	c.JSON(http.StatusOK, departments)
+	if err := h.service.Unassign(c.Request.Context(), userID, assignmentID); err != nil {
+		if errors.Is(err, service.ErrRoleAssignmentNotFound) {
+			c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "解除に失敗しました"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
 }
 
 // ===== DashboardHandler =====
@@ -691,6 +2525,55 @@ func (h *NotificationHandler) GetUnreadCount(c *gin.Context) {
 	c.JSON(http.StatusOK, model.NotificationCount{Unread: int(count)})
 }
 
+// GetPreferences godoc
+// @Summary 通知の受信設定を取得
+// @Tags notifications
+// @Security BearerAuth
+// @Success 200 {object} model.NotificationPreferencesResponse
+// @Router /notifications/preferences [get]
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "取得に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences godoc
+// @Summary 通知の受信設定（種別×チャネル、おやすみ時間帯）を更新
+// @Tags notifications
+// @Security BearerAuth
+// @Param body body model.NotificationPreferencesRequest true "受信設定"
+// @Success 200 {object} model.NotificationPreferencesResponse
+// @Router /notifications/preferences [put]
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	var req model.NotificationPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です", Details: err.Error()})
+		return
+	}
+	prefs, err := h.service.UpdatePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		if respondValidationError(c, err) {
+			return
+		}
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "更新に失敗しました"})
+		return
+	}
+	c.JSON(http.StatusOK, prefs)
+}
+
 func (h *NotificationHandler) MarkAsRead(c *gin.Context) {
 	id, err := parseUUID(c, "id")
 	if err != nil {
@@ -728,6 +2611,190 @@ func (h *NotificationHandler) Delete(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+// ===== EventStreamHandler =====
+
+// eventStreamRetryMillis は切断後にクライアントが再接続するまでの待ち時間
+const eventStreamRetryMillis = 3000
+
+// EventStreamHandler は通知・承認状況の変更・お知らせを Server-Sent Events で配信する
+type EventStreamHandler struct {
+	hub    *realtime.Hub
+	logger *logger.Logger
+}
+
+func NewEventStreamHandler(hub *realtime.Hub, logger *logger.Logger) *EventStreamHandler {
+	return &EventStreamHandler{hub: hub, logger: logger}
+}
+
+// Stream godoc
+// @Summary リアルタイムイベントを購読（Server-Sent Events）
+// @Description 再接続時は Last-Event-ID ヘッダー（または last_event_id クエリ）以降の未受信イベントから再開する。
+// @Description 再開位置が残っていない場合は resync イベントを送るため、クライアントは一覧を取得し直す
+// @Tags events
+// @Security BearerAuth
+// @Produce text/event-stream
+// @Param Last-Event-ID header string false "最後に受信したイベントID"
+// @Success 200 {string} string "notification / approval / announcement / resync イベント"
+// @Router /events [get]
+func (h *EventStreamHandler) Stream(c *gin.Context) {
+	userID, err := getUserIDFromContext(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: "認証が必要です"})
+		return
+	}
+	if h.hub == nil {
+		c.JSON(http.StatusServiceUnavailable, model.ErrorResponse{Code: 503, Message: "リアルタイム配信は利用できません"})
+		return
+	}
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+
+	sub, backlog, resync := h.hub.Subscribe(userID, lastEventID)
+	defer sub.Close()
+
+	// 長時間の接続をサーバーの WriteTimeout で切らないようにする
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", eventStreamRetryMillis)
+	if resync {
+		_ = writeServerSentEvent(c.Writer, realtime.Event{Type: realtime.EventResync, Data: json.RawMessage("{}")})
+	}
+	for _, ev := range backlog {
+		_ = writeServerSentEvent(c.Writer, ev)
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.hub.Heartbeat())
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case ev, ok := <-sub.Events():
+			if !ok {
+				// 送信が追いつかず購読が打ち切られた。クライアントは Last-Event-ID で再開する
+				return
+			}
+			if err := writeServerSentEvent(c.Writer, ev); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeServerSentEvent は1件のイベントを SSE の形式で書き込む。データは改行を含まない JSON
+func writeServerSentEvent(w io.Writer, ev realtime.Event) error {
+	if ev.ID != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", ev.ID); err != nil {
+			return err
+		}
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", ev.Type, ev.Data)
+	return err
+}
+
+// ===== ChatActionHandler =====
+
+// chatActionBodyLimit はチャットのボタン押下のリクエスト本文の上限
+const chatActionBodyLimit = 64 << 10
+
+// ChatActionHandler は Slack / Teams の承認・却下ボタンの押下を受け付ける。
+// 認証はリクエストの署名（Teams は Authorization ヘッダーのトークン）とボタンに埋め込んだトークンで行う
+type ChatActionHandler struct {
+	svc    service.ChatActionService
+	logger *logger.Logger
+}
+
+func NewChatActionHandler(svc service.ChatActionService, logger *logger.Logger) *ChatActionHandler {
+	return &ChatActionHandler{svc: svc, logger: logger}
+}
+
+// SlackAction godoc
+// @Summary Slack の承認・却下ボタンの押下を処理
+// @Description Slack アプリの Interactivity の Request URL に設定する。X-Slack-Signature で送信元を検証し、
+// @Description 結果は response_url を通じて元のメッセージに表示する
+// @Tags integrations
+// @Accept x-www-form-urlencoded
+// @Param X-Slack-Request-Timestamp header string true "リクエストの時刻"
+// @Param X-Slack-Signature header string true "リクエストの署名"
+// @Success 200
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /integrations/slack/actions [post]
+func (h *ChatActionHandler) SlackAction(c *gin.Context) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, chatActionBodyLimit))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+	_, err = h.svc.HandleSlackAction(c.Request.Context(),
+		c.GetHeader("X-Slack-Request-Timestamp"), c.GetHeader("X-Slack-Signature"), body)
+	switch {
+	case errors.Is(err, service.ErrChatActionsDisabled):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+		return
+	}
+	// Slack は3秒以内の 200 を期待する。処理結果は response_url に送信済み
+	c.Status(http.StatusOK)
+}
+
+// TeamsAction godoc
+// @Summary Teams の承認・却下ボタンの押下を処理
+// @Description MessageCard の HttpPOST アクションの送信先。Authorization ヘッダーのトークンで押した人を、
+// @Description 本文のトークンで申請と承認者を検証し、結果を CARD-ACTION-STATUS ヘッダーでカードに表示する
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Teams が付ける Bearer トークン"
+// @Param body body object true "{\"token\": \"...\"}"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} model.ErrorResponse
+// @Failure 401 {object} model.ErrorResponse
+// @Failure 404 {object} model.ErrorResponse
+// @Router /integrations/teams/actions [post]
+func (h *ChatActionHandler) TeamsAction(c *gin.Context) {
+	var req struct {
+		Token string `json:"token"`
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, chatActionBodyLimit)
+	if err := c.ShouldBindJSON(&req); err != nil || req.Token == "" {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "リクエストが不正です"})
+		return
+	}
+	msg, err := h.svc.HandleTeamsAction(c.Request.Context(), c.GetHeader("Authorization"), req.Token)
+	switch {
+	case errors.Is(err, service.ErrChatActionsDisabled):
+		c.JSON(http.StatusNotFound, model.ErrorResponse{Code: 404, Message: err.Error()})
+		return
+	case errors.Is(err, service.ErrInvalidChatAction):
+		c.JSON(http.StatusUnauthorized, model.ErrorResponse{Code: 401, Message: err.Error()})
+		return
+	case err != nil:
+		// 処理済み・権限なしなども、押した人に分かるようカードに表示する
+		h.logger.Warn("Teams からの承認操作を処理できませんでした", "error", err)
+		msg = service.ChatActionFailureMessage(err)
+	}
+	c.Header("CARD-ACTION-STATUS", msg)
+	c.JSON(http.StatusOK, gin.H{"message": msg})
+}
+
 // ===== ProjectHandler =====
 
 type ProjectHandler struct {
@@ -889,6 +2956,10 @@ func (h *TimeEntryHandler) Update(c *gin.Context) {
 		return
 	}
 	entry, err := h.service.Update(c.Request.Context(), id, &req)
+	if errors.Is(err, service.ErrForbidden) {
+		c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: err.Error()})
 		return
@@ -902,6 +2973,10 @@ func (h *TimeEntryHandler) Delete(c *gin.Context) {
 		return
 	}
 	if err := h.service.Delete(c.Request.Context(), id); err != nil {
+		if errors.Is(err, service.ErrForbidden) {
+			c.JSON(http.StatusForbidden, model.ErrorResponse{Code: 403, Message: err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "削除に失敗しました"})
 		return
 	}
@@ -1159,6 +3234,21 @@ func (h *ExportHandler) ExportOvertime(c *gin.Context) {
 	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
 }
 
+func (h *ExportHandler) ExportWorkModes(c *gin.Context) {
+	start, end, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, model.ErrorResponse{Code: 400, Message: "日付フォーマットが不正です"})
+		return
+	}
+	data, err := h.service.ExportWorkModeCSV(c.Request.Context(), start, end)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, model.ErrorResponse{Code: 500, Message: "エクスポートに失敗しました"})
+		return
+	}
+	c.Header("Content-Disposition", "attachment; filename=work_modes.csv")
+	c.Data(http.StatusOK, "text/csv; charset=utf-8", data)
+}
+
 func (h *ExportHandler) ExportProjects(c *gin.Context) {
 	start, end, err := parseDateRange(c)
 	if err != nil {
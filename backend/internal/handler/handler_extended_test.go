@@ -14,6 +14,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/service"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
 
@@ -610,6 +611,40 @@ func TestAttendanceCorrectionHandler_Create_Success(t *testing.T) {
 	}
 }
 
+func TestAttendanceCorrectionHandler_Create_FieldErrors(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockAttendanceCorrectionService{
+		CreateFunc: func(ctx context.Context, uid uuid.UUID, req *model.AttendanceCorrectionCreate) (*model.AttendanceCorrection, error) {
+			verr := &model.ValidationError{}
+			verr.Add("corrected_clock_in", "時刻の形式が不正です")
+			return nil, verr
+		},
+	}
+	handler := NewAttendanceCorrectionHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/corrections", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.Create(c)
+	})
+
+	body := `{"date":"2026-02-10","corrected_clock_in":"9時","reason":"打刻忘れ"}`
+	req, _ := http.NewRequest(http.MethodPost, "/corrections", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "corrected_clock_in" {
+		t.Errorf("Expected corrected_clock_in field error, got %+v", resp.Errors)
+	}
+}
+
 func TestAttendanceCorrectionHandler_Create_Unauthorized(t *testing.T) {
 	mockService := &mocks.MockAttendanceCorrectionService{}
 	handler := NewAttendanceCorrectionHandler(mockService, getTestLogger())
@@ -863,6 +898,358 @@ func TestAttendanceCorrectionHandler_GetPending_ServiceError(t *testing.T) {
 	}
 }
 
+// ===================================================================
+// SubstituteHolidayHandler Tests
+// ===================================================================
+
+func TestSubstituteHolidayHandler_Create_Success(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockSubstituteHolidayService{
+		CreateFunc: func(ctx context.Context, uid uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error) {
+			return &model.SubstituteHolidayRequest{
+				BaseModel: model.BaseModel{ID: uuid.New()}, UserID: uid, Type: req.Type,
+				Status: model.ApprovalStatusPending,
+			}, nil
+		},
+	}
+	handler := NewSubstituteHolidayHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/substitute-holidays", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.Create(c)
+	})
+
+	body := `{"type":"furikae","work_date":"2026-03-07","holiday_date":"2026-03-09","reason":"イベント対応"}`
+	req, _ := http.NewRequest(http.MethodPost, "/substitute-holidays", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("Expected status %d, got %d", http.StatusCreated, w.Code)
+	}
+}
+
+func TestSubstituteHolidayHandler_Create_FieldErrors(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockSubstituteHolidayService{
+		CreateFunc: func(ctx context.Context, uid uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error) {
+			verr := &model.ValidationError{}
+			verr.Add("holiday_date", "取得可能な代休がありません")
+			return nil, verr
+		},
+	}
+	handler := NewSubstituteHolidayHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/substitute-holidays", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.Create(c)
+	})
+
+	body := `{"type":"daikyu","holiday_date":"2026-03-09","reason":"代休"}`
+	req, _ := http.NewRequest(http.MethodPost, "/substitute-holidays", bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp model.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "holiday_date" {
+		t.Errorf("Expected holiday_date field error, got %+v", resp.Errors)
+	}
+}
+
+func TestSubstituteHolidayHandler_Approve_Success(t *testing.T) {
+	mockService := &mocks.MockSubstituteHolidayService{
+		ApproveFunc: func(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.SubstituteHolidayApproval) (*model.SubstituteHolidayRequest, error) {
+			return &model.SubstituteHolidayRequest{BaseModel: model.BaseModel{ID: id}, Status: req.Status}, nil
+		},
+	}
+	handler := NewSubstituteHolidayHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.PUT("/substitute-holidays/:id/approve", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Approve(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/substitute-holidays/"+uuid.New().String()+"/approve", bytes.NewBufferString(`{"status":"approved"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+}
+
+func TestSubstituteHolidayHandler_GetMyCompensatoryDays(t *testing.T) {
+	mockService := &mocks.MockSubstituteHolidayService{
+		GetCompensatoryDaysFunc: func(ctx context.Context, userID uuid.UUID) (*model.CompensatoryDaySummary, error) {
+			return &model.CompensatoryDaySummary{Available: 2}, nil
+		},
+	}
+	handler := NewSubstituteHolidayHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/substitute-holidays/compensatory-days", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.GetMyCompensatoryDays(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/substitute-holidays/compensatory-days", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.CompensatoryDaySummary
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.Available != 2 {
+		t.Errorf("Expected 2 available, got %d", resp.Available)
+	}
+}
+
+// ===================================================================
+// WorkingHourPolicyHandler Tests
+// ===================================================================
+
+func TestWorkingHourPolicyHandler_Save_FieldErrors(t *testing.T) {
+	mockService := &mocks.MockWorkingHourPolicyService{
+		SaveFunc: func(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourPolicyRequest) (*model.WorkingHourPolicy, error) {
+			verr := &model.ValidationError{}
+			verr.Add("period_months", "フレックスタイム制の清算期間は1〜3ヶ月で指定してください")
+			return nil, verr
+		},
+	}
+	handler := NewWorkingHourPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.PUT("/working-hour-policies/:department_id", handler.Save)
+
+	body := `{"system":"flextime","period_start":"2026-04-01","period_months":6}`
+	req, _ := http.NewRequest(http.MethodPut, "/working-hour-policies/"+uuid.New().String(), bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp model.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "period_months" {
+		t.Errorf("Expected period_months field error, got %+v", resp.Errors)
+	}
+}
+
+func TestWorkingHourPolicyHandler_GetSettlement(t *testing.T) {
+	deptID := uuid.New()
+	mockService := &mocks.MockWorkingHourPolicyService{
+		GetSettlementFunc: func(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error) {
+			if date.Format("2006-01-02") != "2026-03-15" {
+				t.Errorf("Expected date 2026-03-15, got %s", date.Format("2006-01-02"))
+			}
+			return &model.WorkingHourSettlementReport{DepartmentID: departmentID, PeriodStart: "2026-03-01", PeriodEnd: "2026-03-31"}, nil
+		},
+	}
+	handler := NewWorkingHourPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/working-hour-policies/:department_id/settlement", handler.GetSettlement)
+
+	req, _ := http.NewRequest(http.MethodGet, "/working-hour-policies/"+deptID.String()+"/settlement?date=2026-03-15", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.WorkingHourSettlementReport
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.DepartmentID != deptID || resp.PeriodEnd != "2026-03-31" {
+		t.Errorf("Unexpected report: %+v", resp)
+	}
+}
+
+func TestWorkingHourPolicyHandler_GetSettlement_NotConfigured(t *testing.T) {
+	mockService := &mocks.MockWorkingHourPolicyService{
+		GetSettlementFunc: func(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error) {
+			return nil, service.ErrWorkingHourPolicyNotFound
+		},
+	}
+	handler := NewWorkingHourPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/working-hour-policies/:department_id/settlement", handler.GetSettlement)
+
+	req, _ := http.NewRequest(http.MethodGet, "/working-hour-policies/"+uuid.New().String()+"/settlement", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestWorkingHourPolicyHandler_GetSettlement_Forbidden(t *testing.T) {
+	mockService := &mocks.MockWorkingHourPolicyService{
+		GetSettlementFunc: func(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error) {
+			return nil, service.ErrForbidden
+		},
+	}
+	handler := NewWorkingHourPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/working-hour-policies/:department_id/settlement", handler.GetSettlement)
+
+	req, _ := http.NewRequest(http.MethodGet, "/working-hour-policies/"+uuid.New().String()+"/settlement", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+// ===================================================================
+// RemoteWorkPolicyHandler Tests
+// ===================================================================
+
+func TestAttendanceHandler_ClockIn_RemoteLimitExceeded(t *testing.T) {
+	mockService := &mocks.MockAttendanceService{
+		ClockInFunc: func(ctx context.Context, userID uuid.UUID, req *model.ClockInRequest) (*model.Attendance, error) {
+			if req.WorkMode != model.WorkModeRemote {
+				t.Errorf("Expected remote work mode, got %s", req.WorkMode)
+			}
+			verr := &model.ValidationError{}
+			verr.Add("work_mode", "今週の在宅勤務は上限（2日）に達しています")
+			return nil, verr
+		},
+	}
+	handler := NewAttendanceHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/attendance/clock-in", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.ClockIn(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/attendance/clock-in", bytes.NewBufferString(`{"work_mode":"remote"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp model.ErrorResponse
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "work_mode" {
+		t.Errorf("Expected work_mode field error, got %+v", resp.Errors)
+	}
+}
+
+func TestRemoteWorkPolicyHandler_Save(t *testing.T) {
+	deptID := uuid.New()
+	mockService := &mocks.MockRemoteWorkPolicyService{
+		SaveFunc: func(ctx context.Context, departmentID uuid.UUID, req *model.RemoteWorkPolicyRequest) (*model.RemoteWorkPolicy, error) {
+			return &model.RemoteWorkPolicy{DepartmentID: departmentID, MaxRemoteDaysPerWeek: *req.MaxRemoteDaysPerWeek}, nil
+		},
+	}
+	handler := NewRemoteWorkPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.PUT("/remote-work-policies/:department_id", handler.Save)
+
+	req, _ := http.NewRequest(http.MethodPut, "/remote-work-policies/"+deptID.String(), bytes.NewBufferString(`{"max_remote_days_per_week":2}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.RemoteWorkPolicy
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.DepartmentID != deptID || resp.MaxRemoteDaysPerWeek != 2 {
+		t.Errorf("Unexpected policy: %+v", resp)
+	}
+}
+
+func TestRemoteWorkPolicyHandler_GetByDepartment_NotFound(t *testing.T) {
+	mockService := &mocks.MockRemoteWorkPolicyService{
+		GetByDepartmentFunc: func(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error) {
+			return nil, service.ErrRemoteWorkPolicyNotFound
+		},
+	}
+	handler := NewRemoteWorkPolicyHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/remote-work-policies/:department_id", handler.GetByDepartment)
+
+	req, _ := http.NewRequest(http.MethodGet, "/remote-work-policies/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// ===================================================================
+// TeamHandler Tests
+// ===================================================================
+
+func TestTeamHandler_GetToday(t *testing.T) {
+	managerID := uuid.New()
+	mockService := &mocks.MockTeamService{
+		GetTodayStatusFunc: func(ctx context.Context, id uuid.UUID) ([]model.TeamMemberStatus, error) {
+			if id != managerID {
+				t.Errorf("Expected manager %s, got %s", managerID, id)
+			}
+			return []model.TeamMemberStatus{{User: model.User{LastName: "Suzuki"}}}, nil
+		},
+	}
+	handler := NewTeamHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/team/today", func(c *gin.Context) {
+		c.Set("userID", managerID.String())
+		handler.GetToday(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/team/today", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp []model.TeamMemberStatus
+	_ = json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp) != 1 || resp[0].Attendance != nil {
+		t.Errorf("Unexpected team status: %+v", resp)
+	}
+}
+
+func TestLeaveHandler_Approve_NotDirectReport(t *testing.T) {
+	mockService := &mocks.MockLeaveService{
+		ApproveFunc: func(ctx context.Context, leaveID uuid.UUID, approverID uuid.UUID, req *model.LeaveRequestApproval) (*model.LeaveRequest, error) {
+			return nil, service.ErrNotDirectReport
+		},
+	}
+	handler := NewLeaveHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.PUT("/leaves/:id/approve", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Approve(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPut, "/leaves/"+uuid.New().String()+"/approve", bytes.NewBufferString(`{"status":"approved"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
 // ===================================================================
 // NotificationHandler Tests
 // ===================================================================
@@ -2676,3 +3063,77 @@ func TestExportHandler_ExportProjects_ServiceError(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusInternalServerError, w.Code)
 	}
 }
+
+func TestNotificationHandler_GetPreferences_Success(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockNotificationService{
+		GetPreferencesFunc: func(ctx context.Context, uid uuid.UUID) (*model.NotificationPreferencesResponse, error) {
+			return &model.NotificationPreferencesResponse{
+				Channels:   model.NotificationChannels,
+				QuietHours: model.NotificationQuietHours{Start: "22:00", End: "07:00", TimeZone: "Asia/Tokyo"},
+			}, nil
+		},
+	}
+	handler := NewNotificationHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/notifications/preferences", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.GetPreferences(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/notifications/preferences", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.NotificationPreferencesResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if resp.QuietHours.Start != "22:00" || len(resp.Channels) != len(model.NotificationChannels) {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestNotificationHandler_UpdatePreferences(t *testing.T) {
+	userID := uuid.New()
+	var got *model.NotificationPreferencesRequest
+	mockService := &mocks.MockNotificationService{
+		UpdatePreferencesFunc: func(ctx context.Context, uid uuid.UUID, req *model.NotificationPreferencesRequest) (*model.NotificationPreferencesResponse, error) {
+			got = req
+			if req.QuietHours != nil && req.QuietHours.Start == "25:00" {
+				verr := &model.ValidationError{}
+				verr.Add("quiet_hours.start", "HH:MM 形式で指定してください")
+				return nil, verr
+			}
+			return &model.NotificationPreferencesResponse{}, nil
+		},
+	}
+	handler := NewNotificationHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.PUT("/notifications/preferences", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.UpdatePreferences(c)
+	})
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"preferences":[{"type":"shift_changed","channel":"email","enabled":false}]}`, http.StatusOK},
+		{`{"quiet_hours":{"enabled":true,"start":"25:00","end":"07:00"}}`, http.StatusBadRequest},
+		{`{"preferences":`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(http.MethodPut, "/notifications/preferences", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.body, tc.want, w.Code)
+		}
+	}
+	if got == nil || got.QuietHours == nil {
+		t.Fatal("Expected the request to reach the service")
+	}
+}
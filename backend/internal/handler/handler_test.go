@@ -12,6 +12,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/service"
@@ -110,6 +111,32 @@ func TestAuthHandler_Login_InvalidCredentials(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_Login_Throttled(t *testing.T) {
+	cases := map[error]int{
+		service.ErrAccountLocked:        http.StatusLocked,
+		service.ErrTooManyLoginAttempts: http.StatusTooManyRequests,
+	}
+	for serviceErr, want := range cases {
+		mockService := &mocks.MockAuthService{
+			LoginFunc: func(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error) {
+				return nil, serviceErr
+			},
+		}
+		handler := NewAuthHandler(mockService, getTestLogger())
+		router := setupRouter()
+		router.POST("/auth/login", handler.Login)
+
+		req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(`{"email":"test@example.com","password":"password123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%v: expected status %d, got %d", serviceErr, want, w.Code)
+		}
+	}
+}
+
 func TestAuthHandler_Login_BadRequest(t *testing.T) {
 	mockService := &mocks.MockAuthService{}
 	handler := NewAuthHandler(mockService, getTestLogger())
@@ -326,6 +353,109 @@ func TestAuthHandler_Logout_Error(t *testing.T) {
 	}
 }
 
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	var requested string
+	mockService := &mocks.MockAuthService{
+		RequestPasswordResetFunc: func(ctx context.Context, email string) error {
+			requested = email
+			return nil
+		},
+	}
+	handler := NewAuthHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/forgot-password", handler.ForgotPassword)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewBufferString(`{"email":"test@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("Expected status %d, got %d", http.StatusAccepted, w.Code)
+	}
+	if requested != "test@example.com" {
+		t.Errorf("Expected reset for test@example.com, got %q", requested)
+	}
+}
+
+func TestAuthHandler_ForgotPassword_RateLimited(t *testing.T) {
+	mockService := &mocks.MockAuthService{
+		RequestPasswordResetFunc: func(ctx context.Context, email string) error {
+			return service.ErrTooManyEmailRequests
+		},
+	}
+	handler := NewAuthHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/forgot-password", handler.ForgotPassword)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/forgot-password", bytes.NewBufferString(`{"email":"test@example.com"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("Expected status %d, got %d", http.StatusTooManyRequests, w.Code)
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	mockService := &mocks.MockAuthService{
+		ResetPasswordFunc: func(ctx context.Context, req *model.ResetPasswordRequest) error {
+			if req.Token != "valid" {
+				return service.ErrInvalidUserToken
+			}
+			return nil
+		},
+	}
+	handler := NewAuthHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/reset-password", handler.ResetPassword)
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"token":"valid","new_password":"newpassword456"}`, http.StatusNoContent},
+		{`{"token":"expired","new_password":"newpassword456"}`, http.StatusBadRequest},
+		{`{"new_password":"newpassword456"}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/reset-password", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	mockService := &mocks.MockAuthService{
+		VerifyEmailFunc: func(ctx context.Context, token string) error {
+			if token != "valid" {
+				return service.ErrInvalidUserToken
+			}
+			return nil
+		},
+	}
+	handler := NewAuthHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/verify-email", handler.VerifyEmail)
+
+	for token, want := range map[string]int{"valid": http.StatusNoContent, "used": http.StatusBadRequest} {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/verify-email", bytes.NewBufferString(`{"token":"`+token+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", token, want, w.Code)
+		}
+	}
+}
+
 // ===== AttendanceHandler Tests =====
 
 func TestAttendanceHandler_ClockIn_Success(t *testing.T) {
@@ -1748,3 +1878,834 @@ func TestNewHandlers(t *testing.T) {
 		t.Error("HealthHandler should not be nil")
 	}
 }
+
+// ===== RoleHandler Tests =====
+
+func TestRoleHandler_Create_ValidationError(t *testing.T) {
+	mockService := &mocks.MockRoleService{
+		CreateFunc: func(ctx context.Context, req *model.CustomRoleRequest) (*model.CustomRole, error) {
+			verr := &model.ValidationError{}
+			verr.Add("permissions", "未定義の権限です: x")
+			return nil, verr
+		},
+	}
+
+	handler := NewRoleHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/roles", handler.Create)
+
+	body, _ := json.Marshal(model.CustomRoleRequest{Name: "経理", Permissions: []string{"x"}})
+	req, _ := http.NewRequest(http.MethodPost, "/roles", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("Expected status %d, got %d", http.StatusBadRequest, w.Code)
+	}
+	var resp model.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	if len(resp.Errors) != 1 || resp.Errors[0].Field != "permissions" {
+		t.Errorf("Expected field error on permissions, got %+v", resp.Errors)
+	}
+}
+
+func TestRoleHandler_Delete_InUse(t *testing.T) {
+	mockService := &mocks.MockRoleService{
+		DeleteFunc: func(ctx context.Context, id uuid.UUID) error {
+			return service.ErrRoleInUse
+		},
+	}
+
+	handler := NewRoleHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.DELETE("/roles/:id", handler.Delete)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/roles/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+func TestRoleHandler_Unassign_NotFound(t *testing.T) {
+	mockService := &mocks.MockRoleService{
+		UnassignFunc: func(ctx context.Context, userID, assignmentID uuid.UUID) error {
+			return service.ErrRoleAssignmentNotFound
+		},
+	}
+
+	handler := NewRoleHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.DELETE("/users/:id/roles/:assignmentId", handler.Unassign)
+
+	req, _ := http.NewRequest(http.MethodDelete, "/users/"+uuid.New().String()+"/roles/"+uuid.New().String(), nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// ===== SSOHandler Tests =====
+
+func TestSSOHandler_LoginAndCallback(t *testing.T) {
+	var gotFlow string
+	var gotCallback *model.SSOCallback
+	mockService := &mocks.MockSSOService{
+		BeginFunc: func(ctx context.Context) (*model.SSOAuthorization, error) {
+			return &model.SSOAuthorization{AuthorizationURL: "https://idp.example.com/authorize?state=s", FlowToken: "flow", ExpiresAt: time.Now().Add(10 * time.Minute)}, nil
+		},
+		CallbackFunc: func(ctx context.Context, flowToken string, cb *model.SSOCallback) (string, error) {
+			gotFlow, gotCallback = flowToken, cb
+			return "https://kintai.example.com/auth/sso/callback?code=login-code", nil
+		},
+	}
+	handler := NewSSOHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/api/v1/auth/oidc/login", handler.Login)
+	router.GET("/api/v1/auth/oidc/callback", handler.Callback)
+
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://idp.example.com/authorize?state=s" {
+		t.Fatalf("Expected redirect to IdP, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != ssoFlowCookie || cookies[0].Value != "flow" || !cookies[0].HttpOnly {
+		t.Fatalf("Expected HttpOnly flow cookie, got %+v", cookies)
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/auth/oidc/callback?code=abc&state=s", nil)
+	req.AddCookie(cookies[0])
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusFound || w.Header().Get("Location") != "https://kintai.example.com/auth/sso/callback?code=login-code" {
+		t.Errorf("Expected redirect to frontend, got %d %s", w.Code, w.Header().Get("Location"))
+	}
+	if gotFlow != "flow" || gotCallback.Code != "abc" || gotCallback.State != "s" {
+		t.Errorf("Unexpected callback arguments: %q %+v", gotFlow, gotCallback)
+	}
+	if cleared := w.Result().Cookies(); len(cleared) != 1 || cleared[0].MaxAge >= 0 {
+		t.Errorf("Expected flow cookie to be cleared, got %+v", cleared)
+	}
+}
+
+func TestSSOHandler_Login_NotConfigured(t *testing.T) {
+	mockService := &mocks.MockSSOService{
+		BeginFunc: func(ctx context.Context) (*model.SSOAuthorization, error) {
+			return nil, service.ErrSSONotConfigured
+		},
+	}
+	handler := NewSSOHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/auth/oidc/login", handler.Login)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/oidc/login", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+func TestSSOHandler_Exchange(t *testing.T) {
+	mockService := &mocks.MockSSOService{
+		ExchangeFunc: func(ctx context.Context, code string) (*model.TokenResponse, error) {
+			if code != "valid" {
+				return nil, service.ErrInvalidUserToken
+			}
+			return &model.TokenResponse{AccessToken: "access"}, nil
+		},
+	}
+	handler := NewSSOHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/oidc/exchange", handler.Exchange)
+
+	for body, want := range map[string]int{
+		`{"code":"valid"}`: http.StatusOK,
+		`{"code":"used"}`:  http.StatusUnauthorized,
+		`{}`:               http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/oidc/exchange", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", body, want, w.Code)
+		}
+	}
+}
+
+// ===== Cookie認証 Tests =====
+
+func cookieAuthHandler(mockService *mocks.MockAuthService) (*AuthHandler, *gin.Engine) {
+	handlers := &Handlers{
+		Auth: NewAuthHandler(mockService, getTestLogger()),
+		SSO:  NewSSOHandler(&mocks.MockSSOService{}, getTestLogger()),
+	}
+	handlers.WithCookieAuth(&config.Config{AuthCookieMode: true, AuthCookieSecure: true, AuthCookieSameSite: "strict", JWTRefreshTokenExpiry: 168})
+	return handlers.Auth, setupRouter()
+}
+
+func responseCookies(w *httptest.ResponseRecorder) map[string]*http.Cookie {
+	cookies := make(map[string]*http.Cookie)
+	for _, c := range w.Result().Cookies() {
+		cookies[c.Name] = c
+	}
+	return cookies
+}
+
+func TestAuthHandler_Login_CookieMode(t *testing.T) {
+	handler, router := cookieAuthHandler(&mocks.MockAuthService{
+		LoginFunc: func(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error) {
+			return &model.TokenResponse{AccessToken: "access", RefreshToken: "refresh", ExpiresIn: 900}, nil
+		},
+	})
+	router.POST("/auth/login", handler.Login)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(`{"email":"a@example.com","password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	cookies := responseCookies(w)
+	access, refresh, csrf := cookies["access_token"], cookies["refresh_token"], cookies["csrf_token"]
+	if access == nil || access.Value != "access" || !access.HttpOnly || !access.Secure || access.SameSite != http.SameSiteStrictMode || access.MaxAge != 900 {
+		t.Errorf("Expected HttpOnly secure strict access token cookie, got %+v", access)
+	}
+	if refresh == nil || refresh.Value != "refresh" || !refresh.HttpOnly || refresh.Path != "/api/v1/auth" || refresh.MaxAge != 168*60*60 {
+		t.Errorf("Expected HttpOnly refresh token cookie limited to /api/v1/auth, got %+v", refresh)
+	}
+	if csrf == nil || csrf.Value == "" || csrf.HttpOnly {
+		t.Errorf("Expected CSRF token cookie readable by scripts, got %+v", csrf)
+	}
+
+	var body model.TokenResponse
+	json.Unmarshal(w.Body.Bytes(), &body)
+	if body.AccessToken != "" || body.RefreshToken != "" {
+		t.Errorf("Expected tokens to be omitted from the body, got %+v", body)
+	}
+	if csrf != nil && body.CSRFToken != csrf.Value {
+		t.Errorf("Expected body CSRF token to match the cookie, got %q", body.CSRFToken)
+	}
+}
+
+func TestAuthHandler_Login_CookieModeMFARequired(t *testing.T) {
+	handler, router := cookieAuthHandler(&mocks.MockAuthService{
+		LoginFunc: func(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error) {
+			return &model.TokenResponse{MFARequired: true, MFAToken: "challenge"}, nil
+		},
+	})
+	router.POST("/auth/login", handler.Login)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/login", bytes.NewBufferString(`{"email":"a@example.com","password":"password"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK || len(w.Result().Cookies()) != 0 {
+		t.Errorf("Expected MFA challenge without cookies, got %d with %v", w.Code, w.Result().Cookies())
+	}
+}
+
+func TestAuthHandler_RefreshToken_CookieMode(t *testing.T) {
+	handler, router := cookieAuthHandler(&mocks.MockAuthService{
+		RefreshTokenFunc: func(ctx context.Context, refreshToken string) (*model.TokenResponse, error) {
+			if refreshToken != "refresh" {
+				return nil, errors.New("無効なリフレッシュトークンです")
+			}
+			return &model.TokenResponse{AccessToken: "access2", RefreshToken: "refresh2", ExpiresIn: 900}, nil
+		},
+	})
+	router.POST("/auth/refresh", handler.RefreshToken)
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "refresh"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if cookies := responseCookies(w); cookies["refresh_token"] == nil || cookies["refresh_token"].Value != "refresh2" {
+		t.Errorf("Expected rotated refresh token cookie, got %v", cookies["refresh_token"])
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "/auth/refresh", nil)
+	req.AddCookie(&http.Cookie{Name: "refresh_token", Value: "revoked"})
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+	if cookies := responseCookies(w); cookies["access_token"] == nil || cookies["access_token"].MaxAge >= 0 {
+		t.Errorf("Expected token cookies to be cleared after a failed refresh, got %v", cookies)
+	}
+}
+
+func TestAuthHandler_Logout_CookieModeClearsCookies(t *testing.T) {
+	handler, router := cookieAuthHandler(&mocks.MockAuthService{
+		LogoutFunc: func(ctx context.Context, userID uuid.UUID) error { return nil },
+	})
+	router.POST("/auth/logout", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Logout(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/auth/logout", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status %d, got %d", http.StatusNoContent, w.Code)
+	}
+	cookies := responseCookies(w)
+	for _, name := range []string{"access_token", "refresh_token"} {
+		if cookies[name] == nil || cookies[name].MaxAge >= 0 {
+			t.Errorf("Expected %s cookie to be cleared, got %v", name, cookies[name])
+		}
+	}
+}
+
+func TestAuthHandler_CSRFToken(t *testing.T) {
+	handler, router := cookieAuthHandler(&mocks.MockAuthService{})
+	router.GET("/auth/csrf", handler.CSRFToken)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/csrf", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var body model.CSRFTokenResponse
+	json.Unmarshal(w.Body.Bytes(), &body)
+	cookie := responseCookies(w)["csrf_token"]
+	if w.Code != http.StatusOK || body.CSRFToken == "" || cookie == nil || cookie.Value != body.CSRFToken {
+		t.Fatalf("Expected a CSRF token in the body and cookie, got %d %+v %v", w.Code, body, cookie)
+	}
+
+	// 発行済みのトークンは使い回す
+	req, _ = http.NewRequest(http.MethodGet, "/auth/csrf", nil)
+	req.AddCookie(cookie)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var again model.CSRFTokenResponse
+	json.Unmarshal(w.Body.Bytes(), &again)
+	if again.CSRFToken != body.CSRFToken {
+		t.Errorf("Expected the issued CSRF token to be reused, got %q", again.CSRFToken)
+	}
+}
+
+func TestAuthHandler_CSRFToken_CookieModeDisabled(t *testing.T) {
+	handler := NewAuthHandler(&mocks.MockAuthService{}, getTestLogger())
+	router := setupRouter()
+	router.GET("/auth/csrf", handler.CSRFToken)
+
+	req, _ := http.NewRequest(http.MethodGet, "/auth/csrf", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("Expected status %d, got %d", http.StatusNotFound, w.Code)
+	}
+}
+
+// ===== MFAHandler Tests =====
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+	mockService := &mocks.MockAuthService{
+		VerifyMFAFunc: func(ctx context.Context, req *model.MFAVerifyRequest) (*model.TokenResponse, error) {
+			switch req.Code {
+			case "123456":
+				return &model.TokenResponse{AccessToken: "access", RefreshToken: "refresh"}, nil
+			case "expired":
+				return nil, service.ErrMFAChallengeInvalid
+			}
+			return nil, service.ErrInvalidMFACode
+		},
+	}
+	handler := NewAuthHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/auth/mfa/verify", handler.VerifyMFA)
+
+	cases := []struct {
+		body string
+		want int
+	}{
+		{`{"mfa_token":"challenge","code":"123456"}`, http.StatusOK},
+		{`{"mfa_token":"challenge","code":"000000"}`, http.StatusUnauthorized},
+		{`{"mfa_token":"challenge","code":"expired"}`, http.StatusUnauthorized},
+		{`{"code":"123456"}`, http.StatusBadRequest},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/auth/mfa/verify", bytes.NewBufferString(tc.body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tc.want {
+			t.Errorf("%s: expected status %d, got %d", tc.body, tc.want, w.Code)
+		}
+	}
+}
+
+func TestMFAHandler_Enable(t *testing.T) {
+	mockService := &mocks.MockMFAService{
+		EnableFunc: func(ctx context.Context, userID uuid.UUID, code string) (*model.MFAEnrollmentResponse, error) {
+			if code != "123456" {
+				return nil, service.ErrInvalidMFACode
+			}
+			return &model.MFAEnrollmentResponse{RecoveryCodes: []string{"AAAAA-BBBBB"}}, nil
+		},
+	}
+	handler := NewMFAHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/me/mfa/enable", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Enable(c)
+	})
+
+	for body, want := range map[string]int{
+		`{"code":"123456"}`: http.StatusOK,
+		`{"code":"000000"}`: http.StatusBadRequest,
+		`{}`:                http.StatusBadRequest,
+	} {
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/enable", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", body, want, w.Code)
+		}
+	}
+}
+
+func TestMFAHandler_Disable_RequiredByPolicy(t *testing.T) {
+	mockService := &mocks.MockMFAService{
+		DisableFunc: func(ctx context.Context, userID uuid.UUID, code string) error {
+			return service.ErrMFARequired
+		},
+	}
+	handler := NewMFAHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/me/mfa/disable", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Disable(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/disable", bytes.NewBufferString(`{"code":"123456"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+func TestMFAHandler_Setup_AlreadyEnabled(t *testing.T) {
+	mockService := &mocks.MockMFAService{
+		SetupFunc: func(ctx context.Context, userID uuid.UUID) (*model.MFASetupResponse, error) {
+			return nil, service.ErrMFAAlreadyEnabled
+		},
+	}
+	handler := NewMFAHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/me/mfa/setup", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.Setup(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/mfa/setup", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("Expected status %d, got %d", http.StatusConflict, w.Code)
+	}
+}
+
+// ===== LoginSecurityHandler Tests =====
+
+func TestLoginSecurityHandler_GetMyHistory(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockLoginSecurityService{
+		GetHistoryFunc: func(ctx context.Context, id uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error) {
+			if id != userID {
+				t.Errorf("Expected history of the current user, got %s", id)
+			}
+			return []model.LoginHistory{{Email: "a@example.com", Method: model.LoginMethodPassword, Success: true}}, 1, nil
+		},
+	}
+	handler := NewLoginSecurityHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/users/me/login-history", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.GetMyHistory(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/login-history", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.PaginatedResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Total != 1 {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestLoginSecurityHandler_Unlock(t *testing.T) {
+	known := uuid.New()
+	mockService := &mocks.MockLoginSecurityService{
+		UnlockFunc: func(ctx context.Context, userID uuid.UUID) error {
+			if userID != known {
+				return service.ErrUserNotFound
+			}
+			return nil
+		},
+	}
+	handler := NewLoginSecurityHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/:id/unlock", handler.Unlock)
+
+	cases := map[string]int{
+		known.String():      http.StatusNoContent,
+		uuid.New().String(): http.StatusNotFound,
+		"invalid":           http.StatusBadRequest,
+	}
+	for id, want := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id+"/unlock", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", id, want, w.Code)
+		}
+	}
+}
+
+// ===== SessionHandler Tests =====
+
+func TestSessionHandler_GetMy(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockSessionService{
+		ListFunc: func(ctx context.Context, id uuid.UUID) ([]model.Session, error) {
+			if id != userID {
+				t.Errorf("Expected sessions of the current user, got %s", id)
+			}
+			return []model.Session{{ID: uuid.New(), Device: "Chrome (Windows)", Current: true}}, nil
+		},
+	}
+	handler := NewSessionHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/users/me/sessions", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.GetMy(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodGet, "/users/me/sessions", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var sessions []model.Session
+	if err := json.Unmarshal(w.Body.Bytes(), &sessions); err != nil || len(sessions) != 1 || !sessions[0].Current {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestSessionHandler_Revoke(t *testing.T) {
+	userID, known := uuid.New(), uuid.New()
+	mockService := &mocks.MockSessionService{
+		RevokeFunc: func(ctx context.Context, id, sessionID uuid.UUID) error {
+			if id != userID || sessionID != known {
+				return service.ErrSessionNotFound
+			}
+			return nil
+		},
+	}
+	handler := NewSessionHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.DELETE("/users/me/sessions/:id", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.Revoke(c)
+	})
+
+	cases := map[string]int{
+		known.String():      http.StatusNoContent,
+		uuid.New().String(): http.StatusNotFound,
+		"invalid":           http.StatusBadRequest,
+	}
+	for id, want := range cases {
+		req, _ := http.NewRequest(http.MethodDelete, "/users/me/sessions/"+id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", id, want, w.Code)
+		}
+	}
+}
+
+func TestSessionHandler_RevokeOthers(t *testing.T) {
+	mockService := &mocks.MockSessionService{
+		RevokeOthersFunc: func(ctx context.Context, userID uuid.UUID) (int, error) {
+			return 3, nil
+		},
+	}
+	handler := NewSessionHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/me/sessions/revoke-others", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		handler.RevokeOthers(c)
+	})
+
+	req, _ := http.NewRequest(http.MethodPost, "/users/me/sessions/revoke-others", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.SessionRevokeResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Revoked != 3 {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
+
+func TestSessionHandler_ForceLogout(t *testing.T) {
+	known := uuid.New()
+	mockService := &mocks.MockSessionService{
+		ForceLogoutFunc: func(ctx context.Context, userID uuid.UUID) error {
+			if userID != known {
+				return service.ErrUserNotFound
+			}
+			return nil
+		},
+	}
+	handler := NewSessionHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/:id/force-logout", handler.ForceLogout)
+
+	cases := map[string]int{
+		known.String():      http.StatusNoContent,
+		uuid.New().String(): http.StatusNotFound,
+		"invalid":           http.StatusBadRequest,
+	}
+	for id, want := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/users/"+id+"/force-logout", nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", id, want, w.Code)
+		}
+	}
+}
+
+// ===== APITokenHandler Tests =====
+
+func TestAPITokenHandler_CreateMy(t *testing.T) {
+	userID := uuid.New()
+	mockService := &mocks.MockAPITokenService{
+		CreatePersonalFunc: func(ctx context.Context, id uuid.UUID, req *model.APITokenRequest) (*model.APITokenIssued, error) {
+			if id != userID {
+				t.Errorf("Expected token for the current user, got %s", id)
+			}
+			if len(req.Scopes) == 0 {
+				verr := &model.ValidationError{}
+				verr.Add("scopes", "スコープを1つ以上指定してください")
+				return nil, verr
+			}
+			return &model.APITokenIssued{Token: "kt_pat_secret", APIToken: &model.APIToken{Name: req.Name}}, nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/users/me/api-tokens", func(c *gin.Context) {
+		c.Set("userID", userID.String())
+		handler.CreateMy(c)
+	})
+
+	cases := map[string]int{
+		`{"name":"BI","scopes":["export:read"],"expires_in_days":30}`: http.StatusCreated,
+		`{"name":"BI","scopes":[],"expires_in_days":30}`:              http.StatusBadRequest,
+		`{invalid`: http.StatusBadRequest,
+	}
+	for body, want := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/users/me/api-tokens", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", body, want, w.Code)
+		}
+		if want == http.StatusCreated {
+			var resp model.APITokenIssued
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Token != "kt_pat_secret" {
+				t.Errorf("Unexpected response: %s", w.Body.String())
+			}
+		}
+	}
+}
+
+func TestAPITokenHandler_CreateServiceAccount(t *testing.T) {
+	mockService := &mocks.MockAPITokenService{
+		CreateServiceAccountFunc: func(ctx context.Context, req *model.ServiceAccountRequest) (*model.ServiceAccount, error) {
+			if req.Role == model.RoleAdmin {
+				return nil, service.ErrForbidden
+			}
+			return &model.ServiceAccount{Name: req.Name, Role: req.Role}, nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.POST("/service-accounts", handler.CreateServiceAccount)
+
+	cases := map[string]int{
+		`{"name":"payroll","role":"manager"}`: http.StatusCreated,
+		`{"name":"payroll","role":"admin"}`:   http.StatusForbidden,
+	}
+	for body, want := range cases {
+		req, _ := http.NewRequest(http.MethodPost, "/service-accounts", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", body, want, w.Code)
+		}
+	}
+}
+
+func TestAPITokenHandler_RevokeServiceAccountToken(t *testing.T) {
+	accountID, tokenID := uuid.New(), uuid.New()
+	mockService := &mocks.MockAPITokenService{
+		RevokeServiceAccountTokenFunc: func(ctx context.Context, a, tok uuid.UUID) error {
+			if a != accountID || tok != tokenID {
+				return service.ErrAPITokenNotFound
+			}
+			return nil
+		},
+	}
+	handler := NewAPITokenHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.DELETE("/service-accounts/:id/tokens/:tokenId", handler.RevokeServiceAccountToken)
+
+	cases := map[string]int{
+		accountID.String() + "/tokens/" + tokenID.String():    http.StatusNoContent,
+		accountID.String() + "/tokens/" + uuid.New().String(): http.StatusNotFound,
+		accountID.String() + "/tokens/invalid":                http.StatusBadRequest,
+	}
+	for path, want := range cases {
+		req, _ := http.NewRequest(http.MethodDelete, "/service-accounts/"+path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", path, want, w.Code)
+		}
+	}
+}
+
+func TestAuditHandler_List(t *testing.T) {
+	actorID := uuid.New()
+	mockService := &mocks.MockAuditService{
+		ListFunc: func(ctx context.Context, q *model.AuditLogQuery) ([]model.AuditLog, int64, error) {
+			if q.ActorID != actorID.String() {
+				t.Errorf("Expected actor filter %s, got %s", actorID, q.ActorID)
+			}
+			if q.EndDate == nil || q.EndDate.Format("2006-01-02") != "2024-01-31" {
+				t.Errorf("Expected end date to be parsed, got %v", q.EndDate)
+			}
+			if q.Page != 2 || q.PageSize != 10 {
+				t.Errorf("Expected page 2 / size 10, got %d / %d", q.Page, q.PageSize)
+			}
+			return []model.AuditLog{{Seq: 1, Action: model.AuditActionUpdate, EntityType: "users"}}, 11, nil
+		},
+	}
+	handler := NewAuditHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/audit-logs", handler.List)
+
+	valid := "/audit-logs?actor_id=" + actorID.String() + "&end_date=2024-01-31&page=2&page_size=10"
+	cases := map[string]int{
+		valid:                               http.StatusOK,
+		"/audit-logs?start_date=2024/01/01": http.StatusBadRequest,
+		"/audit-logs?actor_id=someone":      http.StatusBadRequest,
+	}
+	for url, want := range cases {
+		req, _ := http.NewRequest(http.MethodGet, url, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s: expected status %d, got %d", url, want, w.Code)
+		}
+	}
+}
+
+func TestAuditHandler_Export(t *testing.T) {
+	mockService := &mocks.MockAuditService{
+		ExportCSVFunc: func(ctx context.Context, q *model.AuditLogQuery) ([]byte, error) {
+			return []byte("\xEF\xBB\xBF連番\n"), nil
+		},
+	}
+	handler := NewAuditHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/audit-logs/export", handler.Export)
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit-logs/export", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=audit_logs.csv" {
+		t.Errorf("Unexpected Content-Disposition: %s", got)
+	}
+}
+
+func TestAuditHandler_Verify(t *testing.T) {
+	seq := int64(42)
+	mockService := &mocks.MockAuditService{
+		VerifyFunc: func(ctx context.Context) (*model.AuditVerifyResult, error) {
+			return &model.AuditVerifyResult{Valid: false, Checked: 42, BrokenSeq: &seq, Reason: "hash_mismatch"}, nil
+		},
+	}
+	handler := NewAuditHandler(mockService, getTestLogger())
+	router := setupRouter()
+	router.GET("/audit-logs/verify", handler.Verify)
+
+	req, _ := http.NewRequest(http.MethodGet, "/audit-logs/verify", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	var resp model.AuditVerifyResult
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Valid || resp.BrokenSeq == nil || *resp.BrokenSeq != 42 {
+		t.Errorf("Unexpected response: %s", w.Body.String())
+	}
+}
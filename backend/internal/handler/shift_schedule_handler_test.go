@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/service"
+)
+
+func TestShiftScheduleHandler_Publish(t *testing.T) {
+	var gotNote string
+	publishErr := error(nil)
+	svc := &mocks.MockShiftScheduleService{
+		PublishFunc: func(_ context.Context, id, _ uuid.UUID, req *model.ShiftSchedulePublishRequest) (*model.ShiftSchedule, error) {
+			gotNote = req.ChangeNote
+			if publishErr != nil {
+				return nil, publishErr
+			}
+			return &model.ShiftSchedule{}, nil
+		},
+	}
+	r := setupRouter()
+	r.POST("/shift-schedules/:id/publish", func(c *gin.Context) {
+		c.Set("userID", uuid.New().String())
+		c.Next()
+	}, NewShiftScheduleHandler(svc, getTestLogger()).Publish)
+	post := func(body string) int {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/shift-schedules/"+uuid.New().String()+"/publish", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 公開メモは任意
+	if code := post(""); code != http.StatusOK {
+		t.Errorf("Expected 200 without a body, got %d", code)
+	}
+	if code := post(`{"change_note":"遅番を追加"}`); code != http.StatusOK || gotNote != "遅番を追加" {
+		t.Errorf("Expected 200 with the note, got %d %q", code, gotNote)
+	}
+	if code := post(`{"change_note":`); code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for a malformed body, got %d", code)
+	}
+
+	cases := map[error]int{
+		service.ErrShiftScheduleNotFound:  http.StatusNotFound,
+		service.ErrShiftSchedulePublished: http.StatusConflict,
+		errors.New("db down"):             http.StatusInternalServerError,
+	}
+	for err, want := range cases {
+		publishErr = err
+		if code := post(""); code != want {
+			t.Errorf("%v: expected %d, got %d", err, want, code)
+		}
+	}
+}
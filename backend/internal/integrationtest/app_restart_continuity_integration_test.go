@@ -60,7 +60,7 @@ func TestAppRestartContinuityWithInflightRequest(t *testing.T) {
 	// Simulate restart: create new DB session + new router and switch active instance.
 	newDB, err := openDB(env.Config.DatabaseURL)
 	require.NoError(t, err)
-	newRouter := buildRouter(newDB, env.Config, env.Logger)
+	newRouter := buildRouter(newDB, env.Config, env.Logger, env.Mailer)
 
 	env.DB = newDB
 	env.Router = newRouter
@@ -28,6 +28,14 @@ func TestAuthAndMiddlewareIntegration(t *testing.T) {
 		}, nil)
 		require.Equal(t, http.StatusCreated, registerResp.Code)
 
+		// 確認前はログインできない
+		unverifiedResp := env.DoJSON(t, http.MethodPost, "/api/v1/auth/login", map[string]any{
+			"email":    email,
+			"password": password,
+		}, nil)
+		require.Equal(t, http.StatusUnauthorized, unverifiedResp.Code)
+		env.VerifyEmail(t, email)
+
 		loginResp := env.DoJSON(t, http.MethodPost, "/api/v1/auth/login", map[string]any{
 			"email":    email,
 			"password": password,
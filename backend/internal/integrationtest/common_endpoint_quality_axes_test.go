@@ -96,6 +96,7 @@ func TestCommonEndpointQualityAxes(t *testing.T) {
 		require.NoError(t, json.Unmarshal(registerResp.Body.Bytes(), &registered))
 		require.NotEqual(t, uuid.Nil, registered.ID)
 		require.Equal(t, email, registered.Email)
+		env.VerifyEmail(t, email)
 
 		loginResp := env.DoJSON(t, http.MethodPost, "/api/v1/auth/login", map[string]any{
 			"email":    email,
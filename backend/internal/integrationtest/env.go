@@ -4,8 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"sync"
@@ -13,8 +15,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/your-org/kintai/backend/internal/audit"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/handler"
+	"github.com/your-org/kintai/backend/internal/mailer"
 	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
@@ -57,6 +61,7 @@ type TestEnv struct {
 	Router      *gin.Engine
 	Config      *config.Config
 	Logger      *logger.Logger
+	Mailer      *mailer.MemoryMailer
 	backendRoot string
 }
 
@@ -106,6 +111,7 @@ func NewTestEnv(t testing.TB, input *Options) *TestEnv {
 		DB:          db,
 		Config:      cfg,
 		Logger:      log,
+		Mailer:      mailer.NewMemoryMailer(),
 		backendRoot: root,
 	}
 
@@ -127,7 +133,7 @@ func NewTestEnv(t testing.TB, input *Options) *TestEnv {
 	}
 
 	gin.SetMode(gin.TestMode)
-	env.Router = buildRouter(env.DB, env.Config, env.Logger)
+	env.Router = buildRouter(env.DB, env.Config, env.Logger, env.Mailer)
 
 	t.Cleanup(func() {
 		if sqlDB, err := env.DB.DB(); err == nil {
@@ -232,26 +238,50 @@ func (e *TestEnv) LoadSeeds(seedPaths ...string) error {
 	return nil
 }
 
-func buildRouter(db *gorm.DB, cfg *config.Config, log *logger.Logger) *gin.Engine {
+func buildRouter(db *gorm.DB, cfg *config.Config, log *logger.Logger, mail mailer.Mailer) *gin.Engine {
 	repos := repository.NewRepositories(db)
 	services := service.NewServices(service.Deps{
 		Repos:  repos,
 		Config: cfg,
 		Logger: log,
+		Mailer: mail,
 	})
-	handlers := handler.NewHandlers(services, log)
-	mw := middleware.NewMiddleware(cfg, log)
+	handlers := handler.NewHandlers(services, log).WithCookieAuth(cfg)
+	mw := middleware.NewMiddleware(cfg, log).WithAPITokens(services.APIToken)
 
 	engine := gin.New()
 	router.Setup(engine, handlers, mw)
 	return engine
 }
 
+// VerifyEmail は登録時に送信された確認メールのリンクでメールアドレスを確認する
+func (e *TestEnv) VerifyEmail(t testing.TB, email string) {
+	t.Helper()
+
+	msg, ok := e.Mailer.Last(email)
+	if !ok {
+		t.Fatalf("no email sent to %s", email)
+	}
+	match := verificationTokenPattern.FindStringSubmatch(msg.Body)
+	if match == nil {
+		t.Fatalf("verification link not found in email to %s", email)
+	}
+	resp := e.DoJSON(t, http.MethodPost, "/api/v1/auth/verify-email", map[string]any{"token": match[1]}, nil)
+	if resp.Code != http.StatusNoContent {
+		t.Fatalf("verify email failed: status=%d body=%s", resp.Code, resp.Body.String())
+	}
+}
+
+var verificationTokenPattern = regexp.MustCompile(`verify-email\?token=([A-Za-z0-9_-]+)`)
+
 func openDB(databaseURL string) (*gorm.DB, error) {
 	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
+	if err := db.Use(audit.NewRecorder()); err != nil {
+		return nil, err
+	}
 
 	sqlDB, err := db.DB()
 	if err != nil {
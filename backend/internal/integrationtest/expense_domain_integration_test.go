@@ -47,7 +47,7 @@ type expenseViolationsResponse struct {
 }
 
 type expenseNotificationsResponse struct {
-	Data []model.ExpenseNotification `json:"data"`
+	Data []model.Notification `json:"data"`
 }
 
 type expenseRemindersResponse struct {
@@ -172,10 +172,10 @@ func TestExpenseDomainIntegration(t *testing.T) {
 		require.NotNil(t, approved.ApprovedBy)
 		require.Equal(t, approver.ID, *approved.ApprovedBy)
 
-		var approvedNotif model.ExpenseNotification
+		var approvedNotif model.Notification
 		require.NoError(
 			t,
-			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, pendingForApprove.ID, "approved").
+			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, pendingForApprove.ID, model.NotificationTypeExpenseApproved).
 				First(&approvedNotif).Error,
 		)
 
@@ -191,10 +191,10 @@ func TestExpenseDomainIntegration(t *testing.T) {
 		require.Equal(t, model.ExpenseStatusRejected, rejected.Status)
 		require.Equal(t, "policy violation", rejected.RejectedReason)
 
-		var rejectedNotif model.ExpenseNotification
+		var rejectedNotif model.Notification
 		require.NoError(
 			t,
-			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, pendingForReject.ID, "rejected").
+			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, pendingForReject.ID, model.NotificationTypeExpenseRejected).
 				First(&rejectedNotif).Error,
 		)
 	})
@@ -392,10 +392,10 @@ func TestExpenseDomainIntegration(t *testing.T) {
 		require.NoError(t, json.Unmarshal(historyResp.Body.Bytes(), &histories))
 		require.GreaterOrEqual(t, len(histories.Data), 2)
 
-		var notif model.ExpenseNotification
+		var notif model.Notification
 		require.NoError(
 			t,
-			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, expense.ID, "comment").
+			env.DB.Where("user_id = ? AND expense_id = ? AND type = ?", employee.ID, expense.ID, model.NotificationTypeExpenseComment).
 				First(&notif).Error,
 		)
 	})
@@ -540,22 +540,27 @@ func TestExpenseDomainIntegration(t *testing.T) {
 
 		expense := mustCreateExpense(t, env, headers, model.ExpenseStatusPending)
 
-		notif1 := model.ExpenseNotification{
+		notif1 := model.Notification{
 			UserID:    employee.ID,
 			ExpenseID: &expense.ID,
-			Type:      "approved",
+			Type:      model.NotificationTypeExpenseApproved,
+			Title:     "approved",
 			Message:   "approved notification",
 			IsRead:    false,
 		}
-		notif2 := model.ExpenseNotification{
+		notif2 := model.Notification{
 			UserID:    employee.ID,
 			ExpenseID: &expense.ID,
-			Type:      "comment",
+			Type:      model.NotificationTypeExpenseComment,
+			Title:     "comment",
 			Message:   "comment notification",
 			IsRead:    true,
 		}
+		// 経費申請に紐づかない通知は経費の通知一覧に表示しない
+		other := model.Notification{UserID: employee.ID, Type: model.NotificationTypeGeneral, Title: "other", Message: "other notification"}
 		require.NoError(t, env.DB.Create(&notif1).Error)
 		require.NoError(t, env.DB.Create(&notif2).Error)
+		require.NoError(t, env.DB.Create(&other).Error)
 
 		due := mustDate(t, "2031-04-01")
 		reminder := model.ExpenseReminder{
@@ -574,6 +579,13 @@ func TestExpenseDomainIntegration(t *testing.T) {
 		require.Len(t, notifications.Data, 1)
 		require.Equal(t, notif1.ID, notifications.Data[0].ID)
 
+		// 経費の通知も全体の未読件数に含まれる
+		unreadResp := env.DoJSON(t, http.MethodGet, "/api/v1/notifications/unread-count", nil, headers)
+		require.Equal(t, http.StatusOK, unreadResp.Code)
+		var unread model.NotificationCount
+		require.NoError(t, json.Unmarshal(unreadResp.Body.Bytes(), &unread))
+		require.Equal(t, 2, unread.Unread)
+
 		markReadResp := env.DoJSON(
 			t,
 			http.MethodPut,
@@ -585,6 +597,10 @@ func TestExpenseDomainIntegration(t *testing.T) {
 
 		markAllResp := env.DoJSON(t, http.MethodPut, "/api/v1/expenses/notifications/read-all", nil, headers)
 		require.Equal(t, http.StatusOK, markAllResp.Code)
+		// 経費の一覧からの既読化は経費以外の通知に影響しない
+		var otherAfter model.Notification
+		require.NoError(t, env.DB.First(&otherAfter, "id = ?", other.ID).Error)
+		require.False(t, otherAfter.IsRead)
 
 		settingsResp := env.DoJSON(t, http.MethodGet, "/api/v1/expenses/notification-settings", nil, headers)
 		require.Equal(t, http.StatusOK, settingsResp.Code)
@@ -28,7 +28,7 @@ func TestFailoverAcrossMultipleInstances(t *testing.T) {
 
 	secondaryDB, err := openDB(env.Config.DatabaseURL)
 	require.NoError(t, err)
-	secondaryRouter := buildRouter(secondaryDB, env.Config, env.Logger)
+	secondaryRouter := buildRouter(secondaryDB, env.Config, env.Logger, env.Mailer)
 	t.Cleanup(func() {
 		if sqlDB, err := secondaryDB.DB(); err == nil {
 			_ = sqlDB.Close()
@@ -0,0 +1,58 @@
+// Package mailer はメール送信の抽象化と送信ドライバーを提供する
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+// Message は送信するメール（本文はプレーンテキスト）
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer はメール送信の抽象
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ドライバー名（MAIL_DRIVER）
+const (
+	DriverLog  = "log"
+	DriverSMTP = "smtp"
+	DriverSES  = "ses"
+)
+
+// New は設定のドライバーに応じた Mailer を作成する
+func New(ctx context.Context, cfg *config.Config, log *logger.Logger) (Mailer, error) {
+	switch cfg.MailDriver {
+	case DriverSMTP:
+		return NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SESFromEmail), nil
+	case DriverSES:
+		return NewSESMailer(ctx, cfg.AWSRegion, cfg.SESFromEmail)
+	case DriverLog, "":
+		return NewLogMailer(log), nil
+	}
+	return nil, fmt.Errorf("unknown mail driver: %s", cfg.MailDriver)
+}
+
+// ===== ログ出力 =====
+
+// LogMailer は送信せずにログへ出力する（開発環境用）
+type LogMailer struct {
+	log *logger.Logger
+}
+
+func NewLogMailer(log *logger.Logger) *LogMailer {
+	return &LogMailer{log: log}
+}
+
+func (m *LogMailer) Send(_ context.Context, msg Message) error {
+	m.log.Info("メールを送信しました（ログ出力のみ）", "to", msg.To, "subject", msg.Subject, "body", msg.Body)
+	return nil
+}
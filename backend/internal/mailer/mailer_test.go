@@ -0,0 +1,75 @@
+package mailer
+
+import (
+	"context"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+func TestNew_SelectsDriver(t *testing.T) {
+	log, _ := logger.NewLogger("debug", "test")
+
+	m, err := New(context.Background(), &config.Config{MailDriver: DriverSMTP, SMTPHost: "localhost", SMTPPort: 1025}, log)
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+	if _, ok := m.(*SMTPMailer); !ok {
+		t.Errorf("Expected SMTPMailer, got %T", m)
+	}
+	if m, _ := New(context.Background(), &config.Config{}, log); m == nil {
+		t.Error("Expected log mailer by default")
+	} else if _, ok := m.(*LogMailer); !ok {
+		t.Errorf("Expected LogMailer, got %T", m)
+	}
+	if _, err := New(context.Background(), &config.Config{MailDriver: "pigeon"}, log); err == nil {
+		t.Error("Expected error for unknown driver")
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com", 587, "user", "pass", "noreply@example.com")
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var gotMsg []byte
+	var gotAuth smtp.Auth
+	m.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotAuth, gotFrom, gotTo, gotMsg = addr, a, from, to, msg
+		return nil
+	}
+
+	err := m.Send(context.Background(), Message{To: "user@example.com", Subject: "パスワード再設定", Body: "1行目\n2行目"})
+	if err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotFrom != "noreply@example.com" || len(gotTo) != 1 || gotTo[0] != "user@example.com" {
+		t.Errorf("Unexpected envelope: addr=%s from=%s to=%v", gotAddr, gotFrom, gotTo)
+	}
+	if gotAuth == nil {
+		t.Error("Expected PLAIN auth when username is set")
+	}
+	msg := string(gotMsg)
+	if !strings.Contains(msg, "Subject: =?UTF-8?b?") || !strings.Contains(msg, "1行目\r\n2行目") {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
+
+func TestMemoryMailer_Last(t *testing.T) {
+	m := NewMemoryMailer()
+	_ = m.Send(context.Background(), Message{To: "a@example.com", Subject: "1"})
+	_ = m.Send(context.Background(), Message{To: "b@example.com", Subject: "2"})
+	_ = m.Send(context.Background(), Message{To: "a@example.com", Subject: "3"})
+
+	if msg, ok := m.Last("a@example.com"); !ok || msg.Subject != "3" {
+		t.Errorf("Expected latest message to a@example.com, got %+v", msg)
+	}
+	if _, ok := m.Last("c@example.com"); ok {
+		t.Error("Expected no message to c@example.com")
+	}
+	if len(m.Sent()) != 3 {
+		t.Errorf("Expected 3 sent messages, got %d", len(m.Sent()))
+	}
+}
@@ -0,0 +1,47 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+)
+
+// MemoryMailer は送信したメールを保持する（テスト用）
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []Message
+	// Err を設定すると Send はそのエラーを返す
+	Err error
+}
+
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+func (m *MemoryMailer) Send(_ context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent は送信済みのメールを送信順に返す
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Last は宛先に最後に送信したメールを返す
+func (m *MemoryMailer) Last(to string) (Message, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.sent) - 1; i >= 0; i-- {
+		if m.sent[i].To == to {
+			return m.sent[i], true
+		}
+	}
+	return Message{}, false
+}
@@ -0,0 +1,45 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2"
+	"github.com/aws/aws-sdk-go-v2/service/sesv2/types"
+)
+
+// SESMailer は Amazon SES v2 API で送信する（本番環境用）
+type SESMailer struct {
+	client *sesv2.Client
+	from   string
+}
+
+// NewSESMailer は既定の認証情報チェーンで SES クライアントを作成する
+func NewSESMailer(ctx context.Context, region, from string) (*SESMailer, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(region))
+	if err != nil {
+		return nil, fmt.Errorf("load aws config: %w", err)
+	}
+	return &SESMailer{client: sesv2.NewFromConfig(awsCfg), from: from}, nil
+}
+
+func (m *SESMailer) Send(ctx context.Context, msg Message) error {
+	_, err := m.client.SendEmail(ctx, &sesv2.SendEmailInput{
+		FromEmailAddress: aws.String(m.from),
+		Destination:      &types.Destination{ToAddresses: []string{msg.To}},
+		Content: &types.EmailContent{
+			Simple: &types.Message{
+				Subject: &types.Content{Data: aws.String(msg.Subject), Charset: aws.String("UTF-8")},
+				Body: &types.Body{
+					Text: &types.Content{Data: aws.String(msg.Body), Charset: aws.String("UTF-8")},
+				},
+			},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("ses send: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// ===== APIトークン =====
+
+// APITokenPrefix はAPIトークン（個人用アクセストークン・サービスアカウント）の接頭辞。JWTと区別する
+const APITokenPrefix = "kt_"
+
+// APITokenAuthenticator はAPIトークンを検証し、操作主体を返す
+type APITokenAuthenticator interface {
+	AuthenticateAPIToken(ctx context.Context, token string) (*Actor, error)
+}
+
+// WithAPITokens は Auth で受け付けるAPIトークンの検証を設定する（未設定の場合はJWTのみ受け付ける）
+func (m *Middleware) WithAPITokens(a APITokenAuthenticator) *Middleware {
+	m.apiTokens = a
+	return m
+}
+
+// TokenScope はAPIトークンで利用できる操作の範囲（"リソース:read" または "リソース:write"）。
+// write は同じリソースの read を含む
+type TokenScope string
+
+// tokenResources はスコープのリソースと対象のルート（/api/v1 以下の接頭辞）
+var tokenResources = []struct {
+	name        string
+	description string
+	prefixes    []string
+	writable    bool
+}{
+	{"attendance", "勤怠・休暇・残業・シフト", []string{
+		"/attendance", "/leaves", "/overtime", "/corrections", "/leave-balances", "/substitute-holidays",
+		"/working-hour-policies", "/remote-work-policies", "/team", "/shifts", "/shift-schedules", "/holidays",
+	}, true},
+	{"time_entry", "工数・プロジェクト", []string{"/time-entries", "/projects"}, true},
+	{"expense", "経費", []string{"/expenses"}, true},
+	{"hr", "人事", []string{"/hr"}, true},
+	{"user", "ユーザー・部署", []string{"/users", "/departments"}, true},
+	{"export", "勤怠・休暇・残業・工数のエクスポート", []string{"/export"}, false},
+	{"report", "ダッシュボードの統計", []string{"/dashboard"}, false},
+}
+
+// tokenDeniedPrefixes はAPIトークンでは利用できないルート（アカウントの設定・権限とセキュリティの管理）
+var tokenDeniedPrefixes = []string{"/users/me/", "/users/:id/"}
+
+// TokenScopeDefinitions はAPIトークンに設定できるスコープの一覧を返す
+func TokenScopeDefinitions() []model.APITokenScopeDefinition {
+	var defs []model.APITokenScopeDefinition
+	for _, r := range tokenResources {
+		defs = append(defs, model.APITokenScopeDefinition{Name: r.name + ":read", Description: r.description + "の参照"})
+		if r.writable {
+			defs = append(defs, model.APITokenScopeDefinition{Name: r.name + ":write", Description: r.description + "の参照・登録・更新・削除"})
+		}
+	}
+	return defs
+}
+
+// IsKnownTokenScope はスコープ名が定義済みかを返す
+func IsKnownTokenScope(name string) bool {
+	for _, d := range TokenScopeDefinitions() {
+		if d.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RequiredTokenScope はルート（/api/v1 以下のパターン）の呼び出しに必要なスコープを返す。
+// APIトークンで利用できないルートは空文字列を返す
+func RequiredTokenScope(method, route string) TokenScope {
+	for _, p := range tokenDeniedPrefixes {
+		if strings.HasPrefix(route, p) {
+			return ""
+		}
+	}
+	access := "write"
+	if method == http.MethodGet || method == http.MethodHead {
+		access = "read"
+	}
+	for _, r := range tokenResources {
+		for _, p := range r.prefixes {
+			if route == p || strings.HasPrefix(route, p+"/") {
+				if access == "write" && !r.writable {
+					return ""
+				}
+				return TokenScope(r.name + ":" + access)
+			}
+		}
+	}
+	return ""
+}
+
+// HasScope はAPIトークンのスコープで操作できるかを返す。JWTで認証した操作主体は制限しない
+func (a Actor) HasScope(scope TokenScope) bool {
+	if a.Scopes == nil {
+		return true
+	}
+	resource, access, _ := strings.Cut(string(scope), ":")
+	for _, s := range a.Scopes {
+		if s == scope || (access == "read" && s == TokenScope(resource+":write")) {
+			return true
+		}
+	}
+	return false
+}
+
+// authenticateAPIToken はAPIトークンを検証し、ルートに必要なスコープを確認する
+func (m *Middleware) authenticateAPIToken(c *gin.Context, token string) {
+	if m.apiTokens == nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
+			Code:    401,
+			Message: "無効なトークンです",
+		})
+		return
+	}
+	actor, err := m.apiTokens.AuthenticateAPIToken(c.Request.Context(), token)
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
+			Code:    401,
+			Message: "APIトークンが無効か有効期限が切れています",
+		})
+		return
+	}
+
+	scope := RequiredTokenScope(c.Request.Method, strings.TrimPrefix(c.FullPath(), "/api/v1"))
+	if scope == "" || !actor.HasScope(scope) {
+		message := "この操作はAPIトークンでは利用できません"
+		if scope != "" {
+			message = "APIトークンにスコープ " + string(scope) + " がありません"
+		}
+		c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
+			Code:    403,
+			Message: message,
+		})
+		return
+	}
+
+	// サービスアカウントの UserID はユーザーではないため、ハンドラーには本人として渡さない。
+	// 打刻・工数・経費の登録など、操作主体を記録の所有者とするルートは認証エラーになる
+	if !actor.ServiceAccount {
+		c.Set("userID", actor.UserID.String())
+	}
+	c.Set("role", string(actor.Role))
+	c.Request = c.Request.WithContext(WithActor(c.Request.Context(), *actor))
+	c.Next()
+}
@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+type stubAPITokens map[string]*Actor
+
+func (s stubAPITokens) AuthenticateAPIToken(ctx context.Context, token string) (*Actor, error) {
+	if a, ok := s[token]; ok {
+		return a, nil
+	}
+	return nil, errors.New("invalid token")
+}
+
+func TestRequiredTokenScope(t *testing.T) {
+	tests := []struct {
+		method, route string
+		want          TokenScope
+	}{
+		{http.MethodGet, "/export/attendance", "export:read"},
+		{http.MethodPost, "/export/attendance", ""},
+		{http.MethodGet, "/attendance", "attendance:read"},
+		{http.MethodPost, "/attendance/clock-in", "attendance:write"},
+		{http.MethodPut, "/leaves/:id/approve", "attendance:write"},
+		{http.MethodGet, "/time-entries/summary", "time_entry:read"},
+		{http.MethodGet, "/expenses/export/csv", "expense:read"},
+		{http.MethodGet, "/hr/employees", "hr:read"},
+		{http.MethodGet, "/users", "user:read"},
+		{http.MethodGet, "/users/me", "user:read"},
+		{http.MethodPut, "/users/:id", "user:write"},
+		{http.MethodGet, "/dashboard/stats", "report:read"},
+		// アカウントの設定や権限の管理はトークンで行えない
+		{http.MethodPost, "/users/me/api-tokens", ""},
+		{http.MethodGet, "/users/me/sessions", ""},
+		{http.MethodPost, "/users/:id/roles", ""},
+		{http.MethodGet, "/service-accounts", ""},
+		{http.MethodGet, "/roles", ""},
+		{http.MethodGet, "/notifications", ""},
+		{http.MethodGet, "/attendance-extra", ""},
+	}
+	for _, tt := range tests {
+		if got := RequiredTokenScope(tt.method, tt.route); got != tt.want {
+			t.Errorf("RequiredTokenScope(%s %s) = %q, want %q", tt.method, tt.route, got, tt.want)
+		}
+	}
+	for _, d := range TokenScopeDefinitions() {
+		if !IsKnownTokenScope(d.Name) {
+			t.Errorf("%s should be known", d.Name)
+		}
+	}
+}
+
+func TestActor_HasScope(t *testing.T) {
+	jwtActor := Actor{}
+	if !jwtActor.HasScope("hr:write") {
+		t.Error("JWT actor should not be limited by scopes")
+	}
+	tokenActor := Actor{Scopes: []TokenScope{"attendance:write", "export:read"}}
+	for scope, want := range map[TokenScope]bool{
+		"attendance:write": true,
+		"attendance:read":  true,
+		"export:read":      true,
+		"expense:read":     false,
+	} {
+		if got := tokenActor.HasScope(scope); got != want {
+			t.Errorf("HasScope(%s) = %v, want %v", scope, got, want)
+		}
+	}
+}
+
+func TestAuth_APIToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	m := setupTestMiddleware(t).WithAPITokens(stubAPITokens{
+		"kt_pat_valid": {UserID: userID, Role: model.RoleManager, Scopes: []TokenScope{"export:read"}},
+	})
+
+	engine := gin.New()
+	engine.Use(m.Auth())
+	handler := func(c *gin.Context) {
+		actor, _ := ActorFromContext(c.Request.Context())
+		if actor.UserID != userID || c.GetString("userID") != userID.String() || c.GetString("role") != "manager" {
+			t.Errorf("Unexpected actor %+v", actor)
+		}
+		c.Status(http.StatusOK)
+	}
+	engine.GET("/api/v1/export/attendance", handler)
+	engine.GET("/api/v1/hr/employees", handler)
+	engine.GET("/api/v1/users/me/sessions", handler)
+
+	tests := []struct {
+		token, path string
+		want        int
+	}{
+		{"kt_pat_valid", "/api/v1/export/attendance", http.StatusOK},
+		{"kt_pat_valid", "/api/v1/hr/employees", http.StatusForbidden},
+		{"kt_pat_valid", "/api/v1/users/me/sessions", http.StatusForbidden},
+		{"kt_pat_revoked", "/api/v1/export/attendance", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+tt.token)
+		engine.ServeHTTP(w, req)
+		if w.Code != tt.want {
+			t.Errorf("%s %s: expected status %d, got %d", tt.token, tt.path, tt.want, w.Code)
+		}
+	}
+
+	// サービスアカウントは本人の記録を扱うハンドラーに userID を渡さない
+	accountID := uuid.New()
+	accounts := gin.New()
+	accounts.Use(setupTestMiddleware(t).WithAPITokens(stubAPITokens{
+		"kt_sa_valid": {UserID: accountID, Role: model.RoleAdmin, ServiceAccount: true, Scopes: []TokenScope{"attendance:write"}},
+	}).Auth())
+	accounts.POST("/api/v1/attendance/clock-in", func(c *gin.Context) {
+		actor, _ := ActorFromContext(c.Request.Context())
+		if _, exists := c.Get("userID"); exists || !actor.ServiceAccount || actor.UserID != accountID {
+			t.Errorf("Expected a service account actor without userID, got %+v", actor)
+		}
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodPost, "/api/v1/attendance/clock-in", nil)
+	req.Header.Set("Authorization", "Bearer kt_sa_valid")
+	accounts.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected status %d for service account, got %d", http.StatusOK, w.Code)
+	}
+
+	// 検証が設定されていない場合はAPIトークンを受け付けない
+	plain := gin.New()
+	plain.Use(setupTestMiddleware(t).Auth())
+	plain.GET("/api/v1/export/attendance", handler)
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodGet, "/api/v1/export/attendance", nil)
+	req.Header.Set("Authorization", "Bearer kt_pat_valid")
+	plain.ServeHTTP(w, req)
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d without authenticator, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
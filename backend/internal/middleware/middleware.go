@@ -1,23 +1,30 @@
 package middleware
 
 import (
+	"context"
+	"crypto/subtle"
 	"net/http"
+	"regexp"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/revocation"
 	"github.com/your-org/kintai/backend/pkg/logger"
 	"golang.org/x/time/rate"
 )
 
 // Middleware は全ミドルウェアを束ねる構造体
 type Middleware struct {
-	config *config.Config
-	logger *logger.Logger
+	config    *config.Config
+	logger    *logger.Logger
+	denyList  *revocation.DenyList
+	apiTokens APITokenAuthenticator
 }
 
 // NewMiddleware はミドルウェアを初期化する
@@ -25,6 +32,12 @@ func NewMiddleware(cfg *config.Config, logger *logger.Logger) *Middleware {
 	return &Middleware{config: cfg, logger: logger}
 }
 
+// WithDenyList は Auth で参照するトークン失効リストを設定する
+func (m *Middleware) WithDenyList(d *revocation.DenyList) *Middleware {
+	m.denyList = d
+	return m
+}
+
 // ===== CORS =====
 
 func (m *Middleware) CORS() gin.HandlerFunc {
@@ -42,7 +55,8 @@ func (m *Middleware) CORS() gin.HandlerFunc {
 			c.Header("Access-Control-Allow-Origin", origin)
 		}
 		c.Header("Access-Control-Allow-Methods", "GET, POST, PUT, PATCH, DELETE, OPTIONS")
-		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-CSRF-Token")
+		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept, Authorization, X-CSRF-Token, X-Request-ID")
+		c.Header("Access-Control-Expose-Headers", "X-Request-ID")
 		c.Header("Access-Control-Allow-Credentials", "true")
 		c.Header("Access-Control-Max-Age", "86400")
 
@@ -57,9 +71,35 @@ func (m *Middleware) CORS() gin.HandlerFunc {
 
 // ===== JWT認証 =====
 
+// typ クレームを持つトークン（アクセストークン以外）は Auth で受け付けない
+const (
+	// TokenTypeRefresh はリフレッシュトークンの typ クレーム
+	TokenTypeRefresh = "refresh"
+	// TokenTypeMFA はログイン時のMFAチャレンジトークンの typ クレーム
+	TokenTypeMFA = "mfa"
+	// TokenTypeSSOFlow はSSOのログイン開始からコールバックまでのフロー情報（Cookie）の typ クレーム
+	TokenTypeSSOFlow = "sso_flow"
+)
+
+// Cookie認証モード（AUTH_COOKIE_MODE）で使うCookieとヘッダー
+const (
+	// AccessTokenCookie はアクセストークンを保持する HttpOnly Cookie
+	AccessTokenCookie = "access_token"
+	// RefreshTokenCookie はリフレッシュトークンを保持する HttpOnly Cookie（/api/v1/auth 以下にのみ送信する）
+	RefreshTokenCookie = "refresh_token"
+	// CSRFCookie はCSRFトークンを保持するCookie。SPAが読み取って CSRFHeader に設定する
+	CSRFCookie = "csrf_token"
+	CSRFHeader = "X-CSRF-Token"
+)
+
 func (m *Middleware) Auth() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && m.config.AuthCookieMode {
+			if cookie, err := c.Cookie(AccessTokenCookie); err == nil && cookie != "" {
+				authHeader = "Bearer " + cookie
+			}
+		}
 		if authHeader == "" {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
 				Code:    401,
@@ -76,6 +116,10 @@ func (m *Middleware) Auth() gin.HandlerFunc {
 			})
 			return
 		}
+		if strings.HasPrefix(tokenString, APITokenPrefix) {
+			m.authenticateAPIToken(c, tokenString)
+			return
+		}
 
 		token, err := jwtParse(tokenString, func(token *jwt.Token) (interface{}, error) {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
@@ -93,7 +137,7 @@ func (m *Middleware) Auth() gin.HandlerFunc {
 		}
 
 		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
+		if !ok || claims["typ"] != nil {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
 				Code:    401,
 				Message: "トークンのクレームが不正です",
@@ -103,6 +147,45 @@ func (m *Middleware) Auth() gin.HandlerFunc {
 
 		c.Set("userID", claims["sub"].(string))
 		c.Set("role", claims["role"].(string))
+		if userID, err := uuid.Parse(claims["sub"].(string)); err == nil {
+			actor := Actor{UserID: userID, Role: model.Role(claims["role"].(string))}
+			actor.TokenID, _ = claims["jti"].(string)
+			if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+				actor.TokenExpiresAt = exp.Time
+			}
+			if sid, ok := claims["sid"].(string); ok {
+				actor.SessionID, _ = uuid.Parse(sid)
+			}
+
+			// ログアウト・権限変更・セッションの終了で失効したトークンを拒否する
+			var issuedAt time.Time
+			if iat, err := claims.GetIssuedAt(); err == nil && iat != nil {
+				issuedAt = iat.Time
+			}
+			revoked, err := m.denyList.IsRevoked(c.Request.Context(), actor.TokenID, userID, issuedAt)
+			if err == nil && !revoked {
+				revoked, err = m.denyList.IsSessionRevoked(c.Request.Context(), actor.SessionID)
+			}
+			if err != nil || revoked {
+				c.AbortWithStatusJSON(http.StatusUnauthorized, model.ErrorResponse{
+					Code:    401,
+					Message: "トークンは失効しています",
+				})
+				return
+			}
+
+			// カスタムロールの権限（未知の権限は無視する）
+			if perms, ok := claims["perms"].([]interface{}); ok {
+				for _, p := range perms {
+					if s, ok := p.(string); ok {
+						if g, err := ParseGrant(s); err == nil {
+							actor.Grants = append(actor.Grants, g)
+						}
+					}
+				}
+			}
+			c.Request = c.Request.WithContext(WithActor(c.Request.Context(), actor))
+		}
 		c.Next()
 	}
 }
@@ -227,23 +310,85 @@ func (m *Middleware) RequestLogger() gin.HandlerFunc {
 			"latency", latency.String(),
 			"ip", c.ClientIP(),
 			"user_agent", c.Request.UserAgent(),
+			"request_id", c.Writer.Header().Get(RequestIDHeader),
 		)
 	}
 }
 
+// ===== クライアント情報 =====
+
+type clientInfoKey struct{}
+
+// RequestIDHeader はリクエストIDのヘッダー。受け取った値（英数字・記号 -_. のみ、64文字まで）を引き継ぎ、
+// なければ生成して応答に付ける
+const RequestIDHeader = "X-Request-ID"
+
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ClientInfo はリクエスト元のIPアドレスとUser-Agent、リクエストID（ログイン履歴・監査ログなどに記録する）
+type ClientInfo struct {
+	IPAddress string
+	UserAgent string
+	RequestID string
+}
+
+// WithClientInfo はクライアント情報を持つコンテキストを返す
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// ClientInfoFromContext はクライアント情報を返す。設定されていない場合は空
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
+
+// ClientInfo はリクエストのコンテキストにクライアント情報を設定する
+func (m *Middleware) ClientInfo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ua := c.Request.UserAgent()
+		if len(ua) > 500 {
+			ua = strings.ToValidUTF8(ua[:500], "")
+		}
+		requestID := c.GetHeader(RequestIDHeader)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = uuid.NewString()
+		}
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(WithClientInfo(c.Request.Context(), ClientInfo{
+			IPAddress: c.ClientIP(),
+			UserAgent: ua,
+			RequestID: requestID,
+		}))
+		c.Next()
+	}
+}
+
 // ===== CSRF =====
 
+// CookieAuthEnabled はCookie認証モード（AUTH_COOKIE_MODE）が有効かを返す。
+// 有効な場合、Cookieで送られるトークンを悪用されないよう /api/v1 に CSRF を適用する
+func (m *Middleware) CookieAuthEnabled() bool {
+	return m.config != nil && m.config.AuthCookieMode
+}
+
+// CSRF は状態を変更するリクエストで CSRFHeader と CSRFCookie の一致を確認する（ダブルサブミット）。
+// Authorization ヘッダーで認証するリクエストはブラウザが自動で資格情報を送らないため対象外
 func (m *Middleware) CSRF() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if c.Request.Method == "GET" || c.Request.Method == "HEAD" || c.Request.Method == "OPTIONS" {
 			c.Next()
 			return
 		}
+		if c.GetHeader("Authorization") != "" {
+			c.Next()
+			return
+		}
 
-		token := c.GetHeader("X-CSRF-Token")
-		cookie, err := c.Cookie("csrf_token")
+		token := c.GetHeader(CSRFHeader)
+		cookie, err := c.Cookie(CSRFCookie)
 
-		if err != nil || token == "" || token != cookie {
+		if err != nil || token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(cookie)) != 1 {
 			c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
 				Code:    403,
 				Message: "CSRFトークンが不正です",
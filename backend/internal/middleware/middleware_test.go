@@ -1,15 +1,19 @@
 package middleware
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
 	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/revocation"
 	"github.com/your-org/kintai/backend/pkg/logger"
 )
 
@@ -224,6 +228,54 @@ func TestAuth_WrongSigningMethod(t *testing.T) {
 	}
 }
 
+func TestAuth_RejectsRefreshToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "test-user-id",
+		"role": "employee",
+		"typ":  TokenTypeRefresh,
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+
+	m.Auth()(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
+func TestAuth_RejectsMFAChallengeToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":     "test-user-id",
+		"typ":     TokenTypeMFA,
+		"purpose": "verify",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+
+	m.Auth()(c)
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
+	}
+}
+
 // ===== RequireRole Tests =====
 
 func TestRequireRole_Success(t *testing.T) {
@@ -341,6 +393,72 @@ func TestRequestLogger(t *testing.T) {
 	handler(c)
 }
 
+// ===== ClientInfo Tests =====
+
+func TestClientInfo(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	var got ClientInfo
+	r := gin.New()
+	r.Use(m.ClientInfo())
+	r.GET("/test", func(c *gin.Context) {
+		got = ClientInfoFromContext(c.Request.Context())
+	})
+
+	req, _ := http.NewRequest("GET", "/test", nil)
+	req.RemoteAddr = "192.0.2.10:54321"
+	req.Header.Set("User-Agent", strings.Repeat("a", 600))
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if got.IPAddress != "192.0.2.10" {
+		t.Errorf("Expected client IP 192.0.2.10, got %q", got.IPAddress)
+	}
+	if len(got.UserAgent) != 500 {
+		t.Errorf("Expected user agent truncated to 500 bytes, got %d", len(got.UserAgent))
+	}
+	if (ClientInfoFromContext(context.Background()) != ClientInfo{}) {
+		t.Error("Expected empty client info without middleware")
+	}
+}
+
+func TestClientInfo_RequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	var got ClientInfo
+	r := gin.New()
+	r.Use(m.ClientInfo())
+	r.GET("/test", func(c *gin.Context) {
+		got = ClientInfoFromContext(c.Request.Context())
+	})
+
+	cases := []struct {
+		header string
+		keep   bool
+	}{
+		{"req-123.abc_DEF", true},
+		{"", false},
+		{"bad id\n", false},
+		{strings.Repeat("a", 65), false},
+	}
+	for _, tc := range cases {
+		req, _ := http.NewRequest("GET", "/test", nil)
+		if tc.header != "" {
+			req.Header.Set(RequestIDHeader, tc.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got.RequestID == "" || w.Header().Get(RequestIDHeader) != got.RequestID {
+			t.Errorf("%q: expected request ID in context and response, got %q / %q", tc.header, got.RequestID, w.Header().Get(RequestIDHeader))
+		}
+		if (got.RequestID == tc.header) != tc.keep {
+			t.Errorf("%q: expected keep=%v, got %q", tc.header, tc.keep, got.RequestID)
+		}
+	}
+}
+
 // ===== CSRF Tests =====
 
 func TestCSRF_GetRequest(t *testing.T) {
@@ -443,6 +561,52 @@ func TestCSRF_PostWithMatchingToken(t *testing.T) {
 	}
 }
 
+func TestCSRF_PostWithAuthorizationHeader(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("POST", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer token")
+
+	handler := m.CSRF()
+	handler(c)
+
+	if c.IsAborted() {
+		t.Error("Request authenticated by the Authorization header should not be CSRF checked")
+	}
+}
+
+func TestAuth_CookieMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  "test-user-id",
+		"role": "employee",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+
+	for _, cookieMode := range []bool{true, false} {
+		m := setupTestMiddleware(t)
+		m.config.AuthCookieMode = cookieMode
+
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/test", nil)
+		c.Request.AddCookie(&http.Cookie{Name: AccessTokenCookie, Value: tokenString})
+
+		m.Auth()(c)
+
+		if c.IsAborted() == cookieMode {
+			t.Errorf("cookie mode %v: expected access token cookie accepted=%v, got status %d", cookieMode, cookieMode, w.Code)
+		}
+		if m.CookieAuthEnabled() != cookieMode {
+			t.Errorf("Expected CookieAuthEnabled %v", cookieMode)
+		}
+	}
+}
+
 // ===== Recovery Tests =====
 
 func TestRecovery_NoPanic(t *testing.T) {
@@ -544,3 +708,81 @@ func TestAuth_ExpiredToken(t *testing.T) {
 		t.Errorf("Expected status %d, got %d", http.StatusUnauthorized, w.Code)
 	}
 }
+
+func TestAuth_RejectsRevokedToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	denyList := revocation.NewDenyList(nil, nil)
+	m := setupTestMiddleware(t).WithDenyList(denyList)
+
+	issue := func(jti string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  "00000000-0000-0000-0000-000000000001",
+			"role": "employee",
+			"jti":  jti,
+			"iat":  time.Now().Unix(),
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		})
+		s, _ := token.SignedString([]byte("test-secret-key"))
+		return s
+	}
+	call := func(tokenString string) int {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/test", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+		m.Auth()(c)
+		if c.IsAborted() {
+			return w.Code
+		}
+		return http.StatusOK
+	}
+
+	_ = denyList.RevokeToken(context.Background(), "revoked-jti", time.Now().Add(time.Hour))
+	if code := call(issue("revoked-jti")); code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for revoked token, got %d", http.StatusUnauthorized, code)
+	}
+	if code := call(issue("valid-jti")); code != http.StatusOK {
+		t.Errorf("Expected valid token to pass, got %d", code)
+	}
+}
+
+func TestAuth_RejectsRevokedSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	denyList := revocation.NewDenyList(nil, nil)
+	m := setupTestMiddleware(t).WithDenyList(denyList)
+	revokedSession, activeSession := uuid.New(), uuid.New()
+
+	call := func(sessionID uuid.UUID) (int, Actor) {
+		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+			"sub":  "00000000-0000-0000-0000-000000000001",
+			"role": "employee",
+			"sid":  sessionID.String(),
+			"jti":  uuid.NewString(),
+			"iat":  time.Now().Unix(),
+			"exp":  time.Now().Add(time.Hour).Unix(),
+		})
+		s, _ := token.SignedString([]byte("test-secret-key"))
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/test", nil)
+		c.Request.Header.Set("Authorization", "Bearer "+s)
+		m.Auth()(c)
+		if c.IsAborted() {
+			return w.Code, Actor{}
+		}
+		actor, _ := ActorFromContext(c.Request.Context())
+		return http.StatusOK, actor
+	}
+
+	_ = denyList.RevokeSession(context.Background(), revokedSession, time.Hour)
+	if code, _ := call(revokedSession); code != http.StatusUnauthorized {
+		t.Errorf("Expected status %d for revoked session, got %d", http.StatusUnauthorized, code)
+	}
+	code, actor := call(activeSession)
+	if code != http.StatusOK {
+		t.Fatalf("Expected active session to pass, got %d", code)
+	}
+	if actor.SessionID != activeSession {
+		t.Errorf("Expected session ID %s, got %s", activeSession, actor.SessionID)
+	}
+}
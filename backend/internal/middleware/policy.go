@@ -0,0 +1,371 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// ===== 認可ポリシー =====
+
+// Permission は操作単位の権限
+type Permission string
+
+const (
+	PermAttendanceApprove  Permission = "attendance:approve"
+	PermAttendanceAdmin    Permission = "attendance:admin"
+	PermUserManage         Permission = "user:manage"
+	PermRoleManage         Permission = "role:manage"
+	PermSecurityManage     Permission = "security:manage"
+	PermAuditRead          Permission = "audit:read"
+	PermShiftManage        Permission = "shift:manage"
+	PermProjectManage      Permission = "project:manage"
+	PermHolidayManage      Permission = "holiday:manage"
+	PermApprovalFlowManage Permission = "approval_flow:manage"
+	PermReportRead         Permission = "report:read"
+	PermTimeEntryWrite     Permission = "time_entry:write"
+	PermExpenseRead        Permission = "expense:read"
+	PermExpenseWrite       Permission = "expense:write"
+	PermExpenseApprove     Permission = "expense:approve"
+	PermExpenseReimburse   Permission = "expense:reimburse"
+	PermExpenseReport      Permission = "expense:report"
+	PermExpenseAdmin       Permission = "expense:admin"
+	PermHRRead             Permission = "hr:read"
+	PermHRManage           Permission = "hr:manage"
+	PermHRGoalWrite        Permission = "hr:goal:write"
+	PermHREvaluate         Permission = "hr:evaluate"
+	PermHROneOnOne         Permission = "hr:one_on_one"
+	PermSalaryRead         Permission = "salary:read"
+	PermSalaryManage       Permission = "salary:manage"
+)
+
+// permissionCatalog はカスタムロールに設定できる権限の一覧（表示順）
+var permissionCatalog = []struct {
+	perm        Permission
+	description string
+}{
+	{PermAttendanceApprove, "休暇・残業・勤怠修正・振替休日の承認"},
+	{PermAttendanceAdmin, "労働時間制度・在宅勤務ポリシーの設定"},
+	{PermUserManage, "ユーザーの管理"},
+	{PermRoleManage, "カスタムロールと割り当ての管理"},
+	{PermSecurityManage, "多要素認証ポリシーなどセキュリティ設定の管理"},
+	{PermAuditRead, "監査ログの閲覧・エクスポート・改ざん検証"},
+	{PermShiftManage, "シフト・シフト表の管理"},
+	{PermProjectManage, "プロジェクトの管理"},
+	{PermHolidayManage, "休日カレンダーの管理"},
+	{PermApprovalFlowManage, "承認フローの管理"},
+	{PermReportRead, "勤怠・工数のレポートとエクスポート"},
+	{PermTimeEntryWrite, "工数記録の編集・削除"},
+	{PermExpenseRead, "経費申請の閲覧"},
+	{PermExpenseWrite, "経費申請の編集・削除"},
+	{PermExpenseApprove, "経費申請の承認"},
+	{PermExpenseReimburse, "経費の精算処理"},
+	{PermExpenseReport, "経費レポートとエクスポート"},
+	{PermExpenseAdmin, "経費ポリシーの管理"},
+	{PermHRRead, "人事情報の閲覧"},
+	{PermHRManage, "人事情報の管理"},
+	{PermHRGoalWrite, "目標の管理"},
+	{PermHREvaluate, "人事評価の入力"},
+	{PermHROneOnOne, "1on1の管理"},
+	{PermSalaryRead, "給与情報の閲覧"},
+	{PermSalaryManage, "給与シミュレーション"},
+}
+
+// Scope は権限が及ぶ範囲（本人 < 直属の部下 < 全社）
+type Scope int
+
+const (
+	ScopeNone Scope = iota
+	ScopeOwn
+	ScopeTeam
+	ScopeAll
+)
+
+func (s Scope) String() string {
+	switch s {
+	case ScopeOwn:
+		return "own"
+	case ScopeTeam:
+		return "team"
+	case ScopeAll:
+		return "all"
+	}
+	return "none"
+}
+
+// rolePolicies は基本ロールごとの権限と範囲の対応表
+var rolePolicies = map[model.Role]map[Permission]Scope{
+	model.RoleEmployee: {
+		PermTimeEntryWrite: ScopeOwn,
+		PermExpenseRead:    ScopeOwn,
+		PermExpenseWrite:   ScopeOwn,
+		PermHRGoalWrite:    ScopeOwn,
+	},
+	model.RoleManager: {
+		PermAttendanceApprove:  ScopeTeam,
+		PermUserManage:         ScopeAll,
+		PermShiftManage:        ScopeAll,
+		PermProjectManage:      ScopeAll,
+		PermHolidayManage:      ScopeAll,
+		PermApprovalFlowManage: ScopeAll,
+		PermReportRead:         ScopeAll,
+		PermTimeEntryWrite:     ScopeOwn,
+		PermExpenseRead:        ScopeTeam,
+		PermExpenseWrite:       ScopeOwn,
+		PermExpenseApprove:     ScopeTeam,
+		PermExpenseReport:      ScopeAll,
+		PermHRRead:             ScopeAll,
+		PermHRGoalWrite:        ScopeTeam,
+		PermHREvaluate:         ScopeTeam,
+		PermHROneOnOne:         ScopeTeam,
+	},
+	model.RoleAdmin: {},
+}
+
+func init() {
+	// 管理者はすべての権限を全社範囲で持つ
+	for _, p := range permissionCatalog {
+		rolePolicies[model.RoleAdmin][p.perm] = ScopeAll
+	}
+}
+
+// ErrForbidden は権限不足を表す
+var ErrForbidden = errors.New("この操作を行う権限がありません")
+
+// ScopeOf は基本ロールが持つ権限の範囲を返す
+func ScopeOf(role model.Role, perm Permission) Scope {
+	return rolePolicies[role][perm]
+}
+
+// IsKnownPermission は権限名が定義済みかを返す
+func IsKnownPermission(name string) bool {
+	for _, p := range permissionCatalog {
+		if string(p.perm) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionDefinitions は権限の定義と基本ロールごとの範囲を返す
+func PermissionDefinitions() []model.PermissionDefinition {
+	roles := []model.Role{model.RoleAdmin, model.RoleManager, model.RoleEmployee}
+	defs := make([]model.PermissionDefinition, 0, len(permissionCatalog))
+	for _, p := range permissionCatalog {
+		scopes := make(map[model.Role]string)
+		for _, r := range roles {
+			if s := ScopeOf(r, p.perm); s != ScopeNone {
+				scopes[r] = s.String()
+			}
+		}
+		defs = append(defs, model.PermissionDefinition{Name: string(p.perm), Description: p.description, RoleScopes: scopes})
+	}
+	return defs
+}
+
+// ===== カスタムロールによる付与 =====
+
+// Grant はカスタムロールで付与された権限。DepartmentID が nil なら全社範囲
+type Grant struct {
+	Permission   Permission
+	DepartmentID *uuid.UUID
+}
+
+// String はJWTクレームでの表現（"権限" または "権限@部署ID"）を返す
+func (g Grant) String() string {
+	if g.DepartmentID == nil {
+		return string(g.Permission)
+	}
+	return string(g.Permission) + "@" + g.DepartmentID.String()
+}
+
+// ParseGrant は Grant.String の表現を解析する
+func ParseGrant(s string) (Grant, error) {
+	name, dept, scoped := strings.Cut(s, "@")
+	if !IsKnownPermission(name) {
+		return Grant{}, errors.New("unknown permission: " + name)
+	}
+	g := Grant{Permission: Permission(name)}
+	if scoped {
+		id, err := uuid.Parse(dept)
+		if err != nil {
+			return Grant{}, err
+		}
+		g.DepartmentID = &id
+	}
+	return g, nil
+}
+
+// Resolver はリソース所有者と操作主体の組織上の関係を解決する。
+// 未設定の関数に対応する範囲は一致しないものとして扱う
+type Resolver struct {
+	// IsTeamMember は ownerID が managerID の直属の部下かを返す
+	IsTeamMember func(ctx context.Context, managerID, ownerID uuid.UUID) (bool, error)
+	// DepartmentOf は ownerID の所属部署を返す
+	DepartmentOf func(ctx context.Context, ownerID uuid.UUID) (*uuid.UUID, error)
+}
+
+// Actor はリクエストの操作主体
+type Actor struct {
+	UserID uuid.UUID
+	Role   model.Role
+	Grants []Grant
+	// TokenID と TokenExpiresAt は認証に使ったアクセストークンの jti と有効期限
+	TokenID        string
+	TokenExpiresAt time.Time
+	// SessionID はアクセストークンの sid（リフレッシュトークンのファミリーID）
+	SessionID uuid.UUID
+	// APITokenID と Scopes はAPIトークンで認証した場合のトークンとスコープ（JWTの場合は Scopes が nil）
+	APITokenID uuid.UUID
+	Scopes     []TokenScope
+	// ServiceAccount は UserID がサービスアカウントのIDであることを表す
+	ServiceAccount bool
+}
+
+// Can は範囲を問わず権限を持つかを返す
+func (a Actor) Can(perm Permission) bool {
+	if ScopeOf(a.Role, perm) > ScopeNone {
+		return true
+	}
+	for _, g := range a.Grants {
+		if g.Permission == perm {
+			return true
+		}
+	}
+	return false
+}
+
+// ScopeFor は基本ロールとカスタムロールの付与を合わせた権限の範囲と、
+// 部署を指定して付与された部署の一覧を返す（部署を指定しない付与は全社範囲）
+func (a Actor) ScopeFor(perm Permission) (Scope, []uuid.UUID) {
+	scope := ScopeOf(a.Role, perm)
+	var departments []uuid.UUID
+	for _, g := range a.Grants {
+		if g.Permission != perm {
+			continue
+		}
+		if g.DepartmentID == nil {
+			scope = ScopeAll
+		} else {
+			departments = append(departments, *g.DepartmentID)
+		}
+	}
+	return scope, departments
+}
+
+// Authorize は ownerID が所有するリソースへの操作を判定する。
+// 所有者を持たないリソースは ownerID に uuid.Nil を渡し、全社範囲の権限を要求する
+func (a Actor) Authorize(ctx context.Context, perm Permission, ownerID uuid.UUID, r Resolver) error {
+	scope, departments := a.ScopeFor(perm)
+	if scope == ScopeAll {
+		return nil
+	}
+	if ownerID == uuid.Nil {
+		return ErrForbidden
+	}
+	if scope >= ScopeOwn && ownerID == a.UserID {
+		return nil
+	}
+	if scope == ScopeTeam && r.IsTeamMember != nil {
+		ok, err := r.IsTeamMember(ctx, a.UserID, ownerID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+	}
+	if len(departments) > 0 && r.DepartmentOf != nil {
+		dept, err := r.DepartmentOf(ctx, ownerID)
+		if err != nil {
+			return err
+		}
+		for _, d := range departments {
+			if dept != nil && *dept == d {
+				return nil
+			}
+		}
+	}
+	return ErrForbidden
+}
+
+type actorKey struct{}
+
+// WithActor はコンテキストに操作主体を設定する
+func WithActor(ctx context.Context, a Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, a)
+}
+
+// ActorFromContext はコンテキストから操作主体を取り出す
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	a, ok := ctx.Value(actorKey{}).(Actor)
+	return a, ok
+}
+
+type systemKey struct{}
+
+// WithSystem はバッチ処理などリクエストの操作主体を持たない内部処理のコンテキストを返す
+func WithSystem(ctx context.Context) context.Context {
+	return context.WithValue(ctx, systemKey{}, true)
+}
+
+// IsSystem は ctx が操作主体を持たない内部処理（WithSystem）のコンテキストかを返す
+func IsSystem(ctx context.Context) bool {
+	if _, ok := ActorFromContext(ctx); ok {
+		return false
+	}
+	system, _ := ctx.Value(systemKey{}).(bool)
+	return system
+}
+
+// Authorize はコンテキストの操作主体で Actor.Authorize を評価する。
+// 操作主体を持たないコンテキストは、内部処理（WithSystem）の場合に限り制限しない
+func Authorize(ctx context.Context, perm Permission, ownerID uuid.UUID, r Resolver) error {
+	a, ok := ActorFromContext(ctx)
+	if !ok {
+		if IsSystem(ctx) {
+			return nil
+		}
+		return ErrForbidden
+	}
+	return a.Authorize(ctx, perm, ownerID, r)
+}
+
+// ===== 権限ベースアクセス制御 =====
+
+// RequirePermission はいずれかの権限を持つ操作主体のみ通過させる。
+// 本人・チーム・部署範囲の判定はサービス層で行う
+func (m *Middleware) RequirePermission(perms ...Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor, ok := ActorFromContext(c.Request.Context())
+		if !ok {
+			roleStr, exists := c.Get("role")
+			if !exists {
+				c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
+					Code:    403,
+					Message: "権限がありません",
+				})
+				return
+			}
+			actor = Actor{Role: model.Role(roleStr.(string))}
+		}
+
+		for _, p := range perms {
+			if actor.Can(p) {
+				c.Next()
+				return
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, model.ErrorResponse{
+			Code:    403,
+			Message: ErrForbidden.Error(),
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// ===== RequirePermission Tests =====
+
+func TestRequirePermission_ManagerAllowed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Set("role", "manager")
+
+	m.RequirePermission(PermExpenseApprove)(c)
+
+	if c.IsAborted() {
+		t.Error("Request should not be aborted for manager with expense approval")
+	}
+}
+
+func TestRequirePermission_Forbidden(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	cases := []struct {
+		role string
+		perm Permission
+	}{
+		{"employee", PermHRRead},
+		{"employee", PermExpenseApprove},
+		{"manager", PermSalaryManage},
+		{"manager", PermAttendanceAdmin},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request, _ = http.NewRequest("GET", "/test", nil)
+		c.Set("role", tc.role)
+
+		m.RequirePermission(tc.perm)(c)
+
+		if w.Code != http.StatusForbidden {
+			t.Errorf("%s/%s: expected status %d, got %d", tc.role, tc.perm, http.StatusForbidden, w.Code)
+		}
+	}
+}
+
+func TestRequirePermission_NoRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+
+	m.RequirePermission(PermHRRead)(c)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected status %d, got %d", http.StatusForbidden, w.Code)
+	}
+}
+
+// ===== Actor Tests =====
+
+func TestActorAuthorize_Scopes(t *testing.T) {
+	ctx := context.Background()
+	self, report, other := uuid.New(), uuid.New(), uuid.New()
+	team := Resolver{IsTeamMember: func(_ context.Context, managerID, ownerID uuid.UUID) (bool, error) {
+		return managerID == self && ownerID == report, nil
+	}}
+
+	employee := Actor{UserID: self, Role: model.RoleEmployee}
+	if err := employee.Authorize(ctx, PermExpenseWrite, self, team); err != nil {
+		t.Errorf("Employee should write own expense: %v", err)
+	}
+	if err := employee.Authorize(ctx, PermExpenseWrite, other, team); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Employee should not write others' expense, got %v", err)
+	}
+
+	manager := Actor{UserID: self, Role: model.RoleManager}
+	if err := manager.Authorize(ctx, PermExpenseApprove, report, team); err != nil {
+		t.Errorf("Manager should approve direct report: %v", err)
+	}
+	if err := manager.Authorize(ctx, PermExpenseApprove, other, team); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Manager should not approve outsider, got %v", err)
+	}
+	if err := manager.Authorize(ctx, PermExpenseApprove, uuid.Nil, team); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Team scope should not cover unowned resources, got %v", err)
+	}
+
+	admin := Actor{UserID: self, Role: model.RoleAdmin}
+	if err := admin.Authorize(ctx, PermExpenseApprove, other, Resolver{}); err != nil {
+		t.Errorf("Admin should approve anyone: %v", err)
+	}
+}
+
+func TestAuthorize_WithoutActor(t *testing.T) {
+	if err := Authorize(context.Background(), PermSalaryManage, uuid.New(), Resolver{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without actor, got %v", err)
+	}
+	if err := Authorize(WithSystem(context.Background()), PermSalaryManage, uuid.New(), Resolver{}); err != nil {
+		t.Errorf("Expected no restriction in a system context, got %v", err)
+	}
+
+	// 操作主体がある場合は内部処理のコンテキストでも操作主体で判定する
+	ctx := WithActor(WithSystem(context.Background()), Actor{UserID: uuid.New(), Role: model.RoleEmployee})
+	if IsSystem(ctx) {
+		t.Error("Expected a context with an actor not to be a system context")
+	}
+	if err := Authorize(ctx, PermSalaryManage, uuid.Nil, Resolver{}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for employee, got %v", err)
+	}
+}
+
+func TestAuth_SetsActor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	userID := uuid.New()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  userID.String(),
+		"role": "manager",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+
+	m.Auth()(c)
+
+	actor, ok := ActorFromContext(c.Request.Context())
+	if !ok {
+		t.Fatal("Expected actor in request context")
+	}
+	if actor.UserID != userID || actor.Role != model.RoleManager {
+		t.Errorf("Unexpected actor: %+v", actor)
+	}
+}
+
+// ===== カスタムロール Tests =====
+
+func TestAuth_ParsesPermissionGrants(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	m := setupTestMiddleware(t)
+
+	deptID := uuid.New()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   uuid.New().String(),
+		"role":  "employee",
+		"perms": []string{"salary:read", "expense:reimburse@" + deptID.String(), "unknown:perm"},
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	})
+	tokenString, _ := token.SignedString([]byte("test-secret-key"))
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request, _ = http.NewRequest("GET", "/test", nil)
+	c.Request.Header.Set("Authorization", "Bearer "+tokenString)
+
+	m.Auth()(c)
+
+	actor, _ := ActorFromContext(c.Request.Context())
+	if len(actor.Grants) != 2 {
+		t.Fatalf("Expected 2 grants, got %+v", actor.Grants)
+	}
+	if !actor.Can(PermSalaryRead) || !actor.Can(PermExpenseReimburse) {
+		t.Error("Employee with custom role should hold granted permissions")
+	}
+	if actor.Can(PermExpenseApprove) {
+		t.Error("Custom role should not grant unrelated permissions")
+	}
+}
+
+func TestActorAuthorize_DepartmentGrant(t *testing.T) {
+	ctx := context.Background()
+	deptA, deptB := uuid.New(), uuid.New()
+	inA, inB := uuid.New(), uuid.New()
+	r := Resolver{DepartmentOf: func(_ context.Context, ownerID uuid.UUID) (*uuid.UUID, error) {
+		if ownerID == inA {
+			return &deptA, nil
+		}
+		return &deptB, nil
+	}}
+
+	actor := Actor{UserID: uuid.New(), Role: model.RoleEmployee, Grants: []Grant{{Permission: PermExpenseReimburse, DepartmentID: &deptA}}}
+	if err := actor.Authorize(ctx, PermExpenseReimburse, inA, r); err != nil {
+		t.Errorf("Department grant should cover its members: %v", err)
+	}
+	if err := actor.Authorize(ctx, PermExpenseReimburse, inB, r); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Department grant should not cover other departments, got %v", err)
+	}
+
+	global := Actor{UserID: uuid.New(), Role: model.RoleEmployee, Grants: []Grant{{Permission: PermExpenseReimburse}}}
+	if err := global.Authorize(ctx, PermExpenseReimburse, inB, Resolver{}); err != nil {
+		t.Errorf("Global grant should cover everyone: %v", err)
+	}
+}
+
+func TestParseGrant(t *testing.T) {
+	deptID := uuid.New()
+	g, err := ParseGrant(Grant{Permission: PermHRRead, DepartmentID: &deptID}.String())
+	if err != nil || g.Permission != PermHRRead || g.DepartmentID == nil || *g.DepartmentID != deptID {
+		t.Errorf("Round trip failed: %+v, %v", g, err)
+	}
+	if _, err := ParseGrant("hr:read@not-a-uuid"); err == nil {
+		t.Error("Expected error for invalid department")
+	}
+	if _, err := ParseGrant("nope"); err == nil {
+		t.Error("Expected error for unknown permission")
+	}
+}
@@ -1070,7 +1070,7 @@ func (m *MockExpensePolicyService) GetPolicyViolations(ctx context.Context, user
 // ===== MockExpenseNotificationService =====
 
 type MockExpenseNotificationService struct {
-	GetNotificationsFunc func(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error)
+	GetNotificationsFunc func(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error)
 	MarkAsReadFunc       func(ctx context.Context, id uuid.UUID) error
 	MarkAllAsReadFunc    func(ctx context.Context, userID uuid.UUID) error
 	GetRemindersFunc     func(ctx context.Context, userID uuid.UUID) ([]model.ExpenseReminder, error)
@@ -1079,7 +1079,7 @@ type MockExpenseNotificationService struct {
 	UpdateSettingsFunc   func(ctx context.Context, userID uuid.UUID, req *model.ExpenseNotificationSettingRequest) (*model.ExpenseNotificationSetting, error)
 }
 
-func (m *MockExpenseNotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
+func (m *MockExpenseNotificationService) GetNotifications(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error) {
 	if m.GetNotificationsFunc != nil {
 		return m.GetNotificationsFunc(ctx, userID, filter)
 	}
@@ -1284,6 +1284,170 @@ func (m *MockAttendanceCorrectionService) GetPending(ctx context.Context, page,
 	return nil, 0, nil
 }
 
+// ===== MockSubstituteHolidayService =====
+
+type MockSubstituteHolidayService struct {
+	CreateFunc              func(ctx context.Context, userID uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error)
+	ApproveFunc             func(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.SubstituteHolidayApproval) (*model.SubstituteHolidayRequest, error)
+	GetByUserFunc           func(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	GetPendingFunc          func(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error)
+	GetCompensatoryDaysFunc func(ctx context.Context, userID uuid.UUID) (*model.CompensatoryDaySummary, error)
+}
+
+func (m *MockSubstituteHolidayService) Create(ctx context.Context, userID uuid.UUID, req *model.SubstituteHolidayRequestCreate) (*model.SubstituteHolidayRequest, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockSubstituteHolidayService) Approve(ctx context.Context, id uuid.UUID, approverID uuid.UUID, req *model.SubstituteHolidayApproval) (*model.SubstituteHolidayRequest, error) {
+	if m.ApproveFunc != nil {
+		return m.ApproveFunc(ctx, id, approverID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockSubstituteHolidayService) GetByUser(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	if m.GetByUserFunc != nil {
+		return m.GetByUserFunc(ctx, userID, page, pageSize)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockSubstituteHolidayService) GetPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	if m.GetPendingFunc != nil {
+		return m.GetPendingFunc(ctx, page, pageSize)
+	}
+	return nil, 0, nil
+}
+
+func (m *MockSubstituteHolidayService) GetCompensatoryDays(ctx context.Context, userID uuid.UUID) (*model.CompensatoryDaySummary, error) {
+	if m.GetCompensatoryDaysFunc != nil {
+		return m.GetCompensatoryDaysFunc(ctx, userID)
+	}
+	return &model.CompensatoryDaySummary{}, nil
+}
+
+// ===== MockWorkingHourPolicyService =====
+
+type MockWorkingHourPolicyService struct {
+	ListFunc            func(ctx context.Context) ([]model.WorkingHourPolicy, error)
+	GetByDepartmentFunc func(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error)
+	SaveFunc            func(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourPolicyRequest) (*model.WorkingHourPolicy, error)
+	GetScheduleFunc     func(ctx context.Context, departmentID uuid.UUID, date time.Time) ([]model.WorkingHourScheduleDay, error)
+	SetScheduleFunc     func(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourScheduleRequest) ([]model.WorkingHourScheduleDay, error)
+	GetSettlementFunc   func(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error)
+}
+
+func (m *MockWorkingHourPolicyService) List(ctx context.Context) ([]model.WorkingHourPolicy, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []model.WorkingHourPolicy{}, nil
+}
+
+func (m *MockWorkingHourPolicyService) GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error) {
+	if m.GetByDepartmentFunc != nil {
+		return m.GetByDepartmentFunc(ctx, departmentID)
+	}
+	return nil, nil
+}
+
+func (m *MockWorkingHourPolicyService) Save(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourPolicyRequest) (*model.WorkingHourPolicy, error) {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, departmentID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockWorkingHourPolicyService) GetSchedule(ctx context.Context, departmentID uuid.UUID, date time.Time) ([]model.WorkingHourScheduleDay, error) {
+	if m.GetScheduleFunc != nil {
+		return m.GetScheduleFunc(ctx, departmentID, date)
+	}
+	return []model.WorkingHourScheduleDay{}, nil
+}
+
+func (m *MockWorkingHourPolicyService) SetSchedule(ctx context.Context, departmentID uuid.UUID, req *model.WorkingHourScheduleRequest) ([]model.WorkingHourScheduleDay, error) {
+	if m.SetScheduleFunc != nil {
+		return m.SetScheduleFunc(ctx, departmentID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockWorkingHourPolicyService) GetSettlement(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.WorkingHourSettlementReport, error) {
+	if m.GetSettlementFunc != nil {
+		return m.GetSettlementFunc(ctx, departmentID, date)
+	}
+	return nil, nil
+}
+
+// ===== MockRemoteWorkPolicyService =====
+
+type MockRemoteWorkPolicyService struct {
+	ListFunc            func(ctx context.Context) ([]model.RemoteWorkPolicy, error)
+	GetByDepartmentFunc func(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error)
+	SaveFunc            func(ctx context.Context, departmentID uuid.UUID, req *model.RemoteWorkPolicyRequest) (*model.RemoteWorkPolicy, error)
+}
+
+func (m *MockRemoteWorkPolicyService) List(ctx context.Context) ([]model.RemoteWorkPolicy, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []model.RemoteWorkPolicy{}, nil
+}
+
+func (m *MockRemoteWorkPolicyService) GetByDepartment(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error) {
+	if m.GetByDepartmentFunc != nil {
+		return m.GetByDepartmentFunc(ctx, departmentID)
+	}
+	return nil, nil
+}
+
+func (m *MockRemoteWorkPolicyService) Save(ctx context.Context, departmentID uuid.UUID, req *model.RemoteWorkPolicyRequest) (*model.RemoteWorkPolicy, error) {
+	if m.SaveFunc != nil {
+		return m.SaveFunc(ctx, departmentID, req)
+	}
+	return nil, nil
+}
+
+// ===== MockTeamService =====
+
+type MockTeamService struct {
+	GetMembersFunc     func(ctx context.Context, managerID uuid.UUID) ([]model.User, error)
+	GetTodayStatusFunc func(ctx context.Context, managerID uuid.UUID) ([]model.TeamMemberStatus, error)
+	GetSummariesFunc   func(ctx context.Context, managerID uuid.UUID, start, end time.Time) ([]model.TeamMemberSummary, error)
+	GetPendingFunc     func(ctx context.Context, managerID uuid.UUID) (*model.TeamPendingRequests, error)
+}
+
+func (m *MockTeamService) GetMembers(ctx context.Context, managerID uuid.UUID) ([]model.User, error) {
+	if m.GetMembersFunc != nil {
+		return m.GetMembersFunc(ctx, managerID)
+	}
+	return []model.User{}, nil
+}
+
+func (m *MockTeamService) GetTodayStatus(ctx context.Context, managerID uuid.UUID) ([]model.TeamMemberStatus, error) {
+	if m.GetTodayStatusFunc != nil {
+		return m.GetTodayStatusFunc(ctx, managerID)
+	}
+	return []model.TeamMemberStatus{}, nil
+}
+
+func (m *MockTeamService) GetSummaries(ctx context.Context, managerID uuid.UUID, start, end time.Time) ([]model.TeamMemberSummary, error) {
+	if m.GetSummariesFunc != nil {
+		return m.GetSummariesFunc(ctx, managerID, start, end)
+	}
+	return []model.TeamMemberSummary{}, nil
+}
+
+func (m *MockTeamService) GetPending(ctx context.Context, managerID uuid.UUID) (*model.TeamPendingRequests, error) {
+	if m.GetPendingFunc != nil {
+		return m.GetPendingFunc(ctx, managerID)
+	}
+	return &model.TeamPendingRequests{}, nil
+}
+
 // ===== MockProjectService =====
 
 type MockProjectService struct {
@@ -1503,6 +1667,7 @@ type MockExportService struct {
 	ExportLeavesCSVFunc     func(ctx context.Context, userID *uuid.UUID, start, end time.Time) ([]byte, error)
 	ExportOvertimeCSVFunc   func(ctx context.Context, start, end time.Time) ([]byte, error)
 	ExportProjectsCSVFunc   func(ctx context.Context, start, end time.Time) ([]byte, error)
+	ExportWorkModeCSVFunc   func(ctx context.Context, start, end time.Time) ([]byte, error)
 }
 
 func (m *MockExportService) ExportAttendanceCSV(ctx context.Context, userID *uuid.UUID, start, end time.Time) ([]byte, error) {
@@ -1532,3 +1697,10 @@ func (m *MockExportService) ExportProjectsCSV(ctx context.Context, start, end ti
 	}
 	return nil, nil
 }
+
+func (m *MockExportService) ExportWorkModeCSV(ctx context.Context, start, end time.Time) ([]byte, error) {
+	if m.ExportWorkModeCSVFunc != nil {
+		return m.ExportWorkModeCSVFunc(ctx, start, end)
+	}
+	return nil, nil
+}
@@ -3,10 +3,13 @@ package mocks
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/model"
+	"gorm.io/datatypes"
 )
 
 // MockUserRepository はUserRepositoryのモック
@@ -112,6 +115,8 @@ type MockAttendanceRepository struct {
 	FindByIDErr       error
 	FindByUserDateErr error
 	UpdateErr         error
+	// AutoCloseLocked は他のインスタンスが退勤打刻漏れの自動処理のロックを保持している状態を表す
+	AutoCloseLocked bool
 }
 
 func NewMockAttendanceRepository() *MockAttendanceRepository {
@@ -195,10 +200,17 @@ func (m *MockAttendanceRepository) GetSummary(ctx context.Context, userID uuid.U
 				summary.TotalWorkDays++
 				summary.TotalWorkMinutes += att.WorkMinutes
 				summary.TotalOvertimeMinutes += att.OvertimeMinutes
+				summary.TotalWithinStatutoryOvertimeMinutes += att.WithinStatutoryOvertimeMinutes
+				summary.TotalLateNightMinutes += att.LateNightMinutes
+				summary.TotalHolidayWorkMinutes += att.HolidayWorkMinutes
+				summary.TotalOvertimeOver60Minutes += att.OvertimeOver60Minutes
+				summary.AddWorkModeDay(att.Date, att.WorkMode)
 			} else if att.Status == model.AttendanceStatusAbsent {
 				summary.AbsentDays++
 			} else if att.Status == model.AttendanceStatusLeave {
 				summary.LeaveDays++
+			} else if att.Status == model.AttendanceStatusIncomplete {
+				summary.IncompleteDays++
 			}
 		}
 	}
@@ -208,6 +220,39 @@ func (m *MockAttendanceRepository) GetSummary(ctx context.Context, userID uuid.U
 	return summary, nil
 }
 
+func (m *MockAttendanceRepository) CountWorkModesByUser(ctx context.Context, start, end time.Time) ([]model.UserWorkModeCount, error) {
+	index := make(map[model.UserWorkModeCount]int)
+	for _, att := range m.Attendances {
+		if att.Status == model.AttendanceStatusPresent && !att.Date.Before(start) && !att.Date.After(end) {
+			index[model.UserWorkModeCount{UserID: att.UserID, Month: att.Date.Format("2006-01"), WorkMode: att.WorkMode}]++
+		}
+	}
+	counts := make([]model.UserWorkModeCount, 0, len(index))
+	for key, days := range index {
+		key.Days = days
+		counts = append(counts, key)
+	}
+	sort.Slice(counts, func(i, j int) bool { return counts[i].Month < counts[j].Month })
+	return counts, nil
+}
+
+func (m *MockAttendanceRepository) FindOpenBefore(ctx context.Context, date time.Time) ([]model.Attendance, error) {
+	attendances := make([]model.Attendance, 0)
+	for _, att := range m.Attendances {
+		if att.Date.Before(date) && att.ClockIn != nil && att.ClockOut == nil && att.Status == model.AttendanceStatusPresent {
+			attendances = append(attendances, *att)
+		}
+	}
+	return attendances, nil
+}
+
+func (m *MockAttendanceRepository) WithAutoCloseLock(ctx context.Context, fn func() error) (bool, error) {
+	if m.AutoCloseLocked {
+		return false, nil
+	}
+	return true, fn()
+}
+
 func (m *MockAttendanceRepository) CountTodayPresent(ctx context.Context) (int64, error) {
 	today := time.Now().Truncate(24 * time.Hour)
 	var count int64
@@ -290,6 +335,16 @@ func (m *MockLeaveRequestRepository) FindPending(ctx context.Context, page, page
 	return leaves, int64(len(leaves)), nil
 }
 
+func (m *MockLeaveRequestRepository) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.LeaveRequest, int64, error) {
+	leaves := make([]model.LeaveRequest, 0)
+	for _, l := range m.LeaveRequests {
+		if l.Status == model.ApprovalStatusPending && slices.Contains(userIDs, l.UserID) {
+			leaves = append(leaves, *l)
+		}
+	}
+	return leaves, int64(len(leaves)), nil
+}
+
 func (m *MockLeaveRequestRepository) Update(ctx context.Context, leave *model.LeaveRequest) error {
 	if m.UpdateErr != nil {
 		return m.UpdateErr
@@ -372,6 +427,30 @@ func (m *MockShiftRepository) FindByDateRange(ctx context.Context, start, end ti
 	return shifts, nil
 }
 
+func (m *MockShiftRepository) FindByScheduleID(ctx context.Context, scheduleID uuid.UUID) ([]model.Shift, error) {
+	shifts := make([]model.Shift, 0)
+	for _, s := range m.Shifts {
+		if s.ScheduleID != nil && *s.ScheduleID == scheduleID {
+			shifts = append(shifts, *s)
+		}
+	}
+	return shifts, nil
+}
+
+func (m *MockShiftRepository) AssignSchedule(ctx context.Context, scheduleID uuid.UUID, userIDs []uuid.UUID, start, end time.Time) error {
+	targets := make(map[uuid.UUID]bool, len(userIDs))
+	for _, id := range userIDs {
+		targets[id] = true
+	}
+	for _, s := range m.Shifts {
+		if targets[s.UserID] && s.ScheduleID == nil && !s.Date.Before(start) && !s.Date.After(end) {
+			id := scheduleID
+			s.ScheduleID = &id
+		}
+	}
+	return nil
+}
+
 func (m *MockShiftRepository) Update(ctx context.Context, shift *model.Shift) error {
 	m.Shifts[shift.ID] = shift
 	return nil
@@ -394,6 +473,227 @@ func (m *MockShiftRepository) DeleteByUserAndDateRange(ctx context.Context, user
 	return nil
 }
 
+// MockShiftScheduleRepository はShiftScheduleRepositoryのモック
+type MockShiftScheduleRepository struct {
+	Schedules map[uuid.UUID]*model.ShiftSchedule
+	Versions  map[uuid.UUID][]model.ShiftScheduleVersion
+}
+
+func NewMockShiftScheduleRepository() *MockShiftScheduleRepository {
+	return &MockShiftScheduleRepository{
+		Schedules: make(map[uuid.UUID]*model.ShiftSchedule),
+		Versions:  make(map[uuid.UUID][]model.ShiftScheduleVersion),
+	}
+}
+
+func (m *MockShiftScheduleRepository) Create(ctx context.Context, schedule *model.ShiftSchedule) error {
+	if schedule.ID == uuid.Nil {
+		schedule.ID = uuid.New()
+	}
+	m.Schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (m *MockShiftScheduleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ShiftSchedule, error) {
+	schedule, ok := m.Schedules[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return schedule, nil
+}
+
+func (m *MockShiftScheduleRepository) FindAll(ctx context.Context, departmentID *uuid.UUID) ([]model.ShiftSchedule, error) {
+	schedules := make([]model.ShiftSchedule, 0)
+	for _, s := range m.Schedules {
+		if departmentID == nil || s.DepartmentID == *departmentID {
+			schedules = append(schedules, *s)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *MockShiftScheduleRepository) FindByDepartmentAndDate(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.ShiftSchedule, error) {
+	for _, s := range m.Schedules {
+		if s.DepartmentID == departmentID && !date.Before(s.PeriodStart) && !date.After(s.PeriodEnd) {
+			return s, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockShiftScheduleRepository) CountOverlapping(ctx context.Context, departmentID uuid.UUID, start, end time.Time) (int64, error) {
+	var count int64
+	for _, s := range m.Schedules {
+		if s.DepartmentID == departmentID && !s.PeriodStart.After(end) && !s.PeriodEnd.Before(start) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockShiftScheduleRepository) Update(ctx context.Context, schedule *model.ShiftSchedule) error {
+	m.Schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (m *MockShiftScheduleRepository) SaveVersion(ctx context.Context, schedule *model.ShiftSchedule, version *model.ShiftScheduleVersion) error {
+	if version.ID == uuid.Nil {
+		version.ID = uuid.New()
+	}
+	m.Versions[version.ScheduleID] = append(m.Versions[version.ScheduleID], *version)
+	m.Schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (m *MockShiftScheduleRepository) FindVersions(ctx context.Context, scheduleID uuid.UUID) ([]model.ShiftScheduleVersion, error) {
+	return m.Versions[scheduleID], nil
+}
+
+func (m *MockShiftScheduleRepository) FindVersion(ctx context.Context, scheduleID uuid.UUID, version int) (*model.ShiftScheduleVersion, error) {
+	for _, v := range m.Versions[scheduleID] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// MockWorkingHourPolicyRepository はWorkingHourPolicyRepositoryのモック
+type MockWorkingHourPolicyRepository struct {
+	Policies     map[uuid.UUID]*model.WorkingHourPolicy
+	ScheduleDays map[uuid.UUID][]model.WorkingHourScheduleDay
+}
+
+func NewMockWorkingHourPolicyRepository() *MockWorkingHourPolicyRepository {
+	return &MockWorkingHourPolicyRepository{
+		Policies:     make(map[uuid.UUID]*model.WorkingHourPolicy),
+		ScheduleDays: make(map[uuid.UUID][]model.WorkingHourScheduleDay),
+	}
+}
+
+func (m *MockWorkingHourPolicyRepository) Create(ctx context.Context, policy *model.WorkingHourPolicy) error {
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+	m.Policies[policy.ID] = policy
+	return nil
+}
+
+func (m *MockWorkingHourPolicyRepository) FindAll(ctx context.Context) ([]model.WorkingHourPolicy, error) {
+	policies := make([]model.WorkingHourPolicy, 0, len(m.Policies))
+	for _, p := range m.Policies {
+		policies = append(policies, *p)
+	}
+	return policies, nil
+}
+
+func (m *MockWorkingHourPolicyRepository) FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.WorkingHourPolicy, error) {
+	for _, p := range m.Policies {
+		if p.DepartmentID == departmentID {
+			return p, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockWorkingHourPolicyRepository) Update(ctx context.Context, policy *model.WorkingHourPolicy) error {
+	m.Policies[policy.ID] = policy
+	return nil
+}
+
+func (m *MockWorkingHourPolicyRepository) FindScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time) ([]model.WorkingHourScheduleDay, error) {
+	days := make([]model.WorkingHourScheduleDay, 0)
+	for _, d := range m.ScheduleDays[policyID] {
+		if !d.Date.Before(start) && !d.Date.After(end) {
+			days = append(days, d)
+		}
+	}
+	return days, nil
+}
+
+func (m *MockWorkingHourPolicyRepository) ReplaceScheduleDays(ctx context.Context, policyID uuid.UUID, start, end time.Time, days []model.WorkingHourScheduleDay) error {
+	kept := make([]model.WorkingHourScheduleDay, 0, len(days))
+	for _, d := range m.ScheduleDays[policyID] {
+		if d.Date.Before(start) || d.Date.After(end) {
+			kept = append(kept, d)
+		}
+	}
+	m.ScheduleDays[policyID] = append(kept, days...)
+	return nil
+}
+
+// MockRemoteWorkPolicyRepository はRemoteWorkPolicyRepositoryのモック
+type MockRemoteWorkPolicyRepository struct {
+	Policies map[uuid.UUID]*model.RemoteWorkPolicy
+}
+
+func NewMockRemoteWorkPolicyRepository() *MockRemoteWorkPolicyRepository {
+	return &MockRemoteWorkPolicyRepository{
+		Policies: make(map[uuid.UUID]*model.RemoteWorkPolicy),
+	}
+}
+
+func (m *MockRemoteWorkPolicyRepository) Create(ctx context.Context, policy *model.RemoteWorkPolicy) error {
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+	m.Policies[policy.ID] = policy
+	return nil
+}
+
+func (m *MockRemoteWorkPolicyRepository) FindAll(ctx context.Context) ([]model.RemoteWorkPolicy, error) {
+	policies := make([]model.RemoteWorkPolicy, 0, len(m.Policies))
+	for _, p := range m.Policies {
+		policies = append(policies, *p)
+	}
+	return policies, nil
+}
+
+func (m *MockRemoteWorkPolicyRepository) FindByDepartmentID(ctx context.Context, departmentID uuid.UUID) (*model.RemoteWorkPolicy, error) {
+	for _, p := range m.Policies {
+		if p.DepartmentID == departmentID {
+			return p, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockRemoteWorkPolicyRepository) Update(ctx context.Context, policy *model.RemoteWorkPolicy) error {
+	m.Policies[policy.ID] = policy
+	return nil
+}
+
+// MockTeamRepository はTeamRepositoryのモック
+type MockTeamRepository struct {
+	Reports                map[uuid.UUID][]uuid.UUID
+	FindDirectReportIDsErr error
+}
+
+func NewMockTeamRepository() *MockTeamRepository {
+	return &MockTeamRepository{Reports: make(map[uuid.UUID][]uuid.UUID)}
+}
+
+func (m *MockTeamRepository) FindDirectReportIDs(ctx context.Context, managerID uuid.UUID) ([]uuid.UUID, error) {
+	if m.FindDirectReportIDsErr != nil {
+		return nil, m.FindDirectReportIDsErr
+	}
+	return m.Reports[managerID], nil
+}
+
+// FindManagerIDs は Reports を逆引きして上長を返す
+func (m *MockTeamRepository) FindManagerIDs(ctx context.Context, userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for managerID, reports := range m.Reports {
+		for _, id := range reports {
+			if id == userID {
+				ids = append(ids, managerID)
+				break
+			}
+		}
+	}
+	return ids, nil
+}
+
 // MockDepartmentRepository はDepartmentRepositoryのモック
 type MockDepartmentRepository struct {
 	Departments map[uuid.UUID]*model.Department
@@ -451,18 +751,16 @@ func (m *MockDepartmentRepository) Delete(ctx context.Context, id uuid.UUID) err
 	return nil
 }
 
-// MockRefreshTokenRepository はRefreshTokenRepositoryのモック
+// MockRefreshTokenRepository はRefreshTokenRepositoryのモック（キーはトークンハッシュ）
 type MockRefreshTokenRepository struct {
-	Tokens     map[string]*model.RefreshToken
-	UserTokens map[uuid.UUID][]string
-	RevokeErr  error
-	CreateErr  error
+	Tokens    map[string]*model.RefreshToken
+	RevokeErr error
+	CreateErr error
 }
 
 func NewMockRefreshTokenRepository() *MockRefreshTokenRepository {
 	return &MockRefreshTokenRepository{
-		Tokens:     make(map[string]*model.RefreshToken),
-		UserTokens: make(map[uuid.UUID][]string),
+		Tokens: make(map[string]*model.RefreshToken),
 	}
 }
 
@@ -470,50 +768,743 @@ func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *model.Re
 	if m.CreateErr != nil {
 		return m.CreateErr
 	}
-	m.Tokens[token.Token] = token
-	m.UserTokens[token.UserID] = append(m.UserTokens[token.UserID], token.Token)
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	m.Tokens[token.TokenHash] = token
 	return nil
 }
 
-func (m *MockRefreshTokenRepository) FindByToken(ctx context.Context, token string) (*model.RefreshToken, error) {
-	rt, ok := m.Tokens[token]
-	if !ok || rt.IsRevoked {
+func (m *MockRefreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
+	rt, ok := m.Tokens[tokenHash]
+	if !ok {
 		return nil, ErrNotFound
 	}
 	return rt, nil
 }
 
-func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+func (m *MockRefreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	for _, rt := range m.Tokens {
+		if rt.ID == id && rt.UsedAt == nil && !rt.IsRevoked {
+			rt.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
 	if m.RevokeErr != nil {
 		return m.RevokeErr
 	}
-	for _, tokenStr := range m.UserTokens[userID] {
-		if rt, ok := m.Tokens[tokenStr]; ok {
+	for _, rt := range m.Tokens {
+		if rt.FamilyID == familyID {
 			rt.IsRevoked = true
 		}
 	}
 	return nil
 }
 
-func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, token string) error {
+func (m *MockRefreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
 	if m.RevokeErr != nil {
 		return m.RevokeErr
 	}
-	if rt, ok := m.Tokens[token]; ok {
-		rt.IsRevoked = true
+	for _, rt := range m.Tokens {
+		if rt.UserID == userID {
+			rt.IsRevoked = true
+		}
 	}
 	return nil
 }
 
-func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) error {
+func (m *MockRefreshTokenRepository) FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.RefreshToken, error) {
 	now := time.Now()
+	var tokens []model.RefreshToken
+	for _, rt := range m.Tokens {
+		if rt.UserID == userID && rt.UsedAt == nil && !rt.IsRevoked && rt.ExpiresAt.After(now) {
+			tokens = append(tokens, *rt)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens, nil
+}
+
+func (m *MockRefreshTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	var deleted int64
 	for key, rt := range m.Tokens {
-		if rt.ExpiresAt.Before(now) || rt.IsRevoked {
+		if rt.ExpiresAt.Before(now) {
 			delete(m.Tokens, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// MockUserTokenRepository はUserTokenRepositoryのモック（キーはトークンハッシュ）
+type MockUserTokenRepository struct {
+	Tokens    map[string]*model.UserToken
+	CreateErr error
+}
+
+func NewMockUserTokenRepository() *MockUserTokenRepository {
+	return &MockUserTokenRepository{
+		Tokens: make(map[string]*model.UserToken),
+	}
+}
+
+func (m *MockUserTokenRepository) Create(ctx context.Context, token *model.UserToken) error {
+	if m.CreateErr != nil {
+		return m.CreateErr
+	}
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	m.Tokens[token.TokenHash] = token
+	return nil
+}
+
+func (m *MockUserTokenRepository) FindByTokenHash(ctx context.Context, purpose model.UserTokenPurpose, tokenHash string) (*model.UserToken, error) {
+	t, ok := m.Tokens[tokenHash]
+	if !ok || t.Purpose != purpose {
+		return nil, ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *MockUserTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	for _, t := range m.Tokens {
+		if t.ID == id && t.UsedAt == nil {
+			t.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockUserTokenRepository) InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose model.UserTokenPurpose) error {
+	now := time.Now()
+	for _, t := range m.Tokens {
+		if t.UserID == userID && t.Purpose == purpose && t.UsedAt == nil {
+			t.UsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (m *MockUserTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	now := time.Now()
+	var deleted int64
+	for key, t := range m.Tokens {
+		if t.ExpiresAt.Before(now) {
+			delete(m.Tokens, key)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// MockUserIdentityRepository はUserIdentityRepositoryのモック
+type MockUserIdentityRepository struct {
+	Identities []*model.UserIdentity
+}
+
+func NewMockUserIdentityRepository() *MockUserIdentityRepository {
+	return &MockUserIdentityRepository{}
+}
+
+func (m *MockUserIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	if identity.ID == uuid.Nil {
+		identity.ID = uuid.New()
+	}
+	m.Identities = append(m.Identities, identity)
+	return nil
+}
+
+func (m *MockUserIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*model.UserIdentity, error) {
+	for _, identity := range m.Identities {
+		if identity.Issuer == issuer && identity.Subject == subject {
+			return identity, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *MockUserIdentityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, email string, at time.Time) error {
+	for _, identity := range m.Identities {
+		if identity.ID == id {
+			identity.Email = email
+			identity.LastLoginAt = &at
+			return nil
+		}
+	}
+	return ErrNotFound
+}
+
+// MockLoginHistoryRepository はLoginHistoryRepositoryのモック（記録順に保持する）
+type MockLoginHistoryRepository struct {
+	Histories []*model.LoginHistory
+}
+
+func NewMockLoginHistoryRepository() *MockLoginHistoryRepository {
+	return &MockLoginHistoryRepository{}
+}
+
+func (m *MockLoginHistoryRepository) Create(ctx context.Context, history *model.LoginHistory) error {
+	if history.ID == uuid.Nil {
+		history.ID = uuid.New()
+	}
+	if history.CreatedAt.IsZero() {
+		history.CreatedAt = time.Now()
+	}
+	m.Histories = append(m.Histories, history)
+	return nil
+}
+
+func (m *MockLoginHistoryRepository) FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error) {
+	result := make([]model.LoginHistory, 0)
+	for i := len(m.Histories) - 1; i >= 0; i-- {
+		if h := m.Histories[i]; h.UserID != nil && *h.UserID == userID {
+			result = append(result, *h)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *MockLoginHistoryRepository) HasSuccess(ctx context.Context, userID uuid.UUID, userAgent string) (bool, error) {
+	for _, h := range m.Histories {
+		if h.UserID != nil && *h.UserID == userID && h.Success && (userAgent == "" || h.UserAgent == userAgent) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockLoginHistoryRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	kept := m.Histories[:0]
+	for _, h := range m.Histories {
+		if !h.CreatedAt.Before(before) {
+			kept = append(kept, h)
+		}
+	}
+	deleted := int64(len(m.Histories) - len(kept))
+	m.Histories = kept
+	return deleted, nil
+}
+
+// MockLoginLockoutRepository はLoginLockoutRepositoryのモック（キーはユーザーID）
+type MockLoginLockoutRepository struct {
+	Lockouts map[uuid.UUID]*model.LoginLockout
+}
+
+func NewMockLoginLockoutRepository() *MockLoginLockoutRepository {
+	return &MockLoginLockoutRepository{Lockouts: make(map[uuid.UUID]*model.LoginLockout)}
+}
+
+func (m *MockLoginLockoutRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.LoginLockout, error) {
+	return m.Lockouts[userID], nil
+}
+
+func (m *MockLoginLockoutRepository) RecordFailure(ctx context.Context, userID uuid.UUID, at time.Time) (*model.LoginLockout, error) {
+	lockout, ok := m.Lockouts[userID]
+	if !ok {
+		lockout = &model.LoginLockout{UserID: userID}
+		lockout.ID = uuid.New()
+		m.Lockouts[userID] = lockout
+	}
+	lockout.FailedCount++
+	lockout.LastFailedAt = &at
+	copied := *lockout
+	return &copied, nil
+}
+
+func (m *MockLoginLockoutRepository) Lock(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	lockout, ok := m.Lockouts[userID]
+	if !ok {
+		return ErrNotFound
+	}
+	lockout.FailedCount = 0
+	lockout.LockoutCount++
+	lockout.LockedUntil = &until
+	return nil
+}
+
+func (m *MockLoginLockoutRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	delete(m.Lockouts, userID)
+	return nil
+}
+
+// MockServiceAccountRepository はServiceAccountRepositoryのモック
+type MockServiceAccountRepository struct {
+	Accounts map[uuid.UUID]*model.ServiceAccount
+}
+
+func NewMockServiceAccountRepository() *MockServiceAccountRepository {
+	return &MockServiceAccountRepository{Accounts: make(map[uuid.UUID]*model.ServiceAccount)}
+}
+
+func (m *MockServiceAccountRepository) Create(ctx context.Context, account *model.ServiceAccount) error {
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+	m.Accounts[account.ID] = account
+	return nil
+}
+
+func (m *MockServiceAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ServiceAccount, error) {
+	a, ok := m.Accounts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return a, nil
+}
+
+func (m *MockServiceAccountRepository) FindByName(ctx context.Context, name string) (*model.ServiceAccount, error) {
+	for _, a := range m.Accounts {
+		if a.Name == name {
+			return a, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockServiceAccountRepository) FindAll(ctx context.Context) ([]model.ServiceAccount, error) {
+	accounts := make([]model.ServiceAccount, 0, len(m.Accounts))
+	for _, a := range m.Accounts {
+		accounts = append(accounts, *a)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].Name < accounts[j].Name })
+	return accounts, nil
+}
+
+func (m *MockServiceAccountRepository) Update(ctx context.Context, account *model.ServiceAccount) error {
+	m.Accounts[account.ID] = account
+	return nil
+}
+
+func (m *MockServiceAccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.Accounts, id)
+	return nil
+}
+
+// MockAPITokenRepository はAPITokenRepositoryのモック
+type MockAPITokenRepository struct {
+	Tokens map[uuid.UUID]*model.APIToken
+}
+
+func NewMockAPITokenRepository() *MockAPITokenRepository {
+	return &MockAPITokenRepository{Tokens: make(map[uuid.UUID]*model.APIToken)}
+}
+
+func (m *MockAPITokenRepository) Create(ctx context.Context, token *model.APIToken) error {
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	m.Tokens[token.ID] = token
+	return nil
+}
+
+func (m *MockAPITokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.APIToken, error) {
+	t, ok := m.Tokens[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return t, nil
+}
+
+func (m *MockAPITokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	for _, t := range m.Tokens {
+		if t.TokenHash == tokenHash {
+			return t, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockAPITokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error) {
+	return m.filter(func(t *model.APIToken) bool { return t.UserID != nil && *t.UserID == userID }), nil
+}
+
+func (m *MockAPITokenRepository) FindByServiceAccountID(ctx context.Context, accountID uuid.UUID) ([]model.APIToken, error) {
+	return m.filter(func(t *model.APIToken) bool { return t.ServiceAccountID != nil && *t.ServiceAccountID == accountID }), nil
+}
+
+func (m *MockAPITokenRepository) filter(match func(t *model.APIToken) bool) []model.APIToken {
+	tokens := []model.APIToken{}
+	for _, t := range m.Tokens {
+		if match(t) {
+			tokens = append(tokens, *t)
+		}
+	}
+	sort.Slice(tokens, func(i, j int) bool { return tokens[i].CreatedAt.After(tokens[j].CreatedAt) })
+	return tokens
+}
+
+func (m *MockAPITokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	if t, ok := m.Tokens[id]; ok {
+		t.LastUsedAt = &at
+		t.LastUsedIP = ip
+	}
+	return nil
+}
+
+func (m *MockAPITokenRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	if t, ok := m.Tokens[id]; ok && t.RevokedAt == nil {
+		t.RevokedAt = &at
+	}
+	return nil
+}
+
+func (m *MockAPITokenRepository) RevokeByServiceAccountID(ctx context.Context, accountID uuid.UUID, at time.Time) error {
+	for _, t := range m.Tokens {
+		if t.ServiceAccountID != nil && *t.ServiceAccountID == accountID && t.RevokedAt == nil {
+			t.RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// MockMFARepository はMFARepositoryのモック（キーはユーザーID）
+type MockMFARepository struct {
+	Settings map[uuid.UUID]*model.UserMFA
+	FindErr  error
+}
+
+func NewMockMFARepository() *MockMFARepository {
+	return &MockMFARepository{Settings: make(map[uuid.UUID]*model.UserMFA)}
+}
+
+func (m *MockMFARepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.UserMFA, error) {
+	if m.FindErr != nil {
+		return nil, m.FindErr
+	}
+	return m.Settings[userID], nil
+}
+
+func (m *MockMFARepository) Save(ctx context.Context, mfa *model.UserMFA) error {
+	if mfa.ID == uuid.Nil {
+		mfa.ID = uuid.New()
+	}
+	m.Settings[mfa.UserID] = mfa
+	return nil
+}
+
+func (m *MockMFARepository) AdvanceStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	for _, mfa := range m.Settings {
+		if mfa.ID == id && mfa.LastUsedStep < step {
+			mfa.LastUsedStep = step
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockMFARepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	delete(m.Settings, userID)
+	return nil
+}
+
+// MockMFARecoveryCodeRepository はMFARecoveryCodeRepositoryのモック
+type MockMFARecoveryCodeRepository struct {
+	Codes []*model.MFARecoveryCode
+}
+
+func NewMockMFARecoveryCodeRepository() *MockMFARecoveryCodeRepository {
+	return &MockMFARecoveryCodeRepository{}
+}
+
+func (m *MockMFARecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []model.MFARecoveryCode) error {
+	_ = m.DeleteByUserID(ctx, userID)
+	for i := range codes {
+		c := codes[i]
+		if c.ID == uuid.Nil {
+			c.ID = uuid.New()
+		}
+		m.Codes = append(m.Codes, &c)
+	}
+	return nil
+}
+
+func (m *MockMFARecoveryCodeRepository) FindUnused(ctx context.Context, userID uuid.UUID, codeHash string) (*model.MFARecoveryCode, error) {
+	for _, c := range m.Codes {
+		if c.UserID == userID && c.CodeHash == codeHash && c.UsedAt == nil {
+			return c, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (m *MockMFARecoveryCodeRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	for _, c := range m.Codes {
+		if c.ID == id && c.UsedAt == nil {
+			c.UsedAt = &usedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *MockMFARecoveryCodeRepository) CountUnused(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for _, c := range m.Codes {
+		if c.UserID == userID && c.UsedAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockMFARecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	kept := m.Codes[:0]
+	for _, c := range m.Codes {
+		if c.UserID != userID {
+			kept = append(kept, c)
+		}
+	}
+	m.Codes = kept
+	return nil
+}
+
+// MockMFAPolicyRepository はMFAPolicyRepositoryのモック
+type MockMFAPolicyRepository struct {
+	Policy *model.MFAPolicy
+}
+
+func NewMockMFAPolicyRepository() *MockMFAPolicyRepository {
+	return &MockMFAPolicyRepository{}
+}
+
+func (m *MockMFAPolicyRepository) Get(ctx context.Context) (*model.MFAPolicy, error) {
+	if m.Policy == nil {
+		return &model.MFAPolicy{RequiredRoles: datatypes.JSON("[]"), RequiredCustomRoleIDs: datatypes.JSON("[]")}, nil
+	}
+	return m.Policy, nil
+}
+
+func (m *MockMFAPolicyRepository) Save(ctx context.Context, policy *model.MFAPolicy) error {
+	if policy.ID == uuid.Nil {
+		policy.ID = uuid.New()
+	}
+	m.Policy = policy
+	return nil
+}
+
+// MockRoleRepository はRoleRepositoryのモック
+type MockRoleRepository struct {
+	Roles map[uuid.UUID]*model.CustomRole
+}
+
+func NewMockRoleRepository() *MockRoleRepository {
+	return &MockRoleRepository{Roles: make(map[uuid.UUID]*model.CustomRole)}
+}
+
+func (m *MockRoleRepository) Create(ctx context.Context, role *model.CustomRole) error {
+	if role.ID == uuid.Nil {
+		role.ID = uuid.New()
+	}
+	m.Roles[role.ID] = role
+	return nil
+}
+
+func (m *MockRoleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.CustomRole, error) {
+	role, ok := m.Roles[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return role, nil
+}
+
+func (m *MockRoleRepository) FindByName(ctx context.Context, name string) (*model.CustomRole, error) {
+	for _, r := range m.Roles {
+		if r.Name == name {
+			return r, nil
 		}
 	}
+	return nil, ErrNotFound
+}
+
+func (m *MockRoleRepository) FindAll(ctx context.Context) ([]model.CustomRole, error) {
+	roles := make([]model.CustomRole, 0, len(m.Roles))
+	for _, r := range m.Roles {
+		roles = append(roles, *r)
+	}
+	return roles, nil
+}
+
+func (m *MockRoleRepository) Update(ctx context.Context, role *model.CustomRole) error {
+	m.Roles[role.ID] = role
 	return nil
 }
 
+func (m *MockRoleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.Roles, id)
+	return nil
+}
+
+// MockRoleAssignmentRepository はRoleAssignmentRepositoryのモック。
+// Roles を設定すると検索結果に Role を埋める
+type MockRoleAssignmentRepository struct {
+	Assignments map[uuid.UUID]*model.UserRoleAssignment
+	Roles       *MockRoleRepository
+}
+
+func NewMockRoleAssignmentRepository(roles *MockRoleRepository) *MockRoleAssignmentRepository {
+	return &MockRoleAssignmentRepository{
+		Assignments: make(map[uuid.UUID]*model.UserRoleAssignment),
+		Roles:       roles,
+	}
+}
+
+func (m *MockRoleAssignmentRepository) withRole(a *model.UserRoleAssignment) *model.UserRoleAssignment {
+	if m.Roles != nil {
+		if r, ok := m.Roles.Roles[a.RoleID]; ok {
+			a.Role = r
+		}
+	}
+	return a
+}
+
+func (m *MockRoleAssignmentRepository) Create(ctx context.Context, a *model.UserRoleAssignment) error {
+	if a.ID == uuid.Nil {
+		a.ID = uuid.New()
+	}
+	m.Assignments[a.ID] = a
+	return nil
+}
+
+func (m *MockRoleAssignmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.UserRoleAssignment, error) {
+	a, ok := m.Assignments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return m.withRole(a), nil
+}
+
+func (m *MockRoleAssignmentRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserRoleAssignment, error) {
+	var result []model.UserRoleAssignment
+	for _, a := range m.Assignments {
+		if a.UserID == userID {
+			result = append(result, *m.withRole(a))
+		}
+	}
+	return result, nil
+}
+
+func (m *MockRoleAssignmentRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]model.UserRoleAssignment, error) {
+	var result []model.UserRoleAssignment
+	for _, a := range m.Assignments {
+		if a.RoleID == roleID {
+			result = append(result, *a)
+		}
+	}
+	return result, nil
+}
+
+func (m *MockRoleAssignmentRepository) CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var count int64
+	for _, a := range m.Assignments {
+		if a.RoleID == roleID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MockRoleAssignmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.Assignments, id)
+	return nil
+}
+
+// MockAuditLogRepository はAuditLogRepositoryのモック（Logs は Seq の昇順）
+type MockAuditLogRepository struct {
+	Logs []model.AuditLog
+}
+
+func (m *MockAuditLogRepository) FindAll(ctx context.Context, q *model.AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error) {
+	var result []model.AuditLog
+	for i := len(m.Logs) - 1; i >= 0; i-- {
+		l := m.Logs[i]
+		if (q.ActorID != "" && (l.ActorID == nil || l.ActorID.String() != q.ActorID)) ||
+			(q.Action != "" && l.Action != q.Action) ||
+			(q.EntityType != "" && l.EntityType != q.EntityType) ||
+			(q.EntityID != "" && l.EntityID != q.EntityID) ||
+			(q.RequestID != "" && l.RequestID != q.RequestID) {
+			continue
+		}
+		result = append(result, l)
+	}
+	total := int64(len(result))
+	start := (page - 1) * pageSize
+	if start >= len(result) {
+		return []model.AuditLog{}, total, nil
+	}
+	end := start + pageSize
+	if end > len(result) {
+		end = len(result)
+	}
+	return result[start:end], total, nil
+}
+
+func (m *MockAuditLogRepository) FindAfterSeq(ctx context.Context, afterSeq int64, limit int) ([]model.AuditLog, error) {
+	var result []model.AuditLog
+	for _, l := range m.Logs {
+		if l.Seq > afterSeq && len(result) < limit {
+			result = append(result, l)
+		}
+	}
+	return result, nil
+}
+
 // エラー定義
 var ErrNotFound = errors.New("not found")
+
+// MockNotificationPreferenceRepository はNotificationPreferenceRepositoryのモック
+type MockNotificationPreferenceRepository struct {
+	Prefs    []model.NotificationPreference
+	Settings map[uuid.UUID]*model.NotificationSetting
+}
+
+func NewMockNotificationPreferenceRepository() *MockNotificationPreferenceRepository {
+	return &MockNotificationPreferenceRepository{Settings: make(map[uuid.UUID]*model.NotificationSetting)}
+}
+
+func (m *MockNotificationPreferenceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	prefs := []model.NotificationPreference{}
+	for _, p := range m.Prefs {
+		if p.UserID == userID {
+			prefs = append(prefs, p)
+		}
+	}
+	return prefs, nil
+}
+
+func (m *MockNotificationPreferenceRepository) Upsert(ctx context.Context, prefs []model.NotificationPreference) error {
+	for _, p := range prefs {
+		found := false
+		for i := range m.Prefs {
+			if m.Prefs[i].UserID == p.UserID && m.Prefs[i].Type == p.Type && m.Prefs[i].Channel == p.Channel {
+				m.Prefs[i].Enabled = p.Enabled
+				found = true
+			}
+		}
+		if !found {
+			m.Prefs = append(m.Prefs, p)
+		}
+	}
+	return nil
+}
+
+func (m *MockNotificationPreferenceRepository) FindSetting(ctx context.Context, userID uuid.UUID) (*model.NotificationSetting, error) {
+	return m.Settings[userID], nil
+}
+
+func (m *MockNotificationPreferenceRepository) SaveSetting(ctx context.Context, setting *model.NotificationSetting) error {
+	m.Settings[setting.UserID] = setting
+	return nil
+}
@@ -5,16 +5,25 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 )
 
 // ===== MockAuthService =====
 
 type MockAuthService struct {
-	LoginFunc        func(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error)
-	RegisterFunc     func(ctx context.Context, req *model.RegisterRequest) (*model.User, error)
-	RefreshTokenFunc func(ctx context.Context, refreshToken string) (*model.TokenResponse, error)
-	LogoutFunc       func(ctx context.Context, userID uuid.UUID) error
+	LoginFunc                func(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error)
+	RegisterFunc             func(ctx context.Context, req *model.RegisterRequest) (*model.User, error)
+	RefreshTokenFunc         func(ctx context.Context, refreshToken string) (*model.TokenResponse, error)
+	LogoutFunc               func(ctx context.Context, userID uuid.UUID) error
+	CleanupFunc              func(ctx context.Context) (int64, error)
+	RequestPasswordResetFunc func(ctx context.Context, email string) error
+	ResetPasswordFunc        func(ctx context.Context, req *model.ResetPasswordRequest) error
+	VerifyEmailFunc          func(ctx context.Context, token string) error
+	ResendVerificationFunc   func(ctx context.Context, email string) error
+	VerifyMFAFunc            func(ctx context.Context, req *model.MFAVerifyRequest) (*model.TokenResponse, error)
+	StartMFASetupFunc        func(ctx context.Context, mfaToken string) (*model.MFASetupResponse, error)
+	CompleteMFASetupFunc     func(ctx context.Context, req *model.MFAVerifyRequest) (*model.MFAEnrollmentResponse, error)
 }
 
 func (m *MockAuthService) Login(ctx context.Context, req *model.LoginRequest) (*model.TokenResponse, error) {
@@ -45,6 +54,344 @@ func (m *MockAuthService) Logout(ctx context.Context, userID uuid.UUID) error {
 	return nil
 }
 
+func (m *MockAuthService) CleanupExpiredTokens(ctx context.Context) (int64, error) {
+	if m.CleanupFunc != nil {
+		return m.CleanupFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockAuthService) RequestPasswordReset(ctx context.Context, email string) error {
+	if m.RequestPasswordResetFunc != nil {
+		return m.RequestPasswordResetFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockAuthService) ResetPassword(ctx context.Context, req *model.ResetPasswordRequest) error {
+	if m.ResetPasswordFunc != nil {
+		return m.ResetPasswordFunc(ctx, req)
+	}
+	return nil
+}
+
+func (m *MockAuthService) VerifyEmail(ctx context.Context, token string) error {
+	if m.VerifyEmailFunc != nil {
+		return m.VerifyEmailFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockAuthService) ResendVerification(ctx context.Context, email string) error {
+	if m.ResendVerificationFunc != nil {
+		return m.ResendVerificationFunc(ctx, email)
+	}
+	return nil
+}
+
+func (m *MockAuthService) VerifyMFA(ctx context.Context, req *model.MFAVerifyRequest) (*model.TokenResponse, error) {
+	if m.VerifyMFAFunc != nil {
+		return m.VerifyMFAFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthService) StartMFASetup(ctx context.Context, mfaToken string) (*model.MFASetupResponse, error) {
+	if m.StartMFASetupFunc != nil {
+		return m.StartMFASetupFunc(ctx, mfaToken)
+	}
+	return nil, nil
+}
+
+func (m *MockAuthService) CompleteMFASetup(ctx context.Context, req *model.MFAVerifyRequest) (*model.MFAEnrollmentResponse, error) {
+	if m.CompleteMFASetupFunc != nil {
+		return m.CompleteMFASetupFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+// ===== MockSSOService =====
+
+type MockSSOService struct {
+	BeginFunc    func(ctx context.Context) (*model.SSOAuthorization, error)
+	CallbackFunc func(ctx context.Context, flowToken string, cb *model.SSOCallback) (string, error)
+	ExchangeFunc func(ctx context.Context, code string) (*model.TokenResponse, error)
+}
+
+func (m *MockSSOService) Begin(ctx context.Context) (*model.SSOAuthorization, error) {
+	if m.BeginFunc != nil {
+		return m.BeginFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockSSOService) Callback(ctx context.Context, flowToken string, cb *model.SSOCallback) (string, error) {
+	if m.CallbackFunc != nil {
+		return m.CallbackFunc(ctx, flowToken, cb)
+	}
+	return "", nil
+}
+
+func (m *MockSSOService) Exchange(ctx context.Context, code string) (*model.TokenResponse, error) {
+	if m.ExchangeFunc != nil {
+		return m.ExchangeFunc(ctx, code)
+	}
+	return nil, nil
+}
+
+// ===== MockLoginSecurityService =====
+
+type MockLoginSecurityService struct {
+	GetHistoryFunc func(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error)
+	UnlockFunc     func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (m *MockLoginSecurityService) GetHistory(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error) {
+	if m.GetHistoryFunc != nil {
+		return m.GetHistoryFunc(ctx, userID, page, pageSize)
+	}
+	return []model.LoginHistory{}, 0, nil
+}
+
+func (m *MockLoginSecurityService) Unlock(ctx context.Context, userID uuid.UUID) error {
+	if m.UnlockFunc != nil {
+		return m.UnlockFunc(ctx, userID)
+	}
+	return nil
+}
+
+// ===== MockSessionService =====
+
+type MockSessionService struct {
+	ListFunc         func(ctx context.Context, userID uuid.UUID) ([]model.Session, error)
+	RevokeFunc       func(ctx context.Context, userID, sessionID uuid.UUID) error
+	RevokeOthersFunc func(ctx context.Context, userID uuid.UUID) (int, error)
+	ForceLogoutFunc  func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (m *MockSessionService) List(ctx context.Context, userID uuid.UUID) ([]model.Session, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID)
+	}
+	return []model.Session{}, nil
+}
+
+func (m *MockSessionService) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if m.RevokeFunc != nil {
+		return m.RevokeFunc(ctx, userID, sessionID)
+	}
+	return nil
+}
+
+func (m *MockSessionService) RevokeOthers(ctx context.Context, userID uuid.UUID) (int, error) {
+	if m.RevokeOthersFunc != nil {
+		return m.RevokeOthersFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockSessionService) ForceLogout(ctx context.Context, userID uuid.UUID) error {
+	if m.ForceLogoutFunc != nil {
+		return m.ForceLogoutFunc(ctx, userID)
+	}
+	return nil
+}
+
+// ===== MockAPITokenService =====
+
+type MockAPITokenService struct {
+	ListPersonalFunc              func(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error)
+	CreatePersonalFunc            func(ctx context.Context, userID uuid.UUID, req *model.APITokenRequest) (*model.APITokenIssued, error)
+	RevokePersonalFunc            func(ctx context.Context, userID, tokenID uuid.UUID) error
+	ListServiceAccountsFunc       func(ctx context.Context) ([]model.ServiceAccount, error)
+	CreateServiceAccountFunc      func(ctx context.Context, req *model.ServiceAccountRequest) (*model.ServiceAccount, error)
+	UpdateServiceAccountFunc      func(ctx context.Context, id uuid.UUID, req *model.ServiceAccountUpdateRequest) (*model.ServiceAccount, error)
+	DeleteServiceAccountFunc      func(ctx context.Context, id uuid.UUID) error
+	ListServiceAccountTokensFunc  func(ctx context.Context, accountID uuid.UUID) ([]model.APIToken, error)
+	CreateServiceAccountTokenFunc func(ctx context.Context, accountID uuid.UUID, req *model.APITokenRequest) (*model.APITokenIssued, error)
+	RevokeServiceAccountTokenFunc func(ctx context.Context, accountID, tokenID uuid.UUID) error
+	AuthenticateAPITokenFunc      func(ctx context.Context, token string) (*middleware.Actor, error)
+}
+
+func (m *MockAPITokenService) Scopes() []model.APITokenScopeDefinition {
+	return middleware.TokenScopeDefinitions()
+}
+
+func (m *MockAPITokenService) ListPersonal(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error) {
+	if m.ListPersonalFunc != nil {
+		return m.ListPersonalFunc(ctx, userID)
+	}
+	return []model.APIToken{}, nil
+}
+
+func (m *MockAPITokenService) CreatePersonal(ctx context.Context, userID uuid.UUID, req *model.APITokenRequest) (*model.APITokenIssued, error) {
+	if m.CreatePersonalFunc != nil {
+		return m.CreatePersonalFunc(ctx, userID, req)
+	}
+	return &model.APITokenIssued{}, nil
+}
+
+func (m *MockAPITokenService) RevokePersonal(ctx context.Context, userID, tokenID uuid.UUID) error {
+	if m.RevokePersonalFunc != nil {
+		return m.RevokePersonalFunc(ctx, userID, tokenID)
+	}
+	return nil
+}
+
+func (m *MockAPITokenService) ListServiceAccounts(ctx context.Context) ([]model.ServiceAccount, error) {
+	if m.ListServiceAccountsFunc != nil {
+		return m.ListServiceAccountsFunc(ctx)
+	}
+	return []model.ServiceAccount{}, nil
+}
+
+func (m *MockAPITokenService) CreateServiceAccount(ctx context.Context, req *model.ServiceAccountRequest) (*model.ServiceAccount, error) {
+	if m.CreateServiceAccountFunc != nil {
+		return m.CreateServiceAccountFunc(ctx, req)
+	}
+	return &model.ServiceAccount{}, nil
+}
+
+func (m *MockAPITokenService) UpdateServiceAccount(ctx context.Context, id uuid.UUID, req *model.ServiceAccountUpdateRequest) (*model.ServiceAccount, error) {
+	if m.UpdateServiceAccountFunc != nil {
+		return m.UpdateServiceAccountFunc(ctx, id, req)
+	}
+	return &model.ServiceAccount{}, nil
+}
+
+func (m *MockAPITokenService) DeleteServiceAccount(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteServiceAccountFunc != nil {
+		return m.DeleteServiceAccountFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockAPITokenService) ListServiceAccountTokens(ctx context.Context, accountID uuid.UUID) ([]model.APIToken, error) {
+	if m.ListServiceAccountTokensFunc != nil {
+		return m.ListServiceAccountTokensFunc(ctx, accountID)
+	}
+	return []model.APIToken{}, nil
+}
+
+func (m *MockAPITokenService) CreateServiceAccountToken(ctx context.Context, accountID uuid.UUID, req *model.APITokenRequest) (*model.APITokenIssued, error) {
+	if m.CreateServiceAccountTokenFunc != nil {
+		return m.CreateServiceAccountTokenFunc(ctx, accountID, req)
+	}
+	return &model.APITokenIssued{}, nil
+}
+
+func (m *MockAPITokenService) RevokeServiceAccountToken(ctx context.Context, accountID, tokenID uuid.UUID) error {
+	if m.RevokeServiceAccountTokenFunc != nil {
+		return m.RevokeServiceAccountTokenFunc(ctx, accountID, tokenID)
+	}
+	return nil
+}
+
+func (m *MockAPITokenService) AuthenticateAPIToken(ctx context.Context, token string) (*middleware.Actor, error) {
+	if m.AuthenticateAPITokenFunc != nil {
+		return m.AuthenticateAPITokenFunc(ctx, token)
+	}
+	return nil, ErrNotFound
+}
+
+// ===== MockAuditService =====
+
+type MockAuditService struct {
+	ListFunc      func(ctx context.Context, q *model.AuditLogQuery) ([]model.AuditLog, int64, error)
+	ExportCSVFunc func(ctx context.Context, q *model.AuditLogQuery) ([]byte, error)
+	VerifyFunc    func(ctx context.Context) (*model.AuditVerifyResult, error)
+}
+
+func (m *MockAuditService) List(ctx context.Context, q *model.AuditLogQuery) ([]model.AuditLog, int64, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, q)
+	}
+	return []model.AuditLog{}, 0, nil
+}
+
+func (m *MockAuditService) ExportCSV(ctx context.Context, q *model.AuditLogQuery) ([]byte, error) {
+	if m.ExportCSVFunc != nil {
+		return m.ExportCSVFunc(ctx, q)
+	}
+	return []byte{}, nil
+}
+
+func (m *MockAuditService) Verify(ctx context.Context) (*model.AuditVerifyResult, error) {
+	if m.VerifyFunc != nil {
+		return m.VerifyFunc(ctx)
+	}
+	return &model.AuditVerifyResult{Valid: true}, nil
+}
+
+// ===== MockMFAService =====
+
+type MockMFAService struct {
+	StatusFunc                  func(ctx context.Context, userID uuid.UUID) (*model.MFAStatus, error)
+	SetupFunc                   func(ctx context.Context, userID uuid.UUID) (*model.MFASetupResponse, error)
+	EnableFunc                  func(ctx context.Context, userID uuid.UUID, code string) (*model.MFAEnrollmentResponse, error)
+	RegenerateRecoveryCodesFunc func(ctx context.Context, userID uuid.UUID, code string) (*model.MFAEnrollmentResponse, error)
+	DisableFunc                 func(ctx context.Context, userID uuid.UUID, code string) error
+	ResetFunc                   func(ctx context.Context, userID uuid.UUID) error
+	GetPolicyFunc               func(ctx context.Context) (*model.MFAPolicy, error)
+	UpdatePolicyFunc            func(ctx context.Context, req *model.MFAPolicyRequest) (*model.MFAPolicy, error)
+}
+
+func (m *MockMFAService) Status(ctx context.Context, userID uuid.UUID) (*model.MFAStatus, error) {
+	if m.StatusFunc != nil {
+		return m.StatusFunc(ctx, userID)
+	}
+	return &model.MFAStatus{}, nil
+}
+
+func (m *MockMFAService) Setup(ctx context.Context, userID uuid.UUID) (*model.MFASetupResponse, error) {
+	if m.SetupFunc != nil {
+		return m.SetupFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockMFAService) Enable(ctx context.Context, userID uuid.UUID, code string) (*model.MFAEnrollmentResponse, error) {
+	if m.EnableFunc != nil {
+		return m.EnableFunc(ctx, userID, code)
+	}
+	return nil, nil
+}
+
+func (m *MockMFAService) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) (*model.MFAEnrollmentResponse, error) {
+	if m.RegenerateRecoveryCodesFunc != nil {
+		return m.RegenerateRecoveryCodesFunc(ctx, userID, code)
+	}
+	return nil, nil
+}
+
+func (m *MockMFAService) Disable(ctx context.Context, userID uuid.UUID, code string) error {
+	if m.DisableFunc != nil {
+		return m.DisableFunc(ctx, userID, code)
+	}
+	return nil
+}
+
+func (m *MockMFAService) Reset(ctx context.Context, userID uuid.UUID) error {
+	if m.ResetFunc != nil {
+		return m.ResetFunc(ctx, userID)
+	}
+	return nil
+}
+
+func (m *MockMFAService) GetPolicy(ctx context.Context) (*model.MFAPolicy, error) {
+	if m.GetPolicyFunc != nil {
+		return m.GetPolicyFunc(ctx)
+	}
+	return &model.MFAPolicy{}, nil
+}
+
+func (m *MockMFAService) UpdatePolicy(ctx context.Context, req *model.MFAPolicyRequest) (*model.MFAPolicy, error) {
+	if m.UpdatePolicyFunc != nil {
+		return m.UpdatePolicyFunc(ctx, req)
+	}
+	return nil, nil
+}
+
 // ===== MockAttendanceService =====
 
 type MockAttendanceService struct {
@@ -53,6 +400,7 @@ type MockAttendanceService struct {
 	GetByUserAndDateRangeFunc func(ctx context.Context, userID uuid.UUID, start, end time.Time, page, pageSize int) ([]model.Attendance, int64, error)
 	GetSummaryFunc            func(ctx context.Context, userID uuid.UUID, start, end time.Time) (*model.AttendanceSummary, error)
 	GetTodayStatusFunc        func(ctx context.Context, userID uuid.UUID) (*model.Attendance, error)
+	CloseOpenAttendancesFunc  func(ctx context.Context, now time.Time) (int, error)
 }
 
 func (m *MockAttendanceService) ClockIn(ctx context.Context, userID uuid.UUID, req *model.ClockInRequest) (*model.Attendance, error) {
@@ -90,6 +438,13 @@ func (m *MockAttendanceService) GetTodayStatus(ctx context.Context, userID uuid.
 	return nil, nil
 }
 
+func (m *MockAttendanceService) CloseOpenAttendances(ctx context.Context, now time.Time) (int, error) {
+	if m.CloseOpenAttendancesFunc != nil {
+		return m.CloseOpenAttendancesFunc(ctx, now)
+	}
+	return 0, nil
+}
+
 // ===== MockLeaveService =====
 
 type MockLeaveService struct {
@@ -134,6 +489,7 @@ type MockShiftService struct {
 	BulkCreateFunc            func(ctx context.Context, req *model.ShiftBulkCreateRequest) error
 	GetByUserAndDateRangeFunc func(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.Shift, error)
 	GetByDateRangeFunc        func(ctx context.Context, start, end time.Time) ([]model.Shift, error)
+	UpdateFunc                func(ctx context.Context, id uuid.UUID, req *model.ShiftUpdateRequest) (*model.Shift, error)
 	DeleteFunc                func(ctx context.Context, id uuid.UUID) error
 }
 
@@ -165,6 +521,13 @@ func (m *MockShiftService) GetByDateRange(ctx context.Context, start, end time.T
 	return nil, nil
 }
 
+func (m *MockShiftService) Update(ctx context.Context, id uuid.UUID, req *model.ShiftUpdateRequest) (*model.Shift, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, req)
+	}
+	return nil, nil
+}
+
 func (m *MockShiftService) Delete(ctx context.Context, id uuid.UUID) error {
 	if m.DeleteFunc != nil {
 		return m.DeleteFunc(ctx, id)
@@ -262,6 +625,83 @@ func (m *MockDepartmentService) Delete(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+// ===== MockRoleService =====
+
+type MockRoleService struct {
+	ListPermissionsFunc func(ctx context.Context) []model.PermissionDefinition
+	ListFunc            func(ctx context.Context) ([]model.CustomRole, error)
+	GetByIDFunc         func(ctx context.Context, id uuid.UUID) (*model.CustomRole, error)
+	CreateFunc          func(ctx context.Context, req *model.CustomRoleRequest) (*model.CustomRole, error)
+	UpdateFunc          func(ctx context.Context, id uuid.UUID, req *model.CustomRoleRequest) (*model.CustomRole, error)
+	DeleteFunc          func(ctx context.Context, id uuid.UUID) error
+	ListAssignmentsFunc func(ctx context.Context, userID uuid.UUID) ([]model.UserRoleAssignment, error)
+	AssignFunc          func(ctx context.Context, userID uuid.UUID, req *model.RoleAssignmentRequest) (*model.UserRoleAssignment, error)
+	UnassignFunc        func(ctx context.Context, userID, assignmentID uuid.UUID) error
+}
+
+func (m *MockRoleService) ListPermissions(ctx context.Context) []model.PermissionDefinition {
+	if m.ListPermissionsFunc != nil {
+		return m.ListPermissionsFunc(ctx)
+	}
+	return nil
+}
+
+func (m *MockRoleService) List(ctx context.Context) ([]model.CustomRole, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) GetByID(ctx context.Context, id uuid.UUID) (*model.CustomRole, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) Create(ctx context.Context, req *model.CustomRoleRequest) (*model.CustomRole, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) Update(ctx context.Context, id uuid.UUID, req *model.CustomRoleRequest) (*model.CustomRole, error) {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, id, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) Delete(ctx context.Context, id uuid.UUID) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockRoleService) ListAssignments(ctx context.Context, userID uuid.UUID) ([]model.UserRoleAssignment, error) {
+	if m.ListAssignmentsFunc != nil {
+		return m.ListAssignmentsFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) Assign(ctx context.Context, userID uuid.UUID, req *model.RoleAssignmentRequest) (*model.UserRoleAssignment, error) {
+	if m.AssignFunc != nil {
+		return m.AssignFunc(ctx, userID, req)
+	}
+	return nil, nil
+}
+
+func (m *MockRoleService) Unassign(ctx context.Context, userID, assignmentID uuid.UUID) error {
+	if m.UnassignFunc != nil {
+		return m.UnassignFunc(ctx, userID, assignmentID)
+	}
+	return nil
+}
+
 // ===== MockDashboardService =====
 
 type MockDashboardService struct {
@@ -278,12 +718,16 @@ func (m *MockDashboardService) GetStats(ctx context.Context) (*model.DashboardSt
 // ===== MockNotificationService =====
 
 type MockNotificationService struct {
-	SendFunc           func(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, title, message string) error
-	GetByUserFunc      func(ctx context.Context, userID uuid.UUID, isRead *bool, page, pageSize int) ([]model.Notification, int64, error)
-	MarkAsReadFunc     func(ctx context.Context, id uuid.UUID) error
-	MarkAllAsReadFunc  func(ctx context.Context, userID uuid.UUID) error
-	GetUnreadCountFunc func(ctx context.Context, userID uuid.UUID) (int64, error)
-	DeleteFunc         func(ctx context.Context, id uuid.UUID) error
+	SendFunc              func(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, title, message string) error
+	DispatchFunc          func(ctx context.Context, n *model.Notification) error
+	AllowsFunc            func(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, channel model.NotificationChannel) bool
+	GetPreferencesFunc    func(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferencesResponse, error)
+	UpdatePreferencesFunc func(ctx context.Context, userID uuid.UUID, req *model.NotificationPreferencesRequest) (*model.NotificationPreferencesResponse, error)
+	GetByUserFunc         func(ctx context.Context, userID uuid.UUID, isRead *bool, page, pageSize int) ([]model.Notification, int64, error)
+	MarkAsReadFunc        func(ctx context.Context, id uuid.UUID) error
+	MarkAllAsReadFunc     func(ctx context.Context, userID uuid.UUID) error
+	GetUnreadCountFunc    func(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteFunc            func(ctx context.Context, id uuid.UUID) error
 }
 
 func (m *MockNotificationService) Send(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, title, message string) error {
@@ -293,6 +737,34 @@ func (m *MockNotificationService) Send(ctx context.Context, userID uuid.UUID, no
 	return nil
 }
 
+func (m *MockNotificationService) Dispatch(ctx context.Context, n *model.Notification) error {
+	if m.DispatchFunc != nil {
+		return m.DispatchFunc(ctx, n)
+	}
+	return nil
+}
+
+func (m *MockNotificationService) Allows(ctx context.Context, userID uuid.UUID, notifType model.NotificationType, channel model.NotificationChannel) bool {
+	if m.AllowsFunc != nil {
+		return m.AllowsFunc(ctx, userID, notifType, channel)
+	}
+	return true
+}
+
+func (m *MockNotificationService) GetPreferences(ctx context.Context, userID uuid.UUID) (*model.NotificationPreferencesResponse, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx, userID)
+	}
+	return &model.NotificationPreferencesResponse{}, nil
+}
+
+func (m *MockNotificationService) UpdatePreferences(ctx context.Context, userID uuid.UUID, req *model.NotificationPreferencesRequest) (*model.NotificationPreferencesResponse, error) {
+	if m.UpdatePreferencesFunc != nil {
+		return m.UpdatePreferencesFunc(ctx, userID, req)
+	}
+	return &model.NotificationPreferencesResponse{}, nil
+}
+
 func (m *MockNotificationService) GetByUser(ctx context.Context, userID uuid.UUID, isRead *bool, page, pageSize int) ([]model.Notification, int64, error) {
 	if m.GetByUserFunc != nil {
 		return m.GetByUserFunc(ctx, userID, isRead, page, pageSize)
@@ -327,3 +799,85 @@ func (m *MockNotificationService) Delete(ctx context.Context, id uuid.UUID) erro
 	}
 	return nil
 }
+
+// ===== MockChatActionService =====
+
+type MockChatActionService struct {
+	HandleSlackActionFunc func(ctx context.Context, timestamp, signature string, body []byte) (string, error)
+	HandleTeamsActionFunc func(ctx context.Context, authorization, token string) (string, error)
+}
+
+func (m *MockChatActionService) HandleSlackAction(ctx context.Context, timestamp, signature string, body []byte) (string, error) {
+	if m.HandleSlackActionFunc != nil {
+		return m.HandleSlackActionFunc(ctx, timestamp, signature, body)
+	}
+	return "", nil
+}
+
+func (m *MockChatActionService) HandleTeamsAction(ctx context.Context, authorization, token string) (string, error) {
+	if m.HandleTeamsActionFunc != nil {
+		return m.HandleTeamsActionFunc(ctx, authorization, token)
+	}
+	return "", nil
+}
+
+// ===== MockShiftScheduleService =====
+
+type MockShiftScheduleService struct {
+	CreateFunc      func(ctx context.Context, req *model.ShiftScheduleCreateRequest) (*model.ShiftSchedule, error)
+	GetAllFunc      func(ctx context.Context, departmentID *uuid.UUID) ([]model.ShiftSchedule, error)
+	GetByIDFunc     func(ctx context.Context, id uuid.UUID) (*model.ShiftSchedule, error)
+	GetShiftsFunc   func(ctx context.Context, id uuid.UUID) ([]model.Shift, error)
+	PublishFunc     func(ctx context.Context, id uuid.UUID, publisherID uuid.UUID, req *model.ShiftSchedulePublishRequest) (*model.ShiftSchedule, error)
+	GetVersionsFunc func(ctx context.Context, id uuid.UUID) ([]model.ShiftScheduleVersion, error)
+	GetDiffFunc     func(ctx context.Context, id uuid.UUID, fromVersion, toVersion int) (*model.ShiftScheduleDiff, error)
+}
+
+func (m *MockShiftScheduleService) Create(ctx context.Context, req *model.ShiftScheduleCreateRequest) (*model.ShiftSchedule, error) {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, req)
+	}
+	return &model.ShiftSchedule{}, nil
+}
+
+func (m *MockShiftScheduleService) GetAll(ctx context.Context, departmentID *uuid.UUID) ([]model.ShiftSchedule, error) {
+	if m.GetAllFunc != nil {
+		return m.GetAllFunc(ctx, departmentID)
+	}
+	return []model.ShiftSchedule{}, nil
+}
+
+func (m *MockShiftScheduleService) GetByID(ctx context.Context, id uuid.UUID) (*model.ShiftSchedule, error) {
+	if m.GetByIDFunc != nil {
+		return m.GetByIDFunc(ctx, id)
+	}
+	return &model.ShiftSchedule{}, nil
+}
+
+func (m *MockShiftScheduleService) GetShifts(ctx context.Context, id uuid.UUID) ([]model.Shift, error) {
+	if m.GetShiftsFunc != nil {
+		return m.GetShiftsFunc(ctx, id)
+	}
+	return []model.Shift{}, nil
+}
+
+func (m *MockShiftScheduleService) Publish(ctx context.Context, id uuid.UUID, publisherID uuid.UUID, req *model.ShiftSchedulePublishRequest) (*model.ShiftSchedule, error) {
+	if m.PublishFunc != nil {
+		return m.PublishFunc(ctx, id, publisherID, req)
+	}
+	return &model.ShiftSchedule{}, nil
+}
+
+func (m *MockShiftScheduleService) GetVersions(ctx context.Context, id uuid.UUID) ([]model.ShiftScheduleVersion, error) {
+	if m.GetVersionsFunc != nil {
+		return m.GetVersionsFunc(ctx, id)
+	}
+	return []model.ShiftScheduleVersion{}, nil
+}
+
+func (m *MockShiftScheduleService) GetDiff(ctx context.Context, id uuid.UUID, fromVersion, toVersion int) (*model.ShiftScheduleDiff, error) {
+	if m.GetDiffFunc != nil {
+		return m.GetDiffFunc(ctx, id, fromVersion, toVersion)
+	}
+	return &model.ShiftScheduleDiff{}, nil
+}
@@ -16,8 +16,43 @@ const (
 	AttendanceStatusAbsent  AttendanceStatus = "absent"
 	AttendanceStatusLeave   AttendanceStatus = "leave"
 	AttendanceStatusHoliday AttendanceStatus = "holiday"
+	// AttendanceStatusIncomplete は退勤打刻漏れ（勤怠修正申請による確定が必要）
+	AttendanceStatusIncomplete AttendanceStatus = "incomplete"
 )
 
+// WorkMode は勤務形態
+type WorkMode string
+
+const (
+	WorkModeOffice       WorkMode = "office"        // 出社
+	WorkModeRemote       WorkMode = "remote"        // 在宅勤務
+	WorkModeClientSite   WorkMode = "client_site"   // 客先
+	WorkModeBusinessTrip WorkMode = "business_trip" // 出張
+)
+
+// IsValid は定義済みの勤務形態かどうかを返す
+func (m WorkMode) IsValid() bool {
+	switch m {
+	case WorkModeOffice, WorkModeRemote, WorkModeClientSite, WorkModeBusinessTrip:
+		return true
+	}
+	return false
+}
+
+// Label は勤務形態の表示名を返す
+func (m WorkMode) Label() string {
+	switch m {
+	case WorkModeRemote:
+		return "在宅"
+	case WorkModeClientSite:
+		return "客先"
+	case WorkModeBusinessTrip:
+		return "出張"
+	default:
+		return "出社"
+	}
+}
+
 // Attendance は勤怠レコード
 type Attendance struct {
 	BaseModel
@@ -28,10 +63,16 @@ type Attendance struct {
 	BreakMinutes int              `gorm:"default:0" json:"break_minutes"`
 	Status       AttendanceStatus `gorm:"size:20;not null;default:'present'" json:"status"`
 	Note         string           `gorm:"size:500" json:"note"`
+	WorkMode     WorkMode         `gorm:"size:20;not null;default:'office'" json:"work_mode"`
+	AutoClosedAt *time.Time       `json:"auto_closed_at"` // 退勤打刻漏れを自動で締めた日時
 
-	// 計算フィールド
-	WorkMinutes     int `gorm:"default:0" json:"work_minutes"`
-	OvertimeMinutes int `gorm:"default:0" json:"overtime_minutes"`
+	// 計算フィールド（給与計算用の内訳）
+	WorkMinutes                    int `gorm:"default:0" json:"work_minutes"`
+	WithinStatutoryOvertimeMinutes int `gorm:"default:0" json:"within_statutory_overtime_minutes"` // 法定内残業（所定労働時間超〜8時間）
+	OvertimeMinutes                int `gorm:"default:0" json:"overtime_minutes"`                  // 法定外残業（8時間超・法定外休日の勤務）
+	LateNightMinutes               int `gorm:"default:0" json:"late_night_minutes"`                // 深夜労働（22:00〜5:00）
+	HolidayWorkMinutes             int `gorm:"default:0" json:"holiday_work_minutes"`              // 法定休日労働
+	OvertimeOver60Minutes          int `gorm:"default:0" json:"overtime_over_60_minutes"`          // 月60時間超の法定外残業（OvertimeMinutes の内数）
 
 	// GPS位置情報
 	ClockInLatitude   *float64 `gorm:"type:decimal(10,8)" json:"clock_in_latitude"`
@@ -123,6 +164,44 @@ type LeaveBalance struct {
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+// ===== 振替休日・代休 =====
+
+// SubstituteHolidayType は休日振替の種別
+type SubstituteHolidayType string
+
+const (
+	SubstituteHolidayTypeFurikae SubstituteHolidayType = "furikae" // 振替休日（事前に休日と勤務日を入れ替える）
+	SubstituteHolidayTypeDaikyu  SubstituteHolidayType = "daikyu"  // 代休（休日出勤の後に休みを取得する）
+)
+
+// SubstituteHolidayRequest は振替休日・代休の申請モデル
+type SubstituteHolidayRequest struct {
+	BaseModel
+	UserID            uuid.UUID             `gorm:"type:uuid;not null;index" json:"user_id"`
+	Type              SubstituteHolidayType `gorm:"size:20;not null" json:"type"`
+	WorkDate          *time.Time            `gorm:"type:date" json:"work_date"` // 振替休日: 出勤日に変わる休日
+	HolidayDate       time.Time             `gorm:"type:date;not null" json:"holiday_date"`
+	CompensatoryDayID *uuid.UUID            `gorm:"type:uuid" json:"compensatory_day_id"` // 代休: 消化した代休
+	Reason            string                `gorm:"size:500" json:"reason"`
+	Status            ApprovalStatus        `gorm:"size:20;not null;default:'pending'" json:"status"`
+	ApprovedBy        *uuid.UUID            `gorm:"type:uuid" json:"approved_by"`
+	ApprovedAt        *time.Time            `json:"approved_at"`
+	RejectedReason    string                `gorm:"size:500" json:"rejected_reason"`
+
+	User     *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Approver *User `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
+}
+
+// CompensatoryDay は休日出勤により付与された代休モデル
+type CompensatoryDay struct {
+	BaseModel
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	EarnedDate time.Time  `gorm:"type:date;not null" json:"earned_date"`
+	ExpiresAt  time.Time  `gorm:"type:date;not null" json:"expires_at"`
+	UsedDate   *time.Time `gorm:"type:date" json:"used_date"`
+	RequestID  *uuid.UUID `gorm:"type:uuid" json:"request_id"`
+}
+
 // ===== 勤怠修正申請 =====
 
 // CorrectionStatus は修正申請ステータス
@@ -150,7 +229,58 @@ type AttendanceCorrection struct {
 	ApprovedAt        *time.Time       `json:"approved_at"`
 	RejectedReason    string           `gorm:"size:500" json:"rejected_reason"`
 
+	// 休憩・ステータス・備考の修正（nil は変更なし）
+	OriginalBreakMinutes  *int              `json:"original_break_minutes"`
+	CorrectedBreakMinutes *int              `json:"corrected_break_minutes"`
+	OriginalStatus        *AttendanceStatus `gorm:"size:20" json:"original_status"`
+	CorrectedStatus       *AttendanceStatus `gorm:"size:20" json:"corrected_status"`
+	OriginalNote          *string           `gorm:"size:500" json:"original_note"`
+	CorrectedNote         *string           `gorm:"size:500" json:"corrected_note"`
+
 	User       *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
 	Attendance *Attendance `gorm:"foreignKey:AttendanceID" json:"attendance,omitempty"`
 	Approver   *User       `gorm:"foreignKey:ApprovedBy" json:"approver,omitempty"`
 }
+
+// ===== 労働時間制度 =====
+
+// WorkingHourSystem は労働時間制度の種別
+type WorkingHourSystem string
+
+const (
+	WorkingHourSystemStandard        WorkingHourSystem = "standard"         // 通常の労働時間制
+	WorkingHourSystemFlextime        WorkingHourSystem = "flextime"         // フレックスタイム制
+	WorkingHourSystemMonthlyVariable WorkingHourSystem = "monthly_variable" // 1ヶ月単位の変形労働時間制
+	WorkingHourSystemYearlyVariable  WorkingHourSystem = "yearly_variable"  // 1年単位の変形労働時間制
+)
+
+// WorkingHourPolicy は部署ごとの労働時間制度の設定
+type WorkingHourPolicy struct {
+	BaseModel
+	DepartmentID         uuid.UUID         `gorm:"type:uuid;not null;uniqueIndex" json:"department_id"`
+	System               WorkingHourSystem `gorm:"size:30;not null;default:'standard'" json:"system"`
+	PeriodStart          time.Time         `gorm:"type:date;not null" json:"period_start"`             // 清算期間・対象期間の起算日
+	PeriodMonths         int               `gorm:"not null;default:1" json:"period_months"`            // 清算期間・対象期間の月数
+	StandardDailyMinutes int               `gorm:"not null;default:480" json:"standard_daily_minutes"` // 1日の標準労働時間
+	CoreTimeStart        string            `gorm:"size:5" json:"core_time_start"`                      // コアタイム開始（HH:MM、フレックスのみ）
+	CoreTimeEnd          string            `gorm:"size:5" json:"core_time_end"`                        // コアタイム終了（HH:MM、フレックスのみ）
+
+	Department *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
+
+// WorkingHourScheduleDay は変形労働時間制の日別の所定労働時間（0 は休日）
+type WorkingHourScheduleDay struct {
+	BaseModel
+	PolicyID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_working_hour_schedule_day" json:"policy_id"`
+	Date             time.Time `gorm:"type:date;not null;uniqueIndex:idx_working_hour_schedule_day" json:"date"`
+	ScheduledMinutes int       `gorm:"not null;default:0" json:"scheduled_minutes"`
+}
+
+// RemoteWorkPolicy は部署ごとの在宅勤務の上限設定
+type RemoteWorkPolicy struct {
+	BaseModel
+	DepartmentID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"department_id"`
+	MaxRemoteDaysPerWeek int       `gorm:"not null;default:5" json:"max_remote_days_per_week"` // 週（月〜日）あたりの上限日数。0 は在宅勤務不可
+
+	Department *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -19,7 +20,7 @@ type BaseModel struct {
 
 // ===== ユーザー =====
 
-// Role はユーザーの権限を表す
+// Role はユーザーの基本ロールを表す。追加の権限はカスタムロールで付与する
 type Role string
 
 const (
@@ -38,6 +39,10 @@ type User struct {
 	Role         Role       `gorm:"size:20;not null;default:'employee'" json:"role"`
 	DepartmentID *uuid.UUID `gorm:"type:uuid" json:"department_id"`
 	IsActive     bool       `gorm:"default:true" json:"is_active"`
+	// EmailVerifiedAt はメールアドレスの確認日時。未確認の自己登録ユーザーは nil
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	// PasswordLoginDisabled が true のユーザーはシングルサインオンでのみログインできる
+	PasswordLoginDisabled bool `gorm:"not null;default:false" json:"password_login_disabled"`
 
 	// リレーション
 	Department    *Department    `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
@@ -59,13 +64,229 @@ type Department struct {
 
 // ===== リフレッシュトークン =====
 
-// RefreshToken はリフレッシュトークン管理用モデル
+// RefreshToken はリフレッシュトークン管理用モデル。
+// トークン本体は保存せず SHA-256 ハッシュのみを保持する。
+// ローテーションで発行されたトークンは同じ FamilyID を引き継ぐ（ファミリーが1つのセッション）
 type RefreshToken struct {
 	BaseModel
-	UserID    uuid.UUID `gorm:"type:uuid;not null;index" json:"user_id"`
-	Token     string    `gorm:"size:500;not null;uniqueIndex" json:"-"`
-	ExpiresAt time.Time `gorm:"not null" json:"expires_at"`
-	IsRevoked bool      `gorm:"default:false" json:"is_revoked"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	TokenHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	ExpiresAt time.Time  `gorm:"not null;index" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+	IsRevoked bool       `gorm:"default:false" json:"is_revoked"`
+	// 発行時のクライアントと、ファミリーの開始（ログイン）日時
+	IPAddress string    `gorm:"size:45" json:"ip_address"`
+	UserAgent string    `gorm:"size:500" json:"user_agent"`
+	LoginAt   time.Time `gorm:"not null;default:now()" json:"login_at"`
 
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
+
+// ===== ワンタイムトークン =====
+
+// UserTokenPurpose はワンタイムトークンの用途
+type UserTokenPurpose string
+
+const (
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+	// UserTokenSSOLogin はSSOのコールバックからフロントエンドへ渡すログインコード
+	UserTokenSSOLogin UserTokenPurpose = "sso_login"
+)
+
+// UserToken はパスワードリセット・メールアドレス確認・SSOログイン用の一回限りのトークン。
+// リフレッシュトークンと同様にトークン本体は保存せずハッシュのみを保持する
+type UserToken struct {
+	BaseModel
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;index" json:"user_id"`
+	Purpose   UserTokenPurpose `gorm:"size:30;not null" json:"purpose"`
+	TokenHash string           `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time        `gorm:"not null;index" json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+// ===== 外部ID（シングルサインオン） =====
+
+// UserIdentity はIdPのユーザー（発行者と sub の組）とユーザーの紐付け
+type UserIdentity struct {
+	BaseModel
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Issuer      string     `gorm:"size:255;not null;uniqueIndex:idx_user_identities_issuer_subject" json:"issuer"`
+	Subject     string     `gorm:"size:255;not null;uniqueIndex:idx_user_identities_issuer_subject" json:"subject"`
+	Email       string     `gorm:"size:255" json:"email"`
+	LastLoginAt *time.Time `json:"last_login_at"`
+}
+
+// ===== ログイン履歴・ロックアウト =====
+
+// LoginMethod はログインの方式
+type LoginMethod string
+
+const (
+	LoginMethodPassword LoginMethod = "password"
+	LoginMethodSSO      LoginMethod = "sso"
+)
+
+// LoginHistory はログインの試行の記録。未登録のメールアドレスへの試行は UserID が nil
+type LoginHistory struct {
+	BaseModel
+	UserID *uuid.UUID  `gorm:"type:uuid;index" json:"user_id"`
+	Email  string      `gorm:"size:255;not null" json:"email"`
+	Method LoginMethod `gorm:"size:20;not null" json:"method"`
+	// MFA は多要素認証を経たログインかどうか
+	MFA     bool `gorm:"not null;default:false" json:"mfa"`
+	Success bool `gorm:"not null;index" json:"success"`
+	// FailureReason は失敗の理由（invalid_credentials, account_locked など）
+	FailureReason string `gorm:"size:50" json:"failure_reason,omitempty"`
+	IPAddress     string `gorm:"size:45" json:"ip_address"`
+	UserAgent     string `gorm:"size:500" json:"user_agent"`
+	// NewDevice はこれまでログインに成功したことのない端末（User-Agent）からのログイン
+	NewDevice bool `gorm:"not null;default:false" json:"new_device"`
+}
+
+// LoginLockout はアカウントごとのログイン失敗の状態。ログインに成功するか管理者が解除すると削除する
+type LoginLockout struct {
+	BaseModel
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	// FailedCount は直近のロック以降の連続失敗回数、LockoutCount はロックの回数（ロック時間の倍率）
+	FailedCount  int        `gorm:"not null;default:0" json:"failed_count"`
+	LockoutCount int        `gorm:"not null;default:0" json:"lockout_count"`
+	LockedUntil  *time.Time `json:"locked_until"`
+	LastFailedAt *time.Time `json:"last_failed_at"`
+}
+
+// ===== APIトークン・サービスアカウント =====
+
+// ServiceAccount は連携用スクリプトなど人ではない利用者。権限は基本ロールで決まり、APIトークンでのみ認証する
+type ServiceAccount struct {
+	BaseModel
+	Name        string     `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Description string     `gorm:"size:500" json:"description"`
+	Role        Role       `gorm:"size:20;not null" json:"role"`
+	IsActive    bool       `gorm:"not null;default:true" json:"is_active"`
+	CreatedBy   *uuid.UUID `gorm:"type:uuid" json:"created_by"`
+}
+
+// APIToken は個人用アクセストークンまたはサービスアカウントのトークン（ハッシュのみ保存）。
+// UserID と ServiceAccountID のどちらか一方を持つ
+type APIToken struct {
+	BaseModel
+	Name             string     `gorm:"size:100;not null" json:"name"`
+	UserID           *uuid.UUID `gorm:"type:uuid;index" json:"user_id,omitempty"`
+	ServiceAccountID *uuid.UUID `gorm:"type:uuid;index" json:"service_account_id,omitempty"`
+	TokenHash        string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	// Prefix はトークンの先頭部分（一覧での識別用）
+	Prefix string `gorm:"size:20;not null" json:"prefix"`
+	// Scopes は利用できる操作の範囲（"attendance:read" など）の一覧
+	Scopes     datatypes.JSON `gorm:"type:jsonb;not null" json:"scopes"`
+	ExpiresAt  time.Time      `gorm:"not null" json:"expires_at"`
+	LastUsedAt *time.Time     `json:"last_used_at"`
+	LastUsedIP string         `gorm:"size:45" json:"last_used_ip"`
+	RevokedAt  *time.Time     `json:"revoked_at"`
+}
+
+// ===== 多要素認証 =====
+
+// UserMFA はユーザーのTOTP設定。EnabledAt が nil の間は登録途中
+type UserMFA struct {
+	BaseModel
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	Secret    string     `gorm:"size:64;not null" json:"-"`
+	EnabledAt *time.Time `json:"enabled_at"`
+	// LastUsedStep は最後に受け付けたコードのタイムステップ（同じコードの再利用を防ぐ）
+	LastUsedStep int64 `gorm:"not null;default:0" json:"-"`
+}
+
+// MFARecoveryCode は認証アプリを使えない場合の一回限りのリカバリーコード（ハッシュのみ保存）
+type MFARecoveryCode struct {
+	BaseModel
+	UserID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	CodeHash string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	UsedAt   *time.Time `json:"used_at"`
+}
+
+// MFAPolicy はMFAを必須とするロールの設定（全社で1件）
+type MFAPolicy struct {
+	BaseModel
+	// RequiredRoles は基本ロールの一覧、RequiredCustomRoleIDs はカスタムロールIDの一覧
+	RequiredRoles         datatypes.JSON `gorm:"type:jsonb;not null" json:"required_roles"`
+	RequiredCustomRoleIDs datatypes.JSON `gorm:"type:jsonb;not null" json:"required_custom_role_ids"`
+}
+
+// ===== カスタムロール =====
+
+// CustomRole は管理者が定義する権限のセット
+type CustomRole struct {
+	BaseModel
+	Name        string         `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Description string         `gorm:"size:500" json:"description"`
+	Permissions datatypes.JSON `gorm:"type:jsonb;not null" json:"permissions"`
+}
+
+// UserRoleAssignment はユーザーへのカスタムロールの割り当て。
+// DepartmentID を指定した場合、権限はその部署の所属者のリソースに限定される
+type UserRoleAssignment struct {
+	BaseModel
+	UserID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	RoleID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"role_id"`
+	DepartmentID *uuid.UUID `gorm:"type:uuid" json:"department_id"`
+
+	User       *User       `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Role       *CustomRole `gorm:"foreignKey:RoleID" json:"role,omitempty"`
+	Department *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
+
+// ===== 監査ログ =====
+
+// AuditAction は監査ログの操作
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// AuditActorType は操作主体の種類。anonymous は未認証のリクエスト（ログイン・登録など）、system はバックグラウンドジョブ
+type AuditActorType string
+
+const (
+	AuditActorUser           AuditActorType = "user"
+	AuditActorServiceAccount AuditActorType = "service_account"
+	AuditActorAnonymous      AuditActorType = "anonymous"
+	AuditActorSystem         AuditActorType = "system"
+)
+
+// AuditChanges は変更前後の値（{"列名": {"old": 変更前, "new": 変更後}}）のJSON。
+// ハッシュの計算対象のため、保存したバイト列のまま扱う
+type AuditChanges string
+
+// MarshalJSON は保存したJSONをそのまま出力する
+func (c AuditChanges) MarshalJSON() ([]byte, error) {
+	if c == "" {
+		return []byte("null"), nil
+	}
+	return []byte(c), nil
+}
+
+// AuditLog はデータの作成・更新・削除の記録。追記のみで、Seq の順に前の記録のハッシュを含めて
+// ハッシュチェーンを構成する（途中の記録の改ざん・削除を検出できる）
+type AuditLog struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:gen_random_uuid();primaryKey" json:"id"`
+	Seq        int64          `gorm:"not null;uniqueIndex" json:"seq"`
+	OccurredAt time.Time      `gorm:"not null;index" json:"occurred_at"`
+	ActorID    *uuid.UUID     `gorm:"type:uuid;index" json:"actor_id"`
+	ActorType  AuditActorType `gorm:"size:20;not null" json:"actor_type"`
+	Action     AuditAction    `gorm:"size:20;not null" json:"action"`
+	// EntityType はテーブル名、EntityID は主キー
+	EntityType string       `gorm:"size:100;not null;index:idx_audit_logs_entity" json:"entity_type"`
+	EntityID   string       `gorm:"size:100;index:idx_audit_logs_entity" json:"entity_id"`
+	Changes    AuditChanges `gorm:"type:text" json:"changes"`
+	IPAddress  string       `gorm:"size:45" json:"ip_address"`
+	RequestID  string       `gorm:"size:64;index" json:"request_id"`
+	PrevHash   string       `gorm:"size:64;not null" json:"prev_hash"`
+	Hash       string       `gorm:"size:64;not null" json:"hash"`
+}
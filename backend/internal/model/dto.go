@@ -1,6 +1,8 @@
 package model
 
 import (
+	"fmt"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
@@ -20,21 +22,197 @@ type RegisterRequest struct {
 	LastName  string `json:"last_name" validate:"required"`
 }
 
+// ForgotPasswordRequest はパスワードリセットメールの送信リクエスト
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest はリセットトークンによるパスワード再設定リクエスト
+type ResetPasswordRequest struct {
+	Token       string `json:"token" validate:"required"`
+	NewPassword string `json:"new_password" validate:"required,min=8"`
+}
+
+// VerifyEmailRequest はメールアドレス確認リクエスト
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// ResendVerificationRequest は確認メールの再送リクエスト
+type ResendVerificationRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// TokenResponse はトークンの発行結果。Cookie認証モードではトークンを HttpOnly Cookie で返し、
+// 本文には AccessToken・RefreshToken の代わりに CSRFToken を含める
 type TokenResponse struct {
-	AccessToken  string `json:"access_token"`
-	RefreshToken string `json:"refresh_token"`
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
 	ExpiresIn    int    `json:"expires_in"`
+	CSRFToken    string `json:"csrf_token,omitempty"`
 	User         *User  `json:"user,omitempty"`
+
+	// MFA が必要な場合はトークンを発行せず、MFAToken で /auth/mfa/* を呼び出させる。
+	// MFASetupRequired はポリシーで必須だが未登録の場合
+	MFARequired      bool   `json:"mfa_required,omitempty"`
+	MFASetupRequired bool   `json:"mfa_setup_required,omitempty"`
+	MFAToken         string `json:"mfa_token,omitempty"`
+}
+
+// ===== 多要素認証 =====
+
+// MFAVerifyRequest はログイン時のMFAチャレンジへの応答（Code はTOTPまたはリカバリーコード）
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+	Code     string `json:"code" validate:"required"`
+}
+
+// MFATokenRequest はログイン時のMFA登録開始リクエスト
+type MFATokenRequest struct {
+	MFAToken string `json:"mfa_token" validate:"required"`
+}
+
+// MFACodeRequest は認証済みユーザーのTOTPコード
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required"`
+}
+
+// MFASetupResponse は認証アプリへの登録情報
+type MFASetupResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAEnrollmentResponse はMFA有効化時の応答。リカバリーコードはこの時だけ返す。
+// ログイン時の登録ではトークンも発行する
+type MFAEnrollmentResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+	*TokenResponse
+}
+
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at"`
+	Required               bool       `json:"required"`
+	RecoveryCodesRemaining int64      `json:"recovery_codes_remaining"`
+}
+
+type MFAPolicyRequest struct {
+	RequiredRoles         []Role      `json:"required_roles"`
+	RequiredCustomRoleIDs []uuid.UUID `json:"required_custom_role_ids"`
+}
+
+// SSOAuthorization はIdPへのリダイレクト先と、コールバックで照合する署名付きのフロー情報
+type SSOAuthorization struct {
+	AuthorizationURL string
+	FlowToken        string
+	ExpiresAt        time.Time
+}
+
+// SSOCallback はIdPからのコールバックのクエリ
+type SSOCallback struct {
+	Code             string `form:"code"`
+	State            string `form:"state"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
+
+// SSOExchangeRequest はコールバックでフロントエンドに渡したログインコードをトークンに交換する
+type SSOExchangeRequest struct {
+	Code string `json:"code" validate:"required"`
 }
 
 type RefreshTokenRequest struct {
 	RefreshToken string `json:"refresh_token" validate:"required"`
 }
 
+// CSRFTokenResponse はCookie認証モードで状態を変更するリクエストの X-CSRF-Token ヘッダーに設定する値
+type CSRFTokenResponse struct {
+	CSRFToken string `json:"csrf_token"`
+}
+
+// ===== セッション =====
+
+// Session はログイン中のセッション（リフレッシュトークンのファミリー）。ID はファミリーID
+type Session struct {
+	ID        uuid.UUID `json:"id"`
+	Device    string    `json:"device"`
+	IPAddress string    `json:"ip_address"`
+	UserAgent string    `json:"user_agent"`
+	LoginAt   time.Time `json:"login_at"`
+	// LastUsedAt は最後にトークンを発行（ログイン・リフレッシュ）した日時
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	// Current はリクエストに使ったアクセストークンのセッション
+	Current bool `json:"current"`
+}
+
+// SessionRevokeResponse は終了したセッションの数
+type SessionRevokeResponse struct {
+	Revoked int `json:"revoked"`
+}
+
+// ===== APIトークン・サービスアカウント =====
+
+type APITokenRequest struct {
+	Name   string   `json:"name" validate:"required"`
+	Scopes []string `json:"scopes" validate:"required"`
+	// ExpiresInDays は有効期限（日）。上限は API_TOKEN_MAX_EXPIRY_DAYS
+	ExpiresInDays int `json:"expires_in_days" validate:"required"`
+}
+
+// APITokenIssued は発行したトークン。Token は発行時にのみ返し、保存しない
+type APITokenIssued struct {
+	Token    string    `json:"token"`
+	APIToken *APIToken `json:"api_token"`
+}
+
+// APITokenScopeDefinition はAPIトークンに設定できるスコープ
+type APITokenScopeDefinition struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type ServiceAccountRequest struct {
+	Name        string `json:"name" validate:"required"`
+	Description string `json:"description"`
+	Role        Role   `json:"role" validate:"required"`
+}
+
+type ServiceAccountUpdateRequest struct {
+	Description *string `json:"description"`
+	Role        *Role   `json:"role"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+// ===== 監査ログ =====
+
+// AuditLogQuery は監査ログの検索条件（期間は発生日で、終了日を含む）
+type AuditLogQuery struct {
+	ActorID    string      `form:"actor_id" binding:"omitempty,uuid"`
+	Action     AuditAction `form:"action"`
+	EntityType string      `form:"entity_type"`
+	EntityID   string      `form:"entity_id"`
+	RequestID  string      `form:"request_id"`
+	StartDate  *time.Time  `form:"start_date" time_format:"2006-01-02"`
+	EndDate    *time.Time  `form:"end_date" time_format:"2006-01-02"`
+	Page       int         `form:"page,default=1"`
+	PageSize   int         `form:"page_size,default=20"`
+}
+
+// AuditVerifyResult はハッシュチェーンの検証結果。改ざんを検出した場合は最初の記録の Seq と理由を返す
+type AuditVerifyResult struct {
+	Valid     bool   `json:"valid"`
+	Checked   int64  `json:"checked"`
+	BrokenSeq *int64 `json:"broken_seq,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
 // ===== 打刻 =====
 
 type ClockInRequest struct {
-	Note string `json:"note"`
+	Note     string   `json:"note"`
+	WorkMode WorkMode `json:"work_mode" validate:"omitempty,oneof=office remote client_site business_trip"` // 省略時は出社
 }
 
 type ClockOutRequest struct {
@@ -52,12 +230,97 @@ type AttendanceListQuery struct {
 }
 
 type AttendanceSummary struct {
-	TotalWorkDays        int     `json:"total_work_days"`
-	TotalWorkMinutes     int     `json:"total_work_minutes"`
-	TotalOvertimeMinutes int     `json:"total_overtime_minutes"`
-	AverageWorkMinutes   float64 `json:"average_work_minutes"`
-	AbsentDays           int     `json:"absent_days"`
-	LeaveDays            int     `json:"leave_days"`
+	TotalWorkDays                       int     `json:"total_work_days"`
+	TotalWorkMinutes                    int     `json:"total_work_minutes"`
+	TotalOvertimeMinutes                int     `json:"total_overtime_minutes"`
+	TotalWithinStatutoryOvertimeMinutes int     `json:"total_within_statutory_overtime_minutes"`
+	TotalLateNightMinutes               int     `json:"total_late_night_minutes"`
+	TotalHolidayWorkMinutes             int     `json:"total_holiday_work_minutes"`
+	TotalOvertimeOver60Minutes          int     `json:"total_overtime_over_60_minutes"`
+	AverageWorkMinutes                  float64 `json:"average_work_minutes"`
+	AbsentDays                          int     `json:"absent_days"`
+	LeaveDays                           int     `json:"leave_days"`
+	IncompleteDays                      int     `json:"incomplete_days"`
+
+	WorkModeDays     WorkModeDays          `json:"work_mode_days"`
+	MonthlyWorkModes []MonthlyWorkModeDays `json:"monthly_work_modes"`
+}
+
+// AddWorkModeDay は出勤日1日分を勤務形態の合計と月別の内訳に加算する
+func (s *AttendanceSummary) AddWorkModeDay(date time.Time, mode WorkMode) {
+	s.WorkModeDays.Add(mode)
+	month := date.Format("2006-01")
+	for i := range s.MonthlyWorkModes {
+		if s.MonthlyWorkModes[i].Month == month {
+			s.MonthlyWorkModes[i].Add(mode)
+			return
+		}
+	}
+	entry := MonthlyWorkModeDays{Month: month}
+	entry.Add(mode)
+	s.MonthlyWorkModes = append(s.MonthlyWorkModes, entry)
+	sort.Slice(s.MonthlyWorkModes, func(i, j int) bool { return s.MonthlyWorkModes[i].Month < s.MonthlyWorkModes[j].Month })
+}
+
+// WorkModeDays は勤務形態ごとの出勤日数
+type WorkModeDays struct {
+	Office       int `json:"office"`
+	Remote       int `json:"remote"`
+	ClientSite   int `json:"client_site"`
+	BusinessTrip int `json:"business_trip"`
+}
+
+// Add は勤務形態の日数を1日加算する（未設定は出社として扱う）
+func (d *WorkModeDays) Add(mode WorkMode) {
+	d.AddDays(mode, 1)
+}
+
+// AddDays は勤務形態の日数を days 日加算する
+func (d *WorkModeDays) AddDays(mode WorkMode, days int) {
+	switch mode {
+	case WorkModeRemote:
+		d.Remote += days
+	case WorkModeClientSite:
+		d.ClientSite += days
+	case WorkModeBusinessTrip:
+		d.BusinessTrip += days
+	default:
+		d.Office += days
+	}
+}
+
+type MonthlyWorkModeDays struct {
+	Month string `json:"month"` // YYYY-MM
+	WorkModeDays
+}
+
+// UserWorkModeCount はユーザー・月・勤務形態ごとの出勤日数の集計行
+type UserWorkModeCount struct {
+	UserID   uuid.UUID
+	Month    string // YYYY-MM
+	WorkMode WorkMode
+	Days     int
+}
+
+// ===== チーム（直属の部下） =====
+
+// TeamMemberStatus は部下1人分の本日の勤怠状況（未打刻の場合 Attendance は nil）
+type TeamMemberStatus struct {
+	User       User        `json:"user"`
+	Attendance *Attendance `json:"attendance"`
+}
+
+type TeamMemberSummary struct {
+	User    User               `json:"user"`
+	Summary *AttendanceSummary `json:"summary"`
+}
+
+// TeamPendingRequests は部下から提出された承認待ちの申請
+type TeamPendingRequests struct {
+	Leaves             []LeaveRequest             `json:"leaves"`
+	OvertimeRequests   []OvertimeRequest          `json:"overtime_requests"`
+	Corrections        []AttendanceCorrection     `json:"corrections"`
+	SubstituteHolidays []SubstituteHolidayRequest `json:"substitute_holidays"`
 }
 
 // ===== 休暇申請 =====
@@ -89,6 +352,48 @@ type ShiftBulkCreateRequest struct {
 	Shifts []ShiftCreateRequest `json:"shifts" validate:"required,dive"`
 }
 
+type ShiftUpdateRequest struct {
+	ShiftType *ShiftType `json:"shift_type" validate:"omitempty,oneof=morning day evening night off"`
+	Note      *string    `json:"note"`
+}
+
+// ===== シフト表 =====
+
+type ShiftScheduleCreateRequest struct {
+	DepartmentID uuid.UUID `json:"department_id" validate:"required"`
+	PeriodStart  string    `json:"period_start" validate:"required"`
+	PeriodEnd    string    `json:"period_end" validate:"required"`
+}
+
+type ShiftSchedulePublishRequest struct {
+	ChangeNote string `json:"change_note"`
+}
+
+// ShiftSnapshot はシフト表バージョンに保存される1件分のシフト
+type ShiftSnapshot struct {
+	ShiftID   uuid.UUID `json:"shift_id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Date      string    `json:"date"`
+	ShiftType ShiftType `json:"shift_type"`
+	StartTime string    `json:"start_time,omitempty"`
+	EndTime   string    `json:"end_time,omitempty"`
+	Note      string    `json:"note,omitempty"`
+}
+
+type ShiftChange struct {
+	Before ShiftSnapshot `json:"before"`
+	After  ShiftSnapshot `json:"after"`
+}
+
+type ShiftScheduleDiff struct {
+	ScheduleID  uuid.UUID       `json:"schedule_id"`
+	FromVersion int             `json:"from_version"`
+	ToVersion   int             `json:"to_version"`
+	Added       []ShiftSnapshot `json:"added"`
+	Removed     []ShiftSnapshot `json:"removed"`
+	Changed     []ShiftChange   `json:"changed"`
+}
+
 // ===== ユーザー管理 =====
 
 type UserCreateRequest struct {
@@ -98,6 +403,8 @@ type UserCreateRequest struct {
 	LastName     string     `json:"last_name" validate:"required"`
 	Role         Role       `json:"role" validate:"required,oneof=admin manager employee"`
 	DepartmentID *uuid.UUID `json:"department_id"`
+	// PasswordLoginDisabled が true の場合はSSOでのみログインでき、パスワードは省略できる
+	PasswordLoginDisabled bool `json:"password_login_disabled"`
 }
 
 type UserUpdateRequest struct {
@@ -107,6 +414,28 @@ type UserUpdateRequest struct {
 	DepartmentID *uuid.UUID `json:"department_id"`
 	IsActive     *bool      `json:"is_active"`
 	Password     *string    `json:"password"`
+	// PasswordLoginDisabled はパスワードでのログインを禁止する（SSOのみ）
+	PasswordLoginDisabled *bool `json:"password_login_disabled"`
+}
+
+// ===== カスタムロール =====
+
+// PermissionDefinition は付与可能な権限の定義
+type PermissionDefinition struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	RoleScopes  map[Role]string `json:"role_scopes"`
+}
+
+type CustomRoleRequest struct {
+	Name        string   `json:"name" validate:"required"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions" validate:"required"`
+}
+
+type RoleAssignmentRequest struct {
+	RoleID       uuid.UUID  `json:"role_id" validate:"required"`
+	DepartmentID *uuid.UUID `json:"department_id"`
 }
 
 // ===== ページネーション =====
@@ -122,9 +451,41 @@ type PaginatedResponse struct {
 // ===== エラーレスポンス =====
 
 type ErrorResponse struct {
-	Code    int    `json:"code"`
+	Code    int          `json:"code"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Errors  []FieldError `json:"errors,omitempty"`
+}
+
+// FieldError は入力項目単位のエラー
+type FieldError struct {
+	Field   string `json:"field"`
 	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+}
+
+// ValidationError は項目単位のエラーをまとめた入力検証エラー
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 0 {
+		return "入力内容に誤りがあります"
+	}
+	return fmt.Sprintf("入力内容に誤りがあります: %s %s", e.Fields[0].Field, e.Fields[0].Message)
+}
+
+// Add は項目エラーを追加する
+func (e *ValidationError) Add(field, message string) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: message})
+}
+
+// OrNil はエラーが無ければ nil を返す
+func (e *ValidationError) OrNil() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
 }
 
 // ===== ダッシュボード =====
@@ -201,10 +562,13 @@ type LeaveBalanceUpdate struct {
 // ===== 勤怠修正申請 =====
 
 type AttendanceCorrectionCreate struct {
-	Date              string  `json:"date" validate:"required"`
-	CorrectedClockIn  *string `json:"corrected_clock_in"`
-	CorrectedClockOut *string `json:"corrected_clock_out"`
-	Reason            string  `json:"reason" validate:"required"`
+	Date                  string            `json:"date" validate:"required"`
+	CorrectedClockIn      *string           `json:"corrected_clock_in"`
+	CorrectedClockOut     *string           `json:"corrected_clock_out"`
+	CorrectedBreakMinutes *int              `json:"corrected_break_minutes" validate:"omitempty,min=0"`
+	CorrectedStatus       *AttendanceStatus `json:"corrected_status" validate:"omitempty,oneof=present absent leave holiday"`
+	CorrectedNote         *string           `json:"corrected_note" validate:"omitempty,max=500"`
+	Reason                string            `json:"reason" validate:"required"`
 }
 
 type AttendanceCorrectionApproval struct {
@@ -212,6 +576,76 @@ type AttendanceCorrectionApproval struct {
 	RejectedReason string           `json:"rejected_reason"`
 }
 
+// ===== 振替休日・代休 =====
+
+type SubstituteHolidayRequestCreate struct {
+	Type        SubstituteHolidayType `json:"type" validate:"required,oneof=furikae daikyu"`
+	WorkDate    string                `json:"work_date"` // 振替休日のみ必須
+	HolidayDate string                `json:"holiday_date" validate:"required"`
+	Reason      string                `json:"reason" validate:"required"`
+}
+
+type SubstituteHolidayApproval struct {
+	Status         ApprovalStatus `json:"status" validate:"required,oneof=approved rejected"`
+	RejectedReason string         `json:"rejected_reason"`
+}
+
+type CompensatoryDaySummary struct {
+	Available int               `json:"available"`
+	Days      []CompensatoryDay `json:"days"`
+}
+
+// ===== 在宅勤務ポリシー =====
+
+type RemoteWorkPolicyRequest struct {
+	MaxRemoteDaysPerWeek *int `json:"max_remote_days_per_week" validate:"required,min=0,max=7"`
+}
+
+// ===== 労働時間制度 =====
+
+type WorkingHourPolicyRequest struct {
+	System               WorkingHourSystem `json:"system" validate:"required,oneof=standard flextime monthly_variable yearly_variable"`
+	PeriodStart          string            `json:"period_start" validate:"required"`
+	PeriodMonths         int               `json:"period_months"`
+	StandardDailyMinutes int               `json:"standard_daily_minutes"`
+	CoreTimeStart        string            `json:"core_time_start"`
+	CoreTimeEnd          string            `json:"core_time_end"`
+}
+
+type WorkingHourScheduleDayInput struct {
+	Date             string `json:"date" validate:"required"`
+	ScheduledMinutes int    `json:"scheduled_minutes"`
+}
+
+// WorkingHourScheduleRequest は変形労働時間制の対象期間の勤務カレンダー（期間内の全日を指定する）
+type WorkingHourScheduleRequest struct {
+	PeriodStart string                        `json:"period_start" validate:"required"`
+	Days        []WorkingHourScheduleDayInput `json:"days" validate:"required,min=1"`
+}
+
+// WorkingHourSettlementReport は清算期間・対象期間の締め集計
+type WorkingHourSettlementReport struct {
+	DepartmentID          uuid.UUID                   `json:"department_id"`
+	System                WorkingHourSystem           `json:"system"`
+	PeriodStart           string                      `json:"period_start"`
+	PeriodEnd             string                      `json:"period_end"`
+	RequiredMinutes       int                         `json:"required_minutes"`        // 所定総労働時間
+	StatutoryLimitMinutes int                         `json:"statutory_limit_minutes"` // 法定労働時間の総枠（40時間×暦日数÷7）
+	Users                 []WorkingHourSettlementUser `json:"users"`
+}
+
+type WorkingHourSettlementUser struct {
+	UserID                         uuid.UUID `json:"user_id"`
+	UserName                       string    `json:"user_name"`
+	ActualMinutes                  int       `json:"actual_minutes"` // 実労働時間（法定休日労働を除く）
+	WithinStatutoryOvertimeMinutes int       `json:"within_statutory_overtime_minutes"`
+	OvertimeMinutes                int       `json:"overtime_minutes"`
+	ShortageMinutes                int       `json:"shortage_minutes"` // 所定総労働時間に対する不足（フレックスのみ）
+	LateNightMinutes               int       `json:"late_night_minutes"`
+	HolidayWorkMinutes             int       `json:"holiday_work_minutes"`
+	CoreTimeViolations             []string  `json:"core_time_violations"` // コアタイムを満たさなかった日
+}
+
 // ===== 通知 =====
 
 type NotificationListQuery struct {
@@ -225,6 +659,52 @@ type NotificationCount struct {
 	Unread int `json:"unread"`
 }
 
+// NotificationPreferenceItem は通知種別・チャネルごとの受信可否
+type NotificationPreferenceItem struct {
+	Type    NotificationType    `json:"type"`
+	Channel NotificationChannel `json:"channel"`
+	Enabled bool                `json:"enabled"`
+}
+
+// NotificationQuietHours はおやすみ時間帯（Start・End は HH:MM、TimeZone はIANAのタイムゾーン名）
+type NotificationQuietHours struct {
+	Enabled  bool   `json:"enabled"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	TimeZone string `json:"time_zone"`
+}
+
+// NotificationChatDestination はチャット通知の配信先の更新。WebhookURL を空にすると配信先を削除する。
+// UserID は本人のチャットのアカウントで、未登録の場合は承認依頼に承認・却下ボタンを付けない
+type NotificationChatDestination struct {
+	Provider   ChatProvider `json:"provider"`
+	WebhookURL string       `json:"webhook_url"`
+	UserID     string       `json:"user_id"`
+}
+
+// NotificationChatStatus はチャット通知の配信先の状態。Webhook URL は秘密情報のため返さない
+type NotificationChatStatus struct {
+	Provider   ChatProvider `json:"provider"`
+	Configured bool         `json:"configured"`
+	UserID     string       `json:"user_id"`
+}
+
+// NotificationPreferencesRequest は受信設定の更新。指定した組み合わせ・おやすみ時間帯・チャットの配信先のみ変更する
+type NotificationPreferencesRequest struct {
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+	QuietHours  *NotificationQuietHours      `json:"quiet_hours"`
+	Chat        *NotificationChatDestination `json:"chat"`
+}
+
+// NotificationPreferencesResponse は全種別・全チャネルの受信設定（未設定の組み合わせは既定値）
+type NotificationPreferencesResponse struct {
+	Types       []NotificationTypeDefinition `json:"types"`
+	Channels    []NotificationChannel        `json:"channels"`
+	Preferences []NotificationPreferenceItem `json:"preferences"`
+	QuietHours  NotificationQuietHours       `json:"quiet_hours"`
+	Chat        NotificationChatStatus       `json:"chat"`
+}
+
 // ===== プロジェクト・工数管理 =====
 
 type ProjectCreateRequest struct {
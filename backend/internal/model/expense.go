@@ -142,22 +142,6 @@ type ExpenseBudget struct {
 	SpentAmount  float64         `gorm:"not null;default:0" json:"spent_amount"`
 }
 
-// ===== 経費通知 =====
-
-// ExpenseNotification は経費通知モデル
-type ExpenseNotification struct {
-	BaseModel
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
-	ExpenseID *uuid.UUID `gorm:"type:uuid" json:"expense_id"`
-	Type      string     `gorm:"size:50;not null" json:"type"`
-	Message   string     `gorm:"size:1000;not null" json:"message"`
-	IsRead    bool       `gorm:"default:false" json:"is_read"`
-	ReadAt    *time.Time `json:"read_at"`
-
-	User    *User    `gorm:"foreignKey:UserID" json:"user,omitempty"`
-	Expense *Expense `gorm:"foreignKey:ExpenseID" json:"expense,omitempty"`
-}
-
 // ===== 経費リマインダー =====
 
 // ExpenseReminder は経費リマインダーモデル
@@ -43,7 +43,7 @@ type HREmployee struct {
 	HireDate       *time.Time     `gorm:"type:date" json:"hire_date"`
 	BirthDate      *time.Time     `gorm:"type:date" json:"birth_date"`
 	Address        string         `gorm:"size:500" json:"address"`
-	BaseSalary     float64        `gorm:"default:0" json:"base_salary"`
+	BaseSalary     float64        `gorm:"default:0" json:"base_salary,omitempty"`
 
 	Department *HRDepartment `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
 	Manager    *HREmployee   `gorm:"foreignKey:ManagerID" json:"manager,omitempty"`
@@ -10,11 +10,32 @@ func AutoMigrate(db *gorm.DB) error {
 		&Attendance{},
 		&LeaveRequest{},
 		&Shift{},
+		&ShiftSchedule{},
+		&ShiftScheduleVersion{},
 		&RefreshToken{},
+		&UserToken{},
+		&UserIdentity{},
+		&LoginHistory{},
+		&LoginLockout{},
+		&ServiceAccount{},
+		&APIToken{},
+		&UserMFA{},
+		&MFARecoveryCode{},
+		&MFAPolicy{},
+		&CustomRole{},
+		&UserRoleAssignment{},
+		&AuditLog{},
 		&OvertimeRequest{},
 		&LeaveBalance{},
 		&AttendanceCorrection{},
+		&SubstituteHolidayRequest{},
+		&CompensatoryDay{},
+		&WorkingHourPolicy{},
+		&WorkingHourScheduleDay{},
+		&RemoteWorkPolicy{},
 		&Notification{},
+		&NotificationPreference{},
+		&NotificationSetting{},
 		&Project{},
 		&TimeEntry{},
 		&Holiday{},
@@ -68,7 +89,6 @@ func ExpenseAutoMigrate(db *gorm.DB) error {
 		&ExpenseTemplate{},
 		&ExpensePolicy{},
 		&ExpenseBudget{},
-		&ExpenseNotification{},
 		&ExpenseReminder{},
 		&ExpenseNotificationSetting{},
 		&ExpenseApprovalFlow{},
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
 )
 
 // ===== シフト =====
@@ -22,16 +23,53 @@ const (
 // Shift はシフトモデル
 type Shift struct {
 	BaseModel
-	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
-	Date      time.Time  `gorm:"type:date;not null;index" json:"date" validate:"required"`
-	ShiftType ShiftType  `gorm:"size:20;not null" json:"shift_type" validate:"required"`
-	StartTime *time.Time `gorm:"type:time" json:"start_time"`
-	EndTime   *time.Time `gorm:"type:time" json:"end_time"`
-	Note      string     `gorm:"size:500" json:"note"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Date       time.Time  `gorm:"type:date;not null;index" json:"date" validate:"required"`
+	ShiftType  ShiftType  `gorm:"size:20;not null" json:"shift_type" validate:"required"`
+	StartTime  *time.Time `gorm:"type:time" json:"start_time"`
+	EndTime    *time.Time `gorm:"type:time" json:"end_time"`
+	Note       string     `gorm:"size:500" json:"note"`
+	ScheduleID *uuid.UUID `gorm:"type:uuid;index" json:"schedule_id"`
 
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+// ===== シフト表（公開・版管理） =====
+
+// ShiftScheduleStatus はシフト表の公開状態
+type ShiftScheduleStatus string
+
+const (
+	ShiftScheduleStatusDraft     ShiftScheduleStatus = "draft"     // 下書き（従業員には非公開）
+	ShiftScheduleStatusPublished ShiftScheduleStatus = "published" // 公開済み
+)
+
+// ShiftSchedule は部署・期間単位のシフト表モデル
+type ShiftSchedule struct {
+	BaseModel
+	DepartmentID uuid.UUID           `gorm:"type:uuid;not null;index" json:"department_id"`
+	PeriodStart  time.Time           `gorm:"type:date;not null" json:"period_start"`
+	PeriodEnd    time.Time           `gorm:"type:date;not null" json:"period_end"`
+	Status       ShiftScheduleStatus `gorm:"size:20;not null;default:'draft'" json:"status"`
+	Version      int                 `gorm:"not null;default:0" json:"version"`
+	PublishedAt  *time.Time          `json:"published_at"`
+	PublishedBy  *uuid.UUID          `gorm:"type:uuid" json:"published_by"`
+
+	Department *Department `gorm:"foreignKey:DepartmentID" json:"department,omitempty"`
+}
+
+// ShiftScheduleVersion は公開時点のシフト表スナップショット
+type ShiftScheduleVersion struct {
+	BaseModel
+	ScheduleID  uuid.UUID      `gorm:"type:uuid;not null;index" json:"schedule_id"`
+	Version     int            `gorm:"not null" json:"version"`
+	Shifts      datatypes.JSON `gorm:"type:jsonb" json:"shifts"`
+	ChangeNote  string         `gorm:"size:500" json:"change_note"`
+	PublishedBy *uuid.UUID     `gorm:"type:uuid" json:"published_by"`
+
+	Schedule *ShiftSchedule `gorm:"foreignKey:ScheduleID" json:"schedule,omitempty"`
+}
+
 // ===== 通知 =====
 
 // NotificationType は通知種別
@@ -42,13 +80,101 @@ const (
 	NotificationTypeLeaveRejected    NotificationType = "leave_rejected"
 	NotificationTypeLeaveRequested   NotificationType = "leave_requested"
 	NotificationTypeOvertimeAlert    NotificationType = "overtime_alert"
+	NotificationTypeOvertimeReq      NotificationType = "overtime_requested"
 	NotificationTypeCorrectionResult NotificationType = "correction_result"
 	NotificationTypeCorrectionReq    NotificationType = "correction_requested"
 	NotificationTypeShiftChanged     NotificationType = "shift_changed"
 	NotificationTypeClockReminder    NotificationType = "clock_reminder"
 	NotificationTypeGeneral          NotificationType = "general"
+	NotificationTypeNewDeviceLogin   NotificationType = "new_device_login"
+
+	// 経費
+	NotificationTypeExpenseSubmitted  NotificationType = "expense_submitted"
+	NotificationTypeExpenseApproved   NotificationType = "expense_approved"
+	NotificationTypeExpenseRejected   NotificationType = "expense_rejected"
+	NotificationTypeExpenseComment    NotificationType = "expense_comment"
+	NotificationTypeExpenseReimbursed NotificationType = "expense_reimbursed"
+	NotificationTypeExpenseReminder   NotificationType = "expense_reminder"
+
+	// 人事
+	NotificationTypeEvaluationSubmitted NotificationType = "evaluation_submitted"
+	NotificationTypeOneOnOneScheduled   NotificationType = "one_on_one_scheduled"
+	NotificationTypeTrainingEnrolled    NotificationType = "training_enrolled"
+)
+
+// NotificationTypeDefinition は通知種別の説明。Mandatory の種別は受信設定で止められない
+type NotificationTypeDefinition struct {
+	Type        NotificationType `json:"type"`
+	Module      string           `json:"module"`
+	Description string           `json:"description"`
+	Mandatory   bool             `json:"mandatory"`
+}
+
+// NotificationTypeDefinitions は受信設定の対象となる通知種別
+var NotificationTypeDefinitions = []NotificationTypeDefinition{
+	{NotificationTypeLeaveRequested, "attendance", "休暇申請の承認依頼", false},
+	{NotificationTypeLeaveApproved, "attendance", "休暇申請の承認", false},
+	{NotificationTypeLeaveRejected, "attendance", "休暇申請の却下", false},
+	{NotificationTypeOvertimeReq, "attendance", "残業申請の承認依頼", false},
+	{NotificationTypeOvertimeAlert, "attendance", "残業申請の結果・残業時間の警告", false},
+	{NotificationTypeCorrectionReq, "attendance", "打刻修正の承認依頼", false},
+	{NotificationTypeCorrectionResult, "attendance", "打刻修正の結果", false},
+	{NotificationTypeShiftChanged, "attendance", "シフトの変更・公開", false},
+	{NotificationTypeClockReminder, "attendance", "打刻のリマインド", false},
+	{NotificationTypeExpenseSubmitted, "expense", "経費申請の提出", false},
+	{NotificationTypeExpenseApproved, "expense", "経費申請の承認", false},
+	{NotificationTypeExpenseRejected, "expense", "経費申請の却下", false},
+	{NotificationTypeExpenseComment, "expense", "経費申請へのコメント", false},
+	{NotificationTypeExpenseReimbursed, "expense", "経費の精算完了", false},
+	{NotificationTypeExpenseReminder, "expense", "経費申請のリマインド", false},
+	{NotificationTypeEvaluationSubmitted, "hr", "人事評価の提出", false},
+	{NotificationTypeOneOnOneScheduled, "hr", "1on1の予定", false},
+	{NotificationTypeTrainingEnrolled, "hr", "研修への登録", false},
+	{NotificationTypeGeneral, "shared", "その他のお知らせ", false},
+	{NotificationTypeNewDeviceLogin, "shared", "新しい端末からのログイン（セキュリティ）", true},
+}
+
+// NotificationTypesForModule はモジュールの通知種別を返す
+func NotificationTypesForModule(module string) []NotificationType {
+	var types []NotificationType
+	for _, d := range NotificationTypeDefinitions {
+		if d.Module == module {
+			types = append(types, d.Type)
+		}
+	}
+	return types
+}
+
+// FindNotificationType は通知種別の説明を返す
+func FindNotificationType(t NotificationType) (NotificationTypeDefinition, bool) {
+	for _, d := range NotificationTypeDefinitions {
+		if d.Type == t {
+			return d, true
+		}
+	}
+	return NotificationTypeDefinition{}, false
+}
+
+// NotificationChannel は通知の配信チャネル
+type NotificationChannel string
+
+const (
+	NotificationChannelInApp NotificationChannel = "in_app"
+	NotificationChannelEmail NotificationChannel = "email"
+	NotificationChannelPush  NotificationChannel = "push"
+	NotificationChannelChat  NotificationChannel = "chat"
 )
 
+// NotificationChannels は受信設定の対象となるチャネル
+var NotificationChannels = []NotificationChannel{
+	NotificationChannelInApp, NotificationChannelEmail, NotificationChannelPush, NotificationChannelChat,
+}
+
+// DefaultNotificationPreference は受信設定がない場合の既定値。チャットは利用者が有効にした場合のみ配信する
+func DefaultNotificationPreference(ch NotificationChannel) bool {
+	return ch != NotificationChannelChat
+}
+
 // Notification は通知モデル
 type Notification struct {
 	BaseModel
@@ -59,10 +185,63 @@ type Notification struct {
 	IsRead  bool             `gorm:"default:false" json:"is_read"`
 	ReadAt  *time.Time       `json:"read_at"`
 	LinkURL string           `gorm:"size:500" json:"link_url"`
+	// ExpenseID は経費の通知の対象となる経費申請。経費の通知一覧はこれが設定された通知を表示する
+	ExpenseID *uuid.UUID `gorm:"type:uuid;index" json:"expense_id,omitempty"`
+	// Approval は承認依頼の対象となる申請。チャットへの配信で承認・却下ボタンを付けるために使い、保存しない
+	Approval *ApprovalTarget `gorm:"-" json:"-"`
 
 	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
 }
 
+// ApprovalKind はチャットから承認・却下できる申請の種類
+type ApprovalKind string
+
+const (
+	ApprovalKindLeave    ApprovalKind = "leave"
+	ApprovalKindOvertime ApprovalKind = "overtime"
+	ApprovalKindExpense  ApprovalKind = "expense"
+)
+
+// ApprovalTarget は承認依頼の対象となる申請
+type ApprovalTarget struct {
+	Kind ApprovalKind
+	ID   uuid.UUID
+}
+
+// NotificationPreference は通知種別・チャネルごとの受信設定。行がない組み合わせは既定値に従う
+type NotificationPreference struct {
+	BaseModel
+	UserID  uuid.UUID           `gorm:"type:uuid;not null;uniqueIndex:idx_notification_preferences_key" json:"user_id"`
+	Type    NotificationType    `gorm:"size:30;not null;uniqueIndex:idx_notification_preferences_key" json:"type"`
+	Channel NotificationChannel `gorm:"size:20;not null;uniqueIndex:idx_notification_preferences_key" json:"channel"`
+	Enabled bool                `gorm:"not null" json:"enabled"`
+}
+
+// ChatProvider はチャット通知の配信先サービス
+type ChatProvider string
+
+const (
+	ChatProviderSlack ChatProvider = "slack"
+	ChatProviderTeams ChatProvider = "teams"
+)
+
+// NotificationSetting はユーザーごとの通知設定。おやすみ時間帯はアプリ内通知以外のチャネルへの配信を終了まで遅らせる
+type NotificationSetting struct {
+	BaseModel
+	UserID            uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"user_id"`
+	QuietHoursEnabled bool      `gorm:"not null;default:false" json:"quiet_hours_enabled"`
+	// QuietHoursStart・QuietHoursEnd は HH:MM。開始が終了より遅い場合は日をまたぐ
+	QuietHoursStart string `gorm:"size:5;not null;default:'22:00'" json:"quiet_hours_start"`
+	QuietHoursEnd   string `gorm:"size:5;not null;default:'07:00'" json:"quiet_hours_end"`
+	TimeZone        string `gorm:"size:50;not null;default:'Asia/Tokyo'" json:"time_zone"`
+	// ChatProvider・ChatWebhookURL はチャット通知の配信先（Incoming Webhook）。URL は秘密情報のため応答に含めない
+	ChatProvider   ChatProvider `gorm:"size:20;not null;default:''" json:"chat_provider"`
+	ChatWebhookURL string       `gorm:"size:500;not null;default:''" json:"-"`
+	// ChatUserID は本人のチャットのアカウント（Slack のメンバー ID / Teams のユーザーのオブジェクト ID）。
+	// 承認・却下ボタンはこのアカウントからの操作のみ受け付ける
+	ChatUserID string `gorm:"size:255;not null;default:''" json:"chat_user_id"`
+}
+
 // ===== プロジェクト・工数管理 =====
 
 // ProjectStatus はプロジェクトステータス
@@ -0,0 +1,197 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/oidc"
+)
+
+// ActionDecision はチャットのボタンで選んだ処理
+type ActionDecision string
+
+const (
+	DecisionApprove ActionDecision = "approve"
+	DecisionReject  ActionDecision = "reject"
+)
+
+// ActionClaims はボタンに埋め込むトークンの内容。Approver は通知を受け取った承認者。
+// TokenID は一度だけ使えるよう使用済みを記録するための ID
+type ActionClaims struct {
+	Kind      model.ApprovalKind `json:"kind"`
+	ID        uuid.UUID          `json:"id"`
+	Decision  ActionDecision     `json:"decision"`
+	Approver  uuid.UUID          `json:"approver"`
+	TokenID   string             `json:"jti"`
+	ExpiresAt int64              `json:"exp"`
+}
+
+// ErrInvalidActionToken は改ざん・期限切れなどで検証できないトークン
+var ErrInvalidActionToken = errors.New("invalid or expired chat action token")
+
+// ErrInvalidSlackSignature は Slack からのリクエストとして検証できない署名
+var ErrInvalidSlackSignature = errors.New("invalid slack request signature")
+
+// ErrInvalidTeamsToken は Teams からのリクエストとして検証できない Authorization ヘッダーのトークン
+var ErrInvalidTeamsToken = errors.New("invalid teams action token")
+
+const (
+	// TeamsActionIssuer は Teams（Actionable Message）がボタンの送信時に付けるトークンの発行者
+	TeamsActionIssuer = "https://substrate.office.com/sts/"
+	// TeamsActionKeysURL はそのトークンの署名を検証する公開鍵（JWKS）
+	TeamsActionKeysURL = "https://substrate.office.com/sts/common/discovery/keys"
+)
+
+// slackSignatureMaxAge はリプレイ攻撃を防ぐため、Slack のリクエストの時刻として許容するずれ
+const slackSignatureMaxAge = 5 * time.Minute
+
+// ActionSigner はチャットの承認・却下ボタンのトークンを HMAC-SHA256 で署名・検証する。
+// トークンは通知を受け取った承認者の権限で処理されるため、コールバックではボタンを押した人の
+// チャットのアカウントが承認者の登録したものと一致することも確認する
+type ActionSigner struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+// NewActionSigner は署名器を作成する。secret が空の場合は nil を返す（ボタンを付けない）
+func NewActionSigner(secret string, ttl time.Duration) *ActionSigner {
+	if secret == "" {
+		return nil
+	}
+	if ttl <= 0 {
+		ttl = 72 * time.Hour
+	}
+	return &ActionSigner{secret: []byte(secret), ttl: ttl, now: time.Now}
+}
+
+// Sign は ID と有効期限を設定してトークンを作成する
+func (s *ActionSigner) Sign(claims ActionClaims) (string, error) {
+	claims.TokenID = uuid.NewString()
+	claims.ExpiresAt = s.now().Add(s.ttl).Unix()
+	raw, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	payload := base64.RawURLEncoding.EncodeToString(raw)
+	return payload + "." + base64.RawURLEncoding.EncodeToString(s.mac(payload)), nil
+}
+
+// Verify はトークンの署名と有効期限を検証して内容を返す
+func (s *ActionSigner) Verify(token string) (*ActionClaims, error) {
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, ErrInvalidActionToken
+	}
+	got, err := base64.RawURLEncoding.DecodeString(sig)
+	if err != nil || !hmac.Equal(got, s.mac(payload)) {
+		return nil, ErrInvalidActionToken
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, ErrInvalidActionToken
+	}
+	var claims ActionClaims
+	if err := json.Unmarshal(raw, &claims); err != nil {
+		return nil, ErrInvalidActionToken
+	}
+	if s.now().Unix() >= claims.ExpiresAt {
+		return nil, ErrInvalidActionToken
+	}
+	if claims.Decision != DecisionApprove && claims.Decision != DecisionReject {
+		return nil, ErrInvalidActionToken
+	}
+	return &claims, nil
+}
+
+func (s *ActionSigner) mac(payload string) []byte {
+	h := hmac.New(sha256.New, s.secret)
+	h.Write([]byte("chat-action." + payload))
+	return h.Sum(nil)
+}
+
+// VerifySlackSignature は Slack の署名（X-Slack-Signature: "v0=" + HMAC-SHA256("v0:{timestamp}:{body}")）を検証する。
+// X-Slack-Request-Timestamp が now から5分以上ずれたリクエストは再送として拒否する
+func VerifySlackSignature(secret, timestamp, signature string, body []byte, now time.Time) error {
+	if secret == "" {
+		return ErrInvalidSlackSignature
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrInvalidSlackSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > slackSignatureMaxAge || d < -slackSignatureMaxAge {
+		return ErrInvalidSlackSignature
+	}
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, "v0="))
+	if err != nil || !strings.HasPrefix(signature, "v0=") {
+		return ErrInvalidSlackSignature
+	}
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("v0:" + timestamp + ":"))
+	h.Write(body)
+	if !hmac.Equal(got, h.Sum(nil)) {
+		return ErrInvalidSlackSignature
+	}
+	return nil
+}
+
+// TeamsActionVerifier は Teams がボタンの送信時に Authorization ヘッダーに付ける JWT を検証する。
+// 対象者（aud）はボタンの送信先（TEAMS_ACTION_URL）の origin
+type TeamsActionVerifier struct {
+	keys     *oidc.KeySet
+	issuer   string
+	audience string
+	now      func() time.Time
+}
+
+// NewTeamsActionVerifier は actionURL 宛てのトークンの検証器を作成する。
+// 本番では issuer に TeamsActionIssuer、keys に TeamsActionKeysURL の KeySet を渡す。actionURL が不正な場合は nil を返す
+func NewTeamsActionVerifier(actionURL, issuer string, keys *oidc.KeySet) *TeamsActionVerifier {
+	u, err := url.Parse(actionURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return nil
+	}
+	return &TeamsActionVerifier{keys: keys, issuer: issuer, audience: u.Scheme + "://" + u.Host, now: time.Now}
+}
+
+// Verify は "Bearer {JWT}" の署名・発行者・対象者・有効期限を検証し、ボタンを押した人（sub）を返す
+func (v *TeamsActionVerifier) Verify(ctx context.Context, authorization string) (string, error) {
+	raw, ok := strings.CutPrefix(authorization, "Bearer ")
+	if !ok || raw == "" {
+		return "", ErrInvalidTeamsToken
+	}
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256"}),
+		jwt.WithIssuer(v.issuer),
+		jwt.WithAudience(v.audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(v.now),
+	)
+	mc := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(raw, mc, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return v.keys.Key(ctx, kid)
+	})
+	if err != nil {
+		return "", ErrInvalidTeamsToken
+	}
+	sub, _ := mc["sub"].(string)
+	if sub == "" {
+		return "", ErrInvalidTeamsToken
+	}
+	return sub, nil
+}
@@ -0,0 +1,261 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// ChatConfig はチャットチャネルの設定
+type ChatConfig struct {
+	// AllowedHosts は投稿を許可する Webhook のホスト（先頭が "." の場合はそのサブドメイン）
+	AllowedHosts []string
+	// Signer は承認・却下ボタンのトークンの署名器（nil の場合はボタンを付けない）
+	Signer *ActionSigner
+	// TeamsActionURL は Teams のボタンの送信先（/api/v1/integrations/teams/actions の公開URL）
+	TeamsActionURL string
+	// BaseURL は通知のリンク（LinkURL が相対パスの場合）の前に付ける
+	BaseURL string
+	// Locale はボタンの表記の言語（ja / en）
+	Locale string
+	// Client は投稿に使う HTTP クライアント（nil の場合は既定のクライアント）
+	Client *http.Client
+}
+
+// ChatChannel は通知を Slack / Microsoft Teams の Incoming Webhook に投稿する。
+// 承認依頼（Message.Approval）には、署名付きのトークンを埋め込んだ承認・却下ボタンを付ける
+type ChatChannel struct {
+	cfg    ChatConfig
+	client *http.Client
+}
+
+// NewChatChannel はチャットチャネルを作成する
+func NewChatChannel(cfg ChatConfig) *ChatChannel {
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	client := cfg.Client
+	if client == nil {
+		client = NewWebhookClient()
+	}
+	return &ChatChannel{cfg: cfg, client: client}
+}
+
+// NewWebhookClient は Webhook への投稿に使う HTTP クライアントを作成する。
+// 許可したホストから別のホストへ転送されないよう、リダイレクトには従わない
+func NewWebhookClient() *http.Client {
+	return &http.Client{
+		Timeout: 10 * time.Second,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func (c *ChatChannel) Name() model.NotificationChannel { return model.NotificationChannelChat }
+
+func (c *ChatChannel) Deliver(ctx context.Context, msg Message) error {
+	webhook := msg.Recipient.ChatWebhookURL
+	if webhook == "" {
+		return fmt.Errorf("%w: recipient has no chat webhook", ErrPermanent)
+	}
+	if !WebhookAllowed(webhook, c.cfg.AllowedHosts) {
+		return fmt.Errorf("%w: chat webhook host is not allowed", ErrPermanent)
+	}
+	labels := chatLabelsFor(msg.Recipient.Locale, c.cfg.Locale)
+	link := absoluteLink(c.cfg.BaseURL, msg.LinkURL)
+	approve, reject, err := c.actionTokens(msg)
+	if err != nil {
+		return fmt.Errorf("%w: sign chat action: %v", ErrPermanent, err)
+	}
+
+	var payload any
+	switch msg.Recipient.ChatProvider {
+	case model.ChatProviderSlack:
+		payload = slackPayload(msg, link, approve, reject, labels)
+	case model.ChatProviderTeams:
+		payload = teamsPayload(msg, link, approve, reject, c.cfg.TeamsActionURL, labels)
+	default:
+		return fmt.Errorf("%w: unknown chat provider %q", ErrPermanent, msg.Recipient.ChatProvider)
+	}
+	return PostJSON(ctx, c.client, webhook, payload)
+}
+
+// actionTokens は承認依頼の承認・却下ボタンのトークンを作成する。承認依頼でない場合は空を返す。
+// ボタンを押した人を承認者本人と照合できないため、チャットのアカウントを登録していない宛先にもボタンを付けない
+func (c *ChatChannel) actionTokens(msg Message) (approve, reject string, err error) {
+	if msg.Approval == nil || c.cfg.Signer == nil || msg.Recipient.ChatUserID == "" {
+		return "", "", nil
+	}
+	claims := ActionClaims{Kind: msg.Approval.Kind, ID: msg.Approval.ID, Approver: msg.Recipient.UserID}
+	claims.Decision = DecisionApprove
+	if approve, err = c.cfg.Signer.Sign(claims); err != nil {
+		return "", "", err
+	}
+	claims.Decision = DecisionReject
+	if reject, err = c.cfg.Signer.Sign(claims); err != nil {
+		return "", "", err
+	}
+	return approve, reject, nil
+}
+
+// PostJSON は Webhook に JSON を投稿する。429 と 5xx 以外のエラー応答は再試行しても成功しないため ErrPermanent を包む
+func PostJSON(ctx context.Context, client *http.Client, webhook string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("%w: encode chat payload: %v", ErrPermanent, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: build chat request: %v", ErrPermanent, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("chat webhook responded %d: %s", resp.StatusCode, detail)
+	default:
+		return fmt.Errorf("%w: chat webhook responded %d: %s", ErrPermanent, resp.StatusCode, detail)
+	}
+}
+
+// WebhookAllowed は Webhook の URL が許可したホストへの https であるかを返す。
+// ローカルの検証用サーバー（localhost / 127.0.0.1）に限り http も許可する
+func WebhookAllowed(rawURL string, allowedHosts []string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.User != nil || u.Host == "" {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	switch u.Scheme {
+	case "https":
+	case "http":
+		if host != "localhost" && host != "127.0.0.1" {
+			return false
+		}
+	default:
+		return false
+	}
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "" {
+			continue
+		}
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// ===== ペイロード =====
+
+// chatLabels はボタンなどの表記
+type chatLabels struct {
+	Approve string
+	Reject  string
+	Open    string
+}
+
+var chatLabelSet = map[string]chatLabels{
+	LocaleJa: {Approve: "承認", Reject: "却下", Open: "詳細を開く"},
+	LocaleEn: {Approve: "Approve", Reject: "Reject", Open: "Open"},
+}
+
+func chatLabelsFor(locales ...string) chatLabels {
+	for _, l := range locales {
+		if labels, ok := chatLabelSet[l]; ok {
+			return labels
+		}
+	}
+	return chatLabelSet[LocaleJa]
+}
+
+// slackPayload は Block Kit のメッセージを作成する。ボタンの value にトークンを入れ、
+// 押下は Slack アプリの Interactivity の Request URL（/api/v1/integrations/slack/actions）に届く
+func slackPayload(msg Message, link, approve, reject string, labels chatLabels) map[string]any {
+	blocks := []any{
+		map[string]any{
+			"type": "section",
+			"text": map[string]any{"type": "mrkdwn", "text": "*" + slackEscape(msg.Title) + "*\n" + slackEscape(msg.Body)},
+		},
+	}
+	var elements []any
+	if approve != "" {
+		elements = append(elements,
+			slackButton(labels.Approve, "approve", "primary", approve),
+			slackButton(labels.Reject, "reject", "danger", reject))
+	}
+	if link != "" {
+		elements = append(elements, map[string]any{
+			"type": "button", "action_id": "open",
+			"text": map[string]any{"type": "plain_text", "text": labels.Open},
+			"url":  link,
+		})
+	}
+	if len(elements) > 0 {
+		blocks = append(blocks, map[string]any{"type": "actions", "block_id": "kintai_approval", "elements": elements})
+	}
+	return map[string]any{"text": msg.Title, "blocks": blocks}
+}
+
+func slackButton(text, actionID, style, value string) map[string]any {
+	return map[string]any{
+		"type": "button", "action_id": actionID, "style": style, "value": value,
+		"text": map[string]any{"type": "plain_text", "text": text},
+	}
+}
+
+// slackEscape は mrkdwn の制御文字をエスケープする
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// teamsPayload は Office 365 コネクタの MessageCard を作成する。
+// ボタンは HttpPOST アクションで、本文 {"token": ...} を actionURL に送信する
+func teamsPayload(msg Message, link, approve, reject, actionURL string, labels chatLabels) map[string]any {
+	var actions []any
+	if approve != "" && actionURL != "" {
+		actions = append(actions, teamsPost(labels.Approve, actionURL, approve), teamsPost(labels.Reject, actionURL, reject))
+	}
+	if link != "" {
+		actions = append(actions, map[string]any{
+			"@type": "OpenUri", "name": labels.Open,
+			"targets": []any{map[string]any{"os": "default", "uri": link}},
+		})
+	}
+	card := map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    msg.Title,
+		"themeColor": "0076D7",
+		"title":      msg.Title,
+		// Teams の Markdown では空行で改行する
+		"text": strings.ReplaceAll(msg.Body, "\n", "\n\n"),
+	}
+	if len(actions) > 0 {
+		card["potentialAction"] = actions
+	}
+	return card
+}
+
+func teamsPost(name, target, token string) map[string]any {
+	body, _ := json.Marshal(map[string]string{"token": token})
+	return map[string]any{
+		"@type": "HttpPOST", "name": name, "target": target,
+		"body": string(body), "bodyContentType": "application/json",
+	}
+}
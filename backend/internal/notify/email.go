@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/your-org/kintai/backend/internal/mailer"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// EmailChannel は通知をメールで配信する。送信は mailer（SES / SMTP / メモリ）に任せる
+type EmailChannel struct {
+	mailer    mailer.Mailer
+	templates *Templates
+	// baseURL は通知のリンク（LinkURL が相対パスの場合）の前に付ける
+	baseURL string
+}
+
+// NewEmailChannel はメールチャネルを作成する
+func NewEmailChannel(m mailer.Mailer, templates *Templates, baseURL string) *EmailChannel {
+	return &EmailChannel{mailer: m, templates: templates, baseURL: strings.TrimRight(baseURL, "/")}
+}
+
+func (c *EmailChannel) Name() model.NotificationChannel { return model.NotificationChannelEmail }
+
+func (c *EmailChannel) Deliver(ctx context.Context, msg Message) error {
+	if msg.Recipient.Email == "" {
+		return fmt.Errorf("%w: recipient has no email address", ErrPermanent)
+	}
+	subject, body, err := c.templates.Render(msg.Recipient.Locale, msg.Type, TemplateData{
+		Name:  msg.Recipient.Name,
+		Title: msg.Title,
+		Body:  msg.Body,
+		URL:   c.linkURL(msg.LinkURL),
+	})
+	if err != nil {
+		return fmt.Errorf("%w: render template: %v", ErrPermanent, err)
+	}
+	return c.mailer.Send(ctx, mailer.Message{To: msg.Recipient.Email, Subject: subject, Body: body})
+}
+
+func (c *EmailChannel) linkURL(link string) string {
+	return absoluteLink(c.baseURL, link)
+}
+
+// absoluteLink は通知のリンクを絶対URLにする。リンクがない場合は通知一覧を示す
+func absoluteLink(baseURL, link string) string {
+	if strings.HasPrefix(link, "http://") || strings.HasPrefix(link, "https://") {
+		return link
+	}
+	if baseURL == "" {
+		return ""
+	}
+	if link == "" {
+		link = "/notifications"
+	}
+	if !strings.HasPrefix(link, "/") {
+		link = "/" + link
+	}
+	return baseURL + link
+}
@@ -0,0 +1,280 @@
+// Package notify はアプリ内通知以外の配信チャネル（メール・チャット）と、非同期の配信キューを提供する
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/mailer"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+// Recipient は通知の宛先
+type Recipient struct {
+	UserID uuid.UUID
+	Email  string
+	Name   string
+	// Locale はテンプレートの言語（ja / en、空の場合は既定の言語）
+	Locale string
+	// ChatProvider・ChatWebhookURL はチャット通知の配信先（未登録の場合は空）
+	ChatProvider   model.ChatProvider
+	ChatWebhookURL string
+	// ChatUserID は宛先のチャットのアカウント（Slack のメンバー ID / Teams のユーザーのオブジェクト ID）
+	ChatUserID string
+}
+
+// Message は配信する通知。Title と Body はアプリ内通知と同じ文面
+type Message struct {
+	Recipient Recipient
+	Type      model.NotificationType
+	Title     string
+	Body      string
+	LinkURL   string
+	// Approval は承認依頼の対象。チャットでは承認・却下ボタンを付ける
+	Approval *model.ApprovalTarget
+}
+
+// Channel は通知の配信チャネル。Name は受信設定のチャネルと対応する
+type Channel interface {
+	Name() model.NotificationChannel
+	Deliver(ctx context.Context, msg Message) error
+}
+
+// ErrPermanent を包んだエラーは再試行しても成功しないため、キューは再試行しない
+var ErrPermanent = errors.New("permanent delivery error")
+
+// ErrQueueFull はキューが満杯で配信を受け付けられない場合に返す
+var ErrQueueFull = errors.New("notification queue is full")
+
+// ErrQueueClosed は停止したキューに配信を追加した場合に返す
+var ErrQueueClosed = errors.New("notification queue is closed")
+
+// QueueOptions は配信キューの設定
+type QueueOptions struct {
+	// Workers は並行して配信するワーカー数
+	Workers int
+	// Size はキューに溜められる配信の上限
+	Size int
+	// MaxAttempts は1件あたりの配信の試行回数（初回を含む）
+	MaxAttempts int
+	// Backoff は初回の再試行までの待ち時間。再試行のたびに倍増する
+	Backoff time.Duration
+}
+
+func (o QueueOptions) withDefaults() QueueOptions {
+	if o.Workers <= 0 {
+		o.Workers = 2
+	}
+	if o.Size <= 0 {
+		o.Size = 1000
+	}
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 3
+	}
+	if o.Backoff <= 0 {
+		o.Backoff = time.Second
+	}
+	return o
+}
+
+type job struct {
+	channel Channel
+	msg     Message
+}
+
+// Queue は通知をチャネルごとに非同期で配信し、失敗した配信を指数バックオフで再試行する。
+// キューはプロセス内に保持するため、停止時に未配信のものは Close で配信し切ってから終了する
+type Queue struct {
+	channels []Channel
+	opts     QueueOptions
+	log      *logger.Logger
+	jobs     chan job
+	wg       sync.WaitGroup
+	stop     chan struct{}
+	mu       sync.RWMutex
+	closed   bool
+	// scheduled は EnqueueAfter で予約した配信（時刻が来るとキューに追加する）
+	scheduled map[*time.Timer]Message
+}
+
+// NewQueue は配信キューを作成し、ワーカーを起動する
+func NewQueue(channels []Channel, opts QueueOptions, log *logger.Logger) *Queue {
+	opts = opts.withDefaults()
+	q := &Queue{
+		channels: channels,
+		opts:     opts,
+		log:      log,
+		jobs:      make(chan job, opts.Size),
+		stop:      make(chan struct{}),
+		scheduled: make(map[*time.Timer]Message),
+	}
+	for i := 0; i < opts.Workers; i++ {
+		q.wg.Add(1)
+		go q.work()
+	}
+	return q
+}
+
+// Channels は登録済みのチャネル名を返す
+func (q *Queue) Channels() []model.NotificationChannel {
+	names := make([]model.NotificationChannel, 0, len(q.channels))
+	for _, ch := range q.channels {
+		names = append(names, ch.Name())
+	}
+	return names
+}
+
+// Has はチャネルが登録されているかを返す。nil のキューはどのチャネルも持たない
+func (q *Queue) Has(name model.NotificationChannel) bool {
+	if q == nil {
+		return false
+	}
+	return containsChannel(q.Channels(), name)
+}
+
+// Enqueue は指定したチャネルへの配信をキューに追加する（登録されていないチャネルは無視する）。
+// 満杯のチャネルがあれば ErrQueueFull を返す
+func (q *Queue) Enqueue(msg Message, channels []model.NotificationChannel) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	var err error
+	for _, ch := range q.channels {
+		if !containsChannel(channels, ch.Name()) {
+			continue
+		}
+		select {
+		case q.jobs <- job{channel: ch, msg: msg}:
+		default:
+			q.log.Warn("通知キューが満杯のため配信を破棄しました", "channel", string(ch.Name()), "user_id", msg.Recipient.UserID.String(), "type", string(msg.Type))
+			err = ErrQueueFull
+		}
+	}
+	return err
+}
+
+// EnqueueAfter は delay の経過後に配信をキューに追加する（おやすみ時間帯の終了まで遅らせる場合など）。
+// 予約もプロセス内に保持するため、Close の時点で時刻が来ていない予約は配信せずに破棄する
+func (q *Queue) EnqueueAfter(msg Message, channels []model.NotificationChannel, delay time.Duration) error {
+	if delay <= 0 {
+		return q.Enqueue(msg, channels)
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrQueueClosed
+	}
+	var timer *time.Timer
+	timer = time.AfterFunc(delay, func() {
+		q.mu.Lock()
+		delete(q.scheduled, timer)
+		q.mu.Unlock()
+		if err := q.Enqueue(msg, channels); errors.Is(err, ErrQueueClosed) {
+			q.log.Warn("停止中のため予約した通知を破棄しました", "user_id", msg.Recipient.UserID.String(), "type", string(msg.Type))
+		}
+	})
+	q.scheduled[timer] = msg
+	return nil
+}
+
+// Close は新しい配信の受け付けを止め、キューに残った配信を終えてから戻る。
+// 停止後は再試行の間隔を待たずに残りの試行を行う
+func (q *Queue) Close() {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		for timer, msg := range q.scheduled {
+			if timer.Stop() {
+				q.log.Warn("停止中のため予約した通知を破棄しました", "user_id", msg.Recipient.UserID.String(), "type", string(msg.Type))
+			}
+		}
+		clear(q.scheduled)
+		close(q.stop)
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+	q.wg.Wait()
+}
+
+func (q *Queue) work() {
+	defer q.wg.Done()
+	for j := range q.jobs {
+		q.deliver(j)
+	}
+}
+
+func (q *Queue) deliver(j job) {
+	backoff := q.opts.Backoff
+	for attempt := 1; ; attempt++ {
+		// 停止時にワーカーがブロックされないよう、配信自体にも上限を設ける
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		err := j.channel.Deliver(ctx, j.msg)
+		cancel()
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ErrPermanent) || attempt >= q.opts.MaxAttempts {
+			q.log.Error("通知の配信に失敗しました", "channel", string(j.channel.Name()), "user_id", j.msg.Recipient.UserID.String(),
+				"type", string(j.msg.Type), "attempts", attempt, "error", err)
+			return
+		}
+		q.log.Warn("通知の配信に失敗したため再試行します", "channel", string(j.channel.Name()), "attempt", attempt, "error", err)
+		sleepOrStop(backoff, q.stop)
+		backoff *= 2
+	}
+}
+
+func containsChannel(channels []model.NotificationChannel, name model.NotificationChannel) bool {
+	for _, ch := range channels {
+		if ch == name {
+			return true
+		}
+	}
+	return false
+}
+
+func sleepOrStop(d time.Duration, stop <-chan struct{}) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-stop:
+	}
+}
+
+// New は設定で有効なチャネル（メール・チャット）を持つ配信キューを作成する。
+// どちらも無効な場合は nil を返す
+func New(cfg *config.Config, m mailer.Mailer, log *logger.Logger) (*Queue, error) {
+	var channels []Channel
+	if cfg.NotificationEmailEnabled && m != nil {
+		templates, err := NewTemplates(cfg.NotificationLocale)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, NewEmailChannel(m, templates, cfg.FrontendURL))
+	}
+	if cfg.NotificationChatEnabled {
+		channels = append(channels, NewChatChannel(ChatConfig{
+			AllowedHosts:   cfg.ChatWebhookAllowedHosts,
+			Signer:         NewActionSigner(cfg.ChatActionSecret, time.Duration(cfg.ChatActionTTLHours)*time.Hour),
+			TeamsActionURL: cfg.TeamsActionURL,
+			BaseURL:        cfg.FrontendURL,
+			Locale:         cfg.NotificationLocale,
+		}))
+	}
+	if len(channels) == 0 {
+		return nil, nil
+	}
+	return NewQueue(channels, QueueOptions{
+		Workers:     cfg.NotificationWorkers,
+		MaxAttempts: cfg.NotificationMaxAttempts,
+		Backoff:     time.Duration(cfg.NotificationRetryBackoffSeconds) * time.Second,
+	}, log), nil
+}
@@ -0,0 +1,234 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mailer"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+func testLogger() *logger.Logger {
+	log, _ := logger.NewLogger("debug", "test")
+	return log
+}
+
+// flakyChannel は最初の failures 回の配信に失敗する
+type flakyChannel struct {
+	mu        sync.Mutex
+	name      model.NotificationChannel
+	failures  int
+	err       error
+	attempts  int
+	delivered []Message
+}
+
+func (c *flakyChannel) Name() model.NotificationChannel { return c.name }
+
+func (c *flakyChannel) Deliver(_ context.Context, msg Message) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	if c.attempts <= c.failures {
+		return c.err
+	}
+	c.delivered = append(c.delivered, msg)
+	return nil
+}
+
+func TestTemplates_Render(t *testing.T) {
+	templates, err := NewTemplates(LocaleJa)
+	if err != nil {
+		t.Fatalf("NewTemplates failed: %v", err)
+	}
+	data := TemplateData{Name: "山田 太郎", Title: "2024/04/01 有給休暇", Body: "承認者: 鈴木", URL: "https://kintai.example.com/leaves"}
+
+	subject, body, err := templates.Render(LocaleJa, model.NotificationTypeLeaveApproved, data)
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if subject != "【勤怠管理】休暇申請が承認されました: 2024/04/01 有給休暇" {
+		t.Errorf("Unexpected subject: %s", subject)
+	}
+	for _, want := range []string{"山田 太郎 様", "休暇申請が承認されました。", "承認者: 鈴木", "https://kintai.example.com/leaves"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected body to contain %q, got:\n%s", want, body)
+		}
+	}
+
+	subject, body, _ = templates.Render(LocaleEn, model.NotificationTypeLeaveRejected, data)
+	if !strings.HasPrefix(subject, "[Kintai] Leave request rejected") || !strings.Contains(body, "Hello 山田 太郎,") {
+		t.Errorf("Unexpected English mail: %s\n%s", subject, body)
+	}
+
+	// 未対応の言語は既定の言語、未定義の種別は general を使う
+	subject, _, _ = templates.Render("fr", model.NotificationType("unknown"), TemplateData{Title: "お知らせ\n改行"})
+	if subject != "【勤怠管理】お知らせ 改行" {
+		t.Errorf("Expected fallback subject on one line, got %q", subject)
+	}
+}
+
+func TestEmailChannel_Deliver(t *testing.T) {
+	templates, _ := NewTemplates(LocaleJa)
+	m := mailer.NewMemoryMailer()
+	ch := NewEmailChannel(m, templates, "https://kintai.example.com/")
+
+	err := ch.Deliver(context.Background(), Message{
+		Recipient: Recipient{UserID: uuid.New(), Email: "taro@example.com", Name: "山田 太郎"},
+		Type:      model.NotificationTypeShiftChanged,
+		Title:     "シフトが公開されました",
+		Body:      "4月のシフトを確認してください",
+	})
+	if err != nil {
+		t.Fatalf("Deliver failed: %v", err)
+	}
+	sent, ok := m.Last("taro@example.com")
+	if !ok {
+		t.Fatal("Expected an email to be sent")
+	}
+	if !strings.Contains(sent.Body, "https://kintai.example.com/notifications") {
+		t.Errorf("Expected link to the notification list, got:\n%s", sent.Body)
+	}
+
+	err = ch.Deliver(context.Background(), Message{Recipient: Recipient{UserID: uuid.New()}, Type: model.NotificationTypeGeneral})
+	if !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected permanent error without an address, got %v", err)
+	}
+}
+
+func TestEmailChannel_LinkURL(t *testing.T) {
+	ch := NewEmailChannel(nil, nil, "https://kintai.example.com")
+	cases := map[string]string{
+		"":                        "https://kintai.example.com/notifications",
+		"/leaves/1":               "https://kintai.example.com/leaves/1",
+		"leaves/1":                "https://kintai.example.com/leaves/1",
+		"https://other.example/x": "https://other.example/x",
+	}
+	for link, want := range cases {
+		if got := ch.linkURL(link); got != want {
+			t.Errorf("linkURL(%q) = %q, want %q", link, got, want)
+		}
+	}
+}
+
+func TestQueue_RetriesUntilDelivered(t *testing.T) {
+	ch := &flakyChannel{name: model.NotificationChannelEmail, failures: 2, err: errors.New("temporary")}
+	q := NewQueue([]Channel{ch}, QueueOptions{Workers: 1, MaxAttempts: 3, Backoff: time.Millisecond}, testLogger())
+
+	if err := q.Enqueue(Message{Type: model.NotificationTypeGeneral, Title: "hello"}, []model.NotificationChannel{model.NotificationChannelEmail}); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	q.Close()
+
+	if ch.attempts != 3 || len(ch.delivered) != 1 {
+		t.Errorf("Expected delivery on the 3rd attempt, got attempts=%d delivered=%d", ch.attempts, len(ch.delivered))
+	}
+}
+
+func TestQueue_GivesUp(t *testing.T) {
+	transient := &flakyChannel{name: model.NotificationChannelEmail, failures: 10, err: errors.New("temporary")}
+	permanent := &flakyChannel{name: model.NotificationChannelChat, failures: 10, err: ErrPermanent}
+	q := NewQueue([]Channel{transient, permanent}, QueueOptions{Workers: 2, MaxAttempts: 3, Backoff: time.Millisecond}, testLogger())
+
+	_ = q.Enqueue(Message{Type: model.NotificationTypeGeneral}, q.Channels())
+	q.Close()
+
+	if transient.attempts != 3 {
+		t.Errorf("Expected 3 attempts for a temporary error, got %d", transient.attempts)
+	}
+	if permanent.attempts != 1 {
+		t.Errorf("Expected no retry for a permanent error, got %d attempts", permanent.attempts)
+	}
+	if err := q.Enqueue(Message{}, q.Channels()); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed after Close, got %v", err)
+	}
+}
+
+func TestQueue_Full(t *testing.T) {
+	block := make(chan struct{})
+	ch := &blockingChannel{release: block, started: make(chan struct{})}
+	q := NewQueue([]Channel{ch}, QueueOptions{Workers: 1, Size: 1}, testLogger())
+	defer q.Close()
+	defer close(block)
+
+	// 1件目はワーカーが処理中、2件目はキューに残り、3件目は溢れる
+	email := []model.NotificationChannel{model.NotificationChannelEmail}
+	_ = q.Enqueue(Message{}, email)
+	<-ch.started
+	_ = q.Enqueue(Message{}, email)
+	if err := q.Enqueue(Message{}, email); !errors.Is(err, ErrQueueFull) {
+		t.Errorf("Expected ErrQueueFull, got %v", err)
+	}
+}
+
+type blockingChannel struct {
+	release chan struct{}
+	started chan struct{}
+	once    sync.Once
+}
+
+func (c *blockingChannel) Name() model.NotificationChannel { return model.NotificationChannelEmail }
+
+func (c *blockingChannel) Deliver(_ context.Context, _ Message) error {
+	c.once.Do(func() { close(c.started) })
+	<-c.release
+	return nil
+}
+
+func TestQueue_EnqueueAfter(t *testing.T) {
+	ch := &flakyChannel{name: model.NotificationChannelEmail}
+	q := NewQueue([]Channel{ch}, QueueOptions{Workers: 1}, testLogger())
+	email := []model.NotificationChannel{model.NotificationChannelEmail}
+
+	if err := q.EnqueueAfter(Message{Title: "morning"}, email, 20*time.Millisecond); err != nil {
+		t.Fatalf("EnqueueAfter failed: %v", err)
+	}
+	ch.mu.Lock()
+	early := ch.attempts
+	ch.mu.Unlock()
+	if early != 0 {
+		t.Fatal("Expected no delivery before the delay")
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		ch.mu.Lock()
+		delivered := len(ch.delivered)
+		ch.mu.Unlock()
+		if delivered == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for the scheduled delivery")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 停止時に時刻が来ていない予約は破棄する
+	_ = q.EnqueueAfter(Message{Title: "tomorrow"}, email, time.Hour)
+	q.Close()
+	if len(ch.delivered) != 1 {
+		t.Errorf("Expected the pending schedule to be dropped on Close, got %d deliveries", len(ch.delivered))
+	}
+	if err := q.EnqueueAfter(Message{}, email, time.Minute); !errors.Is(err, ErrQueueClosed) {
+		t.Errorf("Expected ErrQueueClosed after Close, got %v", err)
+	}
+}
+
+func TestQueue_OnlySelectedChannels(t *testing.T) {
+	email := &flakyChannel{name: model.NotificationChannelEmail}
+	chat := &flakyChannel{name: model.NotificationChannelChat}
+	q := NewQueue([]Channel{email, chat}, QueueOptions{}, testLogger())
+
+	_ = q.Enqueue(Message{Type: model.NotificationTypeGeneral}, []model.NotificationChannel{model.NotificationChannelChat, model.NotificationChannelPush})
+	q.Close()
+
+	if email.attempts != 0 || chat.attempts != 1 {
+		t.Errorf("Expected delivery to chat only, got email=%d chat=%d", email.attempts, chat.attempts)
+	}
+}
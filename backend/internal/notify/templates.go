@@ -0,0 +1,167 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// 対応する言語
+const (
+	LocaleJa = "ja"
+	LocaleEn = "en"
+)
+
+// TemplateData はテンプレートに渡す値
+type TemplateData struct {
+	Name  string
+	Title string
+	Body  string
+	URL   string
+}
+
+// templateSource は件名と本文のテンプレート
+type templateSource struct {
+	Subject string
+	Lead    string
+}
+
+// 本文は共通の枠（宛名・導入文・通知の文面・リンク）に、種別ごとの導入文を差し込む
+var bodyLayouts = map[string]string{
+	LocaleJa: `{{if .Name}}{{.Name}} 様{{"\n\n"}}{{end}}{{.Lead}}
+
+{{.Body}}
+{{if .URL}}
+詳細はこちらから確認してください。
+{{.URL}}
+{{end}}
+※このメールは送信専用です。通知のメール配信は個人設定で変更できます。
+`,
+	LocaleEn: `{{if .Name}}Hello {{.Name}},{{"\n\n"}}{{end}}{{.Lead}}
+
+{{.Body}}
+{{if .URL}}
+See the details here:
+{{.URL}}
+{{end}}
+This is an automated message. You can change email notifications in your settings.
+`,
+}
+
+var templateSources = map[string]map[model.NotificationType]templateSource{
+	LocaleJa: {
+		model.NotificationTypeLeaveApproved:       {"【勤怠管理】休暇申請が承認されました: {{.Title}}", "休暇申請が承認されました。"},
+		model.NotificationTypeLeaveRejected:       {"【勤怠管理】休暇申請が却下されました: {{.Title}}", "休暇申請が却下されました。内容を確認してください。"},
+		model.NotificationTypeLeaveRequested:      {"【勤怠管理】休暇申請の承認依頼: {{.Title}}", "休暇申請の承認依頼が届いています。"},
+		model.NotificationTypeOvertimeAlert:       {"【勤怠管理】残業のお知らせ: {{.Title}}", "残業に関するお知らせがあります。"},
+		model.NotificationTypeOvertimeReq:         {"【勤怠管理】残業申請の承認依頼: {{.Title}}", "残業申請の承認依頼が届いています。"},
+		model.NotificationTypeCorrectionResult:    {"【勤怠管理】打刻修正の結果: {{.Title}}", "打刻修正申請の処理結果をお知らせします。"},
+		model.NotificationTypeCorrectionReq:       {"【勤怠管理】打刻修正の承認依頼: {{.Title}}", "打刻修正申請の承認依頼が届いています。"},
+		model.NotificationTypeShiftChanged:        {"【勤怠管理】シフトのお知らせ: {{.Title}}", "シフトに変更がありました。"},
+		model.NotificationTypeClockReminder:       {"【勤怠管理】打刻のリマインド: {{.Title}}", "打刻が確認できていません。"},
+		model.NotificationTypeNewDeviceLogin:      {"【勤怠管理】新しい端末からのログイン", "これまでに使われていない端末からログインがありました。"},
+		model.NotificationTypeExpenseSubmitted:    {"【勤怠管理】経費申請が提出されました: {{.Title}}", "経費申請が提出されました。"},
+		model.NotificationTypeExpenseApproved:     {"【勤怠管理】経費申請が承認されました: {{.Title}}", "経費申請が承認されました。"},
+		model.NotificationTypeExpenseRejected:     {"【勤怠管理】経費申請が却下されました: {{.Title}}", "経費申請が却下されました。内容を確認してください。"},
+		model.NotificationTypeExpenseComment:      {"【勤怠管理】経費申請へのコメント: {{.Title}}", "経費申請にコメントが追加されました。"},
+		model.NotificationTypeExpenseReimbursed:   {"【勤怠管理】経費の精算が完了しました: {{.Title}}", "経費の精算が完了しました。"},
+		model.NotificationTypeExpenseReminder:     {"【勤怠管理】経費申請のリマインド: {{.Title}}", "対応が必要な経費申請があります。"},
+		model.NotificationTypeEvaluationSubmitted: {"【勤怠管理】評価が提出されました: {{.Title}}", "評価が提出されました。"},
+		model.NotificationTypeOneOnOneScheduled:   {"【勤怠管理】1on1の予定: {{.Title}}", "1on1ミーティングが設定されました。"},
+		model.NotificationTypeTrainingEnrolled:    {"【勤怠管理】研修への登録: {{.Title}}", "研修に登録されました。"},
+		model.NotificationTypeGeneral:             {"【勤怠管理】{{.Title}}", "お知らせがあります。"},
+	},
+	LocaleEn: {
+		model.NotificationTypeLeaveApproved:       {"[Kintai] Leave request approved: {{.Title}}", "Your leave request has been approved."},
+		model.NotificationTypeLeaveRejected:       {"[Kintai] Leave request rejected: {{.Title}}", "Your leave request has been rejected. Please review it."},
+		model.NotificationTypeLeaveRequested:      {"[Kintai] Leave request awaiting approval: {{.Title}}", "A leave request is waiting for your approval."},
+		model.NotificationTypeOvertimeAlert:       {"[Kintai] Overtime notice: {{.Title}}", "There is an update about overtime."},
+		model.NotificationTypeOvertimeReq:         {"[Kintai] Overtime request awaiting approval: {{.Title}}", "An overtime request is waiting for your approval."},
+		model.NotificationTypeCorrectionResult:    {"[Kintai] Attendance correction result: {{.Title}}", "Your attendance correction request has been processed."},
+		model.NotificationTypeCorrectionReq:       {"[Kintai] Attendance correction awaiting approval: {{.Title}}", "An attendance correction request is waiting for your approval."},
+		model.NotificationTypeShiftChanged:        {"[Kintai] Shift update: {{.Title}}", "Your shifts have changed."},
+		model.NotificationTypeClockReminder:       {"[Kintai] Clock-in reminder: {{.Title}}", "We could not find your clock-in or clock-out."},
+		model.NotificationTypeNewDeviceLogin:      {"[Kintai] Sign-in from a new device", "Your account was used to sign in from a new device."},
+		model.NotificationTypeExpenseSubmitted:    {"[Kintai] Expense claim submitted: {{.Title}}", "An expense claim has been submitted."},
+		model.NotificationTypeExpenseApproved:     {"[Kintai] Expense claim approved: {{.Title}}", "Your expense claim has been approved."},
+		model.NotificationTypeExpenseRejected:     {"[Kintai] Expense claim rejected: {{.Title}}", "Your expense claim has been rejected. Please review it."},
+		model.NotificationTypeExpenseComment:      {"[Kintai] New comment on your expense claim: {{.Title}}", "A comment was added to your expense claim."},
+		model.NotificationTypeExpenseReimbursed:   {"[Kintai] Expense reimbursed: {{.Title}}", "Your expense claim has been reimbursed."},
+		model.NotificationTypeExpenseReminder:     {"[Kintai] Expense reminder: {{.Title}}", "An expense claim needs your attention."},
+		model.NotificationTypeEvaluationSubmitted: {"[Kintai] Evaluation submitted: {{.Title}}", "An evaluation has been submitted."},
+		model.NotificationTypeOneOnOneScheduled:   {"[Kintai] 1:1 meeting scheduled: {{.Title}}", "A 1:1 meeting has been scheduled."},
+		model.NotificationTypeTrainingEnrolled:    {"[Kintai] Training enrollment: {{.Title}}", "You have been enrolled in a training."},
+		model.NotificationTypeGeneral:             {"[Kintai] {{.Title}}", "You have a new notification."},
+	},
+}
+
+type compiledTemplate struct {
+	subject *template.Template
+	body    *template.Template
+	lead    *template.Template
+}
+
+// Templates は通知種別・言語ごとのメールテンプレート。
+// 未定義の種別は general、未対応の言語は既定の言語のテンプレートを使う
+type Templates struct {
+	defaultLocale string
+	compiled      map[string]map[model.NotificationType]compiledTemplate
+}
+
+// NewTemplates は組み込みのテンプレートを読み込む。defaultLocale が未対応の場合は ja を使う
+func NewTemplates(defaultLocale string) (*Templates, error) {
+	if _, ok := templateSources[defaultLocale]; !ok {
+		defaultLocale = LocaleJa
+	}
+	t := &Templates{defaultLocale: defaultLocale, compiled: map[string]map[model.NotificationType]compiledTemplate{}}
+	for locale, sources := range templateSources {
+		body, err := template.New(locale).Parse(bodyLayouts[locale])
+		if err != nil {
+			return nil, fmt.Errorf("parse body layout %s: %w", locale, err)
+		}
+		t.compiled[locale] = map[model.NotificationType]compiledTemplate{}
+		for notifType, src := range sources {
+			subject, err := template.New(string(notifType)).Parse(src.Subject)
+			if err != nil {
+				return nil, fmt.Errorf("parse subject %s/%s: %w", locale, notifType, err)
+			}
+			lead, err := template.New(string(notifType)).Parse(src.Lead)
+			if err != nil {
+				return nil, fmt.Errorf("parse lead %s/%s: %w", locale, notifType, err)
+			}
+			t.compiled[locale][notifType] = compiledTemplate{subject: subject, body: body, lead: lead}
+		}
+	}
+	return t, nil
+}
+
+// Render は件名と本文を組み立てる
+func (t *Templates) Render(locale string, notifType model.NotificationType, data TemplateData) (subject, body string, err error) {
+	byType, ok := t.compiled[locale]
+	if !ok {
+		byType = t.compiled[t.defaultLocale]
+	}
+	tmpl, ok := byType[notifType]
+	if !ok {
+		tmpl = byType[model.NotificationTypeGeneral]
+	}
+
+	var sb, lb, bb strings.Builder
+	if err := tmpl.subject.Execute(&sb, data); err != nil {
+		return "", "", err
+	}
+	if err := tmpl.lead.Execute(&lb, data); err != nil {
+		return "", "", err
+	}
+	layout := struct {
+		TemplateData
+		Lead string
+	}{data, lb.String()}
+	if err := tmpl.body.Execute(&bb, layout); err != nil {
+		return "", "", err
+	}
+	// 件名に改行が入るとヘッダーが壊れるため1行にする
+	return strings.Join(strings.Fields(sb.String()), " "), bb.String(), nil
+}
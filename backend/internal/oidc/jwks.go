@@ -0,0 +1,154 @@
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// jwksMinRefreshInterval は未知の kid による JWKS の再取得の最短間隔
+const jwksMinRefreshInterval = time.Minute
+
+// KeySet は JWKS の公開鍵を kid ごとにキャッシュする。
+// 未知の kid は鍵のローテーションとみなして JWKS を取り直す
+type KeySet struct {
+	url    string
+	client *http.Client
+
+	mu        sync.Mutex
+	keys      map[string]interface{}
+	fetchedAt time.Time
+	now       func() time.Time
+}
+
+// NewKeySet は jwksURL から公開鍵を取得する KeySet を作成する。client が nil の場合は既定のクライアントを使う
+func NewKeySet(jwksURL string, client *http.Client) *KeySet {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{url: jwksURL, client: client, now: time.Now}
+}
+
+// Key は kid に対応する公開鍵を返す
+func (s *KeySet) Key(ctx context.Context, kid string) (interface{}, error) {
+	s.mu.Lock()
+	keys, fetchedAt := s.keys, s.fetchedAt
+	s.mu.Unlock()
+
+	if k, ok := lookupKey(keys, kid); ok {
+		return k, nil
+	}
+	if keys != nil && s.now().Sub(fetchedAt) < jwksMinRefreshInterval {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+
+	var set jwkSet
+	if err := getJSON(ctx, s.client, s.url, &set); err != nil {
+		return nil, fmt.Errorf("oidc: jwks: %w", err)
+	}
+	keys = set.publicKeys()
+
+	s.mu.Lock()
+	s.keys, s.fetchedAt = keys, s.now()
+	s.mu.Unlock()
+
+	if k, ok := lookupKey(keys, kid); ok {
+		return k, nil
+	}
+	return nil, fmt.Errorf("unknown key id %q", kid)
+}
+
+// lookupKey は kid で鍵を探す。kid のないトークンは鍵が1つの場合のみ受け付ける
+func lookupKey(keys map[string]interface{}, kid string) (interface{}, bool) {
+	if k, ok := keys[kid]; ok {
+		return k, true
+	}
+	if kid == "" && len(keys) == 1 {
+		for _, k := range keys {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// jwkSet は JWKS（RFC 7517）のうち署名検証に使う項目
+type jwkSet struct {
+	Keys []jwk `json:"keys"`
+}
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// publicKeys は署名用の鍵を kid ごとの公開鍵にする。解釈できない鍵は無視する
+func (s jwkSet) publicKeys() map[string]interface{} {
+	keys := make(map[string]interface{}, len(s.Keys))
+	for _, k := range s.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		switch k.Kty {
+		case "RSA":
+			if pub := k.rsaKey(); pub != nil {
+				keys[k.Kid] = pub
+			}
+		case "EC":
+			if pub := k.ecKey(); pub != nil {
+				keys[k.Kid] = pub
+			}
+		}
+	}
+	return keys
+}
+
+func (k jwk) rsaKey() *rsa.PublicKey {
+	n, err := base64.RawURLEncoding.DecodeString(k.N)
+	if err != nil || len(n) == 0 {
+		return nil
+	}
+	e, err := base64.RawURLEncoding.DecodeString(k.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil
+	}
+	exp := 0
+	for _, b := range e {
+		exp = exp<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exp}
+}
+
+func (k jwk) ecKey() *ecdsa.PublicKey {
+	var curve elliptic.Curve
+	switch k.Crv {
+	case "P-256":
+		curve = elliptic.P256()
+	case "P-384":
+		curve = elliptic.P384()
+	case "P-521":
+		curve = elliptic.P521()
+	default:
+		return nil
+	}
+	x, errX := base64.RawURLEncoding.DecodeString(k.X)
+	y, errY := base64.RawURLEncoding.DecodeString(k.Y)
+	if errX != nil || errY != nil {
+		return nil
+	}
+	return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+}
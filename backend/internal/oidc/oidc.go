@@ -0,0 +1,331 @@
+// Package oidc は OpenID Connect の認可コードフロー（PKCE）のクライアント。
+// ディスカバリー・トークン交換・JWKS による ID トークンの検証を行う
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrInvalidIDToken = errors.New("oidc: invalid id token")
+	ErrNonceMismatch  = errors.New("oidc: nonce mismatch")
+)
+
+// Config は IdP とクライアントの設定
+type Config struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	// RedirectURL は IdP から戻るコールバックの URL（IdP に登録したものと一致させる）
+	RedirectURL string
+	Scopes      []string
+	// GroupsClaim はグループ一覧を持つクレーム名（既定は groups）
+	GroupsClaim string
+	HTTPClient  *http.Client
+}
+
+// Metadata はディスカバリー文書のうち利用する項目
+type Metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Token はトークンエンドポイントの応答
+type Token struct {
+	IDToken     string `json:"id_token"`
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	ExpiresIn   int    `json:"expires_in"`
+}
+
+// Claims は検証済み ID トークンの内容
+type Claims struct {
+	Issuer        string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	GivenName     string
+	FamilyName    string
+	Groups        []string
+}
+
+// Provider は1つの IdP に対するクライアント。ディスカバリー文書と公開鍵をキャッシュする
+type Provider struct {
+	cfg    Config
+	client *http.Client
+
+	mu       sync.Mutex
+	metadata *Metadata
+	keySet   *KeySet
+	now      func() time.Time
+}
+
+func NewProvider(cfg Config) *Provider {
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	if cfg.GroupsClaim == "" {
+		cfg.GroupsClaim = "groups"
+	}
+	cfg.IssuerURL = strings.TrimRight(cfg.IssuerURL, "/")
+	return &Provider{cfg: cfg, client: client, now: time.Now}
+}
+
+// Discover はディスカバリー文書を取得する（成功した結果はキャッシュする）
+func (p *Provider) Discover(ctx context.Context) (*Metadata, error) {
+	p.mu.Lock()
+	cached := p.metadata
+	p.mu.Unlock()
+	if cached != nil {
+		return cached, nil
+	}
+
+	var md Metadata
+	if err := getJSON(ctx, p.client, p.cfg.IssuerURL+"/.well-known/openid-configuration", &md); err != nil {
+		return nil, fmt.Errorf("oidc: discovery: %w", err)
+	}
+	if strings.TrimRight(md.Issuer, "/") != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("oidc: issuer mismatch: %q", md.Issuer)
+	}
+	if md.AuthorizationEndpoint == "" || md.TokenEndpoint == "" || md.JWKSURI == "" {
+		return nil, errors.New("oidc: incomplete discovery document")
+	}
+
+	p.mu.Lock()
+	p.metadata = &md
+	p.mu.Unlock()
+	return &md, nil
+}
+
+// AuthCodeURL は IdP の認可エンドポイントへのリダイレクト先を作る
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	md, err := p.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(md.AuthorizationEndpoint)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.cfg.ClientID)
+	q.Set("redirect_uri", p.cfg.RedirectURL)
+	q.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	q.Set("state", state)
+	q.Set("nonce", nonce)
+	q.Set("code_challenge", CodeChallengeS256(verifier))
+	q.Set("code_challenge_method", "S256")
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Exchange は認可コードをトークンに交換する
+func (p *Provider) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	md, err := p.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"client_id":     {p.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, md.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		// client_secret_basic（RFC 6749 2.3.1 によりURLエンコードする）
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		_ = json.Unmarshal(body, &e)
+		return nil, fmt.Errorf("oidc: token endpoint returned %d: %s %s", resp.StatusCode, e.Error, e.Description)
+	}
+
+	var token Token
+	if err := json.Unmarshal(body, &token); err != nil {
+		return nil, fmt.Errorf("oidc: token response: %w", err)
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("oidc: token response has no id_token")
+	}
+	return &token, nil
+}
+
+// VerifyIDToken は署名・発行者・対象者・有効期限・nonce を検証してクレームを返す
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*Claims, error) {
+	parser := jwt.NewParser(
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "ES256", "ES384", "ES512"}),
+		jwt.WithIssuer(p.cfg.IssuerURL),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(time.Minute),
+		jwt.WithTimeFunc(p.now),
+	)
+	mc := jwt.MapClaims{}
+	_, err := parser.ParseWithClaims(raw, mc, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return p.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	// 複数の対象者がいる場合は azp が自分であること
+	if aud, _ := mc.GetAudience(); len(aud) > 1 {
+		if azp, _ := mc["azp"].(string); azp != p.cfg.ClientID {
+			return nil, fmt.Errorf("%w: azp mismatch", ErrInvalidIDToken)
+		}
+	}
+	if got, _ := mc["nonce"].(string); got == "" || got != nonce {
+		return nil, ErrNonceMismatch
+	}
+
+	claims := &Claims{
+		Issuer:        stringClaim(mc, "iss"),
+		Subject:       stringClaim(mc, "sub"),
+		Email:         stringClaim(mc, "email"),
+		EmailVerified: boolClaim(mc, "email_verified"),
+		Name:          stringClaim(mc, "name"),
+		GivenName:     stringClaim(mc, "given_name"),
+		FamilyName:    stringClaim(mc, "family_name"),
+		Groups:        stringsClaim(mc, p.cfg.GroupsClaim),
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: missing sub", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+// key は kid に対応する公開鍵を返す。JWKS の URL はディスカバリー文書から取得する
+func (p *Provider) key(ctx context.Context, kid string) (interface{}, error) {
+	p.mu.Lock()
+	keySet := p.keySet
+	p.mu.Unlock()
+
+	if keySet == nil {
+		md, err := p.Discover(ctx)
+		if err != nil {
+			return nil, err
+		}
+		p.mu.Lock()
+		if p.keySet == nil {
+			p.keySet = &KeySet{url: md.JWKSURI, client: p.client, now: p.now}
+		}
+		keySet = p.keySet
+		p.mu.Unlock()
+	}
+	return keySet.Key(ctx, kid)
+}
+
+func getJSON(ctx context.Context, client *http.Client, rawURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from %s", resp.StatusCode, rawURL)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+// NewCodeVerifier は PKCE の code_verifier（43文字）を生成する
+func NewCodeVerifier() (string, error) {
+	return RandomString(32)
+}
+
+// CodeChallengeS256 は code_verifier から S256 の code_challenge を計算する
+func CodeChallengeS256(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// RandomString は n バイトの乱数を URL セーフな文字列にする（state・nonce 用）
+func RandomString(n int) (string, error) {
+	buf := make([]byte, n)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func stringClaim(mc jwt.MapClaims, name string) string {
+	s, _ := mc[name].(string)
+	return s
+}
+
+// boolClaim は真偽値を文字列で返す IdP（"true"）にも対応する
+func boolClaim(mc jwt.MapClaims, name string) bool {
+	switch v := mc[name].(type) {
+	case bool:
+		return v
+	case string:
+		return strings.EqualFold(v, "true")
+	}
+	return false
+}
+
+// stringsClaim は文字列の配列または単一の文字列を受け付ける
+func stringsClaim(mc jwt.MapClaims, name string) []string {
+	switch v := mc[name].(type) {
+	case string:
+		return []string{v}
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
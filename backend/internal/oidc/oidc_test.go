@@ -0,0 +1,119 @@
+package oidc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/your-org/kintai/backend/internal/oidc"
+	"github.com/your-org/kintai/backend/internal/oidc/oidctest"
+)
+
+func setupProvider(t *testing.T) (*oidctest.Server, *oidc.Provider) {
+	t.Helper()
+	idp, err := oidctest.NewServer("kintai", "secret")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(idp.Close)
+	p := oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "kintai",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+	})
+	return idp, p
+}
+
+func TestProvider_AuthorizationCodeFlow(t *testing.T) {
+	idp, p := setupProvider(t)
+	ctx := context.Background()
+	idp.SetUser(oidctest.User{Subject: "abc", Email: "a@example.com", EmailVerified: true, Groups: []string{"hr"}})
+
+	verifier, _ := oidc.NewCodeVerifier()
+	authURL, err := p.AuthCodeURL(ctx, "state-1", "nonce-1", verifier)
+	if err != nil {
+		t.Fatalf("AuthCodeURL failed: %v", err)
+	}
+	callback, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	if callback.Query().Get("state") != "state-1" {
+		t.Errorf("Expected state to round-trip, got %q", callback.Query().Get("state"))
+	}
+
+	token, err := p.Exchange(ctx, callback.Query().Get("code"), verifier)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, "nonce-1")
+	if err != nil {
+		t.Fatalf("VerifyIDToken failed: %v", err)
+	}
+	if claims.Subject != "abc" || claims.Email != "a@example.com" || !claims.EmailVerified || len(claims.Groups) != 1 || claims.Groups[0] != "hr" {
+		t.Errorf("Unexpected claims: %+v", claims)
+	}
+}
+
+func TestProvider_Exchange_RequiresMatchingVerifier(t *testing.T) {
+	idp, p := setupProvider(t)
+	ctx := context.Background()
+
+	verifier, _ := oidc.NewCodeVerifier()
+	authURL, _ := p.AuthCodeURL(ctx, "s", "n", verifier)
+	callback, err := idp.Authorize(authURL)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	other, _ := oidc.NewCodeVerifier()
+	if _, err := p.Exchange(ctx, callback.Query().Get("code"), other); err == nil {
+		t.Error("Exchange with a different verifier should fail")
+	}
+}
+
+func TestProvider_VerifyIDToken_Rejects(t *testing.T) {
+	idp, p := setupProvider(t)
+	ctx := context.Background()
+	now := time.Now()
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{"iss": idp.Issuer(), "sub": "abc", "aud": "kintai", "exp": now.Add(time.Minute).Unix(), "iat": now.Unix(), "nonce": "n"}
+	}
+
+	cases := map[string]func(jwt.MapClaims){
+		"wrong issuer":   func(c jwt.MapClaims) { c["iss"] = "https://evil.example.com" },
+		"wrong audience": func(c jwt.MapClaims) { c["aud"] = "other" },
+		"expired":        func(c jwt.MapClaims) { c["exp"] = now.Add(-time.Hour).Unix() },
+		"wrong nonce":    func(c jwt.MapClaims) { c["nonce"] = "x" },
+		"foreign azp":    func(c jwt.MapClaims) { c["aud"] = []string{"kintai", "other"}; c["azp"] = "other" },
+	}
+	for name, mutate := range cases {
+		claims := valid()
+		mutate(claims)
+		raw, _ := idp.Sign(claims)
+		if _, err := p.VerifyIDToken(ctx, raw, "n"); err == nil {
+			t.Errorf("%s: expected verification to fail", name)
+		}
+	}
+
+	raw, _ := idp.Sign(valid())
+	if _, err := p.VerifyIDToken(ctx, raw, "n"); err != nil {
+		t.Fatalf("Valid token rejected: %v", err)
+	}
+
+	// HMAC で署名したトークン（アルゴリズムのすり替え）は受け付けない
+	forged, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, valid()).SignedString([]byte("secret"))
+	if _, err := p.VerifyIDToken(ctx, forged, "n"); !errors.Is(err, oidc.ErrInvalidIDToken) {
+		t.Errorf("Expected ErrInvalidIDToken for HS256 token, got %v", err)
+	}
+}
+
+func TestCodeChallengeS256(t *testing.T) {
+	// BASE64URL(SHA256(verifier))（パディングなし）
+	got := oidc.CodeChallengeS256("kintai-pkce-verifier-0123456789abcdefghijklmn")
+	if got != "yjERd_13irBSUmlsLsErf2YRySgw6MpeRJ5d6dukBgk" {
+		t.Errorf("Unexpected code challenge: %s", got)
+	}
+}
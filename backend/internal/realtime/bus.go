@@ -0,0 +1,109 @@
+package realtime
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// redisChannel はイベントを共有する Redis のチャネル名
+const redisChannel = "kintai:events"
+
+// Bus はインスタンス間でイベントを共有する
+type Bus interface {
+	Publish(ctx context.Context, payload []byte) error
+	// Subscribe は受信したメッセージを handler に渡し続ける。ctx が終了するまで戻らない
+	Subscribe(ctx context.Context, handler func(payload []byte)) error
+}
+
+// ===== Redis =====
+
+type redisBus struct {
+	client *redis.Client
+}
+
+// NewRedisBus は REDIS_URL 形式の接続先から Redis pub/sub の Bus を作成する
+func NewRedisBus(redisURL string) (Bus, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	// 発行はリクエスト処理中に行うため、障害時に長く待たないようにする
+	opts.DialTimeout = 500 * time.Millisecond
+	opts.WriteTimeout = 200 * time.Millisecond
+	opts.MaxRetries = 1
+	return &redisBus{client: redis.NewClient(opts)}, nil
+}
+
+func (b *redisBus) Publish(ctx context.Context, payload []byte) error {
+	return b.client.Publish(ctx, redisChannel, payload).Err()
+}
+
+func (b *redisBus) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	// 切断時は go-redis が再接続して購読し直す
+	ps := b.client.Subscribe(ctx, redisChannel)
+	defer ps.Close()
+
+	ch := ps.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			handler([]byte(msg.Payload))
+		}
+	}
+}
+
+// ===== インメモリ =====
+
+// MemoryBus はプロセス内の Bus。同じ MemoryBus を共有する Hub 同士で配信する
+type MemoryBus struct {
+	mu       sync.Mutex
+	nextID   int
+	handlers map[int]func([]byte)
+}
+
+func NewMemoryBus() *MemoryBus {
+	return &MemoryBus{handlers: make(map[int]func([]byte))}
+}
+
+func (b *MemoryBus) Publish(ctx context.Context, payload []byte) error {
+	b.mu.Lock()
+	handlers := make([]func([]byte), 0, len(b.handlers))
+	for _, h := range b.handlers {
+		handlers = append(handlers, h)
+	}
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(payload)
+	}
+	return nil
+}
+
+func (b *MemoryBus) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.handlers[id] = handler
+	b.mu.Unlock()
+
+	<-ctx.Done()
+
+	b.mu.Lock()
+	delete(b.handlers, id)
+	b.mu.Unlock()
+	return ctx.Err()
+}
+
+func (b *MemoryBus) subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.handlers)
+}
@@ -0,0 +1,271 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+// EventType はクライアントへ送るイベントの種類（SSE の event フィールド）
+type EventType string
+
+const (
+	// EventNotification は新しいアプリ内通知
+	EventNotification EventType = "notification"
+	// EventApproval は申請の承認状況の変更
+	EventApproval EventType = "approval"
+	// EventAnnouncement は社内お知らせの公開・更新
+	EventAnnouncement EventType = "announcement"
+	// EventResync は再開位置のイベントが残っていないことを表す。クライアントは一覧を取り直す
+	EventResync EventType = "resync"
+)
+
+// Event はインスタンス間で共有するイベント
+type Event struct {
+	ID   string    `json:"id"`
+	Type EventType `json:"type"`
+	// UserID が nil の場合は接続中の全ユーザーに配信する
+	UserID *uuid.UUID      `json:"user_id,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// ApprovalChange は EventApproval のデータ
+type ApprovalChange struct {
+	// Kind は申請の種類（leave / overtime / correction / substitute_holiday / expense）
+	Kind   string    `json:"kind"`
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+// AnnouncementChange は EventAnnouncement のデータ。本文はクライアントが取得し直す
+type AnnouncementChange struct {
+	ID       uuid.UUID `json:"id"`
+	Title    string    `json:"title"`
+	Priority string    `json:"priority"`
+}
+
+// Config は Hub の設定
+type Config struct {
+	// ReplaySize は Last-Event-ID からの再開のために保持する直近のイベント数
+	ReplaySize int
+	// Heartbeat は接続維持のためにコメント行を送る間隔
+	Heartbeat time.Duration
+}
+
+// subscriberBuffer は購読者ごとの未送信イベントの上限。超えた購読者は切断し、再接続時の再開に任せる
+const subscriberBuffer = 64
+
+// Hub は接続中のクライアントへイベントを配信する。
+// 発行したイベントは Bus を経由して全インスタンスの Hub に届き、各 Hub が自分の接続へ振り分ける。
+// Bus への発行に失敗した場合はこのインスタンスの接続にだけ配信する。
+// nil の Hub は何も配信しない
+type Hub struct {
+	bus    Bus
+	cfg    Config
+	logger *logger.Logger
+
+	mu      sync.Mutex
+	subs    map[*Subscription]struct{}
+	history []Event
+}
+
+// NewHub は Hub を作成する。bus が nil の場合はプロセス内だけで配信する
+func NewHub(bus Bus, cfg Config, log *logger.Logger) *Hub {
+	if cfg.ReplaySize < 0 {
+		cfg.ReplaySize = 0
+	}
+	if cfg.Heartbeat <= 0 {
+		cfg.Heartbeat = 25 * time.Second
+	}
+	return &Hub{bus: bus, cfg: cfg, logger: log, subs: make(map[*Subscription]struct{})}
+}
+
+// NewRedisHub は Redis pub/sub でインスタンス間に配信する Hub を作成する。
+// 接続先が不正な場合はプロセス内だけで配信する
+func NewRedisHub(redisURL string, cfg Config, log *logger.Logger) *Hub {
+	bus, err := NewRedisBus(redisURL)
+	if err != nil {
+		if log != nil {
+			log.Warn("Redisの接続先が不正なため、リアルタイム配信はこのインスタンス内だけで行います", "error", err)
+		}
+		return NewHub(nil, cfg, log)
+	}
+	return NewHub(bus, cfg, log)
+}
+
+// Heartbeat は接続維持のコメント行を送る間隔を返す
+func (h *Hub) Heartbeat() time.Duration {
+	if h == nil {
+		return 25 * time.Second
+	}
+	return h.cfg.Heartbeat
+}
+
+// Run は Bus から届いたイベントを配信し続ける。ctx が終了するまで戻らない
+func (h *Hub) Run(ctx context.Context) {
+	if h == nil || h.bus == nil {
+		return
+	}
+	err := h.bus.Subscribe(ctx, func(payload []byte) {
+		var ev Event
+		if err := json.Unmarshal(payload, &ev); err != nil {
+			if h.logger != nil {
+				h.logger.Warn("不正なリアルタイムイベントを受信しました", "error", err)
+			}
+			return
+		}
+		h.dispatch(ev)
+	})
+	if err != nil && ctx.Err() == nil && h.logger != nil {
+		h.logger.Error("リアルタイムイベントの購読が終了しました", err)
+	}
+}
+
+// PublishTo はユーザーにイベントを配信する
+func (h *Hub) PublishTo(ctx context.Context, userID uuid.UUID, typ EventType, data any) {
+	h.publish(ctx, &userID, typ, data)
+}
+
+// Broadcast は接続中の全ユーザーにイベントを配信する
+func (h *Hub) Broadcast(ctx context.Context, typ EventType, data any) {
+	h.publish(ctx, nil, typ, data)
+}
+
+// publish はイベントを発行する。配信は補助的な経路のため、失敗はログに残すだけにする
+func (h *Hub) publish(ctx context.Context, userID *uuid.UUID, typ EventType, data any) {
+	if h == nil {
+		return
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		if h.logger != nil {
+			h.logger.Warn("リアルタイムイベントの変換に失敗しました", "type", typ, "error", err)
+		}
+		return
+	}
+	ev := Event{ID: newEventID(), Type: typ, UserID: userID, Data: raw}
+	if h.bus == nil {
+		h.dispatch(ev)
+		return
+	}
+	payload, _ := json.Marshal(ev)
+	if err := h.bus.Publish(ctx, payload); err != nil {
+		if h.logger != nil {
+			h.logger.Warn("リアルタイムイベントの共有に失敗しました。このインスタンスの接続にだけ配信します", "type", typ, "error", err)
+		}
+		h.dispatch(ev)
+	}
+}
+
+// dispatch はイベントを履歴に追加し、対象の購読者へ送る。
+// 送り切れない購読者は切断する（クライアントは Last-Event-ID で再開する）
+func (h *Hub) dispatch(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.cfg.ReplaySize > 0 {
+		h.history = append(h.history, ev)
+		if over := len(h.history) - h.cfg.ReplaySize; over > 0 {
+			h.history = append(h.history[:0:0], h.history[over:]...)
+		}
+	}
+	for sub := range h.subs {
+		if !sub.wants(ev) {
+			continue
+		}
+		select {
+		case sub.ch <- ev:
+		default:
+			h.removeLocked(sub)
+		}
+	}
+}
+
+// Subscribe はユーザーの購読を開始する。
+// lastEventID を指定した場合は、それより後に発行された未受信のイベントを backlog として返す。
+// lastEventID が履歴に残っていない場合は resync=true を返す
+func (h *Hub) Subscribe(userID uuid.UUID, lastEventID string) (sub *Subscription, backlog []Event, resync bool) {
+	sub = &Subscription{hub: h, userID: userID, ch: make(chan Event, subscriberBuffer)}
+	if h == nil {
+		close(sub.ch)
+		return sub, nil, false
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// 履歴の参照と購読の登録を同じロック内で行い、取りこぼしと重複を防ぐ
+	if lastEventID != "" {
+		found := false
+		for _, ev := range h.history {
+			if found && sub.wants(ev) {
+				backlog = append(backlog, ev)
+			}
+			if ev.ID == lastEventID {
+				found = true
+			}
+		}
+		if !found {
+			backlog, resync = nil, true
+		}
+	}
+	h.subs[sub] = struct{}{}
+	return sub, backlog, resync
+}
+
+// Subscribers は接続中の購読数を返す
+func (h *Hub) Subscribers() int {
+	if h == nil {
+		return 0
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.subs)
+}
+
+func (h *Hub) removeLocked(sub *Subscription) {
+	if _, ok := h.subs[sub]; !ok {
+		return
+	}
+	delete(h.subs, sub)
+	close(sub.ch)
+}
+
+// Subscription は1つの接続の購読
+type Subscription struct {
+	hub    *Hub
+	userID uuid.UUID
+	ch     chan Event
+}
+
+// Events は配信されたイベントを返す。購読が終了すると閉じる
+func (s *Subscription) Events() <-chan Event {
+	return s.ch
+}
+
+// Close は購読を終了する
+func (s *Subscription) Close() {
+	if s.hub == nil {
+		return
+	}
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.removeLocked(s)
+}
+
+func (s *Subscription) wants(ev Event) bool {
+	return ev.UserID == nil || *ev.UserID == s.userID
+}
+
+// newEventID は発行順に並ぶイベントIDを作成する
+func newEventID() string {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return uuid.NewString()
+	}
+	return id.String()
+}
@@ -0,0 +1,224 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// failingBus は障害中の Redis
+type failingBus struct{}
+
+func (failingBus) Publish(ctx context.Context, payload []byte) error {
+	return errors.New("connection refused")
+}
+
+func (failingBus) Subscribe(ctx context.Context, handler func(payload []byte)) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func receive(t *testing.T, sub *Subscription) Event {
+	t.Helper()
+	select {
+	case ev, ok := <-sub.Events():
+		if !ok {
+			t.Fatal("Subscription closed unexpectedly")
+		}
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	return Event{}
+}
+
+func assertNoEvent(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case ev := <-sub.Events():
+		t.Fatalf("Unexpected event: %+v", ev)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestHub_DeliversToTargetUserOnly(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(nil, Config{ReplaySize: 10}, nil)
+	alice, bob := uuid.New(), uuid.New()
+	subA, _, _ := h.Subscribe(alice, "")
+	subB, _, _ := h.Subscribe(bob, "")
+	defer subA.Close()
+	defer subB.Close()
+
+	h.PublishTo(ctx, alice, EventNotification, map[string]string{"title": "承認されました"})
+
+	ev := receive(t, subA)
+	if ev.Type != EventNotification || ev.ID == "" {
+		t.Errorf("Unexpected event: %+v", ev)
+	}
+	var data map[string]string
+	if err := json.Unmarshal(ev.Data, &data); err != nil || data["title"] != "承認されました" {
+		t.Errorf("Unexpected data: %s (%v)", ev.Data, err)
+	}
+	assertNoEvent(t, subB)
+}
+
+func TestHub_BroadcastReachesEveryone(t *testing.T) {
+	h := NewHub(nil, Config{}, nil)
+	subA, _, _ := h.Subscribe(uuid.New(), "")
+	subB, _, _ := h.Subscribe(uuid.New(), "")
+	defer subA.Close()
+	defer subB.Close()
+
+	h.Broadcast(context.Background(), EventAnnouncement, map[string]string{"title": "全社会議"})
+
+	if ev := receive(t, subA); ev.Type != EventAnnouncement {
+		t.Errorf("Expected announcement, got %s", ev.Type)
+	}
+	if ev := receive(t, subB); ev.Type != EventAnnouncement {
+		t.Errorf("Expected announcement, got %s", ev.Type)
+	}
+}
+
+func TestHub_ResumesFromLastEventID(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(nil, Config{ReplaySize: 10}, nil)
+	userID := uuid.New()
+	sub, _, _ := h.Subscribe(userID, "")
+
+	h.PublishTo(ctx, userID, EventNotification, 1)
+	first := receive(t, sub)
+	sub.Close()
+
+	// 切断中に発行されたイベント（他のユーザー宛は含めない）
+	h.PublishTo(ctx, userID, EventNotification, 2)
+	h.PublishTo(ctx, uuid.New(), EventNotification, 3)
+	h.Broadcast(ctx, EventAnnouncement, 4)
+
+	resumed, backlog, resync := h.Subscribe(userID, first.ID)
+	defer resumed.Close()
+	if resync {
+		t.Fatal("Expected resume without resync")
+	}
+	if len(backlog) != 2 || string(backlog[0].Data) != "2" || string(backlog[1].Data) != "4" {
+		t.Fatalf("Unexpected backlog: %+v", backlog)
+	}
+
+	// 再開後のイベントも届く
+	h.PublishTo(ctx, userID, EventApproval, 5)
+	if ev := receive(t, resumed); string(ev.Data) != "5" {
+		t.Errorf("Expected live event after backlog, got %s", ev.Data)
+	}
+}
+
+func TestHub_ResyncWhenLastEventIDIsGone(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(nil, Config{ReplaySize: 2}, nil)
+	userID := uuid.New()
+	sub, _, _ := h.Subscribe(userID, "")
+	h.PublishTo(ctx, userID, EventNotification, 1)
+	first := receive(t, sub)
+	sub.Close()
+
+	// 履歴の上限を超えて古いイベントが押し出される
+	h.PublishTo(ctx, userID, EventNotification, 2)
+	h.PublishTo(ctx, userID, EventNotification, 3)
+
+	resumed, backlog, resync := h.Subscribe(userID, first.ID)
+	defer resumed.Close()
+	if !resync || len(backlog) != 0 {
+		t.Errorf("Expected resync without backlog, got resync=%v backlog=%d", resync, len(backlog))
+	}
+}
+
+func TestHub_DropsSlowSubscriber(t *testing.T) {
+	ctx := context.Background()
+	h := NewHub(nil, Config{}, nil)
+	userID := uuid.New()
+	sub, _, _ := h.Subscribe(userID, "")
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		h.PublishTo(ctx, userID, EventNotification, i)
+	}
+
+	if h.Subscribers() != 0 {
+		t.Errorf("Expected slow subscriber to be dropped, got %d subscribers", h.Subscribers())
+	}
+	n := 0
+	for range sub.Events() {
+		n++
+	}
+	if n != subscriberBuffer {
+		t.Errorf("Expected %d buffered events before close, got %d", subscriberBuffer, n)
+	}
+	sub.Close()
+}
+
+func TestHub_FansOutAcrossInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	bus := NewMemoryBus()
+	a := NewHub(bus, Config{ReplaySize: 10}, nil)
+	b := NewHub(bus, Config{ReplaySize: 10}, nil)
+	go a.Run(ctx)
+	go b.Run(ctx)
+	waitFor(t, func() bool { return bus.subscribers() == 2 })
+
+	userID := uuid.New()
+	subA, _, _ := a.Subscribe(userID, "")
+	subB, _, _ := b.Subscribe(userID, "")
+	defer subA.Close()
+	defer subB.Close()
+
+	a.PublishTo(ctx, userID, EventApproval, ApprovalChange{Kind: "leave", ID: uuid.New(), Status: "approved"})
+
+	evA, evB := receive(t, subA), receive(t, subB)
+	if evA.ID != evB.ID {
+		t.Errorf("Expected the same event on both instances, got %s and %s", evA.ID, evB.ID)
+	}
+	// 別インスタンスに再接続しても再開できる
+	resumed, backlog, resync := b.Subscribe(userID, evA.ID)
+	defer resumed.Close()
+	if resync || len(backlog) != 0 {
+		t.Errorf("Expected resume on other instance, got resync=%v backlog=%d", resync, len(backlog))
+	}
+}
+
+func TestHub_FallsBackToLocalWhenBusFails(t *testing.T) {
+	h := NewHub(failingBus{}, Config{ReplaySize: 10}, nil)
+	userID := uuid.New()
+	sub, _, _ := h.Subscribe(userID, "")
+	defer sub.Close()
+
+	h.PublishTo(context.Background(), userID, EventNotification, 1)
+
+	if ev := receive(t, sub); string(ev.Data) != "1" {
+		t.Errorf("Expected local delivery, got %s", ev.Data)
+	}
+}
+
+func TestHub_NilIsNoop(t *testing.T) {
+	var h *Hub
+	h.PublishTo(context.Background(), uuid.New(), EventNotification, 1)
+	h.Broadcast(context.Background(), EventAnnouncement, 1)
+	sub, _, _ := h.Subscribe(uuid.New(), "")
+	if _, ok := <-sub.Events(); ok {
+		t.Error("Expected closed subscription from nil hub")
+	}
+	sub.Close()
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("Timed out waiting for condition")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
@@ -10,6 +10,11 @@ type LeaveRequestRepository = appattendance.LeaveRequestRepository
 type OvertimeRequestRepository = appattendance.OvertimeRequestRepository
 type LeaveBalanceRepository = appattendance.LeaveBalanceRepository
 type AttendanceCorrectionRepository = appattendance.AttendanceCorrectionRepository
+type SubstituteHolidayRepository = appattendance.SubstituteHolidayRepository
+type CompensatoryDayRepository = appattendance.CompensatoryDayRepository
+type WorkingHourPolicyRepository = appattendance.WorkingHourPolicyRepository
+type RemoteWorkPolicyRepository = appattendance.RemoteWorkPolicyRepository
+type TeamRepository = appattendance.TeamRepository
 
 func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
 	return appattendance.NewAttendanceRepository(db)
@@ -30,3 +35,23 @@ func NewLeaveBalanceRepository(db *gorm.DB) LeaveBalanceRepository {
 func NewAttendanceCorrectionRepository(db *gorm.DB) AttendanceCorrectionRepository {
 	return appattendance.NewAttendanceCorrectionRepository(db)
 }
+
+func NewSubstituteHolidayRepository(db *gorm.DB) SubstituteHolidayRepository {
+	return appattendance.NewSubstituteHolidayRepository(db)
+}
+
+func NewCompensatoryDayRepository(db *gorm.DB) CompensatoryDayRepository {
+	return appattendance.NewCompensatoryDayRepository(db)
+}
+
+func NewWorkingHourPolicyRepository(db *gorm.DB) WorkingHourPolicyRepository {
+	return appattendance.NewWorkingHourPolicyRepository(db)
+}
+
+func NewRemoteWorkPolicyRepository(db *gorm.DB) RemoteWorkPolicyRepository {
+	return appattendance.NewRemoteWorkPolicyRepository(db)
+}
+
+func NewTeamRepository(db *gorm.DB) TeamRepository {
+	return appattendance.NewTeamRepository(db)
+}
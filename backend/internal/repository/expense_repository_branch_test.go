@@ -210,3 +210,22 @@ func TestExpenseApprovalFlowRepositoryBranches(t *testing.T) {
 	})
 }
 
+
+func TestExpenseNotificationRepositoryScopesSharedNotifications(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+	repo := NewExpenseNotificationRepository(db)
+	ctx := context.Background()
+
+	// 経費の通知は共通の notifications から経費申請に紐づくもの・経費の種別に絞り込む
+	expenseTypes := len(model.NotificationTypesForModule("expense"))
+	expectQuery(mock, `(?i)SELECT .*FROM "notifications" WHERE \(expense_id IS NOT NULL OR type IN \(.*\)\) AND user_id = .*is_read = false`, expenseTypes+2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	_, err := repo.FindByUserID(ctx, uuid.New(), "unread")
+	require.NoError(t, err)
+
+	mock.ExpectExec(`(?i)UPDATE "notifications" SET .* WHERE \(expense_id IS NOT NULL OR type IN \(.*\)\) AND \(user_id = .* AND is_read = false\)`).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	require.NoError(t, repo.MarkAllAsRead(ctx, uuid.New()))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
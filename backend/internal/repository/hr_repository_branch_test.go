@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -18,12 +19,18 @@ func TestHRRepositoryFilterBranchesWithDryRun(t *testing.T) {
 	require.NoError(t, err)
 	_, _, err = empRepo.FindAll(ctx, 1, 10, "eng", "active", "full_time", "alice")
 	require.NoError(t, err)
+	_, err = empRepo.FindByManagerID(ctx, uuid.New())
+	require.NoError(t, err)
+	_, err = empRepo.FindIDsByUserDepartments(ctx, []uuid.UUID{uuid.New()})
+	require.NoError(t, err)
 
 	evalRepo := NewEvaluationRepository(db)
 	_, _, err = evalRepo.FindAll(ctx, 1, 10, "", "")
 	require.NoError(t, err)
 	_, _, err = evalRepo.FindAll(ctx, 1, 10, "cycle-1", "submitted")
 	require.NoError(t, err)
+	_, _, err = evalRepo.FindAllByEmployeeIDs(ctx, []uuid.UUID{uuid.New()}, 1, 10, "cycle-1", "")
+	require.NoError(t, err)
 
 	goalRepo := NewGoalRepository(db)
 	_, _, err = goalRepo.FindAll(ctx, 1, 10, "", "", "")
@@ -65,6 +72,8 @@ func TestHRRepositoryFilterBranchesWithDryRun(t *testing.T) {
 	require.NoError(t, err)
 	_, err = oneOnOneRepo.FindAll(ctx, "scheduled", "emp-1")
 	require.NoError(t, err)
+	_, err = oneOnOneRepo.FindAllByEmployeeIDs(ctx, []uuid.UUID{uuid.New()}, "scheduled", "")
+	require.NoError(t, err)
 
 	skillRepo := NewSkillRepository(db)
 	_, err = skillRepo.FindAll(ctx, "")
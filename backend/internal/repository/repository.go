@@ -2,11 +2,14 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/model"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repositories は全リポジトリを束ねる構造体
@@ -15,12 +18,31 @@ type Repositories struct {
 	Attendance           AttendanceRepository
 	LeaveRequest         LeaveRequestRepository
 	Shift                ShiftRepository
+	ShiftSchedule        ShiftScheduleRepository
 	Department           DepartmentRepository
 	RefreshToken         RefreshTokenRepository
+	UserToken            UserTokenRepository
+	UserIdentity         UserIdentityRepository
+	LoginHistory         LoginHistoryRepository
+	LoginLockout         LoginLockoutRepository
+	ServiceAccount       ServiceAccountRepository
+	APIToken             APITokenRepository
+	AuditLog             AuditLogRepository
+	MFA                  MFARepository
+	MFARecoveryCode      MFARecoveryCodeRepository
+	MFAPolicy            MFAPolicyRepository
+	Role                 RoleRepository
+	RoleAssignment       RoleAssignmentRepository
 	OvertimeRequest      OvertimeRequestRepository
 	LeaveBalance         LeaveBalanceRepository
 	AttendanceCorrection AttendanceCorrectionRepository
+	SubstituteHoliday    SubstituteHolidayRepository
+	CompensatoryDay      CompensatoryDayRepository
+	WorkingHourPolicy    WorkingHourPolicyRepository
+	RemoteWorkPolicy     RemoteWorkPolicyRepository
+	Team                 TeamRepository
 	Notification         NotificationRepository
+	NotificationPref     NotificationPreferenceRepository
 	Project              ProjectRepository
 	TimeEntry            TimeEntryRepository
 	Holiday              HolidayRepository
@@ -63,12 +85,31 @@ func NewRepositories(db *gorm.DB) *Repositories {
 		Attendance:           NewAttendanceRepository(db),
 		LeaveRequest:         NewLeaveRequestRepository(db),
 		Shift:                NewShiftRepository(db),
+		ShiftSchedule:        NewShiftScheduleRepository(db),
 		Department:           NewDepartmentRepository(db),
 		RefreshToken:         NewRefreshTokenRepository(db),
+		UserToken:            NewUserTokenRepository(db),
+		UserIdentity:         NewUserIdentityRepository(db),
+		LoginHistory:         NewLoginHistoryRepository(db),
+		LoginLockout:         NewLoginLockoutRepository(db),
+		ServiceAccount:       NewServiceAccountRepository(db),
+		APIToken:             NewAPITokenRepository(db),
+		AuditLog:             NewAuditLogRepository(db),
+		MFA:                  NewMFARepository(db),
+		MFARecoveryCode:      NewMFARecoveryCodeRepository(db),
+		MFAPolicy:            NewMFAPolicyRepository(db),
+		Role:                 NewRoleRepository(db),
+		RoleAssignment:       NewRoleAssignmentRepository(db),
 		OvertimeRequest:      NewOvertimeRequestRepository(db),
 		LeaveBalance:         NewLeaveBalanceRepository(db),
 		AttendanceCorrection: NewAttendanceCorrectionRepository(db),
+		SubstituteHoliday:    NewSubstituteHolidayRepository(db),
+		CompensatoryDay:      NewCompensatoryDayRepository(db),
+		WorkingHourPolicy:    NewWorkingHourPolicyRepository(db),
+		RemoteWorkPolicy:     NewRemoteWorkPolicyRepository(db),
+		Team:                 NewTeamRepository(db),
 		Notification:         NewNotificationRepository(db),
+		NotificationPref:     NewNotificationPreferenceRepository(db),
 		Project:              NewProjectRepository(db),
 		TimeEntry:            NewTimeEntryRepository(db),
 		Holiday:              NewHolidayRepository(db),
@@ -126,7 +167,16 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 }
 
 func (r *userRepository) Create(ctx context.Context, user *model.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		// is_active は列の既定値が true のため、ゼロ値の false は INSERT で省略される
+		if !user.IsActive {
+			return tx.Model(user).Update("is_active", false).Error
+		}
+		return nil
+	})
 }
 
 func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.User, error) {
@@ -181,8 +231,11 @@ func (r *userRepository) FindByDepartmentID(ctx context.Context, departmentID uu
 type ShiftRepository interface {
 	Create(ctx context.Context, shift *model.Shift) error
 	BulkCreate(ctx context.Context, shifts []model.Shift) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Shift, error)
 	FindByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.Shift, error)
 	FindByDateRange(ctx context.Context, start, end time.Time) ([]model.Shift, error)
+	FindByScheduleID(ctx context.Context, scheduleID uuid.UUID) ([]model.Shift, error)
+	AssignSchedule(ctx context.Context, scheduleID uuid.UUID, userIDs []uuid.UUID, start, end time.Time) error
 	Update(ctx context.Context, shift *model.Shift) error
 	Delete(ctx context.Context, id uuid.UUID) error
 }
@@ -203,9 +256,25 @@ func (r *shiftRepository) BulkCreate(ctx context.Context, shifts []model.Shift)
 	return r.db.WithContext(ctx).CreateInBatches(shifts, 100).Error
 }
 
+func (r *shiftRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Shift, error) {
+	var shift model.Shift
+	err := r.db.WithContext(ctx).First(&shift, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// publishedOnly は下書きシフト表に属するシフトを除外する
+func (r *shiftRepository) publishedOnly(db *gorm.DB) *gorm.DB {
+	published := r.db.Model(&model.ShiftSchedule{}).Select("id").Where("status = ?", model.ShiftScheduleStatusPublished)
+	return db.Where("schedule_id IS NULL OR schedule_id IN (?)", published)
+}
+
 func (r *shiftRepository) FindByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.Shift, error) {
 	var shifts []model.Shift
 	err := r.db.WithContext(ctx).
+		Scopes(r.publishedOnly).
 		Where("user_id = ? AND date BETWEEN ? AND ?", userID, start.Format("2006-01-02"), end.Format("2006-01-02")).
 		Order("date ASC").
 		Find(&shifts).Error
@@ -216,12 +285,32 @@ func (r *shiftRepository) FindByDateRange(ctx context.Context, start, end time.T
 	var shifts []model.Shift
 	err := r.db.WithContext(ctx).
 		Preload("User").
+		Scopes(r.publishedOnly).
 		Where("date BETWEEN ? AND ?", start.Format("2006-01-02"), end.Format("2006-01-02")).
 		Order("date ASC, user_id ASC").
 		Find(&shifts).Error
 	return shifts, err
 }
 
+func (r *shiftRepository) FindByScheduleID(ctx context.Context, scheduleID uuid.UUID) ([]model.Shift, error) {
+	var shifts []model.Shift
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("schedule_id = ?", scheduleID).
+		Order("date ASC, user_id ASC").
+		Find(&shifts).Error
+	return shifts, err
+}
+
+func (r *shiftRepository) AssignSchedule(ctx context.Context, scheduleID uuid.UUID, userIDs []uuid.UUID, start, end time.Time) error {
+	if len(userIDs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&model.Shift{}).
+		Where("user_id IN ? AND date BETWEEN ? AND ? AND schedule_id IS NULL", userIDs, start.Format("2006-01-02"), end.Format("2006-01-02")).
+		Update("schedule_id", scheduleID).Error
+}
+
 func (r *shiftRepository) Update(ctx context.Context, shift *model.Shift) error {
 	return r.db.WithContext(ctx).Save(shift).Error
 }
@@ -230,6 +319,99 @@ func (r *shiftRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&model.Shift{}, "id = ?", id).Error
 }
 
+// ===== ShiftScheduleRepository =====
+
+type ShiftScheduleRepository interface {
+	Create(ctx context.Context, schedule *model.ShiftSchedule) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.ShiftSchedule, error)
+	FindAll(ctx context.Context, departmentID *uuid.UUID) ([]model.ShiftSchedule, error)
+	FindByDepartmentAndDate(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.ShiftSchedule, error)
+	CountOverlapping(ctx context.Context, departmentID uuid.UUID, start, end time.Time) (int64, error)
+	Update(ctx context.Context, schedule *model.ShiftSchedule) error
+	// SaveVersion はシフト表の更新（公開・バージョン）とバージョンの記録を単一トランザクションで保存する
+	SaveVersion(ctx context.Context, schedule *model.ShiftSchedule, version *model.ShiftScheduleVersion) error
+	FindVersions(ctx context.Context, scheduleID uuid.UUID) ([]model.ShiftScheduleVersion, error)
+	FindVersion(ctx context.Context, scheduleID uuid.UUID, version int) (*model.ShiftScheduleVersion, error)
+}
+
+type shiftScheduleRepository struct{ db *gorm.DB }
+
+func NewShiftScheduleRepository(db *gorm.DB) ShiftScheduleRepository {
+	return &shiftScheduleRepository{db: db}
+}
+
+func (r *shiftScheduleRepository) Create(ctx context.Context, schedule *model.ShiftSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *shiftScheduleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ShiftSchedule, error) {
+	var schedule model.ShiftSchedule
+	err := r.db.WithContext(ctx).Preload("Department").First(&schedule, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *shiftScheduleRepository) FindAll(ctx context.Context, departmentID *uuid.UUID) ([]model.ShiftSchedule, error) {
+	var schedules []model.ShiftSchedule
+	query := r.db.WithContext(ctx).Preload("Department")
+	if departmentID != nil {
+		query = query.Where("department_id = ?", *departmentID)
+	}
+	err := query.Order("period_start DESC").Find(&schedules).Error
+	return schedules, err
+}
+
+func (r *shiftScheduleRepository) FindByDepartmentAndDate(ctx context.Context, departmentID uuid.UUID, date time.Time) (*model.ShiftSchedule, error) {
+	var schedule model.ShiftSchedule
+	d := date.Format("2006-01-02")
+	err := r.db.WithContext(ctx).
+		Where("department_id = ? AND period_start <= ? AND period_end >= ?", departmentID, d, d).
+		First(&schedule).Error
+	if err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *shiftScheduleRepository) CountOverlapping(ctx context.Context, departmentID uuid.UUID, start, end time.Time) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.ShiftSchedule{}).
+		Where("department_id = ? AND period_start <= ? AND period_end >= ?", departmentID, end.Format("2006-01-02"), start.Format("2006-01-02")).
+		Count(&count).Error
+	return count, err
+}
+
+// Update はシフト表のみを保存する（FindByID で読み込んだ部署は保存しない）
+func (r *shiftScheduleRepository) Update(ctx context.Context, schedule *model.ShiftSchedule) error {
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(schedule).Error
+}
+
+func (r *shiftScheduleRepository) SaveVersion(ctx context.Context, schedule *model.ShiftSchedule, version *model.ShiftScheduleVersion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(version).Error; err != nil {
+			return err
+		}
+		return tx.Omit(clause.Associations).Save(schedule).Error
+	})
+}
+
+func (r *shiftScheduleRepository) FindVersions(ctx context.Context, scheduleID uuid.UUID) ([]model.ShiftScheduleVersion, error) {
+	var versions []model.ShiftScheduleVersion
+	err := r.db.WithContext(ctx).Where("schedule_id = ?", scheduleID).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+func (r *shiftScheduleRepository) FindVersion(ctx context.Context, scheduleID uuid.UUID, version int) (*model.ShiftScheduleVersion, error) {
+	var v model.ShiftScheduleVersion
+	err := r.db.WithContext(ctx).Where("schedule_id = ? AND version = ?", scheduleID, version).First(&v).Error
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
 // ===== DepartmentRepository =====
 
 type DepartmentRepository interface {
@@ -279,10 +461,12 @@ func (r *departmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *model.RefreshToken) error
-	FindByToken(ctx context.Context, token string) (*model.RefreshToken, error)
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error)
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
 	RevokeByUserID(ctx context.Context, userID uuid.UUID) error
-	Revoke(ctx context.Context, token string) error
-	DeleteExpired(ctx context.Context) error
+	FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.RefreshToken, error)
+	DeleteExpired(ctx context.Context) (int64, error)
 }
 
 type refreshTokenRepository struct {
@@ -297,31 +481,682 @@ func (r *refreshTokenRepository) Create(ctx context.Context, token *model.Refres
 	return r.db.WithContext(ctx).Create(token).Error
 }
 
-func (r *refreshTokenRepository) FindByToken(ctx context.Context, token string) (*model.RefreshToken, error) {
+// FindByTokenHash は失効・使用済みのトークンも含めて検索する（再利用の検知に使う）
+func (r *refreshTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.RefreshToken, error) {
 	var rt model.RefreshToken
-	err := r.db.WithContext(ctx).Where("token = ? AND is_revoked = false AND expires_at > ?", token, time.Now()).First(&rt).Error
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&rt).Error
 	if err != nil {
 		return nil, err
 	}
 	return &rt, nil
 }
 
-func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
+// MarkUsed は未使用かつ有効なトークンを使用済みにする。
+// 同時に使用された場合は一方のみ true を返す
+func (r *refreshTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.RefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND is_revoked = false", id).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *refreshTokenRepository) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
 	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
-		Where("user_id = ?", userID).
+		Where("family_id = ?", familyID).
 		Update("is_revoked", true).Error
 }
 
-func (r *refreshTokenRepository) Revoke(ctx context.Context, token string) error {
+func (r *refreshTokenRepository) RevokeByUserID(ctx context.Context, userID uuid.UUID) error {
 	return r.db.WithContext(ctx).Model(&model.RefreshToken{}).
-		Where("token = ?", token).
+		Where("user_id = ?", userID).
 		Update("is_revoked", true).Error
 }
 
-func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) error {
-	return r.db.WithContext(ctx).
-		Where("expires_at < ? OR is_revoked = true", time.Now()).
-		Delete(&model.RefreshToken{}).Error
+// FindActiveByUserID は未使用・未失効・期限内のトークン（セッションごとに最新の1件）を新しい順に返す
+func (r *refreshTokenRepository) FindActiveByUserID(ctx context.Context, userID uuid.UUID) ([]model.RefreshToken, error) {
+	var tokens []model.RefreshToken
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND used_at IS NULL AND is_revoked = false AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC").
+		Find(&tokens).Error
+	return tokens, err
+}
+
+// DeleteExpired は期限切れのトークンを削除する。
+// 使用済み・失効済みでも期限内のものは再利用の検知のため残す
+func (r *refreshTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("expires_at < ?", time.Now()).
+		Delete(&model.RefreshToken{})
+	return result.RowsAffected, result.Error
+}
+
+// ===== UserTokenRepository =====
+
+type UserTokenRepository interface {
+	Create(ctx context.Context, token *model.UserToken) error
+	FindByTokenHash(ctx context.Context, purpose model.UserTokenPurpose, tokenHash string) (*model.UserToken, error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error)
+	InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose model.UserTokenPurpose) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+type userTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepository(db *gorm.DB) UserTokenRepository {
+	return &userTokenRepository{db: db}
+}
+
+func (r *userTokenRepository) Create(ctx context.Context, token *model.UserToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *userTokenRepository) FindByTokenHash(ctx context.Context, purpose model.UserTokenPurpose, tokenHash string) (*model.UserToken, error) {
+	var t model.UserToken
+	err := r.db.WithContext(ctx).Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&t).Error
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// MarkUsed は未使用のトークンを使用済みにする。
+// 同時に使用された場合は一方のみ true を返す
+func (r *userTokenRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserToken{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+// InvalidateByUser はユーザーの未使用トークンを使用済みにし、以前に送ったリンクを無効にする
+func (r *userTokenRepository) InvalidateByUser(ctx context.Context, userID uuid.UUID, purpose model.UserTokenPurpose) error {
+	return r.db.WithContext(ctx).Model(&model.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+func (r *userTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().
+		Where("expires_at < ?", time.Now()).
+		Delete(&model.UserToken{})
+	return result.RowsAffected, result.Error
+}
+
+// ===== UserIdentityRepository =====
+
+type UserIdentityRepository interface {
+	Create(ctx context.Context, identity *model.UserIdentity) error
+	FindBySubject(ctx context.Context, issuer, subject string) (*model.UserIdentity, error)
+	UpdateLastLogin(ctx context.Context, id uuid.UUID, email string, at time.Time) error
+}
+
+type userIdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewUserIdentityRepository(db *gorm.DB) UserIdentityRepository {
+	return &userIdentityRepository{db: db}
+}
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *model.UserIdentity) error {
+	return r.db.WithContext(ctx).Create(identity).Error
+}
+
+// FindBySubject は未登録の場合 nil, nil を返す
+func (r *userIdentityRepository) FindBySubject(ctx context.Context, issuer, subject string) (*model.UserIdentity, error) {
+	var identity model.UserIdentity
+	err := r.db.WithContext(ctx).Where("issuer = ? AND subject = ?", issuer, subject).First(&identity).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &identity, nil
+}
+
+// UpdateLastLogin はログイン日時とIdP側の現在のメールアドレスを記録する
+func (r *userIdentityRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID, email string, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.UserIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"email": email, "last_login_at": at}).Error
+}
+
+// ===== LoginHistoryRepository =====
+
+type LoginHistoryRepository interface {
+	Create(ctx context.Context, history *model.LoginHistory) error
+	FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error)
+	// HasSuccess はログインに成功した記録があるかを返す。userAgent が空でない場合はその端末に限る
+	HasSuccess(ctx context.Context, userID uuid.UUID, userAgent string) (bool, error)
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+type loginHistoryRepository struct{ db *gorm.DB }
+
+func NewLoginHistoryRepository(db *gorm.DB) LoginHistoryRepository {
+	return &loginHistoryRepository{db: db}
+}
+
+func (r *loginHistoryRepository) Create(ctx context.Context, history *model.LoginHistory) error {
+	return r.db.WithContext(ctx).Create(history).Error
+}
+
+func (r *loginHistoryRepository) FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.LoginHistory, int64, error) {
+	var histories []model.LoginHistory
+	var total int64
+	q := r.db.WithContext(ctx).Model(&model.LoginHistory{}).Where("user_id = ?", userID)
+	if err := q.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := q.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&histories).Error
+	return histories, total, err
+}
+
+func (r *loginHistoryRepository) HasSuccess(ctx context.Context, userID uuid.UUID, userAgent string) (bool, error) {
+	q := r.db.WithContext(ctx).Model(&model.LoginHistory{}).Where("user_id = ? AND success = ?", userID, true)
+	if userAgent != "" {
+		q = q.Where("user_agent = ?", userAgent)
+	}
+	var count int64
+	err := q.Limit(1).Count(&count).Error
+	return count > 0, err
+}
+
+// DeleteBefore は保存期間を過ぎた履歴を物理削除する
+func (r *loginHistoryRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Unscoped().Where("created_at < ?", before).Delete(&model.LoginHistory{})
+	return result.RowsAffected, result.Error
+}
+
+// ===== LoginLockoutRepository =====
+
+type LoginLockoutRepository interface {
+	// FindByUserID は失敗の記録がない場合 nil, nil を返す
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.LoginLockout, error)
+	// RecordFailure は連続失敗回数を1増やし、更新後の状態を返す
+	RecordFailure(ctx context.Context, userID uuid.UUID, at time.Time) (*model.LoginLockout, error)
+	// Lock は連続失敗回数を0に戻してロックの回数を1増やし、until までロックする
+	Lock(ctx context.Context, userID uuid.UUID, until time.Time) error
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type loginLockoutRepository struct{ db *gorm.DB }
+
+func NewLoginLockoutRepository(db *gorm.DB) LoginLockoutRepository {
+	return &loginLockoutRepository{db: db}
+}
+
+func (r *loginLockoutRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.LoginLockout, error) {
+	var lockout model.LoginLockout
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&lockout).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+// RecordFailure は同時に失敗しても回数を取りこぼさないよう UPSERT で加算する
+func (r *loginLockoutRepository) RecordFailure(ctx context.Context, userID uuid.UUID, at time.Time) (*model.LoginLockout, error) {
+	lockout := model.LoginLockout{UserID: userID, FailedCount: 1, LastFailedAt: &at}
+	err := r.db.WithContext(ctx).Clauses(
+		clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"failed_count":   gorm.Expr("login_lockouts.failed_count + 1"),
+				"last_failed_at": at,
+				"updated_at":     at,
+			}),
+		},
+		clause.Returning{},
+	).Create(&lockout).Error
+	if err != nil {
+		return nil, err
+	}
+	return &lockout, nil
+}
+
+func (r *loginLockoutRepository) Lock(ctx context.Context, userID uuid.UUID, until time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.LoginLockout{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"failed_count":  0,
+			"lockout_count": gorm.Expr("lockout_count + 1"),
+			"locked_until":  until,
+		}).Error
+}
+
+func (r *loginLockoutRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.LoginLockout{}).Error
+}
+
+// ===== ServiceAccountRepository =====
+
+type ServiceAccountRepository interface {
+	Create(ctx context.Context, account *model.ServiceAccount) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.ServiceAccount, error)
+	FindByName(ctx context.Context, name string) (*model.ServiceAccount, error)
+	FindAll(ctx context.Context) ([]model.ServiceAccount, error)
+	Update(ctx context.Context, account *model.ServiceAccount) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type serviceAccountRepository struct{ db *gorm.DB }
+
+func NewServiceAccountRepository(db *gorm.DB) ServiceAccountRepository {
+	return &serviceAccountRepository{db: db}
+}
+
+func (r *serviceAccountRepository) Create(ctx context.Context, account *model.ServiceAccount) error {
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+func (r *serviceAccountRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.ServiceAccount, error) {
+	var account model.ServiceAccount
+	err := r.db.WithContext(ctx).First(&account, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) FindByName(ctx context.Context, name string) (*model.ServiceAccount, error) {
+	var account model.ServiceAccount
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+func (r *serviceAccountRepository) FindAll(ctx context.Context) ([]model.ServiceAccount, error) {
+	var accounts []model.ServiceAccount
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *serviceAccountRepository) Update(ctx context.Context, account *model.ServiceAccount) error {
+	return r.db.WithContext(ctx).Save(account).Error
+}
+
+func (r *serviceAccountRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.ServiceAccount{}, "id = ?", id).Error
+}
+
+// ===== APITokenRepository =====
+
+type APITokenRepository interface {
+	Create(ctx context.Context, token *model.APIToken) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.APIToken, error)
+	// FindByTokenHash は失効・期限切れのトークンも含めて検索する
+	FindByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error)
+	FindByServiceAccountID(ctx context.Context, accountID uuid.UUID) ([]model.APIToken, error)
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time, ip string) error
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+	RevokeByServiceAccountID(ctx context.Context, accountID uuid.UUID, at time.Time) error
+}
+
+type apiTokenRepository struct{ db *gorm.DB }
+
+func NewAPITokenRepository(db *gorm.DB) APITokenRepository {
+	return &apiTokenRepository{db: db}
+}
+
+func (r *apiTokenRepository) Create(ctx context.Context, token *model.APIToken) error {
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *apiTokenRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.APIToken, error) {
+	var token model.APIToken
+	err := r.db.WithContext(ctx).First(&token, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*model.APIToken, error) {
+	var token model.APIToken
+	err := r.db.WithContext(ctx).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+func (r *apiTokenRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *apiTokenRepository) FindByServiceAccountID(ctx context.Context, accountID uuid.UUID) ([]model.APIToken, error) {
+	var tokens []model.APIToken
+	err := r.db.WithContext(ctx).Where("service_account_id = ?", accountID).Order("created_at DESC").Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *apiTokenRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, at time.Time, ip string) error {
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": at, "last_used_ip": ip}).Error
+}
+
+func (r *apiTokenRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+}
+
+func (r *apiTokenRepository) RevokeByServiceAccountID(ctx context.Context, accountID uuid.UUID, at time.Time) error {
+	return r.db.WithContext(ctx).Model(&model.APIToken{}).
+		Where("service_account_id = ? AND revoked_at IS NULL", accountID).
+		Update("revoked_at", at).Error
+}
+
+// ===== AuditLogRepository =====
+
+// AuditLogRepository は監査ログの参照のみを扱う（記録は audit.Recorder が行う）
+type AuditLogRepository interface {
+	FindAll(ctx context.Context, q *model.AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error)
+	// FindAfterSeq は afterSeq より後の記録を Seq の昇順で返す（ハッシュチェーンの検証用）
+	FindAfterSeq(ctx context.Context, afterSeq int64, limit int) ([]model.AuditLog, error)
+}
+
+type auditLogRepository struct{ db *gorm.DB }
+
+func NewAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &auditLogRepository{db: db}
+}
+
+func (r *auditLogRepository) FindAll(ctx context.Context, q *model.AuditLogQuery, page, pageSize int) ([]model.AuditLog, int64, error) {
+	var logs []model.AuditLog
+	var total int64
+	query := r.db.WithContext(ctx).Model(&model.AuditLog{})
+	if q.ActorID != "" {
+		query = query.Where("actor_id = ?", q.ActorID)
+	}
+	if q.Action != "" {
+		query = query.Where("action = ?", q.Action)
+	}
+	if q.EntityType != "" {
+		query = query.Where("entity_type = ?", q.EntityType)
+	}
+	if q.EntityID != "" {
+		query = query.Where("entity_id = ?", q.EntityID)
+	}
+	if q.RequestID != "" {
+		query = query.Where("request_id = ?", q.RequestID)
+	}
+	if q.StartDate != nil {
+		query = query.Where("occurred_at >= ?", *q.StartDate)
+	}
+	if q.EndDate != nil {
+		query = query.Where("occurred_at < ?", q.EndDate.AddDate(0, 0, 1))
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	offset := (page - 1) * pageSize
+	err := query.Order("seq DESC").Offset(offset).Limit(pageSize).Find(&logs).Error
+	return logs, total, err
+}
+
+func (r *auditLogRepository) FindAfterSeq(ctx context.Context, afterSeq int64, limit int) ([]model.AuditLog, error) {
+	var logs []model.AuditLog
+	err := r.db.WithContext(ctx).Where("seq > ?", afterSeq).Order("seq ASC").Limit(limit).Find(&logs).Error
+	return logs, err
+}
+
+// ===== MFARepository =====
+
+type MFARepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) (*model.UserMFA, error)
+	Save(ctx context.Context, mfa *model.UserMFA) error
+	AdvanceStep(ctx context.Context, id uuid.UUID, step int64) (bool, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type mfaRepository struct{ db *gorm.DB }
+
+func NewMFARepository(db *gorm.DB) MFARepository {
+	return &mfaRepository{db: db}
+}
+
+// FindByUserID は未登録の場合 nil を返す（エラーは取得の失敗のみ）
+func (r *mfaRepository) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.UserMFA, error) {
+	var mfa model.UserMFA
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&mfa).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *mfaRepository) Save(ctx context.Context, mfa *model.UserMFA) error {
+	return r.db.WithContext(ctx).Save(mfa).Error
+}
+
+// AdvanceStep は受け付けたタイムステップを記録する。
+// 既に同じか新しいステップを受け付けている場合（コードの再利用）は false を返す
+func (r *mfaRepository) AdvanceStep(ctx context.Context, id uuid.UUID, step int64) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.UserMFA{}).
+		Where("id = ? AND last_used_step < ?", id, step).
+		Update("last_used_step", step)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.UserMFA{}).Error
+}
+
+// ===== MFARecoveryCodeRepository =====
+
+type MFARecoveryCodeRepository interface {
+	ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []model.MFARecoveryCode) error
+	FindUnused(ctx context.Context, userID uuid.UUID, codeHash string) (*model.MFARecoveryCode, error)
+	MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error)
+	CountUnused(ctx context.Context, userID uuid.UUID) (int64, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+type mfaRecoveryCodeRepository struct{ db *gorm.DB }
+
+func NewMFARecoveryCodeRepository(db *gorm.DB) MFARecoveryCodeRepository {
+	return &mfaRecoveryCodeRepository{db: db}
+}
+
+// ReplaceForUser は既存のコードを削除して新しいコードを保存する
+func (r *mfaRecoveryCodeRepository) ReplaceForUser(ctx context.Context, userID uuid.UUID, codes []model.MFARecoveryCode) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+func (r *mfaRecoveryCodeRepository) FindUnused(ctx context.Context, userID uuid.UUID, codeHash string) (*model.MFARecoveryCode, error) {
+	var code model.MFARecoveryCode
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		First(&code).Error
+	if err != nil {
+		return nil, err
+	}
+	return &code, nil
+}
+
+// MarkUsed は未使用のコードを使用済みにする。同時に使用された場合は一方のみ true を返す
+func (r *mfaRecoveryCodeRepository) MarkUsed(ctx context.Context, id uuid.UUID, usedAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&model.MFARecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", usedAt)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *mfaRecoveryCodeRepository) CountUnused(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *mfaRecoveryCodeRepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Unscoped().Where("user_id = ?", userID).Delete(&model.MFARecoveryCode{}).Error
+}
+
+// ===== MFAPolicyRepository =====
+
+type MFAPolicyRepository interface {
+	Get(ctx context.Context) (*model.MFAPolicy, error)
+	Save(ctx context.Context, policy *model.MFAPolicy) error
+}
+
+type mfaPolicyRepository struct{ db *gorm.DB }
+
+func NewMFAPolicyRepository(db *gorm.DB) MFAPolicyRepository {
+	return &mfaPolicyRepository{db: db}
+}
+
+// Get は全社のポリシーを返す。未設定の場合は何も必須にしないポリシーを返す
+func (r *mfaPolicyRepository) Get(ctx context.Context) (*model.MFAPolicy, error) {
+	var policy model.MFAPolicy
+	err := r.db.WithContext(ctx).Order("created_at").First(&policy).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.MFAPolicy{RequiredRoles: datatypes.JSON("[]"), RequiredCustomRoleIDs: datatypes.JSON("[]")}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *mfaPolicyRepository) Save(ctx context.Context, policy *model.MFAPolicy) error {
+	return r.db.WithContext(ctx).Save(policy).Error
+}
+
+// ===== RoleRepository =====
+
+type RoleRepository interface {
+	Create(ctx context.Context, role *model.CustomRole) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.CustomRole, error)
+	FindByName(ctx context.Context, name string) (*model.CustomRole, error)
+	FindAll(ctx context.Context) ([]model.CustomRole, error)
+	Update(ctx context.Context, role *model.CustomRole) error
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type roleRepository struct{ db *gorm.DB }
+
+func NewRoleRepository(db *gorm.DB) RoleRepository {
+	return &roleRepository{db: db}
+}
+
+func (r *roleRepository) Create(ctx context.Context, role *model.CustomRole) error {
+	return r.db.WithContext(ctx).Create(role).Error
+}
+
+func (r *roleRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.CustomRole, error) {
+	var role model.CustomRole
+	err := r.db.WithContext(ctx).First(&role, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindByName(ctx context.Context, name string) (*model.CustomRole, error) {
+	var role model.CustomRole
+	err := r.db.WithContext(ctx).Where("name = ?", name).First(&role).Error
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (r *roleRepository) FindAll(ctx context.Context) ([]model.CustomRole, error) {
+	var roles []model.CustomRole
+	err := r.db.WithContext(ctx).Order("name ASC").Find(&roles).Error
+	return roles, err
+}
+
+func (r *roleRepository) Update(ctx context.Context, role *model.CustomRole) error {
+	return r.db.WithContext(ctx).Save(role).Error
+}
+
+func (r *roleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.CustomRole{}, "id = ?", id).Error
+}
+
+// ===== RoleAssignmentRepository =====
+
+type RoleAssignmentRepository interface {
+	Create(ctx context.Context, a *model.UserRoleAssignment) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.UserRoleAssignment, error)
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserRoleAssignment, error)
+	FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]model.UserRoleAssignment, error)
+	CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+type roleAssignmentRepository struct{ db *gorm.DB }
+
+func NewRoleAssignmentRepository(db *gorm.DB) RoleAssignmentRepository {
+	return &roleAssignmentRepository{db: db}
+}
+
+func (r *roleAssignmentRepository) Create(ctx context.Context, a *model.UserRoleAssignment) error {
+	return r.db.WithContext(ctx).Create(a).Error
+}
+
+func (r *roleAssignmentRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.UserRoleAssignment, error) {
+	var a model.UserRoleAssignment
+	err := r.db.WithContext(ctx).Preload("Role").Preload("Department").First(&a, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+func (r *roleAssignmentRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.UserRoleAssignment, error) {
+	var assignments []model.UserRoleAssignment
+	err := r.db.WithContext(ctx).Preload("Role").Preload("Department").
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *roleAssignmentRepository) FindByRoleID(ctx context.Context, roleID uuid.UUID) ([]model.UserRoleAssignment, error) {
+	var assignments []model.UserRoleAssignment
+	err := r.db.WithContext(ctx).Where("role_id = ?", roleID).Find(&assignments).Error
+	return assignments, err
+}
+
+func (r *roleAssignmentRepository) CountByRoleID(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&model.UserRoleAssignment{}).Where("role_id = ?", roleID).Count(&count).Error
+	return count, err
+}
+
+func (r *roleAssignmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&model.UserRoleAssignment{}, "id = ?", id).Error
 }
 
 // ===== NotificationRepository =====
@@ -380,6 +1215,55 @@ func (r *notificationRepository) Delete(ctx context.Context, id uuid.UUID) error
 	return r.db.WithContext(ctx).Delete(&model.Notification{}, "id = ?", id).Error
 }
 
+// ===== NotificationPreferenceRepository =====
+
+// NotificationPreferenceRepository は通知の受信設定とおやすみ時間帯を扱う
+type NotificationPreferenceRepository interface {
+	FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error)
+	Upsert(ctx context.Context, prefs []model.NotificationPreference) error
+	// FindSetting は設定がない場合 nil を返す
+	FindSetting(ctx context.Context, userID uuid.UUID) (*model.NotificationSetting, error)
+	SaveSetting(ctx context.Context, setting *model.NotificationSetting) error
+}
+
+type notificationPreferenceRepository struct{ db *gorm.DB }
+
+func NewNotificationPreferenceRepository(db *gorm.DB) NotificationPreferenceRepository {
+	return &notificationPreferenceRepository{db: db}
+}
+
+func (r *notificationPreferenceRepository) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.NotificationPreference, error) {
+	var prefs []model.NotificationPreference
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Find(&prefs).Error
+	return prefs, err
+}
+
+func (r *notificationPreferenceRepository) Upsert(ctx context.Context, prefs []model.NotificationPreference) error {
+	if len(prefs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}, {Name: "channel"}},
+		DoUpdates: clause.AssignmentColumns([]string{"enabled", "updated_at"}),
+	}).Create(&prefs).Error
+}
+
+func (r *notificationPreferenceRepository) FindSetting(ctx context.Context, userID uuid.UUID) (*model.NotificationSetting, error) {
+	var setting model.NotificationSetting
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+func (r *notificationPreferenceRepository) SaveSetting(ctx context.Context, setting *model.NotificationSetting) error {
+	return r.db.WithContext(ctx).Save(setting).Error
+}
+
 // ===== ProjectRepository =====
 
 type ProjectRepository interface {
@@ -121,18 +121,18 @@ func TestRepositoryCoreErrBranches(t *testing.T) {
 		require.NoError(t, err)
 	})
 
-	t.Run("refresh token find by token", func(t *testing.T) {
+	t.Run("refresh token find by token hash", func(t *testing.T) {
 		db, mock, cleanup := newMockDB(t)
 		defer cleanup()
 		repo := NewRefreshTokenRepository(db)
 
-		expectQuery(mock, `(?i)SELECT .*FROM "refresh_tokens"`, 3).WillReturnError(errors.New("boom"))
-		_, err := repo.FindByToken(ctx, "token")
+		expectQuery(mock, `(?i)SELECT .*FROM "refresh_tokens"`, 2).WillReturnError(errors.New("boom"))
+		_, err := repo.FindByTokenHash(ctx, "hash")
 		require.Error(t, err)
 
-		expectQuery(mock, `(?i)SELECT .*FROM "refresh_tokens"`, 3).
-			WillReturnRows(sqlmock.NewRows([]string{"token"}).AddRow("token"))
-		_, err = repo.FindByToken(ctx, "token")
+		expectQuery(mock, `(?i)SELECT .*FROM "refresh_tokens"`, 2).
+			WillReturnRows(sqlmock.NewRows([]string{"token_hash"}).AddRow("hash"))
+		_, err = repo.FindByTokenHash(ctx, "hash")
 		require.NoError(t, err)
 	})
 
@@ -282,3 +282,56 @@ func TestRepositoryFilterBranchesWithDryRun(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestAttendanceRepository_WithAutoCloseLock(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+	repo := NewAttendanceRepository(db)
+	ctx := context.Background()
+
+	// ロックを取得できた場合のみ処理を実行する
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT pg_try_advisory_xact_lock`).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(true))
+	mock.ExpectCommit()
+	ran := false
+	acquired, err := repo.WithAutoCloseLock(ctx, func() error { ran = true; return nil })
+	require.NoError(t, err)
+	require.True(t, acquired)
+	require.True(t, ran)
+
+	// 他のインスタンスがロックを保持している場合は処理しない
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)SELECT pg_try_advisory_xact_lock`).WillReturnRows(sqlmock.NewRows([]string{"pg_try_advisory_xact_lock"}).AddRow(false))
+	mock.ExpectCommit()
+	ran = false
+	acquired, err = repo.WithAutoCloseLock(ctx, func() error { ran = true; return nil })
+	require.NoError(t, err)
+	require.False(t, acquired)
+	require.False(t, ran)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestShiftScheduleRepository_SaveVersion(t *testing.T) {
+	db, mock, cleanup := newMockDB(t)
+	defer cleanup()
+	repo := NewShiftScheduleRepository(db)
+	ctx := context.Background()
+	schedule := &model.ShiftSchedule{
+		BaseModel: model.BaseModel{ID: uuid.New()}, DepartmentID: uuid.New(), Status: model.ShiftScheduleStatusPublished, Version: 1,
+		Department: &model.Department{BaseModel: model.BaseModel{ID: uuid.New()}, Name: "開発部"},
+	}
+	version := &model.ShiftScheduleVersion{ScheduleID: schedule.ID, Version: 1}
+
+	// バージョンの記録とシフト表の公開を同じトランザクションで保存し、読み込んだ部署は保存しない
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)INSERT INTO "shift_schedule_versions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectExec(`(?i)UPDATE "shift_schedules" SET`).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	require.NoError(t, repo.SaveVersion(ctx, schedule, version))
+
+	// シフト表を保存できない場合はバージョンの記録も取り消す
+	mock.ExpectBegin()
+	mock.ExpectQuery(`(?i)INSERT INTO "shift_schedule_versions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(uuid.New()))
+	mock.ExpectExec(`(?i)UPDATE "shift_schedules" SET`).WillReturnError(errors.New("conflict"))
+	mock.ExpectRollback()
+	require.Error(t, repo.SaveVersion(ctx, schedule, &model.ShiftScheduleVersion{ScheduleID: schedule.ID, Version: 2}))
+}
@@ -30,11 +30,14 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	_, _ = attendanceRepo.GetSummary(ctx, id, now.AddDate(0, 0, -30), now)
 	_, _ = attendanceRepo.CountTodayPresent(ctx)
 	_, _ = attendanceRepo.GetMonthlyOvertime(ctx, now.AddDate(0, -1, 0), now)
+	_, _ = attendanceRepo.CountWorkModesByUser(ctx, now.AddDate(0, -1, 0), now)
+	_, _ = attendanceRepo.FindOpenBefore(ctx, now)
 
 	leaveRepo := NewLeaveRequestRepository(db)
 	_ = leaveRepo.Create(ctx, &model.LeaveRequest{})
 	_, _, _ = leaveRepo.FindByUserID(ctx, id, 1, 10)
 	_, _, _ = leaveRepo.FindPending(ctx, 1, 10)
+	_, _, _ = leaveRepo.FindPendingByUserIDs(ctx, []uuid.UUID{id}, 1, 10)
 	_ = leaveRepo.Update(ctx, &model.LeaveRequest{})
 	_, _ = leaveRepo.CountPending(ctx)
 
@@ -55,13 +58,16 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	rtRepo := NewRefreshTokenRepository(db)
 	_ = rtRepo.Create(ctx, &model.RefreshToken{})
 	_ = rtRepo.RevokeByUserID(ctx, id)
-	_ = rtRepo.Revoke(ctx, "token")
-	_ = rtRepo.DeleteExpired(ctx)
+	_, _ = rtRepo.FindByTokenHash(ctx, "hash")
+	_, _ = rtRepo.MarkUsed(ctx, id, now)
+	_ = rtRepo.RevokeFamily(ctx, id)
+	_, _ = rtRepo.DeleteExpired(ctx)
 
 	otRepo := NewOvertimeRequestRepository(db)
 	_ = otRepo.Create(ctx, &model.OvertimeRequest{})
 	_, _, _ = otRepo.FindByUserID(ctx, id, 1, 10)
 	_, _, _ = otRepo.FindPending(ctx, 1, 10)
+	_, _, _ = otRepo.FindPendingByUserIDs(ctx, []uuid.UUID{id}, 1, 10)
 	_ = otRepo.Update(ctx, &model.OvertimeRequest{})
 	_, _ = otRepo.CountPending(ctx)
 	_, _ = otRepo.GetUserMonthlyOvertime(ctx, id, now.Year(), int(now.Month()))
@@ -77,9 +83,48 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	_ = corrRepo.Create(ctx, &model.AttendanceCorrection{})
 	_, _, _ = corrRepo.FindByUserID(ctx, id, 1, 10)
 	_, _, _ = corrRepo.FindPending(ctx, 1, 10)
+	_, _, _ = corrRepo.FindPendingByUserIDs(ctx, []uuid.UUID{id}, 1, 10)
 	_ = corrRepo.Update(ctx, &model.AttendanceCorrection{})
+	_ = corrRepo.ApplyApproval(ctx, &model.AttendanceCorrection{}, &model.Attendance{})
+	_ = corrRepo.ApplyApproval(ctx, &model.AttendanceCorrection{}, &model.Attendance{BaseModel: model.BaseModel{ID: id}})
 	_, _ = corrRepo.CountPending(ctx)
 
+	shRepo := NewSubstituteHolidayRepository(db)
+	_ = shRepo.Create(ctx, &model.SubstituteHolidayRequest{})
+	_, _ = shRepo.FindByID(ctx, id)
+	_, _, _ = shRepo.FindByUserID(ctx, id, 1, 10)
+	_, _, _ = shRepo.FindPending(ctx, 1, 10)
+	_, _, _ = shRepo.FindPendingByUserIDs(ctx, []uuid.UUID{id}, 1, 10)
+	_, _ = shRepo.FindApprovedByUserAndDateRange(ctx, id, now, now)
+	_, _ = shRepo.CountPendingDaikyu(ctx, id)
+	_ = shRepo.Update(ctx, &model.SubstituteHolidayRequest{})
+	_ = shRepo.ApplyApproval(ctx, &model.SubstituteHolidayRequest{}, &model.CompensatoryDay{})
+
+	cdRepo := NewCompensatoryDayRepository(db)
+	_ = cdRepo.Create(ctx, &model.CompensatoryDay{})
+	_, _ = cdRepo.FindByUserID(ctx, id)
+	_, _ = cdRepo.FindByUserAndEarnedDate(ctx, id, now)
+	_, _ = cdRepo.FindAvailable(ctx, id, now)
+
+	whRepo := NewWorkingHourPolicyRepository(db)
+	_ = whRepo.Create(ctx, &model.WorkingHourPolicy{})
+	_, _ = whRepo.FindAll(ctx)
+	_, _ = whRepo.FindByDepartmentID(ctx, id)
+	_ = whRepo.Update(ctx, &model.WorkingHourPolicy{})
+	_, _ = whRepo.FindScheduleDays(ctx, id, now, now)
+	_ = whRepo.ReplaceScheduleDays(ctx, id, now, now, nil)
+	_ = whRepo.ReplaceScheduleDays(ctx, id, now, now, []model.WorkingHourScheduleDay{{PolicyID: id, Date: now}})
+
+	rwRepo := NewRemoteWorkPolicyRepository(db)
+	_ = rwRepo.Create(ctx, &model.RemoteWorkPolicy{})
+	_, _ = rwRepo.FindAll(ctx)
+	_, _ = rwRepo.FindByDepartmentID(ctx, id)
+	_ = rwRepo.Update(ctx, &model.RemoteWorkPolicy{})
+
+	teamRepo := NewTeamRepository(db)
+	_, _ = teamRepo.FindDirectReportIDs(ctx, id)
+	_, _ = teamRepo.FindManagerIDs(ctx, id)
+
 	notifRepo := NewNotificationRepository(db)
 	_ = notifRepo.Create(ctx, &model.Notification{})
 	_ = notifRepo.MarkAsRead(ctx, id)
@@ -121,6 +166,7 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	_ = hrEmp.Update(ctx, &model.HREmployee{})
 	_ = hrEmp.Delete(ctx, id)
 	_, _ = hrEmp.FindByDepartmentID(ctx, id)
+	_, _ = hrEmp.FindByUserID(ctx, id)
 	_, _, _ = hrEmp.CountByStatus(ctx)
 
 	hrDept := NewHRDepartmentRepository(db)
@@ -196,6 +242,7 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	expenseRepo := NewExpenseRepository(db)
 	_ = expenseRepo.Create(ctx, &model.Expense{})
 	_, _, _ = expenseRepo.FindPending(ctx, 1, 10)
+	_, _, _ = expenseRepo.FindPendingByUserIDs(ctx, []uuid.UUID{id}, 1, 10)
 	_ = expenseRepo.Update(ctx, &model.Expense{})
 	_ = expenseRepo.Delete(ctx, id)
 	_, _ = expenseRepo.GetStats(ctx, id)
@@ -228,7 +275,7 @@ func TestRepositorySmoke_CoverMoreStatements(t *testing.T) {
 	_, _ = expenseBudgetRepo.FindAll(ctx)
 
 	expenseNotifRepo := NewExpenseNotificationRepository(db)
-	_ = expenseNotifRepo.Create(ctx, &model.ExpenseNotification{})
+	_ = expenseNotifRepo.Create(ctx, &model.Notification{})
 	_ = expenseNotifRepo.MarkAsRead(ctx, id)
 	_ = expenseNotifRepo.MarkAllAsRead(ctx, id)
 
@@ -0,0 +1,167 @@
+package revocation
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+const (
+	tokenKeyPrefix   = "revoked:jti:"
+	userKeyPrefix    = "revoked:user:"
+	sessionKeyPrefix = "revoked:session:"
+	consumedPrefix   = "consumed:jti:"
+)
+
+// DenyList は失効したアクセストークンを有効期限まで保持する。
+// トークン単位（jti）、セッション単位（sid）とユーザー単位（指定時刻以前に発行された全トークン）の失効を扱う。
+//
+// 書き込みは常にインメモリにも行い、読み込みはインメモリ→共有ストアの順に確認する。
+// 共有ストア（Redis）の障害時もこのプロセスで失効させたトークンは拒否され続ける。
+// nil の DenyList は何も失効させない
+type DenyList struct {
+	shared Store
+	local  *MemoryStore
+	logger *logger.Logger
+}
+
+// NewDenyList は共有ストアとインメモリの代替を組み合わせた DenyList を作成する。
+// shared が nil の場合はインメモリのみで動作する
+func NewDenyList(shared Store, log *logger.Logger) *DenyList {
+	return &DenyList{shared: shared, local: NewMemoryStore(), logger: log}
+}
+
+// NewRedisDenyList は Redis を共有ストアとする DenyList を作成する。
+// 接続先が不正な場合はインメモリのみで動作する
+func NewRedisDenyList(redisURL string, log *logger.Logger) *DenyList {
+	store, err := NewRedisStore(redisURL)
+	if err != nil {
+		if log != nil {
+			log.Warn("Redisの接続先が不正なため、トークン失効リストはインメモリで動作します", "error", err)
+		}
+		return NewDenyList(nil, log)
+	}
+	return NewDenyList(store, log)
+}
+
+// RevokeToken は jti のトークンを expiresAt まで拒否する
+func (d *DenyList) RevokeToken(ctx context.Context, jti string, expiresAt time.Time) error {
+	if d == nil || jti == "" {
+		return nil
+	}
+	ttl := time.Until(expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return d.set(ctx, tokenKeyPrefix+jti, "1", ttl)
+}
+
+// RevokeUser は現在時刻より前に発行されたユーザーのトークンを ttl の間拒否する。
+// 失効時刻はミリ秒で保持し、失効直後に発行し直したトークンは受け付ける。
+// ttl にはアクセストークンの有効期間を渡す
+func (d *DenyList) RevokeUser(ctx context.Context, userID uuid.UUID, ttl time.Duration) error {
+	if d == nil {
+		return nil
+	}
+	return d.set(ctx, userKeyPrefix+userID.String(), strconv.FormatInt(time.Now().UnixMilli(), 10), ttl)
+}
+
+// RevokeSession はセッション（リフレッシュトークンのファミリー）のトークンを ttl の間拒否する。
+// ttl にはアクセストークンの有効期間を渡す
+func (d *DenyList) RevokeSession(ctx context.Context, sessionID uuid.UUID, ttl time.Duration) error {
+	if d == nil {
+		return nil
+	}
+	return d.set(ctx, sessionKeyPrefix+sessionID.String(), "1", ttl)
+}
+
+// IsSessionRevoked はセッションが終了済みかを返す
+func (d *DenyList) IsSessionRevoked(ctx context.Context, sessionID uuid.UUID) (bool, error) {
+	if d == nil || sessionID == uuid.Nil {
+		return false, nil
+	}
+	_, ok, err := d.get(ctx, sessionKeyPrefix+sessionID.String())
+	return ok, err
+}
+
+// ConsumeToken は一度だけ使えるトークン（チャットの承認ボタンなど）の jti を expiresAt まで使用済みとして記録する。
+// 初めて使われた場合は true、使用済み・期限切れ・jti がない場合は false を返す。
+// 共有ストアの障害時はこのプロセスで使用済みのトークンのみ拒否する
+func (d *DenyList) ConsumeToken(ctx context.Context, jti string, expiresAt time.Time) (bool, error) {
+	if d == nil {
+		return true, nil
+	}
+	ttl := time.Until(expiresAt)
+	if jti == "" || ttl <= 0 {
+		return false, nil
+	}
+	key := consumedPrefix + jti
+	if ok, _ := d.local.SetNX(ctx, key, "1", ttl); !ok {
+		return false, nil
+	}
+	if d.shared == nil {
+		return true, nil
+	}
+	ok, err := d.shared.SetNX(ctx, key, "1", ttl)
+	if err != nil {
+		if d.logger != nil {
+			d.logger.Warn("共有ストアへのトークン使用済みの書き込みに失敗しました。インメモリのみで保持します", "error", err)
+		}
+		return true, nil
+	}
+	return ok, nil
+}
+
+// IsRevoked はトークンが失効済みかを返す。
+// ユーザー単位の失効は issuedAt と失効時刻をミリ秒で比較する
+func (d *DenyList) IsRevoked(ctx context.Context, jti string, userID uuid.UUID, issuedAt time.Time) (bool, error) {
+	if d == nil {
+		return false, nil
+	}
+	if jti != "" {
+		if _, ok, err := d.get(ctx, tokenKeyPrefix+jti); err != nil || ok {
+			return ok, err
+		}
+	}
+	v, ok, err := d.get(ctx, userKeyPrefix+userID.String())
+	if err != nil || !ok {
+		return false, err
+	}
+	revokedAt, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return false, err
+	}
+	return issuedAt.Round(time.Millisecond).UnixMilli() < revokedAt, nil
+}
+
+func (d *DenyList) set(ctx context.Context, key, value string, ttl time.Duration) error {
+	_ = d.local.Set(ctx, key, value, ttl)
+	if d.shared == nil {
+		return nil
+	}
+	if err := d.shared.Set(ctx, key, value, ttl); err != nil && d.logger != nil {
+		d.logger.Warn("共有ストアへのトークン失効の書き込みに失敗しました。インメモリのみで保持します", "error", err)
+	}
+	return nil
+}
+
+func (d *DenyList) get(ctx context.Context, key string) (string, bool, error) {
+	if v, ok, _ := d.local.Get(ctx, key); ok {
+		return v, true, nil
+	}
+	if d.shared == nil {
+		return "", false, nil
+	}
+	v, ok, err := d.shared.Get(ctx, key)
+	if err != nil {
+		// 共有ストアの障害時はインメモリの結果のみで判定する（可用性を優先）
+		if d.logger != nil {
+			d.logger.Warn("共有ストアからトークン失効の読み込みに失敗しました", "error", err)
+		}
+		return "", false, nil
+	}
+	return v, ok, nil
+}
@@ -0,0 +1,189 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// failingStore は障害中の共有ストア
+type failingStore struct{}
+
+func (failingStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return errors.New("connection refused")
+}
+
+func (failingStore) Get(ctx context.Context, key string) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func (failingStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return false, errors.New("connection refused")
+}
+
+func TestDenyList_RevokeToken(t *testing.T) {
+	ctx := context.Background()
+	d := NewDenyList(nil, nil)
+	userID := uuid.New()
+
+	if err := d.RevokeToken(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if revoked, _ := d.IsRevoked(ctx, "jti-1", userID, time.Now()); !revoked {
+		t.Error("Expected revoked token")
+	}
+	if revoked, _ := d.IsRevoked(ctx, "jti-2", userID, time.Now()); revoked {
+		t.Error("Other token should stay valid")
+	}
+}
+
+func TestDenyList_RevokeUser(t *testing.T) {
+	ctx := context.Background()
+	d := NewDenyList(nil, nil)
+	userID := uuid.New()
+
+	if err := d.RevokeUser(ctx, userID, time.Minute); err != nil {
+		t.Fatalf("RevokeUser failed: %v", err)
+	}
+	if revoked, _ := d.IsRevoked(ctx, "old", userID, time.Now().Add(-time.Minute)); !revoked {
+		t.Error("Token issued before revocation should be rejected")
+	}
+	if revoked, _ := d.IsRevoked(ctx, "new", userID, time.Now().Add(2*time.Second)); revoked {
+		t.Error("Token issued after revocation should be accepted")
+	}
+	if revoked, _ := d.IsRevoked(ctx, "other", uuid.New(), time.Now().Add(-time.Minute)); revoked {
+		t.Error("Other users should not be affected")
+	}
+}
+
+func TestDenyList_RevokeUser_AcceptsTokenReissuedInSameSecond(t *testing.T) {
+	ctx := context.Background()
+	d := NewDenyList(nil, nil)
+	userID := uuid.New()
+
+	if err := d.RevokeUser(ctx, userID, time.Minute); err != nil {
+		t.Fatalf("RevokeUser failed: %v", err)
+	}
+	// 失効直後（同じ秒）に発行し直したトークンは受け付ける
+	time.Sleep(2 * time.Millisecond)
+	if revoked, _ := d.IsRevoked(ctx, "reissued", userID, time.Now()); revoked {
+		t.Error("Token issued right after revocation should be accepted")
+	}
+	if revoked, _ := d.IsRevoked(ctx, "old", userID, time.Now().Add(-10*time.Millisecond)); !revoked {
+		t.Error("Token issued just before revocation should be rejected")
+	}
+}
+
+func TestDenyList_FallsBackWhenSharedStoreFails(t *testing.T) {
+	ctx := context.Background()
+	d := NewDenyList(failingStore{}, nil)
+
+	if err := d.RevokeToken(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken should not fail while shared store is down: %v", err)
+	}
+	revoked, err := d.IsRevoked(ctx, "jti-1", uuid.New(), time.Now())
+	if err != nil || !revoked {
+		t.Errorf("Expected in-memory revocation, got %v (%v)", revoked, err)
+	}
+	if revoked, err := d.IsRevoked(ctx, "jti-2", uuid.New(), time.Now()); err != nil || revoked {
+		t.Errorf("Unknown token should be accepted while shared store is down, got %v (%v)", revoked, err)
+	}
+}
+
+func TestDenyList_ReadsSharedStore(t *testing.T) {
+	ctx := context.Background()
+	shared := NewMemoryStore()
+	other := NewDenyList(shared, nil)
+	d := NewDenyList(shared, nil)
+
+	// 別インスタンスで失効させたトークンも拒否する
+	if err := other.RevokeToken(ctx, "jti-1", time.Now().Add(time.Minute)); err != nil {
+		t.Fatalf("RevokeToken failed: %v", err)
+	}
+	if revoked, _ := d.IsRevoked(ctx, "jti-1", uuid.New(), time.Now()); !revoked {
+		t.Error("Expected revocation from shared store")
+	}
+}
+
+func TestDenyList_ConsumeToken(t *testing.T) {
+	ctx := context.Background()
+	shared := NewMemoryStore()
+	d := NewDenyList(shared, nil)
+	other := NewDenyList(shared, nil)
+	expiresAt := time.Now().Add(time.Hour)
+
+	if ok, err := d.ConsumeToken(ctx, "jti-1", expiresAt); !ok || err != nil {
+		t.Fatalf("Expected the first use to succeed, got %v (%v)", ok, err)
+	}
+	if ok, _ := d.ConsumeToken(ctx, "jti-1", expiresAt); ok {
+		t.Error("Expected a second use to be rejected")
+	}
+	// 別インスタンスでの再使用も共有ストアで拒否する
+	if ok, _ := other.ConsumeToken(ctx, "jti-1", expiresAt); ok {
+		t.Error("Expected reuse through another instance to be rejected")
+	}
+	if ok, _ := d.ConsumeToken(ctx, "", expiresAt); ok {
+		t.Error("Expected a token without jti to be rejected")
+	}
+	if ok, _ := d.ConsumeToken(ctx, "jti-2", time.Now().Add(-time.Minute)); ok {
+		t.Error("Expected an expired token to be rejected")
+	}
+
+	// 共有ストアの障害時もこのプロセスでの再使用は拒否する
+	down := NewDenyList(failingStore{}, nil)
+	if ok, _ := down.ConsumeToken(ctx, "jti-3", expiresAt); !ok {
+		t.Error("Expected the first use to succeed while the shared store is down")
+	}
+	if ok, _ := down.ConsumeToken(ctx, "jti-3", expiresAt); ok {
+		t.Error("Expected a second use to be rejected while the shared store is down")
+	}
+}
+
+func TestMemoryStore_Expires(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	_ = s.Set(ctx, "k", "v", time.Minute)
+	if _, ok, _ := s.Get(ctx, "k"); !ok {
+		t.Fatal("Expected value before expiry")
+	}
+	now = now.Add(time.Minute)
+	if _, ok, _ := s.Get(ctx, "k"); ok {
+		t.Error("Expected value to expire")
+	}
+}
+
+func TestNilDenyList(t *testing.T) {
+	var d *DenyList
+	if err := d.RevokeToken(context.Background(), "jti", time.Now().Add(time.Minute)); err != nil {
+		t.Errorf("Unexpected error: %v", err)
+	}
+	if revoked, err := d.IsRevoked(context.Background(), "jti", uuid.New(), time.Now()); revoked || err != nil {
+		t.Errorf("Nil deny list should not revoke, got %v (%v)", revoked, err)
+	}
+}
+
+func TestDenyList_RevokeSession(t *testing.T) {
+	ctx := context.Background()
+	d := NewDenyList(nil, nil)
+	sessionID := uuid.New()
+
+	if err := d.RevokeSession(ctx, sessionID, time.Minute); err != nil {
+		t.Fatalf("RevokeSession failed: %v", err)
+	}
+	if revoked, _ := d.IsSessionRevoked(ctx, sessionID); !revoked {
+		t.Error("Expected revoked session")
+	}
+	if revoked, _ := d.IsSessionRevoked(ctx, uuid.New()); revoked {
+		t.Error("Other sessions should stay valid")
+	}
+	var nilList *DenyList
+	if revoked, err := nilList.IsSessionRevoked(ctx, sessionID); err != nil || revoked {
+		t.Error("Nil deny list should not revoke sessions")
+	}
+}
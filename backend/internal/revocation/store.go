@@ -0,0 +1,114 @@
+package revocation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Store は有効期限付きのキーバリューストア
+type Store interface {
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// Get はキーが存在しない場合 ok=false を返す
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	// SetNX はキーが存在しない場合のみ書き込み、書き込んだかを返す
+	SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error)
+}
+
+// ===== Redis =====
+
+type redisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore は REDIS_URL 形式の接続先から Redis ストアを作成する
+func NewRedisStore(redisURL string) (Store, error) {
+	opts, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, err
+	}
+	// 認証のたびに参照するため、障害時に長く待たないようにする
+	opts.DialTimeout = 500 * time.Millisecond
+	opts.ReadTimeout = 200 * time.Millisecond
+	opts.WriteTimeout = 200 * time.Millisecond
+	opts.MaxRetries = 1
+	return &redisStore{client: redis.NewClient(opts)}, nil
+}
+
+func (s *redisStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+func (s *redisStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (s *redisStore) Get(ctx context.Context, key string) (string, bool, error) {
+	v, err := s.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return v, true, nil
+}
+
+// ===== インメモリ =====
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// MemoryStore はプロセス内のストア。Redis が使えない場合の代替に使う
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+func (s *MemoryStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	// 書き込みのついでに期限切れを掃除する
+	for k, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	s.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+func (s *MemoryStore) SetNX(ctx context.Context, key, value string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if e, ok := s.entries[key]; ok && now.Before(e.expiresAt) {
+		return false, nil
+	}
+	s.entries[key] = memoryEntry{value: value, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+func (s *MemoryStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, ok := s.entries[key]
+	if !ok || !s.now().Before(e.expiresAt) {
+		return "", false, nil
+	}
+	return e.value, true, nil
+}
@@ -18,13 +18,20 @@ func Setup(r *gin.Engine, h *handler.Handlers, mw *middleware.Middleware) {
 	r.Use(mw.CORS())
 	r.Use(mw.SecurityHeaders())
 	r.Use(mw.RateLimit())
+	r.Use(mw.ClientInfo())
 
 	r.GET("/health", h.Health.Health)
 	r.GET("/live", h.Health.Liveness)
 	r.GET("/ready", h.Health.Readiness)
 	r.GET("/metrics", gin.WrapH(promhttp.Handler()))
 
+	// Slack / Teams からのコールバックは Cookie を持たないため、CSRF を適用する v1 より前に登録する
+	sharedroutes.RegisterIntegrationRoutes(r.Group("/api/v1/integrations"), h)
+
 	v1 := r.Group("/api/v1")
+	if mw.CookieAuthEnabled() {
+		v1.Use(mw.CSRF())
+	}
 	sharedroutes.RegisterPublicRoutes(v1, h)
 
 	protected := v1.Group("")
@@ -32,6 +39,6 @@ func Setup(r *gin.Engine, h *handler.Handlers, mw *middleware.Middleware) {
 
 	sharedroutes.RegisterProtectedRoutes(protected, h, mw)
 	attendanceroutes.RegisterProtectedRoutes(protected, h, mw)
-	expenseroutes.RegisterProtectedRoutes(protected, h)
-	hrroutes.RegisterProtectedRoutes(protected, h)
+	expenseroutes.RegisterProtectedRoutes(protected, h, mw)
+	hrroutes.RegisterProtectedRoutes(protected, h, mw)
 }
@@ -119,4 +119,30 @@ func TestSetup_RouteAccessBranches(t *testing.T) {
 			t.Fatalf("expected status %d, got %d", http.StatusBadRequest, w.Code)
 		}
 	})
+
+	t.Run("hr and expense endpoints are gated by permission", func(t *testing.T) {
+		cases := []struct {
+			method, path, role string
+			want               int
+		}{
+			{http.MethodGet, "/api/v1/hr/salary", "employee", http.StatusForbidden},
+			{http.MethodGet, "/api/v1/hr/salary", "manager", http.StatusForbidden},
+			{http.MethodGet, "/api/v1/hr/employees/not-a-uuid", "employee", http.StatusForbidden},
+			{http.MethodGet, "/api/v1/hr/employees/not-a-uuid", "manager", http.StatusBadRequest},
+			{http.MethodPut, "/api/v1/expenses/not-a-uuid/approve", "employee", http.StatusForbidden},
+			{http.MethodPut, "/api/v1/expenses/not-a-uuid/approve", "manager", http.StatusBadRequest},
+			{http.MethodDelete, "/api/v1/expenses/policies/not-a-uuid", "manager", http.StatusForbidden},
+			{http.MethodPut, "/api/v1/working-hour-policies/not-a-uuid", "manager", http.StatusForbidden},
+		}
+		for _, tc := range cases {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(tc.method, tc.path, nil)
+			req.Header.Set("Authorization", makeBearerToken(t, "test-secret", "user-1", tc.role))
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.want {
+				t.Errorf("%s %s as %s: expected status %d, got %d", tc.method, tc.path, tc.role, tc.want, w.Code)
+			}
+		}
+	})
 }
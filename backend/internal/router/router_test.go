@@ -17,13 +17,20 @@ import (
 // createMockServices creates mock services for testing
 func createMockServices() *service.Services {
 	return &service.Services{
-		Auth:       &mocks.MockAuthService{},
-		Attendance: &mocks.MockAttendanceService{},
-		Leave:      &mocks.MockLeaveService{},
-		Shift:      &mocks.MockShiftService{},
-		User:       &mocks.MockUserService{},
-		Department: &mocks.MockDepartmentService{},
-		Dashboard:  &mocks.MockDashboardService{},
+		Auth:          &mocks.MockAuthService{},
+		MFA:           &mocks.MockMFAService{},
+		SSO:           &mocks.MockSSOService{},
+		LoginSecurity: &mocks.MockLoginSecurityService{},
+		Session:       &mocks.MockSessionService{},
+		APIToken:      &mocks.MockAPITokenService{},
+		Audit:         &mocks.MockAuditService{},
+		Attendance:    &mocks.MockAttendanceService{},
+		Leave:         &mocks.MockLeaveService{},
+		Shift:         &mocks.MockShiftService{},
+		User:          &mocks.MockUserService{},
+		Department:    &mocks.MockDepartmentService{},
+		Dashboard:     &mocks.MockDashboardService{},
+		ChatAction:    &mocks.MockChatActionService{},
 	}
 }
 
@@ -120,6 +127,17 @@ func TestSetup_RegisteredRoutes(t *testing.T) {
 		{"POST", "/api/v1/auth/login"},
 		{"POST", "/api/v1/auth/register"},
 		{"POST", "/api/v1/auth/refresh"},
+		{"GET", "/api/v1/auth/csrf"},
+		{"POST", "/api/v1/auth/forgot-password"},
+		{"POST", "/api/v1/auth/reset-password"},
+		{"POST", "/api/v1/auth/verify-email"},
+		{"POST", "/api/v1/auth/resend-verification"},
+		{"POST", "/api/v1/auth/mfa/verify"},
+		{"POST", "/api/v1/auth/mfa/setup"},
+		{"POST", "/api/v1/auth/mfa/setup/confirm"},
+		{"GET", "/api/v1/auth/oidc/login"},
+		{"GET", "/api/v1/auth/oidc/callback"},
+		{"POST", "/api/v1/auth/oidc/exchange"},
 		{"POST", "/api/v1/auth/logout"},
 		{"GET", "/api/v1/attendance"},
 		{"POST", "/api/v1/attendance/clock-in"},
@@ -127,6 +145,37 @@ func TestSetup_RegisteredRoutes(t *testing.T) {
 		{"GET", "/api/v1/leaves"},
 		{"POST", "/api/v1/leaves"},
 		{"GET", "/api/v1/users/me"},
+		{"GET", "/api/v1/users/me/mfa"},
+		{"POST", "/api/v1/users/me/mfa/enable"},
+		{"GET", "/api/v1/mfa-policy"},
+		{"DELETE", "/api/v1/users/:id/mfa"},
+		{"GET", "/api/v1/users/me/login-history"},
+		{"GET", "/api/v1/users/:id/login-history"},
+		{"POST", "/api/v1/users/:id/unlock"},
+		{"GET", "/api/v1/users/me/sessions"},
+		{"DELETE", "/api/v1/users/me/sessions/:id"},
+		{"POST", "/api/v1/users/me/sessions/revoke-others"},
+		{"GET", "/api/v1/users/:id/sessions"},
+		{"POST", "/api/v1/users/:id/force-logout"},
+		{"GET", "/api/v1/users/me/api-tokens"},
+		{"POST", "/api/v1/users/me/api-tokens"},
+		{"DELETE", "/api/v1/users/me/api-tokens/:id"},
+		{"GET", "/api/v1/api-tokens/scopes"},
+		{"GET", "/api/v1/service-accounts"},
+		{"POST", "/api/v1/service-accounts"},
+		{"PUT", "/api/v1/service-accounts/:id"},
+		{"DELETE", "/api/v1/service-accounts/:id"},
+		{"GET", "/api/v1/service-accounts/:id/tokens"},
+		{"POST", "/api/v1/service-accounts/:id/tokens"},
+		{"DELETE", "/api/v1/service-accounts/:id/tokens/:tokenId"},
+		{"GET", "/api/v1/audit-logs"},
+		{"GET", "/api/v1/audit-logs/export"},
+		{"GET", "/api/v1/audit-logs/verify"},
+		{"GET", "/api/v1/notifications/preferences"},
+		{"PUT", "/api/v1/notifications/preferences"},
+		{"GET", "/api/v1/events"},
+		{"POST", "/api/v1/integrations/slack/actions"},
+		{"POST", "/api/v1/integrations/teams/actions"},
 		{"GET", "/api/v1/departments"},
 		{"GET", "/api/v1/shifts"},
 		{"GET", "/api/v1/dashboard/stats"},
@@ -146,3 +195,52 @@ func TestSetup_RegisteredRoutes(t *testing.T) {
 		}
 	}
 }
+
+func TestSetup_CookieModeRequiresCSRF(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		JWTSecretKey:   "test-secret",
+		RateLimitRPS:   100,
+		RateLimitBurst: 10,
+		AllowedOrigins: []string{"*"},
+		AuthCookieMode: true,
+	}
+	log, _ := logger.NewLogger("debug", "test")
+	mw := middleware.NewMiddleware(cfg, log)
+	r := gin.New()
+	Setup(r, handler.NewHandlers(createMockServices(), log).WithCookieAuth(cfg), mw)
+
+	// CSRFトークンを取得し、ログインに付ける
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/api/v1/auth/csrf", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || len(w.Result().Cookies()) != 1 {
+		t.Fatalf("Expected a CSRF token cookie, got %d", w.Code)
+	}
+	csrf := w.Result().Cookies()[0]
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusForbidden {
+		t.Errorf("Expected login without CSRF token to be rejected, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+	req.AddCookie(csrf)
+	req.Header.Set("X-CSRF-Token", csrf.Value)
+	r.ServeHTTP(w, req)
+	if w.Code == http.StatusForbidden {
+		t.Error("Expected login with a matching CSRF token to pass the CSRF check")
+	}
+
+	// Slack / Teams のコールバックは署名で検証するため CSRF トークンを要求しない
+	w = httptest.NewRecorder()
+	req, _ = http.NewRequest(http.MethodPost, "/api/v1/integrations/slack/actions", nil)
+	r.ServeHTTP(w, req)
+	if w.Code == http.StatusForbidden {
+		t.Error("Expected chat integration callbacks to bypass the CSRF check")
+	}
+}
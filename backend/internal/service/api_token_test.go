@@ -0,0 +1,177 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+func TestAPITokenService_CreatePersonal_Validates(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Config.APITokenMaxExpiryDays = 90
+	_, resp := loginTestUser(t, deps)
+	svc := NewAPITokenService(deps)
+	ctx := context.Background()
+
+	tests := map[string]*model.APITokenRequest{
+		"name":            {Name: " ", Scopes: []string{"export:read"}, ExpiresInDays: 30},
+		"scopes":          {Name: "BI", Scopes: nil, ExpiresInDays: 30},
+		"unknown scope":   {Name: "BI", Scopes: []string{"export:write"}, ExpiresInDays: 30},
+		"expires_in_days": {Name: "BI", Scopes: []string{"export:read"}, ExpiresInDays: 91},
+	}
+	for name, req := range tests {
+		_, err := svc.CreatePersonal(ctx, resp.User.ID, req)
+		var verr *model.ValidationError
+		if !errors.As(err, &verr) {
+			t.Errorf("%s: expected ValidationError, got %v", name, err)
+		}
+	}
+	if _, err := svc.CreatePersonal(ctx, uuid.New(), &model.APITokenRequest{Name: "BI", Scopes: []string{"export:read"}, ExpiresInDays: 30}); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestAPITokenService_PersonalToken(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Config.APITokenMaxExpiryDays = 365
+	_, resp := loginTestUser(t, deps)
+	userID := resp.User.ID
+	svc := NewAPITokenService(deps)
+	ctx := context.Background()
+
+	issued, err := svc.CreatePersonal(ctx, userID, &model.APITokenRequest{
+		Name: "給与連携", Scopes: []string{"export:read", "attendance:write", "export:read"}, ExpiresInDays: 30,
+	})
+	if err != nil {
+		t.Fatalf("CreatePersonal failed: %v", err)
+	}
+	if !strings.HasPrefix(issued.Token, "kt_pat_") || !strings.HasPrefix(issued.Token, issued.APIToken.Prefix) {
+		t.Errorf("Unexpected token %q with prefix %q", issued.Token, issued.APIToken.Prefix)
+	}
+	if issued.APIToken.TokenHash == issued.Token || issued.APIToken.TokenHash != hashToken(issued.Token) {
+		t.Error("Only the hash of the token should be stored")
+	}
+
+	actor, err := svc.AuthenticateAPIToken(middleware.WithClientInfo(ctx, middleware.ClientInfo{IPAddress: "203.0.113.5"}), issued.Token)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIToken failed: %v", err)
+	}
+	if actor.UserID != userID || actor.Role != resp.User.Role || actor.ServiceAccount || actor.APITokenID != issued.APIToken.ID {
+		t.Errorf("Unexpected actor: %+v", actor)
+	}
+	if len(actor.Scopes) != 2 || !actor.HasScope("attendance:read") || actor.HasScope("expense:read") {
+		t.Errorf("Unexpected scopes: %v", actor.Scopes)
+	}
+	stored := deps.Repos.APIToken.(*mocks.MockAPITokenRepository).Tokens[issued.APIToken.ID]
+	if stored.LastUsedAt == nil || stored.LastUsedIP != "203.0.113.5" {
+		t.Errorf("Last use should be recorded, got %v from %q", stored.LastUsedAt, stored.LastUsedIP)
+	}
+
+	if tokens, _ := svc.ListPersonal(ctx, userID); len(tokens) != 1 {
+		t.Errorf("Expected 1 token, got %d", len(tokens))
+	}
+	// 他のユーザーのトークンは失効できない
+	if err := svc.RevokePersonal(ctx, uuid.New(), issued.APIToken.ID); !errors.Is(err, ErrAPITokenNotFound) {
+		t.Errorf("Expected ErrAPITokenNotFound, got %v", err)
+	}
+	if err := svc.RevokePersonal(ctx, userID, issued.APIToken.ID); err != nil {
+		t.Fatalf("RevokePersonal failed: %v", err)
+	}
+	if _, err := svc.AuthenticateAPIToken(ctx, issued.Token); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Revoked token should be rejected, got %v", err)
+	}
+}
+
+func TestAPITokenService_AuthenticateAPIToken_Rejects(t *testing.T) {
+	deps := setupTestDeps(t)
+	_, resp := loginTestUser(t, deps)
+	svc := NewAPITokenService(deps)
+	ctx := context.Background()
+	req := &model.APITokenRequest{Name: "BI", Scopes: []string{"export:read"}, ExpiresInDays: 1}
+
+	if _, err := svc.AuthenticateAPIToken(ctx, "kt_pat_unknown"); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Unknown token: expected ErrInvalidAPIToken, got %v", err)
+	}
+
+	expired, _ := svc.CreatePersonal(ctx, resp.User.ID, req)
+	expired.APIToken.ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := svc.AuthenticateAPIToken(ctx, expired.Token); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Expired token: expected ErrInvalidAPIToken, got %v", err)
+	}
+
+	// 無効化されたユーザーのトークンは使えない
+	active, _ := svc.CreatePersonal(ctx, resp.User.ID, req)
+	deps.Repos.User.(*mocks.MockUserRepository).Users[resp.User.ID].IsActive = false
+	if _, err := svc.AuthenticateAPIToken(ctx, active.Token); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Inactive user: expected ErrInvalidAPIToken, got %v", err)
+	}
+}
+
+func TestAPITokenService_ServiceAccount(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc := NewAPITokenService(deps)
+	admin := middleware.WithActor(context.Background(), middleware.Actor{UserID: uuid.New(), Role: model.RoleAdmin})
+	manager := middleware.WithActor(context.Background(), middleware.Actor{UserID: uuid.New(), Role: model.RoleManager})
+
+	// 自分より強いロールは付与できない
+	if _, err := svc.CreateServiceAccount(manager, &model.ServiceAccountRequest{Name: "payroll", Role: model.RoleAdmin}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden, got %v", err)
+	}
+	var verr *model.ValidationError
+	if _, err := svc.CreateServiceAccount(admin, &model.ServiceAccountRequest{Name: "payroll", Role: "owner"}); !errors.As(err, &verr) {
+		t.Errorf("Expected ValidationError for unknown role, got %v", err)
+	}
+
+	account, err := svc.CreateServiceAccount(admin, &model.ServiceAccountRequest{Name: "payroll", Role: model.RoleManager})
+	if err != nil {
+		t.Fatalf("CreateServiceAccount failed: %v", err)
+	}
+	if _, err := svc.CreateServiceAccount(admin, &model.ServiceAccountRequest{Name: "payroll", Role: model.RoleManager}); !errors.As(err, &verr) {
+		t.Errorf("Expected ValidationError for duplicate name, got %v", err)
+	}
+
+	issued, err := svc.CreateServiceAccountToken(admin, account.ID, &model.APITokenRequest{Name: "nightly", Scopes: []string{"export:read"}, ExpiresInDays: 30})
+	if err != nil {
+		t.Fatalf("CreateServiceAccountToken failed: %v", err)
+	}
+	if !strings.HasPrefix(issued.Token, "kt_sa_") {
+		t.Errorf("Unexpected token %q", issued.Token)
+	}
+	actor, err := svc.AuthenticateAPIToken(context.Background(), issued.Token)
+	if err != nil {
+		t.Fatalf("AuthenticateAPIToken failed: %v", err)
+	}
+	if actor.UserID != account.ID || actor.Role != model.RoleManager || !actor.ServiceAccount {
+		t.Errorf("Unexpected actor: %+v", actor)
+	}
+
+	// 無効にすると利用できず、有効に戻すと再び利用できる
+	inactive, active := false, true
+	if _, err := svc.UpdateServiceAccount(admin, account.ID, &model.ServiceAccountUpdateRequest{IsActive: &inactive}); err != nil {
+		t.Fatalf("UpdateServiceAccount failed: %v", err)
+	}
+	if _, err := svc.AuthenticateAPIToken(context.Background(), issued.Token); !errors.Is(err, ErrInvalidAPIToken) {
+		t.Errorf("Inactive account: expected ErrInvalidAPIToken, got %v", err)
+	}
+	_, _ = svc.UpdateServiceAccount(admin, account.ID, &model.ServiceAccountUpdateRequest{IsActive: &active})
+
+	if err := svc.RevokeServiceAccountToken(admin, uuid.New(), issued.APIToken.ID); !errors.Is(err, ErrAPITokenNotFound) {
+		t.Errorf("Expected ErrAPITokenNotFound for another account, got %v", err)
+	}
+	if err := svc.DeleteServiceAccount(admin, account.ID); err != nil {
+		t.Fatalf("DeleteServiceAccount failed: %v", err)
+	}
+	if issued.APIToken.RevokedAt == nil {
+		t.Error("Deleting the account should revoke its tokens")
+	}
+	if _, err := svc.ListServiceAccountTokens(admin, account.ID); !errors.Is(err, ErrServiceAccountNotFound) {
+		t.Errorf("Expected ErrServiceAccountNotFound, got %v", err)
+	}
+}
@@ -1,25 +1,45 @@
 package service
 
-import appattendance "github.com/your-org/kintai/backend/internal/apps/attendance"
+import (
+	"context"
+	"time"
+
+	appattendance "github.com/your-org/kintai/backend/internal/apps/attendance"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
 
 type AttendanceService = appattendance.AttendanceService
 type LeaveService = appattendance.LeaveService
 type OvertimeRequestService = appattendance.OvertimeRequestService
 type LeaveBalanceService = appattendance.LeaveBalanceService
 type AttendanceCorrectionService = appattendance.AttendanceCorrectionService
+type SubstituteHolidayService = appattendance.SubstituteHolidayService
+type WorkingHourPolicyService = appattendance.WorkingHourPolicyService
+type RemoteWorkPolicyService = appattendance.RemoteWorkPolicyService
+type TeamService = appattendance.TeamService
 
 func toAttendanceDeps(deps Deps) appattendance.Deps {
 	return appattendance.Deps{
 		Repos: &appattendance.Repositories{
 			User:                 deps.Repos.User,
+			Shift:                deps.Repos.Shift,
+			ShiftSchedule:        deps.Repos.ShiftSchedule,
 			Attendance:           deps.Repos.Attendance,
 			LeaveRequest:         deps.Repos.LeaveRequest,
 			OvertimeRequest:      deps.Repos.OvertimeRequest,
 			AttendanceCorrection: deps.Repos.AttendanceCorrection,
 			LeaveBalance:         deps.Repos.LeaveBalance,
+			Holiday:              deps.Repos.Holiday,
+			SubstituteHoliday:    deps.Repos.SubstituteHoliday,
+			CompensatoryDay:      deps.Repos.CompensatoryDay,
+			WorkingHourPolicy:    deps.Repos.WorkingHourPolicy,
+			RemoteWorkPolicy:     deps.Repos.RemoteWorkPolicy,
+			Team:                 deps.Repos.Team,
 		},
 		Config: deps.Config,
 		Logger: deps.Logger,
+		Events: deps.Events,
 	}
 }
 
@@ -27,6 +47,15 @@ func NewAttendanceService(deps Deps) AttendanceService {
 	return appattendance.NewAttendanceService(toAttendanceDeps(deps))
 }
 
+// RunAttendanceAutoCloseJob は退勤打刻漏れの自動処理ジョブを実行する（設定が off の場合は何もしない）
+func RunAttendanceAutoCloseJob(ctx context.Context, svc AttendanceService, cfg *config.Config, log *logger.Logger) {
+	if cfg.AttendanceAutoCloseMode == appattendance.AutoCloseModeOff {
+		return
+	}
+	interval := time.Duration(cfg.AttendanceAutoCloseIntervalMinutes) * time.Minute
+	appattendance.RunAutoCloseJob(ctx, svc, interval, log)
+}
+
 func NewLeaveService(deps Deps, notificationSvc NotificationService) LeaveService {
 	return appattendance.NewLeaveService(toAttendanceDeps(deps), notificationSvc)
 }
@@ -42,3 +71,19 @@ func NewLeaveBalanceService(deps Deps) LeaveBalanceService {
 func NewAttendanceCorrectionService(deps Deps, notificationSvc NotificationService) AttendanceCorrectionService {
 	return appattendance.NewAttendanceCorrectionService(toAttendanceDeps(deps), notificationSvc)
 }
+
+func NewSubstituteHolidayService(deps Deps, notificationSvc NotificationService) SubstituteHolidayService {
+	return appattendance.NewSubstituteHolidayService(toAttendanceDeps(deps), notificationSvc)
+}
+
+func NewWorkingHourPolicyService(deps Deps) WorkingHourPolicyService {
+	return appattendance.NewWorkingHourPolicyService(toAttendanceDeps(deps))
+}
+
+func NewRemoteWorkPolicyService(deps Deps) RemoteWorkPolicyService {
+	return appattendance.NewRemoteWorkPolicyService(toAttendanceDeps(deps))
+}
+
+func NewTeamService(deps Deps) TeamService {
+	return appattendance.NewTeamService(toAttendanceDeps(deps))
+}
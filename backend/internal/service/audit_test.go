@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/audit"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// auditChain は正しくハッシュチェーンを構成した監査ログを n 件作成する
+func auditChain(n int) []model.AuditLog {
+	logs := make([]model.AuditLog, n)
+	prevHash := audit.GenesisHash
+	for i := range logs {
+		actorID := uuid.New()
+		logs[i] = model.AuditLog{
+			Seq:        int64(i + 1),
+			OccurredAt: time.Date(2026, 4, 1, 9, 0, i, 0, time.UTC),
+			ActorID:    &actorID,
+			ActorType:  model.AuditActorUser,
+			Action:     model.AuditActionUpdate,
+			EntityType: "users",
+			EntityID:   uuid.NewString(),
+			Changes:    `{"role":{"old":"employee","new":"manager"}}`,
+			PrevHash:   prevHash,
+		}
+		logs[i].Hash = audit.Hash(&logs[i])
+		prevHash = logs[i].Hash
+	}
+	return logs
+}
+
+func TestAuditService_Verify(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		tamper    func(logs []model.AuditLog) []model.AuditLog
+		brokenSeq int64
+	}{
+		{"valid", func(logs []model.AuditLog) []model.AuditLog { return logs }, 0},
+		{"modified changes", func(logs []model.AuditLog) []model.AuditLog {
+			logs[1].Changes = `{"role":{"old":"employee","new":"admin"}}`
+			return logs
+		}, 2},
+		{"rehashed record", func(logs []model.AuditLog) []model.AuditLog {
+			logs[1].EntityID = "other"
+			logs[1].Hash = audit.Hash(&logs[1])
+			return logs
+		}, 3},
+		{"deleted record", func(logs []model.AuditLog) []model.AuditLog {
+			return append(logs[:1], logs[2:]...)
+		}, 2},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			deps := setupTestDeps(t)
+			deps.Repos.AuditLog = &mocks.MockAuditLogRepository{Logs: tc.tamper(auditChain(3))}
+
+			result, err := NewAuditService(deps).Verify(ctx)
+			if err != nil {
+				t.Fatalf("Verify: %v", err)
+			}
+			if tc.brokenSeq == 0 {
+				if !result.Valid || result.Checked != 3 {
+					t.Errorf("Expected a valid chain of 3, got %+v", result)
+				}
+				return
+			}
+			if result.Valid || result.BrokenSeq == nil || *result.BrokenSeq != tc.brokenSeq {
+				t.Errorf("Expected chain broken at %d, got %+v", tc.brokenSeq, result)
+			}
+		})
+	}
+}
+
+func TestAuditService_Verify_ManyBatches(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Repos.AuditLog = &mocks.MockAuditLogRepository{Logs: auditChain(auditVerifyBatchSize + 5)}
+
+	result, err := NewAuditService(deps).Verify(context.Background())
+	if err != nil || !result.Valid || result.Checked != auditVerifyBatchSize+5 {
+		t.Errorf("Expected every record to be checked across batches, got %+v %v", result, err)
+	}
+}
+
+func TestAuditService_ListAndExport(t *testing.T) {
+	deps := setupTestDeps(t)
+	logs := auditChain(3)
+	logs[2].Action = model.AuditActionDelete
+	logs[2].EntityType = "departments"
+	deps.Repos.AuditLog = &mocks.MockAuditLogRepository{Logs: logs}
+	svc := NewAuditService(deps)
+	ctx := context.Background()
+
+	found, total, err := svc.List(ctx, &model.AuditLogQuery{EntityType: "users", Page: 1, PageSize: 20})
+	if err != nil || total != 2 || len(found) != 2 || found[0].Seq != 2 {
+		t.Errorf("Expected 2 user records newest first, got %d %v", total, err)
+	}
+
+	data, err := svc.ExportCSV(ctx, &model.AuditLogQuery{Action: model.AuditActionDelete})
+	if err != nil {
+		t.Fatalf("ExportCSV: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[1], "departments") || !strings.Contains(lines[1], logs[2].Hash) {
+		t.Errorf("Expected header and one delete record, got %q", lines)
+	}
+}
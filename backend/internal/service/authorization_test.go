@@ -0,0 +1,253 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	appexpense "github.com/your-org/kintai/backend/internal/apps/expense"
+	apphr "github.com/your-org/kintai/backend/internal/apps/hr"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+func actorCtx(userID uuid.UUID, role model.Role) context.Context {
+	return middleware.WithActor(context.Background(), middleware.Actor{UserID: userID, Role: role})
+}
+
+func TestTimeEntryService_OwnershipRequired(t *testing.T) {
+	deps, _, _, _, _, _, teRepo, _, _ := setupExtendedTestDeps(t)
+	svc := NewTimeEntryService(deps)
+
+	ownerID := uuid.New()
+	entryID := uuid.New()
+	teRepo.entries[entryID] = &model.TimeEntry{BaseModel: model.BaseModel{ID: entryID}, UserID: ownerID, Minutes: 60}
+
+	minutes := 90
+	otherCtx := actorCtx(uuid.New(), model.RoleEmployee)
+	if _, err := svc.Update(otherCtx, entryID, &model.TimeEntryUpdate{Minutes: &minutes}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on update by other user, got %v", err)
+	}
+	if err := svc.Delete(otherCtx, entryID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on delete by other user, got %v", err)
+	}
+	// マネージャーでも他人の工数は変更できない
+	if err := svc.Delete(actorCtx(uuid.New(), model.RoleManager), entryID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on delete by manager, got %v", err)
+	}
+
+	if _, err := svc.Update(actorCtx(ownerID, model.RoleEmployee), entryID, &model.TimeEntryUpdate{Minutes: &minutes}); err != nil {
+		t.Errorf("Owner update failed: %v", err)
+	}
+	if err := svc.Delete(actorCtx(uuid.New(), model.RoleAdmin), entryID); err != nil {
+		t.Errorf("Admin delete failed: %v", err)
+	}
+}
+
+func TestExpenseService_ApprovalScope(t *testing.T) {
+	env := newExpTestEnv()
+	team := mocks.NewMockTeamRepository()
+	env.deps.Repos.Team = team
+	svc := NewExpenseService(env.deps)
+
+	managerID, reportID, outsiderID := uuid.New(), uuid.New(), uuid.New()
+	team.Reports[managerID] = []uuid.UUID{reportID}
+
+	newExpense := func(ownerID uuid.UUID) uuid.UUID {
+		id := uuid.New()
+		env.expenseRepo.expenses[id] = &model.Expense{
+			BaseModel: model.BaseModel{ID: id}, UserID: ownerID, Title: "交通費", Status: model.ExpenseStatusPending,
+		}
+		return id
+	}
+	approve := &model.ExpenseApproveRequest{Status: "approved"}
+	managerCtx := actorCtx(managerID, model.RoleManager)
+
+	if err := svc.Approve(managerCtx, newExpense(managerID), managerID, approve); !errors.Is(err, appexpense.ErrSelfApproval) {
+		t.Errorf("Expected ErrSelfApproval, got %v", err)
+	}
+	if err := svc.Approve(managerCtx, newExpense(outsiderID), managerID, approve); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for outsider, got %v", err)
+	}
+	if err := svc.AdvancedApprove(managerCtx, newExpense(outsiderID), managerID, &model.ExpenseAdvancedApproveRequest{Action: "approve", Step: 1}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for advanced approval of outsider, got %v", err)
+	}
+	if err := svc.Approve(managerCtx, newExpense(reportID), managerID, approve); err != nil {
+		t.Errorf("Manager approval of direct report failed: %v", err)
+	}
+
+	adminID := uuid.New()
+	if err := svc.Approve(actorCtx(adminID, model.RoleAdmin), newExpense(outsiderID), adminID, approve); err != nil {
+		t.Errorf("Admin approval failed: %v", err)
+	}
+}
+
+func TestExpenseService_OwnerAccess(t *testing.T) {
+	env := newExpTestEnv()
+	svc := NewExpenseService(env.deps)
+
+	ownerID := uuid.New()
+	expenseID := uuid.New()
+	env.expenseRepo.expenses[expenseID] = &model.Expense{
+		BaseModel: model.BaseModel{ID: expenseID}, UserID: ownerID, Title: "出張", Status: model.ExpenseStatusDraft,
+	}
+	otherCtx := actorCtx(uuid.New(), model.RoleEmployee)
+	ownerCtx := actorCtx(ownerID, model.RoleEmployee)
+
+	if _, err := svc.GetByID(otherCtx, expenseID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on read by other user, got %v", err)
+	}
+	if _, err := NewExpenseHistoryService(env.deps).GetHistory(otherCtx, expenseID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on history by other user, got %v", err)
+	}
+	if _, err := svc.Update(otherCtx, expenseID, uuid.New(), &model.ExpenseUpdateRequest{Title: expPtrString("x")}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on update by other user, got %v", err)
+	}
+	if _, err := svc.Update(ownerCtx, expenseID, ownerID, &model.ExpenseUpdateRequest{Status: expPtrString("approved")}); !errors.Is(err, appexpense.ErrInvalidExpenseStatus) {
+		t.Errorf("Expected ErrInvalidExpenseStatus when owner approves own expense, got %v", err)
+	}
+	if err := svc.Delete(otherCtx, expenseID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on delete by other user, got %v", err)
+	}
+	if err := svc.Delete(ownerCtx, expenseID); err != nil {
+		t.Errorf("Owner delete failed: %v", err)
+	}
+}
+
+func TestGoalService_TeamScope(t *testing.T) {
+	deps, repos := setupHRServiceDeps(t)
+	svc := NewGoalService(deps)
+
+	managerUserID, reportUserID := uuid.New(), uuid.New()
+	managerEmpID, reportEmpID := uuid.New(), uuid.New()
+	repos.hrEmployee.items[managerEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: managerEmpID}, UserID: &managerUserID}
+	repos.hrEmployee.items[reportEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: reportEmpID}, UserID: &reportUserID, ManagerID: &managerEmpID}
+
+	req := model.HRGoalCreateRequest{Title: "売上目標", EmployeeID: &reportEmpID}
+	if _, err := svc.Create(actorCtx(uuid.New(), model.RoleEmployee), req, uuid.New()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for unrelated employee, got %v", err)
+	}
+	if _, err := svc.Create(actorCtx(uuid.New(), model.RoleManager), req, uuid.New()); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for other manager, got %v", err)
+	}
+	goal, err := svc.Create(actorCtx(managerUserID, model.RoleManager), req, managerUserID)
+	if err != nil {
+		t.Fatalf("Manager create for direct report failed: %v", err)
+	}
+	if _, err := svc.UpdateProgress(actorCtx(reportUserID, model.RoleEmployee), goal.ID, 50); err != nil {
+		t.Errorf("Owner progress update failed: %v", err)
+	}
+}
+
+func TestEvaluationService_TeamScope(t *testing.T) {
+	deps, repos := setupHRServiceDeps(t)
+	svc := NewEvaluationService(deps)
+
+	managerUserID, reportUserID, outsiderUserID := uuid.New(), uuid.New(), uuid.New()
+	managerEmpID, reportEmpID, outsiderEmpID := uuid.New(), uuid.New(), uuid.New()
+	repos.hrEmployee.items[managerEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: managerEmpID}, UserID: &managerUserID}
+	repos.hrEmployee.items[reportEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: reportEmpID}, UserID: &reportUserID, ManagerID: &managerEmpID}
+	repos.hrEmployee.items[outsiderEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: outsiderEmpID}, UserID: &outsiderUserID}
+
+	managerCtx := actorCtx(managerUserID, model.RoleManager)
+	own, err := svc.Create(managerCtx, model.EvaluationCreateRequest{EmployeeID: reportEmpID}, managerUserID)
+	if err != nil {
+		t.Fatalf("Manager create for direct report failed: %v", err)
+	}
+	if _, err := svc.Create(managerCtx, model.EvaluationCreateRequest{EmployeeID: outsiderEmpID}, managerUserID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for outsider, got %v", err)
+	}
+	other, err := svc.Create(actorCtx(uuid.New(), model.RoleAdmin), model.EvaluationCreateRequest{EmployeeID: outsiderEmpID}, uuid.New())
+	if err != nil {
+		t.Fatalf("Admin create failed: %v", err)
+	}
+	if _, err := svc.FindByID(managerCtx, other.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on read of outsider evaluation, got %v", err)
+	}
+	if _, err := svc.Submit(managerCtx, other.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on submit of outsider evaluation, got %v", err)
+	}
+
+	list, total, err := svc.FindAll(managerCtx, 1, 20, "", "")
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if total != 1 || len(list) != 1 || list[0].ID != own.ID {
+		t.Errorf("Expected only the direct report's evaluation, got %+v", list)
+	}
+}
+
+func TestOneOnOneService_TeamScope(t *testing.T) {
+	deps, repos := setupHRServiceDeps(t)
+	svc := NewOneOnOneService(deps)
+
+	managerUserID, reportUserID, outsiderUserID := uuid.New(), uuid.New(), uuid.New()
+	managerEmpID, reportEmpID, outsiderEmpID := uuid.New(), uuid.New(), uuid.New()
+	repos.hrEmployee.items[managerEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: managerEmpID}, UserID: &managerUserID}
+	repos.hrEmployee.items[reportEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: reportEmpID}, UserID: &reportUserID, ManagerID: &managerEmpID}
+	repos.hrEmployee.items[outsiderEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: outsiderEmpID}, UserID: &outsiderUserID}
+
+	managerCtx := actorCtx(managerUserID, model.RoleManager)
+	if _, err := svc.Create(managerCtx, model.OneOnOneCreateRequest{EmployeeID: outsiderEmpID.String(), ScheduledDate: "2025-04-01"}, managerUserID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for outsider, got %v", err)
+	}
+	if _, err := svc.Create(managerCtx, model.OneOnOneCreateRequest{EmployeeID: reportEmpID.String(), ScheduledDate: "2025-04-01"}, managerUserID); err != nil {
+		t.Fatalf("Manager create for direct report failed: %v", err)
+	}
+	other, err := svc.Create(actorCtx(uuid.New(), model.RoleAdmin), model.OneOnOneCreateRequest{EmployeeID: outsiderEmpID.String(), ScheduledDate: "2025-04-01"}, uuid.New())
+	if err != nil {
+		t.Fatalf("Admin create failed: %v", err)
+	}
+	if err := svc.Delete(managerCtx, other.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden on delete of outsider meeting, got %v", err)
+	}
+
+	list, err := svc.FindAll(managerCtx, "", "")
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(list) != 1 || list[0].EmployeeID != reportEmpID {
+		t.Errorf("Expected only the direct report's meeting, got %+v", list)
+	}
+}
+
+func TestHREmployeeService_SalaryRequiresSalaryRead(t *testing.T) {
+	deps, repos := setupHRServiceDeps(t)
+	svc := NewHREmployeeService(deps)
+
+	id := uuid.New()
+	repos.hrEmployee.items[id] = &model.HREmployee{BaseModel: model.BaseModel{ID: id}, BaseSalary: 400000}
+
+	got, err := svc.FindByID(actorCtx(uuid.New(), model.RoleAdmin), id)
+	if err != nil || got.BaseSalary != 400000 {
+		t.Fatalf("Expected salary for admin, got %v (err=%v)", got, err)
+	}
+	list, _, err := svc.FindAll(actorCtx(uuid.New(), model.RoleManager), 1, 20, "", "", "", "")
+	if err != nil {
+		t.Fatalf("FindAll failed: %v", err)
+	}
+	if len(list) != 1 || list[0].BaseSalary != 0 {
+		t.Errorf("Expected salary to be redacted for manager, got %+v", list)
+	}
+	got, err = svc.FindByID(actorCtx(uuid.New(), model.RoleManager), id)
+	if err != nil || got.BaseSalary != 0 {
+		t.Errorf("Expected salary to be redacted for manager, got %v (err=%v)", got, err)
+	}
+}
+
+func TestGoalService_RequiresHREmployee(t *testing.T) {
+	deps, _ := setupHRServiceDeps(t)
+	svc := NewGoalService(deps)
+
+	// 人事従業員として登録されていないIDはユーザーIDとして扱わない
+	userID := uuid.New()
+	req := model.HRGoalCreateRequest{Title: "売上目標", EmployeeID: &userID}
+	if _, err := svc.Create(actorCtx(userID, model.RoleEmployee), req, userID); !errors.Is(err, apphr.ErrEmployeeNotFound) {
+		t.Errorf("Expected ErrEmployeeNotFound, got %v", err)
+	}
+	if _, err := svc.Create(actorCtx(userID, model.RoleEmployee), model.HRGoalCreateRequest{Title: "売上目標"}, userID); !errors.Is(err, apphr.ErrEmployeeNotFound) {
+		t.Errorf("Expected ErrEmployeeNotFound without an HR record, got %v", err)
+	}
+}
@@ -0,0 +1,185 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// seedOpenAttendance は退勤打刻の無い勤怠を登録する
+func seedOpenAttendance(deps Deps, userID uuid.UUID, clockIn time.Time) *model.Attendance {
+	id := uuid.New()
+	att := &model.Attendance{
+		BaseModel: model.BaseModel{ID: id}, UserID: userID,
+		Date:    time.Date(clockIn.Year(), clockIn.Month(), clockIn.Day(), 0, 0, 0, 0, time.Local),
+		ClockIn: &clockIn, Status: model.AttendanceStatusPresent,
+	}
+	deps.Repos.Attendance.(*mocks.MockAttendanceRepository).Attendances[id] = att
+	return att
+}
+
+func TestAttendanceService_CloseOpenAttendances_MarksIncomplete(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.AttendanceAutoCloseMode = "incomplete"
+	ctx := context.Background()
+	userID := uuid.New()
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+
+	yesterday := time.Now().Truncate(24*time.Hour).AddDate(0, 0, -1).Add(9 * time.Hour)
+	open := seedOpenAttendance(deps, userID, yesterday)
+
+	svc := NewAttendanceService(deps)
+	closed, err := svc.CloseOpenAttendances(ctx, time.Now())
+	if err != nil {
+		t.Fatalf("CloseOpenAttendances failed: %v", err)
+	}
+	if closed != 1 {
+		t.Errorf("Expected 1 record to be processed, got %d", closed)
+	}
+	got := attRepo.Attendances[open.ID]
+	if got.Status != model.AttendanceStatusIncomplete || got.ClockOut != nil {
+		t.Errorf("Expected incomplete record without clock-out, got status=%s clockOut=%v", got.Status, got.ClockOut)
+	}
+
+	// 打刻漏れがあっても当日の出勤打刻はできる
+	if _, err := svc.ClockIn(ctx, userID, &model.ClockInRequest{}); err != nil {
+		t.Errorf("Expected next-day clock-in to succeed, got %v", err)
+	}
+	if closed, _ := svc.CloseOpenAttendances(ctx, time.Now()); closed != 0 {
+		t.Errorf("Expected today's open record to be left alone, got %d processed", closed)
+	}
+}
+
+func TestAttendanceService_CloseOpenAttendances_ShiftEnd(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.AttendanceAutoCloseMode = "shift_end"
+	ctx := context.Background()
+	userID := uuid.New()
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+
+	// 2024-01-15（月）9:00出勤、シフトは17:30まで
+	withShift := seedOpenAttendance(deps, userID, time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local))
+	endTime := time.Date(0, 1, 1, 17, 30, 0, 0, time.UTC)
+	shiftID := uuid.New()
+	deps.Repos.Shift.(*mocks.MockShiftRepository).Shifts[shiftID] = &model.Shift{
+		BaseModel: model.BaseModel{ID: shiftID}, UserID: userID, Date: withShift.Date,
+		ShiftType: model.ShiftTypeDay, EndTime: &endTime,
+	}
+	// 2024-01-16（火）はシフト無し → 所定労働時間（480分）で締める
+	withoutShift := seedOpenAttendance(deps, userID, time.Date(2024, 1, 16, 10, 0, 0, 0, time.Local))
+
+	now := time.Now()
+	closed, err := NewAttendanceService(deps).CloseOpenAttendances(ctx, now)
+	if err != nil {
+		t.Fatalf("CloseOpenAttendances failed: %v", err)
+	}
+	if closed != 2 {
+		t.Fatalf("Expected 2 records to be closed, got %d", closed)
+	}
+
+	got := attRepo.Attendances[withShift.ID]
+	if got.ClockOut == nil || got.ClockOut.Format("15:04") != "17:30" {
+		t.Errorf("Expected clock-out at shift end 17:30, got %v", got.ClockOut)
+	}
+	if got.WorkMinutes != 510 || got.AutoClosedAt == nil || got.Status != model.AttendanceStatusPresent {
+		t.Errorf("Expected 510 work minutes on an auto-closed record, got %d (auto_closed_at=%v)", got.WorkMinutes, got.AutoClosedAt)
+	}
+
+	got = attRepo.Attendances[withoutShift.ID]
+	if got.ClockOut == nil || got.ClockOut.Format("15:04") != "18:00" || got.WorkMinutes != 480 {
+		t.Errorf("Expected fallback clock-out at 18:00 with 480 minutes, got %v / %d", got.ClockOut, got.WorkMinutes)
+	}
+}
+
+func TestAttendanceService_CloseOpenAttendances_SkipsWhenLocked(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.AttendanceAutoCloseMode = "incomplete"
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	attRepo.AutoCloseLocked = true
+	open := seedOpenAttendance(deps, uuid.New(), time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local))
+
+	closed, err := NewAttendanceService(deps).CloseOpenAttendances(context.Background(), time.Now())
+	if err != nil || closed != 0 {
+		t.Errorf("Expected nothing to be processed while another instance holds the lock, got %d (%v)", closed, err)
+	}
+	if attRepo.Attendances[open.ID].Status != model.AttendanceStatusPresent {
+		t.Error("Expected the open record to be left for the lock holder")
+	}
+}
+
+func TestAttendanceService_CloseOpenAttendances_UsesLocalDate(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.AttendanceAutoCloseMode = "incomplete"
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	deps.Config.Location = tokyo
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+
+	// 日付カラムは UTC の0時で読み込まれる。2024-01-16 00:30 JST（UTC では 15日）は16日として扱う
+	clockIn := time.Date(2024, 1, 15, 9, 0, 0, 0, tokyo)
+	id := uuid.New()
+	attRepo.Attendances[id] = &model.Attendance{
+		BaseModel: model.BaseModel{ID: id}, UserID: uuid.New(),
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), ClockIn: &clockIn, Status: model.AttendanceStatusPresent,
+	}
+	closed, err := NewAttendanceService(deps).CloseOpenAttendances(context.Background(), time.Date(2024, 1, 16, 0, 30, 0, 0, tokyo))
+	if err != nil || closed != 1 {
+		t.Errorf("Expected the previous local day to be processed after local midnight, got %d (%v)", closed, err)
+	}
+}
+
+func TestAttendanceService_CloseOpenAttendances_Off(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.AttendanceAutoCloseMode = "off"
+	seedOpenAttendance(deps, uuid.New(), time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local))
+
+	closed, err := NewAttendanceService(deps).CloseOpenAttendances(context.Background(), time.Now())
+	if err != nil || closed != 0 {
+		t.Errorf("Expected nothing to be processed, got %d (%v)", closed, err)
+	}
+}
+
+func TestAttendanceCorrectionService_Approve_IncompleteRequiresClockOut(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	ctx := middleware.WithSystem(context.Background())
+	userID := uuid.New()
+	att := seedOpenAttendance(deps, userID, time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local))
+	att.Status = model.AttendanceStatusIncomplete
+
+	acRepo := deps.Repos.AttendanceCorrection.(*mockAttendanceCorrectionRepo)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+	approval := &model.AttendanceCorrectionApproval{Status: model.CorrectionStatusApproved}
+
+	noteOnly := uuid.New()
+	note := "打刻忘れ"
+	acRepo.corrections[noteOnly] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: noteOnly}, UserID: userID, AttendanceID: &att.ID,
+		Date: att.Date, Status: model.CorrectionStatusPending, CorrectedNote: &note,
+	}
+	var verr *model.ValidationError
+	if _, err := svc.Approve(ctx, noteOnly, uuid.New(), approval); !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error without clock-out, got %v", err)
+	}
+
+	withClockOut := uuid.New()
+	out := time.Date(2024, 1, 15, 18, 0, 0, 0, time.Local)
+	acRepo.corrections[withClockOut] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: withClockOut}, UserID: userID, AttendanceID: &att.ID,
+		Date: att.Date, Status: model.CorrectionStatusPending, CorrectedClockOut: &out,
+	}
+	if _, err := svc.Approve(ctx, withClockOut, uuid.New(), approval); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	applied := acRepo.applied[att.ID]
+	if applied.Status != model.AttendanceStatusPresent || applied.WorkMinutes != 540 {
+		t.Errorf("Expected present record with 540 minutes, got %s / %d", applied.Status, applied.WorkMinutes)
+	}
+}
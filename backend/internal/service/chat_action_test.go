@@ -0,0 +1,469 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/notify"
+	"github.com/your-org/kintai/backend/internal/oidc"
+	"github.com/your-org/kintai/backend/internal/oidc/oidctest"
+	"github.com/your-org/kintai/backend/internal/revocation"
+	"github.com/your-org/kintai/backend/pkg/logger"
+)
+
+// chatWebhookStandIn は Slack の Incoming Webhook と response_url の代わりに投稿を記録するローカルのサーバー
+type chatWebhookStandIn struct {
+	mu       sync.Mutex
+	payloads []json.RawMessage
+	received chan struct{}
+}
+
+func newChatWebhookStandIn(t *testing.T) (*chatWebhookStandIn, *httptest.Server) {
+	s := &chatWebhookStandIn{received: make(chan struct{}, 16)}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		s.payloads = append(s.payloads, body)
+		s.mu.Unlock()
+		s.received <- struct{}{}
+	}))
+	t.Cleanup(srv.Close)
+	return s, srv
+}
+
+func (s *chatWebhookStandIn) next(t *testing.T) json.RawMessage {
+	t.Helper()
+	select {
+	case <-s.received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for a chat post")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.payloads[len(s.payloads)-1]
+}
+
+type chatActionEnv struct {
+	deps     Deps
+	users    *mocks.MockUserRepository
+	leaves   *mocks.MockLeaveRequestRepository
+	team     *mocks.MockTeamRepository
+	prefs    *mocks.MockNotificationPreferenceRepository
+	manager  *model.User
+	employee *model.User
+	svc      *chatActionService
+	leaveSvc LeaveService
+}
+
+func setupChatActionEnv(t *testing.T) *chatActionEnv {
+	deps, _, _, _, _, _, _, _, _ := setupExtendedTestDeps(t)
+	deps.Config.NotificationChatEnabled = true
+	deps.Config.ChatActionSecret = "chat-secret"
+	deps.Config.ChatActionTTLHours = 72
+	deps.Config.SlackSigningSecret = "slack-signing"
+	deps.Config.ChatWebhookAllowedHosts = []string{"127.0.0.1"}
+	deps.Config.NotificationLocale = notify.LocaleJa
+	deps.Config.FrontendURL = "https://kintai.example.com"
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+
+	env := &chatActionEnv{
+		users:  mocks.NewMockUserRepository(),
+		leaves: mocks.NewMockLeaveRequestRepository(),
+		team:   mocks.NewMockTeamRepository(),
+		prefs:  mocks.NewMockNotificationPreferenceRepository(),
+	}
+	deps.Repos.User = env.users
+	deps.Repos.LeaveRequest = env.leaves
+	deps.Repos.Team = env.team
+	deps.Repos.NotificationPref = env.prefs
+
+	env.manager = &model.User{Email: "boss@example.com", FirstName: "一郎", LastName: "佐藤", Role: model.RoleManager, IsActive: true}
+	env.manager.ID = uuid.New()
+	env.employee = &model.User{Email: "taro@example.com", FirstName: "太郎", LastName: "山田", Role: model.RoleEmployee, IsActive: true}
+	env.employee.ID = uuid.New()
+	env.users.Users[env.manager.ID] = env.manager
+	env.users.Users[env.employee.ID] = env.employee
+	env.team.Reports[env.manager.ID] = []uuid.UUID{env.employee.ID}
+	env.linkChat(env.manager.ID, model.ChatProviderSlack, "U-MANAGER")
+
+	env.deps = deps
+	env.leaveSvc = NewLeaveService(deps, &mocks.MockNotificationService{})
+	env.svc = newChatActionService(deps, env.leaveSvc, NewOvertimeRequestService(deps, &mocks.MockNotificationService{}), NewExpenseService(deps))
+	return env
+}
+
+// linkChat はユーザーのチャットの配信先とアカウントを登録する
+func (e *chatActionEnv) linkChat(userID uuid.UUID, provider model.ChatProvider, chatUserID string) {
+	e.prefs.Settings[userID] = &model.NotificationSetting{
+		UserID: userID, ChatProvider: provider, ChatWebhookURL: "http://127.0.0.1:9/hook", ChatUserID: chatUserID,
+	}
+}
+
+func (e *chatActionEnv) pendingLeave() *model.LeaveRequest {
+	leave := &model.LeaveRequest{
+		UserID: e.employee.ID, LeaveType: model.LeaveTypePaid, Status: model.ApprovalStatusPending,
+		StartDate: time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2026, 2, 12, 0, 0, 0, 0, time.UTC),
+	}
+	leave.ID = uuid.New()
+	e.leaves.LeaveRequests[leave.ID] = leave
+	return leave
+}
+
+func (e *chatActionEnv) token(t *testing.T, kind model.ApprovalKind, id, approver uuid.UUID, decision notify.ActionDecision) string {
+	t.Helper()
+	token, err := notify.NewActionSigner(e.deps.Config.ChatActionSecret, time.Hour).Sign(notify.ActionClaims{
+		Kind: kind, ID: id, Decision: decision, Approver: approver,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+// slackRequest は Slack と同じ形式で署名したボタン押下のリクエストを作成する
+func slackRequest(secret string, now time.Time, payload any) (timestamp, signature string, body []byte) {
+	raw, _ := json.Marshal(payload)
+	body = []byte(url.Values{"payload": {string(raw)}}.Encode())
+	timestamp = strconv.FormatInt(now.Unix(), 10)
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte("v0:" + timestamp + ":"))
+	h.Write(body)
+	return timestamp, "v0=" + hex.EncodeToString(h.Sum(nil)), body
+}
+
+func TestChatActionService_HandleAction_ApprovesLeave(t *testing.T) {
+	env := setupChatActionEnv(t)
+	leave := env.pendingLeave()
+
+	msg, err := env.svc.handleAction(context.Background(), env.token(t, model.ApprovalKindLeave, leave.ID, env.manager.ID, notify.DecisionApprove),
+		model.ChatProviderSlack, "U-MANAGER")
+	if err != nil {
+		t.Fatalf("handleAction failed: %v", err)
+	}
+	if msg != "山田 太郎 さんの休暇申請（2026-02-10〜2026-02-12）を承認しました" {
+		t.Errorf("Unexpected message: %s", msg)
+	}
+	if leave.Status != model.ApprovalStatusApproved || leave.ApprovedBy == nil || *leave.ApprovedBy != env.manager.ID {
+		t.Errorf("Expected the leave to be approved by the manager, got %+v", leave)
+	}
+
+	// 別のボタンを押しても二重に処理しない
+	_, err = env.svc.handleAction(context.Background(), env.token(t, model.ApprovalKindLeave, leave.ID, env.manager.ID, notify.DecisionReject),
+		model.ChatProviderSlack, "U-MANAGER")
+	if !errors.Is(err, ErrChatActionProcessed) {
+		t.Errorf("Expected ErrChatActionProcessed, got %v", err)
+	}
+}
+
+func TestChatActionService_HandleAction_Rejections(t *testing.T) {
+	env := setupChatActionEnv(t)
+	leave := env.pendingLeave()
+	ctx := context.Background()
+
+	other := &model.User{FirstName: "花子", LastName: "鈴木", Role: model.RoleManager, IsActive: true}
+	other.ID = uuid.New()
+	env.users.Users[other.ID] = other
+	env.linkChat(other.ID, model.ChatProviderSlack, "U-OTHER")
+	env.linkChat(env.employee.ID, model.ChatProviderSlack, "U-EMPLOYEE")
+	approve := env.token(t, model.ApprovalKindLeave, leave.ID, env.manager.ID, notify.DecisionApprove)
+
+	cases := []struct {
+		name     string
+		token    string
+		provider model.ChatProvider
+		clicker  string
+		want     error
+	}{
+		{"tampered", approve + "x", model.ChatProviderSlack, "U-MANAGER", ErrInvalidChatAction},
+		{"unknown approver", env.token(t, model.ApprovalKindLeave, leave.ID, uuid.New(), notify.DecisionApprove), model.ChatProviderSlack, "U-MANAGER", ErrInvalidChatAction},
+		{"clicked by someone else", approve, model.ChatProviderSlack, "U-OTHER", ErrChatAccountMismatch},
+		{"clicked without an account", approve, model.ChatProviderSlack, "", ErrChatAccountMismatch},
+		{"other provider", approve, model.ChatProviderTeams, "U-MANAGER", ErrChatAccountMismatch},
+		{"employee", env.token(t, model.ApprovalKindLeave, leave.ID, env.employee.ID, notify.DecisionApprove), model.ChatProviderSlack, "U-EMPLOYEE", ErrForbidden},
+		{"not direct report", env.token(t, model.ApprovalKindLeave, leave.ID, other.ID, notify.DecisionApprove), model.ChatProviderSlack, "U-OTHER", ErrNotDirectReport},
+		{"missing request", env.token(t, model.ApprovalKindLeave, uuid.New(), env.manager.ID, notify.DecisionApprove), model.ChatProviderSlack, "U-MANAGER", ErrChatActionTargetNotFound},
+	}
+	for _, tc := range cases {
+		if _, err := env.svc.handleAction(ctx, tc.token, tc.provider, tc.clicker); !errors.Is(err, tc.want) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.want, err)
+		}
+	}
+	if leave.Status != model.ApprovalStatusPending {
+		t.Errorf("Expected the leave to stay pending, got %s", leave.Status)
+	}
+
+	// 別の人が押してもトークンは使用済みにならず、承認者本人は一度だけ使える
+	if _, err := env.svc.handleAction(ctx, approve, model.ChatProviderSlack, "U-MANAGER"); err != nil {
+		t.Fatalf("Expected the approver's first click to succeed, got %v", err)
+	}
+	leave.Status = model.ApprovalStatusPending
+	if _, err := env.svc.handleAction(ctx, approve, model.ChatProviderSlack, "U-MANAGER"); !errors.Is(err, ErrChatActionUsed) {
+		t.Errorf("Expected ErrChatActionUsed for a reused token, got %v", err)
+	}
+
+	disabled := newChatActionService(Deps{Config: &config.Config{}}, nil, nil, nil)
+	if _, err := disabled.HandleTeamsAction(ctx, "Bearer x", "token"); !errors.Is(err, ErrChatActionsDisabled) {
+		t.Errorf("Expected ErrChatActionsDisabled, got %v", err)
+	}
+}
+
+func TestChatActionService_HandleAction_RejectsExpense(t *testing.T) {
+	env := newExpTestEnv()
+	env.deps.Config = &config.Config{NotificationChatEnabled: true, ChatActionSecret: "chat-secret", ChatActionTTLHours: 72}
+	env.deps.Logger, _ = logger.NewLogger("debug", "test")
+	approver := &model.User{FirstName: "一郎", LastName: "佐藤", Role: model.RoleAdmin, IsActive: true}
+	approver.ID = uuid.New()
+	owner := &model.User{FirstName: "太郎", LastName: "山田", Role: model.RoleEmployee, IsActive: true}
+	owner.ID = uuid.New()
+	env.userRepo.users[approver.ID] = approver
+	env.userRepo.users[owner.ID] = owner
+	expenseID := uuid.New()
+	env.expenseRepo.expenses[expenseID] = &model.Expense{
+		BaseModel: model.BaseModel{ID: expenseID}, UserID: owner.ID, Title: "出張", Status: model.ExpenseStatusPending,
+	}
+	prefs := mocks.NewMockNotificationPreferenceRepository()
+	prefs.Settings[approver.ID] = &model.NotificationSetting{UserID: approver.ID, ChatProvider: model.ChatProviderTeams, ChatUserID: "aad-approver"}
+	env.deps.Repos.NotificationPref = prefs
+	svc := newChatActionService(env.deps, nil, nil, NewExpenseService(env.deps))
+	token, _ := notify.NewActionSigner("chat-secret", time.Hour).Sign(notify.ActionClaims{
+		Kind: model.ApprovalKindExpense, ID: expenseID, Decision: notify.DecisionReject, Approver: approver.ID,
+	})
+
+	msg, err := svc.handleAction(context.Background(), token, model.ChatProviderTeams, "aad-approver")
+	if err != nil {
+		t.Fatalf("handleAction failed: %v", err)
+	}
+	if msg != "山田 太郎 さんの経費申請「出張」を却下しました" || env.expenseRepo.expenses[expenseID].Status != model.ExpenseStatusRejected {
+		t.Errorf("Unexpected result: %s / %s", msg, env.expenseRepo.expenses[expenseID].Status)
+	}
+}
+
+func TestChatActionService_HandleSlackAction(t *testing.T) {
+	env := setupChatActionEnv(t)
+	leave := env.pendingLeave()
+	responses, responseSrv := newChatWebhookStandIn(t)
+	now := time.Now()
+	env.svc.now = func() time.Time { return now }
+
+	reject := env.token(t, model.ApprovalKindLeave, leave.ID, env.manager.ID, notify.DecisionReject)
+	payload := map[string]any{
+		"type":         "block_actions",
+		"response_url": responseSrv.URL + "/actions/T1/1/abc",
+		"user":         map[string]string{"id": "U-MANAGER"},
+		"actions": []map[string]string{
+			{"action_id": "reject", "value": reject},
+		},
+	}
+	ts, sig, body := slackRequest("slack-signing", now, payload)
+
+	if _, err := env.svc.HandleSlackAction(context.Background(), ts, "v0=deadbeef", body); !errors.Is(err, ErrInvalidSlackRequest) {
+		t.Fatalf("Expected ErrInvalidSlackRequest for a bad signature, got %v", err)
+	}
+	if leave.Status != model.ApprovalStatusPending {
+		t.Fatal("Expected an unsigned request not to change the leave")
+	}
+
+	// 転送されたメッセージなどで承認者以外が押した場合は処理しない
+	payload["user"] = map[string]string{"id": "U-SOMEONE"}
+	forwardedTS, forwardedSig, forwarded := slackRequest("slack-signing", now, payload)
+	msg, err := env.svc.HandleSlackAction(context.Background(), forwardedTS, forwardedSig, forwarded)
+	if err != nil || msg != ErrChatAccountMismatch.Error() || leave.Status != model.ApprovalStatusPending {
+		t.Fatalf("Expected a mismatch message, got %q, %v, %s", msg, err, leave.Status)
+	}
+	responses.next(t)
+
+	msg, err = env.svc.HandleSlackAction(context.Background(), ts, sig, body)
+	if err != nil {
+		t.Fatalf("HandleSlackAction failed: %v", err)
+	}
+	if leave.Status != model.ApprovalStatusRejected || !strings.HasSuffix(msg, "を却下しました") {
+		t.Errorf("Expected the leave to be rejected, got %s / %s", leave.Status, msg)
+	}
+	var reply struct {
+		ReplaceOriginal bool   `json:"replace_original"`
+		Text            string `json:"text"`
+	}
+	_ = json.Unmarshal(responses.next(t), &reply)
+	if !reply.ReplaceOriginal || reply.Text != msg {
+		t.Errorf("Unexpected response_url reply: %+v", reply)
+	}
+
+	// 使用済みのボタンは失敗の旨を返信し、Slack には 200 を返す
+	msg, err = env.svc.HandleSlackAction(context.Background(), ts, sig, body)
+	if err != nil || msg != ErrChatActionUsed.Error() {
+		t.Errorf("Expected a used message, got %q, %v", msg, err)
+	}
+	_ = json.Unmarshal(responses.next(t), &reply)
+	if reply.Text != ErrChatActionUsed.Error() {
+		t.Errorf("Unexpected response_url reply: %+v", reply)
+	}
+}
+
+func TestChatActionService_HandleTeamsAction(t *testing.T) {
+	env := setupChatActionEnv(t)
+	leave := env.pendingLeave()
+	env.linkChat(env.manager.ID, model.ChatProviderTeams, "aad-manager")
+	idp, err := oidctest.NewServer("teams", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer idp.Close()
+	env.svc.teams = notify.NewTeamsActionVerifier("https://kintai.example.com/api/v1/integrations/teams/actions",
+		idp.Issuer(), oidc.NewKeySet(idp.URL+"/jwks", nil))
+	bearer := func(sub string) string {
+		token, err := idp.Sign(jwt.MapClaims{
+			"iss": idp.Issuer(), "aud": "https://kintai.example.com", "sub": sub, "exp": time.Now().Add(5 * time.Minute).Unix(),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return "Bearer " + token
+	}
+	approve := env.token(t, model.ApprovalKindLeave, leave.ID, env.manager.ID, notify.DecisionApprove)
+	ctx := context.Background()
+
+	if _, err := env.svc.HandleTeamsAction(ctx, "", approve); !errors.Is(err, ErrInvalidChatAction) {
+		t.Errorf("Expected ErrInvalidChatAction without the Teams token, got %v", err)
+	}
+	if _, err := env.svc.HandleTeamsAction(ctx, bearer("aad-someone"), approve); !errors.Is(err, ErrChatAccountMismatch) {
+		t.Errorf("Expected ErrChatAccountMismatch for another user, got %v", err)
+	}
+	if leave.Status != model.ApprovalStatusPending {
+		t.Fatalf("Expected the leave to stay pending, got %s", leave.Status)
+	}
+	msg, err := env.svc.HandleTeamsAction(ctx, bearer("aad-manager"), approve)
+	if err != nil || leave.Status != model.ApprovalStatusApproved || !strings.HasSuffix(msg, "を承認しました") {
+		t.Errorf("Expected the leave to be approved, got %q, %v, %s", msg, err, leave.Status)
+	}
+}
+
+// 休暇申請から上長への承認依頼の投稿、ボタンの押下による承認までを通しで確認する
+func TestChatApproval_LeaveRequestRoundTrip(t *testing.T) {
+	env := setupChatActionEnv(t)
+	webhook, webhookSrv := newChatWebhookStandIn(t)
+	env.prefs.Settings[env.manager.ID] = &model.NotificationSetting{
+		UserID: env.manager.ID, ChatProvider: model.ChatProviderSlack, ChatWebhookURL: webhookSrv.URL, ChatUserID: "U-MANAGER",
+	}
+	// チャットは種別ごとに有効にした通知のみ配信する
+	env.prefs.Prefs = []model.NotificationPreference{
+		{UserID: env.manager.ID, Type: model.NotificationTypeLeaveRequested, Channel: model.NotificationChannelChat, Enabled: true},
+	}
+	queue, err := notify.New(env.deps.Config, nil, env.deps.Logger)
+	if err != nil {
+		t.Fatalf("notify.New failed: %v", err)
+	}
+	defer queue.Close()
+	env.deps.Notifier = queue
+	leaveSvc := NewLeaveService(env.deps, NewNotificationService(env.deps))
+	ctx := context.Background()
+
+	leave, err := leaveSvc.Create(ctx, env.employee.ID, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-12", Reason: "帰省",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	var posted struct {
+		Text   string `json:"text"`
+		Blocks []struct {
+			Elements []struct {
+				ActionID string `json:"action_id"`
+				Value    string `json:"value"`
+			} `json:"elements"`
+		} `json:"blocks"`
+	}
+	if err := json.Unmarshal(webhook.next(t), &posted); err != nil {
+		t.Fatal(err)
+	}
+	if posted.Text != "山田 太郎 さんの休暇申請" || len(posted.Blocks) != 2 || posted.Blocks[1].Elements[0].ActionID != "approve" {
+		t.Fatalf("Unexpected approval request: %+v", posted)
+	}
+
+	svc := newChatActionService(env.deps, leaveSvc, nil, nil)
+	if _, err := svc.handleAction(ctx, posted.Blocks[1].Elements[0].Value, model.ChatProviderSlack, "U-MANAGER"); err != nil {
+		t.Fatalf("handleAction failed: %v", err)
+	}
+	if got := env.leaves.LeaveRequests[leave.ID]; got.Status != model.ApprovalStatusApproved {
+		t.Errorf("Expected the leave to be approved, got %s", got.Status)
+	}
+}
+
+func TestNotificationService_Dispatch_ChatRequiresDestination(t *testing.T) {
+	env := setupChatActionEnv(t)
+	webhook, webhookSrv := newChatWebhookStandIn(t)
+	queue, _ := notify.New(env.deps.Config, nil, env.deps.Logger)
+	env.deps.Notifier = queue
+	svc := NewNotificationService(env.deps)
+	ctx := context.Background()
+	for _, id := range []uuid.UUID{env.employee.ID, env.manager.ID} {
+		env.prefs.Prefs = append(env.prefs.Prefs, model.NotificationPreference{
+			UserID: id, Type: model.NotificationTypeLeaveApproved, Channel: model.NotificationChannelChat, Enabled: true,
+		})
+	}
+
+	// 配信先を登録していないユーザーにはチャットで配信しない
+	if err := svc.Send(ctx, env.employee.ID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "本文"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	env.prefs.Settings[env.manager.ID] = &model.NotificationSetting{
+		UserID: env.manager.ID, ChatProvider: model.ChatProviderTeams, ChatWebhookURL: webhookSrv.URL,
+	}
+	if err := svc.Send(ctx, env.manager.ID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "本文"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	queue.Close()
+
+	if len(webhook.payloads) != 1 || !strings.Contains(string(webhook.payloads[0]), "MessageCard") {
+		t.Errorf("Expected exactly one Teams card, got %d", len(webhook.payloads))
+	}
+}
+
+func TestNotificationService_UpdatePreferences_Chat(t *testing.T) {
+	env := setupChatActionEnv(t)
+	svc := NewNotificationService(env.deps)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	resp, err := svc.UpdatePreferences(ctx, userID, &model.NotificationPreferencesRequest{
+		Chat: &model.NotificationChatDestination{Provider: model.ChatProviderSlack, WebhookURL: "http://127.0.0.1:9999/hook", UserID: "U012AB3CD"},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if !resp.Chat.Configured || resp.Chat.Provider != model.ChatProviderSlack || resp.Chat.UserID != "U012AB3CD" {
+		t.Errorf("Unexpected chat status: %+v", resp.Chat)
+	}
+
+	_, err = svc.UpdatePreferences(ctx, userID, &model.NotificationPreferencesRequest{
+		Chat: &model.NotificationChatDestination{Provider: "discord", WebhookURL: "https://evil.example.com/hook"},
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Errorf("Expected 2 validation errors, got %v", err)
+	}
+
+	// 空の URL で登録を解除する
+	resp, err = svc.UpdatePreferences(ctx, userID, &model.NotificationPreferencesRequest{Chat: &model.NotificationChatDestination{}})
+	if err != nil || resp.Chat.Configured || env.prefs.Settings[userID].ChatUserID != "" {
+		t.Errorf("Expected the chat destination to be cleared, got %+v, %v", resp, err)
+	}
+}
@@ -28,9 +28,12 @@ func toExpenseDeps(deps Deps) appexpense.Deps {
 			ExpenseApprovalFlow:        deps.Repos.ExpenseApprovalFlow,
 			ExpenseDelegate:            deps.Repos.ExpenseDelegate,
 			ExpensePolicyViolation:     deps.Repos.ExpensePolicyViolation,
+			Team:                       deps.Repos.Team,
 		},
-		Config: deps.Config,
-		Logger: deps.Logger,
+		Config:   deps.Config,
+		Logger:   deps.Logger,
+		Notifier: deps.notifications,
+		Events:   deps.Events,
 	}
 }
 
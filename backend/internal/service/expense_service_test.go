@@ -6,11 +6,14 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
 )
@@ -203,6 +206,19 @@ func (m *expTestExpenseRepo) FindPending(ctx context.Context, page, pageSize int
 	return out, int64(len(out)), nil
 }
 
+func (m *expTestExpenseRepo) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.Expense, int64, error) {
+	if m.findPendingErr != nil {
+		return nil, 0, m.findPendingErr
+	}
+	out := make([]model.Expense, 0)
+	for _, e := range m.expenses {
+		if e.Status == model.ExpenseStatusPending && slices.Contains(userIDs, e.UserID) {
+			out = append(out, *e)
+		}
+	}
+	return out, int64(len(out)), nil
+}
+
 func (m *expTestExpenseRepo) FindAll(ctx context.Context, page, pageSize int, status, category string) ([]model.Expense, int64, error) {
 	if m.findAllErr != nil {
 		return nil, 0, m.findAllErr
@@ -501,7 +517,7 @@ func (m *expTestExpenseBudgetRepo) FindAll(ctx context.Context) ([]model.Expense
 }
 
 type expTestExpenseNotificationRepo struct {
-	notifications map[uuid.UUID]*model.ExpenseNotification
+	notifications map[uuid.UUID]*model.Notification
 	createErr     error
 	findErr       error
 	markErr       error
@@ -509,10 +525,10 @@ type expTestExpenseNotificationRepo struct {
 }
 
 func newExpTestExpenseNotificationRepo() *expTestExpenseNotificationRepo {
-	return &expTestExpenseNotificationRepo{notifications: map[uuid.UUID]*model.ExpenseNotification{}}
+	return &expTestExpenseNotificationRepo{notifications: map[uuid.UUID]*model.Notification{}}
 }
 
-func (m *expTestExpenseNotificationRepo) Create(ctx context.Context, notification *model.ExpenseNotification) error {
+func (m *expTestExpenseNotificationRepo) Create(ctx context.Context, notification *model.Notification) error {
 	if m.createErr != nil {
 		return m.createErr
 	}
@@ -523,11 +539,11 @@ func (m *expTestExpenseNotificationRepo) Create(ctx context.Context, notificatio
 	return nil
 }
 
-func (m *expTestExpenseNotificationRepo) FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.ExpenseNotification, error) {
+func (m *expTestExpenseNotificationRepo) FindByUserID(ctx context.Context, userID uuid.UUID, filter string) ([]model.Notification, error) {
 	if m.findErr != nil {
 		return nil, m.findErr
 	}
-	var out []model.ExpenseNotification
+	var out []model.Notification
 	for _, n := range m.notifications {
 		if n.UserID != userID {
 			continue
@@ -872,8 +888,50 @@ func TestExpenseService_Create(t *testing.T) {
 	})
 }
 
+func TestExpenseService_GetByID_WithoutActor(t *testing.T) {
+	env := newExpTestEnv()
+	svc := NewExpenseService(env.deps)
+	expenseID := uuid.New()
+	env.expenseRepo.expenses[expenseID] = &model.Expense{BaseModel: model.BaseModel{ID: expenseID}, UserID: uuid.New()}
+
+	// 操作主体も内部処理の指定もないコンテキストは拒否する
+	if _, err := svc.GetByID(context.Background(), expenseID); !errors.Is(err, middleware.ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without actor, got %v", err)
+	}
+	if _, err := svc.GetByID(middleware.WithSystem(context.Background()), expenseID); err != nil {
+		t.Errorf("Expected a system context to be allowed, got %v", err)
+	}
+}
+
+func TestExpenseService_GetPending_ScopedToActor(t *testing.T) {
+	env := newExpTestEnv()
+	teamRepo := mocks.NewMockTeamRepository()
+	env.deps.Repos.Team = teamRepo
+	svc := NewExpenseService(env.deps)
+
+	managerID, reportID, outsiderID := uuid.New(), uuid.New(), uuid.New()
+	teamRepo.Reports[managerID] = []uuid.UUID{reportID}
+	for _, userID := range []uuid.UUID{reportID, outsiderID} {
+		id := uuid.New()
+		env.expenseRepo.expenses[id] = &model.Expense{BaseModel: model.BaseModel{ID: id}, UserID: userID, Status: model.ExpenseStatusPending}
+	}
+
+	manager := middleware.WithActor(context.Background(), middleware.Actor{UserID: managerID, Role: model.RoleManager})
+	pending, _, err := svc.GetPending(manager, 1, 10)
+	if err != nil {
+		t.Fatalf("GetPending failed: %v", err)
+	}
+	if len(pending) != 1 || pending[0].UserID != reportID {
+		t.Errorf("Expected only the direct report's expense, got %+v", pending)
+	}
+	admin := middleware.WithActor(context.Background(), middleware.Actor{UserID: uuid.New(), Role: model.RoleAdmin})
+	if pending, _, _ := svc.GetPending(admin, 1, 10); len(pending) != 2 {
+		t.Errorf("Expected all pending expenses for admin, got %d", len(pending))
+	}
+}
+
 func TestExpenseService_BasicWrappers(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	env := newExpTestEnv()
 	svc := NewExpenseService(env.deps)
 
@@ -924,7 +982,7 @@ func TestExpenseService_BasicWrappers(t *testing.T) {
 }
 
 func TestExpenseService_Update(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("find by id error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1034,7 +1092,7 @@ func TestExpenseService_Update(t *testing.T) {
 }
 
 func TestExpenseService_Approve(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("find error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1085,7 +1143,7 @@ func TestExpenseService_Approve(t *testing.T) {
 			t.Fatalf("expected 1 notification")
 		}
 		for _, n := range env.notifRepo.notifications {
-			if n.Type != "approved" {
+			if n.Type != model.NotificationTypeExpenseApproved || n.ExpenseID == nil || *n.ExpenseID != expenseID {
 				t.Fatalf("unexpected notif type: %s", n.Type)
 			}
 		}
@@ -1123,7 +1181,7 @@ func TestExpenseService_Approve(t *testing.T) {
 			t.Fatalf("unexpected reject state: %+v", e)
 		}
 		for _, n := range env.notifRepo.notifications {
-			if n.Type != "rejected" {
+			if n.Type != model.NotificationTypeExpenseRejected {
 				t.Fatalf("expected rejected notification")
 			}
 		}
@@ -1135,7 +1193,7 @@ func TestExpenseService_Approve(t *testing.T) {
 }
 
 func TestExpenseService_AdvancedApprove(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("find error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1390,7 +1448,7 @@ func TestExpenseService_ExportPDF(t *testing.T) {
 }
 
 func TestExpenseCommentService_GetComments(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("repo error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1445,7 +1503,7 @@ func TestExpenseCommentService_GetComments(t *testing.T) {
 }
 
 func TestExpenseCommentService_AddComment(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("create error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1508,7 +1566,7 @@ func TestExpenseCommentService_AddComment(t *testing.T) {
 }
 
 func TestExpenseHistoryService_GetHistory(t *testing.T) {
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	t.Run("repo error", func(t *testing.T) {
 		env := newExpTestEnv()
@@ -1806,7 +1864,7 @@ func TestExpenseNotificationService(t *testing.T) {
 	t.Run("wrappers", func(t *testing.T) {
 		env := newExpTestEnv()
 		svc := NewExpenseNotificationService(env.deps)
-		env.notifRepo.notifications[notifID] = &model.ExpenseNotification{
+		env.notifRepo.notifications[notifID] = &model.Notification{
 			BaseModel: model.BaseModel{ID: notifID},
 			UserID:    userID,
 		}
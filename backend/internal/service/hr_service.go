@@ -42,8 +42,10 @@ func toHRDeps(deps Deps) apphr.Deps {
 			Offboarding:     deps.Repos.Offboarding,
 			Survey:          deps.Repos.Survey,
 		},
-		Config: deps.Config,
-		Logger: deps.Logger,
+		Config:   deps.Config,
+		Logger:   deps.Logger,
+		Notifier: deps.notifications,
+		Events:   deps.Events,
 	}
 }
 
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 	"gorm.io/datatypes"
 )
@@ -122,7 +123,7 @@ func TestBranch_Evaluation_CreateError(t *testing.T) {
 
 func TestBranch_Evaluation_Update_AllFields(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewEvaluationService(deps)
 
 	id := uuid.New()
@@ -206,7 +207,7 @@ func TestBranch_Evaluation_CreateCycle_Success(t *testing.T) {
 
 func TestBranch_Goal_Create_AllBranches(t *testing.T) {
 	deps, _ := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewGoalService(deps)
 
 	t.Run("with_employee_id_and_all_fields", func(t *testing.T) {
@@ -248,7 +249,7 @@ func TestBranch_Goal_Create_AllBranches(t *testing.T) {
 
 func TestBranch_Goal_Update_AllFields(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewGoalService(deps)
 
 	id := uuid.New()
@@ -305,7 +306,7 @@ func TestBranch_Goal_Update_Errors(t *testing.T) {
 
 func TestBranch_Goal_UpdateProgress_ZeroAndFindError(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewGoalService(deps)
 
 	t.Run("progress_zero_no_status_change", func(t *testing.T) {
@@ -929,7 +930,7 @@ func TestBranch_OrgChart_Simulate_NoMovesOrRenames(t *testing.T) {
 
 func TestBranch_OneOnOne_Create_RFC3339Date(t *testing.T) {
 	deps, _ := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewOneOnOneService(deps)
 
 	empID := uuid.New()
@@ -965,7 +966,7 @@ func TestBranch_OneOnOne_Create_Error(t *testing.T) {
 
 func TestBranch_OneOnOne_Update_Errors(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewOneOnOneService(deps)
 
 	t.Run("find_error", func(t *testing.T) {
@@ -1001,7 +1002,7 @@ func TestBranch_OneOnOne_Update_Errors(t *testing.T) {
 
 func TestBranch_OneOnOne_AddActionItem_NilActions(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewOneOnOneService(deps)
 
 	id := uuid.New()
@@ -1038,7 +1039,7 @@ func TestBranch_OneOnOne_AddActionItem_UpdateError(t *testing.T) {
 
 func TestBranch_OneOnOne_ToggleActionItem_Errors(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 	svc := NewOneOnOneService(deps)
 
 	t.Run("find_error", func(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
@@ -94,6 +95,8 @@ type mockHREmployeeRepo struct {
 	updateErr     error
 	deleteErr     error
 	findByDeptErr error
+	// idsByUserDept はユーザーの所属部署ごとの人事従業員ID
+	idsByUserDept map[uuid.UUID][]uuid.UUID
 	active        int64
 	total         int64
 	useCount      bool
@@ -204,6 +207,33 @@ func (m *mockHREmployeeRepo) CountByStatus(ctx context.Context) (active int64, t
 	return a, int64(len(m.items)), nil
 }
 
+func (m *mockHREmployeeRepo) FindByUserID(ctx context.Context, userID uuid.UUID) (*model.HREmployee, error) {
+	for _, e := range m.items {
+		if e.UserID != nil && *e.UserID == userID {
+			return e, nil
+		}
+	}
+	return nil, errHRNotFound
+}
+
+func (m *mockHREmployeeRepo) FindByManagerID(ctx context.Context, managerID uuid.UUID) ([]model.HREmployee, error) {
+	var out []model.HREmployee
+	for _, e := range m.items {
+		if e.ManagerID != nil && *e.ManagerID == managerID {
+			out = append(out, *e)
+		}
+	}
+	return out, nil
+}
+
+func (m *mockHREmployeeRepo) FindIDsByUserDepartments(ctx context.Context, departmentIDs []uuid.UUID) ([]uuid.UUID, error) {
+	var out []uuid.UUID
+	for _, d := range departmentIDs {
+		out = append(out, m.idsByUserDept[d]...)
+	}
+	return out, nil
+}
+
 var _ repository.HREmployeeRepository = (*mockHREmployeeRepo)(nil)
 
 type mockHRDepartmentRepo struct {
@@ -319,6 +349,22 @@ func (m *mockEvaluationRepo) FindAll(ctx context.Context, page, pageSize int, cy
 	}
 	return out, int64(len(out)), nil
 }
+func (m *mockEvaluationRepo) FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, page, pageSize int, cycleID, status string) ([]model.Evaluation, int64, error) {
+	list, _, err := m.FindAll(ctx, page, pageSize, cycleID, status)
+	if err != nil {
+		return nil, 0, err
+	}
+	var out []model.Evaluation
+	for _, e := range list {
+		for _, id := range employeeIDs {
+			if e.EmployeeID == id {
+				out = append(out, e)
+				break
+			}
+		}
+	}
+	return out, int64(len(out)), nil
+}
 func (m *mockEvaluationRepo) Update(ctx context.Context, e *model.Evaluation) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -787,6 +833,22 @@ func (m *mockOneOnOneRepo) FindAll(ctx context.Context, status, employeeID strin
 	}
 	return out, nil
 }
+func (m *mockOneOnOneRepo) FindAllByEmployeeIDs(ctx context.Context, employeeIDs []uuid.UUID, status, employeeID string) ([]model.OneOnOneMeeting, error) {
+	list, err := m.FindAll(ctx, status, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	var out []model.OneOnOneMeeting
+	for _, v := range list {
+		for _, id := range employeeIDs {
+			if v.EmployeeID == id {
+				out = append(out, v)
+				break
+			}
+		}
+	}
+	return out, nil
+}
 func (m *mockOneOnOneRepo) Update(ctx context.Context, meeting *model.OneOnOneMeeting) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -1286,7 +1348,7 @@ func TestHRService_HREmployeeAndDepartment(t *testing.T) {
 
 func TestHRService_EvaluationGoalTrainingRecruitment(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	evaluationSvc := NewEvaluationService(deps)
 	goalSvc := NewGoalService(deps)
@@ -1325,11 +1387,15 @@ func TestHRService_EvaluationGoalTrainingRecruitment(t *testing.T) {
 	})
 
 	t.Run("goal_create_defaults_and_progress_branches", func(t *testing.T) {
-		userID := uuid.New()
+		userID, selfEmpID := uuid.New(), uuid.New()
+		r.hrEmployee.items[selfEmpID] = &model.HREmployee{BaseModel: model.BaseModel{ID: selfEmpID}, UserID: &userID}
 		g, err := goalSvc.Create(ctx, model.HRGoalCreateRequest{Title: "Goal"}, userID)
 		if err != nil {
 			t.Fatalf("Create goal failed: %v", err)
 		}
+		if g.EmployeeID != selfEmpID {
+			t.Fatalf("goal should default to the user's HR employee record")
+		}
 		if g.Category != model.GoalCategoryPerformance || g.Weight != 1 {
 			t.Fatalf("default category/weight not applied")
 		}
@@ -1685,7 +1751,7 @@ func TestHRService_AttendanceIntegration(t *testing.T) {
 
 func TestHRService_OneOnOneSkillSalary(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	oneOnOneSvc := NewOneOnOneService(deps)
 	skillSvc := NewSkillService(deps)
@@ -1957,7 +2023,7 @@ func TestHRService_OnboardingOffboardingSurvey(t *testing.T) {
 
 func TestHRService_PassThroughMethods(t *testing.T) {
 	deps, r := setupHRServiceDeps(t)
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	employeeSvc := NewHREmployeeService(deps)
 	deptSvc := NewHRDepartmentService(deps)
@@ -2011,7 +2077,7 @@ func TestHRService_PassThroughMethods(t *testing.T) {
 		t.Fatalf("Evaluation FindAllCycles failed: %v", err)
 	}
 
-	goal, err := goalSvc.Create(ctx, model.HRGoalCreateRequest{Title: "Goal X"}, empID)
+	goal, err := goalSvc.Create(ctx, model.HRGoalCreateRequest{Title: "Goal X", EmployeeID: &empID}, uuid.New())
 	if err != nil {
 		t.Fatalf("Goal Create failed: %v", err)
 	}
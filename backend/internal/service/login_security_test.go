@@ -0,0 +1,214 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func clientContext(ip, userAgent string) context.Context {
+	return middleware.WithClientInfo(context.Background(), middleware.ClientInfo{IPAddress: ip, UserAgent: userAgent})
+}
+
+func TestAuthService_Login_LocksAccountProgressively(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Config.LoginMaxFailedAttempts = 3
+	deps.Config.LoginLockoutMinutes = 5
+	deps.Config.LoginLockoutMaxMinutes = 60
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := clientContext("192.0.2.1", "test-agent")
+	wrong := &model.LoginRequest{Email: "rt@example.com", Password: "wrong-password"}
+	lockouts := deps.Repos.LoginLockout.(*mocks.MockLoginLockoutRepository)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.Login(ctx, wrong); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Attempt %d: expected ErrInvalidCredentials, got %v", i+1, err)
+		}
+	}
+	if _, err := svc.Login(ctx, wrong); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Expected ErrAccountLocked on the 3rd failure, got %v", err)
+	}
+	first := lockouts.Lockouts[userID].LockedUntil
+	if first == nil || first.Sub(time.Now()) > 5*time.Minute || first.Sub(time.Now()) < 4*time.Minute {
+		t.Fatalf("Expected 5 minute lock, got %v", first)
+	}
+
+	// ロック中は正しいパスワードでもログインできない
+	if _, err := login(); !errors.Is(err, ErrAccountLocked) {
+		t.Errorf("Expected ErrAccountLocked during lock, got %v", err)
+	}
+
+	// ロックが明けた後に再び上限まで失敗すると、ロック時間が倍になる
+	expired := time.Now().Add(-time.Second)
+	lockouts.Lockouts[userID].LockedUntil = &expired
+	for i := 0; i < 3; i++ {
+		_, _ = svc.Login(ctx, wrong)
+	}
+	second := lockouts.Lockouts[userID].LockedUntil
+	if second == nil || second.Sub(time.Now()) < 9*time.Minute {
+		t.Errorf("Expected the second lock to be about 10 minutes, got %v", second)
+	}
+
+	// 成功すると記録が消える
+	lockouts.Lockouts[userID].LockedUntil = &expired
+	if _, err := login(); err != nil {
+		t.Fatalf("Login after lock expired failed: %v", err)
+	}
+	if _, ok := lockouts.Lockouts[userID]; ok {
+		t.Error("Successful login should clear the lockout")
+	}
+}
+
+func TestAuthService_Login_BlocksIPAfterFailures(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Config.LoginIPMaxFailedAttempts = 2
+	deps.Config.LoginIPWindowMinutes = 15
+	svc, _, _ := mfaTestUser(t, deps)
+	ctx := clientContext("198.51.100.7", "bot")
+
+	for _, email := range []string{"a@example.com", "b@example.com"} {
+		if _, err := svc.Login(ctx, &model.LoginRequest{Email: email, Password: "password123"}); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Expected ErrInvalidCredentials, got %v", err)
+		}
+	}
+	if _, err := svc.Login(ctx, &model.LoginRequest{Email: "rt@example.com", Password: "password123"}); !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Errorf("Expected ErrTooManyLoginAttempts from the blocked IP, got %v", err)
+	}
+	if _, err := svc.Login(clientContext("198.51.100.8", "bot"), &model.LoginRequest{Email: "a@example.com", Password: "password123"}); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Other IP addresses should not be blocked, got %v", err)
+	}
+}
+
+func TestLoginFailureCounter_WindowExpires(t *testing.T) {
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	l := newLoginFailureCounter(1, 15*time.Minute)
+	l.now = func() time.Time { return now }
+
+	l.Fail("192.0.2.1")
+	if !l.Blocked("192.0.2.1") {
+		t.Fatal("Expected the IP to be blocked within the window")
+	}
+	now = now.Add(15 * time.Minute)
+	if l.Blocked("192.0.2.1") {
+		t.Error("Expected the block to expire after the window")
+	}
+}
+
+func TestLockoutDuration(t *testing.T) {
+	cfg := &config.Config{LoginLockoutMinutes: 5, LoginLockoutMaxMinutes: 30}
+	cases := map[int]time.Duration{0: 5 * time.Minute, 1: 10 * time.Minute, 2: 20 * time.Minute, 3: 30 * time.Minute, 10: 30 * time.Minute}
+	for previous, want := range cases {
+		if got := lockoutDuration(cfg, previous); got != want {
+			t.Errorf("lockoutDuration(%d) = %v, want %v", previous, got, want)
+		}
+	}
+}
+
+func TestAuthService_Login_RecordsHistoryAndNotifiesNewDevice(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	notifications := newMockNotificationRepo()
+	deps.Repos.Notification = notifications
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "device@example.com", Role: model.RoleEmployee, IsActive: true}
+	userRepo := deps.Repos.User.(*mocks.MockUserRepository)
+	userRepo.Users[user.ID] = user
+	userRepo.UsersByEmail[user.Email] = user
+	svc := NewAuthService(deps)
+	histories := deps.Repos.LoginHistory.(*mocks.MockLoginHistoryRepository)
+
+	login := func(ip, userAgent, password string) error {
+		user.PasswordHash = string(hash)
+		_, err := svc.Login(clientContext(ip, userAgent), &model.LoginRequest{Email: user.Email, Password: password})
+		return err
+	}
+
+	if err := login("192.0.2.1", "laptop", "wrong-password"); err == nil {
+		t.Fatal("Expected login to fail")
+	}
+	failed := histories.Histories[0]
+	if failed.Success || failed.FailureReason != loginFailureInvalidCredentials || failed.IPAddress != "192.0.2.1" || failed.UserID == nil || *failed.UserID != user.ID {
+		t.Errorf("Unexpected failure record: %+v", failed)
+	}
+
+	// 初回のログインと既知の端末からのログインは通知しない
+	for _, ua := range []string{"laptop", "laptop", "phone"} {
+		if err := login("192.0.2.1", ua, "password123"); err != nil {
+			t.Fatalf("Login from %s failed: %v", ua, err)
+		}
+	}
+	var newDevices []string
+	for _, h := range histories.Histories {
+		if h.NewDevice {
+			newDevices = append(newDevices, h.UserAgent)
+		}
+	}
+	if len(newDevices) != 1 || newDevices[0] != "phone" {
+		t.Errorf("Expected only the phone login to be a new device, got %v", newDevices)
+	}
+	if len(notifications.notifications) != 1 {
+		t.Errorf("Expected one in-app notification, got %d", len(notifications.notifications))
+	}
+	if _, ok := mail.Last(user.Email); !ok || len(mail.Sent()) != 1 {
+		t.Errorf("Expected one new device email, got %d", len(mail.Sent()))
+	}
+
+	records, total, err := NewLoginSecurityService(deps).GetHistory(context.Background(), user.ID, 1, 20)
+	if err != nil || total != 4 || !records[0].Success || records[0].UserAgent != "phone" {
+		t.Errorf("Expected newest-first history of 4 records, got %d records (%v)", total, err)
+	}
+}
+
+func TestAuthService_VerifyMFA_RecordsMethodAndFailures(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+	secret, _, step := enrollMFA(t, deps, userID)
+	histories := deps.Repos.LoginHistory.(*mocks.MockLoginHistoryRepository)
+
+	challenge, err := login()
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	before := len(histories.Histories)
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "000000"}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("Expected ErrInvalidMFACode, got %v", err)
+	}
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: totpCode(t, secret, step+1)}); err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+
+	records := histories.Histories[before:]
+	if len(records) != 2 || records[0].FailureReason != loginFailureInvalidMFACode ||
+		!records[1].Success || !records[1].MFA || records[1].Method != model.LoginMethodPassword {
+		t.Errorf("Unexpected MFA login records: %+v %+v", records[0], records[len(records)-1])
+	}
+}
+
+func TestLoginSecurityService_Unlock(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.Config.LoginMaxFailedAttempts = 1
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+
+	if _, err := svc.Login(ctx, &model.LoginRequest{Email: "rt@example.com", Password: "wrong-password"}); !errors.Is(err, ErrAccountLocked) {
+		t.Fatalf("Expected ErrAccountLocked, got %v", err)
+	}
+	security := NewLoginSecurityService(deps)
+	if err := security.Unlock(ctx, uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	if err := security.Unlock(ctx, userID); err != nil {
+		t.Fatalf("Unlock failed: %v", err)
+	}
+	if _, err := login(); err != nil {
+		t.Errorf("Login after unlock failed: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/totp"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/datatypes"
+)
+
+func totpCode(t *testing.T, secret string, step int64) string {
+	t.Helper()
+	code, err := totp.CodeAt(secret, step)
+	if err != nil {
+		t.Fatalf("CodeAt failed: %v", err)
+	}
+	return code
+}
+
+// mfaTestUser はテストユーザーを作成し、ログインを繰り返せる関数を返す
+// （モックは同じポインタを返すため、ログイン時に消されるパスワードハッシュを戻す）
+func mfaTestUser(t *testing.T, deps Deps) (AuthService, uuid.UUID, func() (*model.TokenResponse, error)) {
+	t.Helper()
+	svc, resp := loginTestUser(t, deps)
+	user := deps.Repos.User.(*mocks.MockUserRepository).UsersByEmail["rt@example.com"]
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	login := func() (*model.TokenResponse, error) {
+		user.PasswordHash = string(hash)
+		return svc.Login(context.Background(), &model.LoginRequest{Email: "rt@example.com", Password: "password123"})
+	}
+	return svc, resp.User.ID, login
+}
+
+// enrollMFA はテストユーザーのMFAを有効にし、秘密鍵・リカバリーコード・使用済みステップを返す
+func enrollMFA(t *testing.T, deps Deps, userID uuid.UUID) (string, []string, int64) {
+	t.Helper()
+	svc := NewMFAService(deps)
+	ctx := context.Background()
+
+	setup, err := svc.Setup(ctx, userID)
+	if err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	step := totp.Step(time.Now())
+	enrollment, err := svc.Enable(ctx, userID, totpCode(t, setup.Secret, step))
+	if err != nil {
+		t.Fatalf("Enable failed: %v", err)
+	}
+	return setup.Secret, enrollment.RecoveryCodes, step
+}
+
+func TestAuthService_Login_RequiresMFAChallenge(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+	secret, _, step := enrollMFA(t, deps, userID)
+
+	challenge, err := login()
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !challenge.MFARequired || challenge.MFAToken == "" || challenge.AccessToken != "" || challenge.RefreshToken != "" {
+		t.Fatalf("Expected MFA challenge without tokens, got %+v", challenge)
+	}
+
+	// 有効化に使ったコードは再利用できない
+	used := totpCode(t, secret, step)
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: used}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("Expected ErrInvalidMFACode for replayed code, got %v", err)
+	}
+
+	code := totpCode(t, secret, step+1)
+	resp, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: code})
+	if err != nil {
+		t.Fatalf("VerifyMFA failed: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" || resp.User == nil {
+		t.Errorf("Expected tokens after MFA, got %+v", resp)
+	}
+
+	// チャレンジは一度しか使えない
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: code}); !errors.Is(err, ErrMFAChallengeInvalid) {
+		t.Errorf("Expected ErrMFAChallengeInvalid on reuse, got %v", err)
+	}
+	// アクセストークンはMFAトークンとして使えない
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: resp.AccessToken, Code: code}); !errors.Is(err, ErrMFAChallengeInvalid) {
+		t.Errorf("Access token must not be accepted as MFA token, got %v", err)
+	}
+}
+
+func TestAuthService_VerifyMFA_RecoveryCodeSingleUse(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+	_, codes, _ := enrollMFA(t, deps, userID)
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("Expected %d recovery codes, got %d", recoveryCodeCount, len(codes))
+	}
+
+	challenge, _ := login()
+	// 区切りや小文字の違いは許容する
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: " " + codes[0][:5] + codes[0][6:] + " "}); err != nil {
+		t.Fatalf("Recovery code should be accepted: %v", err)
+	}
+
+	challenge, _ = login()
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: codes[0]}); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("Used recovery code should be rejected, got %v", err)
+	}
+
+	status, err := NewMFAService(deps).Status(ctx, userID)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if !status.Enabled || status.RecoveryCodesRemaining != int64(recoveryCodeCount-1) {
+		t.Errorf("Unexpected status: %+v", status)
+	}
+}
+
+func TestAuthService_VerifyMFA_AttemptLimit(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+	secret, _, step := enrollMFA(t, deps, userID)
+
+	challenge, _ := login()
+	for i := 0; i < mfaMaxAttempts; i++ {
+		if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "000000"}); err == nil {
+			t.Fatal("Wrong code should be rejected")
+		}
+	}
+	code := totpCode(t, secret, step+1)
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: code}); !errors.Is(err, ErrMFAChallengeInvalid) {
+		t.Errorf("Challenge should be locked after too many attempts, got %v", err)
+	}
+}
+
+func TestAuthService_Login_PolicyForcesMFASetup(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, userID, login := mfaTestUser(t, deps)
+	ctx := context.Background()
+	deps.Repos.MFAPolicy.(*mocks.MockMFAPolicyRepository).Policy = &model.MFAPolicy{
+		RequiredRoles:         datatypes.JSON(`["employee"]`),
+		RequiredCustomRoleIDs: datatypes.JSON(`[]`),
+	}
+
+	challenge, err := login()
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !challenge.MFASetupRequired || challenge.MFARequired || challenge.AccessToken != "" {
+		t.Fatalf("Expected setup challenge, got %+v", challenge)
+	}
+	// 登録用のチャレンジではコード検証に進めない
+	if _, err := svc.VerifyMFA(ctx, &model.MFAVerifyRequest{MFAToken: challenge.MFAToken, Code: "000000"}); !errors.Is(err, ErrMFAChallengeInvalid) {
+		t.Errorf("Setup challenge must not be accepted for verification, got %v", err)
+	}
+
+	setup, err := svc.StartMFASetup(ctx, challenge.MFAToken)
+	if err != nil {
+		t.Fatalf("StartMFASetup failed: %v", err)
+	}
+	enrollment, err := svc.CompleteMFASetup(ctx, &model.MFAVerifyRequest{
+		MFAToken: challenge.MFAToken,
+		Code:     totpCode(t, setup.Secret, totp.Step(time.Now())),
+	})
+	if err != nil {
+		t.Fatalf("CompleteMFASetup failed: %v", err)
+	}
+	if len(enrollment.RecoveryCodes) != recoveryCodeCount || enrollment.TokenResponse == nil || enrollment.AccessToken == "" {
+		t.Errorf("Expected recovery codes and tokens, got %+v", enrollment)
+	}
+
+	if err := NewMFAService(deps).Disable(ctx, userID, enrollment.RecoveryCodes[0]); !errors.Is(err, ErrMFARequired) {
+		t.Errorf("Expected ErrMFARequired, got %v", err)
+	}
+}
+
+func TestMFAService_DisableAndReset(t *testing.T) {
+	deps := setupTestDeps(t)
+	_, userID, login := mfaTestUser(t, deps)
+	mfaSvc := NewMFAService(deps)
+	ctx := context.Background()
+	secret, _, step := enrollMFA(t, deps, userID)
+
+	if _, err := mfaSvc.Setup(ctx, userID); !errors.Is(err, ErrMFAAlreadyEnabled) {
+		t.Errorf("Expected ErrMFAAlreadyEnabled, got %v", err)
+	}
+	if err := mfaSvc.Disable(ctx, userID, "000000"); !errors.Is(err, ErrInvalidMFACode) {
+		t.Errorf("Expected ErrInvalidMFACode, got %v", err)
+	}
+	if err := mfaSvc.Disable(ctx, userID, totpCode(t, secret, step+1)); err != nil {
+		t.Fatalf("Disable failed: %v", err)
+	}
+	resp, err := login()
+	if err != nil || resp.AccessToken == "" {
+		t.Fatalf("Login without MFA should issue tokens: %+v, %v", resp, err)
+	}
+
+	enrollMFA(t, deps, userID)
+	if err := mfaSvc.Reset(ctx, userID); err != nil {
+		t.Fatalf("Reset failed: %v", err)
+	}
+	if codes := deps.Repos.MFARecoveryCode.(*mocks.MockMFARecoveryCodeRepository).Codes; len(codes) != 0 {
+		t.Errorf("Recovery codes should be removed, got %d", len(codes))
+	}
+	if err := mfaSvc.Reset(ctx, uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestMFAService_UpdatePolicy_Validation(t *testing.T) {
+	deps := setupTestDeps(t)
+	roles := mocks.NewMockRoleRepository()
+	deps.Repos.Role = roles
+	svc := NewMFAService(deps)
+	ctx := context.Background()
+
+	role := &model.CustomRole{BaseModel: model.BaseModel{ID: uuid.New()}, Name: "人事"}
+	roles.Roles[role.ID] = role
+
+	_, err := svc.UpdatePolicy(ctx, &model.MFAPolicyRequest{RequiredRoles: []model.Role{"owner"}, RequiredCustomRoleIDs: []uuid.UUID{uuid.New()}})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 2 {
+		t.Fatalf("Expected validation errors for both fields, got %v", err)
+	}
+
+	policy, err := svc.UpdatePolicy(ctx, &model.MFAPolicyRequest{
+		RequiredRoles:         []model.Role{model.RoleAdmin, model.RoleAdmin},
+		RequiredCustomRoleIDs: []uuid.UUID{role.ID},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePolicy failed: %v", err)
+	}
+	if string(policy.RequiredRoles) != `["admin"]` {
+		t.Errorf("Expected deduplicated roles, got %s", policy.RequiredRoles)
+	}
+}
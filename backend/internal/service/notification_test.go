@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/mailer"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/notify"
+)
+
+func setupNotificationDeliveryDeps(t *testing.T) (Deps, *mocks.MockUserRepository, *mailer.MemoryMailer) {
+	deps, _, _, _, _, _, _, _, _ := setupExtendedTestDeps(t)
+	users := mocks.NewMockUserRepository()
+	deps.Repos.User = users
+	deps.Repos.NotificationPref = mocks.NewMockNotificationPreferenceRepository()
+	mail := mailer.NewMemoryMailer()
+	queue, err := notify.New(&config.Config{
+		NotificationEmailEnabled: true,
+		NotificationLocale:       notify.LocaleJa,
+		FrontendURL:              "https://kintai.example.com",
+	}, mail, deps.Logger)
+	if err != nil {
+		t.Fatalf("notify.New failed: %v", err)
+	}
+	deps.Notifier = queue
+	return deps, users, mail
+}
+
+func TestNotificationService_Send_DeliversEmail(t *testing.T) {
+	deps, users, mail := setupNotificationDeliveryDeps(t)
+	user := &model.User{Email: "taro@example.com", FirstName: "太郎", LastName: "山田", IsActive: true}
+	user.ID = uuid.New()
+	users.Users[user.ID] = user
+
+	svc := NewNotificationService(deps)
+	if err := svc.Send(context.Background(), user.ID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "2024/04/01 有給休暇"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	deps.Notifier.Close()
+
+	sent, ok := mail.Last("taro@example.com")
+	if !ok {
+		t.Fatal("Expected a notification email")
+	}
+	if !strings.Contains(sent.Subject, "休暇申請が承認されました") || !strings.Contains(sent.Body, "山田 太郎 様") ||
+		!strings.Contains(sent.Body, "2024/04/01 有給休暇") {
+		t.Errorf("Unexpected email: %s\n%s", sent.Subject, sent.Body)
+	}
+}
+
+func TestNotificationService_Send_SkipsInactiveAndUnknownUsers(t *testing.T) {
+	deps, users, mail := setupNotificationDeliveryDeps(t)
+	inactive := &model.User{Email: "left@example.com", IsActive: false}
+	inactive.ID = uuid.New()
+	users.Users[inactive.ID] = inactive
+
+	svc := NewNotificationService(deps)
+	for _, id := range []uuid.UUID{inactive.ID, uuid.New()} {
+		// アプリ内通知は保存できているため、配信できなくてもエラーにしない
+		if err := svc.Send(context.Background(), id, model.NotificationTypeGeneral, "お知らせ", "本文"); err != nil {
+			t.Fatalf("Send failed: %v", err)
+		}
+	}
+	deps.Notifier.Close()
+
+	if sent := mail.Sent(); len(sent) != 0 {
+		t.Errorf("Expected no emails, got %d", len(sent))
+	}
+}
+
+func TestNotificationService_Dispatch_HonorsPreferences(t *testing.T) {
+	deps, users, mail := setupNotificationDeliveryDeps(t)
+	user := &model.User{Email: "taro@example.com", FirstName: "太郎", LastName: "山田", IsActive: true}
+	user.ID = uuid.New()
+	users.Users[user.ID] = user
+	prefs := deps.Repos.NotificationPref.(*mocks.MockNotificationPreferenceRepository)
+	prefs.Prefs = []model.NotificationPreference{
+		{UserID: user.ID, Type: model.NotificationTypeShiftChanged, Channel: model.NotificationChannelInApp, Enabled: false},
+		{UserID: user.ID, Type: model.NotificationTypeLeaveApproved, Channel: model.NotificationChannelEmail, Enabled: false},
+	}
+	notifications := deps.Repos.Notification.(*mockNotificationRepo)
+
+	svc := NewNotificationService(deps)
+	ctx := context.Background()
+	_ = svc.Send(ctx, user.ID, model.NotificationTypeShiftChanged, "シフトが公開されました", "4月")
+	_ = svc.Send(ctx, user.ID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "4/1")
+	deps.Notifier.Close()
+
+	// シフトはメールのみ、休暇はアプリ内のみ
+	if len(notifications.notifications) != 1 {
+		t.Fatalf("Expected 1 in-app notification, got %d", len(notifications.notifications))
+	}
+	for _, n := range notifications.notifications {
+		if n.Type != model.NotificationTypeLeaveApproved {
+			t.Errorf("Unexpected in-app notification: %s", n.Type)
+		}
+	}
+	sent := mail.Sent()
+	if len(sent) != 1 || !strings.Contains(sent[0].Subject, "シフト") {
+		t.Errorf("Expected only the shift email, got %+v", sent)
+	}
+	if svc.Allows(ctx, user.ID, model.NotificationTypeGeneral, model.NotificationChannelChat) {
+		t.Error("Expected chat to be disabled by default")
+	}
+}
+
+func TestNotificationService_Dispatch_QuietHours(t *testing.T) {
+	deps, users, mail := setupNotificationDeliveryDeps(t)
+	user := &model.User{Email: "taro@example.com", IsActive: true}
+	user.ID = uuid.New()
+	users.Users[user.ID] = user
+	prefs := deps.Repos.NotificationPref.(*mocks.MockNotificationPreferenceRepository)
+	prefs.Settings[user.ID] = &model.NotificationSetting{
+		UserID: user.ID, QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "Asia/Tokyo",
+	}
+
+	jst := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2024, 4, 1, 23, 30, 0, 0, jst)
+	svc := &notificationService{deps: deps, now: func() time.Time { return now }}
+	ctx := context.Background()
+	_ = svc.Send(ctx, user.ID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "4/1")
+	// セキュリティに関する通知はおやすみ時間帯でも配信する
+	_ = svc.Send(ctx, user.ID, model.NotificationTypeNewDeviceLogin, "新しい端末からのログイン", "Chrome")
+
+	// 時間帯の終了間際の通知は、終了後に配信する
+	now = time.Date(2024, 4, 2, 6, 59, 59, 950_000_000, jst)
+	_ = svc.Send(ctx, user.ID, model.NotificationTypeShiftChanged, "シフトが公開されました", "4月")
+	deadline := time.Now().Add(2 * time.Second)
+	for len(mail.Sent()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	deps.Notifier.Close()
+
+	if len(deps.Repos.Notification.(*mockNotificationRepo).notifications) != 3 {
+		t.Error("Expected in-app notifications to be stored during quiet hours")
+	}
+	sent := mail.Sent()
+	if len(sent) != 2 || !strings.Contains(sent[0].Subject, "新しい端末") || !strings.Contains(sent[1].Subject, "シフト") {
+		t.Errorf("Expected the security email and the deferred shift email, got %+v", sent)
+	}
+}
+
+func TestQuietHoursEnd(t *testing.T) {
+	setting := &model.NotificationSetting{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "Asia/Tokyo"}
+	jst := time.FixedZone("JST", 9*60*60)
+	cases := map[string]time.Time{
+		"2024-04-01T23:30:00+09:00": time.Date(2024, 4, 2, 7, 0, 0, 0, jst),
+		"2024-04-02T06:59:00+09:00": time.Date(2024, 4, 2, 7, 0, 0, 0, jst),
+		"2024-03-31T15:30:00Z":      time.Date(2024, 4, 1, 7, 0, 0, 0, jst),
+	}
+	for at, want := range cases {
+		tm, _ := time.Parse(time.RFC3339, at)
+		if got, quiet := quietHoursEnd(setting, tm); !quiet || !got.Equal(want) {
+			t.Errorf("quietHoursEnd(%s) = %s, %v, want %s", at, got, quiet, want)
+		}
+	}
+	if _, quiet := quietHoursEnd(setting, time.Date(2024, 4, 2, 7, 0, 0, 0, jst)); quiet {
+		t.Error("Expected no quiet hours at the end of the window")
+	}
+}
+
+func TestInQuietHours(t *testing.T) {
+	setting := &model.NotificationSetting{QuietHoursEnabled: true, QuietHoursStart: "22:00", QuietHoursEnd: "07:00", TimeZone: "Asia/Tokyo"}
+	cases := map[string]bool{
+		"2024-04-01T12:59:00Z": false, // 21:59 JST
+		"2024-04-01T13:00:00Z": true,  // 22:00 JST
+		"2024-04-01T21:59:00Z": true,  // 06:59 JST
+		"2024-04-01T22:00:00Z": false, // 07:00 JST
+	}
+	for at, want := range cases {
+		tm, _ := time.Parse(time.RFC3339, at)
+		if got := inQuietHours(setting, tm); got != want {
+			t.Errorf("inQuietHours(%s) = %v, want %v", at, got, want)
+		}
+	}
+
+	daytime := &model.NotificationSetting{QuietHoursEnabled: true, QuietHoursStart: "12:00", QuietHoursEnd: "13:00", TimeZone: "UTC"}
+	if !inQuietHours(daytime, time.Date(2024, 4, 1, 12, 30, 0, 0, time.UTC)) || inQuietHours(daytime, time.Date(2024, 4, 1, 13, 0, 0, 0, time.UTC)) {
+		t.Error("Unexpected result for a same-day window")
+	}
+	if inQuietHours(nil, time.Now()) {
+		t.Error("Expected no quiet hours without a setting")
+	}
+}
+
+func TestNotificationService_UpdatePreferences(t *testing.T) {
+	deps, _, _ := setupNotificationDeliveryDeps(t)
+	defer deps.Notifier.Close()
+	svc := NewNotificationService(deps)
+	ctx := context.Background()
+	userID := uuid.New()
+
+	resp, err := svc.UpdatePreferences(ctx, userID, &model.NotificationPreferencesRequest{
+		Preferences: []model.NotificationPreferenceItem{
+			{Type: model.NotificationTypeExpenseComment, Channel: model.NotificationChannelEmail, Enabled: false},
+			{Type: model.NotificationTypeLeaveApproved, Channel: model.NotificationChannelChat, Enabled: true},
+		},
+		QuietHours: &model.NotificationQuietHours{Enabled: true, Start: "23:00", End: "06:30"},
+	})
+	if err != nil {
+		t.Fatalf("UpdatePreferences failed: %v", err)
+	}
+	if resp.QuietHours.Start != "23:00" || resp.QuietHours.TimeZone != "Asia/Tokyo" || !resp.QuietHours.Enabled {
+		t.Errorf("Unexpected quiet hours: %+v", resp.QuietHours)
+	}
+	if len(resp.Preferences) != len(model.NotificationTypeDefinitions)*len(model.NotificationChannels) {
+		t.Errorf("Expected every type and channel, got %d", len(resp.Preferences))
+	}
+	if svc.Allows(ctx, userID, model.NotificationTypeExpenseComment, model.NotificationChannelEmail) ||
+		!svc.Allows(ctx, userID, model.NotificationTypeLeaveApproved, model.NotificationChannelChat) {
+		t.Error("Expected updated preferences to apply")
+	}
+
+	_, err = svc.UpdatePreferences(ctx, userID, &model.NotificationPreferencesRequest{
+		Preferences: []model.NotificationPreferenceItem{
+			{Type: model.NotificationTypeNewDeviceLogin, Channel: model.NotificationChannelEmail, Enabled: false},
+			{Type: "unknown", Channel: model.NotificationChannelEmail},
+			{Type: model.NotificationTypeGeneral, Channel: "fax"},
+		},
+		QuietHours: &model.NotificationQuietHours{Start: "25:00", End: "07:00", TimeZone: "Mars/Base"},
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || len(verr.Fields) != 5 {
+		t.Errorf("Expected 5 validation errors, got %v", err)
+	}
+}
+
+func TestExpenseNotifications_RouteThroughDispatcher(t *testing.T) {
+	ctx := middleware.WithSystem(context.Background())
+	env := newExpTestEnv()
+	expenseID, ownerID := uuid.New(), uuid.New()
+	env.expenseRepo.expenses[expenseID] = &model.Expense{BaseModel: model.BaseModel{ID: expenseID}, UserID: ownerID, Title: "出張"}
+
+	var dispatched []*model.Notification
+	env.deps.notifications = &mocks.MockNotificationService{
+		DispatchFunc: func(_ context.Context, n *model.Notification) error {
+			dispatched = append(dispatched, n)
+			return nil
+		},
+	}
+	if err := NewExpenseService(env.deps).Approve(ctx, expenseID, uuid.New(), &model.ExpenseApproveRequest{Status: "rejected"}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	// 保存はディスパッチャーが受信設定に従って行う
+	if len(env.notifRepo.notifications) != 0 {
+		t.Errorf("Expected no direct write, got %d", len(env.notifRepo.notifications))
+	}
+	if len(dispatched) != 1 {
+		t.Fatalf("Expected 1 dispatched notification, got %d", len(dispatched))
+	}
+	n := dispatched[0]
+	if n.Type != model.NotificationTypeExpenseRejected || n.UserID != ownerID || n.ExpenseID == nil || *n.ExpenseID != expenseID ||
+		n.LinkURL != "/expenses/"+expenseID.String() {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+}
+
+func TestHRNotifications_DispatchToLinkedUser(t *testing.T) {
+	ctx := middleware.WithSystem(context.Background())
+	deps, repos := setupHRServiceDeps(t)
+	var dispatched []*model.Notification
+	deps.notifications = &mocks.MockNotificationService{
+		DispatchFunc: func(_ context.Context, n *model.Notification) error {
+			dispatched = append(dispatched, n)
+			return nil
+		},
+	}
+	userID := uuid.New()
+	linked := &model.HREmployee{UserID: &userID}
+	linked.ID = uuid.New()
+	unlinked := &model.HREmployee{}
+	unlinked.ID = uuid.New()
+	repos.hrEmployee.items[linked.ID] = linked
+	repos.hrEmployee.items[unlinked.ID] = unlinked
+
+	svc := NewOneOnOneService(deps)
+	for _, emp := range []*model.HREmployee{linked, unlinked} {
+		if _, err := svc.Create(ctx, model.OneOnOneCreateRequest{EmployeeID: emp.ID.String(), ScheduledDate: "2024-04-01"}, uuid.New()); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	if len(dispatched) != 1 || dispatched[0].UserID != userID || dispatched[0].Type != model.NotificationTypeOneOnOneScheduled {
+		t.Errorf("Expected one notification to the linked user, got %+v", dispatched)
+	}
+}
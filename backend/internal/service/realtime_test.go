@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/realtime"
+)
+
+// subscribeEvents はユーザーの購読を開始し、終了時に閉じる
+func subscribeEvents(t *testing.T, hub *realtime.Hub, userID uuid.UUID) *realtime.Subscription {
+	t.Helper()
+	sub, _, _ := hub.Subscribe(userID, "")
+	t.Cleanup(sub.Close)
+	return sub
+}
+
+func receiveEvent(t *testing.T, sub *realtime.Subscription) realtime.Event {
+	t.Helper()
+	select {
+	case ev := <-sub.Events():
+		return ev
+	case <-time.After(time.Second):
+		t.Fatal("Timed out waiting for event")
+	}
+	return realtime.Event{}
+}
+
+func assertNoEvent(t *testing.T, sub *realtime.Subscription) {
+	t.Helper()
+	select {
+	case ev := <-sub.Events():
+		t.Errorf("Unexpected event: %s %s", ev.Type, ev.Data)
+	default:
+	}
+}
+
+func TestNotificationService_Dispatch_PublishesStoredNotifications(t *testing.T) {
+	ctx := context.Background()
+	deps, _, _, _, _, _, _, _, _ := setupExtendedTestDeps(t)
+	deps.Repos.NotificationPref = mocks.NewMockNotificationPreferenceRepository()
+	deps.Events = realtime.NewHub(nil, realtime.Config{}, nil)
+	userID := uuid.New()
+	prefs := deps.Repos.NotificationPref.(*mocks.MockNotificationPreferenceRepository)
+	prefs.Prefs = []model.NotificationPreference{
+		{UserID: userID, Type: model.NotificationTypeShiftChanged, Channel: model.NotificationChannelInApp, Enabled: false},
+	}
+	sub := subscribeEvents(t, deps.Events, userID)
+
+	svc := NewNotificationService(deps)
+	if err := svc.Send(ctx, userID, model.NotificationTypeLeaveApproved, "休暇申請が承認されました", "本文"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	ev := receiveEvent(t, sub)
+	var n model.Notification
+	if err := json.Unmarshal(ev.Data, &n); err != nil {
+		t.Fatalf("Invalid event data: %v", err)
+	}
+	if ev.Type != realtime.EventNotification || n.Title != "休暇申請が承認されました" || n.ID == uuid.Nil {
+		t.Errorf("Unexpected event: %s %+v", ev.Type, n)
+	}
+
+	// アプリ内通知を受け取らない種別は配信しない
+	if err := svc.Send(ctx, userID, model.NotificationTypeShiftChanged, "シフト変更", "本文"); err != nil {
+		t.Fatalf("Send failed: %v", err)
+	}
+	assertNoEvent(t, sub)
+}
+
+func TestLeaveService_Approve_PublishesApprovalChange(t *testing.T) {
+	ctx := middleware.WithSystem(context.Background())
+	deps := setupTestDeps(t)
+	deps.Events = realtime.NewHub(nil, realtime.Config{}, nil)
+	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
+	userID := uuid.New()
+	leave, _ := leaveService.Create(ctx, userID, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-12", Reason: "Test",
+	})
+	sub := subscribeEvents(t, deps.Events, userID)
+
+	if _, err := leaveService.Approve(ctx, leave.ID, uuid.New(), &model.LeaveRequestApproval{Status: model.ApprovalStatusApproved}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	ev := receiveEvent(t, sub)
+	var change realtime.ApprovalChange
+	_ = json.Unmarshal(ev.Data, &change)
+	if ev.Type != realtime.EventApproval || change.Kind != "leave" || change.ID != leave.ID || change.Status != "approved" {
+		t.Errorf("Unexpected event: %s %+v", ev.Type, change)
+	}
+}
+
+func TestExpenseService_Approve_PublishesApprovalChange(t *testing.T) {
+	ctx := middleware.WithSystem(context.Background())
+	env := newExpTestEnv()
+	env.deps.Events = realtime.NewHub(nil, realtime.Config{}, nil)
+	expenseID, ownerID := uuid.New(), uuid.New()
+	env.expenseRepo.expenses[expenseID] = &model.Expense{BaseModel: model.BaseModel{ID: expenseID}, UserID: ownerID, Title: "出張"}
+	sub := subscribeEvents(t, env.deps.Events, ownerID)
+
+	if err := NewExpenseService(env.deps).Approve(ctx, expenseID, uuid.New(), &model.ExpenseApproveRequest{Status: "rejected"}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	ev := receiveEvent(t, sub)
+	var change realtime.ApprovalChange
+	_ = json.Unmarshal(ev.Data, &change)
+	if ev.Type != realtime.EventApproval || change.Kind != "expense" || change.ID != expenseID || change.Status != "rejected" {
+		t.Errorf("Unexpected event: %s %+v", ev.Type, change)
+	}
+}
+
+func TestAnnouncementService_PublishesOnlyPublishedAnnouncements(t *testing.T) {
+	ctx := context.Background()
+	deps, _ := setupHRServiceDeps(t)
+	deps.Events = realtime.NewHub(nil, realtime.Config{}, nil)
+	sub := subscribeEvents(t, deps.Events, uuid.New())
+	svc := NewAnnouncementService(deps)
+
+	a, err := svc.Create(ctx, model.AnnouncementCreateRequest{Title: "全社会議", Content: "本文", Priority: "high"}, uuid.New())
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	// 下書きは配信しない
+	assertNoEvent(t, sub)
+
+	published := true
+	if _, err := svc.Update(ctx, a.ID, model.AnnouncementUpdateRequest{IsPublished: &published}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	ev := receiveEvent(t, sub)
+	var change realtime.AnnouncementChange
+	_ = json.Unmarshal(ev.Data, &change)
+	if ev.Type != realtime.EventAnnouncement || ev.UserID != nil || change.ID != a.ID || change.Title != "全社会議" || change.Priority != "high" {
+		t.Errorf("Unexpected event: %s %+v", ev.Type, change)
+	}
+}
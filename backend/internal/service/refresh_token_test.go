@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/revocation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func loginTestUser(t *testing.T, deps Deps) (AuthService, *model.TokenResponse) {
+	t.Helper()
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	user := &model.User{BaseModel: model.BaseModel{ID: uuid.New()}, Email: "rt@example.com", PasswordHash: string(hash), Role: model.RoleEmployee, IsActive: true}
+	userRepo := deps.Repos.User.(*mocks.MockUserRepository)
+	userRepo.Users[user.ID] = user
+	userRepo.UsersByEmail[user.Email] = user
+
+	svc := NewAuthService(deps)
+	resp, err := svc.Login(context.Background(), &model.LoginRequest{Email: user.Email, Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	return svc, resp
+}
+
+func TestAuthService_Login_StoresHashedRefreshToken(t *testing.T) {
+	deps := setupTestDeps(t)
+	_, resp := loginTestUser(t, deps)
+
+	rtRepo := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository)
+	stored, ok := rtRepo.Tokens[hashToken(resp.RefreshToken)]
+	if !ok {
+		t.Fatal("Refresh token hash should be stored")
+	}
+	if stored.TokenHash == resp.RefreshToken || stored.FamilyID == uuid.Nil {
+		t.Errorf("Unexpected stored token: %+v", stored)
+	}
+}
+
+func TestAuthService_RefreshToken_RotatesAndDetectsReuse(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	svc, first := loginTestUser(t, deps)
+	ctx := context.Background()
+
+	second, err := svc.RefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if second.RefreshToken == first.RefreshToken {
+		t.Fatal("Refresh token should be rotated")
+	}
+
+	rtRepo := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository)
+	if rtRepo.Tokens[hashToken(first.RefreshToken)].FamilyID != rtRepo.Tokens[hashToken(second.RefreshToken)].FamilyID {
+		t.Error("Rotated token should inherit the family")
+	}
+
+	// 使用済みトークンの再提示でファミリー全体が失効する
+	if _, err := svc.RefreshToken(ctx, first.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("Expected ErrRefreshTokenReused, got %v", err)
+	}
+	if _, err := svc.RefreshToken(ctx, second.RefreshToken); !errors.Is(err, ErrRefreshTokenReused) {
+		t.Errorf("Expected latest token to be revoked, got %v", err)
+	}
+	// 発行済みのアクセストークンもセッション単位で拒否する
+	family := rtRepo.Tokens[hashToken(first.RefreshToken)].FamilyID
+	if revoked, _ := deps.DenyList.IsSessionRevoked(ctx, family); !revoked {
+		t.Error("Expected the session's access tokens to be revoked")
+	}
+}
+
+func TestAuthService_Logout_InvalidatesRefreshToken(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, resp := loginTestUser(t, deps)
+	ctx := context.Background()
+
+	if err := svc.Logout(ctx, resp.User.ID); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if _, err := svc.RefreshToken(ctx, resp.RefreshToken); err == nil {
+		t.Error("Refresh should fail after logout")
+	}
+}
+
+func TestAuthService_RefreshToken_RejectsUnissuedToken(t *testing.T) {
+	deps := setupTestDeps(t)
+	svc, resp := loginTestUser(t, deps)
+
+	// アクセストークンは保存されていないためリフレッシュに使えない
+	if _, err := svc.RefreshToken(context.Background(), resp.AccessToken); !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Expected ErrInvalidCredentials, got %v", err)
+	}
+}
+
+func TestAuthService_CleanupExpiredTokens(t *testing.T) {
+	deps := setupTestDeps(t)
+	rtRepo := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository)
+	rtRepo.Tokens["expired"] = &model.RefreshToken{TokenHash: "expired", ExpiresAt: time.Now().Add(-time.Minute)}
+	rtRepo.Tokens["active"] = &model.RefreshToken{TokenHash: "active", ExpiresAt: time.Now().Add(time.Hour), IsRevoked: true}
+
+	deleted, err := NewAuthService(deps).CleanupExpiredTokens(context.Background())
+	if err != nil || deleted != 1 {
+		t.Fatalf("Expected 1 deleted, got %d (%v)", deleted, err)
+	}
+	if _, ok := rtRepo.Tokens["active"]; !ok {
+		t.Error("Revoked but unexpired token should be kept for reuse detection")
+	}
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func setupRoleDeps(t *testing.T) (Deps, *mocks.MockRoleRepository, *mocks.MockRoleAssignmentRepository) {
+	t.Helper()
+	deps := setupTestDeps(t)
+	roleRepo := mocks.NewMockRoleRepository()
+	assignmentRepo := mocks.NewMockRoleAssignmentRepository(roleRepo)
+	deps.Repos.Role = roleRepo
+	deps.Repos.RoleAssignment = assignmentRepo
+	return deps, roleRepo, assignmentRepo
+}
+
+func TestRoleService_CreateValidation(t *testing.T) {
+	deps, _, _ := setupRoleDeps(t)
+	svc := NewRoleService(deps)
+	ctx := actorCtx(uuid.New(), model.RoleAdmin)
+
+	var verr *model.ValidationError
+	_, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:reimburse", "expense:fly"}})
+	if !errors.As(err, &verr) || verr.Fields[0].Field != "permissions" {
+		t.Fatalf("Expected validation error on permissions, got %v", err)
+	}
+
+	role, err := svc.Create(ctx, &model.CustomRoleRequest{
+		Name:        " 経理 ",
+		Permissions: []string{"expense:reimburse", "expense:report", "expense:reimburse"},
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if role.Name != "経理" {
+		t.Errorf("Expected trimmed name, got %q", role.Name)
+	}
+	if string(role.Permissions) != `["expense:reimburse","expense:report"]` {
+		t.Errorf("Expected deduplicated permissions, got %s", role.Permissions)
+	}
+
+	if _, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:read"}}); !errors.As(err, &verr) {
+		t.Errorf("Expected validation error on duplicate name, got %v", err)
+	}
+	// 自身の名前のままの更新は重複扱いしない
+	if _, err := svc.Update(ctx, role.ID, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:read"}}); err != nil {
+		t.Errorf("Update keeping name failed: %v", err)
+	}
+}
+
+func TestRoleService_AssignAndDelete(t *testing.T) {
+	deps, _, _ := setupRoleDeps(t)
+	svc := NewRoleService(deps)
+	ctx := actorCtx(uuid.New(), model.RoleAdmin)
+
+	userID := uuid.New()
+	deps.Repos.User.(*mocks.MockUserRepository).Users[userID] = &model.User{BaseModel: model.BaseModel{ID: userID}}
+	role, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "人事", Permissions: []string{"hr:read", "salary:read"}})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	missingDept := uuid.New()
+	var verr *model.ValidationError
+	if _, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID, DepartmentID: &missingDept}); !errors.As(err, &verr) {
+		t.Errorf("Expected validation error for unknown department, got %v", err)
+	}
+	if _, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: uuid.New()}); !errors.Is(err, ErrRoleNotFound) {
+		t.Errorf("Expected ErrRoleNotFound, got %v", err)
+	}
+
+	assignment, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID})
+	if err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+	if err := svc.Delete(ctx, role.ID); !errors.Is(err, ErrRoleInUse) {
+		t.Errorf("Expected ErrRoleInUse, got %v", err)
+	}
+	if err := svc.Unassign(ctx, uuid.New(), assignment.ID); !errors.Is(err, ErrRoleAssignmentNotFound) {
+		t.Errorf("Expected ErrRoleAssignmentNotFound for other user, got %v", err)
+	}
+	if err := svc.Unassign(ctx, userID, assignment.ID); err != nil {
+		t.Fatalf("Unassign failed: %v", err)
+	}
+	if err := svc.Delete(ctx, role.ID); err != nil {
+		t.Errorf("Delete failed: %v", err)
+	}
+}
+
+func TestAuthService_Login_IncludesPermissionClaims(t *testing.T) {
+	deps, _, _ := setupRoleDeps(t)
+	ctx := context.Background()
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	userID := uuid.New()
+	user := &model.User{BaseModel: model.BaseModel{ID: userID}, Email: "hr@example.com", PasswordHash: string(hash), Role: model.RoleEmployee, IsActive: true}
+	userRepo := deps.Repos.User.(*mocks.MockUserRepository)
+	userRepo.Users[userID] = user
+	userRepo.UsersByEmail[user.Email] = user
+
+	deptID := uuid.New()
+	deps.Repos.Department.(*mocks.MockDepartmentRepository).Departments[deptID] = &model.Department{BaseModel: model.BaseModel{ID: deptID}}
+
+	roleSvc := NewRoleService(deps)
+	adminCtx := actorCtx(uuid.New(), model.RoleAdmin)
+	hr, _ := roleSvc.Create(adminCtx, &model.CustomRoleRequest{Name: "人事", Permissions: []string{"salary:read"}})
+	acct, _ := roleSvc.Create(adminCtx, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:reimburse"}})
+	if _, err := roleSvc.Assign(adminCtx, userID, &model.RoleAssignmentRequest{RoleID: hr.ID}); err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+	if _, err := roleSvc.Assign(adminCtx, userID, &model.RoleAssignmentRequest{RoleID: acct.ID, DepartmentID: &deptID}); err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+
+	resp, err := NewAuthService(deps).Login(ctx, &model.LoginRequest{Email: user.Email, Password: "password123"})
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(resp.AccessToken, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(deps.Config.JWTSecretKey), nil
+	}); err != nil {
+		t.Fatalf("Failed to parse token: %v", err)
+	}
+	perms, _ := claims["perms"].([]interface{})
+	got := make(map[string]bool)
+	for _, p := range perms {
+		got[p.(string)] = true
+	}
+	scoped := middleware.Grant{Permission: middleware.PermExpenseReimburse, DepartmentID: &deptID}.String()
+	if len(got) != 2 || !got["salary:read"] || !got[scoped] {
+		t.Errorf("Unexpected perms claim: %v", perms)
+	}
+}
+
+func TestRoleService_GrantWithinActorScope(t *testing.T) {
+	deps, _, _ := setupRoleDeps(t)
+	svc := NewRoleService(deps)
+
+	deptID, otherDeptID := uuid.New(), uuid.New()
+	depts := deps.Repos.Department.(*mocks.MockDepartmentRepository).Departments
+	depts[deptID] = &model.Department{BaseModel: model.BaseModel{ID: deptID}}
+	depts[otherDeptID] = &model.Department{BaseModel: model.BaseModel{ID: otherDeptID}}
+	userID := uuid.New()
+	deps.Repos.User.(*mocks.MockUserRepository).Users[userID] = &model.User{BaseModel: model.BaseModel{ID: userID}}
+
+	// ロール管理権限と部署限定の精算権限だけを持つ操作主体
+	ctx := middleware.WithActor(context.Background(), middleware.Actor{
+		UserID: userID,
+		Role:   model.RoleEmployee,
+		Grants: []middleware.Grant{
+			{Permission: middleware.PermRoleManage},
+			{Permission: middleware.PermExpenseReimburse, DepartmentID: &deptID},
+		},
+	})
+
+	if _, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "給与", Permissions: []string{"salary:read"}}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a permission the actor lacks, got %v", err)
+	}
+	role, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:reimburse"}})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for an unscoped assignment, got %v", err)
+	}
+	if _, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID, DepartmentID: &otherDeptID}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for another department, got %v", err)
+	}
+	if _, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID, DepartmentID: &deptID}); err != nil {
+		t.Fatalf("Assign within department failed: %v", err)
+	}
+	if _, err := svc.Update(ctx, role.ID, &model.CustomRoleRequest{Name: "経理", Permissions: []string{"expense:reimburse", "salary:read"}}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden when widening an assigned role, got %v", err)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
@@ -421,7 +422,7 @@ func TestAttendanceCorrectionService_Approve_WithOnlyCorrectedClockIn(t *testing
 		// ClockOut is nil → ClockIn != nil && ClockOut != nil is false
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -462,7 +463,7 @@ func TestAttendanceCorrectionService_Approve_NoOvertime(t *testing.T) {
 		OvertimeMinutes: 60, // 既存の残業を0にリセットされるか確認
 	}
 
-	_, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	_, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -495,7 +496,7 @@ func TestAttendanceCorrectionService_Approve_NewAttendanceNoOvertime(t *testing.
 		// AttendanceIDなし → 新規作成
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -522,7 +523,7 @@ func TestAttendanceCorrectionService_Approve_NewAttendanceClockInOnly(t *testing
 		// CorrectedClockOutなし → ClockIn && ClockOut条件がfalse
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -641,7 +642,7 @@ func TestTimeEntryService_Update_Description(t *testing.T) {
 	}
 
 	newDesc := "Updated description"
-	e, err := svc.Update(context.Background(), eID, &model.TimeEntryUpdate{Description: &newDesc})
+	e, err := svc.Update(middleware.WithSystem(context.Background()), eID, &model.TimeEntryUpdate{Description: &newDesc})
 	if err != nil {
 		t.Fatalf("Update failed: %v", err)
 	}
@@ -1180,7 +1181,7 @@ func TestExportService_ExportProjectsCSV_WithSummaries(t *testing.T) {
 func TestLeaveService_Approve_RejectedNoReason(t *testing.T) {
 	deps := setupTestDeps(t)
 	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	userID := uuid.New()
 	leave, _ := leaveService.Create(ctx, userID, &model.LeaveRequestCreate{
@@ -1213,17 +1214,18 @@ func TestAttendanceCorrectionService_Create_InvalidClockInFormat(t *testing.T) {
 	svc := NewAttendanceCorrectionService(deps, notifSvc)
 
 	invalidTime := "invalid-time"
-	result, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
+	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
 		Date:             "2024-01-15",
 		CorrectedClockIn: &invalidTime,
 		Reason:           "Test",
 	})
-	if err != nil {
-		t.Fatalf("Create failed: %v", err)
+	// 不正な時刻は黙って捨てず、項目エラーとして返す
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
 	}
-	// 不正な時刻はパースに失敗するので、CorrectedClockInはnilのまま
-	if result.CorrectedClockIn != nil {
-		t.Error("Expected nil CorrectedClockIn for invalid format")
+	if verr.Fields[0].Field != "corrected_clock_in" {
+		t.Errorf("Expected corrected_clock_in field error, got %s", verr.Fields[0].Field)
 	}
 }
 
@@ -1234,17 +1236,18 @@ func TestAttendanceCorrectionService_Create_InvalidClockOutFormat(t *testing.T)
 
 	clockIn := "09:00"
 	invalidTime := "invalid-time"
-	result, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
+	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
 		Date:              "2024-01-15",
 		CorrectedClockIn:  &clockIn,
 		CorrectedClockOut: &invalidTime,
 		Reason:            "Test",
 	})
-	if err != nil {
-		t.Fatalf("Create failed: %v", err)
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
 	}
-	if result.CorrectedClockOut != nil {
-		t.Error("Expected nil CorrectedClockOut for invalid format")
+	if len(verr.Fields) != 1 || verr.Fields[0].Field != "corrected_clock_out" {
+		t.Errorf("Expected only corrected_clock_out field error, got %+v", verr.Fields)
 	}
 }
 
@@ -1253,9 +1256,11 @@ func TestAttendanceCorrectionService_Create_NilClockTimes(t *testing.T) {
 	notifSvc := NewNotificationService(deps)
 	svc := NewAttendanceCorrectionService(deps, notifSvc)
 
+	breakMinutes := 45
 	result, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
-		Date:   "2024-01-15",
-		Reason: "Test",
+		Date:                  "2024-01-15",
+		CorrectedBreakMinutes: &breakMinutes,
+		Reason:                "Test",
 	})
 	if err != nil {
 		t.Fatalf("Create failed: %v", err)
@@ -1362,12 +1367,19 @@ func TestExportService_ExportProjectsCSV_WithBudget(t *testing.T) {
 // ===================================================================
 
 func TestAttendanceService_ClockOut_WithOvertime(t *testing.T) {
-	deps := setupTestDeps(t)
+	deps, shRepo, _ := setupSubstituteHolidayDeps(t)
 	ctx := context.Background()
 
 	userID := uuid.New()
 	today := time.Now().Truncate(24 * time.Hour)
 
+	// 実行日の曜日に依存しないよう、今日を振替出勤日（勤務日）にする
+	reqID := uuid.New()
+	shRepo.requests[reqID] = &model.SubstituteHolidayRequest{
+		BaseModel: model.BaseModel{ID: reqID}, UserID: userID, Type: model.SubstituteHolidayTypeFurikae,
+		WorkDate: &today, HolidayDate: today.AddDate(0, 0, 7), Status: model.ApprovalStatusApproved,
+	}
+
 	// 10時間前にClockIn（残業が発生する）
 	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
 	attID := uuid.New()
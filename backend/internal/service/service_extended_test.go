@@ -3,11 +3,13 @@ package service
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
 	"github.com/your-org/kintai/backend/pkg/logger"
@@ -75,6 +77,16 @@ func (m *mockOvertimeRequestRepo) FindPending(ctx context.Context, page, pageSiz
 	return result, int64(len(result)), nil
 }
 
+func (m *mockOvertimeRequestRepo) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.OvertimeRequest, int64, error) {
+	var result []model.OvertimeRequest
+	for _, r := range m.requests {
+		if r.Status == model.OvertimeStatusPending && slices.Contains(userIDs, r.UserID) {
+			result = append(result, *r)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
 func (m *mockOvertimeRequestRepo) Update(ctx context.Context, req *model.OvertimeRequest) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -173,13 +185,17 @@ func (m *mockLeaveBalanceRepo) Upsert(ctx context.Context, balance *model.LeaveB
 // --- AttendanceCorrectionRepository mock ---
 type mockAttendanceCorrectionRepo struct {
 	corrections map[uuid.UUID]*model.AttendanceCorrection
+	applied     map[uuid.UUID]*model.Attendance
 	createErr   error
 	findByIDErr error
 	updateErr   error
 }
 
 func newMockAttendanceCorrectionRepo() *mockAttendanceCorrectionRepo {
-	return &mockAttendanceCorrectionRepo{corrections: make(map[uuid.UUID]*model.AttendanceCorrection)}
+	return &mockAttendanceCorrectionRepo{
+		corrections: make(map[uuid.UUID]*model.AttendanceCorrection),
+		applied:     make(map[uuid.UUID]*model.Attendance),
+	}
 }
 
 func (m *mockAttendanceCorrectionRepo) Create(ctx context.Context, c *model.AttendanceCorrection) error {
@@ -224,6 +240,16 @@ func (m *mockAttendanceCorrectionRepo) FindPending(ctx context.Context, page, pa
 	return result, int64(len(result)), nil
 }
 
+func (m *mockAttendanceCorrectionRepo) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.AttendanceCorrection, int64, error) {
+	var result []model.AttendanceCorrection
+	for _, c := range m.corrections {
+		if c.Status == model.CorrectionStatusPending && slices.Contains(userIDs, c.UserID) {
+			result = append(result, *c)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
 func (m *mockAttendanceCorrectionRepo) Update(ctx context.Context, c *model.AttendanceCorrection) error {
 	if m.updateErr != nil {
 		return m.updateErr
@@ -232,6 +258,19 @@ func (m *mockAttendanceCorrectionRepo) Update(ctx context.Context, c *model.Atte
 	return nil
 }
 
+func (m *mockAttendanceCorrectionRepo) ApplyApproval(ctx context.Context, c *model.AttendanceCorrection, att *model.Attendance) error {
+	if m.updateErr != nil {
+		return m.updateErr
+	}
+	if att.ID == uuid.Nil {
+		att.ID = uuid.New()
+	}
+	m.applied[att.ID] = att
+	c.AttendanceID = &att.ID
+	m.corrections[c.ID] = c
+	return nil
+}
+
 func (m *mockAttendanceCorrectionRepo) CountPending(ctx context.Context) (int64, error) {
 	var count int64
 	for _, c := range m.corrections {
@@ -962,7 +1001,7 @@ func TestTimeEntryService_Update_Success(t *testing.T) {
 	teRepo.entries[eID] = &model.TimeEntry{BaseModel: model.BaseModel{ID: eID}, Minutes: 60}
 
 	newMin := 120
-	e, err := svc.Update(context.Background(), eID, &model.TimeEntryUpdate{Minutes: &newMin})
+	e, err := svc.Update(middleware.WithSystem(context.Background()), eID, &model.TimeEntryUpdate{Minutes: &newMin})
 	if err != nil {
 		t.Fatalf("Update failed: %v", err)
 	}
@@ -988,7 +1027,7 @@ func TestTimeEntryService_Delete(t *testing.T) {
 	eID := uuid.New()
 	teRepo.entries[eID] = &model.TimeEntry{BaseModel: model.BaseModel{ID: eID}}
 
-	err := svc.Delete(context.Background(), eID)
+	err := svc.Delete(middleware.WithSystem(context.Background()), eID)
 	if err != nil {
 		t.Fatalf("Delete failed: %v", err)
 	}
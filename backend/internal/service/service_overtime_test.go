@@ -8,6 +8,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
@@ -125,7 +126,7 @@ func TestOvertimeRequestService_Approve_Success(t *testing.T) {
 	}
 
 	approverID := uuid.New()
-	result, err := svc.Approve(context.Background(), reqID, approverID, &model.OvertimeRequestApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), reqID, approverID, &model.OvertimeRequestApproval{
 		Status: model.OvertimeStatusApproved,
 	})
 	if err != nil {
@@ -151,7 +152,7 @@ func TestOvertimeRequestService_Approve_Rejected(t *testing.T) {
 		Date:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 	}
 
-	result, err := svc.Approve(context.Background(), reqID, uuid.New(), &model.OvertimeRequestApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), reqID, uuid.New(), &model.OvertimeRequestApproval{
 		Status: model.OvertimeStatusRejected, RejectedReason: "Not needed",
 	})
 	if err != nil {
@@ -255,7 +256,7 @@ func TestOvertimeRequestService_GetPending(t *testing.T) {
 		Status:    model.OvertimeStatusApproved,
 	}
 
-	results, total, err := svc.GetPending(context.Background(), 1, 20)
+	results, total, err := svc.GetPending(middleware.WithSystem(context.Background()), 1, 20)
 	if err != nil {
 		t.Fatalf("Failed: %v", err)
 	}
@@ -432,8 +433,9 @@ func TestAttendanceCorrectionService_Create_RepoError(t *testing.T) {
 	notifSvc := NewNotificationService(deps)
 	svc := NewAttendanceCorrectionService(deps, notifSvc)
 
+	clockIn := "09:00"
 	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
-		Date: "2024-01-15", Reason: "Test",
+		Date: "2024-01-15", CorrectedClockIn: &clockIn, Reason: "Test",
 	})
 	if err == nil {
 		t.Error("Expected error")
@@ -467,7 +469,7 @@ func TestAttendanceCorrectionService_Approve_Success(t *testing.T) {
 		ClockIn:   &origIn, ClockOut: &origOut,
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -496,7 +498,7 @@ func TestAttendanceCorrectionService_Approve_Rejected(t *testing.T) {
 		Date:   time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusRejected, RejectedReason: "Not valid",
 	})
 	if err != nil {
@@ -558,7 +560,7 @@ func TestAttendanceCorrectionService_Approve_NoAttendanceID(t *testing.T) {
 		// No AttendanceID - triggers new attendance creation branch
 	}
 
-	result, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	result, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -597,7 +599,7 @@ func TestAttendanceCorrectionService_Approve_OvertimeCalculation(t *testing.T) {
 		ClockIn:   &origIn, ClockOut: &origOut, BreakMinutes: 0,
 	}
 
-	_, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+	_, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
 		Status: model.CorrectionStatusApproved,
 	})
 	if err != nil {
@@ -641,7 +643,7 @@ func TestAttendanceCorrectionService_GetPending(t *testing.T) {
 		Status:    model.CorrectionStatusPending,
 	}
 
-	results, total, err := svc.GetPending(context.Background(), 1, 20)
+	results, total, err := svc.GetPending(middleware.WithSystem(context.Background()), 1, 20)
 	if err != nil {
 		t.Fatalf("Failed: %v", err)
 	}
@@ -850,3 +852,141 @@ func containsSubstring(s, sub string) bool {
 	}
 	return false
 }
+
+func TestAttendanceCorrectionService_Create_ClockOutBeforeClockIn(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	clockIn := "18:00"
+	clockOut := "09:00"
+	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
+		Date: "2024-01-15", CorrectedClockIn: &clockIn, CorrectedClockOut: &clockOut, Reason: "修正",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Fields[0].Field != "corrected_clock_out" {
+		t.Fatalf("Expected corrected_clock_out validation error, got %v", err)
+	}
+}
+
+func TestAttendanceCorrectionService_Create_FutureTime(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	future := time.Now().Add(48 * time.Hour)
+	clockIn := future.Format("2006-01-02T15:04:05")
+	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
+		Date: future.Format("2006-01-02"), CorrectedClockIn: &clockIn, Reason: "修正",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+}
+
+func TestAttendanceCorrectionService_Create_InvalidStatus(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	status := model.AttendanceStatus("unknown")
+	_, err := svc.Create(context.Background(), uuid.New(), &model.AttendanceCorrectionCreate{
+		Date: "2024-01-15", CorrectedStatus: &status, Reason: "修正",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Fields[0].Field != "corrected_status" {
+		t.Fatalf("Expected corrected_status validation error, got %v", err)
+	}
+}
+
+func TestAttendanceCorrectionService_Approve_BreakAndStatus(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	acRepo := deps.Repos.AttendanceCorrection.(*mockAttendanceCorrectionRepo)
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	attID := uuid.New()
+	clockIn := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	clockOut := time.Date(2024, 1, 15, 19, 0, 0, 0, time.UTC)
+	attRepo.Attendances[attID] = &model.Attendance{
+		BaseModel: model.BaseModel{ID: attID},
+		ClockIn:   &clockIn, ClockOut: &clockOut,
+		Status: model.AttendanceStatusAbsent,
+	}
+
+	cID := uuid.New()
+	breakMinutes := 60
+	status := model.AttendanceStatusPresent
+	acRepo.corrections[cID] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: cID}, UserID: uuid.New(),
+		AttendanceID: &attID, Status: model.CorrectionStatusPending,
+		Date:                  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		CorrectedBreakMinutes: &breakMinutes,
+		CorrectedStatus:       &status,
+	}
+
+	if _, err := svc.Approve(middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+		Status: model.CorrectionStatusApproved,
+	}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	att := acRepo.applied[attID]
+	if att == nil {
+		t.Fatal("Expected attendance to be applied")
+	}
+	if att.BreakMinutes != 60 || att.Status != model.AttendanceStatusPresent {
+		t.Errorf("Expected break 60 and present, got %d %s", att.BreakMinutes, att.Status)
+	}
+	if att.WorkMinutes != 540 || att.OvertimeMinutes != 60 {
+		t.Errorf("Expected 540 work / 60 overtime, got %d / %d", att.WorkMinutes, att.OvertimeMinutes)
+	}
+}
+
+func TestAttendanceCorrectionService_Approve_InconsistentAttendance(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	acRepo := deps.Repos.AttendanceCorrection.(*mockAttendanceCorrectionRepo)
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	attID := uuid.New()
+	origIn := time.Date(2024, 1, 15, 9, 0, 0, 0, time.UTC)
+	attRepo.Attendances[attID] = &model.Attendance{BaseModel: model.BaseModel{ID: attID}, ClockIn: &origIn}
+
+	// 申請後に出勤時刻が変わり、退勤時刻が出勤時刻より前になったケース
+	cID := uuid.New()
+	clockOut := time.Date(2024, 1, 15, 8, 0, 0, 0, time.UTC)
+	acRepo.corrections[cID] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: cID}, UserID: uuid.New(),
+		AttendanceID: &attID, Status: model.CorrectionStatusPending,
+		Date:              time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		CorrectedClockOut: &clockOut,
+	}
+
+	_, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+		Status: model.CorrectionStatusApproved,
+	})
+	if err == nil {
+		t.Fatal("Expected validation error")
+	}
+	if len(acRepo.applied) != 0 {
+		t.Error("Expected nothing to be applied")
+	}
+}
+
+func TestAttendanceCorrectionService_Approve_AttendanceNotFound(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	svc := NewAttendanceCorrectionService(deps, NewNotificationService(deps))
+
+	acRepo := deps.Repos.AttendanceCorrection.(*mockAttendanceCorrectionRepo)
+	cID := uuid.New()
+	missing := uuid.New()
+	acRepo.corrections[cID] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: cID}, UserID: uuid.New(),
+		AttendanceID: &missing, Status: model.CorrectionStatusPending,
+		Date: time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+	}
+
+	if _, err := svc.Approve(context.Background(), cID, uuid.New(), &model.AttendanceCorrectionApproval{
+		Status: model.CorrectionStatusApproved,
+	}); err == nil {
+		t.Error("Expected error for missing attendance")
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/your-org/kintai/backend/internal/config"
+	"github.com/your-org/kintai/backend/internal/middleware"
 	"github.com/your-org/kintai/backend/internal/mocks"
 	"github.com/your-org/kintai/backend/internal/model"
 	"github.com/your-org/kintai/backend/internal/repository"
@@ -41,12 +42,21 @@ func setupTestDeps(t *testing.T) Deps {
 		Config: cfg,
 		Logger: log,
 		Repos: &repository.Repositories{
-			User:         mocks.NewMockUserRepository(),
-			Attendance:   mocks.NewMockAttendanceRepository(),
-			LeaveRequest: mocks.NewMockLeaveRequestRepository(),
-			Shift:        mocks.NewMockShiftRepository(),
-			Department:   mocks.NewMockDepartmentRepository(),
-			RefreshToken: mocks.NewMockRefreshTokenRepository(),
+			User:            mocks.NewMockUserRepository(),
+			Attendance:      mocks.NewMockAttendanceRepository(),
+			LeaveRequest:    mocks.NewMockLeaveRequestRepository(),
+			Shift:           mocks.NewMockShiftRepository(),
+			Department:      mocks.NewMockDepartmentRepository(),
+			RefreshToken:    mocks.NewMockRefreshTokenRepository(),
+			UserToken:       mocks.NewMockUserTokenRepository(),
+			MFA:             mocks.NewMockMFARepository(),
+			MFARecoveryCode: mocks.NewMockMFARecoveryCodeRepository(),
+			MFAPolicy:       mocks.NewMockMFAPolicyRepository(),
+			LoginHistory:    mocks.NewMockLoginHistoryRepository(),
+			LoginLockout:    mocks.NewMockLoginLockoutRepository(),
+			ServiceAccount:  mocks.NewMockServiceAccountRepository(),
+			APIToken:        mocks.NewMockAPITokenRepository(),
+			AuditLog:        &mocks.MockAuditLogRepository{},
 		},
 	}
 }
@@ -205,6 +215,7 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 		FirstName: "Test",
 		LastName:  "User",
 		Role:      model.RoleEmployee,
+		IsActive:  true,
 	}
 	mockUserRepo := deps.Repos.User.(*mocks.MockUserRepository)
 	mockUserRepo.Users[userID] = testUser
@@ -214,6 +225,13 @@ func TestAuthService_RefreshToken_Success(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to generate refresh token: %v", err)
 	}
+	// 発行済みトークンとして保存する
+	deps.Repos.RefreshToken.Create(ctx, &model.RefreshToken{
+		UserID:    userID,
+		TokenHash: hashToken(refreshToken),
+		FamilyID:  uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour),
+	})
 
 	// リフレチE��ュ
 	resp, err := authService.RefreshToken(ctx, refreshToken)
@@ -549,7 +567,7 @@ func TestLeaveService_Create_InvalidEndDate(t *testing.T) {
 func TestLeaveService_Approve_Success(t *testing.T) {
 	deps := setupTestDeps(t)
 	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	// 休暇申請を作�E
 	userID := uuid.New()
@@ -592,7 +610,7 @@ func TestLeaveService_Approve_NotFound(t *testing.T) {
 func TestLeaveService_Approve_AlreadyProcessed(t *testing.T) {
 	deps := setupTestDeps(t)
 	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	// 休暇申請を作�Eして承誁E
 	userID := uuid.New()
@@ -621,7 +639,7 @@ func TestLeaveService_Approve_AlreadyProcessed(t *testing.T) {
 func TestLeaveService_Approve_Rejected(t *testing.T) {
 	deps := setupTestDeps(t)
 	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	// 休暇申請を作�E
 	userID := uuid.New()
@@ -678,7 +696,7 @@ func TestLeaveService_GetByUser(t *testing.T) {
 func TestLeaveService_GetPending(t *testing.T) {
 	deps := setupTestDeps(t)
 	leaveService := NewLeaveService(deps, &mocks.MockNotificationService{})
-	ctx := context.Background()
+	ctx := middleware.WithSystem(context.Background())
 
 	userID := uuid.New()
 	_, _ = leaveService.Create(ctx, userID, &model.LeaveRequestCreate{
@@ -704,7 +722,7 @@ func TestLeaveService_GetPending(t *testing.T) {
 
 func TestShiftService_Create_Success(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	userID := uuid.New()
@@ -728,7 +746,7 @@ func TestShiftService_Create_Success(t *testing.T) {
 
 func TestShiftService_Create_InvalidDate(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	_, err := shiftService.Create(ctx, &model.ShiftCreateRequest{
@@ -744,7 +762,7 @@ func TestShiftService_Create_InvalidDate(t *testing.T) {
 
 func TestShiftService_BulkCreate_Success(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	userID := uuid.New()
@@ -762,7 +780,7 @@ func TestShiftService_BulkCreate_Success(t *testing.T) {
 
 func TestShiftService_BulkCreate_InvalidDate(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	err := shiftService.BulkCreate(ctx, &model.ShiftBulkCreateRequest{
@@ -778,7 +796,7 @@ func TestShiftService_BulkCreate_InvalidDate(t *testing.T) {
 
 func TestShiftService_GetByUserAndDateRange(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	userID := uuid.New()
@@ -802,7 +820,7 @@ func TestShiftService_GetByUserAndDateRange(t *testing.T) {
 
 func TestShiftService_GetByDateRange(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	_, _ = shiftService.Create(ctx, &model.ShiftCreateRequest{
@@ -825,7 +843,7 @@ func TestShiftService_GetByDateRange(t *testing.T) {
 
 func TestShiftService_Delete(t *testing.T) {
 	deps := setupTestDeps(t)
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	shift, _ := shiftService.Create(ctx, &model.ShiftCreateRequest{
@@ -1302,7 +1320,7 @@ func TestShiftService_Create_RepoError(t *testing.T) {
 	mockShiftRepo := deps.Repos.Shift.(*mocks.MockShiftRepository)
 	mockShiftRepo.CreateErr = errors.New("database error")
 
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	_, err := shiftService.Create(ctx, &model.ShiftCreateRequest{
@@ -1320,7 +1338,7 @@ func TestShiftService_BulkCreate_RepoError(t *testing.T) {
 	mockShiftRepo := deps.Repos.Shift.(*mocks.MockShiftRepository)
 	mockShiftRepo.BulkCreateErr = errors.New("database error")
 
-	shiftService := NewShiftService(deps)
+	shiftService := NewShiftService(deps, nil)
 	ctx := context.Background()
 
 	err := shiftService.BulkCreate(ctx, &model.ShiftBulkCreateRequest{
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/revocation"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	chromeWindowsUA = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	safariIPhoneUA  = "Mozilla/5.0 (iPhone; CPU iPhone OS 17_0 like Mac OS X) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/17.0 Mobile/15E148 Safari/604.1"
+)
+
+// sessionTestUser はテストユーザーを作成し、指定した接続元からログインする関数を返す
+func sessionTestUser(t *testing.T, deps Deps) (AuthService, uuid.UUID, func(ip, userAgent string) *model.TokenResponse) {
+	t.Helper()
+	svc, resp := loginTestUser(t, deps)
+	user := deps.Repos.User.(*mocks.MockUserRepository).UsersByEmail["rt@example.com"]
+	hash, _ := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.MinCost)
+	login := func(ip, userAgent string) *model.TokenResponse {
+		t.Helper()
+		user.PasswordHash = string(hash)
+		tokens, err := svc.Login(clientContext(ip, userAgent), &model.LoginRequest{Email: "rt@example.com", Password: "password123"})
+		if err != nil {
+			t.Fatalf("Login failed: %v", err)
+		}
+		return tokens
+	}
+	return svc, resp.User.ID, login
+}
+
+// sessionOf はリフレッシュトークンのセッションID（ファミリーID）を返す
+func sessionOf(t *testing.T, deps Deps, refreshToken string) uuid.UUID {
+	t.Helper()
+	stored, ok := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository).Tokens[hashToken(refreshToken)]
+	if !ok {
+		t.Fatal("Refresh token not stored")
+	}
+	return stored.FamilyID
+}
+
+func sessionContext(userID, sessionID uuid.UUID) context.Context {
+	return middleware.WithActor(context.Background(), middleware.Actor{
+		UserID: userID, SessionID: sessionID, TokenID: uuid.NewString(), TokenExpiresAt: time.Now().Add(time.Minute),
+	})
+}
+
+func TestSessionService_List(t *testing.T) {
+	deps := setupTestDeps(t)
+	authSvc, userID, login := sessionTestUser(t, deps)
+	svc := NewSessionService(deps)
+
+	// loginTestUser のセッションを終了しておく
+	if err := deps.Repos.RefreshToken.RevokeByUserID(context.Background(), userID); err != nil {
+		t.Fatal(err)
+	}
+	pc := login("192.0.2.10", chromeWindowsUA)
+	phone := login("198.51.100.20", safariIPhoneUA)
+	pcSession := sessionOf(t, deps, pc.RefreshToken)
+
+	// リフレッシュしてもセッションとログイン日時は変わらず、接続元は更新される
+	loginAt := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository).Tokens[hashToken(pc.RefreshToken)].LoginAt
+	refreshed, err := authSvc.RefreshToken(clientContext("192.0.2.11", chromeWindowsUA), pc.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken failed: %v", err)
+	}
+	if sessionOf(t, deps, refreshed.RefreshToken) != pcSession {
+		t.Fatal("Refresh should keep the session")
+	}
+
+	sessions, err := svc.List(sessionContext(userID, pcSession), userID)
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 sessions, got %d", len(sessions))
+	}
+	for _, s := range sessions {
+		switch s.ID {
+		case pcSession:
+			if !s.Current || s.Device != "Chrome (Windows)" || s.IPAddress != "192.0.2.11" || !s.LoginAt.Equal(loginAt) {
+				t.Errorf("Unexpected PC session: %+v", s)
+			}
+		case sessionOf(t, deps, phone.RefreshToken):
+			if s.Current || s.Device != "Safari (iOS)" || s.IPAddress != "198.51.100.20" {
+				t.Errorf("Unexpected phone session: %+v", s)
+			}
+		default:
+			t.Errorf("Unexpected session %s", s.ID)
+		}
+	}
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	authSvc, userID, login := sessionTestUser(t, deps)
+	svc := NewSessionService(deps)
+	phone := login("198.51.100.20", safariIPhoneUA)
+	phoneSession := sessionOf(t, deps, phone.RefreshToken)
+	ctx := context.Background()
+
+	if err := svc.Revoke(ctx, userID, uuid.New()); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	// 他のユーザーのセッションは終了できない
+	if err := svc.Revoke(ctx, uuid.New(), phoneSession); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for another user, got %v", err)
+	}
+
+	if err := svc.Revoke(ctx, userID, phoneSession); err != nil {
+		t.Fatalf("Revoke failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsSessionRevoked(ctx, phoneSession); !revoked {
+		t.Error("Access tokens of the session should be revoked")
+	}
+	if _, err := authSvc.RefreshToken(ctx, phone.RefreshToken); err == nil {
+		t.Error("Revoked session should not refresh")
+	}
+	if err := svc.Revoke(ctx, userID, phoneSession); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound for an ended session, got %v", err)
+	}
+}
+
+func TestSessionService_RevokeOthers(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	authSvc, userID, login := sessionTestUser(t, deps)
+	svc := NewSessionService(deps)
+	current := login("192.0.2.10", chromeWindowsUA)
+	login("198.51.100.20", safariIPhoneUA)
+	currentSession := sessionOf(t, deps, current.RefreshToken)
+
+	// loginTestUser のセッションを含めて2件が終了する
+	revoked, err := svc.RevokeOthers(sessionContext(userID, currentSession), userID)
+	if err != nil {
+		t.Fatalf("RevokeOthers failed: %v", err)
+	}
+	if revoked != 2 {
+		t.Errorf("Expected 2 revoked sessions, got %d", revoked)
+	}
+	sessions, _ := svc.List(context.Background(), userID)
+	if len(sessions) != 1 || sessions[0].ID != currentSession {
+		t.Errorf("Only the current session should remain, got %+v", sessions)
+	}
+	if _, err := authSvc.RefreshToken(context.Background(), current.RefreshToken); err != nil {
+		t.Errorf("Current session should still refresh: %v", err)
+	}
+}
+
+func TestAuthService_Logout_EndsCurrentSessionOnly(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	authSvc, userID, login := sessionTestUser(t, deps)
+	pc := login("192.0.2.10", chromeWindowsUA)
+	phone := login("198.51.100.20", safariIPhoneUA)
+
+	if err := authSvc.Logout(sessionContext(userID, sessionOf(t, deps, pc.RefreshToken)), userID); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if _, err := authSvc.RefreshToken(context.Background(), pc.RefreshToken); err == nil {
+		t.Error("Logged out session should not refresh")
+	}
+	if _, err := authSvc.RefreshToken(context.Background(), phone.RefreshToken); err != nil {
+		t.Errorf("Other session should remain: %v", err)
+	}
+}
+
+func TestSessionService_ForceLogout(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	authSvc, userID, login := sessionTestUser(t, deps)
+	svc := NewSessionService(deps)
+	phone := login("198.51.100.20", safariIPhoneUA)
+	ctx := context.Background()
+	issuedAt := time.Now().Add(-time.Second)
+
+	if err := svc.ForceLogout(ctx, uuid.New()); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	if err := svc.ForceLogout(ctx, userID); err != nil {
+		t.Fatalf("ForceLogout failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); !revoked {
+		t.Error("Access tokens should be revoked")
+	}
+	if _, err := authSvc.RefreshToken(ctx, phone.RefreshToken); err == nil {
+		t.Error("Refresh tokens should be revoked")
+	}
+	if sessions, _ := svc.List(ctx, userID); len(sessions) != 0 {
+		t.Errorf("Expected no sessions, got %d", len(sessions))
+	}
+}
+
+func TestDescribeDevice(t *testing.T) {
+	tests := []struct {
+		userAgent string
+		want      string
+	}{
+		{chromeWindowsUA, "Chrome (Windows)"},
+		{safariIPhoneUA, "Safari (iOS)"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36 Edg/120.0.0.0", "Edge (macOS)"},
+		{"Mozilla/5.0 (X11; Linux x86_64; rv:121.0) Gecko/20100101 Firefox/121.0", "Firefox (Linux)"},
+		{"curl/8.4.0", "curl"},
+		{"", "不明なデバイス"},
+	}
+	for _, tt := range tests {
+		if got := describeDevice(tt.userAgent); got != tt.want {
+			t.Errorf("describeDevice(%q) = %q, want %q", tt.userAgent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+type shiftScheduleTestEnv struct {
+	deps      Deps
+	notifRepo *mockNotificationRepo
+	shifts    ShiftService
+	schedules ShiftScheduleService
+	deptID    uuid.UUID
+	userID    uuid.UUID
+}
+
+func setupShiftScheduleEnv(t *testing.T) *shiftScheduleTestEnv {
+	deps := setupTestDeps(t)
+	notifRepo := newMockNotificationRepo()
+	deps.Repos.Notification = notifRepo
+	deps.Repos.ShiftSchedule = mocks.NewMockShiftScheduleRepository()
+
+	deptID := uuid.New()
+	deps.Repos.Department.(*mocks.MockDepartmentRepository).Departments[deptID] = &model.Department{
+		BaseModel: model.BaseModel{ID: deptID}, Name: "開発部",
+	}
+	userID := uuid.New()
+	user := &model.User{BaseModel: model.BaseModel{ID: userID}, Email: "shift@example.com", DepartmentID: &deptID}
+	userRepo := deps.Repos.User.(*mocks.MockUserRepository)
+	userRepo.Users[userID] = user
+	userRepo.UsersByEmail[user.Email] = user
+
+	notifSvc := NewNotificationService(deps)
+	return &shiftScheduleTestEnv{
+		deps:      deps,
+		notifRepo: notifRepo,
+		shifts:    NewShiftService(deps, notifSvc),
+		schedules: NewShiftScheduleService(deps, notifSvc),
+		deptID:    deptID,
+		userID:    userID,
+	}
+}
+
+func (e *shiftScheduleTestEnv) shiftChangedCount() int {
+	count := 0
+	for _, n := range e.notifRepo.notifications {
+		if n.UserID == e.userID && n.Type == model.NotificationTypeShiftChanged {
+			count++
+		}
+	}
+	return count
+}
+
+func TestShiftScheduleService_DraftIsSilentUntilPublish(t *testing.T) {
+	env := setupShiftScheduleEnv(t)
+	ctx := context.Background()
+
+	schedule, err := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: env.deptID, PeriodStart: "2026-03-01", PeriodEnd: "2026-03-31",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if schedule.Status != model.ShiftScheduleStatusDraft {
+		t.Errorf("Expected draft status, got %s", schedule.Status)
+	}
+
+	shift, err := env.shifts.Create(ctx, &model.ShiftCreateRequest{
+		UserID: env.userID, Date: "2026-03-02", ShiftType: model.ShiftTypeDay,
+	})
+	if err != nil {
+		t.Fatalf("Shift create failed: %v", err)
+	}
+	if shift.ScheduleID == nil || *shift.ScheduleID != schedule.ID {
+		t.Fatal("Shift should be attached to the schedule")
+	}
+	if got := env.shiftChangedCount(); got != 0 {
+		t.Errorf("Expected no notifications for draft edits, got %d", got)
+	}
+
+	published, err := env.schedules.Publish(ctx, schedule.ID, uuid.New(), &model.ShiftSchedulePublishRequest{})
+	if err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+	if published.Status != model.ShiftScheduleStatusPublished || published.Version != 1 {
+		t.Errorf("Expected published version 1, got %s v%d", published.Status, published.Version)
+	}
+	if got := env.shiftChangedCount(); got != 1 {
+		t.Errorf("Expected 1 notification after publish, got %d", got)
+	}
+
+	if _, err := env.schedules.Publish(ctx, schedule.ID, uuid.New(), &model.ShiftSchedulePublishRequest{}); err == nil {
+		t.Error("Expected error when publishing twice")
+	}
+}
+
+func TestShiftScheduleService_EditAfterPublishCreatesVersion(t *testing.T) {
+	env := setupShiftScheduleEnv(t)
+	ctx := context.Background()
+
+	schedule, _ := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: env.deptID, PeriodStart: "2026-03-01", PeriodEnd: "2026-03-31",
+	})
+	shift, _ := env.shifts.Create(ctx, &model.ShiftCreateRequest{
+		UserID: env.userID, Date: "2026-03-02", ShiftType: model.ShiftTypeDay,
+	})
+	if _, err := env.schedules.Publish(ctx, schedule.ID, uuid.New(), &model.ShiftSchedulePublishRequest{}); err != nil {
+		t.Fatalf("Publish failed: %v", err)
+	}
+
+	night := model.ShiftTypeNight
+	if _, err := env.shifts.Update(ctx, shift.ID, &model.ShiftUpdateRequest{ShiftType: &night}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if _, err := env.shifts.Create(ctx, &model.ShiftCreateRequest{
+		UserID: env.userID, Date: "2026-03-03", ShiftType: model.ShiftTypeMorning,
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	versions, _ := env.schedules.GetVersions(ctx, schedule.ID)
+	if len(versions) != 3 {
+		t.Fatalf("Expected 3 versions, got %d", len(versions))
+	}
+	if got := env.shiftChangedCount(); got != 3 {
+		t.Errorf("Expected 3 notifications, got %d", got)
+	}
+
+	diff, err := env.schedules.GetDiff(ctx, schedule.ID, 1, 3)
+	if err != nil {
+		t.Fatalf("GetDiff failed: %v", err)
+	}
+	if len(diff.Changed) != 1 || diff.Changed[0].After.ShiftType != model.ShiftTypeNight {
+		t.Errorf("Expected one changed shift to night, got %+v", diff.Changed)
+	}
+	if len(diff.Added) != 1 || len(diff.Removed) != 0 {
+		t.Errorf("Expected 1 added and 0 removed, got %d/%d", len(diff.Added), len(diff.Removed))
+	}
+}
+
+func TestShiftScheduleService_Create_Validation(t *testing.T) {
+	env := setupShiftScheduleEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: env.deptID, PeriodStart: "2026-03-31", PeriodEnd: "2026-03-01",
+	}); err == nil {
+		t.Error("Expected error for reversed period")
+	}
+	if _, err := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: uuid.New(), PeriodStart: "2026-03-01", PeriodEnd: "2026-03-31",
+	}); err == nil {
+		t.Error("Expected error for unknown department")
+	}
+	if _, err := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: env.deptID, PeriodStart: "2026-03-01", PeriodEnd: "2026-03-31",
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := env.schedules.Create(ctx, &model.ShiftScheduleCreateRequest{
+		DepartmentID: env.deptID, PeriodStart: "2026-03-15", PeriodEnd: "2026-04-15",
+	}); err == nil {
+		t.Error("Expected error for overlapping period")
+	}
+}
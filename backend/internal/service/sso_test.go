@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/oidc"
+	"github.com/your-org/kintai/backend/internal/oidc/oidctest"
+)
+
+func setupSSOTestDeps(t *testing.T) (Deps, *oidctest.Server) {
+	t.Helper()
+	idp, err := oidctest.NewServer("kintai", "secret")
+	if err != nil {
+		t.Fatalf("NewServer failed: %v", err)
+	}
+	t.Cleanup(idp.Close)
+
+	deps := setupTestDeps(t)
+	deps.Repos.UserIdentity = mocks.NewMockUserIdentityRepository()
+	deps.Config.FrontendURL = "https://kintai.example.com"
+	deps.Config.OIDCRoleMapping = map[string]string{"kintai-admins": "admin", "kintai-managers": "manager"}
+	deps.OIDC = oidc.NewProvider(oidc.Config{
+		IssuerURL:    idp.Issuer(),
+		ClientID:     "kintai",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/callback",
+	})
+	return deps, idp
+}
+
+// ssoLogin はブラウザと同様に IdP を経由してコールバックまで進め、フロントエンドへのリダイレクト先を返す
+func ssoLogin(t *testing.T, svc SSOService, idp *oidctest.Server) (*url.URL, error) {
+	t.Helper()
+	ctx := context.Background()
+	auth, err := svc.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin failed: %v", err)
+	}
+	callback, err := idp.Authorize(auth.AuthorizationURL)
+	if err != nil {
+		t.Fatalf("Authorize failed: %v", err)
+	}
+	q := callback.Query()
+	redirect, cbErr := svc.Callback(ctx, auth.FlowToken, &model.SSOCallback{Code: q.Get("code"), State: q.Get("state")})
+	u, err := url.Parse(redirect)
+	if err != nil {
+		t.Fatalf("Invalid redirect %q: %v", redirect, err)
+	}
+	return u, cbErr
+}
+
+func TestSSOService_AutoProvisionAndExchange(t *testing.T) {
+	deps, idp := setupSSOTestDeps(t)
+	deps.Config.OIDCAutoProvision = true
+	svc := NewSSOService(deps)
+	ctx := context.Background()
+	idp.SetUser(oidctest.User{Subject: "sub-1", Email: "new@example.com", EmailVerified: true, GivenName: "花子", FamilyName: "佐藤", Groups: []string{"kintai-managers", "everyone"}})
+
+	redirect, err := ssoLogin(t, svc, idp)
+	if err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	if redirect.Path != "/auth/sso/callback" || redirect.Query().Get("code") == "" {
+		t.Fatalf("Unexpected redirect: %s", redirect)
+	}
+
+	user := deps.Repos.User.(*mocks.MockUserRepository).UsersByEmail["new@example.com"]
+	if user == nil || !user.IsActive || !user.PasswordLoginDisabled || user.Role != model.RoleManager || user.EmailVerifiedAt == nil {
+		t.Fatalf("Unexpected provisioned user: %+v", user)
+	}
+
+	code := redirect.Query().Get("code")
+	resp, err := svc.Exchange(ctx, code)
+	if err != nil {
+		t.Fatalf("Exchange failed: %v", err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" || resp.User.ID != user.ID {
+		t.Errorf("Expected tokens for provisioned user, got %+v", resp)
+	}
+	if _, err := svc.Exchange(ctx, code); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("Login code should be single-use, got %v", err)
+	}
+
+	// 2回目以降は紐付け済みのIDでログインし、ユーザーを重複して作らない
+	if _, err := ssoLogin(t, svc, idp); err != nil {
+		t.Fatalf("Second login failed: %v", err)
+	}
+	if n := len(deps.Repos.User.(*mocks.MockUserRepository).Users); n != 1 {
+		t.Errorf("Expected 1 user, got %d", n)
+	}
+	if n := len(deps.Repos.UserIdentity.(*mocks.MockUserIdentityRepository).Identities); n != 1 {
+		t.Errorf("Expected 1 identity, got %d", n)
+	}
+}
+
+func TestSSOService_LinksExistingUserAndSyncsRole(t *testing.T) {
+	deps, idp := setupSSOTestDeps(t)
+	_, userID, _ := mfaTestUser(t, deps)
+	svc := NewSSOService(deps)
+
+	idp.SetUser(oidctest.User{Subject: "sub-2", Email: "rt@example.com", EmailVerified: false})
+	if redirect, err := ssoLogin(t, svc, idp); !errors.Is(err, ErrSSOEmailNotVerified) || redirect.Query().Get("sso_error") != "email_not_verified" {
+		t.Errorf("Unverified email must not be linked, got %v (%s)", err, redirect)
+	}
+
+	idp.SetUser(oidctest.User{Subject: "sub-2", Email: "rt@example.com", EmailVerified: true, Groups: []string{"kintai-admins"}})
+	if _, err := ssoLogin(t, svc, idp); err != nil {
+		t.Fatalf("Callback failed: %v", err)
+	}
+	identities := deps.Repos.UserIdentity.(*mocks.MockUserIdentityRepository).Identities
+	if len(identities) != 1 || identities[0].UserID != userID || identities[0].LastLoginAt == nil {
+		t.Errorf("Expected identity linked to existing user, got %+v", identities)
+	}
+	user := deps.Repos.User.(*mocks.MockUserRepository).Users[userID]
+	if user.Role != model.RoleAdmin {
+		t.Errorf("Expected role synced to admin, got %s", user.Role)
+	}
+}
+
+func TestSSOService_Callback_Rejects(t *testing.T) {
+	deps, idp := setupSSOTestDeps(t)
+	svc := NewSSOService(deps)
+	ctx := context.Background()
+
+	// 自動作成が無効の場合は未登録のユーザーを拒否する
+	if redirect, err := ssoLogin(t, svc, idp); !errors.Is(err, ErrSSOAccountNotFound) || redirect.Path != "/login" || redirect.Query().Get("sso_error") != "account_not_found" {
+		t.Errorf("Expected account_not_found, got %v (%s)", err, redirect)
+	}
+
+	auth, _ := svc.Begin(ctx)
+	callback, _ := idp.Authorize(auth.AuthorizationURL)
+	code := callback.Query().Get("code")
+	if _, err := svc.Callback(ctx, auth.FlowToken, &model.SSOCallback{Code: code, State: "forged"}); !errors.Is(err, ErrSSOInvalidState) {
+		t.Errorf("Expected ErrSSOInvalidState for state mismatch, got %v", err)
+	}
+	if _, err := svc.Callback(ctx, "", &model.SSOCallback{Code: code, State: callback.Query().Get("state")}); !errors.Is(err, ErrSSOInvalidState) {
+		t.Errorf("Expected ErrSSOInvalidState without flow cookie, got %v", err)
+	}
+	if redirect, err := svc.Callback(ctx, auth.FlowToken, &model.SSOCallback{Error: "access_denied"}); !errors.Is(err, ErrSSOFailed) {
+		t.Errorf("Expected ErrSSOFailed for IdP error, got %v (%s)", err, redirect)
+	}
+
+	unconfigured := setupTestDeps(t)
+	if _, err := NewSSOService(unconfigured).Begin(ctx); !errors.Is(err, ErrSSONotConfigured) {
+		t.Errorf("Expected ErrSSONotConfigured, got %v", err)
+	}
+}
+
+func TestAuthService_PasswordLoginDisabled(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	svc, _, login := mfaTestUser(t, deps)
+	deps.Repos.User.(*mocks.MockUserRepository).UsersByEmail["rt@example.com"].PasswordLoginDisabled = true
+
+	if _, err := login(); !errors.Is(err, ErrPasswordLoginDisabled) {
+		t.Errorf("Expected ErrPasswordLoginDisabled, got %v", err)
+	}
+	if err := svc.RequestPasswordReset(context.Background(), "rt@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset failed: %v", err)
+	}
+	if len(mail.Sent()) != 0 {
+		t.Error("Should not send password reset to SSO-only users")
+	}
+}
+
+func TestMappedRole(t *testing.T) {
+	mapping := map[string]string{"a": "employee", "b": "admin", "c": "manager", "d": "owner"}
+	cases := []struct {
+		groups []string
+		want   model.Role
+		ok     bool
+	}{
+		{[]string{"a", "c"}, model.RoleManager, true},
+		{[]string{"c", "b", "a"}, model.RoleAdmin, true},
+		{[]string{"d", "x"}, "", false},
+		{nil, "", false},
+	}
+	for _, tc := range cases {
+		got, ok := mappedRole(mapping, tc.groups)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("mappedRole(%v) = %s, %v; want %s, %v", tc.groups, got, ok, tc.want, tc.ok)
+		}
+	}
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// --- SubstituteHolidayRepository mock ---
+type mockSubstituteHolidayRepo struct {
+	requests map[uuid.UUID]*model.SubstituteHolidayRequest
+	applyErr error
+}
+
+func newMockSubstituteHolidayRepo() *mockSubstituteHolidayRepo {
+	return &mockSubstituteHolidayRepo{requests: make(map[uuid.UUID]*model.SubstituteHolidayRequest)}
+}
+
+func (m *mockSubstituteHolidayRepo) Create(ctx context.Context, r *model.SubstituteHolidayRequest) error {
+	if r.ID == uuid.Nil {
+		r.ID = uuid.New()
+	}
+	m.requests[r.ID] = r
+	return nil
+}
+
+func (m *mockSubstituteHolidayRepo) FindByID(ctx context.Context, id uuid.UUID) (*model.SubstituteHolidayRequest, error) {
+	r, ok := m.requests[id]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return r, nil
+}
+
+func (m *mockSubstituteHolidayRepo) FindByUserID(ctx context.Context, userID uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var result []model.SubstituteHolidayRequest
+	for _, r := range m.requests {
+		if r.UserID == userID {
+			result = append(result, *r)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *mockSubstituteHolidayRepo) FindPending(ctx context.Context, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var result []model.SubstituteHolidayRequest
+	for _, r := range m.requests {
+		if r.Status == model.ApprovalStatusPending {
+			result = append(result, *r)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *mockSubstituteHolidayRepo) FindPendingByUserIDs(ctx context.Context, userIDs []uuid.UUID, page, pageSize int) ([]model.SubstituteHolidayRequest, int64, error) {
+	var result []model.SubstituteHolidayRequest
+	for _, r := range m.requests {
+		if r.Status == model.ApprovalStatusPending && slices.Contains(userIDs, r.UserID) {
+			result = append(result, *r)
+		}
+	}
+	return result, int64(len(result)), nil
+}
+
+func (m *mockSubstituteHolidayRepo) FindApprovedByUserAndDateRange(ctx context.Context, userID uuid.UUID, start, end time.Time) ([]model.SubstituteHolidayRequest, error) {
+	inRange := func(d time.Time) bool { return !d.Before(start) && !d.After(end) }
+	var result []model.SubstituteHolidayRequest
+	for _, r := range m.requests {
+		if r.UserID != userID || r.Status != model.ApprovalStatusApproved {
+			continue
+		}
+		if inRange(r.HolidayDate) || (r.WorkDate != nil && inRange(*r.WorkDate)) {
+			result = append(result, *r)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockSubstituteHolidayRepo) CountPendingDaikyu(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	for _, r := range m.requests {
+		if r.UserID == userID && r.Type == model.SubstituteHolidayTypeDaikyu && r.Status == model.ApprovalStatusPending {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *mockSubstituteHolidayRepo) Update(ctx context.Context, r *model.SubstituteHolidayRequest) error {
+	m.requests[r.ID] = r
+	return nil
+}
+
+func (m *mockSubstituteHolidayRepo) ApplyApproval(ctx context.Context, r *model.SubstituteHolidayRequest, day *model.CompensatoryDay) error {
+	if m.applyErr != nil {
+		return m.applyErr
+	}
+	m.requests[r.ID] = r
+	return nil
+}
+
+// --- CompensatoryDayRepository mock ---
+type mockCompensatoryDayRepo struct {
+	days map[uuid.UUID]*model.CompensatoryDay
+}
+
+func newMockCompensatoryDayRepo() *mockCompensatoryDayRepo {
+	return &mockCompensatoryDayRepo{days: make(map[uuid.UUID]*model.CompensatoryDay)}
+}
+
+func (m *mockCompensatoryDayRepo) Create(ctx context.Context, d *model.CompensatoryDay) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	m.days[d.ID] = d
+	return nil
+}
+
+func (m *mockCompensatoryDayRepo) FindByUserID(ctx context.Context, userID uuid.UUID) ([]model.CompensatoryDay, error) {
+	var result []model.CompensatoryDay
+	for _, d := range m.days {
+		if d.UserID == userID {
+			result = append(result, *d)
+		}
+	}
+	return result, nil
+}
+
+func (m *mockCompensatoryDayRepo) FindByUserAndEarnedDate(ctx context.Context, userID uuid.UUID, date time.Time) (*model.CompensatoryDay, error) {
+	for _, d := range m.days {
+		if d.UserID == userID && d.EarnedDate.Equal(date) {
+			return d, nil
+		}
+	}
+	return nil, errors.New("not found")
+}
+
+func (m *mockCompensatoryDayRepo) FindAvailable(ctx context.Context, userID uuid.UUID, date time.Time) ([]model.CompensatoryDay, error) {
+	var result []model.CompensatoryDay
+	for _, d := range m.days {
+		if d.UserID == userID && d.UsedDate == nil && d.EarnedDate.Before(date) && !d.ExpiresAt.Before(date) {
+			result = append(result, *d)
+		}
+	}
+	// 実リポジトリと同じく有効期限の近い順に返す
+	sort.Slice(result, func(i, j int) bool { return result[i].ExpiresAt.Before(result[j].ExpiresAt) })
+	return result, nil
+}
+
+func setupSubstituteHolidayDeps(t *testing.T) (Deps, *mockSubstituteHolidayRepo, *mockCompensatoryDayRepo) {
+	deps := setupTestDeps(t)
+	deps.Repos.Notification = newMockNotificationRepo()
+	shRepo := newMockSubstituteHolidayRepo()
+	cdRepo := newMockCompensatoryDayRepo()
+	deps.Repos.SubstituteHoliday = shRepo
+	deps.Repos.CompensatoryDay = cdRepo
+	return deps, shRepo, cdRepo
+}
+
+// nextWeekday は from 以降で最初の指定曜日を返す
+func nextWeekday(from time.Time, wd time.Weekday) time.Time {
+	d := from.Truncate(24 * time.Hour)
+	for d.Weekday() != wd {
+		d = d.AddDate(0, 0, 1)
+	}
+	return d
+}
+
+func TestSubstituteHolidayService_Furikae_SwapsRestDay(t *testing.T) {
+	deps, _, _ := setupSubstituteHolidayDeps(t)
+	svc := NewSubstituteHolidayService(deps, NewNotificationService(deps))
+	ctx := middleware.WithSystem(context.Background())
+	userID := uuid.New()
+
+	saturday := nextWeekday(time.Now().AddDate(0, 0, 1), time.Saturday)
+	monday := saturday.AddDate(0, 0, 2)
+	req, err := svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeFurikae, WorkDate: saturday.Format("2006-01-02"),
+		HolidayDate: monday.Format("2006-01-02"), Reason: "イベント対応",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if _, err := svc.Approve(ctx, req.ID, uuid.New(), &model.SubstituteHolidayApproval{Status: model.ApprovalStatusApproved}); err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+
+	// 振替後の月曜日は休日扱いとなるため、休みとして申請できない
+	_, err = svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeDaikyu, HolidayDate: monday.Format("2006-01-02"), Reason: "代休",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Fields[0].Message != "休日には振替・代休を設定できません" {
+		t.Errorf("Expected swapped Monday to be a rest day, got %v", err)
+	}
+}
+
+func TestSubstituteHolidayService_Furikae_Validation(t *testing.T) {
+	deps, _, _ := setupSubstituteHolidayDeps(t)
+	svc := NewSubstituteHolidayService(deps, NewNotificationService(deps))
+	ctx := context.Background()
+
+	tuesday := nextWeekday(time.Now().AddDate(0, 0, 1), time.Tuesday)
+	wednesday := tuesday.AddDate(0, 0, 1)
+	_, err := svc.Create(ctx, uuid.New(), &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeFurikae, WorkDate: tuesday.Format("2006-01-02"),
+		HolidayDate: wednesday.Format("2006-01-02"), Reason: "test",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Fields[0].Field != "work_date" {
+		t.Fatalf("Expected work_date validation error, got %v", err)
+	}
+
+	pastSaturday := nextWeekday(time.Now().AddDate(0, 0, -14), time.Saturday)
+	_, err = svc.Create(ctx, uuid.New(), &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeFurikae, WorkDate: pastSaturday.Format("2006-01-02"),
+		HolidayDate: wednesday.Format("2006-01-02"), Reason: "test",
+	})
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error for past work date, got %v", err)
+	}
+}
+
+func TestSubstituteHolidayService_Daikyu_ConsumesCompensatoryDay(t *testing.T) {
+	deps, shRepo, cdRepo := setupSubstituteHolidayDeps(t)
+	svc := NewSubstituteHolidayService(deps, NewNotificationService(deps))
+	ctx := middleware.WithSystem(context.Background())
+	userID := uuid.New()
+
+	monday := nextWeekday(time.Now().AddDate(0, 0, 1), time.Monday)
+	_, err := svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeDaikyu, HolidayDate: monday.Format("2006-01-02"), Reason: "代休",
+	})
+	if err == nil {
+		t.Fatal("Expected error without compensatory days")
+	}
+
+	earned := monday.AddDate(0, 0, -2)
+	soon := &model.CompensatoryDay{UserID: userID, EarnedDate: earned, ExpiresAt: monday.AddDate(0, 0, 5)}
+	later := &model.CompensatoryDay{UserID: userID, EarnedDate: earned.AddDate(0, 0, -7), ExpiresAt: monday.AddDate(0, 0, 30)}
+	_ = cdRepo.Create(ctx, soon)
+	_ = cdRepo.Create(ctx, later)
+
+	req, err := svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeDaikyu, HolidayDate: monday.Format("2006-01-02"), Reason: "代休",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	approved, err := svc.Approve(ctx, req.ID, uuid.New(), &model.SubstituteHolidayApproval{Status: model.ApprovalStatusApproved})
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	if approved.CompensatoryDayID == nil || *approved.CompensatoryDayID != soon.ID {
+		t.Error("Expected the soonest-expiring compensatory day to be consumed")
+	}
+	if shRepo.requests[req.ID].Status != model.ApprovalStatusApproved {
+		t.Error("Expected request to be approved")
+	}
+}
+
+func TestSubstituteHolidayService_Daikyu_CountsPendingRequests(t *testing.T) {
+	deps, _, cdRepo := setupSubstituteHolidayDeps(t)
+	svc := NewSubstituteHolidayService(deps, NewNotificationService(deps))
+	ctx := context.Background()
+	userID := uuid.New()
+
+	monday := nextWeekday(time.Now().AddDate(0, 0, 1), time.Monday)
+	_ = cdRepo.Create(ctx, &model.CompensatoryDay{UserID: userID, EarnedDate: monday.AddDate(0, 0, -2), ExpiresAt: monday.AddDate(0, 0, 30)})
+
+	if _, err := svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeDaikyu, HolidayDate: monday.Format("2006-01-02"), Reason: "代休",
+	}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	// 1日分の代休は承認待ちの申請で予約済みのため、2件目は申請できない
+	_, err := svc.Create(ctx, userID, &model.SubstituteHolidayRequestCreate{
+		Type: model.SubstituteHolidayTypeDaikyu, HolidayDate: monday.AddDate(0, 0, 1).Format("2006-01-02"), Reason: "代休",
+	})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) || verr.Fields[0].Message != "取得可能な代休がありません" {
+		t.Fatalf("Expected no available compensatory day, got %v", err)
+	}
+}
+
+func TestSubstituteHolidayService_Approve_AlreadyProcessed(t *testing.T) {
+	deps, shRepo, _ := setupSubstituteHolidayDeps(t)
+	svc := NewSubstituteHolidayService(deps, NewNotificationService(deps))
+
+	id := uuid.New()
+	shRepo.requests[id] = &model.SubstituteHolidayRequest{BaseModel: model.BaseModel{ID: id}, Status: model.ApprovalStatusRejected}
+	if _, err := svc.Approve(context.Background(), id, uuid.New(), &model.SubstituteHolidayApproval{Status: model.ApprovalStatusApproved}); err == nil {
+		t.Error("Expected error for processed request")
+	}
+}
+
+func TestAttendanceService_ClockOut_OnSwappedRestDay(t *testing.T) {
+	deps, shRepo, cdRepo := setupSubstituteHolidayDeps(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	today := time.Now().Truncate(24 * time.Hour)
+
+	// 法定休日（日曜）からの振替先を今日にする
+	workDate := nextWeekday(today.AddDate(0, 0, -7), time.Sunday)
+	reqID := uuid.New()
+	shRepo.requests[reqID] = &model.SubstituteHolidayRequest{
+		BaseModel: model.BaseModel{ID: reqID}, UserID: userID, Type: model.SubstituteHolidayTypeFurikae,
+		WorkDate: &workDate, HolidayDate: today, Status: model.ApprovalStatusApproved,
+	}
+
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	clockIn := time.Now().Add(-10 * time.Hour)
+	att := &model.Attendance{
+		BaseModel: model.BaseModel{ID: uuid.New()}, UserID: userID, Date: today,
+		ClockIn: &clockIn, Status: model.AttendanceStatusPresent,
+	}
+	attRepo.Attendances[att.ID] = att
+	attRepo.UserDateIndex[userID.String()+today.Format("2006-01-02")] = att
+
+	attendance, err := NewAttendanceService(deps).ClockOut(ctx, userID, &model.ClockOutRequest{})
+	if err != nil {
+		t.Fatalf("ClockOut failed: %v", err)
+	}
+	if attendance.OvertimeMinutes != 0 || attendance.HolidayWorkMinutes != attendance.WorkMinutes {
+		t.Errorf("Expected all minutes as legal holiday work, got overtime=%d holiday=%d work=%d",
+			attendance.OvertimeMinutes, attendance.HolidayWorkMinutes, attendance.WorkMinutes)
+	}
+	if len(cdRepo.days) != 1 {
+		t.Errorf("Expected 1 compensatory day granted, got %d", len(cdRepo.days))
+	}
+}
+
+func TestAttendanceService_ClockOut_UsesPublishedShifts(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().Truncate(24 * time.Hour)
+
+	setup := func(t *testing.T) (Deps, *mockCompensatoryDayRepo, uuid.UUID) {
+		deps, _, cdRepo := setupSubstituteHolidayDeps(t)
+		userID, departmentID := uuid.New(), uuid.New()
+		deps.Repos.User.(*mocks.MockUserRepository).Users[userID] = &model.User{
+			BaseModel: model.BaseModel{ID: userID}, Role: model.RoleEmployee, DepartmentID: &departmentID,
+		}
+		schedules := mocks.NewMockShiftScheduleRepository()
+		scheduleID := uuid.New()
+		schedules.Schedules[scheduleID] = &model.ShiftSchedule{
+			BaseModel: model.BaseModel{ID: scheduleID}, DepartmentID: departmentID,
+			PeriodStart: today.AddDate(0, 0, -7), PeriodEnd: today.AddDate(0, 0, 7),
+			Status: model.ShiftScheduleStatusPublished,
+		}
+		deps.Repos.ShiftSchedule = schedules
+
+		attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+		clockIn := time.Now().Add(-9 * time.Hour)
+		att := &model.Attendance{
+			BaseModel: model.BaseModel{ID: uuid.New()}, UserID: userID, Date: today,
+			ClockIn: &clockIn, Status: model.AttendanceStatusPresent,
+		}
+		attRepo.Attendances[att.ID] = att
+		attRepo.UserDateIndex[userID.String()+today.Format("2006-01-02")] = att
+		return deps, cdRepo, userID
+	}
+
+	t.Run("no shift in published schedule is a rest day", func(t *testing.T) {
+		deps, cdRepo, userID := setup(t)
+		attendance, err := NewAttendanceService(deps).ClockOut(ctx, userID, &model.ClockOutRequest{})
+		if err != nil {
+			t.Fatalf("ClockOut failed: %v", err)
+		}
+		if attendance.OvertimeMinutes+attendance.HolidayWorkMinutes != attendance.WorkMinutes {
+			t.Errorf("Expected all minutes as rest-day work, got overtime=%d holiday=%d work=%d",
+				attendance.OvertimeMinutes, attendance.HolidayWorkMinutes, attendance.WorkMinutes)
+		}
+		if len(cdRepo.days) != 1 {
+			t.Errorf("Expected 1 compensatory day granted, got %d", len(cdRepo.days))
+		}
+	})
+
+	t.Run("work shift is a workday regardless of weekday", func(t *testing.T) {
+		deps, cdRepo, userID := setup(t)
+		shiftID := uuid.New()
+		deps.Repos.Shift.(*mocks.MockShiftRepository).Shifts[shiftID] = &model.Shift{
+			BaseModel: model.BaseModel{ID: shiftID}, UserID: userID, Date: today, ShiftType: model.ShiftTypeDay,
+		}
+		attendance, err := NewAttendanceService(deps).ClockOut(ctx, userID, &model.ClockOutRequest{})
+		if err != nil {
+			t.Fatalf("ClockOut failed: %v", err)
+		}
+		if attendance.HolidayWorkMinutes != 0 || len(cdRepo.days) != 0 {
+			t.Errorf("Expected a workday, got holiday=%d compensatory days=%d", attendance.HolidayWorkMinutes, len(cdRepo.days))
+		}
+	})
+}
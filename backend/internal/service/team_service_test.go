@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+type teamTestEnv struct {
+	deps      Deps
+	managerID uuid.UUID
+	adminID   uuid.UUID
+	reportA   uuid.UUID
+	reportB   uuid.UUID
+	outsider  uuid.UUID
+}
+
+// setupTeamEnv はマネージャー1名・部下2名・他部署の社員1名を用意する
+func setupTeamEnv(t *testing.T) *teamTestEnv {
+	t.Helper()
+	deps, _, userRepo := setupOvertimeDeps(t)
+	teamRepo := mocks.NewMockTeamRepository()
+	deps.Repos.Team = teamRepo
+
+	env := &teamTestEnv{
+		deps: deps, managerID: uuid.New(), adminID: uuid.New(),
+		reportA: uuid.New(), reportB: uuid.New(), outsider: uuid.New(),
+	}
+	for id, u := range map[uuid.UUID]model.User{
+		env.managerID: {LastName: "Manager", Role: model.RoleManager},
+		env.adminID:   {LastName: "Admin", Role: model.RoleAdmin},
+		env.reportA:   {LastName: "Suzuki", Role: model.RoleEmployee},
+		env.reportB:   {LastName: "Abe", Role: model.RoleEmployee},
+		env.outsider:  {LastName: "Tanaka", Role: model.RoleEmployee},
+	} {
+		u.ID = id
+		u.IsActive = true
+		userRepo.Users[id] = &u
+	}
+	teamRepo.Reports[env.managerID] = []uuid.UUID{env.reportA, env.reportB}
+	return env
+}
+
+// as は userID のユーザーを操作主体とするコンテキストを返す
+func (env *teamTestEnv) as(userID uuid.UUID) context.Context {
+	user, _ := env.deps.Repos.User.FindByID(context.Background(), userID)
+	return middleware.WithActor(context.Background(), middleware.Actor{UserID: userID, Role: user.Role})
+}
+
+func TestTeamService_MembersAndTodayStatus(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	svc := NewTeamService(env.deps)
+
+	members, err := svc.GetMembers(ctx, env.managerID)
+	if err != nil {
+		t.Fatalf("GetMembers failed: %v", err)
+	}
+	if len(members) != 2 || members[0].ID != env.reportB || members[1].ID != env.reportA {
+		t.Fatalf("Expected reports sorted by name, got %+v", members)
+	}
+
+	if _, err := NewAttendanceService(env.deps).ClockIn(ctx, env.reportA, &model.ClockInRequest{}); err != nil {
+		t.Fatalf("ClockIn failed: %v", err)
+	}
+	statuses, err := svc.GetTodayStatus(ctx, env.managerID)
+	if err != nil {
+		t.Fatalf("GetTodayStatus failed: %v", err)
+	}
+	for _, s := range statuses {
+		clockedIn := s.Attendance != nil && s.Attendance.ClockIn != nil
+		if clockedIn != (s.User.ID == env.reportA) {
+			t.Errorf("Unexpected today status for %s: %+v", s.User.LastName, s.Attendance)
+		}
+	}
+
+	summaries, err := svc.GetSummaries(ctx, env.managerID, time.Now().AddDate(0, 0, -30), time.Now())
+	if err != nil || len(summaries) != 2 {
+		t.Errorf("Expected 2 summaries, got %d (%v)", len(summaries), err)
+	}
+}
+
+func TestTeamService_GetPending_OnlyDirectReports(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	for _, userID := range []uuid.UUID{env.reportA, env.outsider} {
+		if _, err := leaveSvc.Create(ctx, userID, &model.LeaveRequestCreate{
+			LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+		}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+
+	pending, err := NewTeamService(env.deps).GetPending(ctx, env.managerID)
+	if err != nil {
+		t.Fatalf("GetPending failed: %v", err)
+	}
+	if len(pending.Leaves) != 1 || pending.Leaves[0].UserID != env.reportA {
+		t.Errorf("Expected only the direct report's leave, got %+v", pending.Leaves)
+	}
+}
+
+func TestLeaveService_Approve_RestrictedToDirectReports(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	approval := &model.LeaveRequestApproval{Status: model.ApprovalStatusApproved}
+
+	outsiderLeave, _ := leaveSvc.Create(ctx, env.outsider, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+	})
+	if _, err := leaveSvc.Approve(env.as(env.managerID), outsiderLeave.ID, env.managerID, approval); !errors.Is(err, ErrNotDirectReport) {
+		t.Errorf("Expected ErrNotDirectReport, got %v", err)
+	}
+	// 操作主体のないコンテキストでは承認できない
+	if _, err := leaveSvc.Approve(ctx, outsiderLeave.ID, env.adminID, approval); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without actor, got %v", err)
+	}
+	// 管理者は全社の申請を承認できる
+	if _, err := leaveSvc.Approve(env.as(env.adminID), outsiderLeave.ID, env.adminID, approval); err != nil {
+		t.Errorf("Expected admin approval to succeed, got %v", err)
+	}
+
+	reportLeave, _ := leaveSvc.Create(ctx, env.reportA, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-11", EndDate: "2026-02-11",
+	})
+	if _, err := leaveSvc.Approve(env.as(env.managerID), reportLeave.ID, env.managerID, approval); err != nil {
+		t.Errorf("Expected manager approval of direct report to succeed, got %v", err)
+	}
+
+	// マネージャー本人の申請は承認できない
+	ownLeave, _ := leaveSvc.Create(ctx, env.managerID, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-12", EndDate: "2026-02-12",
+	})
+	if _, err := leaveSvc.Approve(env.as(env.managerID), ownLeave.ID, env.managerID, approval); !errors.Is(err, ErrNotDirectReport) {
+		t.Errorf("Expected ErrNotDirectReport for own request, got %v", err)
+	}
+}
+
+func TestLeaveService_Approve_ReturnsLookupError(t *testing.T) {
+	env := setupTeamEnv(t)
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	leave, _ := leaveSvc.Create(context.Background(), env.reportA, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+	})
+
+	lookupErr := errors.New("team lookup failed")
+	env.deps.Repos.Team.(*mocks.MockTeamRepository).FindDirectReportIDsErr = lookupErr
+	_, err := leaveSvc.Approve(env.as(env.managerID), leave.ID, env.managerID, &model.LeaveRequestApproval{Status: model.ApprovalStatusApproved})
+	if !errors.Is(err, lookupErr) {
+		t.Errorf("Expected the lookup error, got %v", err)
+	}
+}
+
+func TestOvertimeRequestService_Approve_RestrictedToDirectReports(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	svc := NewOvertimeRequestService(env.deps, NewNotificationService(env.deps))
+
+	ot, err := svc.Create(ctx, env.outsider, &model.OvertimeRequestCreate{
+		Date: "2024-01-15", PlannedMinutes: 60, Reason: "Release",
+	})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	_, err = svc.Approve(env.as(env.managerID), ot.ID, env.managerID, &model.OvertimeRequestApproval{Status: model.OvertimeStatusApproved})
+	if !errors.Is(err, ErrNotDirectReport) {
+		t.Errorf("Expected ErrNotDirectReport, got %v", err)
+	}
+}
+
+func TestLeaveService_GetPending_ScopedToActor(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	for _, userID := range []uuid.UUID{env.reportA, env.outsider, env.managerID} {
+		if _, err := leaveSvc.Create(ctx, userID, &model.LeaveRequestCreate{
+			LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+		}); err != nil {
+			t.Fatalf("Create failed: %v", err)
+		}
+	}
+	departmentID := uuid.New()
+	env.deps.Repos.User.(*mocks.MockUserRepository).Users[env.outsider].DepartmentID = &departmentID
+
+	requesters := func(ctx context.Context) []uuid.UUID {
+		t.Helper()
+		leaves, _, err := leaveSvc.GetPending(ctx, 1, 20)
+		if err != nil {
+			t.Fatalf("GetPending failed: %v", err)
+		}
+		ids := make([]uuid.UUID, 0, len(leaves))
+		for _, l := range leaves {
+			ids = append(ids, l.UserID)
+		}
+		return ids
+	}
+
+	// マネージャーは直属の部下の申請のみ（本人の申請は含めない）
+	if got := requesters(env.as(env.managerID)); len(got) != 1 || got[0] != env.reportA {
+		t.Errorf("Expected only the direct report's leave, got %v", got)
+	}
+	if got := requesters(env.as(env.adminID)); len(got) != 3 {
+		t.Errorf("Expected all pending leaves for admin, got %v", got)
+	}
+	// 部署を指定した付与はその部署の申請のみ
+	granted := middleware.WithActor(ctx, middleware.Actor{
+		UserID: env.reportB, Role: model.RoleEmployee,
+		Grants: []middleware.Grant{{Permission: middleware.PermAttendanceApprove, DepartmentID: &departmentID}},
+	})
+	if got := requesters(granted); len(got) != 1 || got[0] != env.outsider {
+		t.Errorf("Expected only the department's leave, got %v", got)
+	}
+	if _, _, err := leaveSvc.GetPending(ctx, 1, 20); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden without actor, got %v", err)
+	}
+}
+
+func TestLeaveService_Approve_DepartmentScopedGrant(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	approval := &model.LeaveRequestApproval{Status: model.ApprovalStatusApproved}
+
+	sales, support := uuid.New(), uuid.New()
+	users := env.deps.Repos.User.(*mocks.MockUserRepository).Users
+	users[env.reportA].DepartmentID = &sales
+	users[env.outsider].DepartmentID = &support
+
+	// 営業部に限定して承認権限を付与された一般社員
+	approver := middleware.WithActor(ctx, middleware.Actor{
+		UserID: env.reportB, Role: model.RoleEmployee,
+		Grants: []middleware.Grant{{Permission: middleware.PermAttendanceApprove, DepartmentID: &sales}},
+	})
+
+	outsiderLeave, _ := leaveSvc.Create(ctx, env.outsider, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+	})
+	if _, err := leaveSvc.Approve(approver, outsiderLeave.ID, env.reportB, approval); !errors.Is(err, ErrNotDirectReport) {
+		t.Errorf("Expected ErrNotDirectReport outside the granted department, got %v", err)
+	}
+
+	salesLeave, _ := leaveSvc.Create(ctx, env.reportA, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-11", EndDate: "2026-02-11",
+	})
+	if _, err := leaveSvc.Approve(approver, salesLeave.ID, env.reportB, approval); err != nil {
+		t.Errorf("Expected approval within the granted department to succeed, got %v", err)
+	}
+}
+
+func TestApprovals_DeniedToServiceAccounts(t *testing.T) {
+	env := setupTeamEnv(t)
+	ctx := context.Background()
+	leaveSvc := NewLeaveService(env.deps, &mocks.MockNotificationService{})
+	leave, _ := leaveSvc.Create(ctx, env.reportA, &model.LeaveRequestCreate{
+		LeaveType: model.LeaveTypePaid, StartDate: "2026-02-10", EndDate: "2026-02-10",
+	})
+
+	// 管理者ロールのサービスアカウントでも申請者との組織上の関係を持たないため承認できない
+	accountID := uuid.New()
+	account := middleware.WithActor(ctx, middleware.Actor{UserID: accountID, Role: model.RoleAdmin, ServiceAccount: true})
+	if _, err := leaveSvc.Approve(account, leave.ID, accountID, &model.LeaveRequestApproval{Status: model.ApprovalStatusApproved}); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden for a service account, got %v", err)
+	}
+	if _, _, err := leaveSvc.GetPending(account, 1, 20); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden listing pending leaves as a service account, got %v", err)
+	}
+
+	expenseEnv := newExpTestEnv()
+	expenseID := uuid.New()
+	expenseEnv.expenseRepo.expenses[expenseID] = &model.Expense{
+		BaseModel: model.BaseModel{ID: expenseID}, UserID: env.reportA, Status: model.ExpenseStatusPending,
+	}
+	err := NewExpenseService(expenseEnv.deps).Approve(account, expenseID, accountID, &model.ExpenseApproveRequest{Status: string(model.ExpenseStatusApproved)})
+	if !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden approving an expense as a service account, got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"github.com/your-org/kintai/backend/internal/revocation"
+)
+
+func TestAuthService_Logout_RevokesAccessToken(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	svc, resp := loginTestUser(t, deps)
+
+	ctx := middleware.WithActor(context.Background(), middleware.Actor{
+		UserID: resp.User.ID, TokenID: "current-jti", TokenExpiresAt: time.Now().Add(time.Minute),
+	})
+	if err := svc.Logout(ctx, resp.User.ID); err != nil {
+		t.Fatalf("Logout failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "current-jti", resp.User.ID, time.Now()); !revoked {
+		t.Error("Current access token should be revoked on logout")
+	}
+}
+
+func TestUserService_Update_RevokesTokens(t *testing.T) {
+	deps := setupTestDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	authSvc, resp := loginTestUser(t, deps)
+	userSvc := NewUserService(deps)
+	ctx := context.Background()
+	userID := resp.User.ID
+	issuedAt := time.Now().Add(-time.Second)
+
+	// 氏名の変更では失効しない
+	name := "変更後"
+	if _, err := userSvc.Update(ctx, userID, &model.UserUpdateRequest{FirstName: &name}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); revoked {
+		t.Fatal("Profile change should not revoke tokens")
+	}
+
+	// ロール変更はアクセストークンのみ失効し、リフレッシュは可能
+	role := model.RoleManager
+	if _, err := userSvc.Update(ctx, userID, &model.UserUpdateRequest{Role: &role}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); !revoked {
+		t.Error("Role change should revoke access tokens")
+	}
+	refreshed, err := authSvc.RefreshToken(ctx, resp.RefreshToken)
+	if err != nil {
+		t.Fatalf("Refresh after role change failed: %v", err)
+	}
+
+	// 無効化はリフレッシュトークンも失効させる
+	inactive := false
+	if _, err := userSvc.Update(ctx, userID, &model.UserUpdateRequest{IsActive: &inactive}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	rtRepo := deps.Repos.RefreshToken.(*mocks.MockRefreshTokenRepository)
+	if !rtRepo.Tokens[hashToken(refreshed.RefreshToken)].IsRevoked {
+		t.Error("Deactivation should revoke refresh tokens")
+	}
+	if _, err := authSvc.RefreshToken(ctx, refreshed.RefreshToken); err == nil {
+		t.Error("Deactivated user should not refresh")
+	}
+}
+
+func TestRoleService_RevokesAffectedUserTokens(t *testing.T) {
+	deps, _, _ := setupRoleDeps(t)
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	svc := NewRoleService(deps)
+	ctx := actorCtx(uuid.New(), model.RoleAdmin)
+
+	userID := uuid.New()
+	deps.Repos.User.(*mocks.MockUserRepository).Users[userID] = &model.User{BaseModel: model.BaseModel{ID: userID}}
+	role, err := svc.Create(ctx, &model.CustomRoleRequest{Name: "人事", Permissions: []string{"hr:read"}})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+
+	issuedAt := time.Now().Add(-time.Second)
+	assignment, err := svc.Assign(ctx, userID, &model.RoleAssignmentRequest{RoleID: role.ID})
+	if err != nil {
+		t.Fatalf("Assign failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); !revoked {
+		t.Error("Assign should revoke the user's access tokens")
+	}
+
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	svc = NewRoleService(deps)
+	if _, err := svc.Update(ctx, role.ID, &model.CustomRoleRequest{Name: "人事", Permissions: []string{"hr:read", "salary:read"}}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); !revoked {
+		t.Error("Update should revoke access tokens of assigned users")
+	}
+
+	deps.DenyList = revocation.NewDenyList(nil, nil)
+	svc = NewRoleService(deps)
+	if err := svc.Unassign(ctx, userID, assignment.ID); err != nil {
+		t.Fatalf("Unassign failed: %v", err)
+	}
+	if revoked, _ := deps.DenyList.IsRevoked(ctx, "", userID, issuedAt); !revoked {
+		t.Error("Unassign should revoke the user's access tokens")
+	}
+}
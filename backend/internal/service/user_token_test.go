@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/your-org/kintai/backend/internal/mailer"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var mailTokenPattern = regexp.MustCompile(`token=([A-Za-z0-9_-]+)`)
+
+func mailToken(t *testing.T, mail *mailer.MemoryMailer, to string) string {
+	t.Helper()
+	msg, ok := mail.Last(to)
+	if !ok {
+		t.Fatalf("No email sent to %s", to)
+	}
+	m := mailTokenPattern.FindStringSubmatch(msg.Body)
+	if m == nil {
+		t.Fatalf("Token link not found in email: %s", msg.Body)
+	}
+	return m[1]
+}
+
+func setupMailTestDeps(t *testing.T) (Deps, *mailer.MemoryMailer) {
+	deps := setupTestDeps(t)
+	mail := mailer.NewMemoryMailer()
+	deps.Mailer = mail
+	deps.Config.FrontendURL = "https://kintai.example.com"
+	return deps, mail
+}
+
+func TestAuthService_Register_RequiresEmailVerification(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	svc := NewAuthService(deps)
+	ctx := context.Background()
+
+	user, err := svc.Register(ctx, &model.RegisterRequest{Email: "verify@example.com", Password: "password123", FirstName: "太郎", LastName: "山田"})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	if user.IsActive || user.EmailVerifiedAt != nil {
+		t.Errorf("Registered user should be inactive until verified: %+v", user)
+	}
+
+	login := &model.LoginRequest{Email: "verify@example.com", Password: "password123"}
+	if _, err := svc.Login(ctx, login); !errors.Is(err, ErrEmailNotVerified) {
+		t.Errorf("Expected ErrEmailNotVerified, got %v", err)
+	}
+
+	token := mailToken(t, mail, "verify@example.com")
+	if err := svc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("VerifyEmail failed: %v", err)
+	}
+	if _, err := svc.Login(ctx, login); err != nil {
+		t.Errorf("Login after verification failed: %v", err)
+	}
+	if err := svc.VerifyEmail(ctx, token); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("Expected ErrInvalidUserToken on reuse, got %v", err)
+	}
+}
+
+func TestAuthService_ResendVerification_InvalidatesPreviousLink(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	svc := NewAuthService(deps)
+	ctx := context.Background()
+
+	if _, err := svc.Register(ctx, &model.RegisterRequest{Email: "resend@example.com", Password: "password123"}); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	first := mailToken(t, mail, "resend@example.com")
+	if err := svc.ResendVerification(ctx, "resend@example.com"); err != nil {
+		t.Fatalf("ResendVerification failed: %v", err)
+	}
+	second := mailToken(t, mail, "resend@example.com")
+
+	if err := svc.VerifyEmail(ctx, first); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("Previous link should be invalidated, got %v", err)
+	}
+	if err := svc.VerifyEmail(ctx, second); err != nil {
+		t.Errorf("Latest link should verify: %v", err)
+	}
+
+	// 確認済み・未登録のアドレスには送信しない
+	sent := len(mail.Sent())
+	_ = svc.ResendVerification(ctx, "resend@example.com")
+	_ = svc.ResendVerification(ctx, "unknown@example.com")
+	if len(mail.Sent()) != sent {
+		t.Error("Should not send to verified or unknown addresses")
+	}
+}
+
+func TestAuthService_PasswordReset(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	svc, tokens := loginTestUser(t, deps)
+	ctx := context.Background()
+
+	if err := svc.RequestPasswordReset(ctx, "nobody@example.com"); err != nil {
+		t.Errorf("Unknown email should not be reported, got %v", err)
+	}
+	if len(mail.Sent()) != 0 {
+		t.Error("Should not send mail to unknown address")
+	}
+
+	if err := svc.RequestPasswordReset(ctx, "rt@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset failed: %v", err)
+	}
+	token := mailToken(t, mail, "rt@example.com")
+
+	if err := svc.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "short"}); err == nil {
+		t.Error("Expected validation error for short password")
+	}
+	if err := svc.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "newpassword456"}); err != nil {
+		t.Fatalf("ResetPassword failed: %v", err)
+	}
+	if err := svc.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "another789"}); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("Expected ErrInvalidUserToken on reuse, got %v", err)
+	}
+
+	user := deps.Repos.User.(*mocks.MockUserRepository).UsersByEmail["rt@example.com"]
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte("newpassword456")) != nil {
+		t.Error("Password should be updated")
+	}
+	if _, err := svc.RefreshToken(ctx, tokens.RefreshToken); err == nil {
+		t.Error("Refresh tokens issued before reset should be revoked")
+	}
+}
+
+func TestAuthService_ResetPassword_ExpiredToken(t *testing.T) {
+	deps, mail := setupMailTestDeps(t)
+	svc, _ := loginTestUser(t, deps)
+	ctx := context.Background()
+
+	if err := svc.RequestPasswordReset(ctx, "rt@example.com"); err != nil {
+		t.Fatalf("RequestPasswordReset failed: %v", err)
+	}
+	token := mailToken(t, mail, "rt@example.com")
+	deps.Repos.UserToken.(*mocks.MockUserTokenRepository).Tokens[hashToken(token)].ExpiresAt = time.Now().Add(-time.Minute)
+
+	if err := svc.ResetPassword(ctx, &model.ResetPasswordRequest{Token: token, NewPassword: "newpassword456"}); !errors.Is(err, ErrInvalidUserToken) {
+		t.Errorf("Expected ErrInvalidUserToken for expired token, got %v", err)
+	}
+}
+
+func TestAuthService_EmailRateLimit(t *testing.T) {
+	deps, _ := setupMailTestDeps(t)
+	deps.Config.AuthEmailRateLimit = 2
+	deps.Config.AuthEmailRateWindowMinutes = 15
+	svc := NewAuthService(deps)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := svc.RequestPasswordReset(ctx, "limit@example.com"); err != nil {
+			t.Fatalf("Request %d should be allowed: %v", i+1, err)
+		}
+	}
+	if err := svc.ResendVerification(ctx, "LIMIT@example.com"); !errors.Is(err, ErrTooManyEmailRequests) {
+		t.Errorf("Expected ErrTooManyEmailRequests, got %v", err)
+	}
+	if err := svc.RequestPasswordReset(ctx, "other@example.com"); err != nil {
+		t.Errorf("Other addresses should not be limited: %v", err)
+	}
+}
+
+func TestEmailRateLimiter_WindowExpires(t *testing.T) {
+	now := time.Date(2024, 4, 1, 9, 0, 0, 0, time.UTC)
+	l := newEmailRateLimiter(1, 15*time.Minute)
+	l.now = func() time.Time { return now }
+
+	if !l.Allow("a@example.com") || l.Allow("a@example.com") {
+		t.Fatal("Expected only one request within the window")
+	}
+	now = now.Add(15 * time.Minute)
+	if !l.Allow("a@example.com") {
+		t.Error("Expected request to be allowed after the window")
+	}
+}
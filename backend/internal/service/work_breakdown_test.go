@@ -0,0 +1,167 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// approveForBreakdown は勤怠修正申請を承認し、反映後の勤怠データを返す
+func approveForBreakdown(t *testing.T, deps Deps, userID uuid.UUID, in, out time.Time, breakMinutes int) *model.Attendance {
+	t.Helper()
+	acRepo := deps.Repos.AttendanceCorrection.(*mockAttendanceCorrectionRepo)
+	cID := uuid.New()
+	acRepo.corrections[cID] = &model.AttendanceCorrection{
+		BaseModel: model.BaseModel{ID: cID}, UserID: userID,
+		Status:                model.CorrectionStatusPending,
+		Date:                  time.Date(in.Year(), in.Month(), in.Day(), 0, 0, 0, 0, time.Local),
+		CorrectedClockIn:      &in,
+		CorrectedClockOut:     &out,
+		CorrectedBreakMinutes: &breakMinutes,
+	}
+	result, err := NewAttendanceCorrectionService(deps, NewNotificationService(deps)).Approve(
+		middleware.WithSystem(context.Background()), cID, uuid.New(), &model.AttendanceCorrectionApproval{Status: model.CorrectionStatusApproved})
+	if err != nil {
+		t.Fatalf("Approve failed: %v", err)
+	}
+	return acRepo.applied[*result.AttendanceID]
+}
+
+func TestWorkBreakdown_WithinStatutoryOvertime(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	deps.Config.ScheduledWorkMinutes = 7 * 60
+
+	// 2024-01-15（月）9:00〜20:00、休憩60分 → 600分勤務
+	att := approveForBreakdown(t, deps, uuid.New(),
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), time.Date(2024, 1, 15, 20, 0, 0, 0, time.Local), 60)
+
+	if att.WorkMinutes != 600 {
+		t.Errorf("Expected 600 work minutes, got %d", att.WorkMinutes)
+	}
+	if att.WithinStatutoryOvertimeMinutes != 60 {
+		t.Errorf("Expected 60 within-statutory overtime, got %d", att.WithinStatutoryOvertimeMinutes)
+	}
+	if att.OvertimeMinutes != 120 {
+		t.Errorf("Expected 120 statutory overtime, got %d", att.OvertimeMinutes)
+	}
+	if att.LateNightMinutes != 0 || att.HolidayWorkMinutes != 0 {
+		t.Errorf("Expected no late-night/holiday minutes, got %d/%d", att.LateNightMinutes, att.HolidayWorkMinutes)
+	}
+}
+
+func TestWorkBreakdown_LateNightAcrossMidnight(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+
+	// 2024-01-15（月）18:00〜翌2:00、休憩60分 → 深夜帯は22:00〜2:00の240分
+	att := approveForBreakdown(t, deps, uuid.New(),
+		time.Date(2024, 1, 15, 18, 0, 0, 0, time.Local), time.Date(2024, 1, 16, 2, 0, 0, 0, time.Local), 60)
+
+	if att.WorkMinutes != 420 {
+		t.Errorf("Expected 420 work minutes, got %d", att.WorkMinutes)
+	}
+	if att.LateNightMinutes != 240 {
+		t.Errorf("Expected 240 late-night minutes, got %d", att.LateNightMinutes)
+	}
+	if att.OvertimeMinutes != 0 {
+		t.Errorf("Expected no overtime, got %d", att.OvertimeMinutes)
+	}
+}
+
+func TestWorkBreakdown_LegalHolidayAndRestDay(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	userID := uuid.New()
+
+	// 2024-01-14（日）は法定休日
+	sunday := approveForBreakdown(t, deps, userID,
+		time.Date(2024, 1, 14, 9, 0, 0, 0, time.Local), time.Date(2024, 1, 14, 18, 0, 0, 0, time.Local), 60)
+	if sunday.HolidayWorkMinutes != 480 || sunday.OvertimeMinutes != 0 {
+		t.Errorf("Expected 480 legal holiday minutes, got holiday=%d overtime=%d", sunday.HolidayWorkMinutes, sunday.OvertimeMinutes)
+	}
+
+	// 2024-01-13（土）は法定外休日のため全時間が法定外残業
+	saturday := approveForBreakdown(t, deps, userID,
+		time.Date(2024, 1, 13, 9, 0, 0, 0, time.Local), time.Date(2024, 1, 13, 18, 0, 0, 0, time.Local), 60)
+	if saturday.OvertimeMinutes != 480 || saturday.HolidayWorkMinutes != 0 {
+		t.Errorf("Expected 480 statutory overtime, got overtime=%d holiday=%d", saturday.OvertimeMinutes, saturday.HolidayWorkMinutes)
+	}
+}
+
+func TestWorkBreakdown_OvertimeOver60HoursPerMonth(t *testing.T) {
+	deps, _, _ := setupOvertimeDeps(t)
+	userID := uuid.New()
+
+	// 当月の前日までに59時間の法定外残業がある
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	prevID := uuid.New()
+	attRepo.Attendances[prevID] = &model.Attendance{
+		BaseModel: model.BaseModel{ID: prevID}, UserID: userID,
+		Date:            time.Date(2024, 1, 10, 0, 0, 0, 0, time.Local),
+		OvertimeMinutes: 59 * 60, Status: model.AttendanceStatusPresent,
+	}
+
+	// 2024-01-15（月）に120分の法定外残業 → うち60分が月60時間超
+	att := approveForBreakdown(t, deps, userID,
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), time.Date(2024, 1, 15, 20, 0, 0, 0, time.Local), 60)
+
+	if att.OvertimeMinutes != 120 {
+		t.Errorf("Expected 120 statutory overtime, got %d", att.OvertimeMinutes)
+	}
+	if att.OvertimeOver60Minutes != 60 {
+		t.Errorf("Expected 60 minutes over the monthly threshold, got %d", att.OvertimeOver60Minutes)
+	}
+}
+
+func TestWorkBreakdown_SummaryAndCSV(t *testing.T) {
+	deps, _, userRepo := setupOvertimeDeps(t)
+	ctx := context.Background()
+	userID := uuid.New()
+	userRepo.Users[userID] = &model.User{BaseModel: model.BaseModel{ID: userID}, FirstName: "Taro", LastName: "Test"}
+
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	attID := uuid.New()
+	attRepo.Attendances[attID] = &model.Attendance{
+		BaseModel: model.BaseModel{ID: attID}, UserID: userID,
+		Date:        time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		WorkMinutes: 600, WithinStatutoryOvertimeMinutes: 30, OvertimeMinutes: 120,
+		LateNightMinutes: 45, OvertimeOver60Minutes: 60,
+		Status: model.AttendanceStatusPresent,
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+	summary, err := NewAttendanceService(deps).GetSummary(ctx, userID, start, end)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	if summary.TotalWithinStatutoryOvertimeMinutes != 30 || summary.TotalLateNightMinutes != 45 || summary.TotalOvertimeOver60Minutes != 60 {
+		t.Errorf("Unexpected summary breakdown: %+v", summary)
+	}
+
+	data, err := NewExportService(deps).ExportAttendanceCSV(ctx, &userID, start, end)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data[3:])).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Expected header and 1 row, got %d rows", len(records))
+	}
+	header, row := records[0], records[1]
+	want := map[string]string{
+		"残業時間(分)": "120", "法定内残業(分)": "30", "深夜労働(分)": "45", "法定休日労働(分)": "0", "月60時間超残業(分)": "60",
+	}
+	for i, col := range header {
+		if v, ok := want[col]; ok && row[i] != v {
+			t.Errorf("Column %s: expected %s, got %s", col, v, row[i])
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+// setupWorkModeEnv は在宅勤務ポリシー付きの部署に所属するユーザーを用意する
+func setupWorkModeEnv(t *testing.T, maxRemoteDays int) (Deps, uuid.UUID) {
+	t.Helper()
+	deps := setupTestDeps(t)
+	policyRepo := mocks.NewMockRemoteWorkPolicyRepository()
+	deps.Repos.RemoteWorkPolicy = policyRepo
+
+	deptID := uuid.New()
+	_ = policyRepo.Create(context.Background(), &model.RemoteWorkPolicy{DepartmentID: deptID, MaxRemoteDaysPerWeek: maxRemoteDays})
+	userID := uuid.New()
+	deps.Repos.User.(*mocks.MockUserRepository).Users[userID] = &model.User{
+		BaseModel: model.BaseModel{ID: userID}, FirstName: "Taro", LastName: "Test", DepartmentID: &deptID,
+	}
+	return deps, userID
+}
+
+// seedRemoteDays は今週（月〜日）の本日以外の日に在宅勤務の勤怠を登録する
+func seedRemoteDays(deps Deps, userID uuid.UUID, days int) {
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	today := time.Now().Truncate(24 * time.Hour)
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	for d := weekStart; days > 0; d = d.AddDate(0, 0, 1) {
+		if d.Equal(today) {
+			continue
+		}
+		id := uuid.New()
+		attRepo.Attendances[id] = &model.Attendance{
+			BaseModel: model.BaseModel{ID: id}, UserID: userID, Date: d,
+			Status: model.AttendanceStatusPresent, WorkMode: model.WorkModeRemote,
+		}
+		days--
+	}
+}
+
+func TestAttendanceService_ClockIn_DefaultsToOffice(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 2)
+
+	att, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{})
+	if err != nil {
+		t.Fatalf("ClockIn failed: %v", err)
+	}
+	if att.WorkMode != model.WorkModeOffice {
+		t.Errorf("Expected office work mode, got %s", att.WorkMode)
+	}
+}
+
+func TestAttendanceService_ClockIn_InvalidWorkMode(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 2)
+
+	_, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{WorkMode: "cafe"})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+}
+
+func TestAttendanceService_ClockIn_RemoteWithinAllowance(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 2)
+	seedRemoteDays(deps, userID, 1)
+
+	att, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{WorkMode: model.WorkModeRemote})
+	if err != nil {
+		t.Fatalf("ClockIn failed: %v", err)
+	}
+	if att.WorkMode != model.WorkModeRemote {
+		t.Errorf("Expected remote work mode, got %s", att.WorkMode)
+	}
+}
+
+func TestAttendanceService_ClockIn_RemoteOverWeeklyLimit(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 2)
+	seedRemoteDays(deps, userID, 2)
+
+	_, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{WorkMode: model.WorkModeRemote})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+
+	// 客先・出張は上限の対象外
+	if _, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{WorkMode: model.WorkModeClientSite}); err != nil {
+		t.Errorf("Expected client site clock-in to succeed, got %v", err)
+	}
+}
+
+func TestAttendanceService_ClockIn_RemoteNotAllowed(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 0)
+
+	_, err := NewAttendanceService(deps).ClockIn(context.Background(), userID, &model.ClockInRequest{WorkMode: model.WorkModeRemote})
+	var verr *model.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("Expected validation error, got %v", err)
+	}
+}
+
+func TestRemoteWorkPolicyService_Save(t *testing.T) {
+	deps, _ := setupWorkModeEnv(t, 2)
+	svc := NewRemoteWorkPolicyService(deps)
+	ctx := context.Background()
+	deptID := uuid.New()
+
+	invalid := 8
+	if _, err := svc.Save(ctx, deptID, &model.RemoteWorkPolicyRequest{MaxRemoteDaysPerWeek: &invalid}); err == nil {
+		t.Error("Expected error for more than 7 days")
+	}
+
+	days := 3
+	created, err := svc.Save(ctx, deptID, &model.RemoteWorkPolicyRequest{MaxRemoteDaysPerWeek: &days})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	days = 1
+	updated, err := svc.Save(ctx, deptID, &model.RemoteWorkPolicyRequest{MaxRemoteDaysPerWeek: &days})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if updated.ID != created.ID || updated.MaxRemoteDaysPerWeek != 1 {
+		t.Errorf("Expected policy to be updated in place, got %+v", updated)
+	}
+
+	if _, err := svc.GetByDepartment(ctx, uuid.New()); !errors.Is(err, ErrRemoteWorkPolicyNotFound) {
+		t.Errorf("Expected ErrRemoteWorkPolicyNotFound, got %v", err)
+	}
+}
+
+func TestWorkMode_SummaryAndExport(t *testing.T) {
+	deps, userID := setupWorkModeEnv(t, 5)
+	ctx := context.Background()
+	attRepo := deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	for _, a := range []struct {
+		date time.Time
+		mode model.WorkMode
+	}{
+		{time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), model.WorkModeOffice},
+		{time.Date(2024, 1, 16, 0, 0, 0, 0, time.UTC), model.WorkModeRemote},
+		{time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC), model.WorkModeRemote},
+		{time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), model.WorkModeBusinessTrip},
+	} {
+		id := uuid.New()
+		attRepo.Attendances[id] = &model.Attendance{
+			BaseModel: model.BaseModel{ID: id}, UserID: userID, Date: a.date,
+			Status: model.AttendanceStatusPresent, WorkMode: a.mode,
+		}
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	summary, err := NewAttendanceService(deps).GetSummary(ctx, userID, start, end)
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	want := model.WorkModeDays{Office: 1, Remote: 2, BusinessTrip: 1}
+	if summary.WorkModeDays != want {
+		t.Errorf("Expected %+v, got %+v", want, summary.WorkModeDays)
+	}
+	if len(summary.MonthlyWorkModes) != 2 || summary.MonthlyWorkModes[0].Month != "2024-01" || summary.MonthlyWorkModes[0].Remote != 2 {
+		t.Errorf("Unexpected monthly breakdown: %+v", summary.MonthlyWorkModes)
+	}
+
+	data, err := NewExportService(deps).ExportWorkModeCSV(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Export failed: %v", err)
+	}
+	records, err := csv.NewReader(bytes.NewReader(data[3:])).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV: %v", err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("Expected header and 2 rows, got %d rows", len(records))
+	}
+	if got := records[1]; got[1] != "2024-01" || got[2] != "1" || got[3] != "2" || got[6] != "3" {
+		t.Errorf("Unexpected January row: %v", got)
+	}
+}
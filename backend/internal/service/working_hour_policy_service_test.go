@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/your-org/kintai/backend/internal/middleware"
+	"github.com/your-org/kintai/backend/internal/mocks"
+	"github.com/your-org/kintai/backend/internal/model"
+)
+
+type workingHourTestEnv struct {
+	deps   Deps
+	svc    WorkingHourPolicyService
+	deptID uuid.UUID
+	userID uuid.UUID
+}
+
+func setupWorkingHourEnv(t *testing.T) *workingHourTestEnv {
+	deps, _, userRepo := setupOvertimeDeps(t)
+	deps.Repos.WorkingHourPolicy = mocks.NewMockWorkingHourPolicyRepository()
+
+	deptID := uuid.New()
+	userID := uuid.New()
+	user := &model.User{
+		BaseModel: model.BaseModel{ID: userID}, Email: "flex@example.com",
+		FirstName: "Hanako", LastName: "Flex", DepartmentID: &deptID,
+	}
+	userRepo.Users[userID] = user
+	userRepo.UsersByEmail[user.Email] = user
+
+	return &workingHourTestEnv{deps: deps, svc: NewWorkingHourPolicyService(deps), deptID: deptID, userID: userID}
+}
+
+func (e *workingHourTestEnv) addAttendance(date time.Time, in, out string, workMinutes int) {
+	attRepo := e.deps.Repos.Attendance.(*mocks.MockAttendanceRepository)
+	clockIn, _ := time.ParseInLocation("2006-01-02 15:04", date.Format("2006-01-02")+" "+in, time.Local)
+	clockOut, _ := time.ParseInLocation("2006-01-02 15:04", date.Format("2006-01-02")+" "+out, time.Local)
+	id := uuid.New()
+	attRepo.Attendances[id] = &model.Attendance{
+		BaseModel: model.BaseModel{ID: id}, UserID: e.userID, Date: date,
+		ClockIn: &clockIn, ClockOut: &clockOut, WorkMinutes: workMinutes,
+		Status: model.AttendanceStatusPresent,
+	}
+}
+
+func TestWorkingHourPolicyService_Save_Validation(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		req   model.WorkingHourPolicyRequest
+		field string
+	}{
+		{"起算日が29日以降", model.WorkingHourPolicyRequest{System: model.WorkingHourSystemStandard, PeriodStart: "2024-01-29"}, "period_start"},
+		{"フレックスの清算期間が3ヶ月超", model.WorkingHourPolicyRequest{System: model.WorkingHourSystemFlextime, PeriodStart: "2024-01-01", PeriodMonths: 4}, "period_months"},
+		{"1年単位の対象期間が1ヶ月", model.WorkingHourPolicyRequest{System: model.WorkingHourSystemYearlyVariable, PeriodStart: "2024-04-01", PeriodMonths: 1}, "period_months"},
+		{"コアタイムの終了が開始より前", model.WorkingHourPolicyRequest{System: model.WorkingHourSystemFlextime, PeriodStart: "2024-01-01", CoreTimeStart: "15:00", CoreTimeEnd: "10:00"}, "core_time_end"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.svc.Save(ctx, env.deptID, &tt.req)
+			var verr *model.ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected ValidationError, got %v", err)
+			}
+			if verr.Fields[0].Field != tt.field {
+				t.Errorf("Expected error on %s, got %+v", tt.field, verr.Fields)
+			}
+		})
+	}
+}
+
+func TestWorkingHourPolicyService_Save_Upsert(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := context.Background()
+
+	created, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemYearlyVariable, PeriodStart: "2024-04-01",
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if created.PeriodMonths != 12 || created.StandardDailyMinutes != 480 {
+		t.Errorf("Expected defaults 12 months/480 minutes, got %d/%d", created.PeriodMonths, created.StandardDailyMinutes)
+	}
+
+	updated, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemFlextime, PeriodStart: "2024-04-01", CoreTimeStart: "10:00", CoreTimeEnd: "15:00",
+	})
+	if err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if updated.ID != created.ID || updated.PeriodMonths != 1 {
+		t.Errorf("Expected the same policy updated to a 1-month flextime period, got %+v", updated)
+	}
+}
+
+func TestWorkingHourPolicyService_Flextime_SettlesByPeriod(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := middleware.WithSystem(context.Background())
+
+	if _, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemFlextime, PeriodStart: "2024-01-01", StandardDailyMinutes: 450,
+		CoreTimeStart: "10:00", CoreTimeEnd: "15:00",
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	// フレックスタイム制では1日8時間を超えても日ごとの残業にはならない
+	att := approveForBreakdown(t, env.deps, env.userID,
+		time.Date(2024, 1, 15, 9, 0, 0, 0, time.Local), time.Date(2024, 1, 15, 21, 0, 0, 0, time.Local), 60)
+	if att.WorkMinutes != 660 || att.OvertimeMinutes != 0 || att.WithinStatutoryOvertimeMinutes != 0 {
+		t.Errorf("Expected 660 minutes without daily overtime, got work=%d overtime=%d within=%d",
+			att.WorkMinutes, att.OvertimeMinutes, att.WithinStatutoryOvertimeMinutes)
+	}
+
+	// 2024年1月の15日以外の平日22日を1日500分勤務、16日はコアタイム違反
+	for d := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); d.Month() == time.January; d = d.AddDate(0, 0, 1) {
+		if d.Weekday() == time.Saturday || d.Weekday() == time.Sunday || d.Day() == 15 {
+			continue
+		}
+		if d.Day() == 16 {
+			env.addAttendance(d, "11:00", "19:20", 500)
+			continue
+		}
+		env.addAttendance(d, "09:00", "17:20", 500)
+	}
+
+	report, err := env.svc.GetSettlement(ctx, env.deptID, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetSettlement failed: %v", err)
+	}
+	if report.PeriodStart != "2024-01-01" || report.PeriodEnd != "2024-01-31" {
+		t.Errorf("Unexpected period %s〜%s", report.PeriodStart, report.PeriodEnd)
+	}
+	// 所定総労働時間 = 23日×450分、法定労働時間の総枠 = 2400×31÷7
+	if report.RequiredMinutes != 23*450 || report.StatutoryLimitMinutes != 10628 {
+		t.Errorf("Expected required=%d limit=10628, got %d/%d", 23*450, report.RequiredMinutes, report.StatutoryLimitMinutes)
+	}
+	if len(report.Users) != 1 {
+		t.Fatalf("Expected 1 user, got %d", len(report.Users))
+	}
+	row := report.Users[0]
+	actual := 22 * 500
+	if row.ActualMinutes != actual {
+		t.Errorf("Expected %d actual minutes, got %d", actual, row.ActualMinutes)
+	}
+	if row.OvertimeMinutes != actual-10628 {
+		t.Errorf("Expected %d overtime, got %d", actual-10628, row.OvertimeMinutes)
+	}
+	if row.WithinStatutoryOvertimeMinutes != 10628-23*450 || row.ShortageMinutes != 0 {
+		t.Errorf("Expected within=%d shortage=0, got %d/%d", 10628-23*450, row.WithinStatutoryOvertimeMinutes, row.ShortageMinutes)
+	}
+	if len(row.CoreTimeViolations) != 1 || row.CoreTimeViolations[0] != "2024-01-16" {
+		t.Errorf("Expected core time violation on 2024-01-16, got %v", row.CoreTimeViolations)
+	}
+}
+
+// februarySchedule は2024年2月の勤務カレンダー（月曜600分、その他の平日420分、土日休み）を返す
+func februarySchedule() []model.WorkingHourScheduleDayInput {
+	days := make([]model.WorkingHourScheduleDayInput, 0, 29)
+	for d := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC); d.Month() == time.February; d = d.AddDate(0, 0, 1) {
+		minutes := 420
+		switch d.Weekday() {
+		case time.Saturday, time.Sunday:
+			minutes = 0
+		case time.Monday:
+			minutes = 600
+		}
+		days = append(days, model.WorkingHourScheduleDayInput{Date: d.Format("2006-01-02"), ScheduledMinutes: minutes})
+	}
+	return days
+}
+
+func TestWorkingHourPolicyService_MonthlyVariable(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := middleware.WithSystem(context.Background())
+
+	if _, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemMonthlyVariable, PeriodStart: "2024-01-01",
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := env.svc.SetSchedule(ctx, env.deptID, &model.WorkingHourScheduleRequest{
+		PeriodStart: "2024-02-01", Days: februarySchedule(),
+	}); err != nil {
+		t.Fatalf("SetSchedule failed: %v", err)
+	}
+
+	// 所定10時間の月曜は10時間まで残業にならない
+	monday := approveForBreakdown(t, env.deps, env.userID,
+		time.Date(2024, 2, 5, 9, 0, 0, 0, time.Local), time.Date(2024, 2, 5, 20, 0, 0, 0, time.Local), 60)
+	if monday.WorkMinutes != 600 || monday.OvertimeMinutes != 0 || monday.WithinStatutoryOvertimeMinutes != 0 {
+		t.Errorf("Expected no overtime on a 600-minute day, got overtime=%d within=%d", monday.OvertimeMinutes, monday.WithinStatutoryOvertimeMinutes)
+	}
+
+	// 所定7時間の火曜は8時間までが法定内残業、超過分が法定外残業
+	tuesday := approveForBreakdown(t, env.deps, env.userID,
+		time.Date(2024, 2, 6, 9, 0, 0, 0, time.Local), time.Date(2024, 2, 6, 18, 30, 0, 0, time.Local), 60)
+	if tuesday.WithinStatutoryOvertimeMinutes != 60 || tuesday.OvertimeMinutes != 30 {
+		t.Errorf("Expected within=60 overtime=30, got %d/%d", tuesday.WithinStatutoryOvertimeMinutes, tuesday.OvertimeMinutes)
+	}
+
+	report, err := env.svc.GetSettlement(ctx, env.deptID, time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetSettlement failed: %v", err)
+	}
+	if report.RequiredMinutes != 4*600+17*420 {
+		t.Errorf("Expected required minutes %d, got %d", 4*600+17*420, report.RequiredMinutes)
+	}
+}
+
+func TestWorkingHourPolicyService_GetSettlement_ApprovalScope(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	team := mocks.NewMockTeamRepository()
+	env.deps.Repos.Team = team
+	env.svc = NewWorkingHourPolicyService(env.deps)
+	if _, err := env.svc.Save(middleware.WithSystem(context.Background()), env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemFlextime, PeriodStart: "2024-01-01",
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	otherID := uuid.New()
+	userRepo := env.deps.Repos.User.(*mocks.MockUserRepository)
+	userRepo.Users[otherID] = &model.User{BaseModel: model.BaseModel{ID: otherID}, Email: "other@example.com", DepartmentID: &env.deptID}
+
+	managerID := uuid.New()
+	team.Reports[managerID] = []uuid.UUID{env.userID}
+	date := time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC)
+
+	// 直属の部下のみが集計され、同じ部署の他のメンバーは含まれない
+	report, err := env.svc.GetSettlement(actorCtx(managerID, model.RoleManager), env.deptID, date)
+	if err != nil {
+		t.Fatalf("GetSettlement failed: %v", err)
+	}
+	if len(report.Users) != 1 || report.Users[0].UserID != env.userID {
+		t.Errorf("Expected only the direct report, got %+v", report.Users)
+	}
+
+	// 承認範囲に部署のメンバーがいない場合は参照できない
+	if _, err := env.svc.GetSettlement(actorCtx(uuid.New(), model.RoleManager), env.deptID, date); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden outside the approval scope, got %v", err)
+	}
+
+	report, err = env.svc.GetSettlement(actorCtx(uuid.New(), model.RoleAdmin), env.deptID, date)
+	if err != nil || len(report.Users) != 2 {
+		t.Errorf("Expected admin to see all department members, got %v (%v)", report, err)
+	}
+}
+
+func TestWorkingHourPolicyService_SetSchedule_Validation(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := context.Background()
+
+	if _, err := env.svc.SetSchedule(ctx, env.deptID, &model.WorkingHourScheduleRequest{
+		PeriodStart: "2024-02-01", Days: februarySchedule(),
+	}); !errors.Is(err, ErrWorkingHourPolicyNotFound) {
+		t.Errorf("Expected ErrWorkingHourPolicyNotFound, got %v", err)
+	}
+
+	if _, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemMonthlyVariable, PeriodStart: "2024-01-01",
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	over := februarySchedule()
+	for i := range over {
+		if over[i].ScheduledMinutes > 0 {
+			over[i].ScheduledMinutes = 480
+		}
+	}
+	tests := []struct {
+		name string
+		req  model.WorkingHourScheduleRequest
+	}{
+		{"期間の開始日ではない", model.WorkingHourScheduleRequest{PeriodStart: "2024-02-02", Days: februarySchedule()}},
+		{"期間内の日が不足", model.WorkingHourScheduleRequest{PeriodStart: "2024-02-01", Days: februarySchedule()[:28]}},
+		{"法定労働時間の総枠を超過", model.WorkingHourScheduleRequest{PeriodStart: "2024-02-01", Days: over}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := env.svc.SetSchedule(ctx, env.deptID, &tt.req)
+			var verr *model.ValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("Expected ValidationError, got %v", err)
+			}
+		})
+	}
+}
+
+func TestWorkingHourPolicyService_YearlyPeriodBoundaries(t *testing.T) {
+	env := setupWorkingHourEnv(t)
+	ctx := middleware.WithSystem(context.Background())
+
+	if _, err := env.svc.Save(ctx, env.deptID, &model.WorkingHourPolicyRequest{
+		System: model.WorkingHourSystemYearlyVariable, PeriodStart: "2024-04-01",
+	}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+
+	tests := []struct {
+		date       time.Time
+		start, end string
+	}{
+		{time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC), "2024-04-01", "2025-03-31"},
+		{time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC), "2025-04-01", "2026-03-31"},
+		{time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), "2023-04-01", "2024-03-31"},
+	}
+	for _, tt := range tests {
+		report, err := env.svc.GetSettlement(ctx, env.deptID, tt.date)
+		if err != nil {
+			t.Fatalf("GetSettlement failed: %v", err)
+		}
+		if report.PeriodStart != tt.start || report.PeriodEnd != tt.end {
+			t.Errorf("%s: expected %s〜%s, got %s〜%s", tt.date.Format("2006-01-02"), tt.start, tt.end, report.PeriodStart, report.PeriodEnd)
+		}
+	}
+}
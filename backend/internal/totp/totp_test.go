@@ -0,0 +1,64 @@
+package totp
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// RFC 6238 付録 B の SHA1 テストベクター（8桁の下位6桁）
+func TestCodeAt_RFC6238Vectors(t *testing.T) {
+	secret := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+	cases := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+	for _, tc := range cases {
+		got, err := CodeAt(secret, Step(time.Unix(tc.unix, 0)))
+		if err != nil {
+			t.Fatalf("CodeAt failed: %v", err)
+		}
+		if got != tc.want {
+			t.Errorf("T=%d: expected %s, got %s", tc.unix, tc.want, got)
+		}
+	}
+}
+
+func TestValidate_Skew(t *testing.T) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret failed: %v", err)
+	}
+	now := time.Unix(1700000000, 0)
+	prev, _ := CodeAt(secret, Step(now)-1)
+
+	step, ok := Validate(secret, prev, now, 1)
+	if !ok || step != Step(now)-1 {
+		t.Errorf("Previous step code should be accepted with skew 1, got step=%d ok=%v", step, ok)
+	}
+	if _, ok := Validate(secret, prev, now, 0); ok {
+		t.Error("Previous step code should be rejected without skew")
+	}
+	if _, ok := Validate(secret, "12345", now, 1); ok {
+		t.Error("Short code should be rejected")
+	}
+}
+
+func TestProvisioningURI(t *testing.T) {
+	uri := ProvisioningURI("Kintai", "user@example.com", "JBSWY3DPEHPK3PXP")
+	if !strings.HasPrefix(uri, "otpauth://totp/Kintai:user@example.com?") {
+		t.Errorf("Unexpected label: %s", uri)
+	}
+	for _, p := range []string{"secret=JBSWY3DPEHPK3PXP", "issuer=Kintai", "digits=6", "period=30"} {
+		if !strings.Contains(uri, p) {
+			t.Errorf("Expected %s in %s", p, uri)
+		}
+	}
+}
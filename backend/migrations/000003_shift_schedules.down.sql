@@ -0,0 +1,7 @@
+-- 000003_shift_schedules.down.sql
+-- シフト表の公開・バージョン管理ロールバック
+
+DROP INDEX IF EXISTS idx_shifts_schedule_id;
+ALTER TABLE shifts DROP COLUMN IF EXISTS schedule_id;
+DROP TABLE IF EXISTS shift_schedule_versions;
+DROP TABLE IF EXISTS shift_schedules;
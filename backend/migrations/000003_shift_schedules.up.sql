@@ -0,0 +1,40 @@
+-- 000003_shift_schedules.up.sql
+-- シフト表の公開・バージョン管理
+
+-- ===== シフト表テーブル =====
+CREATE TABLE IF NOT EXISTS shift_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    department_id UUID NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    version INT NOT NULL DEFAULT 0,
+    published_at TIMESTAMPTZ,
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_schedules_department_id ON shift_schedules(department_id);
+CREATE INDEX IF NOT EXISTS idx_shift_schedules_period ON shift_schedules(period_start, period_end);
+
+-- ===== シフト表バージョンテーブル =====
+CREATE TABLE IF NOT EXISTS shift_schedule_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    schedule_id UUID NOT NULL REFERENCES shift_schedules(id) ON DELETE CASCADE,
+    version INT NOT NULL,
+    shifts JSONB,
+    change_note VARCHAR(500),
+    published_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    UNIQUE(schedule_id, version)
+);
+
+CREATE INDEX IF NOT EXISTS idx_shift_schedule_versions_schedule_id ON shift_schedule_versions(schedule_id);
+
+-- ===== シフトにシフト表IDカラム追加 =====
+ALTER TABLE shifts ADD COLUMN IF NOT EXISTS schedule_id UUID REFERENCES shift_schedules(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_shifts_schedule_id ON shifts(schedule_id);
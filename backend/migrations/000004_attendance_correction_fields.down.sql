@@ -0,0 +1,9 @@
+-- 000004_attendance_correction_fields.down.sql
+-- 勤怠修正申請の追加項目ロールバック
+
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS corrected_note;
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS original_note;
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS corrected_status;
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS original_status;
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS corrected_break_minutes;
+ALTER TABLE attendance_corrections DROP COLUMN IF EXISTS original_break_minutes;
@@ -0,0 +1,9 @@
+-- 000004_attendance_correction_fields.up.sql
+-- 勤怠修正申請に休憩・ステータス・備考の修正項目を追加
+
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS original_break_minutes INT;
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS corrected_break_minutes INT;
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS original_status VARCHAR(20);
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS corrected_status VARCHAR(20);
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS original_note VARCHAR(500);
+ALTER TABLE attendance_corrections ADD COLUMN IF NOT EXISTS corrected_note VARCHAR(500);
@@ -0,0 +1,6 @@
+-- 000005_substitute_holidays.down.sql
+-- 振替休日・代休ロールバック
+
+DROP TABLE IF EXISTS compensatory_days;
+DROP TABLE IF EXISTS substitute_holiday_requests;
+ALTER TABLE attendances DROP COLUMN IF EXISTS holiday_work_minutes;
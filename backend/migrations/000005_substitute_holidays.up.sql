@@ -0,0 +1,42 @@
+-- 000005_substitute_holidays.up.sql
+-- 振替休日・代休
+
+-- ===== 勤怠テーブルに休日労働時間カラム追加 =====
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS holiday_work_minutes INT NOT NULL DEFAULT 0;
+
+-- ===== 振替休日・代休申請テーブル =====
+CREATE TABLE IF NOT EXISTS substitute_holiday_requests (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    work_date DATE,
+    holiday_date DATE NOT NULL,
+    compensatory_day_id UUID,
+    reason VARCHAR(500),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    approved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    approved_at TIMESTAMPTZ,
+    rejected_reason VARCHAR(500),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_substitute_holiday_requests_user_id ON substitute_holiday_requests(user_id);
+CREATE INDEX IF NOT EXISTS idx_substitute_holiday_requests_status ON substitute_holiday_requests(status);
+
+-- ===== 代休テーブル =====
+CREATE TABLE IF NOT EXISTS compensatory_days (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    earned_date DATE NOT NULL,
+    expires_at DATE NOT NULL,
+    used_date DATE,
+    request_id UUID REFERENCES substitute_holiday_requests(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    UNIQUE(user_id, earned_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_compensatory_days_user_id ON compensatory_days(user_id);
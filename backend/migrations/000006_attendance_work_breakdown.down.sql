@@ -0,0 +1,6 @@
+-- 000006_attendance_work_breakdown.down.sql
+-- 勤怠の労働時間内訳ロールバック
+
+ALTER TABLE attendances DROP COLUMN IF EXISTS overtime_over60_minutes;
+ALTER TABLE attendances DROP COLUMN IF EXISTS late_night_minutes;
+ALTER TABLE attendances DROP COLUMN IF EXISTS within_statutory_overtime_minutes;
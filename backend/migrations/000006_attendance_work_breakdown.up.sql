@@ -0,0 +1,6 @@
+-- 000006_attendance_work_breakdown.up.sql
+-- 勤怠の労働時間内訳（法定内残業・深夜労働・月60時間超残業）
+
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS within_statutory_overtime_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS late_night_minutes INT NOT NULL DEFAULT 0;
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS overtime_over60_minutes INT NOT NULL DEFAULT 0;
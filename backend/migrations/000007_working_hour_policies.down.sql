@@ -0,0 +1,5 @@
+-- 000007_working_hour_policies.down.sql
+-- 労働時間制度ロールバック
+
+DROP TABLE IF EXISTS working_hour_schedule_days;
+DROP TABLE IF EXISTS working_hour_policies;
@@ -0,0 +1,29 @@
+-- 000007_working_hour_policies.up.sql
+-- 部署ごとの労働時間制度（フレックスタイム制・変形労働時間制）
+
+-- ===== 労働時間制度テーブル =====
+CREATE TABLE IF NOT EXISTS working_hour_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    department_id UUID NOT NULL UNIQUE REFERENCES departments(id) ON DELETE CASCADE,
+    system VARCHAR(30) NOT NULL DEFAULT 'standard',
+    period_start DATE NOT NULL,
+    period_months INT NOT NULL DEFAULT 1,
+    standard_daily_minutes INT NOT NULL DEFAULT 480,
+    core_time_start VARCHAR(5),
+    core_time_end VARCHAR(5),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+-- ===== 変形労働時間制の勤務カレンダー =====
+CREATE TABLE IF NOT EXISTS working_hour_schedule_days (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    policy_id UUID NOT NULL REFERENCES working_hour_policies(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    scheduled_minutes INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    UNIQUE(policy_id, date)
+);
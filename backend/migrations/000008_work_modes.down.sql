@@ -0,0 +1,5 @@
+-- 000008_work_modes.down.sql
+-- 勤務形態ロールバック
+
+DROP TABLE IF EXISTS remote_work_policies;
+ALTER TABLE attendances DROP COLUMN IF EXISTS work_mode;
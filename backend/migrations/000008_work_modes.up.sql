@@ -0,0 +1,15 @@
+-- 000008_work_modes.up.sql
+-- 勤務形態（出社・在宅・客先・出張）と部署ごとの在宅勤務ポリシー
+
+-- ===== 勤怠テーブルへの勤務形態の追加 =====
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS work_mode VARCHAR(20) NOT NULL DEFAULT 'office';
+
+-- ===== 在宅勤務ポリシーテーブル =====
+CREATE TABLE IF NOT EXISTS remote_work_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    department_id UUID NOT NULL UNIQUE REFERENCES departments(id) ON DELETE CASCADE,
+    max_remote_days_per_week INT NOT NULL DEFAULT 5,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
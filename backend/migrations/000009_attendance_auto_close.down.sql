@@ -0,0 +1,5 @@
+-- 000009_attendance_auto_close.down.sql
+-- 退勤打刻漏れの自動処理ロールバック
+
+DROP INDEX IF EXISTS idx_attendances_open;
+ALTER TABLE attendances DROP COLUMN IF EXISTS auto_closed_at;
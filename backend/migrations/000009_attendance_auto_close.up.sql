@@ -0,0 +1,6 @@
+-- 000009_attendance_auto_close.up.sql
+-- 退勤打刻漏れの自動処理（自動締め日時の記録と未確定勤怠の検索用インデックス）
+
+ALTER TABLE attendances ADD COLUMN IF NOT EXISTS auto_closed_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_attendances_open ON attendances(date) WHERE clock_in IS NOT NULL AND clock_out IS NULL;
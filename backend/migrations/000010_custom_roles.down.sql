@@ -0,0 +1,5 @@
+-- 000010_custom_roles.down.sql
+-- カスタムロールロールバック
+
+DROP TABLE IF EXISTS user_role_assignments;
+DROP TABLE IF EXISTS custom_roles;
@@ -0,0 +1,15 @@
+-- 000011_refresh_token_rotation.down.sql
+-- リフレッシュトークンのハッシュ保存ロールバック
+
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS used_at,
+    DROP COLUMN IF EXISTS family_id,
+    DROP COLUMN IF EXISTS token_hash;
+
+ALTER TABLE refresh_tokens ADD COLUMN token VARCHAR(500) NOT NULL UNIQUE;
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_token ON refresh_tokens(token);
@@ -0,0 +1,16 @@
+-- 000011_refresh_token_rotation.up.sql
+-- リフレッシュトークンのハッシュ保存・ローテーション・再利用検知
+
+-- 平文トークンの行は破棄する（発行時に保存されていなかったため実質的に空）
+DELETE FROM refresh_tokens;
+
+DROP INDEX IF EXISTS idx_refresh_tokens_token;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token;
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN token_hash VARCHAR(64) NOT NULL UNIQUE,
+    ADD COLUMN family_id UUID NOT NULL,
+    ADD COLUMN used_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_family_id ON refresh_tokens(family_id);
+CREATE INDEX IF NOT EXISTS idx_refresh_tokens_expires_at ON refresh_tokens(expires_at);
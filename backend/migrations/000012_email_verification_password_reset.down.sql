@@ -0,0 +1,6 @@
+-- 000012_email_verification_password_reset.down.sql
+-- メールアドレス確認・パスワードリセットのロールバック
+
+DROP TABLE IF EXISTS user_tokens;
+
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
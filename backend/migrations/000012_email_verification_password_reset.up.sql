@@ -0,0 +1,25 @@
+-- 000012_email_verification_password_reset.up.sql
+-- メールアドレス確認・パスワードリセット用のワンタイムトークン
+
+-- ===== メールアドレス確認日時 =====
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMPTZ;
+
+-- 既存ユーザーは確認済みとして扱う
+UPDATE users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+-- ===== ワンタイムトークンテーブル =====
+-- トークン本体は保存せず SHA-256 ハッシュのみを保持する
+CREATE TABLE IF NOT EXISTS user_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(30) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    expires_at TIMESTAMPTZ NOT NULL,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_tokens_user_id ON user_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_tokens_expires_at ON user_tokens(expires_at);
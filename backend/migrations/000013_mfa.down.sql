@@ -0,0 +1,6 @@
+-- 000013_mfa.down.sql
+-- 多要素認証のロールバック
+
+DROP TABLE IF EXISTS mfa_policies;
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_mfas;
@@ -0,0 +1,39 @@
+-- 000013_mfa.up.sql
+-- TOTPによる多要素認証（登録情報・リカバリーコード・必須ロールのポリシー）
+
+-- ===== TOTP設定テーブル =====
+-- enabled_at が NULL の間は登録途中でログインには使われない
+CREATE TABLE IF NOT EXISTS user_mfas (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(64) NOT NULL,
+    enabled_at TIMESTAMPTZ,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+-- ===== リカバリーコードテーブル =====
+-- コード本体は保存せず SHA-256 ハッシュのみを保持する
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    code_hash VARCHAR(64) NOT NULL UNIQUE,
+    used_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_mfa_recovery_codes_user_id ON mfa_recovery_codes(user_id);
+
+-- ===== MFAポリシーテーブル（全社で1件） =====
+CREATE TABLE IF NOT EXISTS mfa_policies (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    required_roles JSONB NOT NULL DEFAULT '[]',
+    required_custom_role_ids JSONB NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
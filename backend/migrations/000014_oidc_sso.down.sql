@@ -0,0 +1,6 @@
+-- 000014_oidc_sso.down.sql
+-- シングルサインオンのロールバック
+
+DROP TABLE IF EXISTS user_identities;
+
+ALTER TABLE users DROP COLUMN IF EXISTS password_login_disabled;
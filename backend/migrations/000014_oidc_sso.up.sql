@@ -0,0 +1,22 @@
+-- 000014_oidc_sso.up.sql
+-- OpenID Connect によるシングルサインオン（外部IDの紐付け・パスワードログインの無効化）
+
+-- ===== パスワードログインの無効化 =====
+ALTER TABLE users ADD COLUMN IF NOT EXISTS password_login_disabled BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- ===== 外部IDテーブル =====
+-- IdPのユーザーは発行者（issuer）と sub の組で識別する
+CREATE TABLE IF NOT EXISTS user_identities (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issuer VARCHAR(255) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    last_login_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_issuer_subject ON user_identities(issuer, subject);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
@@ -0,0 +1,5 @@
+-- 000015_login_security.down.sql
+-- ロックアウトとログイン履歴のロールバック
+
+DROP TABLE IF EXISTS login_lockouts;
+DROP TABLE IF EXISTS login_histories;
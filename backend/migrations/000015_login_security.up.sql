@@ -0,0 +1,40 @@
+-- 000015_login_security.up.sql
+-- ログインの連続失敗によるロックアウトとログイン履歴
+
+-- ===== ログイン履歴テーブル =====
+-- 未登録のメールアドレスへの試行は user_id が NULL
+CREATE TABLE IF NOT EXISTS login_histories (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    email VARCHAR(255) NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    mfa BOOLEAN NOT NULL DEFAULT FALSE,
+    success BOOLEAN NOT NULL,
+    failure_reason VARCHAR(50),
+    ip_address VARCHAR(45),
+    user_agent VARCHAR(500),
+    new_device BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_histories_user_id ON login_histories(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_login_histories_success ON login_histories(success);
+CREATE INDEX IF NOT EXISTS idx_login_histories_created_at ON login_histories(created_at);
+
+-- ===== ロックアウトテーブル =====
+-- アカウントごとの連続失敗回数とロック状態（ログイン成功・管理者の解除で削除する）
+CREATE TABLE IF NOT EXISTS login_lockouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    lockout_count INTEGER NOT NULL DEFAULT 0,
+    locked_until TIMESTAMPTZ,
+    last_failed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_login_lockouts_user_id ON login_lockouts(user_id);
@@ -0,0 +1,7 @@
+-- 000016_sessions.down.sql
+-- セッション情報のロールバック
+
+ALTER TABLE refresh_tokens
+    DROP COLUMN IF EXISTS login_at,
+    DROP COLUMN IF EXISTS user_agent,
+    DROP COLUMN IF EXISTS ip_address;
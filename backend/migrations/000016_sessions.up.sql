@@ -0,0 +1,17 @@
+-- 000016_sessions.up.sql
+-- セッション一覧のためにリフレッシュトークンへ接続元とログイン日時を記録する
+
+ALTER TABLE refresh_tokens
+    ADD COLUMN ip_address VARCHAR(45),
+    ADD COLUMN user_agent VARCHAR(500),
+    ADD COLUMN login_at TIMESTAMPTZ NOT NULL DEFAULT NOW();
+
+-- 既存のトークンはファミリーの最初の発行日時をログイン日時とする
+UPDATE refresh_tokens rt
+SET login_at = f.first_created_at
+FROM (
+    SELECT family_id, MIN(created_at) AS first_created_at
+    FROM refresh_tokens
+    GROUP BY family_id
+) f
+WHERE rt.family_id = f.family_id;
@@ -0,0 +1,5 @@
+-- 000017_api_tokens.down.sql
+-- APIトークンとサービスアカウントのロールバック
+
+DROP TABLE IF EXISTS api_tokens;
+DROP TABLE IF EXISTS service_accounts;
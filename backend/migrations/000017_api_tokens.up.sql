@@ -0,0 +1,43 @@
+-- 000017_api_tokens.up.sql
+-- 連携用の個人用アクセストークンとサービスアカウント
+
+-- ===== サービスアカウントテーブル =====
+CREATE TABLE IF NOT EXISTS service_accounts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    description VARCHAR(500),
+    role VARCHAR(20) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_service_accounts_name ON service_accounts(name);
+CREATE INDEX IF NOT EXISTS idx_service_accounts_deleted_at ON service_accounts(deleted_at);
+
+-- ===== APIトークンテーブル =====
+-- user_id（個人用アクセストークン）と service_account_id のどちらか一方を持つ
+CREATE TABLE IF NOT EXISTS api_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE CASCADE,
+    service_account_id UUID REFERENCES service_accounts(id) ON DELETE CASCADE,
+    token_hash VARCHAR(64) NOT NULL,
+    prefix VARCHAR(20) NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]',
+    expires_at TIMESTAMPTZ NOT NULL,
+    last_used_at TIMESTAMPTZ,
+    last_used_ip VARCHAR(45),
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ,
+    CONSTRAINT chk_api_tokens_owner CHECK ((user_id IS NULL) <> (service_account_id IS NULL))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_api_tokens_token_hash ON api_tokens(token_hash);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_user_id ON api_tokens(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_service_account_id ON api_tokens(service_account_id);
+CREATE INDEX IF NOT EXISTS idx_api_tokens_deleted_at ON api_tokens(deleted_at);
@@ -0,0 +1,38 @@
+-- 000018_audit_logs.up.sql
+-- ハッシュチェーン付きの監査ログ（追記専用）
+
+-- ===== 監査ログテーブル =====
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    seq BIGINT NOT NULL,
+    occurred_at TIMESTAMPTZ NOT NULL,
+    actor_id UUID,
+    actor_type VARCHAR(20) NOT NULL,
+    action VARCHAR(20) NOT NULL,
+    entity_type VARCHAR(100) NOT NULL,
+    entity_id VARCHAR(100),
+    changes TEXT,
+    ip_address VARCHAR(45),
+    request_id VARCHAR(64),
+    prev_hash VARCHAR(64) NOT NULL,
+    hash VARCHAR(64) NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_seq ON audit_logs(seq);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_occurred_at ON audit_logs(occurred_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity ON audit_logs(entity_type, entity_id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_request_id ON audit_logs(request_id);
+
+-- ===== 更新・削除の禁止 =====
+-- 記録の書き換えはハッシュ検証でも検出できるが、DBレベルでも拒否しておく
+CREATE OR REPLACE FUNCTION audit_logs_immutable() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_logs is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_logs_immutable ON audit_logs;
+CREATE TRIGGER trg_audit_logs_immutable
+    BEFORE UPDATE OR DELETE ON audit_logs
+    FOR EACH ROW EXECUTE FUNCTION audit_logs_immutable();
@@ -0,0 +1,5 @@
+-- 000019_notification_preferences.down.sql
+-- 通知の受信設定のロールバック
+
+DROP TABLE IF EXISTS notification_settings;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,52 @@
+-- 000019_notification_preferences.up.sql
+-- 通知種別・チャネルごとの受信設定とおやすみ時間帯
+
+-- ===== 受信設定 =====
+-- 行がない組み合わせはアプリの既定値（チャット以外は有効）に従う
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_preferences_key ON notification_preferences(user_id, type, channel);
+CREATE INDEX IF NOT EXISTS idx_notification_preferences_deleted_at ON notification_preferences(deleted_at);
+
+-- ===== 通知設定（おやすみ時間帯） =====
+CREATE TABLE IF NOT EXISTS notification_settings (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    quiet_hours_enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    quiet_hours_start VARCHAR(5) NOT NULL DEFAULT '22:00',
+    quiet_hours_end VARCHAR(5) NOT NULL DEFAULT '07:00',
+    time_zone VARCHAR(50) NOT NULL DEFAULT 'Asia/Tokyo',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_settings_user_id ON notification_settings(user_id);
+CREATE INDEX IF NOT EXISTS idx_notification_settings_deleted_at ON notification_settings(deleted_at);
+
+-- ===== 経費の通知設定の移行 =====
+-- 経費の通知設定（メール・プッシュ・承認通知）で停止していたものを受信設定に引き継ぐ
+DO $$
+BEGIN
+    IF to_regclass('expense_notification_settings') IS NOT NULL THEN
+        INSERT INTO notification_preferences (user_id, type, channel, enabled)
+        SELECT s.user_id, t.type, c.channel, FALSE
+        FROM expense_notification_settings s
+        CROSS JOIN (VALUES ('expense_approved'), ('expense_rejected'), ('expense_comment')) AS t(type)
+        CROSS JOIN (VALUES ('in_app'), ('email'), ('push')) AS c(channel)
+        WHERE s.deleted_at IS NULL
+          AND ((c.channel = 'email' AND NOT s.email_enabled)
+            OR (c.channel = 'push' AND NOT s.push_enabled)
+            OR (t.type IN ('expense_approved', 'expense_rejected') AND NOT s.approval_alerts))
+        ON CONFLICT (user_id, type, channel) DO NOTHING;
+    END IF;
+END $$;
@@ -0,0 +1,31 @@
+-- 000020_merge_expense_notifications.down.sql
+-- 経費の通知を expense_notifications に戻す
+
+CREATE TABLE IF NOT EXISTS expense_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL,
+    expense_id UUID,
+    type VARCHAR(50) NOT NULL,
+    message VARCHAR(1000) NOT NULL,
+    is_read BOOLEAN DEFAULT FALSE,
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    deleted_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS idx_expense_notifications_user_id ON expense_notifications(user_id);
+CREATE INDEX IF NOT EXISTS idx_expense_notifications_deleted_at ON expense_notifications(deleted_at);
+
+INSERT INTO expense_notifications (id, user_id, expense_id, type, message, is_read, read_at, created_at, updated_at, deleted_at)
+SELECT id, user_id, expense_id,
+    CASE WHEN type LIKE 'expense\_%' THEN substring(type FROM 9) ELSE type END,
+    message, is_read, read_at, created_at, updated_at, deleted_at
+FROM notifications
+WHERE expense_id IS NOT NULL OR type LIKE 'expense\_%'
+ON CONFLICT (id) DO NOTHING;
+
+DELETE FROM notifications WHERE expense_id IS NOT NULL OR type LIKE 'expense\_%';
+
+DROP INDEX IF EXISTS idx_notifications_expense_id;
+ALTER TABLE notifications DROP COLUMN IF EXISTS expense_id;
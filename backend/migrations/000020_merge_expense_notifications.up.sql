@@ -0,0 +1,41 @@
+-- 000020_merge_expense_notifications.up.sql
+-- 経費の通知（expense_notifications）を共通の通知（notifications）に統合する
+
+-- ===== 経費申請へのリンク =====
+ALTER TABLE notifications ADD COLUMN IF NOT EXISTS expense_id UUID;
+CREATE INDEX IF NOT EXISTS idx_notifications_expense_id ON notifications(expense_id);
+
+-- ===== 既存データの移行 =====
+-- 種別は expense_ を付けた共通の種別に変換する（未知の種別は general）。ID は引き継ぐ
+DO $$
+BEGIN
+    IF to_regclass('expense_notifications') IS NOT NULL THEN
+        INSERT INTO notifications (id, user_id, type, title, message, is_read, read_at, link_url, expense_id, created_at, updated_at, deleted_at)
+        SELECT
+            n.id,
+            n.user_id,
+            CASE WHEN n.type IN ('submitted', 'approved', 'rejected', 'comment', 'reimbursed', 'reminder')
+                THEN 'expense_' || n.type ELSE 'general' END,
+            CASE n.type
+                WHEN 'submitted' THEN '経費申請が提出されました'
+                WHEN 'approved' THEN '経費申請が承認されました'
+                WHEN 'rejected' THEN '経費申請が却下されました'
+                WHEN 'comment' THEN '経費申請にコメントがありました'
+                WHEN 'reimbursed' THEN '経費の精算が完了しました'
+                WHEN 'reminder' THEN '経費申請のリマインド'
+                ELSE '経費のお知らせ'
+            END,
+            n.message,
+            n.is_read,
+            n.read_at,
+            CASE WHEN n.expense_id IS NOT NULL THEN '/expenses/' || n.expense_id::text ELSE NULL END,
+            n.expense_id,
+            n.created_at,
+            n.updated_at,
+            n.deleted_at
+        FROM expense_notifications n
+        ON CONFLICT (id) DO NOTHING;
+
+        DROP TABLE expense_notifications;
+    END IF;
+END $$;
@@ -0,0 +1,5 @@
+-- 000021_notification_chat.down.sql
+-- チャット通知の配信先を削除する
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS chat_webhook_url;
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS chat_provider;
@@ -0,0 +1,5 @@
+-- 000021_notification_chat.up.sql
+-- チャット通知（Slack / Microsoft Teams の Incoming Webhook）の配信先を通知設定に追加する
+
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS chat_provider VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS chat_webhook_url VARCHAR(500) NOT NULL DEFAULT '';
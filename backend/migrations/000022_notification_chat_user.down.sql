@@ -0,0 +1,4 @@
+-- 000022_notification_chat_user.down.sql
+-- 通知設定のチャットのアカウントを削除する
+
+ALTER TABLE notification_settings DROP COLUMN IF EXISTS chat_user_id;
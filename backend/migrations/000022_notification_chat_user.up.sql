@@ -0,0 +1,4 @@
+-- 000022_notification_chat_user.up.sql
+-- チャットの承認・却下ボタンを押した人と照合する、本人のチャットのアカウントを通知設定に追加する
+
+ALTER TABLE notification_settings ADD COLUMN IF NOT EXISTS chat_user_id VARCHAR(255) NOT NULL DEFAULT '';
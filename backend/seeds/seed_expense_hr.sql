@@ -764,12 +764,19 @@ ON CONFLICT DO NOTHING;
 -- ============================================================
 -- ■ 経費通知データ
 -- ============================================================
-INSERT INTO expense_notifications (id, user_id, expense_id, type, message, is_read, read_at, created_at, updated_at)
+INSERT INTO notifications (id, user_id, expense_id, type, title, message, is_read, read_at, link_url, created_at, updated_at)
 SELECT
     gen_random_uuid(),
     e.user_id,
     e.id,
-    (ARRAY['submitted', 'approved', 'rejected', 'reimbursed', 'reminder'])[1 + (row_number() OVER() % 5)::int],
+    (ARRAY['expense_submitted', 'expense_approved', 'expense_rejected', 'expense_reimbursed', 'expense_reminder'])[1 + (row_number() OVER() % 5)::int],
+    (ARRAY[
+        '経費申請が提出されました',
+        '経費申請が承認されました',
+        '経費申請が却下されました',
+        '経費の精算が完了しました',
+        '経費申請のリマインド'
+    ])[1 + (row_number() OVER() % 5)::int],
     (ARRAY[
         '経費申請「' || e.title || '」が提出されました。',
         '経費申請「' || e.title || '」が承認されました。',
@@ -779,6 +786,7 @@ SELECT
     ])[1 + (row_number() OVER() % 5)::int],
     random() < 0.7,
     CASE WHEN random() < 0.7 THEN NOW() - (floor(random() * 10) || ' days')::interval ELSE NULL END,
+    '/expenses/' || e.id::text,
     e.created_at + interval '1 hour',
     NOW()
 FROM expenses e
@@ -890,7 +898,6 @@ ANALYZE expense_histories;
 ANALYZE expense_templates;
 ANALYZE expense_policies;
 ANALYZE expense_budgets;
-ANALYZE expense_notifications;
 ANALYZE expense_reminders;
 ANALYZE expense_notification_settings;
 ANALYZE expense_approval_flows;
@@ -929,7 +936,7 @@ UNION ALL SELECT '', 'expense_items', COUNT(*) FROM expense_items
 UNION ALL SELECT '', 'expense_comments', COUNT(*) FROM expense_comments
 UNION ALL SELECT '', 'expense_histories', COUNT(*) FROM expense_histories
 UNION ALL SELECT '', 'expense_templates', COUNT(*) FROM expense_templates
-UNION ALL SELECT '', 'expense_notifications', COUNT(*) FROM expense_notifications
+UNION ALL SELECT '', 'notifications (expense)', COUNT(*) FROM notifications WHERE expense_id IS NOT NULL
 UNION ALL SELECT '', 'expense_notification_settings', COUNT(*) FROM expense_notification_settings
 UNION ALL SELECT '', 'expense_delegates', COUNT(*) FROM expense_delegates
 UNION ALL SELECT '', 'expense_reminders', COUNT(*) FROM expense_reminders
@@ -61,6 +61,10 @@ vi.mock('@/stores/authStore', () => ({
   }),
 }));
 
+vi.mock('@/lib/eventStream', () => ({
+  useEventStream: vi.fn(),
+}));
+
 vi.mock('@/stores/themeStore', () => ({
   useThemeStore: () => ({
     theme: mocks.theme,
@@ -5,6 +5,7 @@ import { useThemeStore, applyTheme } from '@/stores/themeStore';
 import { useState, useEffect, useMemo, useCallback } from 'react';
 import { AppSwitcher } from './AppSwitcher';
 import { getActiveApp } from '@/config/apps';
+import { useEventStream } from '@/lib/eventStream';
 
 // Material Symbols icon component
 function MaterialIcon({ name, className = '' }: { name: string; className?: string }) {
@@ -24,6 +25,9 @@ export function Layout() {
   });
   const [mobileDrawerOpen, setMobileDrawerOpen] = useState(false);
 
+  // 通知・承認状況・お知らせをリアルタイムに反映する
+  useEventStream();
+
   // モバイルドロワーが開いている時はスクロールを無効化
   useEffect(() => {
     if (mobileDrawerOpen) {
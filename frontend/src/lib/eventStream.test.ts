@@ -0,0 +1,49 @@
+import { describe, it, expect } from 'vitest';
+import { parseServerSentEvents, queryKeysForEvent } from './eventStream';
+
+describe('parseServerSentEvents', () => {
+  it('should parse complete events and keep the incomplete tail', () => {
+    const { events, rest, retry } = parseServerSentEvents(
+      'retry: 3000\n\nid: 1\nevent: notification\ndata: {"title":"a"}\n\n: ping\n\nid: 2\nevent: appr',
+    );
+    expect(retry).toBe(3000);
+    expect(events).toEqual([{ id: '1', event: 'notification', data: '{"title":"a"}' }]);
+    expect(rest).toBe('id: 2\nevent: appr');
+  });
+
+  it('should continue parsing with the remaining buffer', () => {
+    const first = parseServerSentEvents('id: 2\nevent: appr');
+    const second = parseServerSentEvents(first.rest + 'oval\ndata: {}\n\n');
+    expect(second.events).toEqual([{ id: '2', event: 'approval', data: '{}' }]);
+    expect(second.rest).toBe('');
+  });
+
+  it('should handle CRLF line endings and multi-line data', () => {
+    const { events } = parseServerSentEvents('data: a\r\ndata: b\r\n\r\n');
+    expect(events).toEqual([{ id: undefined, event: 'message', data: 'a\nb' }]);
+  });
+});
+
+describe('queryKeysForEvent', () => {
+  it('should refresh notifications on new notification', () => {
+    expect(queryKeysForEvent({ event: 'notification', data: '{}' })).toContainEqual(['notifications']);
+  });
+
+  it('should refresh the list matching the approval kind', () => {
+    expect(queryKeysForEvent({ event: 'approval', data: '{"kind":"leave","id":"x","status":"approved"}' })).toEqual([
+      ['leaves'],
+    ]);
+    expect(queryKeysForEvent({ event: 'approval', data: '{"kind":"expense","id":"e1","status":"rejected"}' })).toContainEqual([
+      'expense',
+      'e1',
+    ]);
+    expect(queryKeysForEvent({ event: 'approval', data: 'broken' })).toEqual([]);
+  });
+
+  it('should refresh everything on resync', () => {
+    const keys = queryKeysForEvent({ event: 'resync', data: '{}' });
+    expect(keys).toContainEqual(['notifications']);
+    expect(keys).toContainEqual(['overtime']);
+    expect(keys).toContainEqual(['hr-announcements']);
+  });
+});
@@ -0,0 +1,150 @@
+import { useEffect } from 'react';
+import { useQueryClient, type QueryKey } from '@tanstack/react-query';
+import { useAuthStore } from '@/stores/authStore';
+
+const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || '/api/v1';
+
+// サーバーが retry を送らない場合の再接続までの待ち時間（ミリ秒）
+const DEFAULT_RETRY_MS = 3000;
+
+export interface ServerSentEvent {
+  id?: string;
+  event: string;
+  data: string;
+}
+
+/**
+ * 受信したテキストを SSE のイベントに分解する。
+ * 末尾の未完成のイベントは rest として返し、次の受信分と連結して渡す。
+ */
+export function parseServerSentEvents(buffer: string): {
+  events: ServerSentEvent[];
+  rest: string;
+  retry?: number;
+} {
+  const blocks = buffer.replace(/\r\n/g, '\n').split('\n\n');
+  const rest = blocks.pop() ?? '';
+  const events: ServerSentEvent[] = [];
+  let retry: number | undefined;
+
+  for (const block of blocks) {
+    let id: string | undefined;
+    let event = 'message';
+    const data: string[] = [];
+    for (const line of block.split('\n')) {
+      // コメント行（接続維持の ping）は読み飛ばす
+      if (line === '' || line.startsWith(':')) continue;
+      const sep = line.indexOf(':');
+      const field = sep === -1 ? line : line.slice(0, sep);
+      const value = sep === -1 ? '' : line.slice(sep + 1).replace(/^ /, '');
+      if (field === 'id') id = value;
+      else if (field === 'event') event = value;
+      else if (field === 'data') data.push(value);
+      else if (field === 'retry' && /^\d+$/.test(value)) retry = Number(value);
+    }
+    if (data.length > 0) {
+      events.push({ id, event, data: data.join('\n') });
+    }
+  }
+  return { events, rest, retry };
+}
+
+// 承認状況の変更の種類ごとに、取得し直す一覧
+const approvalQueryKeys: Record<string, QueryKey[]> = {
+  leave: [['leaves']],
+  overtime: [['overtime']],
+  correction: [['corrections']],
+  expense: [['expenses'], ['expense-stats']],
+};
+
+/** イベントを受けて取得し直すクエリを返す */
+export function queryKeysForEvent(ev: ServerSentEvent): QueryKey[] {
+  switch (ev.event) {
+    case 'notification':
+      return [['notifications'], ['expense-notifications']];
+    case 'approval': {
+      try {
+        const change = JSON.parse(ev.data) as { kind?: string; id?: string };
+        const keys = approvalQueryKeys[change.kind ?? ''] ?? [];
+        return change.kind === 'expense' && change.id ? [...keys, ['expense', change.id]] : keys;
+      } catch {
+        return [];
+      }
+    }
+    case 'announcement':
+      return [['hr-announcements']];
+    case 'resync':
+      // 再開位置が失われたため、リアルタイムで更新される一覧をすべて取得し直す
+      return [
+        ['notifications'],
+        ['expense-notifications'],
+        ...Object.values(approvalQueryKeys).flat(),
+        ['hr-announcements'],
+      ];
+    default:
+      return [];
+  }
+}
+
+/**
+ * /events を購読し、届いたイベントに応じてクエリを無効化する。
+ * 切断時は最後に受信したイベントIDを Last-Event-ID で送って再開する。
+ * Authorization ヘッダーを付けるため EventSource ではなく fetch で受信する
+ */
+export function useEventStream() {
+  const queryClient = useQueryClient();
+  const accessToken = useAuthStore((s) => s.accessToken);
+  const isAuthenticated = useAuthStore((s) => s.isAuthenticated);
+
+  useEffect(() => {
+    if (!isAuthenticated) return;
+
+    const controller = new AbortController();
+    let lastEventId: string | undefined;
+    let retryMs = DEFAULT_RETRY_MS;
+    let timer: ReturnType<typeof setTimeout> | undefined;
+
+    const connect = async () => {
+      try {
+        const response = await fetch(`${API_BASE_URL}/events`, {
+          headers: {
+            Accept: 'text/event-stream',
+            ...(accessToken ? { Authorization: `Bearer ${accessToken}` } : {}),
+            ...(lastEventId ? { 'Last-Event-ID': lastEventId } : {}),
+          },
+          credentials: 'include',
+          signal: controller.signal,
+        });
+        // 認証切れはトークンの更新後に張り直す（accessToken の変更で再実行される）
+        if (response.status === 401 || !response.body) return;
+
+        const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+        let buffer = '';
+        for (;;) {
+          const { value, done } = await reader.read();
+          if (done) break;
+          const parsed = parseServerSentEvents(buffer + value);
+          buffer = parsed.rest;
+          if (parsed.retry !== undefined) retryMs = parsed.retry;
+          for (const ev of parsed.events) {
+            if (ev.id) lastEventId = ev.id;
+            for (const queryKey of queryKeysForEvent(ev)) {
+              queryClient.invalidateQueries({ queryKey });
+            }
+          }
+        }
+      } catch {
+        // 切断・ネットワークエラーは再接続で回復する
+      }
+      if (!controller.signal.aborted) {
+        timer = setTimeout(connect, retryMs);
+      }
+    };
+
+    connect();
+    return () => {
+      controller.abort();
+      if (timer) clearTimeout(timer);
+    };
+  }, [queryClient, accessToken, isAuthenticated]);
+}
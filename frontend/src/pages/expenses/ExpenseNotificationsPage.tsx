@@ -35,6 +35,8 @@ export function ExpenseNotificationsPage() {
     mutationFn: (id: string) => api.expenses.markNotificationRead(id),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['expense-notifications'] });
+      // 経費の通知は全体の通知と共通のため、未読バッジも更新する
+      queryClient.invalidateQueries({ queryKey: ['notifications'] });
     },
   });
 
@@ -42,6 +44,7 @@ export function ExpenseNotificationsPage() {
     mutationFn: () => api.expenses.markAllNotificationsRead(),
     onSuccess: () => {
       queryClient.invalidateQueries({ queryKey: ['expense-notifications'] });
+      queryClient.invalidateQueries({ queryKey: ['notifications'] });
     },
   });
 
@@ -63,7 +66,8 @@ export function ExpenseNotificationsPage() {
       policy_violation: { icon: 'warning', color: 'text-red-400', bg: 'bg-red-500/20' },
       returned: { icon: 'replay', color: 'text-orange-400', bg: 'bg-orange-500/20' },
     };
-    return icons[type] || { icon: 'notifications', color: 'text-indigo-400', bg: 'bg-indigo-500/20' };
+    // 共通の通知の種別（expense_approved など）は接頭辞を除いて対応させる
+    return icons[(type || '').replace(/^expense_/, '')] || { icon: 'notifications', color: 'text-indigo-400', bg: 'bg-indigo-500/20' };
   };
 
   const unreadCount = notifications.filter((n: Record<string, unknown>) => !n.is_read).length;